@@ -72,6 +72,102 @@ teardown() {
     [[ ! "$output" =~ "Initialize data repository" ]] || false
 }
 
+@test "dolt log with abbreviated commit hashes, and commits referenced by them" {
+    dolt add test
+    dolt commit -m "first commit"
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added test row"
+    head=`dolt log | grep -m 1 commit | awk '{print $2}'`
+    [ "${#head}" -eq 32 ]
+    run dolt log --abbrev-commit
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "commit ${head:0:8}"$'\n' ]] || false
+    run dolt log --abbrev 12 -n 1
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "commit ${head:0:12}" ]] || false
+    [ "${#lines[0]}" -eq 19 ]
+    dolt config --local --add core.abbrev 6
+    run dolt log --abbrev-commit -n 1
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "commit ${head:0:6}" ]
+    run dolt log --abbrev 2
+    [ "$status" -ne 0 ]
+    run dolt log "${head:0:6}" -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "added test row" ]] || false
+    run dolt diff "${head:0:6}~1" "${head:0:6}"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "+" ]] || false
+    run dolt checkout -b older-branch "${head:0:6}^"
+    [ "$status" -eq 0 ]
+    run dolt log
+    [[ ! "$output" =~ "added test row" ]] || false
+    run dolt sql -q "select count(*) from test as of '${head:0:6}'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " 1 " ]] || false
+}
+
+@test "dolt log -S shows the commits which changed how many rows hold a value" {
+    dolt add test
+    dolt commit -m "first commit"
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added row with c1 1"
+    dolt table put-row test pk:0 c1:1 c2:20 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "changed c2"
+    dolt table put-row test pk:1 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added another row with c1 1"
+    dolt table rm-row test 0
+    dolt add test
+    dolt commit -m "removed row with c1 1"
+    run dolt log -S 1 --table test --col c1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "removed row with c1 1" ]] || false
+    [[ "$output" =~ "added another row with c1 1" ]] || false
+    [[ "$output" =~ "added row with c1 1" ]] || false
+    [[ ! "$output" =~ "changed c2" ]] || false
+    [[ ! "$output" =~ "first commit" ]] || false
+    run dolt log -S 1 --table test --col c1 -n 1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "removed row with c1 1" ]] || false
+    [[ ! "$output" =~ "added another row with c1 1" ]] || false
+    run dolt log -S 20 --table test --col c2
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "changed c2" ]] || false
+    [[ "$output" =~ "removed row with c1 1" ]] || false
+    [[ ! "$output" =~ "added row with c1 1" ]] || false
+    run dolt log -S 1 --table test
+    [ "$status" -ne 0 ]
+}
+
+@test "dolt grep searches cell values in the working set and history" {
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "first commit"
+    commit=`dolt log | grep -m 1 commit | awk '{print $2}'`
+    dolt table put-row test pk:0 c1:11 c2:2 c3:3 c4:4 c5:5
+    dolt table put-row test pk:1 c1:101 c2:2 c3:3 c4:4 c5:5
+    run dolt grep '^1+$'
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[0]}" = "test (pk=0) c1: 11" ]
+    [ "${lines[1]}" = "test (pk=1) pk: 1" ]
+    run dolt grep --all-history '^1+$' test
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ "${lines[0]}" = "WORKING:test (pk=0) c1: 11" ]
+    [ "${lines[2]}" = "$commit:test (pk=0) c1: 1" ]
+    run dolt grep '^1+$' not_a_table
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "unknown table" ]] || false
+    run dolt grep '^7$'
+    [ "$status" -eq 1 ]
+    [ "$output" = "" ]
+}
+
 @test "add a row to a created table using dolt table put-row" {
     dolt add test
     dolt commit -m "create table"
@@ -158,23 +254,30 @@ if rows[2] != "9,8,7,6,5,4".split(","):
 }
 
 @test "dolt sql with insert ignore" {
-    skip "New engine does not support insert ignore"
     dolt sql -q "insert into test (pk,c1,c2,c3,c4,c5) values (0,6,6,6,6,6)"
     run dolt sql -q "insert ignore into test (pk,c1,c2,c3,c4,c5) values (0,6,6,6,6,6),(11,111,111,111,111,111)"
     [ "$status" -eq 0 ]
-    [ "${lines[0]}" = "Rows inserted: 1" ]
-    [ "${lines[1]}" = "Errors ignored: 1" ]
+    [[ "$output" =~ "| 1       |" ]] || false
+    run dolt table select test
+    [[ "$output" =~ "111" ]] || false
+}
+
+@test "dolt sql insert on duplicate key update" {
+    dolt sql -q "insert into test (pk,c1,c2,c3,c4,c5) values (0,6,6,6,6,6)"
+    run dolt sql -q "insert into test (pk,c1,c2,c3,c4,c5) values (0,7,7,7,7,7),(11,111,111,111,111,111) on duplicate key update c1 = values(c1)"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 3       |" ]] || false
+    run dolt sql -q "select c1, c2 from test where pk = 0"
+    [[ "$output" =~ "| 7  | 6  |" ]] || false
     run dolt table select test
     [[ "$output" =~ "111" ]] || false
 }
 
 @test "dolt sql replace into" {
-    skip "New engine does not support replace"
     dolt sql -q "insert into test (pk,c1,c2,c3,c4,c5) values (0,6,6,6,6,6)"
     run dolt sql -q "replace into test (pk,c1,c2,c3,c4,c5) values (0,7,7,7,7,7),(1,8,8,8,8,8)"
     [ "$status" -eq 0 ]
-    [ "${lines[0]}" = "Rows inserted: 1" ]
-    [ "${lines[1]}" = "Rows updated: 1" ]
+    [[ "$output" =~ "| 3       |" ]] || false
     run dolt table select test
     [[ "$output" =~ "7" ]] || false
     [[ "$output" =~ "8" ]] || false
@@ -284,6 +387,9 @@ if rows[2] != "9,8,7,6,5,4".split(","):
     [[ "$output" =~ "100" ]] || false
     [[ "$output" =~ "10" ]] || false
     [[ ! "$output" =~ "11" ]] || false
+    run dolt sql -q "update test set pk=null where pk=0"
+    [ "$status" -eq 1 ]
+    [ "$output" = "column name 'pk' is non-nullable but attempted to set a value of null" ]
 }
 
 @test "dolt sql delete queries" {
@@ -310,6 +416,25 @@ if rows[2] != "9,8,7,6,5,4".split(","):
     [[ "$output" =~ "| 0       |" ]] || false
 }
 
+@test "dolt sql reports the rows inserts, updates and deletes affect" {
+    run dolt sql -q "insert into test (pk,c1,c2,c3,c4,c5) values (0,1,2,3,4,5),(1,11,12,13,14,15)"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Query OK, 2 rows affected" ]] || false
+    run dolt sql -q "insert ignore into test (pk,c1,c2,c3,c4,c5) values (0,6,6,6,6,6),(2,21,22,23,24,25)"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Query OK, 1 row affected, 1 warning" ]] || false
+    run dolt sql -q "update test set c1=1 where pk<2"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Query OK, 1 row affected" ]] || false
+    [[ "$output" =~ "Rows matched: 2  Changed: 1  Warnings: 0" ]] || false
+    run dolt sql -q "delete from test where c1=1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Query OK, 2 rows affected" ]] || false
+    run dolt sql -q "delete from test"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Query OK, 1 row affected" ]] || false
+}
+
 @test "delete a row with dolt table rm-row" {
     dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
     run dolt table rm-row test 0
@@ -391,6 +516,31 @@ if rows[2] != "9,8,7,6,5,4".split(","):
     [ "$output" = "" ]
 }
 
+@test "filter branches with --contains, --merged and --no-merged" {
+    dolt add test
+    dolt commit -m "first commit"
+    dolt branch merged-branch
+    dolt checkout -b unmerged-branch
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added test row"
+    dolt checkout master
+    run dolt branch --merged master
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "merged-branch" ]] || false
+    [[ ! "$output" =~ "unmerged-branch" ]] || false
+    run dolt branch --no-merged master
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ " merged-branch" ]] || false
+    [[ "$output" =~ "unmerged-branch" ]] || false
+    run dolt branch --contains unmerged-branch
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "unmerged-branch" ]] || false
+    [[ ! "$output" =~ "master" ]] || false
+    run dolt branch --contains not-a-branch
+    [ "$status" -ne 0 ]
+}
+
 @test "generate a merge conflict and resolve with ours" {
     dolt add test
     dolt commit -m "added test table"
@@ -502,6 +652,76 @@ if rows[2] != "9,8,7,6,5,4".split(","):
     [[ ! "$output" =~ "|5" ]] || false
 }
 
+@test "generate a merge conflict and resolve with a csv file" {
+    dolt add test
+    dolt commit -m "added test table"
+    dolt branch test-branch
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt table put-row test pk:1 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added test rows"
+    dolt checkout test-branch
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:6
+    dolt table put-row test pk:1 c1:1 c2:2 c3:3 c4:4 c5:6
+    dolt add test
+    dolt commit -m "added conflicting test rows"
+    dolt checkout master
+    dolt merge test-branch
+    run dolt conflicts cat --format csv test
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "op,source,pk,c1,c2,c3,c4,c5" ]
+    [[ "$output" =~ "added,theirs,0,1,2,3,4,6" ]] || false
+    [[ "$output" =~ "added,ours,1,1,2,3,4,5" ]] || false
+    dolt conflicts cat --format csv test | grep -v ",ours," > resolved.csv
+    head -2 resolved.csv > partial.csv
+    run dolt conflicts resolve --file partial.csv test
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "1 is not resolved" ]] || false
+    run dolt conflicts resolve --file resolved.csv test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "2 rows resolved successfully" ]] || false
+    run dolt table select test
+    [[ "$output" =~ \|[[:space:]]+6 ]] || false
+    [[ ! "$output" =~ \|[[:space:]]+5[[:space:]]+\| ]] || false
+}
+
+@test "generate a merge conflict and resolve it with the dolt_conflicts system tables" {
+    dolt add test
+    dolt commit -m "added test table"
+    dolt branch test-branch
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt table put-row test pk:1 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added test rows"
+    dolt checkout test-branch
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:6
+    dolt table put-row test pk:1 c1:1 c2:2 c3:3 c4:4 c5:6
+    dolt add test
+    dolt commit -m "added conflicting test rows"
+    dolt checkout master
+    dolt merge test-branch
+    run dolt sql -q "select * from dolt_conflicts"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ \|[[:space:]]+test[[:space:]]+\|[[:space:]]+2[[:space:]]+\| ]] || false
+    run dolt sql -q "select our_pk, our_c5, their_c5 from dolt_conflicts_test where base_pk is null order by our_pk"
+    [ "$status" -eq 0 ]
+    [[ "${lines[3]}" =~ \|[[:space:]]+0[[:space:]]+\|[[:space:]]+5[[:space:]]+\|[[:space:]]+6[[:space:]]+\| ]] || false
+    [[ "${lines[4]}" =~ \|[[:space:]]+1[[:space:]]+\|[[:space:]]+5[[:space:]]+\|[[:space:]]+6[[:space:]]+\| ]] || false
+    run dolt sql -q "update dolt_conflicts_test set their_c5 = 7"
+    [ "$status" -ne 0 ]
+    dolt sql -q "update dolt_conflicts_test set our_c5 = their_c5 where our_pk = 0"
+    dolt sql -q "delete from dolt_conflicts_test"
+    run dolt sql -q "select * from dolt_conflicts"
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "test" ]] || false
+    run dolt sql -q "select pk, c5 from test order by pk"
+    [[ "$output" =~ \|[[:space:]]+0[[:space:]]+\|[[:space:]]+6[[:space:]]+\| ]] || false
+    [[ "$output" =~ \|[[:space:]]+1[[:space:]]+\|[[:space:]]+5[[:space:]]+\| ]] || false
+    dolt add test
+    run dolt commit -m "resolved conflicts"
+    [ "$status" -eq 0 ]
+}
+
 @test "put a row that violates the schema" {
     run dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:foo
     [ "$status" -ne 0 ]
@@ -594,6 +814,128 @@ if rows[2] != "9,8,7,6,5,4".split(","):
     [ -f export.csv ]
 }
 
+@test "dolt table export only the rows changed since a commit" {
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt table put-row test pk:1 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt table put-row test pk:2 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added three rows"
+    dolt table put-row test pk:1 c1:11 c2:2 c3:3 c4:4 c5:5
+    dolt table put-row test pk:3 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt table rm-row test 2
+    run dolt table export --since HEAD test export.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully exported data." ]] || false
+    run cat export.csv
+    [ "${#lines[@]}" -eq 3 ]
+    [[ "$output" =~ "1,11,2,3,4,5" ]] || false
+    [[ "$output" =~ "3,1,2,3,4,5" ]] || false
+    [[ ! "$output" =~ "0,1,2,3,4,5" ]] || false
+    run cat export.deletes.csv
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[0]}" = "pk" ]
+    [ "${lines[1]}" = "2" ]
+    run dolt table export --since HEAD test export.csv
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "Data already exists" ]] || false
+    run dolt table export -f --since HEAD --deletes-file tombstones.csv test export.csv
+    [ "$status" -eq 0 ]
+    [ -f tombstones.csv ]
+    run dolt table export --deletes-file tombstones.csv test other.csv
+    [ "$status" -ne 0 ]
+}
+
+@test "dolt admin rechunk rewrites tables with new chunking parameters" {
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt table put-row test pk:1 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added rows"
+    run dolt admin rechunk --target-size 3000
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "power of two" ]] || false
+    run dolt admin rechunk --target-size 65536 --window 131
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Rewrote test" ]] || false
+    [[ "$output" =~ "a target size of 65536 bytes and a window of 131 bytes" ]] || false
+    run dolt sql -q "select * from dolt_chunking"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "target_size | 65536" ]] || false
+    [[ "$output" =~ "window      | 131" ]] || false
+    run dolt diff test
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "+" ]] || false
+    run dolt sql -q "select count(*) from test"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " 2 " ]] || false
+    run dolt admin rechunk not_a_table
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "unknown table not_a_table" ]] || false
+}
+
+@test "dolt admin set-ref and list-refs read and write refs directly" {
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added a row"
+    run dolt admin list-refs
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "refs/heads/master" ]] || false
+    run dolt admin set-ref refs/heads/backup HEAD~1
+    [ "$status" -eq 0 ]
+    run dolt admin set-ref refs/remotes/origin/master master
+    [ "$status" -eq 0 ]
+    run dolt admin list-refs
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "refs/heads/backup" ]] || false
+    [[ "$output" =~ "refs/remotes/origin/master" ]] || false
+    run dolt branch -a
+    [[ "$output" =~ "backup" ]] || false
+    [[ "$output" =~ "remotes/origin/master" ]] || false
+    run dolt admin set-ref --old master refs/heads/backup master
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "rather than" ]] || false
+    run dolt admin set-ref --old HEAD~1 refs/heads/backup master
+    [ "$status" -eq 0 ]
+    run dolt admin set-ref refs/heads/master backup
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "checked out" ]] || false
+    run dolt admin set-ref refs/unknown/thing master
+    [ "$status" -ne 0 ]
+    run dolt admin set-ref refs/heads/backup not_a_commit
+    [ "$status" -ne 0 ]
+    run dolt admin set-ref -d refs/heads/master
+    [ "$status" -ne 0 ]
+    run dolt admin set-ref -d refs/heads/backup
+    [ "$status" -eq 0 ]
+    run dolt admin list-refs
+    [[ ! "$output" =~ "refs/heads/backup" ]] || false
+}
+
+@test "dolt merge refuses to merge branches with different chunking parameters" {
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added a row"
+    dolt checkout -b other
+    dolt table put-row test pk:1 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt add test
+    dolt commit -m "added another row"
+    dolt checkout master
+    dolt table put-row test pk:2 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt admin rechunk --target-size 65536
+    dolt add .
+    dolt commit -m "rechunked"
+    run dolt merge other
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "different chunking parameters" ]] || false
+    dolt checkout other
+    run dolt admin rechunk --target-size 65536
+    [ "$status" -eq 0 ]
+    dolt add .
+    dolt commit -m "rechunked"
+    dolt checkout master
+    run dolt merge other
+    [ "$status" -eq 0 ]
+}
+
 @test "dolt table SQL export" {
     dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
     run dolt table export test export.sql
@@ -685,3 +1027,28 @@ if rows[2] != "9,8,7,6,5,4".split(","):
     [ "${#lines[@]}" -eq 6 ]
     [[ "$output" =~ "<NULL>" ]] || false
 }
+
+@test "dolt table dedupe finds and removes duplicate rows" {
+    dolt sql -q "insert into test (pk,c1,c2,c3,c4,c5) values (0,1,2,3,4,5),(1,1,2,3,4,5),(2,1,2,3,4,6),(3,1,2,0,4,5)"
+    run dolt table dedupe test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Found 1 rows of test which are duplicates by c1, c2, c3, c4, c5:" ]] || false
+    [[ "$output" =~ "pk: 1" ]] || false
+    run dolt table dedupe --by c1,c3 test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Found 2 rows of test which are duplicates by c1, c3:" ]] || false
+    [[ "$output" =~ "pk: 2" ]] || false
+    run dolt table dedupe --by c6 test
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "test has no column 'c6'" ]] || false
+    run dolt table dedupe --remove --by c1,c3 test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Removed 2 rows of test which were duplicates by c1, c3:" ]] || false
+    run dolt sql -q "select pk from test"
+    [ "${#lines[@]}" -eq 6 ]
+    [[ "$output" =~ "| 0  |" ]] || false
+    [[ "$output" =~ "| 3  |" ]] || false
+    run dolt table dedupe --by c1,c3 test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "No rows of test are duplicates by c1, c3" ]] || false
+}
@@ -277,10 +277,119 @@ teardown() {
     [[ "$output" =~ "c6" ]] || false
 }
 
-@test "sql alter table to change column type not supported" {
-    run dolt sql -q "alter table one_pk modify column c5 varchar"
+@test "sql alter table add column with default" {
+    run dolt sql -q "alter table one_pk add column c6 int not null default 7"
+    [ $status -eq 0 ]
+    run dolt sql -q "select pk from one_pk where c6 = 7"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 8 ]
+    run dolt sql -q "alter table one_pk add column C6 int"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "A column with the name 'C6' already exists" ]] || false
+}
+
+@test "sql alter table add the same column on two branches and merge" {
+    dolt add .
+    dolt commit -m "added tables"
+    dolt branch other
+    dolt sql -q "alter table one_pk add column c6 int default 6"
+    dolt sql -q "alter table one_pk add column c7 int"
+    dolt add .
+    dolt commit -m "added c6 and c7"
+    dolt checkout other
+    dolt sql -q "alter table one_pk add column c6 int default 6"
+    dolt sql -q "alter table one_pk add column c8 varchar(20)"
+    dolt add .
+    dolt commit -m "added c6 and c8"
+    dolt checkout master
+    run dolt merge other
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+    run dolt sql -q "select pk,c6,c7,c8 from one_pk where pk = 0"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "| 0  | 6  | <NULL> | <NULL> |" ]] || false
+}
+
+@test "sql alter table drop column and add it back" {
+    run dolt sql -q "alter table one_pk drop column C5"
+    [ $status -eq 0 ]
+    run dolt schema show one_pk
+    [[ ! "$output" =~ "c5" ]] || false
+    dolt sql -q "alter table one_pk add column c6 int default 6"
+    dolt sql -q "alter table one_pk drop column c6"
+    dolt sql -q "alter table one_pk add column c6 int"
+    run dolt sql -q "select pk from one_pk where c6 is null"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 8 ]
+}
+
+@test "sql alter table modify column type" {
+    run dolt sql -q "alter table one_pk modify column c5 varchar(20)"
+    [ $status -eq 0 ]
+    run dolt schema show one_pk
+    [[ "$output" =~ "\`c5\` TEXT COMMENT 'tag:5'" ]] || false
+    run dolt sql -q "select pk from one_pk where c5 = '20'"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 5 ]
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,40,40,40,40,'forty')"
+    run dolt sql -q "alter table one_pk modify c5 int"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "Cannot modify column c5: 1 rows have values that can't be converted to Int, with primary keys (4)" ]] || false
+    run dolt sql -q "alter table one_pk modify column pk varchar(20)"
     [ $status -eq 1 ]
-    [[ "$output" =~ "Unsupported alter table statement" ]] || false
+    [[ "$output" =~ "Cannot modify column in primary key" ]] || false
+}
+
+@test "sql alter table change column" {
+    run dolt sql -q "alter table one_pk change column C4 c6 bigint not null"
+    [ $status -eq 0 ]
+    run dolt schema show one_pk
+    [[ "$output" =~ "\`c6\` BIGINT NOT NULL COMMENT 'tag:4'" ]] || false
+    [[ ! "$output" =~ "c4" ]] || false
+    run dolt sql -q "select pk from one_pk where c6 = 30"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 5 ]
+    run dolt sql -q "alter table one_pk change column c6 c1 bigint"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "A column with the name 'c1' already exists" ]] || false
+}
+
+@test "sql create index" {
+    run dolt sql -q "create index c1_idx on one_pk (c1)"
+    [ $status -eq 0 ]
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,20,40,40,40,40)"
+    run env DOLT_USE_INDEXES=1 dolt sql -q "explain select pk from one_pk where c1 = 20"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "index-only scan of c1_idx, 2 keys" ]] || false
+    run env DOLT_USE_INDEXES=1 dolt sql -q "explain select pk, c2 from one_pk where c1 = 20"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "index c1_idx lookup, 2 keys" ]] || false
+    run env DOLT_USE_INDEXES=1 dolt sql -q "select pk from one_pk where c1 >= 10 and c1 < 30"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 7 ]
+    [[ "$output" =~ "| 1  |" ]] || false
+    [[ "$output" =~ "| 2  |" ]] || false
+    [[ "$output" =~ "| 4  |" ]] || false
+    run dolt sql -q "create index C1_IDX on one_pk (c2)"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "An index with the name 'C1_IDX' already exists on table 'one_pk'" ]] || false
+    run dolt sql -q "create index c9_idx on one_pk (c9)"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "Unknown column: 'c9'" ]] || false
+}
+
+@test "dolt admin rebuild-indexes" {
+    dolt sql -q "create index c1_idx on one_pk (c1)"
+    run dolt admin rebuild-indexes --verify
+    [ $status -eq 0 ]
+    [[ "$output" =~ "one_pk: ok" ]] || false
+    [[ "$output" =~ "two_pk: ok" ]] || false
+    run dolt admin rebuild-indexes one_pk
+    [ $status -eq 0 ]
+    [ "$output" = "one_pk: ok" ]
+    run dolt admin rebuild-indexes not_a_table
+    [ $status -eq 1 ]
+    [[ "$output" =~ "unknown table not_a_table" ]] || false
 }
 
 @test "sql drop table" {
@@ -290,4 +399,388 @@ teardown() {
     run dolt sql -q "drop table poop"
     [ $status -eq 1 ]
     [ "$output" = "table not found: poop" ]
-}
\ No newline at end of file
+}
+
+@test "sql drop table if exists and multiple tables" {
+    run dolt sql -q "drop table one_pk, poop, two_pk"
+    [ $status -eq 1 ]
+    [ "$output" = "table not found: poop" ]
+    run dolt ls
+    [[ "$output" =~ "one_pk" ]] || false
+    [[ "$output" =~ "two_pk" ]] || false
+    run dolt sql -q "drop table if exists ONE_PK, poop, two_pk"
+    [ $status -eq 0 ]
+    run dolt ls
+    [[ ! "$output" =~ "one_pk" ]] || false
+    [[ ! "$output" =~ "two_pk" ]] || false
+}
+
+@test "sql drop system table" {
+    run dolt sql -q "drop table dolt_log"
+    [ $status -eq 1 ]
+    [ "$output" = "table dolt_log is a system table and cannot be dropped" ]
+}
+
+@test "sql truncate table" {
+    dolt sql -q "create index c1_idx on one_pk (c1)"
+    dolt sql -q "truncate table one_pk"
+    run dolt sql -q "select * from one_pk"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 4 ]
+    run dolt schema show one_pk
+    [[ "$output" =~ "c5" ]] || false
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (5,50,50,50,50,50)"
+    run env DOLT_USE_INDEXES=1 dolt sql -q "select pk from one_pk where c1 = 50"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "| 5  |" ]] || false
+    run dolt sql -q "truncate TWO_PK"
+    [ $status -eq 0 ]
+    run dolt sql -q "select * from two_pk"
+    [ "${#lines[@]}" -eq 4 ]
+    run dolt sql -q "truncate table poop"
+    [ $status -eq 1 ]
+    [ "$output" = "table not found: poop" ]
+    run dolt sql -q "truncate table dolt_log"
+    [ $status -eq 1 ]
+    [ "$output" = "table dolt_log is a system table and cannot be truncated" ]
+}
+
+@test "sql rename table is reported as a rename" {
+    dolt add .
+    dolt commit -m "added tables"
+    dolt sql -q "rename table one_pk to renamed"
+    run dolt status
+    [ $status -eq 0 ]
+    [[ "$output" =~ "renamed:        one_pk -> renamed" ]] || false
+    [[ ! "$output" =~ "deleted" ]] || false
+    run dolt diff
+    [ $status -eq 0 ]
+    [[ "$output" =~ "diff --dolt a/one_pk b/renamed" ]] || false
+    [[ "$output" =~ "renamed table" ]] || false
+    [[ ! "$output" =~ "added table" ]] || false
+    run dolt diff --sql
+    [ $status -eq 0 ]
+    [[ "$output" =~ "RENAME TABLE \`one_pk\` TO \`renamed\`" ]] || false
+    dolt add one_pk renamed
+    run dolt status
+    [[ "$output" =~ "Changes to be committed" ]] || false
+    [[ "$output" =~ "renamed:        one_pk -> renamed" ]] || false
+    dolt table mv renamed one_pk
+    run dolt status
+    [ $status -eq 0 ]
+    [[ "$output" =~ "renamed:        renamed -> one_pk" ]] || false
+    run dolt table mv one_pk "bad name"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "'bad name' is not a valid table name" ]] || false
+    run dolt sql -q "rename table one_pk to \`bad name\`"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "Invalid table name: 'bad name'" ]] || false
+}
+
+@test "sql create view" {
+    dolt sql -q "create view big_pks as select pk, c1 from one_pk where pk >= 2"
+    run dolt sql -q "select * from big_pks"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 6 ]
+    [[ "$output" =~ "| 3  | 30 |" ]] || false
+    run dolt sql -q "select * from dolt_schemas"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "select pk, c1 from one_pk where pk >= 2" ]] || false
+    dolt add .
+    dolt commit -m "added a view"
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,40,40,40,40,40)"
+    run dolt sql -q "select * from big_pks"
+    [ "${#lines[@]}" -eq 7 ]
+    run dolt sql -q "create view big_pks as select 1"
+    [ $status -eq 1 ]
+    run dolt sql -q "create view one_pk as select 1"
+    [ $status -eq 1 ]
+    dolt sql -q "drop view big_pks"
+    run dolt sql -q "select * from big_pks"
+    [ $status -eq 1 ]
+    run dolt status
+    [[ "$output" =~ "modified:       dolt_schemas" ]] || false
+    dolt checkout dolt_schemas
+    run dolt sql -q "select * from big_pks"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 7 ]
+}
+
+@test "sql explain select" {
+    run dolt sql -q "explain select pk from one_pk where c1 = 10"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "plan" ]] || false
+    [[ "$output" =~ "Filter(one_pk.c1 = 10)" ]] || false
+    [[ "$output" =~ "one_pk" ]] || false
+    run dolt sql -q "explain select pk,pk1,pk2 from one_pk join two_pk on one_pk.c1=two_pk.c1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "InnerJoin(one_pk.c1 = two_pk.c1)" ]] || false
+}
+
+@test "sql -q writes results to a file with -o" {
+    run dolt sql -q "select pk,c1 from one_pk where pk < 2 order by pk" -o results.csv
+    [ "$status" -eq 0 ]
+    [ "$output" = "" ]
+    run cat results.csv
+    [ "${lines[0]}" = "pk,c1" ]
+    [ "${lines[1]}" = "0,0" ]
+    [ "${lines[2]}" = "1,10" ]
+    [ "${#lines[@]}" -eq 3 ]
+    run dolt sql -q "select pk,c1 from one_pk where pk = 1" -o results.json
+    [ "$status" -eq 0 ]
+    [ "$output" = "" ]
+    run cat results.json
+    [[ "$output" =~ '"c1":"10"' ]] || false
+    run dolt sql -q "select * from one_pk" -o results.txt
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unable to determine the output format" ]] || false
+    run dolt sql -o results.csv
+    [ "$status" -eq 1 ]
+}
+
+@test "sql select as of a past commit" {
+    dolt add one_pk
+    dolt commit -m "four rows"
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,40,40,40,40,40)"
+    dolt add one_pk
+    dolt commit -m "five rows"
+    run dolt sql -q "select count(*) from one_pk as of 'HEAD~1'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " 4 " ]] || false
+    run dolt sql -q "select count(*) from one_pk AS OF 'master'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " 5 " ]] || false
+    head=`dolt log | grep -m 1 commit | awk '{print $2}'`
+    run dolt sql -q "select o.pk from one_pk as of '$head~1' o where o.c1 = 30"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " 3 " ]] || false
+    run dolt sql -q "select * from one_pk as of 'HEAD~5'"
+    [ "$status" -eq 1 ]
+    run dolt sql -q "insert into \`one_pk@HEAD~1\` (pk,c1,c2,c3,c4,c5) values (5,50,50,50,50,50)"
+    [ "$status" -eq 1 ]
+}
+
+@test "sql self-join of a table as of two commits" {
+    dolt add one_pk
+    dolt commit -m "four rows"
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,40,40,40,40,40)"
+    dolt sql -q "update one_pk set c1 = 11 where pk = 1"
+    dolt add one_pk
+    dolt commit -m "changed rows"
+    run dolt sql -q "select o.pk, o.c1, n.c1 from one_pk as of 'HEAD~1' o join one_pk as of 'HEAD' n on o.pk = n.pk where o.c1 <> n.c1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 1  | 10 | 11 |" ]] || false
+    [ "${#lines[@]}" -eq 5 ]
+    run dolt sql -q "select n.pk from one_pk as of 'HEAD' n left join one_pk as of 'HEAD~1' o on n.pk = o.pk where o.pk is null"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " 4 " ]] || false
+    [ "${#lines[@]}" -eq 5 ]
+}
+
+@test "sql null values follow three-valued logic" {
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,NULL,40,40,40,40),(5,NULL,50,50,50,50)"
+    dolt sql -q "insert into two_pk (pk1,pk2,c1,c2,c3,c4,c5) values (2,0,NULL,0,0,0,0)"
+    run dolt sql -q "select pk from one_pk where c1 not in (0, NULL)"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 4 ]
+    run dolt sql -q "select pk, pk1, pk2 from one_pk join two_pk on one_pk.c1 = two_pk.c1"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 8 ]
+    run dolt sql -q "select avg(c1) from one_pk"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 15 " ]] || false
+    run dolt sql -q "select pk from one_pk order by c1 desc, pk"
+    [ "$status" -eq 0 ]
+    [[ "${lines[3]}" =~ " 3 " ]] || false
+    [[ "${lines[8]}" =~ " 5 " ]] || false
+    run dolt sql <<< "set @@dolt_null_ordering = 'first'; select pk from one_pk order by c1 desc, pk;"
+    [ "$status" -eq 0 ]
+    [[ "${lines[3]}" =~ " 4 " ]] || false
+    [[ "${lines[8]}" =~ " 0 " ]] || false
+}
+
+@test "sql commit functions" {
+    dolt add one_pk
+    dolt commit -m "four rows"
+    head=`dolt log | grep -m 1 commit | awk '{print $2}'`
+    run dolt sql -q "select hashof('HEAD'), hashof('master') = hashof('${head:0:8}')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| $head | true " ]] || false
+    run dolt sql -q "select commit_message('HEAD'), commit_author('master')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "four rows" ]] || false
+    [[ "$output" =~ "<bats@email.fake>" ]] || false
+    run dolt sql -q "select year(commit_date('HEAD')) > 2018"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " true " ]] || false
+    dolt checkout -b other
+    run dolt sql -q "select active_branch()"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ " other " ]] || false
+    run dolt sql -q "select hashof('no_such_branch')"
+    [ "$status" -eq 1 ]
+}
+
+@test "sql attach another repository" {
+    mkdir "$BATS_TMPDIR/attached-repo-$$"
+    pushd "$BATS_TMPDIR/attached-repo-$$"
+    dolt init
+    dolt table create -s=`batshelper 1pk5col-ints.schema` other_pk
+    dolt sql -q "insert into other_pk (pk,c1,c2,c3,c4,c5) values (1,100,0,0,0,0),(3,300,0,0,0,0)"
+    dolt add other_pk
+    dolt commit -m "added other_pk"
+    popd
+    run dolt sql --attach "other=$BATS_TMPDIR/attached-repo-$$" -q "select one_pk.c1, o.c1 from one_pk join other.other_pk o on one_pk.pk = o.pk"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 10 | 100 |" ]] || false
+    [[ "$output" =~ "| 30 | 300 |" ]] || false
+    [ "${#lines[@]}" -eq 6 ]
+    run dolt sql --attach "other=$BATS_TMPDIR/attached-repo-$$" -q "insert into other.other_pk (pk,c1,c2,c3,c4,c5) values (5,500,0,0,0,0)"
+    [ "$status" -eq 1 ]
+    run dolt sql --attach "dolt=$BATS_TMPDIR/attached-repo-$$" -q "select * from one_pk"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "there is already a database named 'dolt'" ]] || false
+    run dolt sql --attach "other=$BATS_TMPDIR/no-such-repo-$$" -q "select * from one_pk"
+    [ "$status" -eq 1 ]
+    rm -rf "$BATS_TMPDIR/attached-repo-$$"
+}
+
+@test "sql create and check out branches" {
+    dolt add .
+    dolt commit -m "added tables"
+    run dolt sql -q "select dolt_branch('feature')"
+    [ "$status" -eq 0 ]
+    run dolt branch
+    [[ "$output" =~ "feature" ]] || false
+    run dolt sql -q "select dolt_branch('feature')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "already exists" ]] || false
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,40,40,40,40,40)"
+    run dolt sql -q "select dolt_checkout('feature')"
+    [ "$status" -eq 0 ]
+    run dolt branch
+    [[ "$output" =~ "* feature" ]] || false
+    dolt add one_pk
+    dolt commit -m "added a row on feature"
+    run dolt sql -q "select dolt_checkout('master')"
+    [ "$status" -eq 0 ]
+    run dolt sql -q "select * from one_pk where pk = 4"
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ " 40 " ]] || false
+    run dolt branch
+    [[ "$output" =~ "* master" ]] || false
+    run dolt sql -q "select dolt_checkout('no_such_branch')"
+    [ "$status" -eq 1 ]
+}
+
+@test "sql merge branches" {
+    dolt add .
+    dolt commit -m "added tables"
+    dolt branch other
+    dolt sql -q "update one_pk set c1 = 11 where pk = 1"
+    dolt add one_pk
+    dolt commit -m "changed c1 on master"
+    dolt checkout other
+    dolt sql -q "update one_pk set c2 = 12 where pk = 1"
+    dolt sql -q "update one_pk set c3 = 13 where pk = 2"
+    dolt add one_pk
+    dolt commit -m "changed c2 and c3 on other"
+    dolt checkout master
+    run dolt sql -q "select dolt_merge('other')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 0 " ]] || false
+    run dolt sql -q "select c1, c2 from one_pk where pk = 1"
+    [[ "$output" =~ "| 11 | 12 |" ]] || false
+    dolt add one_pk
+    dolt commit -m "merged other"
+    dolt checkout other
+    dolt sql -q "update one_pk set c3 = 23 where pk = 2"
+    dolt add one_pk
+    dolt commit -m "changed c3 again on other"
+    dolt checkout master
+    dolt sql -q "update one_pk set c3 = 33 where pk = 2"
+    dolt add one_pk
+    dolt commit -m "changed c3 on master"
+    run dolt sql -q "select dolt_merge('other')"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 1 " ]] || false
+    run dolt sql -q "select * from dolt_conflicts"
+    [[ "$output" =~ "one_pk" ]] || false
+    run dolt sql -q "select dolt_merge('other')"
+    [ "$status" -eq 1 ]
+}
+
+@test "sql unnest expands json arrays into rows" {
+    dolt sql -q "create table items (id int, tags varchar(1023), primary key(id))"
+    dolt sql -q "insert into items values (1, '[\"red\", \"blue\"]'), (2, '[]'), (3, '[\"green\"]')"
+    run dolt sql -q "select id, unnest(tags) as tag from items order by id"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 1  | red " ]] || false
+    [[ "$output" =~ "| 1  | blue " ]] || false
+    [[ "$output" =~ "| 3  | green " ]] || false
+    [[ ! "$output" =~ "| 2 " ]] || false
+    run dolt sql -q "select unnest('not json')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "expects a JSON array" ]] || false
+}
+
+@test "sql diff system table between two branches" {
+    dolt add one_pk
+    dolt commit -m "four rows"
+    dolt checkout -b feature
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,40,40,40,40,40)"
+    dolt sql -q "update one_pk set c1 = 11 where pk = 1"
+    dolt sql -q "delete from one_pk where pk = 2"
+    dolt add one_pk
+    dolt commit -m "changed rows"
+    dolt checkout master
+    run dolt sql -q "select from_pk, to_pk, diff_type from dolt_diff_one_pk where from_commit = 'master' and to_commit = 'feature'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 1       | 1      | modified  |" ]] || false
+    [[ "$output" =~ "| 2       | <NULL> | removed   |" ]] || false
+    [[ "$output" =~ "| <NULL>  | 4      | added     |" ]] || false
+    run dolt sql -q "select * from dolt_diff_one_pk where from_commit = 'no_such_branch'"
+    [ "$status" -eq 1 ]
+}
+
+@test "sql session pinned to a commit with dolt_head" {
+    dolt add one_pk
+    dolt commit -m "four rows"
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,40,40,40,40,40)"
+    run dolt sql <<< "set @@dolt_head = 'HEAD'; select count(*) from one_pk; set @@dolt_head = ''; select count(*) from one_pk;"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 4        |" ]] || false
+    [[ "$output" =~ "| 5        |" ]] || false
+    run dolt sql <<< "set @@dolt_head = 'HEAD'; insert into one_pk (pk,c1,c2,c3,c4,c5) values (5,50,50,50,50,50);"
+    [ "$status" -eq 1 ]
+    run dolt sql <<< "set @@dolt_head = 'no_such_branch';"
+    [ "$status" -eq 1 ]
+}
+
+@test "sql read-only session opened at a commit with --at" {
+    dolt add one_pk
+    dolt commit -m "four rows"
+    commit=`dolt log | grep -m 1 commit | awk '{print $2}'`
+    dolt sql -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (4,40,40,40,40,40)"
+    dolt add one_pk
+    dolt commit -m "five rows"
+    run dolt sql --at "$commit" -q "select count(*) from one_pk"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 4        |" ]] || false
+    run dolt sql --at "$commit" -q "select @@dolt_head"
+    [[ "$output" =~ "$commit" ]] || false
+    run dolt sql --at "$commit" -q "insert into one_pk (pk,c1,c2,c3,c4,c5) values (5,50,50,50,50,50)"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "read-only" ]] || false
+    run dolt sql --at "$commit" -q "drop table one_pk"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "read-only" ]] || false
+    run dolt sql --at "$commit" <<< "delete from one_pk;"
+    [ "$status" -eq 1 ]
+    run dolt sql -q "select count(*) from one_pk"
+    [[ "$output" =~ "| 5        |" ]] || false
+    run dolt status
+    [[ "$output" =~ "nothing to commit" ]] || false
+    run dolt sql --at no_such_branch -q "select 1"
+    [ "$status" -eq 1 ]
+}
@@ -0,0 +1,106 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt table create -s `batshelper 1pk5col-ints.schema` test
+    dolt table put-row test pk:0 c1:1 c2:2 c3:3 c4:4 c5:5
+    dolt table append-only test
+    dolt add test
+    dolt commit -m "append-only test table"
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "append-only tables are listed and shown" {
+    dolt table create -s `batshelper 1pk5col-ints.schema` other
+    run dolt table append-only
+    [ "$status" -eq 0 ]
+    [ "$output" = "test" ]
+    run dolt schema show test
+    [[ "$output" =~ "-- append-only" ]] || false
+    run dolt schema show other
+    [[ ! "$output" =~ "append-only" ]] || false
+    run dolt table append-only not_a_table
+    [ "$status" -eq 1 ]
+}
+
+@test "rows can be added to append-only tables but not changed" {
+    run dolt sql -q "insert into test values (1, 1, 2, 3, 4, 5)"
+    [ "$status" -eq 0 ]
+    run dolt sql -q "update test set c5 = 10 where pk = 0"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "table test is append-only" ]] || false
+    run dolt sql -q "delete from test where pk = 0"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "table test is append-only" ]] || false
+    run dolt sql -q "delete from test"
+    [ "$status" -eq 1 ]
+    run dolt sql -q "truncate table test"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "table test is append-only" ]] || false
+    run dolt sql -q "replace into test values (0, 9, 9, 9, 9, 9)"
+    [ "$status" -eq 1 ]
+    run dolt table put-row test pk:0 c1:9 c2:9 c3:9 c4:9 c5:9
+    [ "$status" -eq 1 ]
+    run dolt table rm-row test 0
+    [ "$status" -eq 1 ]
+    run dolt sql -q "select count(*) from test where c5 = 5"
+    [[ "$output" =~ "| 2 " ]] || false
+}
+
+@test "imports can only add rows to append-only tables" {
+    cat <<DELIM > more.csv
+pk,c1,c2,c3,c4,c5
+0,1,2,3,4,5
+1,1,2,3,4,5
+DELIM
+    run dolt table import -u test more.csv
+    [ "$status" -eq 0 ]
+    cat <<DELIM > changed.csv
+pk,c1,c2,c3,c4,c5
+1,9,9,9,9,9
+DELIM
+    run dolt table import -u test changed.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "test is append-only" ]] || false
+    run dolt table import -r test changed.csv
+    [ "$status" -eq 1 ]
+    run dolt sql -q "select c1 from test where pk = 1"
+    [[ ! "$output" =~ "9" ]] || false
+}
+
+@test "append-only can be turned off and survives schema changes" {
+    run dolt schema add-column test c6 int
+    [ "$status" -eq 0 ]
+    run dolt table append-only
+    [ "$output" = "test" ]
+    run dolt table append-only --off test
+    [ "$status" -eq 0 ]
+    run dolt table append-only
+    [ "$output" = "" ]
+    run dolt sql -q "delete from test where pk = 0"
+    [ "$status" -eq 0 ]
+}
+
+@test "merging append-only tables combines the rows added on each branch" {
+    dolt checkout -b other
+    dolt sql -q "insert into test values (1, 1, 2, 3, 4, 5)"
+    dolt add test
+    dolt commit -m "row 1 on other"
+    dolt checkout master
+    dolt sql -q "insert into test values (2, 1, 2, 3, 4, 5)"
+    dolt add test
+    dolt commit -m "row 2 on master"
+    run dolt merge other
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+    run dolt sql -q "select pk from test"
+    [[ "$output" =~ "| 0 " ]] || false
+    [[ "$output" =~ "| 1 " ]] || false
+    [[ "$output" =~ "| 2 " ]] || false
+    run dolt table append-only
+    [ "$output" = "test" ]
+}
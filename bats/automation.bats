@@ -0,0 +1,118 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    dolt table create -s `batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "create test"
+    cat <<DELIM > steps.json
+[
+  {"type":"import", "table":"test", "url":"`batshelper 1pk5col-ints.csv`", "mode":"replace"},
+  {"type":"sql", "query":"update test set c5 = 10 where pk = 1"},
+  {"type":"commit", "message":"scheduled import"}
+]
+DELIM
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "automation add, ls and rm" {
+    run dolt automation add nightly 24h steps.json
+    [ "$status" -eq 0 ]
+    run dolt automation ls
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "nightly	every 24h" ]] || false
+    [[ "$output" =~ "3. commit \"scheduled import\"" ]] || false
+    run dolt status
+    [[ "$output" =~ "dolt_automation" ]] || false
+    run dolt automation rm nightly
+    [ "$status" -eq 0 ]
+    run dolt automation ls
+    [ "$output" = "" ]
+    run dolt automation rm nightly
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "job 'nightly' not found" ]] || false
+}
+
+@test "automation add rejects invalid jobs" {
+    run dolt automation add nightly daily steps.json
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid schedule 'daily'" ]] || false
+    echo '[{"type":"merge"}]' > bad.json
+    run dolt automation add nightly 24h bad.json
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown step type 'merge'" ]] || false
+    run dolt automation ls
+    [ "$output" = "" ]
+}
+
+@test "automation run once imports, updates and commits" {
+    dolt automation add nightly 24h steps.json
+    dolt add dolt_automation
+    dolt commit -m "add nightly job"
+    run dolt automation run --once
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Running job nightly" ]] || false
+    run dolt log
+    [[ "$output" =~ "scheduled import" ]] || false
+    run dolt sql -q "select c5 from test where pk = 1"
+    [[ "$output" =~ "10" ]] || false
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    # nothing has changed, so the commit step is skipped
+    run dolt automation run --once nightly
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "nothing to commit" ]] || false
+}
+
+@test "automation run stops a job at its first failing step" {
+    cat <<DELIM > failing.json
+[
+  {"type":"sql", "query":"select * from missing"},
+  {"type":"commit", "message":"should not happen"}
+]
+DELIM
+    dolt automation add failing 1h failing.json
+    run dolt automation run --once failing
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "job failing failed at step 1" ]] || false
+    run dolt log
+    [[ ! "$output" =~ "should not happen" ]] || false
+    run dolt automation run --once missing
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "job 'missing' not found" ]] || false
+}
+
+@test "automation run pushes to a remote" {
+    mkdir remotedir
+    dolt remote add origin file://remotedir
+    echo '[{"type":"commit", "message":"scheduled commit"}, {"type":"push"}]' > push.json
+    dolt automation add publish 1h push.json
+    run dolt automation run --once publish
+    [ "$status" -eq 0 ]
+    mkdir clones
+    cd clones
+    dolt clone file://../remotedir test-repo
+    cd test-repo
+    run dolt log
+    [[ "$output" =~ "scheduled commit" ]] || false
+}
+
+@test "automation run mirrors every branch to a backup remote" {
+    mkdir backupdir
+    dolt remote add backup file://backupdir
+    dolt branch feature
+    echo '[{"type":"push", "remote":"backup", "mirror":true}]' > mirror.json
+    dolt automation add backup 10m mirror.json
+    run dolt automation ls
+    [[ "$output" =~ "push --mirror backup" ]] || false
+    run dolt automation run --once backup
+    [ "$status" -eq 0 ]
+    run dolt branch -a
+    [[ "$output" =~ "remotes/backup/feature" ]] || false
+    [[ "$output" =~ "remotes/backup/master" ]] || false
+}
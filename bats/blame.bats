@@ -129,3 +129,19 @@ setup_repository() {
     [ "$status" -eq 1 ]
     [[ "$output" =~ "no table named blame_test found" ]] || false
 }
+
+@test "dolt_blame system table shows the commit which last modified each row" {
+    run dolt sql -q "select pk, committer, message from dolt_blame_blame_test order by pk"
+    [ "$status" -eq 0 ]
+    [[ "${lines[3]}" =~ "| 1  | Thomas Foolery" ]] || false
+    [[ "${lines[3]}" =~ "create blame_test table" ]] || false
+    [[ "${lines[4]}" =~ "| 2  | Harry Wombat" ]] || false
+    [[ "${lines[4]}" =~ "replace richard with harry" ]] || false
+    [[ "${lines[5]}" =~ "| 3  | Johnny Moolah" ]] || false
+    [[ "${lines[6]}" =~ "| 4  | Johnny Moolah" ]] || false
+    run dolt sql -q "select email from dolt_blame_blame_test where pk = 2"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "bats-3@email.fake" ]] || false
+    run dolt sql -q "select * from dolt_blame_not_a_table"
+    [ "$status" -eq 1 ]
+}
@@ -117,3 +117,30 @@ teardown() {
     [[ "$output" =~ "test = local" ]] || false
     [[ ! "$output" =~ "test = global" ]] || false
 }
+
+@test "record commands to the command log" {
+    dolt config --global --add user.name "bats tester"
+    dolt config --global --add user.email "bats-tester@liquidata.co"
+    dolt status || true
+    [ ! -f $BATS_TMPDIR/config-test$$/.dolt/command_log.jsonl ]
+    dolt config --global --add metrics.command_log true
+    run dolt status
+    [ "$status" -ne 0 ]
+    dolt init
+    dolt table bogus || true
+    run cat $BATS_TMPDIR/config-test$$/.dolt/command_log.jsonl
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 4 ]
+    [[ "${lines[0]}" =~ '"command":"dolt config"' ]] || false
+    [[ "${lines[1]}" =~ '"command":"dolt status","duration_ms":' ]] || false
+    [[ "${lines[1]}" =~ '"repo_size":"none","error":"invalid_repo"' ]] || false
+    [[ "${lines[2]}" =~ '"command":"dolt init"' ]] || false
+    [[ ! "${lines[2]}" =~ '"error"' ]] || false
+    [[ "${lines[3]}" =~ '"command":"dolt table","duration_ms":' ]] || false
+    [[ "${lines[3]}" =~ '"repo_size":"small","error":"unknown_command"' ]] || false
+    dolt config --global --add metrics.command_log_file $BATS_TMPDIR/config-test$$/commands.jsonl
+    dolt status
+    run cat $BATS_TMPDIR/config-test$$/commands.jsonl
+    [ "${#lines[@]}" -eq 2 ]
+    [[ "${lines[1]}" =~ '"command":"dolt status"' ]] || false
+}
@@ -108,3 +108,54 @@ select * from will_be_broken;
 SQL
     [ "$status" -eq 1 ]
 }
+
+@test "show tables lists views" {
+    dolt sql <<SQL
+create table my_users (id int primary key);
+create view my_view as select id from my_users;
+SQL
+    run dolt sql -q "show full tables"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "my_users     | BASE TABLE" ]] || false
+    [[ "$output" =~ "my_view      | VIEW" ]] || false
+    dolt add .
+    dolt commit -m "added a view"
+    dolt sql -q "drop view my_view"
+    run dolt sql -q "show tables"
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "my_view" ]] || false
+    run dolt diff
+    [[ "$output" =~ "dolt_schemas" ]] || false
+}
+
+@test "materialized view results are recomputed on commit" {
+    dolt sql <<SQL
+create table sales (id int primary key, region varchar(20), amount int);
+insert into sales values (1, 'east', 10), (2, 'west', 5), (3, 'east', 7);
+SQL
+    run dolt schema materialize totals "select region, sum(amount) as total from sales group by region"
+    [ "$status" -eq 0 ]
+    run dolt sql -q "select total from totals where region = 'east'"
+    [ "$status" -eq 0 ]
+    [[ "${lines[3]}" =~ ' 17 ' ]] || false
+    dolt add .
+    dolt commit -m "added sales"
+    dolt sql -q "insert into sales values (4, 'east', 1)"
+    run dolt sql -q "select total from totals where region = 'east'"
+    [[ "${lines[3]}" =~ ' 17 ' ]] || false
+    dolt add sales
+    dolt commit -m "added more sales"
+    run dolt sql -q "select total from totals where region = 'east'"
+    [ "$status" -eq 0 ]
+    [[ "${lines[3]}" =~ ' 18 ' ]] || false
+    run dolt status
+    [[ "$output" =~ "nothing to commit" ]] || false
+    run dolt sql -q "select total from totals as of 'HEAD~1' where region = 'east'"
+    [[ "${lines[3]}" =~ ' 17 ' ]] || false
+    run dolt schema materialize sales "select 1"
+    [ "$status" -ne 0 ]
+    run dolt schema materialize --drop totals
+    [ "$status" -eq 0 ]
+    run dolt ls
+    [[ ! "$output" =~ "totals" ]] || false
+}
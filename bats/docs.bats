@@ -0,0 +1,49 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+}
+
+teardown() {
+    teardown_common
+}
+
+@test "dolt docs upload, print, ls and rm" {
+    echo "# My dataset" > README.md
+    run dolt docs upload README.md README.md
+    [ "$status" -eq 0 ]
+    echo "CC0" > LICENSE.md
+    run dolt docs upload LICENSE.md LICENSE.md
+    [ "$status" -eq 0 ]
+    run dolt docs ls
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "LICENSE.md" ]
+    [ "${lines[1]}" = "README.md" ]
+    run dolt docs print README.md
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "# My dataset" ]] || false
+    run dolt docs rm LICENSE.md
+    [ "$status" -eq 0 ]
+    run dolt docs print LICENSE.md
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "document 'LICENSE.md' not found" ]] || false
+}
+
+@test "dolt docs are versioned and visible over sql" {
+    echo "version one" > README.md
+    dolt docs upload README.md README.md
+    dolt add dolt_docs
+    dolt commit -m "added readme"
+    echo "version two" > README.md
+    dolt docs upload README.md README.md
+    run dolt sql -q "select doc_text from dolt_docs where doc_name = 'README.md'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "version two" ]] || false
+    run dolt diff
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "dolt_docs" ]] || false
+    dolt checkout dolt_docs
+    run dolt docs print README.md
+    [[ "$output" =~ "version one" ]] || false
+}
@@ -135,6 +135,14 @@ teardown() {
     [[ "${lines[0]}" =~ "usage" ]] || false
 }
 
+@test "dolt admin storage-report in a new repository" {
+    run dolt admin storage-report
+    [ "$status" -eq 0 ]
+    [[ "${lines[0]}" =~ "Table files:" ]] || false
+    [[ "$output" =~ "Duplicated:  0 chunks, 0 B (0.0%)" ]] || false
+    [[ "$output" =~ "Chunk sizes:" ]] || false
+}
+
 @test "dolt checkout master on master" {
     run dolt checkout master
     [ "$status" -eq 1 ]
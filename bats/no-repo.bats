@@ -224,6 +224,18 @@ NOT_VALID_REPO_ERROR="The current directory is not a valid dolt repository."
     [[ "$output" =~ "resolve -" ]] || false
 }
 
+@test "dolt admin outside of a dolt repository" {
+    run dolt admin
+    [ "$status" -ne 0 ]
+    [ "${lines[0]}" = "Valid commands for dolt admin are" ]
+    [[ "$output" =~ "storage-report -" ]] || false
+    [[ "$output" =~ "rechunk -" ]] || false
+    [[ "$output" =~ "rebuild-indexes -" ]] || false
+    run dolt admin storage-report
+    [ "$status" -ne 0 ]
+    [ "${lines[0]}" = "$NOT_VALID_REPO_ERROR" ]
+}
+
 @test "initializing a dolt repository" {
     mkdir dolt-repo-$$-new
     cd dolt-repo-$$-new
@@ -47,6 +47,57 @@ teardown() {
     [[ "$output" =~ "unknown remote poop" ]] || false
 }
 
+@test "list the refs of a remote with dolt ls-remote" {
+    dolt remote add test-remote http://localhost:50051/test-org/test-repo
+    dolt push test-remote master
+    dolt branch test-branch
+    dolt push test-remote test-branch
+    run dolt ls-remote test-remote
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [[ "${lines[0]}" =~ "refs/heads/master" ]] || false
+    [[ "${lines[1]}" =~ "refs/heads/test-branch" ]] || false
+    cd "dolt-repo-clones"
+    run dolt ls-remote http://localhost:50051/test-org/test-repo
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "refs/heads/master" ]] || false
+    run dolt ls-remote not-a-remote
+    [ "$status" -ne 0 ]
+}
+
+@test "compare a branch with a remote using dolt verify-remote" {
+    dolt remote add test-remote http://localhost:50051/test-org/test-repo
+    dolt sql -q "create table test (pk int, c1 int, primary key(pk))"
+    dolt add test
+    dolt commit -m "test table"
+    dolt push test-remote master
+    run dolt verify-remote test-remote master
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "'master' is up to date with 'test-remote'" ]] || false
+    dolt sql -q "insert into test values (1, 1)"
+    dolt sql -q "create table other (pk int, primary key(pk))"
+    dolt add .
+    dolt commit -m "changed tables"
+    run dolt verify-remote test-remote master
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "'master' has commits that haven't been pushed to 'test-remote'" ]] || false
+    [[ "$output" =~ "modified:" ]] || false
+    [[ "$output" =~ "only local:" ]] || false
+    [[ "$output" =~ "local chunks missing from test-remote" ]] || false
+    dolt push test-remote master
+    run dolt verify-remote test-remote master
+    [ "$status" -eq 0 ]
+    run dolt verify-remote test-remote test-branch
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown branch" ]] || false
+    dolt branch test-branch
+    run dolt verify-remote test-remote test-branch
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "'test-remote' has no branch 'test-branch'" ]] || false
+    run dolt verify-remote not-a-remote master
+    [ "$status" -eq 1 ]
+}
+
 @test "push and pull master branch from a remote" {
     dolt remote add test-remote http://localhost:50051/test-org/test-repo
     run dolt push test-remote master
@@ -95,6 +146,25 @@ teardown() {
     [[ "$output" =~ "test commit" ]] || false
 }
 
+@test "clone into an existing repository" {
+    dolt remote add test-remote http://localhost:50051/test-org/test-repo
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "test commit"
+    dolt push test-remote master
+    cd "dolt-repo-clones"
+    run dolt clone http://localhost:50051/test-org/test-repo
+    [ "$status" -eq 0 ]
+    [ ! -f test-repo/.dolt/clone_state.json ]
+    run dolt clone http://localhost:50051/test-org/test-repo
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "data repository already exists at test-repo" ]] || false
+    cd test-repo
+    run dolt log
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "test commit" ]] || false
+}
+
 @test "clone an empty remote" {
     skip "Cloning an empty remote is busted"
     run dolt clone localhost:50051/test-org/empty
@@ -365,6 +435,93 @@ teardown() {
     [[ "$output" = "$master_state1" ]] || false
 }
 
+@test "dolt push --mirror makes the remote branches match the local ones" {
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "test commit"
+    dolt branch feature
+    dolt branch other
+    mkdir backup
+    dolt remote add backup file://backup
+    dolt push backup other
+    dolt branch -d other
+
+    run dolt push --mirror backup
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "+ feature -> feature" ]] || false
+    [[ "$output" =~ "+ master -> master" ]] || false
+    [[ "$output" =~ "[deleted]         other" ]] || false
+    run dolt branch -a
+    [[ "$output" =~ "remotes/backup/feature" ]] || false
+    [[ ! "$output" =~ "remotes/backup/other" ]] || false
+    run dolt push --mirror backup
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Everything up-to-date" ]] || false
+
+    # rewrite master's history, which a regular push rejects
+    dolt table put-row test pk:0 c1:0 c2:0 c3:0 c4:0 c5:0
+    dolt add test
+    dolt commit -m "pushed commit"
+    dolt push backup master
+    dolt admin set-ref --force refs/heads/master HEAD~1
+    dolt reset --hard
+    dolt table put-row test pk:1 c1:1 c2:1 c3:1 c4:1 c5:1
+    dolt add test
+    dolt commit -m "replaced commit"
+    run dolt push backup master
+    [[ "$output" =~ "rejected" ]] || false
+    run dolt push --mirror
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "+ master -> master" ]] || false
+
+    run dolt ls-remote backup
+    [[ "$output" =~ "refs/heads/feature" ]] || false
+    [[ "$output" =~ "refs/heads/master" ]] || false
+    [[ ! "$output" =~ "refs/heads/other" ]] || false
+
+    cd dolt-repo-clones
+    dolt clone file://../backup mirror-clone
+    cd mirror-clone
+    run dolt log
+    [[ "$output" =~ "replaced commit" ]] || false
+    [[ ! "$output" =~ "pushed commit" ]] || false
+
+    run dolt push --mirror origin master
+    [ "$status" -eq 1 ]
+}
+
+@test "sql functions push, fetch and pull through a remote" {
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "test commit"
+    mkdir shared
+    dolt remote add origin file://shared
+    run dolt sql -q "select dolt_push('origin', 'master')"
+    [ "$status" -eq 0 ]
+    cd "dolt-repo-clones"
+    dolt clone file://../shared clone
+    cd clone
+    dolt table put-row test pk:0 c1:0 c2:0 c3:0 c4:0 c5:0
+    dolt add test
+    dolt commit -m "added a row in the clone"
+    run dolt sql -q "select dolt_push()"
+    [ "$status" -eq 0 ]
+    cd ../..
+    run dolt sql -q "select dolt_fetch()"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 1 " ]] || false
+    run dolt sql -q "select dolt_fetch('origin')"
+    [[ "$output" =~ "| 0 " ]] || false
+    run dolt sql -q "select dolt_pull()"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| 0 " ]] || false
+    run dolt log
+    [[ "$output" =~ "added a row in the clone" ]] || false
+    run dolt sql -q "select dolt_push('no_such_remote')"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown remote" ]] || false
+}
+
 @test "multiple remotes" {
     # seed with some data
     dolt table create -s=`batshelper 1pk5col-ints.schema` test
@@ -400,4 +557,51 @@ teardown() {
     run dolt branch -a
     [[ "$output" =~ "remotes/anything/master" ]] || false
     [[ "$output" =~ "remotes/something/master" ]] || false
-}
\ No newline at end of file
+}
+@test "ephemeral branches expire locally and on remotes" {
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "test commit"
+    mkdir shared
+    dolt remote add origin file://shared
+    dolt push origin master
+
+    run dolt branch --ttl 2x ci/bad
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid --ttl" ]] || false
+
+    dolt branch --ttl 5s ci/short
+    dolt branch --ttl 7d ci/long
+    dolt branch --ttl 5s ci/kept
+    run dolt branch -v
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ci/long".*"expires" ]] || false
+    [[ ! "$output" =~ "master".*"expires" ]] || false
+
+    dolt push origin ci/short
+    dolt push origin ci/kept
+    run dolt branch --promote ci/kept
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Branch 'ci/kept' is now permanent." ]] || false
+    dolt push origin ci/kept
+    sleep 5
+
+    # pushing any branch deletes the remote's expired branches
+    run dolt push origin ci/long
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "[expired]         ci/short" ]] || false
+    run dolt ls-remote origin
+    [[ "$output" =~ "refs/heads/ci/long" ]] || false
+    [[ "$output" =~ "refs/heads/ci/kept" ]] || false
+    [[ ! "$output" =~ "refs/heads/ci/short" ]] || false
+    run dolt branch -a
+    [[ ! "$output" =~ "remotes/origin/ci/short" ]] || false
+
+    run dolt branch --delete-expired
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Deleted branch ci/short" ]] || false
+    run dolt branch
+    [[ "$output" =~ "ci/long" ]] || false
+    [[ "$output" =~ "ci/kept" ]] || false
+    [[ ! "$output" =~ "ci/short" ]] || false
+}
@@ -27,6 +27,23 @@ teardown() {
     [[ "$output" =~ "cause: Schema from file does not match schema from existing table." ]] || false
 }
 
+@test "replace table using csv adding missing columns" {
+    run dolt table create -s `batshelper 1pk5col-ints.schema` test
+    [ "$status" -eq 0 ]
+    cat <<DELIM > extra-cols.csv
+pk,c1,c2,c3,c4,c5,c6
+0,1,2,3,4,5,6
+1,1,2,3,4,5,7
+DELIM
+    run dolt table import -r --add-missing-columns test extra-cols.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Added column: c6" ]] || false
+    [[ "$output" =~ "Rows Processed: 2, Additions: 2, Modifications: 0, Had No Effect: 0" ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt sql -q "select c6 from test where pk = 1"
+    [[ "$output" =~ "7" ]] || false
+}
+
 @test "replace table using psv" {
     run dolt table create -s `batshelper 1pk5col-ints.schema` test
     [ "$status" -eq 0 ]
@@ -157,3 +157,159 @@ teardown() {
     [[ "$output" =~ "<    PRIMARY KEY (\`pk\`)" ]] || false
     [[ "$output" =~ ">    PRIMARY KEY (\`c3\`)" ]] || false
 }
+
+@test "dolt schema provenance" {
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    run dolt schema provenance test --source-url https://example.com/test.csv --license CC0
+    [ "$status" -eq 0 ]
+    run dolt schema provenance test c1 --extracted 2019-12-01
+    [ "$status" -eq 0 ]
+    run dolt schema provenance test --license ODbL
+    [ "$status" -eq 0 ]
+    run dolt schema show test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "-- provenance: source_url: https://example.com/test.csv, license: ODbL" ]] || false
+    [[ "$output" =~ "-- provenance of \`c1\`: extracted: 2019-12-01" ]] || false
+    run dolt sql -q "select license from dolt_provenance where table_name = 'test' and column_name = ''"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ODbL" ]] || false
+    run dolt schema provenance test c9 --license CC0
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Column c9 unknown" ]] || false
+    run dolt schema provenance test
+    [ "$status" -eq 1 ]
+}
+
+@test "table and column comments in DDL, schema commands, exports and information_schema" {
+    dolt sql -q "create table test (pk bigint primary key comment 'The key', c1 bigint comment 'First value tag:10') comment='Test table'"
+    run dolt sql -q "show create table test"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "COMMENT 'The key tag:0'" ]] || false
+    [[ "$output" =~ "COMMENT 'First value tag:10'" ]] || false
+    [[ "$output" =~ "COMMENT='Test table'" ]] || false
+    dolt sql -q "alter table test comment='Renamed test table'"
+    dolt sql -q "alter table test add column c2 bigint comment 'Second value'"
+    run dolt schema comment test c1 "First value, updated"
+    [ "$status" -eq 0 ]
+    run dolt schema show test
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "COMMENT 'First value, updated tag:10'" ]] || false
+    [[ "$output" =~ "COMMENT 'Second value tag:" ]] || false
+    [[ "$output" =~ "COMMENT='Renamed test table'" ]] || false
+    run dolt schema export test export.json
+    [ "$status" -eq 0 ]
+    run cat export.json
+    [[ "$output" =~ '"comment": "Renamed test table"' ]] || false
+    [[ "$output" =~ '"comment": "Second value"' ]] || false
+    run dolt sql -q "select table_comment from information_schema.tables where table_name = 'test'"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Renamed test table" ]] || false
+    run dolt sql -q "select column_name, column_comment from information_schema.columns where table_name = 'test' order by column_name"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "| c1          | First value, updated |" ]] || false
+    [[ "$output" =~ "| c2          | Second value         |" ]] || false
+    run dolt schema comment test ""
+    [ "$status" -eq 0 ]
+    run dolt schema show test
+    [[ ! "$output" =~ "COMMENT='" ]] || false
+    run dolt schema comment test c9 "Nope"
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Column c9 unknown" ]] || false
+}
+
+@test "dolt schema diff reports drift and exits 1 on breaking changes" {
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "Added test table"
+    dolt branch v1
+    dolt schema add-column test c6 int
+    dolt table create -s=`batshelper 1pk5col-ints.schema` other
+    dolt add .
+    dolt commit -m "Added a column and a table"
+    run dolt schema diff v1 master
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "added table other" ]] || false
+    [[ "$output" =~ "test: added column c6 int" ]] || false
+    [[ ! "$output" =~ "breaking" ]] || false
+    dolt schema rename-column test c1 c1_new
+    dolt schema drop-column test c2
+    dolt add test
+    dolt commit -m "Renamed and dropped columns"
+    run dolt schema diff v1 HEAD
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "test: renamed column c1 to c1_new (breaking)" ]] || false
+    [[ "$output" =~ "test: removed column c2 (breaking)" ]] || false
+    [[ "$output" =~ "2 breaking schema changes" ]] || false
+    run dolt schema diff v1 HEAD other
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "added table other" ]] || false
+    [[ ! "$output" =~ "test:" ]] || false
+    run dolt schema diff HEAD v1
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "removed table other (breaking)" ]] || false
+    run dolt schema diff v1 HEAD nosuchtable
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown table 'nosuchtable'" ]] || false
+}
+
+@test "dolt schema diff against another repository" {
+    mkdir "$BATS_TMPDIR/schema-diff-repo-$$"
+    pushd "$BATS_TMPDIR/schema-diff-repo-$$"
+    dolt init
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt schema drop-column test c5
+    dolt add test
+    dolt commit -m "Added test table"
+    popd
+    dolt table create -s=`batshelper 1pk5col-ints.schema` test
+    dolt add test
+    dolt commit -m "Added test table"
+    run dolt schema diff --repo "$BATS_TMPDIR/schema-diff-repo-$$" HEAD master
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "test: removed column c5 (breaking)" ]] || false
+    rm -rf "$BATS_TMPDIR/schema-diff-repo-$$"
+}
+
+@test "counter and set merge strategies combine changes made on both branches" {
+    dolt sql -q "create table test (pk bigint primary key, hits bigint, tags varchar(80), note varchar(80))"
+    dolt sql -q "insert into test (pk, hits, tags, note) values (1, 10, 'a,b', 'x')"
+    run dolt schema merge-strategy test pk counter
+    [ "$status" -eq 1 ]
+    run dolt schema merge-strategy test note counter
+    [ "$status" -eq 1 ]
+    run dolt schema merge-strategy test hits sometimes
+    [ "$status" -eq 1 ]
+    dolt schema merge-strategy test hits counter
+    dolt schema merge-strategy test tags set
+    run dolt schema show test
+    [[ "$output" =~ "-- merge strategy of \`hits\`: counter" ]] || false
+    [[ "$output" =~ "-- merge strategy of \`tags\`: set" ]] || false
+    dolt add test
+    dolt commit -m "created test"
+    dolt branch other
+    dolt sql -q "update test set hits = 12, tags = 'a,b,c' where pk = 1"
+    dolt add test
+    dolt commit -m "on master"
+    dolt checkout other
+    dolt sql -q "update test set hits = 15, tags = 'b,d' where pk = 1"
+    dolt add test
+    dolt commit -m "on other"
+    dolt checkout master
+    run dolt merge other
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "CONFLICT" ]] || false
+    run dolt sql -q "select hits, tags from test where pk = 1"
+    [[ "$output" =~ "| 17   | b,c,d |" ]] || false
+    dolt add test
+    dolt commit -m "merged"
+    dolt checkout other
+    dolt sql -q "update test set note = 'y' where pk = 1"
+    dolt add test
+    dolt commit -m "note on other"
+    dolt checkout master
+    dolt sql -q "update test set note = 'z' where pk = 1"
+    dolt add test
+    dolt commit -m "note on master"
+    run dolt merge other
+    [[ "$output" =~ "CONFLICT" ]] || false
+}
@@ -23,4 +23,18 @@ teardown() {
     run dolt sql <<< "select * from test; This is bad sql; insert into test (pk) values (666); select * from test;"
     [ $status -eq 1 ]
     [[ ! "$output" =~ "666" ]] || false
-}
\ No newline at end of file
+}
+@test "transactions in sql shell" {
+    run dolt sql <<< "insert into test (pk) values (1); begin; insert into test (pk) values (2); rollback; start transaction; insert into test (pk) values (3); commit; begin; insert into test (pk) values (4);"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Rolled back" ]] || false
+    run dolt sql -q "select pk from test"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "| 1  |" ]] || false
+    [[ ! "$output" =~ "| 2  |" ]] || false
+    [[ "$output" =~ "| 3  |" ]] || false
+    [[ ! "$output" =~ "| 4  |" ]] || false
+    run dolt sql <<< "begin; begin;"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "already in progress" ]] || false
+}
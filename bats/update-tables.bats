@@ -93,3 +93,155 @@ teardown() {
     [[ "$output" =~ "Rows Processed: 3, Additions: 3, Modifications: 0, Had No Effect: 0" ]] || false
     [[ "$output" =~ "Import completed successfully." ]] || false
 }
+
+@test "update table adding missing columns from csv" {
+    run dolt table create -s `batshelper 1pk5col-ints.schema` test
+    [ "$status" -eq 0 ]
+    run dolt table import -u test `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 0 ]
+    dolt add test
+    dolt commit -m "initial data"
+    cat <<DELIM > extra-cols.csv
+pk,c1,c2,c3,c4,c5,c6,name
+0,1,2,3,4,5,6,zero
+2,1,2,3,4,5,1.5,two
+DELIM
+    run dolt table import -u test extra-cols.csv
+    [ "$status" -eq 0 ]
+    run dolt schema show test
+    [[ ! "$output" =~ "c6" ]] || false
+    run dolt table import -u --add-missing-columns test extra-cols.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Added column: c6" ]] || false
+    [[ "$output" =~ "Added column: name" ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt schema show test
+    [[ "$output" =~ "\`c6\` DOUBLE" ]] || false
+    [[ "$output" =~ "\`name\` TEXT" ]] || false
+    run dolt sql -q "select pk, c6, name from test where pk = 1"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "<NULL>" ]] || false
+    run dolt status
+    [[ "$output" =~ "Changes to be committed" ]] || false
+    [[ "$output" =~ "Changes not staged for commit" ]] || false
+    run dolt diff --schema
+    [[ ! "$output" =~ "c6" ]] || false
+    run dolt diff --schema HEAD
+    [[ "$output" =~ "c6" ]] || false
+}
+
+@test "update table adding missing columns not supported for json or create" {
+    run dolt table create -s `batshelper employees-sch.json` employees
+    [ "$status" -eq 0 ]
+    run dolt table import -u --add-missing-columns employees `batshelper employees-tbl.json`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "add-missing-columns is not supported for json files" ]] || false
+    run dolt table import -c --add-missing-columns test `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "fatal: add-missing-columns is only supported for update or replace operations" ]] || false
+}
+
+@test "update table from multiple csv files" {
+    run dolt table create -s `batshelper 1pk5col-ints.schema` test
+    [ "$status" -eq 0 ]
+    mkdir data
+    cat <<DELIM > data/part-1.csv
+pk,c1,c2,c3,c4,c5
+0,1,2,3,4,5
+1,1,2,3,4,5
+DELIM
+    cat <<DELIM > data/part-2.csv
+c5,c4,c3,c2,c1,pk
+50,40,30,20,10,2
+DELIM
+    run dolt table import -u test 'data/part-*.csv'
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Rows Processed: 3, Additions: 3, Modifications: 0, Had No Effect: 0" ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt sql -q "select c5 from test where pk = 2"
+    [[ "$output" =~ "50" ]] || false
+    run dolt table import -u --parallel test data/part-1.csv data/part-2.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Rows Processed: 3, Additions: 0, Modifications: 0, Had No Effect: 3" ]] || false
+    run dolt table import -u test 'data/nothing-*.csv'
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "no files match 'data/nothing-*.csv'" ]] || false
+}
+
+@test "update table using a saved mapping preset" {
+    dolt table create -s `batshelper 1pk5col-ints.schema` test
+    cat <<DELIM > renamed.csv
+id,a,b,c,d,e
+0,1,2,3,4,5
+1,1,2,3,4,5
+DELIM
+    cat <<DELIM > mapping.json
+{"id":"pk","a":"c1","b":"c2","c":"c3","d":"c4","e":"c5"}
+DELIM
+    run dolt table import -u test renamed.csv --mapping-preset vendor_a
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "mapping preset 'vendor_a' not found" ]] || false
+    run dolt table import -u -m mapping.json --save-mapping-preset vendor_a test renamed.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Saved mapping preset 'vendor_a'." ]] || false
+    run dolt ls
+    [[ "$output" =~ "dolt_mapping_presets" ]] || false
+    dolt sql -q "delete from test"
+    rm mapping.json
+    run dolt table import -u --mapping-preset vendor_a test renamed.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Rows Processed: 2, Additions: 2, Modifications: 0, Had No Effect: 0" ]] || false
+    run dolt table import -u --mapping-preset vendor_a -m mapping.json test renamed.csv
+    [ "$status" -eq 1 ]
+    run dolt table import -u --save-mapping-preset vendor_b test renamed.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "save-mapping-preset requires a mapping file" ]] || false
+}
+
+@test "update table resuming an interrupted import from its checkpoint" {
+    dolt sql -q "create table test (pk bigint, c1 bigint, primary key (pk))"
+    cat <<DELIM > data.csv
+pk,c1
+1,1
+2,2
+3,3
+4,x
+5,5
+DELIM
+    run dolt table import -u --checkpoint-rows 2 test data.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "A bad row was encountered" ]] || false
+    [[ "$output" =~ "resumed after the 2 rows imported" ]] || false
+    run dolt sql -q "select count(*) from test"
+    [[ "$output" =~ "| 0 " ]] || false
+    run dolt table import -u --resume test other.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "The interrupted import was a different import" ]] || false
+    cat <<DELIM > data.csv
+pk,c1
+1,1
+2,2
+3,3
+4,4
+5,5
+DELIM
+    run dolt table import -u --resume test data.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Resuming the import after 2 rows." ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt sql -q "select sum(c1) from test"
+    [[ "$output" =~ "| 15 " ]] || false
+    run dolt table import -u --resume test data.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "There is no interrupted import to resume." ]] || false
+}
+
+@test "import checkpointing not supported with parallel or stdin" {
+    dolt sql -q "create table test (pk bigint, c1 bigint, primary key (pk))"
+    run dolt table import -u --parallel --checkpoint-rows 2 test `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "are not supported with parallel" ]] || false
+    run dolt table import -u --checkpoint-rows 2 test < `batshelper 1pk5col-ints.csv`
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "not supported when importing from stdin" ]] || false
+}
@@ -68,18 +68,26 @@ func GenSubCommandHandler(commands []*Command) CommandFunc {
 
 		subCommandStr := strings.ToLower(strings.TrimSpace(args[0]))
 		if command, ok := commandMap[subCommandStr]; ok {
+			info := events.GetCommandInfoFromContext(ctx)
+			if info != nil {
+				info.Name = commandStr + " " + subCommandStr
+			}
+
 			if command.ReqRepo && !hasHelpFlag(args) {
 				if !dEnv.HasDoltDir() {
 					PrintErrln(color.RedString("The current directory is not a valid dolt repository."))
 					PrintErrln("run: dolt init before trying to run this command")
+					setErrorCategory(info, events.ErrCategoryInvalidRepo)
 					return 2
 				} else if dEnv.RSLoadErr != nil {
 					PrintErrln(color.RedString("The current directories repository state is invalid"))
 					PrintErrln(dEnv.RSLoadErr.Error())
+					setErrorCategory(info, events.ErrCategoryInvalidRepo)
 					return 2
 				} else if dEnv.DBLoadError != nil {
 					PrintErrln(color.RedString("Failed to load database."))
 					PrintErrln(dEnv.DBLoadError.Error())
+					setErrorCategory(info, events.ErrCategoryInvalidRepo)
 					return 2
 				}
 			}
@@ -96,17 +104,30 @@ func GenSubCommandHandler(commands []*Command) CommandFunc {
 				events.GlobalCollector.CloseEventAndAdd(evt)
 			}
 
+			if ret != 0 {
+				setErrorCategory(info, events.ErrCategoryFailed)
+			}
+
 			return ret
 		}
 
 		if !isHelp(subCommandStr) {
 			PrintErrln(color.RedString("Unknown Command " + subCommandStr))
+			setErrorCategory(events.GetCommandInfoFromContext(ctx), events.ErrCategoryUnknownCommand)
 		}
 		printUsage(commandStr, commands)
 		return 1
 	}
 }
 
+// setErrorCategory sets the category of error a command ended with on the command info given, unless a subcommand
+// already set one.
+func setErrorCategory(info *events.CommandInfo, category string) {
+	if info != nil && info.ErrorCategory == "" {
+		info.ErrorCategory = category
+	}
+}
+
 func isHelp(str string) bool {
 	switch {
 	case str == "-h":
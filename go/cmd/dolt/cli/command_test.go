@@ -20,7 +20,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/events"
 )
 
 const (
@@ -96,6 +99,38 @@ func TestCommands(t *testing.T) {
 	}
 }
 
+func TestCommandInfo(t *testing.T) {
+	succeeded := &trackedCommandFunc{}
+	failed := func(ctx context.Context, cmdStr string, args []string, dEnv *env.DoltEnv) int {
+		return 1
+	}
+	commands := &Command{Name: appName, Desc: "test application", Func: GenSubCommandHandler([]*Command{
+		{Name: "child1", Desc: "first child command", Func: succeeded.commandFunc},
+		{Name: "child2", Desc: "second child command", Func: GenSubCommandHandler([]*Command{
+			{Name: "grandchild1", Desc: "child2's first child", Func: failed},
+		})},
+	})}
+
+	tests := []struct {
+		commandLine string
+		expected    events.CommandInfo
+	}{
+		{"child1 arg0", events.CommandInfo{Name: "app child1"}},
+		{"child2 grandchild1 arg0", events.CommandInfo{Name: "app child2 grandchild1", ErrorCategory: events.ErrCategoryFailed}},
+		{"invalid", events.CommandInfo{ErrorCategory: events.ErrCategoryUnknownCommand}},
+		{"child2 invalid", events.CommandInfo{Name: "app child2", ErrorCategory: events.ErrCategoryUnknownCommand}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.commandLine, func(t *testing.T) {
+			info := &events.CommandInfo{}
+			ctx := events.NewContextForCommandInfo(context.Background(), info)
+			commands.Func(ctx, appName, strings.Split(test.commandLine, " "), nil)
+			assert.Equal(t, test.expected, *info)
+		})
+	}
+}
+
 func runCommand(root *Command, commandLine string) int {
 	tokens := strings.Split(commandLine, " ")
 
@@ -0,0 +1,27 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admincmds
+
+import (
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+)
+
+var Commands = cli.GenSubCommandHandler([]*cli.Command{
+	{Name: "storage-report", Desc: "Reports how the repository's chunks are stored, duplicated and garbage collectable.", Func: StorageReport, ReqRepo: true},
+	{Name: "rechunk", Desc: "Rewrites tables with new chunking parameters, which can improve deduplication of tables with very wide rows.", Func: Rechunk, ReqRepo: true},
+	{Name: "rebuild-indexes", Desc: "Rebuilds the secondary indexes and zone maps of tables from their rows, reporting any that were wrong.", Func: RebuildIndexes, ReqRepo: true},
+	{Name: "set-ref", Desc: "Points a ref of any type at a commit, or deletes it, for repairing repositories.", Func: SetRef, ReqRepo: true},
+	{Name: "list-refs", Desc: "Lists every ref and the commit it points at.", Func: ListRefs, ReqRepo: true},
+})
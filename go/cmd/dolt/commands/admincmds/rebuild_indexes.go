@@ -0,0 +1,134 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admincmds
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+const verifyParam = "verify"
+
+var rebuildIndexesShortDesc = "Rebuilds the secondary indexes and zone maps of tables from their rows"
+var rebuildIndexesLongDesc = "Rebuilds the secondary indexes and zone maps of the given tables in the working set, or of " +
+	"every table if none are given, from the tables' rows. Each index is compared with the index rebuilt for it, and " +
+	"the rows it was missing entries for, the entries it had that no row matches, and the entries pointing to the " +
+	"wrong row are reported, along with the number of row data chunks whose zone maps were missing or wrong.\n" +
+	"\n" +
+	"Indexes and zone maps are kept up to date as tables are written, so this is only needed to recover from bugs or " +
+	"from changes made to a repository's data by other means. Rebuilt tables show as modified until they are " +
+	"committed if anything was wrong with them.\n" +
+	"\n" +
+	"With <b>--verify</b>, nothing is written and the command fails if anything was found to be wrong."
+var rebuildIndexesSynopsis = []string{
+	"[--verify] [<table>...]",
+}
+
+func RebuildIndexes(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["table"] = "The tables to rebuild. Every table in the working set is rebuilt if none are given."
+	ap.SupportsFlag(verifyParam, "", "Only report what is wrong with the indexes and zone maps, without rebuilding them.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, rebuildIndexesShortDesc, rebuildIndexesLongDesc, rebuildIndexesSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	verr := rebuildIndexes(ctx, dEnv, apr.Args(), apr.Contains(verifyParam))
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+// rebuildIndexes rebuilds the indexes and zone maps of the tables with the names given in the working root, or all of
+// them if none are given, and reports what was wrong with them. If verifyOnly is true the working root isn't updated,
+// and an error is returned if anything was wrong.
+func rebuildIndexes(ctx context.Context, dEnv *env.DoltEnv, tblNames []string, verifyOnly bool) errhand.VerboseError {
+	root, err := dEnv.WorkingRoot(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("error: unable to read the working set").AddCause(err).Build()
+	}
+
+	if len(tblNames) == 0 {
+		tblNames, err = root.GetTableNames(ctx)
+
+		if err != nil {
+			return errhand.BuildDError("error: unable to read the tables of the working set").AddCause(err).Build()
+		}
+	}
+
+	inconsistent := 0
+	for _, tblName := range tblNames {
+		tbl, ok, err := root.GetTable(ctx, tblName)
+
+		if err != nil {
+			return errhand.BuildDError("error: unable to read %s", tblName).AddCause(err).Build()
+		} else if !ok {
+			return errhand.BuildDError("error: unknown table %s", tblName).Build()
+		}
+
+		tbl, report, err := tbl.RebuildIndexes(ctx)
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to rebuild the indexes of %s", tblName).AddCause(err).Build()
+		}
+
+		if report.IsConsistent() {
+			cli.Println(color.GreenString("%s: ok", tblName))
+			continue
+		}
+
+		inconsistent++
+		printRebuildReport(tblName, report)
+
+		if !verifyOnly {
+			root, err = root.PutTable(ctx, tblName, tbl)
+
+			if err != nil {
+				return errhand.BuildDError("error: failed to rebuild the indexes of %s", tblName).AddCause(err).Build()
+			}
+		}
+	}
+
+	if verifyOnly {
+		if inconsistent > 0 {
+			return errhand.BuildDError("error: %d of %d tables have indexes or zone maps that don't match their rows", inconsistent, len(tblNames)).Build()
+		}
+
+		return nil
+	}
+
+	if err := dEnv.UpdateWorkingRoot(ctx, root); err != nil {
+		return errhand.BuildDError("error: failed to update the working set").AddCause(err).Build()
+	}
+
+	return nil
+}
+
+func printRebuildReport(tblName string, report doltdb.RebuildReport) {
+	cli.Println(color.YellowString("%s:", tblName))
+
+	for _, idx := range report.Indexes {
+		cli.Printf("\tindex %s: %d missing, %d extra and %d mismatched entries\n", idx.Name, idx.Missing, idx.Extra, idx.Mismatched)
+	}
+
+	if report.StaleZoneMaps > 0 {
+		cli.Printf("\tzone maps: %d chunks with missing or wrong zone maps\n", report.StaleZoneMaps)
+	}
+}
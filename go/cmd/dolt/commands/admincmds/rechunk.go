@@ -0,0 +1,181 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admincmds
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	targetSizeParam = "target-size"
+	windowParam     = "window"
+)
+
+var rechunkShortDesc = "Rewrites tables with new chunking parameters"
+var rechunkLongDesc = "Rewrites the row data and indexes of the given tables in the working set, or of every table if none " +
+	"are given, so that they are split into chunks with the repository's chunking parameters.\n" +
+	"\n" +
+	"Row data is split into chunks where a rolling hash of the last <b>window</b> bytes matches a pattern which " +
+	"occurs once every <b>target size</b> bytes on average. The defaults are a target size of 4096 bytes and a " +
+	"window of 67 bytes, which suit most tables. Tables with very wide rows end up with a chunk boundary inside nearly " +
+	"every row, and dedupe better between versions with a larger target size.\n" +
+	"\n" +
+	"The parameters are stored in the <b>dolt_chunking</b> table, which <b>--target-size</b> and <b>--window</b> update " +
+	"in the working set before rewriting. Like any other table, it is committed, pushed and pulled along with the data, " +
+	"so that every clone of the repository splits the same rows into the same chunks. New parameters apply to all data " +
+	"written afterwards, but existing tables keep the chunks they were written with until they are rewritten by this " +
+	"command.\n" +
+	"\n" +
+	"Rewritten tables have the same rows, but show as modified until they are committed. Branches with different " +
+	"parameters can't be merged until one of them is rewritten with the parameters of the other."
+var rechunkSynopsis = []string{
+	"[--target-size <bytes>] [--window <bytes>] [<table>...]",
+}
+
+func Rechunk(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["table"] = "The tables to rewrite. Every table in the working set is rewritten if none are given."
+	ap.SupportsString(targetSizeParam, "", "bytes", "The average size of the chunks to split tables into, a power of two.")
+	ap.SupportsString(windowParam, "", "bytes", "The number of bytes the rolling hash that finds chunk boundaries is computed over.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, rechunkShortDesc, rechunkLongDesc, rechunkSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	root, err := dEnv.WorkingRoot(ctx)
+
+	if err != nil {
+		verr := errhand.BuildDError("error: unable to read the working set").AddCause(err).Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	root, cfg, verr := updateChunkingConfig(ctx, dEnv, root, apr)
+
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	verr = rechunkTables(ctx, dEnv, root, apr.Args())
+
+	if verr == nil {
+		cli.Printf("Chunking parameters: a target size of %d bytes and a window of %d bytes\n", cfg.TargetSize, cfg.Window)
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+// updateChunkingConfig stores the chunking parameters given on the command line, if any, in the chunking table of the
+// root given and sets them on the repository's database. It returns the updated root and the repository's parameters.
+func updateChunkingConfig(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, apr *argparser.ArgParseResults) (*doltdb.RootValue, types.ChunkingConfig, errhand.VerboseError) {
+	cfg, err := root.GetChunkingConfig(ctx)
+
+	if err != nil {
+		return nil, types.ChunkingConfig{}, errhand.BuildDError("error: invalid chunking parameters in %s", doltdb.ChunkingTableName).AddCause(err).Build()
+	}
+
+	updated := false
+	params := []struct {
+		name string
+		val  *uint32
+	}{
+		{targetSizeParam, &cfg.TargetSize},
+		{windowParam, &cfg.Window},
+	}
+
+	for _, param := range params {
+		if str, ok := apr.GetValue(param.name); ok {
+			n, err := strconv.ParseUint(str, 10, 32)
+
+			if err != nil {
+				return nil, types.ChunkingConfig{}, errhand.BuildDError("error: invalid --%s '%s'", param.name, str).Build()
+			}
+
+			*param.val = uint32(n)
+			updated = true
+		}
+	}
+
+	if !updated {
+		return root, cfg, nil
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, types.ChunkingConfig{}, errhand.BuildDError("error: invalid chunking parameters").AddCause(err).Build()
+	}
+
+	root, err = root.PutChunkingConfig(ctx, cfg)
+
+	if err != nil {
+		return nil, types.ChunkingConfig{}, errhand.BuildDError("error: failed to update %s", doltdb.ChunkingTableName).AddCause(err).Build()
+	}
+
+	if err := dEnv.DoltDB.SetChunkingConfig(cfg); err != nil {
+		return nil, types.ChunkingConfig{}, errhand.BuildDError("error: failed to set the chunking parameters").AddCause(err).Build()
+	}
+
+	return root, cfg, nil
+}
+
+// rechunkTables rewrites the tables with the names given in the root, or all of them if none are given, with the
+// chunking parameters of the repository's database, and makes the result the working root.
+func rechunkTables(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, tblNames []string) errhand.VerboseError {
+	var err error
+	if len(tblNames) == 0 {
+		tblNames, err = root.GetTableNames(ctx)
+
+		if err != nil {
+			return errhand.BuildDError("error: unable to read the tables of the working set").AddCause(err).Build()
+		}
+	}
+
+	for _, tblName := range tblNames {
+		tbl, ok, err := root.GetTable(ctx, tblName)
+
+		if err != nil {
+			return errhand.BuildDError("error: unable to read %s", tblName).AddCause(err).Build()
+		} else if !ok {
+			return errhand.BuildDError("error: unknown table %s", tblName).Build()
+		}
+
+		tbl, err = tbl.Rechunk(ctx)
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to rewrite %s", tblName).AddCause(err).Build()
+		}
+
+		root, err = root.PutTable(ctx, tblName, tbl)
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to rewrite %s", tblName).AddCause(err).Build()
+		}
+
+		cli.Println(color.GreenString("Rewrote %s", tblName))
+	}
+
+	if err := dEnv.UpdateWorkingRoot(ctx, root); err != nil {
+		return errhand.BuildDError("error: failed to update the working set").AddCause(err).Build()
+	}
+
+	return nil
+}
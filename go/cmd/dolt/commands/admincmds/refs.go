@@ -0,0 +1,250 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admincmds
+
+import (
+	"context"
+	"sort"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	oldParam    = "old"
+	deleteParam = "delete"
+	forceParam  = "force"
+)
+
+var setRefShortDesc = "Points a ref at a commit, or deletes it"
+var setRefLongDesc = "Points the ref given at the commit given, creating the ref if it doesn't exist. The ref must be a " +
+	"full ref name such as <b>refs/heads/master</b> or <b>refs/remotes/origin/master</b>, and the commit can be given " +
+	"by its hash or by any other commit spec. Unlike <b>dolt branch</b>, this sets refs of any type and doesn't check " +
+	"that the ref is moved to a descendant of the commit it pointed at, so it's meant for repairing repositories and " +
+	"for tooling.\n" +
+	"\n" +
+	"With <b>--old</b>, the ref is only changed if it currently points at the commit given, which guards against " +
+	"changes made since the ref was read. Moving the checked out branch leaves the working set and staged tables as " +
+	"they are, so that they show as changes relative to the new commit, and requires <b>--force</b>.\n" +
+	"\n" +
+	"With <b>--delete</b>, the ref is removed. The checked out branch can't be deleted."
+var setRefSynopsis = []string{
+	"[--old <commit>] [--force] <ref> <commit>",
+	"--delete [--old <commit>] <ref>",
+}
+
+func SetRef(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["ref"] = "The full name of the ref, starting with refs/."
+	ap.ArgListHelp["commit"] = "The commit the ref is pointed at."
+	ap.SupportsString(oldParam, "", "commit", "Only change the ref if it currently points at this commit.")
+	ap.SupportsFlag(deleteParam, "d", "Removes the ref.")
+	ap.SupportsFlag(forceParam, "f", "Allows the checked out branch to be moved.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, setRefShortDesc, setRefLongDesc, setRefSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	var verr errhand.VerboseError
+	if apr.Contains(deleteParam) {
+		verr = deleteRef(ctx, dEnv, apr)
+	} else {
+		verr = setRef(ctx, dEnv, apr)
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+func setRef(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() != 2 {
+		return errhand.BuildDError("Must specify a ref and a commit.").SetPrintUsage().Build()
+	}
+
+	dref, verr := parseFullRef(apr.Arg(0))
+	if verr != nil {
+		return verr
+	}
+
+	if ref.Equals(dref, dEnv.RepoState.Head.Ref) && !apr.Contains(forceParam) {
+		return errhand.BuildDError("error: %s is checked out. Use --force to move it.", dref.String()).Build()
+	}
+
+	cm, verr := resolveCommit(ctx, dEnv, apr.Arg(1))
+	if verr != nil {
+		return verr
+	}
+
+	if verr := checkOldCommit(ctx, dEnv, dref, apr); verr != nil {
+		return verr
+	}
+
+	if err := dEnv.DoltDB.SetRef(ctx, dref, cm); err != nil {
+		return errhand.BuildDError("error: failed to set %s", dref.String()).AddCause(err).Build()
+	}
+
+	return nil
+}
+
+func deleteRef(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.VerboseError {
+	if apr.NArg() != 1 {
+		return errhand.BuildDError("Must specify exactly one ref to delete.").SetPrintUsage().Build()
+	}
+
+	dref, verr := parseFullRef(apr.Arg(0))
+	if verr != nil {
+		return verr
+	}
+
+	if ref.Equals(dref, dEnv.RepoState.Head.Ref) {
+		return errhand.BuildDError("error: %s is checked out, so it can't be deleted.", dref.String()).Build()
+	}
+
+	if verr := checkOldCommit(ctx, dEnv, dref, apr); verr != nil {
+		return verr
+	}
+
+	err := dEnv.DoltDB.DeleteBranch(ctx, dref)
+
+	if err == doltdb.ErrBranchNotFound {
+		return errhand.BuildDError("error: %s not found", dref.String()).Build()
+	} else if err != nil {
+		return errhand.BuildDError("error: failed to delete %s", dref.String()).AddCause(err).Build()
+	}
+
+	return nil
+}
+
+// parseFullRef parses the ref name given, which must be a full ref name of a known type. Branch and remote tracking
+// branch names must also be valid.
+func parseFullRef(refStr string) (ref.DoltRef, errhand.VerboseError) {
+	if !ref.IsRef(refStr) {
+		return nil, errhand.BuildDError("error: '%s' is not a full ref name, such as refs/heads/master", refStr).Build()
+	}
+
+	dref, err := ref.Parse(refStr)
+
+	if err != nil {
+		return nil, errhand.BuildDError("error: invalid ref '%s'", refStr).AddCause(err).Build()
+	}
+
+	valid := dref.GetPath() != ""
+	switch dref.GetType() {
+	case ref.BranchRefType:
+		valid = doltdb.IsValidBranchRef(dref)
+	case ref.RemoteRefType:
+		valid = doltdb.IsValidUserBranchName(dref.(ref.RemoteRef).GetBranch())
+	}
+
+	if !valid {
+		return nil, errhand.BuildDError("error: invalid ref '%s'", refStr).Build()
+	}
+
+	return dref, nil
+}
+
+// checkOldCommit returns an error if the --old argument was given and the ref given doesn't point at it.
+func checkOldCommit(ctx context.Context, dEnv *env.DoltEnv, dref ref.DoltRef, apr *argparser.ArgParseResults) errhand.VerboseError {
+	oldStr, ok := apr.GetValue(oldParam)
+	if !ok {
+		return nil
+	}
+
+	old, verr := resolveCommit(ctx, dEnv, oldStr)
+	if verr != nil {
+		return verr
+	}
+
+	current, verr := resolveCommit(ctx, dEnv, dref.String())
+	if verr != nil {
+		return verr
+	}
+
+	oldHash, err := old.HashOf()
+	if err != nil {
+		return errhand.BuildDError("error: failed to read %s", oldStr).AddCause(err).Build()
+	}
+
+	currentHash, err := current.HashOf()
+	if err != nil {
+		return errhand.BuildDError("error: failed to read %s", dref.String()).AddCause(err).Build()
+	}
+
+	if oldHash != currentHash {
+		return errhand.BuildDError("error: %s points at %s rather than %s", dref.String(), currentHash.String(), oldHash.String()).Build()
+	}
+
+	return nil
+}
+
+func resolveCommit(ctx context.Context, dEnv *env.DoltEnv, cSpecStr string) (*doltdb.Commit, errhand.VerboseError) {
+	cs, err := doltdb.NewCommitSpec(cSpecStr, dEnv.RepoState.Head.Ref.String())
+
+	if err != nil {
+		return nil, errhand.BuildDError("error: invalid commit '%s'", cSpecStr).AddCause(err).Build()
+	}
+
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+
+	if err != nil {
+		return nil, errhand.BuildDError("error: unable to resolve '%s'", cSpecStr).AddCause(err).Build()
+	}
+
+	return cm, nil
+}
+
+var listRefsShortDesc = "Lists every ref and the commit it points at"
+var listRefsLongDesc = "Lists the branches, remote tracking branches and internal refs of the repository, in order of " +
+	"name, along with the hash of the commit each points at."
+var listRefsSynopsis = []string{""}
+
+func ListRefs(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	help, usage := cli.HelpAndUsagePrinters(commandStr, listRefsShortDesc, listRefsLongDesc, listRefsSynopsis, ap)
+	cli.ParseArgs(ap, args, help)
+
+	verr := listRefs(ctx, dEnv)
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+func listRefs(ctx context.Context, dEnv *env.DoltEnv) errhand.VerboseError {
+	refs, err := dEnv.DoltDB.GetRefs(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to read refs").AddCause(err).Build()
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].String() < refs[j].String()
+	})
+
+	for _, dref := range refs {
+		cm, verr := resolveCommit(ctx, dEnv, dref.String())
+		if verr != nil {
+			return verr
+		}
+
+		h, err := cm.HashOf()
+		if err != nil {
+			return errhand.BuildDError("error: failed to read %s", dref.String()).AddCause(err).Build()
+		}
+
+		cli.Printf("%s\t%s\n", h.String(), dref.String())
+	}
+
+	return nil
+}
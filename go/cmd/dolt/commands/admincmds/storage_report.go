@@ -0,0 +1,100 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admincmds
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/nbs"
+)
+
+var storageReportShortDesc = "Reports how the repository's chunks are stored"
+var storageReportLongDesc = "Scans the table files of the repository and reports:\n" +
+	"\n" +
+	"A histogram of the sizes of the chunks stored, after compression.\n" +
+	"\n" +
+	"The number of chunks and bytes held by each table file.\n" +
+	"\n" +
+	"Chunks stored in more than one table file. Each copy after the first is attributed to the table file holding " +
+	"it, and would be removed by conjoining the table files.\n" +
+	"\n" +
+	"Chunks which are not reachable from any branch, remote or working set, which garbage collection would remove.\n" +
+	"\n" +
+	"Reachability is computed by reading every reachable chunk, so the report can take a while on large repositories."
+var storageReportSynopsis = []string{""}
+
+func StorageReport(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	help, usage := cli.HelpAndUsagePrinters(commandStr, storageReportShortDesc, storageReportLongDesc, storageReportSynopsis, ap)
+	cli.ParseArgs(ap, args, help)
+
+	roots := []hash.Hash{dEnv.RepoState.WorkingHash(), dEnv.RepoState.StagedHash()}
+	if dEnv.RepoState.Merge != nil {
+		roots = append(roots, hash.Parse(dEnv.RepoState.Merge.PreMergeWorking))
+	}
+
+	report, err := dEnv.DoltDB.StorageReport(ctx, roots)
+
+	if err != nil {
+		verr := errhand.BuildDError("error: failed to build the storage report").AddCause(err).Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	printStorageReport(report)
+	return 0
+}
+
+func printStorageReport(report nbs.StorageReport) {
+	cli.Printf("Table files: %d\n", len(report.Tables))
+	cli.Printf("Chunks:      %s stored, %s unique, %s\n", humanize.Comma(int64(report.TotalChunks)), humanize.Comma(int64(report.UniqueChunks)), humanize.Bytes(report.TotalBytes))
+	cli.Printf("Duplicated:  %s chunks, %s (%s)\n", humanize.Comma(int64(report.DuplicateChunks)), humanize.Bytes(report.DuplicateBytes), percent(report.DuplicateBytes, report.TotalBytes))
+	cli.Printf("Garbage:     %s chunks, %s (%s)\n", humanize.Comma(int64(report.GarbageChunks)), humanize.Bytes(report.GarbageBytes), percent(report.GarbageBytes, report.TotalBytes))
+
+	cli.Println()
+	cli.Println("Chunk sizes:")
+	for _, b := range report.ChunkSizes {
+		cli.Printf("  <= %-8s %10s chunks %10s\n", humanize.IBytes(b.UpperBound), humanize.Comma(int64(b.Chunks)), humanize.Bytes(b.Bytes))
+	}
+
+	cli.Println()
+	cli.Printf("%-32s %10s %10s %21s %21s\n", "Table file", "chunks", "size", "duplicated", "garbage")
+	for _, t := range report.Tables {
+		cli.Printf("%-32s %10s %10s %10s %10s %10s %10s\n",
+			t.Name,
+			humanize.Comma(int64(t.Chunks)),
+			humanize.Bytes(t.Bytes),
+			humanize.Comma(int64(t.DuplicateChunks)),
+			humanize.Bytes(t.DuplicateBytes),
+			humanize.Comma(int64(t.GarbageChunks)),
+			humanize.Bytes(t.GarbageBytes))
+	}
+}
+
+func percent(n, total uint64) string {
+	if total == 0 {
+		return "0%"
+	}
+
+	return fmt.Sprintf("%.1f%%", 100*float64(n)/float64(total))
+}
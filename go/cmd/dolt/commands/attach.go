@@ -0,0 +1,93 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+)
+
+// AttachHelp describes the repositories that can be attached to a SQL engine with AttachedDatabases.
+var AttachHelp = `Other dolt repositories can be attached as additional databases with --attach name=location, or a comma separated
+list of them, so that queries can join their tables with the tables of this repository without merging them, as in
+SELECT * FROM people JOIN other.addresses ON people.id = other.addresses.person_id. The location is the path of a local
+repository, the name of one of this repository's remotes or the url of a remote repository, given as to dolt remote
+add. The chunks of remote repositories are fetched as queries read them, rather than cloning them. Attached databases
+are read-only and read the head of the master branch, and other commits with AS OF.`
+
+// AttachedDatabases returns the databases of the repositories given by the attach argument, a comma separated list of
+// name=location pairs as described by AttachHelp.
+func AttachedDatabases(ctx context.Context, dEnv *env.DoltEnv, attachArg string) ([]*dsqle.AttachedDatabase, error) {
+	var dbs []*dsqle.AttachedDatabase
+	names := map[string]bool{"dolt": true}
+	for _, pair := range strings.Split(attachArg, ",") {
+		tokens := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+			return nil, fmt.Errorf("invalid attached database '%s', expected name=location", pair)
+		}
+
+		name, location := tokens[0], tokens[1]
+		if !doltdb.IsValidTableName(name) {
+			return nil, fmt.Errorf("invalid attached database name '%s'", name)
+		} else if names[strings.ToLower(name)] {
+			return nil, fmt.Errorf("there is already a database named '%s'", name)
+		}
+		names[strings.ToLower(name)] = true
+
+		ddb, err := OpenDoltDB(ctx, dEnv, location)
+		if err != nil {
+			return nil, fmt.Errorf("unable to attach '%s': %v", location, err)
+		}
+
+		db, err := dsqle.NewAttachedDatabase(ctx, name, ddb, doltdb.MasterBranch)
+		if err != nil {
+			return nil, fmt.Errorf("unable to attach '%s': %v", location, err)
+		}
+
+		dbs = append(dbs, db)
+	}
+
+	return dbs, nil
+}
+
+// OpenDoltDB opens the database of the repository at the location given, which is the name of a remote, the path of a
+// local repository or the url of a remote repository. The chunks of a remote repository are fetched as they're read.
+func OpenDoltDB(ctx context.Context, dEnv *env.DoltEnv, location string) (*doltdb.DoltDB, error) {
+	if r, ok := dEnv.RepoState.Remotes[location]; ok {
+		return r.GetRemoteDB(ctx, dEnv.DoltDB.Format())
+	}
+
+	var url string
+	var err error
+	dataDir := filepath.Join(location, dbfactory.DoltDataDir)
+	if exists, isDir := dEnv.FS.Exists(dataDir); exists && isDir {
+		url, err = getAbsFileRemoteUrl(dataDir, dEnv.FS)
+	} else {
+		_, url, err = getAbsRemoteUrl(dEnv.FS, dEnv.Config, location)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return doltdb.LoadDoltDB(ctx, dEnv.DoltDB.Format(), url)
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automationcmds
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+var addShortDesc = "Adds or replaces an automation job"
+var addLongDesc = "Stores a job, which is run by <b>dolt automation run</b> once every interval given by its schedule, in the " +
+	doltdb.AutomationTableName + " table of the working set, replacing any job with the same name. The schedule is an " +
+	"interval such as 30m, 6h or 24h. Like any other table, the automation table is staged and committed, so a " +
+	"dataset's update jobs are versioned and shared along with its data.\n" +
+	"\n" + StepsFileHelp
+var addSynopsis = []string{"<job> <schedule> <steps_file>"}
+
+func Add(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["job"] = "name of the job to add."
+	ap.ArgListHelp["schedule"] = "interval between runs of the job, such as 30m or 24h."
+	ap.ArgListHelp["steps_file"] = "json file listing the steps of the job."
+	help, usage := cli.HelpAndUsagePrinters(commandStr, addShortDesc, addLongDesc, addSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 3 {
+		usage()
+		return 1
+	}
+
+	name, schedule, path := apr.Arg(0), apr.Arg(1), apr.Arg(2)
+
+	if _, err := parseSchedule(schedule); err != nil {
+		return commands.HandleVErrAndExitCode(errhand.BuildDError("error: %v", err).Build(), usage)
+	}
+
+	data, err := dEnv.FS.ReadFile(path)
+
+	if err != nil {
+		verr := errhand.BuildDError("error: failed to read '%s'", path).AddCause(err).Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	if _, err := parseSteps(data); err != nil {
+		verr := errhand.BuildDError("error: failed to read the steps in '%s'", path).AddCause(err).Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr == nil {
+		root, err = root.PutAutomationJob(ctx, doltdb.AutomationJob{Name: name, Schedule: schedule, Steps: string(data)})
+
+		if err != nil {
+			verr = errhand.BuildDError("error: failed to write job '%s'", name).AddCause(err).Build()
+		} else {
+			verr = commands.UpdateWorkingWithVErr(dEnv, root)
+		}
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
@@ -0,0 +1,26 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automationcmds
+
+import (
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+)
+
+var Commands = cli.GenSubCommandHandler([]*cli.Command{
+	{Name: "ls", Desc: "Lists the automation jobs in the working set.", Func: Ls, ReqRepo: true},
+	{Name: "add", Desc: "Adds or replaces an automation job.", Func: Add, ReqRepo: true},
+	{Name: "rm", Desc: "Removes automation jobs.", Func: Rm, ReqRepo: true},
+	{Name: "run", Desc: "Runs automation jobs on their schedules.", Func: Run, ReqRepo: true},
+})
@@ -0,0 +1,374 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automationcmds
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands/tblcmds"
+	eventsapi "github.com/liquidata-inc/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/events"
+)
+
+const (
+	importStep = "import"
+	sqlStep    = "sql"
+	commitStep = "commit"
+	pushStep   = "push"
+
+	updateMode  = "update"
+	replaceMode = "replace"
+	createMode  = "create"
+
+	defaultRemote = "origin"
+)
+
+var StepsFileHelp = "A steps file is a json list of the steps of a job, which are run in order:" + `
+[
+	{"type":"import", "table":"<b>TABLE</b>", "url":"<b>URL_OR_PATH</b>", "mode":"update|replace|create", "mapping_preset":"<b>NAME</b>", "file_type":"<b>TYPE</b>"},
+	{"type":"sql", "query":"<b>QUERY</b>"},
+	{"type":"commit", "message":"<b>MESSAGE</b>"},
+	{"type":"push", "remote":"<b>REMOTE</b>", "branch":"<b>BRANCH</b>", "mirror":true}
+]
+
+An import step imports a file, downloading it first if url is an http or https url, into table. mode defaults to update,
+and create overwrites any existing table. mapping_preset and file_type are optional, and are passed on to dolt table import.
+
+A sql step runs a query against the working set.
+
+A commit step stages all changes and commits them with the message given.  It is skipped if nothing has changed.
+
+A push step pushes a branch, by default the current branch, to a remote, by default origin. If mirror is true, every
+branch is pushed instead, making the remote's branches match the local ones exactly as dolt push --mirror does, which
+keeps a backup remote in sync on the job's schedule.
+`
+
+// step is a single step of an automation job.
+type step struct {
+	Type          string `json:"type"`
+	Table         string `json:"table,omitempty"`
+	URL           string `json:"url,omitempty"`
+	Mode          string `json:"mode,omitempty"`
+	MappingPreset string `json:"mapping_preset,omitempty"`
+	FileType      string `json:"file_type,omitempty"`
+	Query         string `json:"query,omitempty"`
+	Message       string `json:"message,omitempty"`
+	Remote        string `json:"remote,omitempty"`
+	Branch        string `json:"branch,omitempty"`
+	Mirror        bool   `json:"mirror,omitempty"`
+}
+
+// job is an automation job whose schedule and steps have been parsed.
+type job struct {
+	name     string
+	schedule string
+	interval time.Duration
+	steps    []step
+}
+
+// parseJob parses the schedule and steps of a job stored in the automation table.
+func parseJob(stored doltdb.AutomationJob) (job, error) {
+	interval, err := parseSchedule(stored.Schedule)
+
+	if err != nil {
+		return job{}, err
+	}
+
+	steps, err := parseSteps([]byte(stored.Steps))
+
+	if err != nil {
+		return job{}, err
+	}
+
+	return job{stored.Name, stored.Schedule, interval, steps}, nil
+}
+
+// parseSchedule parses a schedule, which is the interval between the runs of a job, such as 30m or 24h.
+func parseSchedule(schedule string) (time.Duration, error) {
+	interval, err := time.ParseDuration(schedule)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule '%s', expected an interval such as 30m or 24h", schedule)
+	} else if interval <= 0 {
+		return 0, fmt.Errorf("invalid schedule '%s', the interval must be positive", schedule)
+	}
+
+	return interval, nil
+}
+
+// parseSteps parses and validates the json list of a job's steps.
+func parseSteps(data []byte) ([]step, error) {
+	var steps []step
+	err := json.Unmarshal(data, &steps)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid steps: %v", err)
+	} else if len(steps) == 0 {
+		return nil, errors.New("a job must have at least one step")
+	}
+
+	for i, s := range steps {
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("invalid step %d: %v", i+1, err)
+		}
+	}
+
+	return steps, nil
+}
+
+func (s step) validate() error {
+	switch s.Type {
+	case importStep:
+		if s.Table == "" || s.URL == "" {
+			return errors.New("import steps require a table and a url")
+		} else if !doltdb.IsValidTableName(s.Table) {
+			return fmt.Errorf("'%s' is not a valid table name", s.Table)
+		}
+
+		switch s.Mode {
+		case "", updateMode, replaceMode, createMode:
+		default:
+			return fmt.Errorf("unknown import mode '%s'", s.Mode)
+		}
+	case sqlStep:
+		if s.Query == "" {
+			return errors.New("sql steps require a query")
+		}
+	case commitStep:
+		if s.Message == "" {
+			return errors.New("commit steps require a message")
+		}
+	case pushStep:
+		if s.Mirror && s.Branch != "" {
+			return errors.New("mirror push steps push every branch, so they can't have a branch")
+		}
+	default:
+		return fmt.Errorf("unknown step type '%s'", s.Type)
+	}
+
+	return nil
+}
+
+// String returns a description of the step.
+func (s step) String() string {
+	switch s.Type {
+	case importStep:
+		mode := s.Mode
+		if mode == "" {
+			mode = updateMode
+		}
+
+		return fmt.Sprintf("import %s into %s (%s)", s.URL, s.Table, mode)
+	case sqlStep:
+		return "sql " + s.Query
+	case commitStep:
+		return fmt.Sprintf("commit \"%s\"", s.Message)
+	case pushStep:
+		if s.Mirror {
+			return "push --mirror " + s.remote()
+		}
+
+		return strings.TrimSpace(fmt.Sprintf("push %s %s", s.remote(), s.Branch))
+	}
+
+	return s.Type
+}
+
+func (s step) remote() string {
+	if s.Remote == "" {
+		return defaultRemote
+	}
+
+	return s.Remote
+}
+
+// run runs the step against the repository of the environment given. Each step is run by the same dolt command that
+// would run it by hand, in this process and against the same environment, so later steps see the changes made by earlier
+// ones.
+func (s step) run(ctx context.Context, dEnv *env.DoltEnv) error {
+	switch s.Type {
+	case importStep:
+		return s.runImport(ctx, dEnv)
+	case sqlStep:
+		return runDolt(ctx, dEnv, sqlCmd, "-q", s.Query)
+	case commitStep:
+		if unchanged, err := dEnv.IsUnchangedFromHead(ctx); err != nil {
+			return err
+		} else if unchanged {
+			cli.Println("nothing to commit")
+			return nil
+		}
+
+		if err := runDolt(ctx, dEnv, addCmd, "."); err != nil {
+			return err
+		}
+
+		return runDolt(ctx, dEnv, commitCmd, "-m", s.Message)
+	case pushStep:
+		if s.Mirror {
+			return runDolt(ctx, dEnv, pushCmd, "--"+commands.MirrorFlag, s.remote())
+		}
+
+		branch := s.Branch
+		if branch == "" {
+			branch = dEnv.RepoState.Head.Ref.GetPath()
+		}
+
+		return runDolt(ctx, dEnv, pushCmd, s.remote(), branch)
+	}
+
+	return fmt.Errorf("unknown step type '%s'", s.Type)
+}
+
+func (s step) runImport(ctx context.Context, dEnv *env.DoltEnv) error {
+	file := s.URL
+	if u, err := url.Parse(s.URL); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		dir, err := ioutil.TempDir("", "dolt-automation")
+
+		if err != nil {
+			return err
+		}
+
+		defer os.RemoveAll(dir)
+
+		file, err = download(ctx, u, dir)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	var args []string
+	switch s.Mode {
+	case replaceMode:
+		args = append(args, "-r")
+	case createMode:
+		args = append(args, "-c", "-f")
+	default:
+		args = append(args, "-u")
+	}
+
+	if s.MappingPreset != "" {
+		args = append(args, "--mapping-preset", s.MappingPreset)
+	}
+
+	if s.FileType != "" {
+		args = append(args, "--file-type", s.FileType)
+	}
+
+	return runDolt(ctx, dEnv, importCmd, append(args, s.Table, file)...)
+}
+
+// download downloads the file at the url given into the directory given, keeping the file's name so that its type can
+// be inferred from its extension, and returns its path.
+func download(ctx context.Context, u *url.URL, dir string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", u.String(), resp.Status)
+	}
+
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = "download"
+	}
+
+	file := filepath.Join(dir, name)
+	f, err := os.Create(file)
+
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(f, resp.Body)
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+// The dolt commands run by steps, along with the events they are reported under when run by hand.
+var (
+	importCmd = cli.Command{Name: "table import", Func: tblcmds.Import, EventType: eventsapi.ClientEventType_TABLE_IMPORT}
+	sqlCmd    = cli.Command{Name: "sql", Func: commands.Sql, EventType: eventsapi.ClientEventType_SQL}
+	addCmd    = cli.Command{Name: "add", Func: commands.Add, EventType: eventsapi.ClientEventType_ADD}
+	commitCmd = cli.Command{Name: "commit", Func: commands.Commit, EventType: eventsapi.ClientEventType_COMMIT}
+	pushCmd   = cli.Command{Name: "push", Func: commands.Push, EventType: eventsapi.ClientEventType_PUSH}
+)
+
+// runDolt runs the dolt command given against the environment given, with its output going to the cli's output as it
+// would when run by hand. Commands report their own errors, so a failing command only needs to be named.
+func runDolt(ctx context.Context, dEnv *env.DoltEnv, cmd cli.Command, args ...string) error {
+	evt := events.NewEvent(cmd.EventType)
+	exitCode := cmd.Func(events.NewContextForEvent(ctx, evt), "dolt "+cmd.Name, args, dEnv)
+	events.GlobalCollector.CloseEventAndAdd(evt)
+
+	if exitCode != 0 {
+		return fmt.Errorf("dolt %s failed with exit code %d", cmd.Name, exitCode)
+	}
+
+	return nil
+}
+
+// refreshEnv brings the environment's view of its repository up to date with changes made outside of this process since
+// it was loaded.
+func refreshEnv(ctx context.Context, dEnv *env.DoltEnv) error {
+	if err := dEnv.DoltDB.Rebase(ctx); err != nil {
+		return err
+	}
+
+	rs, err := env.LoadRepoState(dEnv.FS)
+
+	if err != nil {
+		return err
+	}
+
+	*dEnv.RepoState = *rs
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automationcmds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+)
+
+func TestParseJob(t *testing.T) {
+	stored := doltdb.AutomationJob{
+		Name:     "nightly",
+		Schedule: "24h",
+		Steps: `[
+			{"type":"import", "table":"prices", "url":"https://example.com/prices.csv", "mode":"replace"},
+			{"type":"sql", "query":"delete from prices where price < 0"},
+			{"type":"commit", "message":"nightly prices"},
+			{"type":"push"},
+			{"type":"push", "remote":"backup", "mirror":true}
+		]`,
+	}
+
+	j, err := parseJob(stored)
+	require.NoError(t, err)
+	assert.Equal(t, "nightly", j.name)
+	assert.Equal(t, 24*time.Hour, j.interval)
+
+	var descs []string
+	for _, s := range j.steps {
+		descs = append(descs, s.String())
+	}
+
+	assert.Equal(t, []string{
+		"import https://example.com/prices.csv into prices (replace)",
+		"sql delete from prices where price < 0",
+		`commit "nightly prices"`,
+		"push origin",
+		"push --mirror backup",
+	}, descs)
+}
+
+func TestParseJobErrors(t *testing.T) {
+	tests := []struct {
+		schedule string
+		steps    string
+	}{
+		{"daily", `[{"type":"push"}]`},
+		{"-1h", `[{"type":"push"}]`},
+		{"1h", `[]`},
+		{"1h", `{"type":"push"}`},
+		{"1h", `[{"type":"merge"}]`},
+		{"1h", `[{"type":"import", "table":"prices"}]`},
+		{"1h", `[{"type":"import", "table":"prices", "url":"prices.csv", "mode":"append"}]`},
+		{"1h", `[{"type":"import", "table":"1prices", "url":"prices.csv"}]`},
+		{"1h", `[{"type":"sql"}]`},
+		{"1h", `[{"type":"commit"}]`},
+		{"1h", `[{"type":"push", "branch":"master", "mirror":true}]`},
+	}
+
+	for _, test := range tests {
+		_, err := parseJob(doltdb.AutomationJob{Name: "job", Schedule: test.schedule, Steps: test.steps})
+		assert.Error(t, err, "schedule: %s, steps: %s", test.schedule, test.steps)
+	}
+}
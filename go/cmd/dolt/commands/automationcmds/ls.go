@@ -0,0 +1,64 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automationcmds
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+var lsShortDesc = "Lists the automation jobs in the working set"
+var lsLongDesc = "Lists the jobs stored in the working set, with their schedules and steps."
+var lsSynopsis = []string{""}
+
+func Ls(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	help, usage := cli.HelpAndUsagePrinters(commandStr, lsShortDesc, lsLongDesc, lsSynopsis, ap)
+	cli.ParseArgs(ap, args, help)
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr == nil {
+		stored, err := root.GetAutomationJobs(ctx)
+
+		if err != nil {
+			verr = errhand.BuildDError("error: failed to read automation jobs").AddCause(err).Build()
+		}
+
+		for _, sj := range stored {
+			j, err := parseJob(sj)
+
+			if err != nil {
+				cli.Println(fmt.Sprintf("%s\t%s", sj.Name, color.RedString("invalid: %v", err)))
+				continue
+			}
+
+			cli.Println(fmt.Sprintf("%s\tevery %s", j.name, j.schedule))
+			for i, s := range j.steps {
+				cli.Println(fmt.Sprintf("\t%d. %s", i+1, s))
+			}
+		}
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
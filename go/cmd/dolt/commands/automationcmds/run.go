@@ -0,0 +1,183 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package automationcmds
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	onceParam = "once"
+
+	// pollInterval is the longest the runner waits before reading the jobs again, so that jobs added, changed or
+	// removed while it runs are picked up.
+	pollInterval = time.Minute
+)
+
+var runShortDesc = "Runs automation jobs on their schedules"
+var runLongDesc = "Runs the jobs stored in the " + doltdb.AutomationTableName + " table of the working set, or only the " +
+	"jobs given, until interrupted. Each job is run when the runner starts and then once every interval given by its " +
+	"schedule. The jobs are read again from the working set between runs, so changes to them, including changes pulled " +
+	"or merged from other branches, take effect without restarting the runner.\n" +
+	"\n" +
+	"A job's steps are run in order, and the job stops at the first step that fails. The runner keeps running the " +
+	"job on its schedule.\n" +
+	"\n" +
+	"If <b>--once</b> is given, each job is run a single time and the command exits, with an error if any job failed. " +
+	"This can be used to run jobs from an external scheduler such as cron."
+var runSynopsis = []string{"[--once] [<job>...]"}
+
+func Run(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["job"] = "names of the jobs to run. Defaults to all jobs."
+	ap.SupportsFlag(onceParam, "", "Run each job once and exit.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, runShortDesc, runLongDesc, runSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	jobs, verr := loadJobs(ctx, dEnv, apr.Args())
+
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	if apr.Contains(onceParam) {
+		failed := false
+		for _, j := range jobs {
+			if !runJob(ctx, dEnv, j) {
+				failed = true
+			}
+		}
+
+		if failed {
+			return 1
+		}
+
+		return 0
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	lastRun := make(map[string]time.Time)
+	for {
+		wait := pollInterval
+		for _, j := range jobs {
+			if last, ok := lastRun[j.name]; !ok || time.Since(last) >= j.interval {
+				lastRun[j.name] = time.Now()
+				runJob(ctx, dEnv, j)
+			}
+
+			if untilDue := j.interval - time.Since(lastRun[j.name]); untilDue < wait {
+				wait = untilDue
+			}
+		}
+
+		select {
+		case <-sigCh:
+			return 0
+		case <-time.After(wait):
+		}
+
+		jobs, verr = loadJobs(ctx, dEnv, apr.Args())
+
+		if verr != nil {
+			cli.PrintErrln(verr.Verbose())
+			jobs = nil
+		}
+	}
+}
+
+// loadJobs reads the jobs with the names given, or all jobs if no names are given, from the current working root of
+// the environment. Jobs which are invalid are reported and left out.
+func loadJobs(ctx context.Context, dEnv *env.DoltEnv, names []string) ([]job, errhand.VerboseError) {
+	if err := refreshEnv(ctx, dEnv); err != nil {
+		return nil, errhand.BuildDError("error: failed to read the repository state").AddCause(err).Build()
+	}
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr != nil {
+		return nil, verr
+	}
+
+	stored, err := root.GetAutomationJobs(ctx)
+
+	if err != nil {
+		return nil, errhand.BuildDError("error: failed to read automation jobs").AddCause(err).Build()
+	}
+
+	if len(names) > 0 {
+		byName := make(map[string]doltdb.AutomationJob, len(stored))
+		for _, sj := range stored {
+			byName[sj.Name] = sj
+		}
+
+		stored = stored[:0]
+		for _, name := range names {
+			sj, ok := byName[name]
+
+			if !ok {
+				return nil, errhand.BuildDError("error: job '%s' not found", name).Build()
+			}
+
+			stored = append(stored, sj)
+		}
+	}
+
+	jobs := make([]job, 0, len(stored))
+	for _, sj := range stored {
+		j, err := parseJob(sj)
+
+		if err != nil {
+			cli.PrintErrln(color.RedString("skipping invalid job '%s': %v", sj.Name, err))
+			continue
+		}
+
+		jobs = append(jobs, j)
+	}
+
+	return jobs, nil
+}
+
+// runJob runs the steps of the job given in order, stopping at the first step that fails, and returns whether every
+// step succeeded.
+func runJob(ctx context.Context, dEnv *env.DoltEnv, j job) bool {
+	cli.Println(color.CyanString("Running job %s", j.name))
+
+	for i, s := range j.steps {
+		cli.Println(color.CyanString("step %d: %s", i+1, s))
+
+		if err := s.run(ctx, dEnv); err != nil {
+			cli.PrintErrln(color.RedString("job %s failed at step %d: %v", j.name, i+1, err))
+			return false
+		}
+	}
+
+	return true
+}
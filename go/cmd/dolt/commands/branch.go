@@ -16,9 +16,12 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 
@@ -43,7 +46,11 @@ With a <b>-m</b>, <oldbranch> will be renamed to <newbranch>. If <newbranch> exi
 
 The <b>-c</b> options have the exact same semantics as <b>-m</b>, except instead of the branch being renamed it will be copied to a new name.
 
-With a <b>-d</b>, <branchname> will be deleted. You may specify more than one branch for deletion.`
+With a <b>-d</b>, <branchname> will be deleted. You may specify more than one branch for deletion.
+
+When listing, <b>--contains</b>, <b>--merged</b> and <b>--no-merged</b> restrict the output to branches whose heads contain the given commit, are reachable from the given commit, or are not reachable from the given commit respectively. This is useful for finding branches which can be safely deleted.
+
+With <b>--ttl</b>, the new branch is ephemeral: it expires once the duration given, such as 12h or 7d, has passed, which suits the snapshots of data that CI jobs create. The time it expires is listed with <b>-v</b>. Ephemeral branches that have expired are deleted with <b>--delete-expired</b>, whether or not they're merged, except for the branch checked out, and pushing to a remote deletes the remote's expired branches (see <b>dolt push</b>). With <b>--promote</b>, an ephemeral branch is made permanent.`
 
 var branchForceFlagDesc = "Reset <branchname> to <startpoint>, even if <branchname> exists already. Without -f, dolt branch " +
 	"refuses to change an existing branch. In combination with -d (or --delete), allow deleting the branch irrespective " +
@@ -51,11 +58,14 @@ var branchForceFlagDesc = "Reset <branchname> to <startpoint>, even if <branchna
 	"already exists, the same applies for -c (or --copy)."
 
 var branchSynopsis = []string{
-	`[--list] [-v] [-a]`,
+	`[--list] [-v] [-a] [--contains <commit>] [--merged <commit>] [--no-merged <commit>]`,
 	`[-f] <branchname> [<start-point>]`,
 	`-m [-f] [<oldbranch>] <newbranch>`,
 	`-c [-f] [<oldbranch>] <newbranch>`,
 	`-d [-f] <branchname>...`,
+	`--ttl <duration> [-f] <branchname> [<start-point>]`,
+	`--promote <branchname>...`,
+	`--delete-expired`,
 }
 
 const (
@@ -67,6 +77,12 @@ const (
 	deleteForceFlag = "D"
 	verboseFlag     = "verbose"
 	allFlag         = "all"
+	containsParam   = "contains"
+	mergedParam     = "merged"
+	noMergedParam   = "no-merged"
+	ttlParam        = "ttl"
+	promoteFlag     = "promote"
+	deleteExpired   = "delete-expired"
 )
 
 func Branch(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
@@ -80,6 +96,12 @@ func Branch(ctx context.Context, commandStr string, args []string, dEnv *env.Dol
 	ap.SupportsFlag(deleteForceFlag, "", "Shortcut for --delete --force.")
 	ap.SupportsFlag(verboseFlag, "v", "When in list mode, show the hash and commit subject line for each head")
 	ap.SupportsFlag(allFlag, "a", "When in list mode, shows remote tracked branches")
+	ap.SupportsString(containsParam, "", "commit", "When in list mode, only list branches which contain the specified commit")
+	ap.SupportsString(mergedParam, "", "commit", "When in list mode, only list branches whose heads are reachable from the specified commit")
+	ap.SupportsString(noMergedParam, "", "commit", "When in list mode, only list branches whose heads are not reachable from the specified commit")
+	ap.SupportsString(ttlParam, "", "duration", "Create an ephemeral branch, which expires after the duration given, such as 12h or 7d")
+	ap.SupportsFlag(promoteFlag, "", "Make ephemeral branches permanent")
+	ap.SupportsFlag(deleteExpired, "", "Delete the ephemeral branches that have expired")
 	help, usage := cli.HelpAndUsagePrinters(commandStr, branchShortDesc, branchLongDesc, branchSynopsis, ap)
 	apr := cli.ParseArgs(ap, args, help)
 
@@ -92,7 +114,11 @@ func Branch(ctx context.Context, commandStr string, args []string, dEnv *env.Dol
 		return deleteBranches(ctx, dEnv, apr, usage)
 	case apr.Contains(deleteForceFlag):
 		return deleteForceBranches(ctx, dEnv, apr, usage)
-	case apr.Contains(listFlag):
+	case apr.Contains(promoteFlag):
+		return promoteBranches(ctx, dEnv, apr, usage)
+	case apr.Contains(deleteExpired):
+		return deleteExpiredBranches(ctx, dEnv, apr, usage)
+	case apr.Contains(listFlag), apr.Contains(containsParam), apr.Contains(mergedParam), apr.Contains(noMergedParam):
 		return printBranches(ctx, dEnv, apr, usage)
 	case apr.NArg() > 0:
 		return createBranch(ctx, dEnv, apr, usage)
@@ -118,6 +144,26 @@ func printBranches(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPar
 		return branches[i].String() < branches[j].String()
 	})
 
+	filters, verr := getBranchFilters(ctx, dEnv, apr)
+
+	if verr != nil {
+		return HandleVErrAndExitCode(verr, nil)
+	}
+
+	var expiries map[string]time.Time
+	if verbose {
+		ebs, err := dEnv.DoltDB.GetEphemeralBranches(ctx)
+
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: failed to read the expiries of branches").AddCause(err).Build(), nil)
+		}
+
+		expiries = make(map[string]time.Time)
+		for _, eb := range ebs {
+			expiries[eb.Branch.GetPath()] = eb.Expires
+		}
+	}
+
 	for _, branch := range branches {
 		if branchSet.Size() > 0 && !branchSet.Contains(branch.GetPath()) {
 			continue
@@ -125,10 +171,28 @@ func printBranches(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPar
 
 		cs, _ := doltdb.NewCommitSpec("HEAD", branch.String())
 
-		if branch.GetType() != ref.BranchRefType && !printAll {
+		if branch.GetType() == ref.InternalRefType || (branch.GetType() != ref.BranchRefType && !printAll) {
 			continue
 		}
 
+		if len(filters) > 0 {
+			cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+
+			if err != nil {
+				return HandleVErrAndExitCode(errhand.BuildDError("error: failed to resolve branch '%s'", branch.GetPath()).AddCause(err).Build(), nil)
+			}
+
+			matches, err := matchesBranchFilters(ctx, cm, filters)
+
+			if err != nil {
+				return HandleVErrAndExitCode(errhand.BuildDError("error: failed to check ancestry of branch '%s'", branch.GetPath()).AddCause(err).Build(), nil)
+			}
+
+			if !matches {
+				continue
+			}
+		}
+
 		commitStr := ""
 		branchName := "  " + branch.GetPath()
 		branchLen := len(branchName)
@@ -152,6 +216,10 @@ func printBranches(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPar
 
 				commitStr = h.String()
 			}
+
+			if expires, ok := expiries[branch.GetPath()]; ok && branch.GetType() == ref.BranchRefType {
+				commitStr += "\texpires " + expires.Local().Format(time.RFC3339)
+			}
 		}
 
 		fmtStr := fmt.Sprintf("%%s%%%ds\t%%s", 48-branchLen)
@@ -163,6 +231,67 @@ func printBranches(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPar
 	return 0
 }
 
+// branchFilter restricts the branches listed to those with a particular ancestry relationship to a commit.
+type branchFilter struct {
+	param  string
+	commit *doltdb.Commit
+}
+
+// getBranchFilters resolves the commits given to the --contains, --merged and --no-merged params.
+func getBranchFilters(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults) ([]branchFilter, errhand.VerboseError) {
+	var filters []branchFilter
+	for _, param := range []string{containsParam, mergedParam, noMergedParam} {
+		cSpecStr, ok := apr.GetValue(param)
+
+		if !ok {
+			continue
+		}
+
+		cs, err := doltdb.NewCommitSpec(cSpecStr, dEnv.RepoState.Head.Ref.String())
+
+		if err != nil {
+			return nil, errhand.BuildDError("fatal: '%s' is not a valid commit", cSpecStr).Build()
+		}
+
+		cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+
+		if err != nil {
+			return nil, errhand.BuildDError("fatal: unable to resolve commit '%s'", cSpecStr).AddCause(err).Build()
+		}
+
+		filters = append(filters, branchFilter{param, cm})
+	}
+
+	return filters, nil
+}
+
+// matchesBranchFilters returns whether the branch head given satisfies all of the filters given.
+func matchesBranchFilters(ctx context.Context, head *doltdb.Commit, filters []branchFilter) (bool, error) {
+	for _, filter := range filters {
+		var matches bool
+		var err error
+		switch filter.param {
+		case containsParam:
+			matches, err = filter.commit.IsAncestorOf(ctx, head)
+		case mergedParam:
+			matches, err = head.IsAncestorOf(ctx, filter.commit)
+		case noMergedParam:
+			matches, err = head.IsAncestorOf(ctx, filter.commit)
+			matches = !matches
+		}
+
+		if err != nil {
+			return false, err
+		}
+
+		if !matches {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func moveBranch(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults, usage cli.UsagePrinter) int {
 	if apr.NArg() != 2 {
 		usage()
@@ -267,12 +396,105 @@ func createBranch(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 		startPt = apr.Arg(1)
 	}
 
-	verr := createBranchWithStartPt(ctx, dEnv, newBranch, startPt, apr.Contains(forceFlag))
+	var ttl time.Duration
+	if ttlStr, ok := apr.GetValue(ttlParam); ok {
+		var err error
+		ttl, err = parseTTL(ttlStr)
+
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("fatal: invalid --ttl '%s'", ttlStr).AddCause(err).Build(), usage)
+		}
+	}
+
+	verr := createBranchWithStartPt(ctx, dEnv, newBranch, startPt, apr.Contains(forceFlag), ttl)
 	return HandleVErrAndExitCode(verr, usage)
 }
 
-func createBranchWithStartPt(ctx context.Context, dEnv *env.DoltEnv, newBranch, startPt string, force bool) errhand.VerboseError {
-	err := actions.CreateBranch(ctx, dEnv, newBranch, startPt, force)
+// parseTTL parses the duration of an ephemeral branch, which is a Go duration such as 90m or 12h, or a number of days
+// such as 7d.
+func parseTTL(ttlStr string) (time.Duration, error) {
+	var ttl time.Duration
+	if strings.HasSuffix(ttlStr, "d") {
+		days, err := strconv.ParseUint(strings.TrimSuffix(ttlStr, "d"), 10, 32)
+
+		if err != nil {
+			return 0, fmt.Errorf("'%s' is not a number of days", ttlStr)
+		}
+
+		ttl = time.Duration(days) * 24 * time.Hour
+	} else {
+		var err error
+		ttl, err = time.ParseDuration(ttlStr)
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if ttl <= 0 {
+		return 0, errors.New("the duration must be greater than 0")
+	}
+
+	return ttl, nil
+}
+
+func promoteBranches(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults, usage cli.UsagePrinter) int {
+	if apr.NArg() == 0 {
+		usage()
+		return 1
+	}
+
+	for _, brName := range apr.Args() {
+		dref := ref.NewBranchRef(brName)
+
+		if hasRef, err := dEnv.DoltDB.HasRef(ctx, dref); err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: failed to read from db").AddCause(err).Build(), usage)
+		} else if !hasRef {
+			return HandleVErrAndExitCode(errhand.BuildDError("fatal: branch '%s' not found", brName).Build(), usage)
+		}
+
+		promoted, err := dEnv.DoltDB.ClearBranchExpiry(ctx, dref)
+
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("fatal: Unexpected error promoting '%s'", brName).AddCause(err).Build(), usage)
+		} else if promoted {
+			cli.Printf("Branch '%s' is now permanent.\n", brName)
+		} else {
+			cli.Printf("Branch '%s' is already permanent.\n", brName)
+		}
+	}
+
+	return 0
+}
+
+func deleteExpiredBranches(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults, usage cli.UsagePrinter) int {
+	if apr.NArg() != 0 {
+		usage()
+		return 1
+	}
+
+	deleted, err := actions.DeleteExpiredBranches(ctx, dEnv.DoltDB, time.Now(), dEnv.RepoState.Head.Ref)
+
+	for _, eb := range deleted {
+		cli.Printf("Deleted branch %s (expired %s).\n", eb.Branch.GetPath(), eb.Expires.Local().Format(time.RFC3339))
+	}
+
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("fatal: Unexpected error deleting expired branches").AddCause(err).Build(), usage)
+	}
+
+	return 0
+}
+
+// createBranchWithStartPt creates the branch given at the start point given, which is ephemeral and expires after the
+// ttl given if it's greater than 0.
+func createBranchWithStartPt(ctx context.Context, dEnv *env.DoltEnv, newBranch, startPt string, force bool, ttl time.Duration) errhand.VerboseError {
+	var err error
+	if ttl > 0 {
+		err = actions.CreateEphemeralBranch(ctx, dEnv, newBranch, startPt, force, ttl)
+	} else {
+		err = actions.CreateBranch(ctx, dEnv, newBranch, startPt, force)
+	}
 
 	if err != nil {
 		if err == actions.ErrAlreadyExists {
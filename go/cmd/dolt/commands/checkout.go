@@ -109,7 +109,7 @@ func Checkout(ctx context.Context, commandStr string, args []string, dEnv *env.D
 }
 
 func checkoutNewBranch(ctx context.Context, dEnv *env.DoltEnv, newBranch, startPt string) errhand.VerboseError {
-	verr := createBranchWithStartPt(ctx, dEnv, newBranch, startPt, false)
+	verr := createBranchWithStartPt(ctx, dEnv, newBranch, startPt, false, 0)
 
 	if verr != nil {
 		return verr
@@ -54,7 +54,11 @@ var cloneLongDesc = "Clones a repository into a newly created directory, creates
 	"pull</b> without arguments will in addition merge the remote branch into the current branch\n" +
 	"\n" +
 	"This default configuration is achieved by creating references to the remote branch heads under refs/remotes/origin " +
-	"and by creating a remote named 'origin'."
+	"and by creating a remote named 'origin'.\n" +
+	"\n" +
+	"If a clone fails, the data downloaded before it failed is kept in the new directory. Running the same clone " +
+	"command again resumes the clone, downloading only the data that is missing. A clone only completes once every " +
+	"chunk reachable from the cloned branches has been checked to be present."
 var cloneSynopsis = []string{
 	"[-remote <remote>] [-branch <branch>]  [--aws-region <region>] [--aws-creds-type <creds-type>] [--aws-creds-file <file>] [--aws-creds-profile <profile>] <remote-url> <new-dir>",
 }
@@ -93,6 +97,7 @@ func Clone(ctx context.Context, commandStr string, args []string, dEnv *env.Dolt
 				dEnv, verr = envForClone(ctx, srcDB.ValueReadWriter().Format(), r, dir, dEnv.FS)
 
 				if verr == nil {
+					// The directory is kept if the clone fails, so that running the clone again resumes it.
 					verr = cloneRemote(ctx, srcDB, remoteName, branch, dEnv)
 
 					if verr == nil {
@@ -105,12 +110,6 @@ func Clone(ctx context.Context, commandStr string, args []string, dEnv *env.Dolt
 							}
 						}
 					}
-
-					// Make best effort to delete the directory we created.
-					if verr != nil {
-						_ = os.Chdir("../")
-						_ = dEnv.FS.Delete(dir, true)
-					}
 				}
 			}
 		}
@@ -150,7 +149,7 @@ func envForClone(ctx context.Context, nbf *types.NomsBinFormat, r env.Remote, di
 	exists, _ := fs.Exists(filepath.Join(dir, dbfactory.DoltDir))
 
 	if exists {
-		return nil, errhand.BuildDError("error: data repository already exists at " + dir).Build()
+		return envForResumedClone(ctx, r, dir, fs)
 	}
 
 	err := fs.MkDirs(dir)
@@ -179,6 +178,45 @@ func envForClone(ctx context.Context, nbf *types.NomsBinFormat, r env.Remote, di
 		return nil, errhand.BuildDError("error: unable to create repo state with remote " + r.Name).AddCause(err).Build()
 	}
 
+	cloneState := &env.CloneState{Remote: r}
+	err = cloneState.Save(dEnv.FS)
+
+	if err != nil {
+		return nil, errhand.BuildDError("error: unable to write clone state").AddCause(err).Build()
+	}
+
+	return dEnv, nil
+}
+
+// envForResumedClone returns the environment of the repository at dir, which must hold a clone of the remote given
+// that didn't complete.
+func envForResumedClone(ctx context.Context, r env.Remote, dir string, fs filesys.Filesys) (*env.DoltEnv, errhand.VerboseError) {
+	err := os.Chdir(dir)
+
+	if err != nil {
+		return nil, errhand.BuildDError("error: unable to access directory %s", dir).Build()
+	}
+
+	dEnv := env.Load(ctx, env.GetCurrentUserHomeDir, fs, doltdb.LocalDirDoltDB)
+	cloneState, ok, err := env.LoadCloneState(dEnv.FS)
+
+	if err != nil {
+		return nil, errhand.BuildDError("error: unable to read clone state").AddCause(err).Build()
+	} else if !ok {
+		return nil, errhand.BuildDError("error: data repository already exists at %s", dir).Build()
+	} else if cloneState.Remote.Url != r.Url || cloneState.Remote.Name != r.Name {
+		return nil, errhand.BuildDError("error: %s holds a clone of %s as remote '%s' that didn't complete", dir, cloneState.Remote.Url, cloneState.Remote.Name).
+			AddDetails("run that clone again to resume it, or delete %s", dir).Build()
+	}
+
+	if dEnv.DBLoadError != nil {
+		return nil, errhand.BuildDError("error: unable to load the database at %s", dir).AddCause(dEnv.DBLoadError).Build()
+	} else if dEnv.RSLoadErr != nil {
+		return nil, errhand.BuildDError("error: unable to load the repo state at %s", dir).AddCause(dEnv.RSLoadErr).Build()
+	}
+
+	cli.Printf("resuming the clone into %s\n", dir)
+
 	return dEnv, nil
 }
 
@@ -253,7 +291,15 @@ func cloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 	wg.Wait()
 
 	if err != nil {
-		return errhand.BuildDError("error: clone failed").AddCause(err).Build()
+		return errhand.BuildDError("error: clone failed").AddCause(err).
+			AddDetails("run the same clone command again to resume it").Build()
+	}
+
+	err = dEnv.DoltDB.CheckComplete(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("error: clone is incomplete").AddCause(err).
+			AddDetails("run the same clone command again to resume it").Build()
 	}
 
 	if branch == "" {
@@ -313,5 +359,11 @@ func cloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 		return errhand.BuildDError("error: failed to write repo state").AddCause(err).Build()
 	}
 
+	err = env.RemoveCloneState(dEnv.FS)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to remove clone state").AddCause(err).Build()
+	}
+
 	return nil
 }
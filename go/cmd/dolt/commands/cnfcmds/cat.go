@@ -16,6 +16,9 @@ package cnfcmds
 
 import (
 	"context"
+	"io"
+
+	"github.com/fatih/color"
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
@@ -35,18 +38,36 @@ import (
 )
 
 var catShortDesc = "print conflicts"
-var catLongDesc = `The dolt conflicts cat command reads table conflicts and writes them to the standard output.`
+var catLongDesc = "The dolt conflicts cat command reads table conflicts and writes them to the standard output.\n" +
+	"\n" +
+	"With <b>--format csv</b> the conflicts of a single table are written as csv, with the version of each row in the " +
+	"<b>source</b> column and the change that version made in the <b>op</b> column. The file can be edited to hold " +
+	"one row per conflict and then given to <b>dolt conflicts resolve --file</b>."
 var catSynopsis = []string{
 	"[<commit>] <table>...",
+	"--format csv [<commit>] <table>",
 }
 
+const (
+	formatParam   = "format"
+	tabularFormat = "tabular"
+	csvFormat     = "csv"
+)
+
 func Cat(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
 	ap := argparser.NewArgParser()
 	ap.ArgListHelp["table"] = "List of tables to be printed. '.' can be used to print conflicts for all tables."
+	ap.SupportsString(formatParam, "", "format", "How the conflicts are written, either 'tabular' or 'csv'. Defaults to 'tabular'.")
 	help, usage := cli.HelpAndUsagePrinters(commandStr, catShortDesc, catLongDesc, catSynopsis, ap)
 	apr := cli.ParseArgs(ap, args, help)
 	args = apr.Args()
 
+	format := apr.GetValueOrDefault(formatParam, tabularFormat)
+	if format != tabularFormat && format != csvFormat {
+		cli.PrintErrln(color.RedString("Invalid format '%s', valid formats are '%s' and '%s'", format, tabularFormat, csvFormat))
+		return 1
+	}
+
 	if len(args) == 0 {
 		cli.Println("No tables specified")
 		cli.Println(" Maybe you wanted to say 'dolt conflicts cat .'?")
@@ -77,7 +98,16 @@ func Cat(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEn
 				return 1
 			}
 
-			verr = printConflicts(ctx, root, args)
+			if format == csvFormat {
+				if len(args) != 1 || args[0] == "." {
+					cli.PrintErrln(color.RedString("The csv format writes the conflicts of a single table"))
+					return 1
+				}
+
+				verr = printConflictsCSV(ctx, root, args[0])
+			} else {
+				verr = printConflicts(ctx, root, args)
+			}
 		}
 	}
 
@@ -166,3 +196,49 @@ func printConflicts(ctx context.Context, root *doltdb.RootValue, tblNames []stri
 
 	return nil
 }
+
+func printConflictsCSV(ctx context.Context, root *doltdb.RootValue, tblName string) errhand.VerboseError {
+	tbl, ok, err := root.GetTable(ctx, tblName)
+
+	if err != nil {
+		return errhand.BuildDError("error: unable to read database").AddCause(err).Build()
+	} else if !ok {
+		return errhand.BuildDError("error: unknown table '%s'", tblName).Build()
+	}
+
+	cnfRd, err := merge.NewConflictReader(ctx, tbl)
+
+	if err == doltdb.ErrNoConflicts {
+		return nil
+	} else if err != nil {
+		return errhand.BuildDError("failed to read conflicts").AddCause(err).Build()
+	}
+
+	defer cnfRd.Close()
+
+	cnfWr, err := merge.NewConflictCSVSink(iohelp.NopWrCloser(cli.CliOut), cnfRd.GetSchema())
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to write conflicts").AddCause(err).Build()
+	}
+
+	for {
+		r, props, err := cnfRd.NextConflict(ctx)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errhand.BuildDError("failed to read conflicts").AddCause(err).Build()
+		}
+
+		if err = cnfWr.ProcRowWithProps(r, props); err != nil {
+			return errhand.BuildDError("error: failed to write conflicts").AddCause(err).Build()
+		}
+	}
+
+	if err = cnfWr.Close(ctx); err != nil {
+		return errhand.BuildDError("error: failed to write conflicts").AddCause(err).Build()
+	}
+
+	return nil
+}
@@ -16,6 +16,7 @@ package cnfcmds
 
 import (
 	"context"
+	"io"
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
@@ -24,7 +25,13 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/merge"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/rowconv"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/csv"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
 var resShortDesc = "Removes rows from list of conflicts"
@@ -36,15 +43,23 @@ var resLongDesc = "When a merge operation finds conflicting changes, the rows wi
 	"the conflicts whose keys are provided.\n" +
 	"\n" +
 	"In it's second form <b>dolt conflicts resolve --ours|--theirs <table>...</b>, resolve runs in auto resolve mode. " +
-	"where conflicts are resolved using a rule to determine which version of a row should be used."
+	"where conflicts are resolved using a rule to determine which version of a row should be used.\n" +
+	"\n" +
+	"In it's third form <b>dolt conflicts resolve --file <file> <table></b>, every conflict in the table is resolved " +
+	"using the rows of a csv file, such as one written by <b>dolt conflicts cat --format csv</b> and edited to hold " +
+	"the resolved version of each row. The file must have exactly one row for each conflict, and no rows that aren't " +
+	"in conflict. A row whose <b>op</b> column is 'removed' resolves its conflict by deleting the row. If the file " +
+	"doesn't resolve every conflict nothing is changed."
 var resSynopsis = []string{
 	"<table> [<key_definition>] <key>...",
 	"--ours|--theirs <table>...",
+	"--file <file> <table>",
 }
 
 const (
 	oursFlag   = "ours"
 	theirsFlag = "theirs"
+	fileParam  = "file"
 )
 
 var autoResolvers = map[string]merge.AutoResolver{
@@ -67,11 +82,14 @@ func Resolve(ctx context.Context, commandStr string, args []string, dEnv *env.Do
 	ap.ArgListHelp["key"] = "key(s) of rows within a table whose conflicts have been resolved"
 	ap.SupportsFlag("ours", "", "For all conflicts, take the version from our branch and resolve the conflict")
 	ap.SupportsFlag("theirs", "", "Fol all conflicts, take the version from our branch and resolve the conflict")
+	ap.SupportsString(fileParam, "", "file", "A csv file holding the resolved version of every conflicting row")
 	help, usage := cli.HelpAndUsagePrinters(commandStr, resShortDesc, resLongDesc, resSynopsis, ap)
 	apr := cli.ParseArgs(ap, args, help)
 
 	var verr errhand.VerboseError
-	if apr.ContainsAny(autoResolverParams...) {
+	if apr.Contains(fileParam) {
+		verr = fileResolve(ctx, apr, dEnv)
+	} else if apr.ContainsAny(autoResolverParams...) {
 		verr = autoResolve(ctx, apr, dEnv)
 	} else {
 		verr = manualResolve(ctx, apr, dEnv)
@@ -112,6 +130,148 @@ func autoResolve(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.
 	return nil
 }
 
+func fileResolve(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEnv) errhand.VerboseError {
+	if apr.NArg() != 1 || apr.ContainsAny(autoResolverParams...) {
+		return errhand.BuildDError("").SetPrintUsage().Build()
+	}
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr != nil {
+		return verr
+	}
+
+	tblName := apr.Arg(0)
+	tbl, ok, err := root.GetTable(ctx, tblName)
+
+	if err != nil {
+		return errhand.BuildDError("error: could not read tables").AddCause(err).Build()
+	} else if !ok {
+		return errhand.BuildDError("error: table '%s' not found", tblName).Build()
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to get schema").AddCause(err).Build()
+	}
+
+	path := apr.MustGetValue(fileParam)
+	rows, removed, verr := readResolvedRows(ctx, dEnv, path, sch)
+
+	if verr != nil {
+		return verr
+	}
+
+	updatedTbl, err := merge.ResolveTableWithRows(ctx, root.VRW(), tbl, rows, removed)
+
+	if err != nil {
+		if err == doltdb.ErrNoConflicts {
+			cli.Println("no conflicts to resolve.")
+			return nil
+		} else if resErr, ok := err.(*merge.ResolutionError); ok {
+			bdr := errhand.BuildDError("error: '%s' does not resolve the conflicts in table '%s'", path, tblName)
+			for _, key := range resErr.Unresolved {
+				bdr.AddDetails("%s is not resolved", key)
+			}
+			for _, key := range resErr.Duplicated {
+				bdr.AddDetails("%s is resolved more than once", key)
+			}
+			for _, key := range resErr.NotConflicts {
+				bdr.AddDetails("%s is not the primary key of a conflicting row", key)
+			}
+
+			return bdr.Build()
+		} else if table.IsBadRow(err) {
+			return errhand.BuildDError("error: '%s' contains a row that is not valid for table '%s'", path, tblName).AddCause(err).Build()
+		}
+
+		return errhand.BuildDError("fatal: Failed to resolve conflicts").AddCause(err).Build()
+	}
+
+	root, err = root.PutTable(ctx, tblName, updatedTbl)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to write table '%s'", tblName).AddCause(err).Build()
+	}
+
+	verr = commands.UpdateWorkingWithVErr(dEnv, root)
+
+	if verr != nil {
+		return verr
+	}
+
+	cli.Println(len(rows)+len(removed), "rows resolved successfully")
+
+	return nil
+}
+
+// readResolvedRows reads the rows of a csv file holding resolved conflicts, converting them to the schema given. Rows
+// whose op column is 'removed' are returned separately.
+func readResolvedRows(ctx context.Context, dEnv *env.DoltEnv, path string, sch schema.Schema) (rows, removed []row.Row, verr errhand.VerboseError) {
+	rd, err := csv.OpenCSVReader(dEnv.DoltDB.Format(), path, dEnv.FS, csv.NewCSVInfo())
+
+	if err != nil {
+		return nil, nil, errhand.BuildDError("error: failed to open '%s'", path).AddCause(err).Build()
+	}
+
+	defer rd.Close(ctx)
+
+	fileSch := rd.GetSchema()
+	err = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if _, ok := fileSch.GetAllCols().GetByName(col.Name); !ok {
+			verr = errhand.BuildDError("error: '%s' is missing primary key column '%s'", path, col.Name).Build()
+			return true, nil
+		}
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, nil, errhand.BuildDError("error: failed to read schema").AddCause(err).Build()
+	} else if verr != nil {
+		return nil, nil, verr
+	}
+
+	mapping, err := rowconv.NameMapping(fileSch, sch)
+
+	if err != nil {
+		return nil, nil, errhand.BuildDError("error: failed to map the columns of '%s'", path).AddCause(err).Build()
+	}
+
+	conv, err := rowconv.NewRowConverter(mapping)
+
+	if err != nil {
+		return nil, nil, errhand.BuildDError("error: failed to map the columns of '%s'", path).AddCause(err).Build()
+	}
+
+	opCol, hasOp := fileSch.GetAllCols().GetByName(merge.ConflictOpCol)
+
+	for {
+		r, err := rd.ReadRow(ctx)
+
+		if err == io.EOF {
+			return rows, removed, nil
+		} else if err != nil {
+			return nil, nil, errhand.BuildDError("error: failed to read '%s'", path).AddCause(err).Build()
+		}
+
+		converted, err := conv.Convert(r)
+
+		if err != nil {
+			return nil, nil, errhand.BuildDError("error: failed to convert a row of '%s'", path).AddCause(err).Build()
+		}
+
+		if hasOp {
+			if op, ok := r.GetColVal(opCol.Tag); ok && !types.IsNull(op) && string(op.(types.String)) == merge.ConflictOpRemoved {
+				removed = append(removed, converted)
+				continue
+			}
+		}
+
+		rows = append(rows, converted)
+	}
+}
+
 func manualResolve(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env.DoltEnv) errhand.VerboseError {
 	args := apr.Args()
 
@@ -26,8 +26,12 @@ import (
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/creds"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/integrity"
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/editor"
 )
@@ -36,6 +40,7 @@ const (
 	allowEmptyFlag   = "allow-empty"
 	dateParam        = "date"
 	commitMessageArg = "message"
+	manifestParam    = "write-integrity-manifest"
 )
 
 var commitShortDesc = `Record changes to the repository`
@@ -49,7 +54,15 @@ var commitLongDesc = "Stores the current contents of the staged tables in a new
 	"opened where you can review the commit and provide a log message.\n" +
 	"\n" +
 	"The commit timestamp can be modified using the --date parameter.  Dates can be specified in the formats YYYY-MM-DD " +
-	"YYYY-MM-DDTHH:MM:SS, or YYYY-MM-DDTHH:MM:SSZ07:00 (where 07:00 is the time zone offset)."
+	"YYYY-MM-DDTHH:MM:SS, or YYYY-MM-DDTHH:MM:SSZ07:00 (where 07:00 is the time zone offset).\n" +
+	"\n" +
+	"The tables of materialized views, defined with dolt schema materialize, are recomputed from the staged tables " +
+	"before they're committed.\n" +
+	"\n" +
+	"With --write-integrity-manifest, a manifest of the new commit is written to <file>. It lists a SHA-256 hash of " +
+	"the rows of each table, as dolt table export writes them, and is signed with the credentials set by user.creds, " +
+	"so that whoever receives an export of the tables can check with dolt verify-manifest that it has the data of " +
+	"the commit."
 var commitSynopsis = []string{
 	"[options]",
 }
@@ -60,6 +73,7 @@ func Commit(ctx context.Context, commandStr string, args []string, dEnv *env.Dol
 	ap.SupportsString(commitMessageArg, "m", "msg", "Use the given <msg> as the commit message.")
 	ap.SupportsFlag(allowEmptyFlag, "", "Allow recording a commit that has the exact same data as its sole parent. This is usually a mistake, so it is disabled by default. This option bypasses that safety.")
 	ap.SupportsString(dateParam, "", "date", "Specify the date used in the commit. If not specified the current system time is used.")
+	ap.SupportsString(manifestParam, "", "file", "Write a signed manifest of the hashes of the tables of the commit to <file>.")
 	help, usage := cli.HelpAndUsagePrinters(commandStr, commitShortDesc, commitLongDesc, commitSynopsis, ap)
 	apr := cli.ParseArgs(ap, args, help)
 
@@ -78,8 +92,35 @@ func Commit(ctx context.Context, commandStr string, args []string, dEnv *env.Dol
 		}
 	}
 
+	manifestPath, writeManifest := apr.GetValue(manifestParam)
+	var signingCreds creds.DoltCreds
+	if writeManifest {
+		var ok bool
+		var err error
+		signingCreds, ok, err = dEnv.UserCreds()
+
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: failed to read the credentials to sign the manifest with").AddCause(err).Build(), usage)
+		} else if !ok {
+			bdr := errhand.BuildDError("error: there are no credentials to sign the manifest with.")
+			bdr.AddDetails("create them with dolt creds new, and set %s to their public key with dolt config --global --add %[1]s <public key>", env.UserCreds)
+			return HandleVErrAndExitCode(bdr.Build(), usage)
+		}
+	}
+
+	if err := dsqle.RefreshStagedMaterializedViews(ctx, dEnv); err != nil {
+		verr := errhand.BuildDError("error: failed to refresh materialized views").AddCause(err).Build()
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
 	err := actions.CommitStaged(ctx, dEnv, msg, t, apr.Contains(allowEmptyFlag))
 	if err == nil {
+		if writeManifest {
+			if verr := writeIntegrityManifest(ctx, dEnv, manifestPath, signingCreds); verr != nil {
+				return HandleVErrAndExitCode(verr, usage)
+			}
+		}
+
 		// if the commit was successful, print it out using the log command
 		return Log(ctx, "log", []string{"-n=1"}, dEnv)
 	}
@@ -87,6 +128,32 @@ func Commit(ctx context.Context, commandStr string, args []string, dEnv *env.Dol
 	return handleCommitErr(err, usage)
 }
 
+// writeIntegrityManifest writes the manifest of the head commit, signed with the credentials given, to the path given.
+func writeIntegrityManifest(ctx context.Context, dEnv *env.DoltEnv, path string, signingCreds creds.DoltCreds) errhand.VerboseError {
+	cs, _ := doltdb.NewCommitSpec("HEAD", dEnv.RepoState.Head.Ref.String())
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to resolve the new commit").AddCause(err).Build()
+	}
+
+	m, err := integrity.NewManifest(ctx, cm)
+
+	if err == nil {
+		err = m.Sign(signingCreds)
+	}
+
+	if err == nil {
+		err = m.Write(dEnv.FS, path)
+	}
+
+	if err != nil {
+		return errhand.BuildDError("error: the commit was made, but its manifest couldn't be written to '%s'", path).AddCause(err).Build()
+	}
+
+	return nil
+}
+
 // we are more permissive than what is documented.
 var supportedLayouts = []string{
 	"2006/01/02",
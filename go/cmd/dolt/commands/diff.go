@@ -169,7 +169,9 @@ func getRoots(ctx context.Context, args []string, dEnv *env.DoltEnv) (r1, r2 *do
 		}
 
 		cm, err := dEnv.DoltDB.Resolve(ctx, cs)
-		if err != nil {
+		if err == doltdb.ErrAmbiguousHashPrefix {
+			return nil, nil, nil, errhand.BuildDError("error: '%s' is the start of more than one commit hash", arg).Build()
+		} else if err != nil {
 			break
 		}
 
@@ -267,7 +269,28 @@ func diffRoots(ctx context.Context, r1, r2 *doltdb.RootValue, tblNames []string,
 		}
 	}
 
+	renamedTo, renamedFrom, verr := tableRenames(ctx, r1, r2)
+
+	if verr != nil {
+		return verr
+	}
+
+	printedRenames := make(map[string]bool)
 	for _, tblName := range tblNames {
+		if newName, ok := renamedTo[tblName]; ok {
+			tblName = newName
+		}
+
+		if oldName, ok := renamedFrom[tblName]; ok {
+			// a renamed table's contents are unchanged, so it's printed once, under both of its names
+			if dArgs.diffOutput == TabularDiffOutput && !printedRenames[tblName] {
+				printTableRenameSummary(oldName, tblName)
+			}
+
+			printedRenames[tblName] = true
+			continue
+		}
+
 		tbl1, ok1, err := r1.GetTable(ctx, tblName)
 
 		if err != nil {
@@ -513,7 +536,12 @@ func sqlSchemaDiff(tableName string, tags []uint64, diffs map[uint64]diff.Schema
 		case diff.SchDiffColRemoved:
 			cli.Print(sql.AlterTableDropColStmt(tableName, dff.Old.Name))
 		case diff.SchDiffColModified:
-			cli.Print(sql.AlterTableRenameColStmt(tableName, dff.Old.Name, dff.New.Name))
+			if dff.Old.Name != dff.New.Name {
+				cli.Print(sql.AlterTableRenameColStmt(tableName, dff.Old.Name, dff.New.Name))
+			}
+			if dff.Old.Comment != dff.New.Comment {
+				cli.Println(sql.AlterTableModifyColStmt(tableName, sql.FmtCol(0, 0, 0, *dff.New)))
+			}
 		}
 	}
 }
@@ -768,6 +796,35 @@ func createSplitter(newSch schema.Schema, oldSch schema.Schema, joiner *rowconv.
 
 var emptyHash = hash.Hash{}
 
+// tableRenames returns the tables renamed in r1 since r2, as maps from their names in r2 to their names in r1 and back.
+func tableRenames(ctx context.Context, r1, r2 *doltdb.RootValue) (map[string]string, map[string]string, errhand.VerboseError) {
+	added, _, removed, err := r1.TableDiff(ctx, r2)
+
+	if err != nil {
+		return nil, nil, errhand.BuildDError("error: unable to diff tables").AddCause(err).Build()
+	}
+
+	renamedTo, _, _, err := r1.TableRenames(ctx, r2, added, removed)
+
+	if err != nil {
+		return nil, nil, errhand.BuildDError("error: unable to diff tables").AddCause(err).Build()
+	}
+
+	renamedFrom := make(map[string]string, len(renamedTo))
+	for oldName, newName := range renamedTo {
+		renamedFrom[newName] = oldName
+	}
+
+	return renamedTo, renamedFrom, nil
+}
+
+func printTableRenameSummary(oldName, newName string) {
+	bold := color.New(color.Bold)
+
+	_, _ = bold.Printf("diff --dolt a/%s b/%s\n", oldName, newName)
+	_, _ = bold.Println("renamed table")
+}
+
 func printTableDiffSummary(tblName string, tbl1, tbl2 *doltdb.Table) {
 	bold := color.New(color.Bold)
 
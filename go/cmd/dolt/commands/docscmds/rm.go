@@ -0,0 +1,58 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docscmds
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+var rmShortDesc = "Removes a document"
+var rmLongDesc = "Removes the document with the given name from the working set."
+var rmSynopsis = []string{"<doc>"}
+
+func Rm(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["doc"] = "name of the document to remove."
+	help, usage := cli.HelpAndUsagePrinters(commandStr, rmShortDesc, rmLongDesc, rmSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 1 {
+		usage()
+		return 1
+	}
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr == nil {
+		docName := apr.Arg(0)
+		newRoot, ok, err := root.RemoveDoc(ctx, docName)
+
+		if err != nil {
+			verr = errhand.BuildDError("error: failed to remove document '%s'", docName).AddCause(err).Build()
+		} else if !ok {
+			verr = errhand.BuildDError("error: document '%s' not found", docName).Build()
+		} else {
+			verr = commands.UpdateWorkingWithVErr(dEnv, newRoot)
+		}
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docscmds
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+var uploadShortDesc = "Sets a document from the contents of a file"
+var uploadLongDesc = "Sets the document with the given name in the working set to the contents of the file given, creating it " +
+	"if it does not exist. Documents are stored in the " + doltdb.DocTableName + " table, and are staged and committed like any other table."
+var uploadSynopsis = []string{"<doc> <file>"}
+
+func Upload(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["doc"] = "name of the document to set, for example README.md."
+	ap.ArgListHelp["file"] = "file holding the contents of the document."
+	help, usage := cli.HelpAndUsagePrinters(commandStr, uploadShortDesc, uploadLongDesc, uploadSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 2 {
+		usage()
+		return 1
+	}
+
+	docName, path := apr.Arg(0), apr.Arg(1)
+	text, err := dEnv.FS.ReadFile(path)
+
+	if err != nil {
+		verr := errhand.BuildDError("error: failed to read '%s'", path).AddCause(err).Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr == nil {
+		root, err = root.PutDoc(ctx, docName, string(text))
+
+		if err != nil {
+			verr = errhand.BuildDError("error: failed to write document '%s'", docName).AddCause(err).Build()
+		} else {
+			verr = commands.UpdateWorkingWithVErr(dEnv, root)
+		}
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
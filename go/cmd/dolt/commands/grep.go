@@ -0,0 +1,184 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/grep"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+const (
+	allHistoryParam = "all-history"
+	ignoreCaseParam = "ignore-case"
+)
+
+var grepShortDesc = `Search table cell values for a pattern`
+var grepLongDesc = "Searches the cell values of the tables in the working set for matches of the regular expression " +
+	"<b>pattern</b>, and prints the table, primary key, column and value of each match. If tables are given, only " +
+	"those tables are searched.\n" +
+	"\n" +
+	"Values are matched as they're displayed by <b>dolt table select</b>, so numbers and other non-string values can be " +
+	"searched for too.\n" +
+	"\n" +
+	"With <b>--all-history</b>, every commit in the history of the current branch is searched as well, and each match " +
+	"is prefixed with the commit it was found in, or WORKING if it's in the working set. Each distinct value of a cell " +
+	"is only printed once, for the most recent commit it's found in.\n" +
+	"\n" +
+	"Exits with 1 if nothing matched."
+
+var grepSynopsis = []string{
+	"[-i] [--all-history] <pattern> [<table>...]",
+}
+
+var errNoGrepMatches = errors.New("no matches")
+
+func Grep(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["pattern"] = "The regular expression to search cell values for."
+	ap.ArgListHelp["table"] = "A table to search. Defaults to all tables."
+	ap.SupportsFlag(allHistoryParam, "", "Search every commit in the history of the current branch as well as the working set.")
+	ap.SupportsFlag(ignoreCaseParam, "i", "Ignore case when matching the pattern.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, grepShortDesc, grepLongDesc, grepSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() == 0 {
+		usage()
+		return 1
+	}
+
+	pattern := apr.Arg(0)
+	if apr.Contains(ignoreCaseParam) {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: invalid pattern '%s'", apr.Arg(0)).AddCause(err).Build(), usage)
+	}
+
+	err = runGrep(ctx, dEnv, grep.NewSearcher(re), apr.Args()[1:], apr.Contains(allHistoryParam))
+
+	if err == errNoGrepMatches {
+		return 1
+	} else if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: failed to search tables").AddCause(err).Build(), usage)
+	}
+
+	return 0
+}
+
+// runGrep searches the working root, and then the roots of the commits in the current branch's history from newest to
+// oldest if allHistory is true, printing each match. errNoGrepMatches is returned if nothing matched.
+func runGrep(ctx context.Context, dEnv *env.DoltEnv, s *grep.Searcher, tblNames []string, allHistory bool) error {
+	found := false
+	printMatch := func(m grep.Match) error {
+		found = true
+
+		if allHistory {
+			commitStr := "WORKING"
+			if !m.Commit.IsEmpty() {
+				commitStr = m.Commit.String()
+			}
+
+			cli.Printf("%s:", commitStr)
+		}
+
+		cli.Printf("%s %s %s: %s\n", m.Table, m.Key, m.Column, m.Value)
+		return nil
+	}
+
+	root, err := dEnv.WorkingRoot(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if err := checkGrepTables(ctx, root, tblNames, !allHistory); err != nil {
+		return err
+	}
+
+	if err := s.SearchRoot(ctx, root, hash.Hash{}, tblNames, printMatch); err != nil {
+		return err
+	}
+
+	if allHistory {
+		head, err := dEnv.DoltDB.Resolve(ctx, dEnv.RepoState.CWBHeadSpec())
+
+		if err != nil {
+			return err
+		}
+
+		commits, err := actions.TimeSortedCommits(ctx, dEnv.DoltDB, head, -1)
+
+		if err != nil {
+			return err
+		}
+
+		for _, cm := range commits {
+			h, err := cm.HashOf()
+
+			if err != nil {
+				return err
+			}
+
+			cmRoot, err := cm.GetRootValue()
+
+			if err != nil {
+				return err
+			}
+
+			if err := s.SearchRoot(ctx, cmRoot, h, tblNames, printMatch); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !found {
+		return errNoGrepMatches
+	}
+
+	return nil
+}
+
+// checkGrepTables returns an error if any of the tables given isn't a valid table name, or, if mustExist is true,
+// isn't in the root given.
+func checkGrepTables(ctx context.Context, root *doltdb.RootValue, tblNames []string, mustExist bool) error {
+	for _, tblName := range tblNames {
+		if !doltdb.IsValidTableName(tblName) {
+			return errors.New("invalid table name " + tblName)
+		}
+
+		if mustExist {
+			if ok, err := root.HasTable(ctx, tblName); err != nil {
+				return err
+			} else if !ok {
+				return errors.New("unknown table " + tblName)
+			}
+		}
+	}
+
+	return nil
+}
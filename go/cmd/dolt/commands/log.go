@@ -17,38 +17,58 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/diff"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
 	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
 const (
-	numLinesParam = "number"
+	numLinesParam     = "number"
+	abbrevCommitParam = "abbrev-commit"
+	abbrevParam       = "abbrev"
+	searchParam       = "search"
+	tableParam        = "table"
+	colParam          = "col"
+
+	defaultHashAbbrevLen = 8
 )
 
 var logShortDesc = `Show commit logs`
 var logLongDesc = "Shows the commit logs.\n" +
 	"\n" +
-	"The command takes options to control what is shown and how."
+	"The command takes options to control what is shown and how.\n" +
+	"\n" +
+	"With <b>--abbrev-commit</b>, commit hashes are abbreviated to their first 8 characters, or to the number of " +
+	"characters set with <b>--abbrev</b> or the <b>core.abbrev</b> config value. Abbreviated hashes can be used " +
+	"anywhere a commit is expected, as long as no other commit's hash starts with the same characters.\n" +
+	"\n" +
+	"With <b>-S</b> <value>, along with <b>--table</b> and <b>--col</b>, only the commits which changed the number of " +
+	"rows of the table whose column holds the value are shown, such as the commits which added or removed the value, " +
+	"found by comparing each commit to its first parent. <b>-n</b> limits the number of these commits shown."
 
 var logSynopsis = []string{
-	"[-n <num_commits>] [<commit>]",
+	"[-n <num_commits>] [--abbrev-commit] [--abbrev <length>] [-S <value> --table <table> --col <column>] [<commit>]",
 }
 
-type commitLoggerFunc func(*doltdb.CommitMeta, []hash.Hash, hash.Hash)
+// commitLoggerFunc logs a commit, with its hashes abbreviated to hashLen characters, or in full if hashLen is 0.
+type commitLoggerFunc func(cm *doltdb.CommitMeta, parentHashes []hash.Hash, ch hash.Hash, hashLen int)
 
-func logToStdOutFunc(cm *doltdb.CommitMeta, parentHashes []hash.Hash, ch hash.Hash) {
-	cli.Println(color.YellowString("commit %s", ch.String()))
+func logToStdOutFunc(cm *doltdb.CommitMeta, parentHashes []hash.Hash, ch hash.Hash, hashLen int) {
+	cli.Println(color.YellowString("commit %s", abbreviateHash(ch, hashLen)))
 
 	if len(parentHashes) > 1 {
-		printMerge(parentHashes)
+		printMerge(parentHashes, hashLen)
 	}
 
 	printAuthor(cm)
@@ -56,14 +76,25 @@ func logToStdOutFunc(cm *doltdb.CommitMeta, parentHashes []hash.Hash, ch hash.Ha
 	printDesc(cm)
 }
 
-func printMerge(hashes []hash.Hash) {
+func printMerge(hashes []hash.Hash, hashLen int) {
 	cli.Print("Merge:")
 	for _, h := range hashes {
-		cli.Print(" " + h.String())
+		cli.Print(" " + abbreviateHash(h, hashLen))
 	}
 	cli.Println()
 }
 
+// abbreviateHash returns the first n characters of the hash given, or the whole hash if n is 0.
+func abbreviateHash(h hash.Hash, n int) string {
+	str := h.String()
+
+	if n > 0 && n < len(str) {
+		return str[:n]
+	}
+
+	return str
+}
+
 func printAuthor(cm *doltdb.CommitMeta) {
 	cli.Printf("Author: %s <%s>\n", cm.Name, cm.Email)
 }
@@ -85,6 +116,11 @@ func Log(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEn
 func logWithLoggerFunc(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, loggerFunc commitLoggerFunc) int {
 	ap := argparser.NewArgParser()
 	ap.SupportsInt(numLinesParam, "n", "num_commits", "Limit the number of commits to output")
+	ap.SupportsFlag(abbrevCommitParam, "", "Show only a prefix of each commit hash, which is enough to reference the commit by.")
+	ap.SupportsInt(abbrevParam, "", "length", "The number of characters to abbreviate commit hashes to. Implies --abbrev-commit.")
+	ap.SupportsString(searchParam, "S", "value", "Show only the commits which changed the number of rows whose column given by --col holds the value.")
+	ap.SupportsString(tableParam, "", "table", "The table to search with -S.")
+	ap.SupportsString(colParam, "", "column", "The column to search with -S.")
 	help, usage := cli.HelpAndUsagePrinters(commandStr, logShortDesc, logLongDesc, logSynopsis, ap)
 	apr := cli.ParseArgs(ap, args, help)
 
@@ -99,8 +135,128 @@ func logWithLoggerFunc(ctx context.Context, commandStr string, args []string, dE
 		return 1
 	}
 
+	hashLen, err := parseHashAbbrevLen(dEnv, apr)
+	if err != nil {
+		cli.PrintErrln(color.RedString(err.Error()))
+		return 1
+	}
+
+	filter, err := parseSearchFilter(dEnv, apr)
+	if err != nil {
+		cli.PrintErrln(color.RedString(err.Error()))
+		return 1
+	}
+
 	numLines := apr.GetIntOrDefault(numLinesParam, -1)
-	return logCommits(ctx, dEnv, cs, loggerFunc, numLines)
+	return logCommits(ctx, dEnv, cs, loggerFunc, filter, numLines, hashLen)
+}
+
+// commitFilter returns whether a commit should be logged.
+type commitFilter func(ctx context.Context, cm *doltdb.Commit) (bool, error)
+
+// parseSearchFilter returns a filter for the commits which changed the number of rows holding the value given by -S, or
+// nil if -S wasn't given.
+func parseSearchFilter(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (commitFilter, error) {
+	val, ok := apr.GetValue(searchParam)
+	tblName, hasTbl := apr.GetValue(tableParam)
+	colName, hasCol := apr.GetValue(colParam)
+
+	if !ok {
+		if hasTbl || hasCol {
+			return nil, fmt.Errorf("--%s and --%s can only be used with -S", tableParam, colParam)
+		}
+
+		return nil, nil
+	} else if !hasTbl || !hasCol {
+		return nil, fmt.Errorf("-S requires --%s and --%s", tableParam, colParam)
+	}
+
+	return func(ctx context.Context, cm *doltdb.Commit) (bool, error) {
+		to, toSch, err := commitTableData(ctx, dEnv.DoltDB, cm, tblName)
+		if err != nil {
+			return false, err
+		}
+
+		from, fromSch, err := commitTableData(ctx, dEnv.DoltDB, nil, tblName)
+		if err != nil {
+			return false, err
+		}
+
+		if n, err := cm.NumParents(); err != nil {
+			return false, err
+		} else if n > 0 {
+			parent, err := dEnv.DoltDB.ResolveParent(ctx, cm, 0)
+			if err != nil {
+				return false, err
+			}
+
+			from, fromSch, err = commitTableData(ctx, dEnv.DoltDB, parent, tblName)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		n, err := diff.ValueCountChange(ctx, from, to, fromSch, toSch, colName, val)
+		return n != 0, err
+	}, nil
+}
+
+// commitTableData returns the row data and schema of the table with the name given as of the commit given, or an empty
+// map and a nil schema if the table doesn't exist then or the commit is nil.
+func commitTableData(ctx context.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit, tblName string) (types.Map, schema.Schema, error) {
+	if cm != nil {
+		root, err := cm.GetRootValue()
+		if err != nil {
+			return types.EmptyMap, nil, err
+		}
+
+		tbl, ok, err := root.GetTable(ctx, tblName)
+		if err != nil {
+			return types.EmptyMap, nil, err
+		} else if ok {
+			sch, err := tbl.GetSchema(ctx)
+			if err != nil {
+				return types.EmptyMap, nil, err
+			}
+
+			rows, err := tbl.GetRowData(ctx)
+			if err != nil {
+				return types.EmptyMap, nil, err
+			}
+
+			return rows, sch, nil
+		}
+	}
+
+	empty, err := types.NewMap(ctx, ddb.ValueReadWriter())
+	return empty, nil, err
+}
+
+// parseHashAbbrevLen returns the number of characters to abbreviate commit hashes to, or 0 if they shouldn't be
+// abbreviated.
+func parseHashAbbrevLen(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (int, error) {
+	hashLen, ok := apr.GetInt(abbrevParam)
+
+	if !ok {
+		if !apr.Contains(abbrevCommitParam) {
+			return 0, nil
+		}
+
+		cfgLen := dEnv.Config.GetStringOrDefault(env.HashAbbrevLenKey, strconv.Itoa(defaultHashAbbrevLen))
+		n, err := strconv.Atoi(*cfgLen)
+
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s '%s'", env.HashAbbrevLenKey, *cfgLen)
+		}
+
+		hashLen = n
+	}
+
+	if hashLen < doltdb.MinHashPrefixLen || hashLen > hash.StringLen {
+		return 0, fmt.Errorf("commit hashes can only be abbreviated to between %d and %d characters", doltdb.MinHashPrefixLen, hash.StringLen)
+	}
+
+	return hashLen, nil
 }
 
 func parseCommitSpec(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (*doltdb.CommitSpec, error) {
@@ -118,21 +274,51 @@ func parseCommitSpec(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (*doltdb
 	return cs, nil
 }
 
-func logCommits(ctx context.Context, dEnv *env.DoltEnv, cs *doltdb.CommitSpec, loggerFunc commitLoggerFunc, numLines int) int {
+// logCommits logs up to numLines of the commits reachable from the commit given, newest first, or all of them if
+// numLines is negative. If filter isn't nil, only the commits it accepts are logged.
+func logCommits(ctx context.Context, dEnv *env.DoltEnv, cs *doltdb.CommitSpec, loggerFunc commitLoggerFunc, filter commitFilter, numLines, hashLen int) int {
 	commit, err := dEnv.DoltDB.Resolve(ctx, cs)
 
-	if err != nil {
+	if err == doltdb.ErrAmbiguousHashPrefix {
+		cli.PrintErrln(color.HiRedString("Fatal error: the abbreviated commit hash matches more than one commit."))
+		return 1
+	} else if err != nil {
 		cli.PrintErrln(color.HiRedString("Fatal error: cannot get HEAD commit for current branch."))
 		return 1
 	}
 
-	commits, err := actions.TimeSortedCommits(ctx, dEnv.DoltDB, commit, numLines)
+	maxCommits := numLines
+	if filter != nil {
+		maxCommits = -1
+	}
+
+	commits, err := actions.TimeSortedCommits(ctx, dEnv.DoltDB, commit, maxCommits)
 
 	if err != nil {
 		cli.PrintErrln("Error retrieving commit.")
 		return 1
 	}
 
+	if filter != nil {
+		var filtered []*doltdb.Commit
+		for _, comm := range commits {
+			if numLines >= 0 && len(filtered) >= numLines {
+				break
+			}
+
+			ok, err := filter(ctx, comm)
+
+			if err != nil {
+				cli.PrintErrln(color.HiRedString("error: failed to search commit: %v", err))
+				return 1
+			} else if ok {
+				filtered = append(filtered, comm)
+			}
+		}
+
+		commits = filtered
+	}
+
 	for _, comm := range commits {
 		meta, err := comm.GetCommitMeta()
 
@@ -154,7 +340,7 @@ func logCommits(ctx context.Context, dEnv *env.DoltEnv, cs *doltdb.CommitSpec, l
 			cli.PrintErrln("error: failed to get commit hash")
 			return 1
 		}
-		loggerFunc(meta, pHashes, cmHash)
+		loggerFunc(meta, pHashes, cmHash, hashLen)
 	}
 
 	return 0
@@ -0,0 +1,147 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+var lsRemoteShortDesc = "List references in a remote repository"
+var lsRemoteLongDesc = "Displays the branches available in a remote repository along with the hash of the commit at the " +
+	"head of each one. No data is fetched and the command does not need to be run inside a dolt repository, making " +
+	"it useful for scripting and for inspecting a repository before cloning it.\n" +
+	"\n" +
+	"The <repository> parameter may be either the name of a remote configured for the current repository or a remote " +
+	"url in any of the forms accepted by <b>dolt clone</b>. If it is omitted the remote named 'origin' is used."
+var lsRemoteSynopsis = []string{
+	"[--aws-region <region>] [--aws-creds-type <creds-type>] [--aws-creds-file <file>] [--aws-creds-profile <profile>] [<repository>]",
+}
+
+func LsRemote(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["repository"] = "The name of a remote, or the url of a remote repository."
+	ap.SupportsString(dbfactory.AWSRegionParam, "", "region", "")
+	ap.SupportsValidatedString(dbfactory.AWSCredsTypeParam, "", "creds-type", "", argparser.ValidatorFromStrList(dbfactory.AWSCredsTypeParam, credTypes))
+	ap.SupportsString(dbfactory.AWSCredsFileParam, "", "file", "AWS credentials file.")
+	ap.SupportsString(dbfactory.AWSCredsProfile, "", "profile", "AWS profile to use.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, lsRemoteShortDesc, lsRemoteLongDesc, lsRemoteSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() > 1 {
+		usage()
+		return 1
+	}
+
+	r, verr := getLsRemoteTarget(dEnv, apr)
+
+	if verr == nil {
+		verr = printRemoteRefs(ctx, r)
+	}
+
+	return HandleVErrAndExitCode(verr, usage)
+}
+
+// getLsRemoteTarget returns the remote named by the arguments given. A configured remote with a matching name takes
+// precedence over interpreting the argument as a url.
+func getLsRemoteTarget(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) (env.Remote, errhand.VerboseError) {
+	remName := "origin"
+	if apr.NArg() == 1 {
+		remName = apr.Arg(0)
+	}
+
+	if dEnv.HasDoltDir() && dEnv.RSLoadErr == nil {
+		remotes, err := dEnv.GetRemotes()
+
+		if err != nil {
+			return env.NoRemote, errhand.BuildDError("error: unable to read remotes").AddCause(err).Build()
+		}
+
+		if r, ok := remotes[remName]; ok {
+			return r, nil
+		}
+	}
+
+	if apr.NArg() == 0 {
+		return env.NoRemote, errhand.BuildDError("error: unknown remote 'origin'").SetPrintUsage().Build()
+	}
+
+	scheme, remoteUrl, err := getAbsRemoteUrl(dEnv.FS, dEnv.Config, remName)
+
+	if err != nil {
+		return env.NoRemote, errhand.BuildDError("error: '%s' is not a known remote or a valid remote url.", remName).Build()
+	}
+
+	params, verr := parseRemoteArgs(apr, scheme, remoteUrl)
+
+	if verr != nil {
+		return env.NoRemote, verr
+	}
+
+	return env.NewRemote(remName, remoteUrl, params), nil
+}
+
+// printRemoteRefs prints the hash and name of each branch in the remote given, sorted by name.
+func printRemoteRefs(ctx context.Context, r env.Remote) errhand.VerboseError {
+	srcDB, err := r.GetRemoteDB(ctx, types.Format_Default)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to get remote db").AddCause(err).Build()
+	}
+
+	refs, err := srcDB.GetRefsOfType(ctx, map[ref.RefType]struct{}{ref.BranchRefType: {}})
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to read refs from remote").AddCause(err).Build()
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].String() < refs[j].String()
+	})
+
+	for _, dref := range refs {
+		cs, err := doltdb.NewCommitSpec("HEAD", dref.String())
+
+		if err != nil {
+			return errhand.BuildDError("error: invalid ref '%s'", dref.String()).AddCause(err).Build()
+		}
+
+		cm, err := srcDB.Resolve(ctx, cs)
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to resolve '%s'", dref.String()).AddCause(err).Build()
+		}
+
+		h, err := cm.HashOf()
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to hash commit").AddCause(err).Build()
+		}
+
+		cli.Println(fmt.Sprintf("%s\t%s", h.String(), dref.String()))
+	}
+
+	return nil
+}
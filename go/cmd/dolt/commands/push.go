@@ -17,6 +17,7 @@ package commands
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -34,10 +35,12 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/earl"
 	"github.com/liquidata-inc/dolt/go/store/datas"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 )
 
 const (
 	SetUpstreamFlag = "set-upstream"
+	MirrorFlag      = "mirror"
 )
 
 var pushShortDesc = "Update remote refs along with associated objects"
@@ -53,15 +56,26 @@ var pushLongDesc = "Updates remote refs using local refs, while sending objects
 	"\n" +
 	"\nWhen neither the command-line does not specify what to push, the default behavior is used, which corresponds to the " +
 	"current branch being pushed to the corresponding upstream branch, but as a safety measure, the push is aborted if " +
-	"the upstream branch does not have the same name as the local one."
+	"the upstream branch does not have the same name as the local one." +
+	"\n" +
+	"\nWith <b>--mirror</b>, every local branch is pushed to the branch of the same name on the remote, replacing it even " +
+	"if the push isn't a fast forward, and branches of the remote which don't exist locally are deleted, so that the " +
+	"remote's branches match the local ones exactly. This keeps a backup of a repository, and can be run on a schedule " +
+	"with a push step of an automation job (see <b>dolt automation</b>)." +
+	"\n" +
+	"\nPushing an ephemeral branch, created with <b>dolt branch --ttl</b>, gives the remote branch the same expiry, and " +
+	"pushing a permanent one makes the remote branch permanent. Every push deletes the remote's ephemeral branches that " +
+	"have expired, so that the branches CI jobs push to a shared remote don't accumulate."
 
 var pushSynopsis = []string{
 	"[-u | --set-upstream] [<remote>] [<refspec>]",
+	"--mirror [<remote>]",
 }
 
 func Push(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
 	ap := argparser.NewArgParser()
 	ap.SupportsFlag(SetUpstreamFlag, "u", "For every branch that is up to date or successfully pushed, add upstream (tracking) reference, used by argument-less dolt pull and other commands.")
+	ap.SupportsFlag(MirrorFlag, "", "Make the branches of the remote match the local branches exactly, forcing updates and deleting branches that don't exist locally.")
 	help, usage := cli.HelpAndUsagePrinters(commandStr, pushShortDesc, pushLongDesc, pushSynopsis, ap)
 	apr := cli.ParseArgs(ap, args, help)
 
@@ -72,6 +86,10 @@ func Push(ctx context.Context, commandStr string, args []string, dEnv *env.DoltE
 		return 1
 	}
 
+	if apr.Contains(MirrorFlag) {
+		return HandleVErrAndExitCode(pushMirror(ctx, dEnv, apr, remotes), usage)
+	}
+
 	remoteName := "origin"
 	remote, remoteOK := remotes[remoteName]
 
@@ -179,6 +197,10 @@ func Push(ctx context.Context, commandStr string, args []string, dEnv *env.DoltE
 					verr = deleteRemoteBranch(ctx, dest, remoteRef, dEnv.DoltDB, destDB, remote)
 				} else {
 					verr = pushToRemoteBranch(ctx, dEnv, src, dest, remoteRef, dEnv.DoltDB, destDB, remote)
+
+					if verr == nil {
+						verr = deleteExpiredRemoteBranches(ctx, dEnv, destDB, remote)
+					}
 				}
 			}
 
@@ -204,6 +226,186 @@ func Push(ctx context.Context, commandStr string, args []string, dEnv *env.DoltE
 	return HandleVErrAndExitCode(verr, usage)
 }
 
+// pushMirror pushes every local branch to the remote given by the arguments, or the default remote, replacing the
+// remote's branches whether or not the pushes are fast forwards, and deletes the remote's branches that don't exist
+// locally. The remote tracking branches are updated to match.
+func pushMirror(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults, remotes map[string]env.Remote) errhand.VerboseError {
+	if apr.NArg() > 1 || apr.Contains(SetUpstreamFlag) {
+		return errhand.BuildDError("error: --mirror takes only a remote, and can't be used with refspecs or --set-upstream.").SetPrintUsage().Build()
+	}
+
+	var remote env.Remote
+	if apr.NArg() == 1 {
+		var ok bool
+		remote, ok = remotes[apr.Arg(0)]
+
+		if !ok {
+			return errhand.BuildDError("fatal: unknown remote %s", apr.Arg(0)).Build()
+		}
+	} else {
+		var verr errhand.VerboseError
+		remote, verr = dEnv.GetDefaultRemote()
+
+		if verr != nil {
+			return verr
+		}
+	}
+
+	destDB, err := remote.GetRemoteDB(ctx, dEnv.DoltDB.ValueReadWriter().Format())
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to get remote db").AddCause(err).Build()
+	}
+
+	localBranches, err := dEnv.DoltDB.GetBranches(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to read branches").AddCause(err).Build()
+	}
+
+	remoteBranches, err := destDB.GetBranches(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to read the branches of remote '%s'", remote.Name).AddCause(err).Build()
+	}
+
+	sort.Slice(localBranches, func(i, j int) bool {
+		return localBranches[i].GetPath() < localBranches[j].GetPath()
+	})
+
+	remoteHashes := make(map[string]hash.Hash)
+	for _, branch := range remoteBranches {
+		cm, err := resolveBranchHead(ctx, destDB, branch)
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to resolve '%s' on '%s'", branch.GetPath(), remote.Name).AddCause(err).Build()
+		}
+
+		remoteHashes[branch.GetPath()], err = cm.HashOf()
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to hash commit").AddCause(err).Build()
+		}
+	}
+
+	changed := false
+	printChange := func(format string, args ...interface{}) {
+		if !changed {
+			cli.Printf("To %s\n", remote.Url)
+			changed = true
+		}
+
+		cli.Printf(format, args...)
+	}
+
+	isLocal := make(map[string]bool)
+	for _, branch := range localBranches {
+		isLocal[branch.GetPath()] = true
+
+		cm, err := resolveBranchHead(ctx, dEnv.DoltDB, branch)
+
+		if err != nil {
+			return errhand.BuildDError("error: unable to find %v", branch.GetPath()).AddCause(err).Build()
+		}
+
+		h, err := cm.HashOf()
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to hash commit").AddCause(err).Build()
+		}
+
+		if remoteHash, ok := remoteHashes[branch.GetPath()]; !ok || remoteHash != h {
+			wg, progChan, pullerEventCh := runProgFuncs()
+			err = actions.MirrorBranch(ctx, dEnv, branch.(ref.BranchRef), dEnv.DoltDB, destDB, cm, progChan, pullerEventCh)
+			stopProgFuncs(wg, progChan, pullerEventCh)
+
+			if err != nil && err != doltdb.ErrUpToDate {
+				return errhand.BuildDError("error: failed to push '%s' to remote '%s'", branch.GetPath(), remote.Name).AddCause(err).Build()
+			}
+
+			printChange(" + %s -> %s\n", branch.GetPath(), branch.GetPath())
+		}
+
+		if err := actions.PushBranchExpiry(ctx, branch, branch, dEnv.DoltDB, destDB); err != nil {
+			return errhand.BuildDError("error: failed to push the expiry of '%s'", branch.GetPath()).AddCause(err).Build()
+		}
+
+		remoteRef, verr := getTrackingRef(branch, remote)
+
+		if verr != nil {
+			return verr
+		} else if remoteRef != nil {
+			if err := dEnv.DoltDB.SetRef(ctx, remoteRef, cm); err != nil {
+				return errhand.BuildDError("error: failed to update '%s'", remoteRef.String()).AddCause(err).Build()
+			}
+		}
+	}
+
+	for _, branch := range remoteBranches {
+		if isLocal[branch.GetPath()] {
+			continue
+		}
+
+		if err := destDB.DeleteBranch(ctx, branch); err != nil {
+			return errhand.BuildDError("error: failed to delete '%s' from remote '%s'", branch.GetPath(), remote.Name).AddCause(err).Build()
+		} else if _, err := destDB.ClearBranchExpiry(ctx, branch); err != nil {
+			return errhand.BuildDError("error: failed to delete '%s' from remote '%s'", branch.GetPath(), remote.Name).AddCause(err).Build()
+		}
+
+		printChange(" - [deleted]         %s\n", branch.GetPath())
+
+		remoteRef, verr := getTrackingRef(branch, remote)
+
+		if verr != nil {
+			return verr
+		} else if remoteRef != nil {
+			if hasRef, err := dEnv.DoltDB.HasRef(ctx, remoteRef); err != nil {
+				return errhand.BuildDError("error: failed to read from db").AddCause(err).Build()
+			} else if hasRef {
+				if err := dEnv.DoltDB.DeleteBranch(ctx, remoteRef); err != nil {
+					return errhand.BuildDError("error: failed to delete '%s'", remoteRef.String()).AddCause(err).Build()
+				}
+			}
+		}
+	}
+
+	if !changed {
+		cli.Println("Everything up-to-date")
+	}
+
+	return deleteExpiredRemoteBranches(ctx, dEnv, destDB, remote)
+}
+
+// deleteExpiredRemoteBranches deletes the ephemeral branches of the remote given that have expired, along with their
+// remote tracking branches, so that the branches pushed to a shared remote by CI jobs don't accumulate.
+func deleteExpiredRemoteBranches(ctx context.Context, dEnv *env.DoltEnv, destDB *doltdb.DoltDB, remote env.Remote) errhand.VerboseError {
+	deleted, err := actions.DeleteExpiredBranches(ctx, destDB, time.Now(), nil)
+
+	for _, eb := range deleted {
+		cli.Printf(" - [expired]         %s\n", eb.Branch.GetPath())
+
+		remoteRef, verr := getTrackingRef(eb.Branch, remote)
+
+		if verr != nil {
+			return verr
+		} else if remoteRef != nil {
+			if hasRef, err := dEnv.DoltDB.HasRef(ctx, remoteRef); err != nil {
+				return errhand.BuildDError("error: failed to read from db").AddCause(err).Build()
+			} else if hasRef {
+				if err := dEnv.DoltDB.DeleteBranch(ctx, remoteRef); err != nil {
+					return errhand.BuildDError("error: failed to delete '%s'", remoteRef.String()).AddCause(err).Build()
+				}
+			}
+		}
+	}
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to delete the expired branches of remote '%s'", remote.Name).AddCause(err).Build()
+	}
+
+	return nil
+}
+
 func getTrackingRef(branchRef ref.DoltRef, remote env.Remote) (ref.DoltRef, errhand.VerboseError) {
 	for _, fsStr := range remote.FetchSpecs {
 		fs, err := ref.ParseRefSpecForRemote(remote.Name, fsStr)
@@ -253,6 +455,12 @@ func pushToRemoteBranch(ctx context.Context, dEnv *env.DoltEnv, srcRef, destRef,
 		err = actions.Push(ctx, dEnv, destRef.(ref.BranchRef), remoteRef.(ref.RemoteRef), localDB, remoteDB, cm, progChan, pullerEventCh)
 		stopProgFuncs(wg, progChan, pullerEventCh)
 
+		if err == nil || err == doltdb.ErrUpToDate {
+			if err := actions.PushBranchExpiry(ctx, srcRef, destRef, localDB, remoteDB); err != nil {
+				return errhand.BuildDError("error: failed to push the expiry of '%s'", srcRef.GetPath()).AddCause(err).Build()
+			}
+		}
+
 		if err != nil {
 			if err == doltdb.ErrUpToDate {
 				cli.Println("Everything up-to-date")
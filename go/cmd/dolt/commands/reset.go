@@ -172,7 +172,7 @@ func printNotStaged(ctx context.Context, dEnv *env.DoltEnv, staged *doltdb.RootV
 		return
 	}
 
-	if notStaged.NumRemoved+notStaged.NumModified > 0 {
+	if notStaged.NumRemoved+notStaged.NumModified+notStaged.NumRenamed > 0 {
 		cli.Println("Unstaged changes after reset:")
 
 		lines := make([]string, 0, notStaged.Len())
@@ -180,7 +180,7 @@ func printNotStaged(ctx context.Context, dEnv *env.DoltEnv, staged *doltdb.RootV
 			tdt := notStaged.TableToType[tblName]
 
 			if tdt != actions.AddedTable {
-				lines = append(lines, fmt.Sprintf("%s\t%s", tblDiffTypeToShortLabel[tdt], tblName))
+				lines = append(lines, fmt.Sprintf("%s\t%s", tblDiffTypeToShortLabel[tdt], diffTableName(notStaged, tblName)))
 			}
 		}
 
@@ -34,12 +34,13 @@ const (
 	defaultParam = "default"
 	tagParam     = "tag"
 	notNullFlag  = "not-null"
+	commentParam = "comment"
 )
 
 var schAddColShortDesc = "Adds a column to specified table's schema"
 var schAddColLongDesc = "Adds a column to the specified table's schema. If no default value is provided the column will be empty"
 var schAddColSynopsis = []string{
-	"[--default <default_value>] [--not-null] [--tag <tag-number>] <table> <name> <type>",
+	"[--default <default_value>] [--not-null] [--tag <tag-number>] [--comment <comment>] <table> <name> <type>",
 }
 
 func AddColumn(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
@@ -47,6 +48,7 @@ func AddColumn(ctx context.Context, commandStr string, args []string, dEnv *env.
 	ap.ArgListHelp["table"] = "table where the new column should be added."
 	ap.SupportsString(defaultParam, "", "default-value", "If provided all existing rows will be given this value as their default.")
 	ap.SupportsUint(tagParam, "", "tag-number", "The numeric tag for the new column.")
+	ap.SupportsString(commentParam, "", "comment", "The comment documenting the new column.")
 	ap.SupportsFlag(notNullFlag, "", "If provided rows without a value in this column will be considered invalid.  If rows already exist and not-null is specified then a default value must be provided.")
 
 	help, usage := cli.HelpAndUsagePrinters(commandStr, schAddColShortDesc, schAddColLongDesc, schAddColSynopsis, ap)
@@ -114,6 +116,13 @@ func addField(ctx context.Context, apr *argparser.ArgParseResults, root *doltdb.
 		return errhand.VerboseErrorFromError(err)
 	}
 
+	if comment, ok := apr.GetValue(commentParam); ok {
+		newTable, err = alterschema.SetColumnComment(ctx, dEnv.DoltDB, newTable, newFieldName, comment)
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+	}
+
 	root, err = root.PutTable(ctx, tblName, newTable)
 
 	if err != nil {
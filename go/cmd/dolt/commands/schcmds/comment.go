@@ -0,0 +1,92 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schcmds
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/alterschema"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+var schCommentShortDesc = "Sets the comment documenting a table or column"
+var schCommentLongDesc = "Sets the comment documenting a table, or a single column of a table if a column name is given, replacing any " +
+	"comment it had. An empty comment removes it. Comments are stored in the table's schema, and are included in the " +
+	"output of dolt schema show and dolt schema export, and in SHOW CREATE TABLE and information_schema in dolt sql."
+var schCommentSynopsis = []string{
+	"<table> [<column>] <comment>",
+}
+
+func Comment(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["table"] = "table the comment applies to."
+	ap.ArgListHelp["column"] = "column the comment applies to. If omitted the comment applies to the whole table."
+	ap.ArgListHelp["comment"] = "the text of the comment."
+
+	help, usage := cli.HelpAndUsagePrinters(commandStr, schCommentShortDesc, schCommentLongDesc, schCommentSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr == nil {
+		verr = setComment(ctx, apr, root, dEnv)
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+func setComment(ctx context.Context, apr *argparser.ArgParseResults, root *doltdb.RootValue, dEnv *env.DoltEnv) errhand.VerboseError {
+	if apr.NArg() < 2 || apr.NArg() > 3 {
+		return errhand.BuildDError("Must specify a table name, optionally a column name, and a comment.").SetPrintUsage().Build()
+	}
+
+	tblName := apr.Arg(0)
+	tbl, ok, err := root.GetTable(ctx, tblName)
+
+	if err != nil {
+		return errhand.BuildDError("error: could not read tables from database").AddCause(err).Build()
+	} else if !ok {
+		return errhand.BuildDError("%s not found", tblName).Build()
+	}
+
+	var newTbl *doltdb.Table
+	if apr.NArg() == 2 {
+		newTbl, err = alterschema.SetTableComment(ctx, dEnv.DoltDB, tbl, apr.Arg(1))
+	} else {
+		colName := apr.Arg(1)
+		newTbl, err = alterschema.SetColumnComment(ctx, dEnv.DoltDB, tbl, colName, apr.Arg(2))
+
+		if err != nil {
+			return errToVerboseErr(colName, colName, err)
+		}
+	}
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to set comment").AddCause(err).Build()
+	}
+
+	root, err = root.PutTable(ctx, tblName, newTbl)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to write table back to database").Build()
+	}
+
+	return commands.UpdateWorkingWithVErr(dEnv, root)
+}
@@ -0,0 +1,197 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schcmds
+
+import (
+	"context"
+	"sort"
+
+	"github.com/fatih/color"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/diff"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/set"
+)
+
+const repoParam = "repo"
+
+var schDiffShortDesc = "Reports the schema changes between two commits."
+var schDiffLongDesc = `Reports the changes made to the schemas of tables between the <from> and <to> commits, which are branch names,
+commit hashes or other commit specs. The tables given are compared, or all the tables of either commit if none are.
+
+Each change is reported on its own line: tables added and removed, and columns added, removed, renamed, changing type,
+moving in or out of the primary key, or changing whether they can be null. Columns are matched by their tags, or by
+their names when a column was dropped and added back. Changes that may break the readers of the <from> commit are
+marked as breaking: removing a table or a column, renaming a column, changing its type, changing the primary key and
+allowing nulls in a column that didn't allow them.
+
+The command exits with 1 if there are breaking changes, so it can be used to detect breaking schema drift before
+upgrading to a new version of a dataset. With --repo, <to> is a commit of another repository, given by the path of a
+local repository, the name of a remote or the url of a remote repository.`
+
+var schDiffSynopsis = []string{
+	"[--repo <location>] <from> <to> [<table>...]",
+}
+
+func Diff(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["from"] = "the commit whose schemas are compared against."
+	ap.ArgListHelp["to"] = "the commit whose schemas are compared."
+	ap.ArgListHelp["table"] = "table(s) whose schemas are compared."
+	ap.SupportsString(repoParam, "", "location", "The repository that <to> is a commit of, instead of this one.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, schDiffShortDesc, schDiffLongDesc, schDiffSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() < 2 {
+		usage()
+		return 1
+	}
+
+	fromRoot, verr := resolveRoot(ctx, dEnv.DoltDB, dEnv.RepoState.Head.Ref.String(), apr.Arg(0))
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	toDB, toHead := dEnv.DoltDB, dEnv.RepoState.Head.Ref.String()
+	if location, ok := apr.GetValue(repoParam); ok {
+		var err error
+		toDB, err = commands.OpenDoltDB(ctx, dEnv, location)
+		if err != nil {
+			verr := errhand.BuildDError("error: unable to open repository '%s'", location).AddCause(err).Build()
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
+		toHead = doltdb.MasterBranch
+	}
+
+	toRoot, verr := resolveRoot(ctx, toDB, toHead, apr.Arg(1))
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	breaking, verr := printSchemaDrift(ctx, fromRoot, toRoot, apr.Args()[2:])
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	if breaking == 1 {
+		cli.PrintErrln(color.RedString("1 breaking schema change"))
+		return 1
+	} else if breaking > 1 {
+		cli.PrintErrln(color.RedString("%d breaking schema changes", breaking))
+		return 1
+	}
+
+	return 0
+}
+
+// resolveRoot returns the root of the commit given by the spec in the database given, in which HEAD refers to the
+// branch given.
+func resolveRoot(ctx context.Context, ddb *doltdb.DoltDB, head, spec string) (*doltdb.RootValue, errhand.VerboseError) {
+	cs, err := doltdb.NewCommitSpec(spec, head)
+	if err != nil {
+		return nil, errhand.BuildDError("'%s' is not a valid commit", spec).Build()
+	}
+
+	cm, err := ddb.Resolve(ctx, cs)
+	if err != nil {
+		return nil, errhand.BuildDError("error: unable to resolve '%s'", spec).AddCause(err).Build()
+	}
+
+	root, err := cm.GetRootValue()
+	if err != nil {
+		return nil, errhand.BuildDError("error: unable to read '%s'", spec).AddCause(err).Build()
+	}
+
+	return root, nil
+}
+
+// printSchemaDrift prints the changes to the schemas of the tables given, or of all tables if none are given, between
+// the two roots, and returns the number of breaking changes.
+func printSchemaDrift(ctx context.Context, fromRoot, toRoot *doltdb.RootValue, tblNames []string) (int, errhand.VerboseError) {
+	if len(tblNames) == 0 {
+		fromNames, err := fromRoot.GetTableNames(ctx)
+		if err != nil {
+			return 0, errhand.BuildDError("error: unable to read the tables").AddCause(err).Build()
+		}
+
+		toNames, err := toRoot.GetTableNames(ctx)
+		if err != nil {
+			return 0, errhand.BuildDError("error: unable to read the tables").AddCause(err).Build()
+		}
+
+		tblNames = set.Unique(append(fromNames, toNames...))
+		sort.Strings(tblNames)
+	}
+
+	breaking := 0
+	for _, tblName := range tblNames {
+		fromSch, inFrom, err := tableSchema(ctx, fromRoot, tblName)
+		if err != nil {
+			return 0, errhand.BuildDError("error: unable to read the schema of table '%s'", tblName).AddCause(err).Build()
+		}
+
+		toSch, inTo, err := tableSchema(ctx, toRoot, tblName)
+		if err != nil {
+			return 0, errhand.BuildDError("error: unable to read the schema of table '%s'", tblName).AddCause(err).Build()
+		}
+
+		switch {
+		case !inFrom && !inTo:
+			return 0, errhand.BuildDError("error: unknown table '%s'", tblName).Build()
+		case !inFrom:
+			cli.Println(color.GreenString("added table %s", tblName))
+		case !inTo:
+			cli.Println(color.RedString("removed table %s (breaking)", tblName))
+			breaking++
+		default:
+			drift, err := diff.DriftSchemas(fromSch, toSch)
+			if err != nil {
+				return 0, errhand.BuildDError("error: failed to diff the schemas of table '%s'", tblName).AddCause(err).Build()
+			}
+
+			for _, d := range drift {
+				if d.Breaking {
+					cli.Println(color.RedString("%s: %s (breaking)", tblName, d.Desc))
+					breaking++
+				} else {
+					cli.Println(color.GreenString("%s: %s", tblName, d.Desc))
+				}
+			}
+		}
+	}
+
+	return breaking, nil
+}
+
+// tableSchema returns the schema of the table with the name given in the root given, and whether the table exists.
+func tableSchema(ctx context.Context, root *doltdb.RootValue, tblName string) (schema.Schema, bool, error) {
+	tbl, ok, err := root.GetTable(ctx, tblName)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return sch, true, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schcmds
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+const dropParam = "drop"
+
+var schMaterializeShortDesc = "Defines a materialized view"
+var schMaterializeLongDesc = "Defines a materialized view, which stores the results of a select statement in the table with the view's name, " +
+	"replacing any materialized view with the same name. The first column of the results is the table's primary key, " +
+	"so it must be unique and never null.\n" +
+	"\n" +
+	"The results are recomputed from the staged tables by each dolt commit, so each commit holds the results of the " +
+	"statement as of that commit, and reading them is as fast as reading any other table. Definitions are stored in " +
+	"the " + doltdb.SchemasTableName + " table, which is versioned like any other table.\n" +
+	"\n" +
+	"With <b>--drop</b>, the materialized view and its table are removed."
+var schMaterializeSynopsis = []string{
+	"<view> <select statement>",
+	"--drop <view>",
+}
+
+func Materialize(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["view"] = "name of the materialized view, and of the table holding its results."
+	ap.ArgListHelp["select statement"] = "the select statement whose results are stored."
+	ap.SupportsFlag(dropParam, "", "Removes the materialized view and its table.")
+
+	help, usage := cli.HelpAndUsagePrinters(commandStr, schMaterializeShortDesc, schMaterializeLongDesc, schMaterializeSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr == nil {
+		if apr.Contains(dropParam) {
+			verr = dropMaterializedView(ctx, apr, root, dEnv)
+		} else {
+			verr = materializeView(ctx, apr, root, dEnv)
+		}
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+func materializeView(ctx context.Context, apr *argparser.ArgParseResults, root *doltdb.RootValue, dEnv *env.DoltEnv) errhand.VerboseError {
+	if apr.NArg() != 2 {
+		return errhand.BuildDError("Must specify a view name and a select statement.").SetPrintUsage().Build()
+	}
+
+	name := apr.Arg(0)
+	root, err := dsqle.MaterializeView(ctx, root, name, apr.Arg(1))
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to materialize view '%s'", name).AddCause(err).Build()
+	}
+
+	return commands.UpdateWorkingWithVErr(dEnv, root)
+}
+
+func dropMaterializedView(ctx context.Context, apr *argparser.ArgParseResults, root *doltdb.RootValue, dEnv *env.DoltEnv) errhand.VerboseError {
+	if apr.NArg() != 1 {
+		return errhand.BuildDError("Must specify a view name.").SetPrintUsage().Build()
+	}
+
+	name := apr.Arg(0)
+	root, ok, err := dsqle.DropMaterializedView(ctx, root, name)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to drop materialized view '%s'", name).AddCause(err).Build()
+	} else if !ok {
+		return errhand.BuildDError("error: materialized view '%s' not found", name).Build()
+	}
+
+	return commands.UpdateWorkingWithVErr(dEnv, root)
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schcmds
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/alterschema"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+var schMergeStrategyShortDesc = "Sets how changes to a column are merged"
+var schMergeStrategyLongDesc = `Sets the strategy used to merge the changes made to the values of a column on two branches. The strategy is stored in the table's schema, is shown by dolt schema show, and is honored by dolt merge when the same value was changed on both sides of a merge:
+
+	default  the value conflicts, unless both sides changed it to the same value
+	counter  the value is a counter, and the changes made on both sides are added up, so that the increments and decrements made on each side are all kept. Only numeric columns can be counters.
+	set      the value is a comma separated set of elements. The elements added on either side are kept, except for those that were removed on either side, and are sorted. Only string columns can be sets.
+
+A value that both sides changed to the same value is kept with every strategy. Primary key columns always use the default strategy, and with the other strategies a value still conflicts when either side set it to NULL.`
+var schMergeStrategySynopsis = []string{
+	"<table> <column> default|counter|set",
+}
+
+func MergeStrategy(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["table"] = "table containing the column."
+	ap.ArgListHelp["column"] = "column the strategy applies to."
+
+	help, usage := cli.HelpAndUsagePrinters(commandStr, schMergeStrategyShortDesc, schMergeStrategyLongDesc, schMergeStrategySynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr == nil {
+		verr = setMergeStrategy(ctx, apr, root, dEnv)
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+func setMergeStrategy(ctx context.Context, apr *argparser.ArgParseResults, root *doltdb.RootValue, dEnv *env.DoltEnv) errhand.VerboseError {
+	if apr.NArg() != 3 {
+		return errhand.BuildDError("Must specify a table name, a column name and a merge strategy.").SetPrintUsage().Build()
+	}
+
+	tblName, colName := apr.Arg(0), apr.Arg(1)
+	strategy, err := schema.ParseMergeStrategy(apr.Arg(2))
+
+	if err != nil {
+		return errhand.BuildDError("error: %s", err.Error()).Build()
+	}
+
+	tbl, ok, err := root.GetTable(ctx, tblName)
+
+	if err != nil {
+		return errhand.BuildDError("error: could not read tables from database").AddCause(err).Build()
+	} else if !ok {
+		return errhand.BuildDError("%s not found", tblName).Build()
+	}
+
+	newTbl, err := alterschema.SetColumnMergeStrategy(ctx, dEnv.DoltDB, tbl, colName, strategy)
+
+	if err != nil {
+		return errToVerboseErr(colName, colName, err)
+	}
+
+	root, err = root.PutTable(ctx, tblName, newTbl)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to write table back to database").Build()
+	}
+
+	return commands.UpdateWorkingWithVErr(dEnv, root)
+}
@@ -0,0 +1,128 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schcmds
+
+import (
+	"context"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+const (
+	sourceURLParam = "source-url"
+	licenseParam   = "license"
+	extractedParam = "extracted"
+)
+
+var schProvenanceShortDesc = "Records where the data in a table or column came from"
+var schProvenanceLongDesc = "Attaches provenance metadata to a table, or to a single column of a table if a column name is given. " +
+	"Metadata is stored in the " + doltdb.ProvenanceTableName + " table, which is versioned like any other table and can be queried with dolt sql. " +
+	"Only the fields provided are changed. Recorded provenance is displayed by dolt schema show."
+var schProvenanceSynopsis = []string{
+	"[--source-url <url>] [--license <license>] [--extracted <date>] <table> [<column>]",
+}
+
+func Provenance(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["table"] = "table the provenance applies to."
+	ap.ArgListHelp["column"] = "column the provenance applies to. If omitted the provenance applies to the whole table."
+	ap.SupportsString(sourceURLParam, "", "url", "The URL the data was retrieved from.")
+	ap.SupportsString(licenseParam, "", "license", "The license the data is distributed under.")
+	ap.SupportsString(extractedParam, "", "date", "The date the data was extracted from its source.")
+
+	help, usage := cli.HelpAndUsagePrinters(commandStr, schProvenanceShortDesc, schProvenanceLongDesc, schProvenanceSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	root, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr == nil {
+		verr = putProvenance(ctx, apr, root, dEnv)
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+func putProvenance(ctx context.Context, apr *argparser.ArgParseResults, root *doltdb.RootValue, dEnv *env.DoltEnv) errhand.VerboseError {
+	if apr.NArg() < 1 || apr.NArg() > 2 {
+		return errhand.BuildDError("Must specify a table name and optionally a column name.").SetPrintUsage().Build()
+	}
+
+	if !apr.ContainsAny(sourceURLParam, licenseParam, extractedParam) {
+		return errhand.BuildDError("Must specify at least one of --%s, --%s or --%s.", sourceURLParam, licenseParam, extractedParam).SetPrintUsage().Build()
+	}
+
+	tblName := apr.Arg(0)
+	tbl, ok, err := root.GetTable(ctx, tblName)
+
+	if err != nil {
+		return errhand.BuildDError("error: could not read tables from database").AddCause(err).Build()
+	} else if !ok {
+		return errhand.BuildDError("%s not found", tblName).Build()
+	}
+
+	colName := ""
+	if apr.NArg() == 2 {
+		colName = apr.Arg(1)
+		sch, err := tbl.GetSchema(ctx)
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to read schema for '%s'", tblName).AddCause(err).Build()
+		}
+
+		if _, ok := sch.GetAllCols().GetByName(colName); !ok {
+			return errhand.BuildDError("error: Column %s unknown", colName).Build()
+		}
+	}
+
+	provs, err := root.GetProvenance(ctx, tblName)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to read provenance for '%s'", tblName).AddCause(err).Build()
+	}
+
+	prov := doltdb.Provenance{TableName: tblName, ColumnName: colName}
+	for _, p := range provs {
+		if p.ColumnName == colName {
+			prov = p
+			break
+		}
+	}
+
+	if val, ok := apr.GetValue(sourceURLParam); ok {
+		prov.SourceURL = strings.TrimSpace(val)
+	}
+
+	if val, ok := apr.GetValue(licenseParam); ok {
+		prov.License = strings.TrimSpace(val)
+	}
+
+	if val, ok := apr.GetValue(extractedParam); ok {
+		prov.Extracted = strings.TrimSpace(val)
+	}
+
+	root, err = root.PutProvenance(ctx, prov)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to write provenance for '%s'", tblName).AddCause(err).Build()
+	}
+
+	return commands.UpdateWorkingWithVErr(dEnv, root)
+}
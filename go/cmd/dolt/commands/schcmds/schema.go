@@ -21,9 +21,14 @@ import (
 
 var Commands = cli.GenSubCommandHandler([]*cli.Command{
 	{Name: "add-column", Desc: "Adds a column to specified table's schema.", Func: AddColumn, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
+	{Name: "comment", Desc: "Sets the comment documenting a table or column.", Func: Comment, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
+	{Name: "diff", Desc: "Reports the schema changes between two commits.", Func: Diff, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
 	{Name: "drop-column", Desc: "Removes a column of the specified table.", Func: DropColumn, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
 	{Name: "export", Desc: "Exports a table's schema.", Func: Export, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
 	{Name: "import", Desc: "Creates a new table with an inferred schema.", Func: Import, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
+	{Name: "materialize", Desc: "Defines a materialized view.", Func: Materialize, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
+	{Name: "merge-strategy", Desc: "Sets how changes to a column are merged.", Func: MergeStrategy, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
+	{Name: "provenance", Desc: "Records where the data in a table or column came from.", Func: Provenance, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
 	{Name: "rename-column", Desc: "Renames a column of the specified table.", Func: RenameColumn, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
 	{Name: "show", Desc: "Shows the schema of one or more tables.", Func: Show, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
 })
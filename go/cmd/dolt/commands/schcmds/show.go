@@ -16,6 +16,8 @@ package schcmds
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/fatih/color"
 
@@ -113,7 +115,7 @@ func printSchemas(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env
 			if !ok {
 				notFound = append(notFound, tblName)
 			} else {
-				verr = printTblSchema(ctx, cmStr, tblName, tbl)
+				verr = printTblSchema(ctx, cmStr, tblName, tbl, root)
 				cli.Println()
 			}
 		}
@@ -126,7 +128,7 @@ func printSchemas(ctx context.Context, apr *argparser.ArgParseResults, dEnv *env
 	return verr
 }
 
-func printTblSchema(ctx context.Context, cmStr string, tblName string, tbl *doltdb.Table) errhand.VerboseError {
+func printTblSchema(ctx context.Context, cmStr string, tblName string, tbl *doltdb.Table, root *doltdb.RootValue) errhand.VerboseError {
 	cli.Println(bold.Sprint(tblName), "@", cmStr)
 	sch, err := tbl.GetSchema(ctx)
 
@@ -135,9 +137,62 @@ func printTblSchema(ctx context.Context, cmStr string, tblName string, tbl *dolt
 	}
 
 	cli.Println(sql.SchemaAsCreateStmt(tblName, sch))
+
+	if appendOnly, err := tbl.IsAppendOnly(); err != nil {
+		return errhand.BuildDError("unable to read table properties").AddCause(err).Build()
+	} else if appendOnly {
+		cli.Println("-- append-only")
+	}
+
+	err = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if col.TypeName != "" {
+			cli.Printf("-- custom type of %s: %s\n", sql.QuoteIdentifier(col.Name), col.TypeName)
+		}
+		if col.MergeStrategy != schema.MergeDefault {
+			cli.Printf("-- merge strategy of %s: %s\n", sql.QuoteIdentifier(col.Name), col.MergeStrategy)
+		}
+		return false, nil
+	})
+
+	if err != nil {
+		return errhand.BuildDError("unable to get schema").AddCause(err).Build()
+	}
+
+	provs, err := root.GetProvenance(ctx, tblName)
+
+	if err != nil {
+		return errhand.BuildDError("unable to get provenance").AddCause(err).Build()
+	}
+
+	for _, p := range provs {
+		cli.Println(provenanceComment(p))
+	}
+
 	return nil
 }
 
+// provenanceComment formats provenance as a SQL comment so that the output of schema show remains valid SQL.
+func provenanceComment(p doltdb.Provenance) string {
+	var fields []string
+	if p.SourceURL != "" {
+		fields = append(fields, "source_url: "+p.SourceURL)
+	}
+
+	if p.License != "" {
+		fields = append(fields, "license: "+p.License)
+	}
+
+	if p.Extracted != "" {
+		fields = append(fields, "extracted: "+p.Extracted)
+	}
+
+	if p.IsTableLevel() {
+		return "-- provenance: " + strings.Join(fields, ", ")
+	}
+
+	return fmt.Sprintf("-- provenance of `%s`: %s", p.ColumnName, strings.Join(fields, ", "))
+}
+
 func errToVerboseErr(oldName, newName string, err error) errhand.VerboseError {
 	switch err {
 	case schema.ErrColNameCollision:
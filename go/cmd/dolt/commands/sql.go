@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/abiosoft/readline"
@@ -36,6 +38,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	dsql "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql"
@@ -46,6 +49,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/nullprinter"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/tabular"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/osutil"
 	"github.com/liquidata-inc/dolt/go/store/types"
@@ -65,12 +69,27 @@ Reasonably well supported functionality:
 * SELECT statements, including most kinds of joins
 * CREATE TABLE statements
 * ALTER TABLE / DROP TABLE statements
+* CREATE VIEW / DROP VIEW statements. Views are stored in the dolt_schemas table, and are versioned like other tables
 * UPDATE and DELETE statements
 * Table and column aliases
 * Column functions, e.g. CONCAT
 * ORDER BY and LIMIT clauses
 * GROUP BY
 * Aggregate functions, e.g. SUM 
+* Reading a table as of a past commit, e.g. SELECT * FROM people AS OF 'HEAD~3'. The commit can be a commit hash, a
+  branch name or HEAD, followed by ancestor references such as ~3. A table can be read as of several commits in one
+  query by giving each an alias, e.g. to compare them in a self-join
+* Commit functions, which take commits given the same way: HASHOF('master'), COMMIT_DATE('HEAD~1'),
+  COMMIT_AUTHOR(commit), COMMIT_MESSAGE(commit), and ACTIVE_BRANCH() for the branch checked out
+* Transactions in the shell and in piped scripts. BEGIN (or START TRANSACTION) starts one, COMMIT merges the changes
+  made in the session into the working set, failing if they conflict with changes made to it by another process, and
+  ROLLBACK discards the changes made since BEGIN. A transaction left open when the session ends is rolled back
+* The session variables @@dolt_head and @@dolt_working, which hold the hashes of the head commit and of the working
+  root read by queries. Setting @@dolt_head to a commit, or @@dolt_working to a root, pins the session to it, making
+  its tables read-only until the variable is set to ''
+* NULL values, which follow standard three-valued logic in filters, IN, joins and aggregates. ORDER BY puts them
+  before all other values in ascending order and after them in descending order, unless the session variable
+  @@dolt_null_ordering is set to 'first' or 'last' to put them there in both
 
 Known limitations:
 * Some expressions in SELECT statements
@@ -80,14 +99,25 @@ Known limitations:
 * Column constraints besides NOT NULL
 * VARCHAR columns are unlimited length; FLOAT, INTEGER columns are 64 bit
 * Performance is very bad for many SELECT statements, especially JOINs
+
+With -q, the results can be written to a file with -o instead of printed. The format of the file is inferred from its
+extension, which must be one of .csv, .psv or .json.
+
+With --at, queries read the tables as of the commit given, which can be a commit hash, a branch name or HEAD, followed
+by ancestor references such as ~3, rather than the working set. The database is read-only, and statements that would
+write to it fail. System tables such as dolt_log still describe the branch checked out.
+
+` + AttachHelp + `
 `
 var sqlSynopsis = []string{
-	"",
-	"-q <query>",
+	"[--attach <name>=<location>] [--at <commit>]",
+	"[--attach <name>=<location>] [--at <commit>] -q <query> [-o <file>]",
 }
 
 const (
 	queryFlag  = "query"
+	attachFlag = "attach"
+	atFlag     = "at"
 	welcomeMsg = `# Welcome to the DoltSQL shell.
 # Statements must be terminated with ';'.
 # "exit" or "quit" (or Ctrl-D) to exit.`
@@ -96,6 +126,9 @@ const (
 func Sql(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
 	ap := argparser.NewArgParser()
 	ap.SupportsString(queryFlag, "q", "SQL query to run", "Runs a single query and exits")
+	ap.SupportsString(outputFlag, "o", "file", "Writes the results of the query given with -q to a file, in the format given by its extension")
+	ap.SupportsString(attachFlag, "", "name=location", "Attaches other dolt repositories as read-only databases, given as a comma separated list")
+	ap.SupportsString(atFlag, "", "commit", "Reads the tables as of the commit given, rather than the working set, and rejects writes")
 	help, usage := cli.HelpAndUsagePrinters(commandStr, sqlShortDesc, sqlLongDesc, sqlSynopsis, ap)
 
 	apr := cli.ParseArgs(ap, args, help)
@@ -106,21 +139,55 @@ func Sql(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEn
 		return HandleVErrAndExitCode(verr, usage)
 	}
 
-	origRoot := root
+	var attached []*dsqle.AttachedDatabase
+	if attachArg, ok := apr.GetValue(attachFlag); ok {
+		var err error
+		attached, err = AttachedDatabases(ctx, dEnv, attachArg)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+	}
+
+	var resultFile *mvdata.FileDataLocation
+	if outPath, ok := apr.GetValue(outputFlag); ok {
+		if !apr.Contains(queryFlag) {
+			return HandleVErrAndExitCode(errhand.BuildDError("--%s can only be used with --%s", outputFlag, queryFlag).SetPrintUsage().Build(), usage)
+		}
+
+		resultFile, verr = resultFileLocation(outPath)
+		if verr != nil {
+			return HandleVErrAndExitCode(verr, usage)
+		}
+	}
+
+	// newEngine returns an engine for the working set, in batch insert mode if batched is true, or for the commit given
+	// with --at, which is read-only
+	newEngine := func(batched bool) (*sqlEngine, error) {
+		db := dsqle.NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+		if spec, ok := apr.GetValue(atFlag); ok {
+			var err error
+			db, err = dsqle.NewCommitDatabase(ctx, "dolt", dEnv.DoltDB, dEnv.RepoState, spec)
+			if err != nil {
+				return nil, err
+			}
+		} else if batched {
+			db = dsqle.NewBatchedDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+		}
+
+		return newSqlEngine(dEnv, db, attached)
+	}
 
 	// run a single command and exit
 	if query, ok := apr.GetValue(queryFlag); ok {
-		se, err := newSqlEngine(dEnv, dsqle.NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+		se, err := newEngine(false)
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 		}
+		se.resultFile = resultFile
 		if err := processQuery(ctx, query, se); err != nil {
 			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
-		} else if se.sdb.Root() != origRoot {
-			return HandleVErrAndExitCode(UpdateWorkingWithVErr(dEnv, se.sdb.Root()), usage)
-		} else {
-			return 0
 		}
+		return HandleVErrAndExitCode(se.close(), usage)
 	}
 
 	// Run in either batch mode for piped input, or shell mode for interactive
@@ -128,7 +195,7 @@ func Sql(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEn
 	var se *sqlEngine
 	// Windows has a bug where STDIN can't be statted in some cases, see https://github.com/golang/go/issues/33570
 	if (err != nil && osutil.IsWindows) || (fi.Mode()&os.ModeCharDevice) == 0 {
-		se, err = newSqlEngine(dEnv, dsqle.NewBatchedDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+		se, err = newEngine(true)
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 		}
@@ -139,7 +206,7 @@ func Sql(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEn
 	} else if err != nil {
 		HandleVErrAndExitCode(errhand.BuildDError("Couldn't stat STDIN. This is a bug.").Build(), usage)
 	} else {
-		se, err = newSqlEngine(dEnv, dsqle.NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+		se, err = newEngine(false)
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 		}
@@ -149,12 +216,7 @@ func Sql(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEn
 		}
 	}
 
-	// If the SQL session wrote a new root value, update the working set with it
-	if se.sdb.Root() != origRoot {
-		return HandleVErrAndExitCode(UpdateWorkingWithVErr(dEnv, se.sdb.Root()), usage)
-	}
-
-	return 0
+	return HandleVErrAndExitCode(se.close(), usage)
 }
 
 // ScanStatements is a split function for a Scanner that returns each SQL statement in the input as a token. It doesn't
@@ -427,8 +489,14 @@ func prepend(s string, ss []string) []string {
 	return newSs
 }
 
-// Processes a single query. The Root of the sqlEngine will be updated if necessary.
+// Processes a single query. The Root of the sqlEngine will be updated if necessary. Receiving SIGINT while the query
+// runs cancels it.
 func processQuery(ctx context.Context, query string, se *sqlEngine) error {
+	ctx, stop := cancelOnInterrupt(ctx)
+	defer stop()
+
+	query = dsqle.RewriteAsOf(query)
+
 	sqlStatement, err := sqlparser.Parse(query)
 	if err == sqlparser.ErrEmpty {
 		// silently skip empty statements
@@ -437,11 +505,33 @@ func processQuery(ctx context.Context, query string, se *sqlEngine) error {
 		return fmt.Errorf("Error parsing SQL: %v.", err.Error())
 	}
 
+	if se.sdb.ReadOnly() && dsqle.IsWriteStatement(sqlStatement) {
+		return dsqle.ErrReadOnly
+	}
+
 	switch s := sqlStatement.(type) {
-	case *sqlparser.Select, *sqlparser.OtherRead, *sqlparser.Insert, *sqlparser.Update, *sqlparser.Show:
+	case *sqlparser.OtherRead:
+		sqlSch, rowIter, err := se.query(ctx, explainTreeFormat(query))
+		if err == nil {
+			err = se.printResults(ctx, sqlSch, rowIter)
+		}
+		return err
+	case *sqlparser.Insert:
+		sqlSch, rowIter, err := se.insert(ctx, query, s)
+		if err == nil {
+			err = se.printRowCounts(ctx, sqlSch, rowIter)
+		}
+		return err
+	case *sqlparser.Select, *sqlparser.Show:
+		sqlSch, rowIter, err := se.query(ctx, query)
+		if err == nil {
+			err = se.printResults(ctx, sqlSch, rowIter)
+		}
+		return err
+	case *sqlparser.Update:
 		sqlSch, rowIter, err := se.query(ctx, query)
 		if err == nil {
-			err = prettyPrintResults(ctx, se.ddb.ValueReadWriter().Format(), sqlSch, rowIter)
+			err = se.printRowCounts(ctx, sqlSch, rowIter)
 		}
 		return err
 	case *sqlparser.Delete:
@@ -451,7 +541,7 @@ func processQuery(ctx context.Context, query string, se *sqlEngine) error {
 		}
 		sqlSch, rowIter, err := se.query(ctx, query)
 		if err == nil {
-			err = prettyPrintResults(ctx, se.ddb.Format(), sqlSch, rowIter)
+			err = se.printRowCounts(ctx, sqlSch, rowIter)
 		}
 		return err
 	case *sqlparser.DDL:
@@ -460,11 +550,54 @@ func processQuery(ctx context.Context, query string, se *sqlEngine) error {
 			return fmt.Errorf("Error parsing DDL: %v.", err.Error())
 		}
 		return se.ddl(ctx, s, query)
+	case *sqlparser.Set:
+		_, rowIter, err := se.query(ctx, query)
+		if err == nil {
+			err = rowIter.Close()
+		}
+		return err
+	case *sqlparser.Begin:
+		return se.begin()
+	case *sqlparser.Commit:
+		return se.commit(ctx)
+	case *sqlparser.Rollback:
+		se.rollback()
+		return nil
 	default:
 		return fmt.Errorf("Unsupported SQL statement: '%v'.", query)
 	}
 }
 
+// cancelOnInterrupt returns a context which is canceled if the process receives SIGINT, so that a long-running query
+// can be aborted without exiting. The returned function must be called to stop listening for the signal.
+func cancelOnInterrupt(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+
+	go func() {
+		select {
+		case <-sigCh:
+			cli.PrintErrln(color.RedString("Received SIGINT. Canceling query."))
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(sigCh)
+		cancel()
+	}
+}
+
+var explainSelectRegex = regexp.MustCompile(`(?is)^\s*(explain|describe|desc)\s+(select\s.*)$`)
+
+// explainTreeFormat returns the query given with the tree format made explicit if it's an EXPLAIN of a select
+// statement that doesn't specify a format, which the engine requires. Other queries are returned unchanged.
+func explainTreeFormat(query string) string {
+	return explainSelectRegex.ReplaceAllString(query, "$1 format=tree $2")
+}
+
 type stats struct {
 	numRowsInserted  int
 	numRowsUpdated   int
@@ -479,6 +612,8 @@ const updateInterval = 500
 
 // Processes a single query in batch mode. The Root of the sqlEngine may or may not be changed.
 func processBatchQuery(ctx context.Context, query string, se *sqlEngine) error {
+	query = dsqle.RewriteAsOf(query)
+
 	sqlStatement, err := sqlparser.Parse(query)
 	if err == sqlparser.ErrEmpty {
 		// silently skip empty statements
@@ -487,9 +622,13 @@ func processBatchQuery(ctx context.Context, query string, se *sqlEngine) error {
 		return fmt.Errorf("Error parsing SQL: %v.", err.Error())
 	}
 
-	switch sqlStatement.(type) {
+	if se.sdb.ReadOnly() && dsqle.IsWriteStatement(sqlStatement) {
+		return dsqle.ErrReadOnly
+	}
+
+	switch s := sqlStatement.(type) {
 	case *sqlparser.Insert:
-		_, rowIter, err := se.query(ctx, query)
+		_, rowIter, err := se.insert(ctx, query, s)
 		if err != nil {
 			return fmt.Errorf("Error inserting rows: %v", err.Error())
 		}
@@ -535,6 +674,8 @@ func updateBatchInsertOutput() {
 
 // Updates the batch insert stats with the results of an insert operation.
 func mergeInsertResultIntoStats(rowIter sql.RowIter, s *stats) error {
+	defer rowIter.Close()
+
 	for {
 		row, err := rowIter.Next()
 		if err == io.EOF {
@@ -551,13 +692,40 @@ func mergeInsertResultIntoStats(rowIter sql.RowIter, s *stats) error {
 type sqlEngine struct {
 	sdb    *dsqle.Database
 	ddb    *doltdb.DoltDB
+	fs     filesys.Filesys
 	engine *sqle.Engine
+	dEnv   *env.DoltEnv
+	// sess is the session all of the engine's queries run in, so that session variables last until the engine exits
+	sess sql.Session
+
+	// workingRoot is the root of the working set the database's root was read from or last committed to
+	workingRoot *doltdb.RootValue
+	// tx is the transaction in progress, or nil if there isn't one
+	tx *dsqle.Transaction
+
+	// resultFile is the file query results are written to. When nil, results are printed to the CLI.
+	resultFile *mvdata.FileDataLocation
 }
 
 // sqlEngine packages up the context necessary to run sql queries against sqle.
-func newSqlEngine(dEnv *env.DoltEnv, db *dsqle.Database) (*sqlEngine, error) {
-	engine := sqle.NewDefault()
+func newSqlEngine(dEnv *env.DoltEnv, db *dsqle.Database, attached []*dsqle.AttachedDatabase) (*sqlEngine, error) {
+	collation, err := dsqle.ParseCollation(*dEnv.Config.GetStringOrDefault(env.SqlCollationKey, string(dsqle.CaseSensitive)))
+	if err != nil {
+		return nil, err
+	}
+
+	engine := dsqle.NewEngine(collation)
 	engine.AddDatabase(db)
+	for _, adb := range attached {
+		engine.AddDatabase(adb)
+	}
+	// Locks only last as long as the command, but scripts written for the server can still be run
+	engine.Catalog.MustRegister(dsqle.LockFunctions(dsqle.NewLockManager())...)
+	if !db.ReadOnly() {
+		engine.Catalog.MustRegister(dsqle.BranchFunctions(dEnv, db, nil)...)
+		engine.Catalog.MustRegister(dsqle.RemoteFunctions(dEnv, db, nil)...)
+	}
+	engine.Catalog.MustRegister(dsqle.CommitFunctions(db)...)
 
 	// SQL engine still gives buggy results with indexes on
 	if _, ok := os.LookupEnv(UseIndexesEnv); ok {
@@ -568,15 +736,84 @@ func newSqlEngine(dEnv *env.DoltEnv, db *dsqle.Database) (*sqlEngine, error) {
 		}
 	}
 
-	return &sqlEngine{db, dEnv.DoltDB, engine}, nil
+	return &sqlEngine{sdb: db, ddb: dEnv.DoltDB, fs: dEnv.FS, engine: engine, dEnv: dEnv, sess: sql.NewBaseSession(), workingRoot: db.Root()}, nil
+}
+
+// Begins a transaction, snapshotting the root of the database so that the writes made until the transaction is
+// committed can be rolled back. A read-only database has nothing to commit, so no transaction is begun for it.
+func (se *sqlEngine) begin() error {
+	if se.tx != nil {
+		return dsqle.ErrTransactionInProgress
+	} else if se.sdb.ReadOnly() {
+		return nil
+	}
+
+	se.tx = dsqle.BeginTransaction(se.sdb, se.workingRoot)
+	return nil
+}
+
+// Commits the transaction in progress, if there is one, merging the changes made in the session into the working set
+// and writing it.
+func (se *sqlEngine) commit(ctx context.Context) error {
+	if se.tx == nil {
+		return nil
+	}
+
+	working, err := se.dEnv.WorkingRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	merged, err := se.tx.Commit(ctx, working)
+	if err != nil {
+		return err
+	}
+
+	if err := se.dEnv.UpdateWorkingRoot(ctx, merged); err != nil {
+		return err
+	}
+
+	se.workingRoot = merged
+	se.tx = nil
+	return nil
+}
+
+// Rolls back the transaction in progress, if there is one.
+func (se *sqlEngine) rollback() {
+	if se.tx != nil {
+		se.tx.Rollback()
+		se.tx = nil
+	}
+}
+
+// Ends the session, rolling back the transaction in progress and writing the working set if the session changed it,
+// which a session opened at a commit can't.
+func (se *sqlEngine) close() errhand.VerboseError {
+	if se.tx != nil {
+		se.rollback()
+		cli.PrintErrln(color.YellowString("Rolled back the transaction which wasn't committed."))
+	}
+
+	if !se.sdb.ReadOnly() && se.sdb.Root() != se.workingRoot {
+		return UpdateWorkingWithVErr(se.dEnv, se.sdb.Root())
+	}
+
+	return nil
 }
 
 // Execute a SQL statement and return values for printing.
 func (se *sqlEngine) query(ctx context.Context, query string) (sql.Schema, sql.RowIter, error) {
-	sqlCtx := sql.NewContext(ctx)
+	sqlCtx := sql.NewContext(ctx, sql.WithSession(se.sess), sql.WithQuery(query))
 	return se.engine.Query(sqlCtx, query)
 }
 
+// Executes an insert statement, including the INSERT IGNORE and ON DUPLICATE KEY UPDATE variants the engine can't
+// execute directly.
+func (se *sqlEngine) insert(ctx context.Context, query string, ins *sqlparser.Insert) (sql.Schema, sql.RowIter, error) {
+	sqlCtx := sql.NewContext(ctx, sql.WithSession(se.sess))
+	return dsqle.ExecuteInsert(sqlCtx, se.engine, se.sdb, query, ins)
+}
+
 // Executes a SQL show statement and prints the result to the CLI.
 func (se *sqlEngine) show(ctx context.Context, show *sqlparser.Show) error {
 	root := se.sdb.Root()
@@ -587,8 +824,141 @@ func (se *sqlEngine) show(ctx context.Context, show *sqlparser.Show) error {
 	return runPrintingPipeline(ctx, root.VRW().Format(), p, sch)
 }
 
-// Pretty prints the output of the new SQL engine
+// printResults prints the results of a query to the CLI, or writes them to the engine's result file if it has one.
+func (se *sqlEngine) printResults(ctx context.Context, sqlSch sql.Schema, rowIter sql.RowIter) error {
+	if se.resultFile != nil {
+		return writeResultsToFile(ctx, se.ddb.Format(), se.fs, *se.resultFile, sqlSch, rowIter)
+	}
+	return prettyPrintResults(ctx, se.ddb.Format(), sqlSch, rowIter)
+}
+
+// printRowCounts prints the result of an INSERT, UPDATE or DELETE statement like printResults does. When the result is
+// printed to the CLI, it's followed by a summary of the rows the statement affected like the one the mysql client
+// prints, including the number of rows an UPDATE matched and the warnings the statement raised.
+func (se *sqlEngine) printRowCounts(ctx context.Context, sqlSch sql.Schema, rowIter sql.RowIter) error {
+	rows, err := sql.RowIterToRows(rowIter)
+	if err != nil {
+		return err
+	}
+
+	if err := se.printResults(ctx, sqlSch, sql.RowsToRowIter(rows...)); err != nil {
+		return err
+	}
+
+	if se.resultFile == nil {
+		cli.Println(rowCountSummary(sqlSch, rows, se.sess.WarningCount()))
+	}
+
+	return nil
+}
+
+// rowCountSummary returns the summary of the result of an INSERT, UPDATE or DELETE statement that printRowCounts
+// prints. Update results have the number of rows matched followed by the number changed; the others have only the
+// number affected.
+func rowCountSummary(sqlSch sql.Schema, rows []sql.Row, warnings uint16) string {
+	var matched, affected int64
+	for _, row := range rows {
+		for i, col := range sqlSch {
+			n, ok := row[i].(int64)
+			if !ok {
+				continue
+			}
+
+			if col.Name == "matched" {
+				matched += n
+			} else {
+				affected += n
+			}
+		}
+	}
+
+	summary := "Query OK, " + quantity(affected, "row", "rows") + " affected"
+	if len(sqlSch) == 2 {
+		return fmt.Sprintf("%s\nRows matched: %d  Changed: %d  Warnings: %d", summary, matched, affected, warnings)
+	} else if warnings > 0 {
+		summary += ", " + quantity(int64(warnings), "warning", "warnings")
+	}
+
+	return summary
+}
+
+// quantity returns the count given followed by whichever of the nouns given agrees with it.
+func quantity(n int64, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// resultFileLocation returns the location of the file query results are written to, with its format inferred from the
+// extension of the path given. Only formats with a table writer that takes untyped rows are supported.
+func resultFileLocation(path string) (*mvdata.FileDataLocation, errhand.VerboseError) {
+	switch dataFmt := mvdata.DFFromString(filepath.Ext(path)); dataFmt {
+	case mvdata.CsvFile, mvdata.PsvFile, mvdata.JsonFile:
+		return &mvdata.FileDataLocation{Path: path, Format: dataFmt}, nil
+	default:
+		return nil, errhand.BuildDError("error: unable to determine the output format of '%s' from its extension. Supported extensions are .csv, .psv and .json", path).Build()
+	}
+}
+
+// writeResultsToFile writes the results of a query to the file given using the table writer for its format. Values are
+// written as strings, and NULLs are omitted. The row iterator given is closed before returning.
+func writeResultsToFile(ctx context.Context, nbf *types.NomsBinFormat, fs filesys.Filesys, dl mvdata.FileDataLocation, sqlSch sql.Schema, rowIter sql.RowIter) (err error) {
+	defer rowIter.Close()
+
+	doltSch, err := dsqle.SqlSchemaToDoltResultSchema(sqlSch)
+	if err != nil {
+		return err
+	}
+
+	untypedSch, err := untyped.UntypeUnkeySchema(doltSch)
+	if err != nil {
+		return err
+	}
+
+	wr, err := dl.NewCreatingWriter(ctx, &mvdata.MoveOptions{}, nil, fs, false, untypedSch, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		closeErr := wr.Close(ctx)
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	for {
+		sqlRow, err := rowIter.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error processing results: %v", err)
+		}
+
+		taggedVals := make(row.TaggedValues)
+		for i, col := range sqlRow {
+			if col != nil {
+				taggedVals[uint64(i)] = types.String(fmt.Sprintf("%v", col))
+			}
+		}
+
+		r, err := row.New(nbf, untypedSch, taggedVals)
+		if err != nil {
+			return err
+		}
+
+		if err = wr.WriteRow(ctx, r); err != nil {
+			return fmt.Errorf("error writing results to %s: %v", dl.Path, err)
+		}
+	}
+}
+
+// Pretty prints the output of the new SQL engine. Rows are streamed from the iterator given, which is closed before
+// returning.
 func prettyPrintResults(ctx context.Context, nbf *types.NomsBinFormat, sqlSch sql.Schema, rowIter sql.RowIter) error {
+	defer rowIter.Close()
+
 	var chanErr error
 	doltSch, err := dsqle.SqlSchemaToDoltResultSchema(sqlSch)
 	if err != nil {
@@ -753,6 +1123,7 @@ func (se *sqlEngine) checkThenDeleteAllRows(ctx context.Context, s *sqlparser.De
 						return false
 					}
 					_ = prettyPrintResults(ctx, root.VRW().Format(), sql.Schema{{Name: "updated", Type: sql.Uint64}}, printRowIter)
+					cli.Println("Query OK, " + quantity(int64(rowData.Len()), "row", "rows") + " affected")
 					se.sdb.SetRoot(newRoot)
 					return true
 				}
@@ -766,12 +1137,21 @@ func (se *sqlEngine) checkThenDeleteAllRows(ctx context.Context, s *sqlparser.De
 // the sqlEngine if necessary.
 func (se *sqlEngine) ddl(ctx context.Context, ddl *sqlparser.DDL, query string) error {
 	switch ddl.Action {
-	case sqlparser.CreateStr, sqlparser.DropStr:
+	case sqlparser.CreateStr:
 		_, ri, err := se.query(ctx, query)
 		if err == nil {
 			ri.Close()
 		}
 		return err
+	case sqlparser.DropStr:
+		if len(ddl.FromViews) > 0 {
+			_, ri, err := se.query(ctx, query)
+			if err == nil {
+				ri.Close()
+			}
+			return err
+		}
+		return dsqle.ExecuteDropTable(sql.NewContext(ctx, sql.WithSession(se.sess)), se.sdb, ddl)
 	case sqlparser.AlterStr, sqlparser.RenameStr:
 		newRoot, err := dsql.ExecuteAlter(ctx, se.ddb, se.sdb.Root(), ddl, query)
 		if err != nil {
@@ -780,7 +1160,7 @@ func (se *sqlEngine) ddl(ctx context.Context, ddl *sqlparser.DDL, query string)
 		se.sdb.SetRoot(newRoot)
 		return nil
 	case sqlparser.TruncateStr:
-		return fmt.Errorf("Unhandled DDL action %v in query %v", ddl.Action, query)
+		return dsqle.ExecuteTruncateTable(sql.NewContext(ctx, sql.WithSession(se.sess)), se.sdb, ddl)
 	default:
 		return fmt.Errorf("Unhandled DDL action %v in query %v", ddl.Action, query)
 	}
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/src-d/go-mysql-server/sql"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
@@ -101,6 +102,39 @@ func TestSqlShow(t *testing.T) {
 	}
 }
 
+// Smoke tests, values are printed to console
+func TestSqlExplain(t *testing.T) {
+	tests := []struct {
+		query       string
+		expectedRes int
+	}{
+		{"explain select * from people where age = 32", 0},
+		{"EXPLAIN SELECT name FROM people WHERE age > 24 ORDER BY name", 0},
+		{"desc select * from people", 0},
+		{"explain format=tree select * from people", 0},
+		{"explain select * from doesnt_exist", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			dEnv := createEnvWithSeedData(t)
+
+			args := []string{"-q", test.query}
+
+			commandStr := "dolt sql"
+			result := Sql(context.TODO(), commandStr, args, dEnv)
+			assert.Equal(t, test.expectedRes, result)
+		})
+	}
+}
+
+func TestExplainTreeFormat(t *testing.T) {
+	assert.Equal(t, "explain format=tree select * from t", explainTreeFormat("explain select * from t"))
+	assert.Equal(t, "DESC format=tree SELECT a\nFROM t", explainTreeFormat("DESC SELECT a\nFROM t"))
+	assert.Equal(t, "explain format=tree select 1", explainTreeFormat("explain format=tree select 1"))
+	assert.Equal(t, "describe people", explainTreeFormat("describe people"))
+}
+
 // Tests of the create table SQL command, mostly a smoke test for errors in the command line handler. Most tests of
 // create table SQL command are in the sql package.
 func TestCreateTable(t *testing.T) {
@@ -402,14 +436,14 @@ func TestUpdate(t *testing.T) {
 			query:       `update people set name = null where id ='00000000-0000-0000-0000-000000000000'`,
 			expectedRes: 1,
 		},
-		//{
-		//	name:  "on duplicate update",
-		//	query: `insert into people (id, name, age, is_married) values
-		//		('00000000-0000-0000-0000-000000000000', 'Bill Billerson', 99, true)
-		//		ON DUPLICATE KEY UPDATE age=99`,
-		//	expectedIds: []uuid.UUID{uuid.MustParse("00000000-0000-0000-0000-000000000000")},
-		//	expectedAges: []uint{99},
-		//},
+		{
+			name: "on duplicate update",
+			query: `insert into people (id, name, age, is_married) values
+				('00000000-0000-0000-0000-000000000000', 'Bill Billerson', 99, true)
+				ON DUPLICATE KEY UPDATE age=99`,
+			expectedIds:  []uuid.UUID{uuid.MustParse("00000000-0000-0000-0000-000000000000")},
+			expectedAges: []uint{99},
+		},
 	}
 
 	for _, test := range tests {
@@ -523,6 +557,31 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestRowCountSummary(t *testing.T) {
+	affectedSch := sql.Schema{{Name: "updated", Type: sql.Int64}}
+	updateSch := sql.Schema{{Name: "matched", Type: sql.Int64}, {Name: "updated", Type: sql.Int64}}
+
+	tests := []struct {
+		name     string
+		sch      sql.Schema
+		rows     []sql.Row
+		warnings uint16
+		expected string
+	}{
+		{"one row", affectedSch, []sql.Row{{int64(1)}}, 0, "Query OK, 1 row affected"},
+		{"no rows", affectedSch, []sql.Row{{int64(0)}}, 0, "Query OK, 0 rows affected"},
+		{"warnings", affectedSch, []sql.Row{{int64(2)}}, 1, "Query OK, 2 rows affected, 1 warning"},
+		{"update", updateSch, []sql.Row{{int64(3), int64(2)}}, 0, "Query OK, 2 rows affected\nRows matched: 3  Changed: 2  Warnings: 0"},
+		{"update nothing", updateSch, []sql.Row{{int64(0), int64(0)}}, 2, "Query OK, 0 rows affected\nRows matched: 0  Changed: 0  Warnings: 2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, rowCountSummary(test.sch, test.rows, test.warnings))
+		})
+	}
+}
+
 func createEnvWithSeedData(t *testing.T) *env.DoltEnv {
 	dEnv := dtestutils.CreateTestEnv()
 	imt, sch := dtestutils.CreateTestDataTable(true)
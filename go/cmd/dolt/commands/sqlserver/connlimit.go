@@ -0,0 +1,103 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vitess.io/vitess/go/mysql"
+)
+
+// The error that connections over the limit are sent, as by MySQL.
+const (
+	tooManyConnectionsState = "08004"
+	tooManyConnectionsMsg   = "Too many connections"
+)
+
+// rejectTimeout is how long writing the error to a connection over the limit may take.
+const rejectTimeout = 5 * time.Second
+
+// connLimitListener is a net.Listener that accepts at most limit connections at once. Connections accepted while the
+// limit is reached are sent a Too many connections error in place of the server's handshake, as MySQL does, and closed
+// without being returned from Accept.
+type connLimitListener struct {
+	net.Listener
+	limit int32
+	open  int32
+}
+
+func newConnLimitListener(l net.Listener, limit int) *connLimitListener {
+	return &connLimitListener{Listener: l, limit: int32(limit)}
+}
+
+// Accept implements net.Listener
+func (l *connLimitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		if atomic.AddInt32(&l.open, 1) > l.limit {
+			atomic.AddInt32(&l.open, -1)
+			go rejectConn(conn)
+			continue
+		}
+
+		return &limitedConn{Conn: conn, l: l}, nil
+	}
+}
+
+// rejectConn sends the connection given a Too many connections error and closes it.
+func rejectConn(conn net.Conn) {
+	defer conn.Close()
+
+	_ = conn.SetWriteDeadline(time.Now().Add(rejectTimeout))
+	_, _ = conn.Write(errorPacket(mysql.ERConCount, tooManyConnectionsState, tooManyConnectionsMsg))
+}
+
+// errorPacket returns the first packet of a connection holding the error given.
+func errorPacket(code uint16, state string, msg string) []byte {
+	payload := make([]byte, 0, 9+len(msg))
+	payload = append(payload, 0xff)
+	payload = append(payload, byte(code), byte(code>>8))
+	payload = append(payload, '#')
+	payload = append(payload, state...)
+	payload = append(payload, msg...)
+
+	packet := make([]byte, 4, 4+len(payload))
+	binary.LittleEndian.PutUint32(packet, uint32(len(payload)))
+	packet[3] = 0 // The sequence number
+	return append(packet, payload...)
+}
+
+// limitedConn is a connection counted towards the limit of its listener until it's closed.
+type limitedConn struct {
+	net.Conn
+	l    *connLimitListener
+	once sync.Once
+}
+
+// Close implements net.Conn
+func (c *limitedConn) Close() error {
+	c.once.Do(func() {
+		atomic.AddInt32(&c.l.open, -1)
+	})
+	return c.Conn.Close()
+}
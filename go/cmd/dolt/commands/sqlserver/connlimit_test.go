@@ -0,0 +1,66 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"vitess.io/vitess/go/mysql"
+)
+
+func TestConnLimitListener(t *testing.T) {
+	netL, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	l := newConnLimitListener(netL, 1)
+	defer l.Close()
+
+	accepted := make(chan net.Conn)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				close(accepted)
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	first, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer first.Close()
+	firstAccepted := <-accepted
+
+	// a connection over the limit is sent the error and closed without being accepted
+	second, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer second.Close()
+	data, err := ioutil.ReadAll(second)
+	require.NoError(t, err)
+	assert.Equal(t, errorPacket(mysql.ERConCount, tooManyConnectionsState, tooManyConnectionsMsg), data)
+	assert.Equal(t, []byte{0xff, 0x10, 0x04, '#', '0', '8', '0', '0', '4'}, data[4:13])
+
+	// closing an accepted connection makes room for another
+	require.NoError(t, firstAccepted.Close())
+	third, err := net.Dial("tcp", l.Addr().String())
+	require.NoError(t, err)
+	defer third.Close()
+	thirdAccepted := <-accepted
+	require.NoError(t, thirdAccepted.Close())
+}
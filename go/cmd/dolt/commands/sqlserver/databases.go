@@ -0,0 +1,193 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	sqle "github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/auth"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+// servedDatabase is a repository served as a database of the server, along with the engine that runs the queries of
+// the connections using it.
+type servedDatabase struct {
+	name string
+	dEnv *env.DoltEnv
+
+	db        *dsqle.Database
+	watcher   *dsqle.RootWatcher
+	committer *dsqle.Committer // nil if writes aren't committed
+	engine    *sqle.Engine
+}
+
+// loadMultiDBDir returns a database for each dolt repository in the subdirectories of the directory given, named
+// after its subdirectory. Subdirectories that aren't repositories are skipped.
+func loadMultiDBDir(ctx context.Context, dir string) ([]*servedDatabase, error) {
+	var subdirs []string
+	err := filesys.LocalFS.Iter(dir, false, func(path string, size int64, isDir bool) (stop bool) {
+		if isDir {
+			subdirs = append(subdirs, path)
+		}
+		return false
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the databases directory %s: %v", dir, err)
+	}
+
+	var served []*servedDatabase
+	names := make(map[string]string)
+	for _, subdir := range subdirs {
+		fs, err := filesys.LocalFilesysWithWorkingDir(subdir)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists, isDir := fs.Exists(dbfactory.DoltDataDir); !exists || !isDir {
+			continue
+		}
+
+		urlStr, err := dataDirURL(fs)
+		if err != nil {
+			return nil, err
+		}
+
+		dEnv := env.Load(ctx, env.GetCurrentUserHomeDir, fs, urlStr)
+		if dEnv.RSLoadErr != nil {
+			return nil, fmt.Errorf("unable to load the repository in %s: %v", subdir, dEnv.RSLoadErr)
+		} else if dEnv.DBLoadError != nil {
+			return nil, fmt.Errorf("unable to load the repository in %s: %v", subdir, dEnv.DBLoadError)
+		}
+
+		name := filepath.Base(subdir)
+		if other, ok := names[strings.ToLower(name)]; ok {
+			return nil, fmt.Errorf("the repositories in %s and %s would both be served as database %s, as database names aren't case sensitive", other, subdir, name)
+		}
+		names[strings.ToLower(name)] = subdir
+
+		served = append(served, &servedDatabase{name: name, dEnv: dEnv})
+	}
+
+	if len(served) == 0 {
+		return nil, fmt.Errorf("no dolt repositories were found in the databases directory %s", dir)
+	}
+
+	return served, nil
+}
+
+// dataDirURL returns the url of the data of the repository in the working directory of the filesystem given. It's
+// absolute, as the working directory of the filesystem isn't that of the process.
+func dataDirURL(fs filesys.Filesys) (string, error) {
+	dataDir, err := fs.Abs(dbfactory.DoltDataDir)
+	if err != nil {
+		return "", err
+	}
+
+	urlPath := filepath.ToSlash(dataDir)
+	if !strings.HasPrefix(urlPath, "/") {
+		urlPath = "/" + urlPath
+	}
+
+	return dbfactory.FileScheme + "://" + urlPath, nil
+}
+
+// open opens the database's repository for serving, at the commit the config given names if it names one and its
+// working set if it doesn't.
+func (sdb *servedDatabase) open(ctx context.Context, serverConfig *ServerConfig) error {
+	var err error
+	if serverConfig.AtCommit != "" {
+		sdb.db, err = dsqle.NewCommitDatabase(ctx, sdb.name, sdb.dEnv.DoltDB, sdb.dEnv.RepoState, serverConfig.AtCommit)
+		if err != nil {
+			return fmt.Errorf("%s: %v", sdb.name, err)
+		}
+	} else {
+		root, err := sdb.dEnv.WorkingRoot(ctx)
+		if err != nil {
+			return err
+		}
+		sdb.db = dsqle.NewDatabase(sdb.name, root, sdb.dEnv.DoltDB, sdb.dEnv.RepoState)
+	}
+
+	sdb.watcher, err = dsqle.NewRootWatcher(ctx, sdb.dEnv)
+	if err != nil {
+		return err
+	}
+	// A database opened at a commit is a snapshot, which changes to the working set mustn't replace
+	if !sdb.db.ReadOnly() {
+		sdb.watcher.Watch(sdb.db)
+	}
+	if serverConfig.PollInterval > 0 {
+		sdb.watcher.StartPolling(serverConfig.PollInterval)
+	}
+
+	if serverConfig.Commits.Policy != dsqle.CommitNone && !serverConfig.ReadOnly {
+		sdb.committer = dsqle.NewCommitter(sdb.dEnv, sdb.db, serverConfig.Commits)
+	}
+
+	return nil
+}
+
+// newEngine creates the engine that runs the queries of the connections using the database. Its current database is
+// this one, and the other databases served and the databases attached can be queried by qualifying their tables with
+// their names. The functions that work with a repository, such as DOLT_COMMIT and DOLT_CHECKOUT, work with this
+// database's repository. The engine checks the permissions of users with the auth given.
+func (sdb *servedDatabase) newEngine(serverConfig *ServerConfig, userAuth auth.Auth, locks *dsqle.LockManager, served []*servedDatabase, attached []*dsqle.AttachedDatabase) {
+	sdb.engine = dsqle.NewEngine(serverConfig.Collation)
+	sdb.engine.Auth = userAuth
+	sdb.engine.Analyzer.Parallelism = serverConfig.Parallelism
+	sdb.engine.AddDatabase(sdb.db)
+	for _, other := range served {
+		if other != sdb {
+			sdb.engine.AddDatabase(other.db)
+		}
+	}
+	for _, adb := range attached {
+		sdb.engine.AddDatabase(adb)
+	}
+
+	if sdb.committer != nil {
+		sdb.engine.Catalog.MustRegister(dsqle.DoltCommitFunction(sdb.committer, userAuth))
+	}
+	if !serverConfig.ReadOnly {
+		sdb.engine.Catalog.MustRegister(dsqle.BranchFunctions(sdb.dEnv, sdb.db, userAuth)...)
+		sdb.engine.Catalog.MustRegister(dsqle.RemoteFunctions(sdb.dEnv, sdb.db, userAuth)...)
+	}
+	sdb.engine.Catalog.MustRegister(dsqle.LockFunctions(locks)...)
+	sdb.engine.Catalog.MustRegister(dsqle.CommitFunctions(sdb.db)...)
+}
+
+// close commits the writes the database's commit policy hasn't committed yet, and stops polling its repository for
+// changes. It can be called whether or not the database was opened.
+func (sdb *servedDatabase) close(ctx context.Context) error {
+	var err error
+	if sdb.committer != nil {
+		err = sdb.committer.Close(ctx)
+	}
+
+	if sdb.watcher != nil {
+		sdb.watcher.Close()
+	}
+
+	return err
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/auth"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestLoadMultiDBDir(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "multi_db_dir")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = loadMultiDBDir(ctx, dir)
+	assert.Error(t, err, "a directory without repositories")
+
+	createRepo(t, filepath.Join(dir, "alpha"))
+	createRepo(t, filepath.Join(dir, "beta"))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "notrepo"), os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "README"), []byte("databases"), os.ModePerm))
+
+	served, err := loadMultiDBDir(ctx, dir)
+	require.NoError(t, err)
+	var names []string
+	for _, sdb := range served {
+		names = append(names, sdb.name)
+	}
+	assert.ElementsMatch(t, []string{"alpha", "beta"}, names)
+
+	// each database's engine has it as its current database, and the others qualified by their names
+	serverConfig := DefaultServerConfig()
+	locks := dsqle.NewLockManager()
+	for _, sdb := range served {
+		require.NoError(t, sdb.open(ctx, serverConfig))
+		defer sdb.close(ctx)
+	}
+	for _, sdb := range served {
+		sdb.newEngine(serverConfig, new(auth.None), locks, served, nil)
+		_, iter, err := sdb.engine.Query(sql.NewEmptyContext(), "select database()")
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(iter)
+		require.NoError(t, err)
+		assert.Equal(t, []sql.Row{{sdb.name}}, rows)
+		for _, other := range served {
+			_, err := sdb.engine.Catalog.Database(other.name)
+			assert.NoError(t, err)
+		}
+	}
+
+	createRepo(t, filepath.Join(dir, "Alpha"))
+	_, err = loadMultiDBDir(ctx, dir)
+	assert.Error(t, err, "database names differing only in case")
+}
+
+// createRepo initializes a repository in the directory given, creating it.
+func createRepo(t *testing.T, dir string) {
+	require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	fs, err := filesys.LocalFilesysWithWorkingDir(dir)
+	require.NoError(t, err)
+	urlStr, err := dataDirURL(fs)
+	require.NoError(t, err)
+
+	dEnv := env.Load(context.Background(), env.GetCurrentUserHomeDir, fs, urlStr)
+	require.NoError(t, dEnv.InitRepo(context.Background(), types.Format_7_18, "Bill Billerson", "bill@billerson.com"))
+}
@@ -0,0 +1,299 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+	"github.com/liquidata-inc/dolt/go/store/metrics"
+	"github.com/liquidata-inc/dolt/go/store/nbs"
+)
+
+// metricsHandler serves the metrics of the branches served by the databases of the server at /metrics, in the
+// Prometheus text format, so that operators can alert on stale data being served:
+//
+//	dolt_head_commit_age_seconds  the time since the head commit of the branch was made.
+//	dolt_working_set_dirty        1 if the data served differs from the head commit of the branch, as it does when
+//	                              writes haven't been committed, and 0 if it doesn't.
+//	dolt_replication_lag_seconds  how far the head commit of the branch trails the head of its upstream branch as of
+//	                              the last fetch, or 0 if it doesn't. Only reported for branches with an upstream,
+//	                              which is set with dolt push --set-upstream.
+//
+// Each sample is labeled with the name of its database and branch. The queries run by the server are counted and timed
+// as described by queryMetrics, and for databases stored in a NomsBlockStore, its statistics are served as well:
+//
+//	dolt_nbs_chunks                          the number of chunks stored.
+//	dolt_nbs_<operation>_latency_seconds     a summary of the time taken by get, has, put, commit and persist
+//	                                         operations, and reads and writes of the manifest.
+//	dolt_nbs_chunks_read_total               the number of chunks read.
+//	dolt_nbs_chunks_persisted_total          the number of chunks written to table files.
+//	dolt_nbs_bytes_persisted_total           the number of bytes written to table files.
+//
+// These are labeled with the name of their database.
+type metricsHandler struct {
+	databases []metricsDatabase
+	queries   *queryMetrics
+	now       func() time.Time
+}
+
+// metricsDatabase is a database whose metrics are served, which serves the branch of the databases watching its
+// root watcher.
+type metricsDatabase struct {
+	name    string
+	ddb     *doltdb.DoltDB
+	watcher *dsqle.RootWatcher
+}
+
+// metricSample is a sample of a gauge or counter, with its labels in the Prometheus text format.
+type metricSample struct {
+	labels string
+	val    float64
+}
+
+// summarySample is a sample of a summary without quantiles, with its labels in the Prometheus text format.
+type summarySample struct {
+	labels string
+	sum    float64
+	count  uint64
+}
+
+// newMetricsServer returns a server of the metrics of the databases and queries given at the address given.
+func newMetricsServer(addr string, databases []metricsDatabase, queries *queryMetrics) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", &metricsHandler{databases, queries, time.Now})
+	return &http.Server{Addr: addr, Handler: mux}
+}
+
+// ServeHTTP implements http.Handler
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	buf := &bytes.Buffer{}
+	if err := h.write(r.Context(), buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// write writes the current values of the metrics of every database to the buffer given.
+func (h *metricsHandler) write(ctx context.Context, buf *bytes.Buffer) error {
+	var ages, dirties, lags []metricSample
+	for _, db := range h.databases {
+		age, dirty, lag, err := h.samples(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		ages = append(ages, age)
+		dirties = append(dirties, dirty)
+		lags = append(lags, lag...)
+	}
+
+	writeMetric(buf, "dolt_head_commit_age_seconds", "gauge", "The time since the head commit of the branch served was made.", ages)
+	writeMetric(buf, "dolt_working_set_dirty", "gauge", "Whether the data served differs from the head commit of its branch.", dirties)
+	writeMetric(buf, "dolt_replication_lag_seconds", "gauge",
+		"How far the head commit of the branch served trails the head of its upstream branch as of the last fetch.", lags)
+
+	if h.queries != nil {
+		h.queries.write(buf)
+	}
+
+	return h.writeStoreStats(buf)
+}
+
+// storeLatencies are the latencies of the NomsBlockStore statistics that are served, by the operation they time.
+var storeLatencies = []struct {
+	operation string
+	latency   func(stats nbs.Stats) metrics.Histogram
+}{
+	{"get", func(stats nbs.Stats) metrics.Histogram { return stats.GetLatency }},
+	{"has", func(stats nbs.Stats) metrics.Histogram { return stats.HasLatency }},
+	{"put", func(stats nbs.Stats) metrics.Histogram { return stats.PutLatency }},
+	{"commit", func(stats nbs.Stats) metrics.Histogram { return stats.CommitLatency }},
+	{"persist", func(stats nbs.Stats) metrics.Histogram { return stats.PersistLatency }},
+	{"read_manifest", func(stats nbs.Stats) metrics.Histogram { return stats.ReadManifestLatency }},
+	{"write_manifest", func(stats nbs.Stats) metrics.Histogram { return stats.WriteManifestLatency }},
+}
+
+// writeStoreStats writes the statistics of the NomsBlockStore of each database stored in one to the buffer given.
+func (h *metricsHandler) writeStoreStats(buf *bytes.Buffer) error {
+	var names []string
+	var stats []nbs.Stats
+	var chunks, chunksRead, chunksPersisted, bytesPersisted []metricSample
+	for _, db := range h.databases {
+		dbStats, ok := db.ddb.StoreStats()
+		if !ok {
+			continue
+		}
+
+		count, err := db.ddb.ChunkCount()
+		if err != nil {
+			return err
+		}
+
+		labels := fmt.Sprintf(`database="%s"`, escapeLabel(db.name))
+		names = append(names, labels)
+		stats = append(stats, dbStats)
+		chunks = append(chunks, metricSample{labels, float64(count)})
+		chunksRead = append(chunksRead, metricSample{labels, float64(dbStats.ChunksPerGet.Sum())})
+		chunksPersisted = append(chunksPersisted, metricSample{labels, float64(dbStats.ChunksPerPersist.Sum())})
+		bytesPersisted = append(bytesPersisted, metricSample{labels, float64(dbStats.BytesPerPersist.Sum())})
+	}
+
+	writeMetric(buf, "dolt_nbs_chunks", "gauge", "The number of chunks stored.", chunks)
+	for _, sl := range storeLatencies {
+		samples := make([]summarySample, len(stats))
+		for i, dbStats := range stats {
+			latency := sl.latency(dbStats)
+			samples[i] = summarySample{names[i], time.Duration(latency.Sum()).Seconds(), latency.Samples()}
+		}
+
+		name := fmt.Sprintf("dolt_nbs_%s_latency_seconds", sl.operation)
+		writeSummary(buf, name, fmt.Sprintf("The time taken by %s operations of the chunk store.", strings.Replace(sl.operation, "_", " ", -1)), samples)
+	}
+	writeMetric(buf, "dolt_nbs_chunks_read_total", "counter", "The number of chunks read from the chunk store.", chunksRead)
+	writeMetric(buf, "dolt_nbs_chunks_persisted_total", "counter", "The number of chunks written to table files.", chunksPersisted)
+	writeMetric(buf, "dolt_nbs_bytes_persisted_total", "counter", "The number of bytes written to table files.", bytesPersisted)
+
+	return nil
+}
+
+// samples returns the current head commit age and working set dirtiness of the database given, and its replication
+// lag if its branch has an upstream that's been fetched.
+func (h *metricsHandler) samples(ctx context.Context, db metricsDatabase) (age, dirty metricSample, lag []metricSample, err error) {
+	root, rs := db.watcher.Served()
+	branch := rs.Head.Ref
+
+	head, err := resolveRef(ctx, db.ddb, branch)
+	if err != nil {
+		return age, dirty, nil, err
+	}
+
+	headTime, err := commitTime(head)
+	if err != nil {
+		return age, dirty, nil, err
+	}
+
+	headRoot, err := head.GetRootValue()
+	if err != nil {
+		return age, dirty, nil, err
+	}
+
+	headRootH, err := headRoot.HashOf()
+	if err != nil {
+		return age, dirty, nil, err
+	}
+
+	rootH, err := root.HashOf()
+	if err != nil {
+		return age, dirty, nil, err
+	}
+
+	labels := fmt.Sprintf(`database="%s",branch="%s"`, escapeLabel(db.name), escapeLabel(branch.GetPath()))
+	age = metricSample{labels, h.now().Sub(headTime).Seconds()}
+	dirty = metricSample{labels, 0}
+	if rootH != headRootH {
+		dirty.val = 1
+	}
+
+	if upstream, ok := rs.Branches[branch.GetPath()]; ok && upstream.Merge.Ref != nil {
+		tracking := ref.NewRemoteRef(upstream.Remote, upstream.Merge.Ref.GetPath())
+		if hasRef, err := db.ddb.HasRef(ctx, tracking); err != nil {
+			return age, dirty, nil, err
+		} else if hasRef {
+			upstreamHead, err := resolveRef(ctx, db.ddb, tracking)
+			if err != nil {
+				return age, dirty, nil, err
+			}
+
+			upstreamTime, err := commitTime(upstreamHead)
+			if err != nil {
+				return age, dirty, nil, err
+			}
+
+			secs := upstreamTime.Sub(headTime).Seconds()
+			if secs < 0 {
+				secs = 0
+			}
+
+			lag = append(lag, metricSample{fmt.Sprintf(`%s,remote="%s"`, labels, escapeLabel(upstream.Remote)), secs})
+		}
+	}
+
+	return age, dirty, lag, nil
+}
+
+// resolveRef returns the head commit of the ref given.
+func resolveRef(ctx context.Context, ddb *doltdb.DoltDB, dref ref.DoltRef) (*doltdb.Commit, error) {
+	cs, err := doltdb.NewCommitSpec("HEAD", dref.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return ddb.Resolve(ctx, cs)
+}
+
+// commitTime returns the time the commit given was made.
+func commitTime(cm *doltdb.Commit) (time.Time, error) {
+	meta, err := cm.GetCommitMeta()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return meta.Time(), nil
+}
+
+// writeMetric writes a gauge or counter, as given by typ, with the samples given to the buffer given. Nothing is written
+// if there are no samples.
+func writeMetric(buf *bytes.Buffer, name, typ, help string, samples []metricSample) {
+	if len(samples) == 0 {
+		return
+	}
+
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	for _, sample := range samples {
+		fmt.Fprintf(buf, "%s{%s} %g\n", name, sample.labels, sample.val)
+	}
+}
+
+// writeSummary writes a summary with the samples given to the buffer given. Nothing is written if there are no
+// samples.
+func writeSummary(buf *bytes.Buffer, name, help string, samples []summarySample) {
+	if len(samples) == 0 {
+		return
+	}
+
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s summary\n", name, help, name)
+	for _, sample := range samples {
+		fmt.Fprintf(buf, "%s_sum{%s} %g\n", name, sample.labels, sample.sum)
+		fmt.Fprintf(buf, "%s_count{%s} %d\n", name, sample.labels, sample.count)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value of the Prometheus text format.
+func escapeLabel(val string) string {
+	return labelEscaper.Replace(val)
+}
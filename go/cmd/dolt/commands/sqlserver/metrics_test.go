@@ -0,0 +1,85 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+)
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	dEnv := createEnvWithSeedData(t)
+	start := time.Date(2019, 12, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+	require.NoError(t, actions.CommitStaged(ctx, dEnv, "added people", start, false))
+
+	watcher, err := dsqle.NewRootWatcher(ctx, dEnv)
+	require.NoError(t, err)
+	h := &metricsHandler{[]metricsDatabase{{"dolt", dEnv.DoltDB, watcher}}, nil, func() time.Time { return start.Add(time.Hour) }}
+
+	assert.Equal(t, `# HELP dolt_head_commit_age_seconds The time since the head commit of the branch served was made.
+# TYPE dolt_head_commit_age_seconds gauge
+dolt_head_commit_age_seconds{database="dolt",branch="master"} 3600
+# HELP dolt_working_set_dirty Whether the data served differs from the head commit of its branch.
+# TYPE dolt_working_set_dirty gauge
+dolt_working_set_dirty{database="dolt",branch="master"} 0
+`, writeMetrics(t, h))
+
+	// an upstream branch that was fetched 10 minutes ahead of the branch served, which has a write that isn't committed
+	first := headCommit(t, dEnv)
+	require.NoError(t, actions.CommitStaged(ctx, dEnv, "nothing", start.Add(10*time.Minute), true))
+	remoteRef := ref.NewRemoteRef("origin", "master")
+	require.NoError(t, dEnv.DoltDB.FastForward(ctx, remoteRef, headCommit(t, dEnv)))
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("master"), first))
+	dEnv.RepoState.Branches = map[string]env.BranchConfig{
+		"master": {Merge: ref.MarshalableRef{Ref: ref.NewBranchRef("master")}, Remote: "origin"},
+	}
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	root, err = root.RemoveTables(ctx, "people")
+	require.NoError(t, err)
+	require.NoError(t, watcher.Publish(root))
+
+	metrics := writeMetrics(t, h)
+	assert.Contains(t, metrics, "dolt_working_set_dirty{database=\"dolt\",branch=\"master\"} 1\n")
+	assert.Contains(t, metrics, "dolt_replication_lag_seconds{database=\"dolt\",branch=\"master\",remote=\"origin\"} 600\n")
+}
+
+func writeMetrics(t *testing.T, h *metricsHandler) string {
+	buf := &bytes.Buffer{}
+	require.NoError(t, h.write(context.Background(), buf))
+	return buf.String()
+}
+
+func headCommit(t *testing.T, dEnv *env.DoltEnv) *doltdb.Commit {
+	cs, err := doltdb.NewCommitSpec("HEAD", dEnv.RepoState.Head.Ref.String())
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Resolve(context.Background(), cs)
+	require.NoError(t, err)
+	return cm
+}
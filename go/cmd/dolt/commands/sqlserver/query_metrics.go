@@ -0,0 +1,170 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// queryDurationBuckets are the upper bounds, in seconds, of the buckets of the query duration histogram.
+var queryDurationBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 60}
+
+// queryMetrics counts the queries run by the server and how long they take, by the database they're run against and
+// the kind of statement they are:
+//
+//	dolt_queries_total           a counter of the queries run, also labeled with whether they succeeded.
+//	dolt_query_duration_seconds  a histogram of the time taken to run queries.
+type queryMetrics struct {
+	mu        sync.Mutex
+	counts    map[queryLabels]uint64
+	durations map[queryLabels]*durationHistogram
+}
+
+// queryLabels are the labels of a query sample. The result is left empty for durations.
+type queryLabels struct {
+	database  string
+	statement string
+	result    string
+}
+
+// durationHistogram is a histogram of durations in seconds, with a count for each of the queryDurationBuckets.
+type durationHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+func newQueryMetrics() *queryMetrics {
+	return &queryMetrics{
+		counts:    make(map[queryLabels]uint64),
+		durations: make(map[queryLabels]*durationHistogram),
+	}
+}
+
+// observe records a query of the kind of statement given that was run against the database given, and took the
+// duration given. err is the error the query failed with, if it did.
+func (m *queryMetrics) observe(database, statement string, err error, d time.Duration) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counts[queryLabels{database, statement, result}]++
+
+	labels := queryLabels{database: database, statement: statement}
+	hist, ok := m.durations[labels]
+	if !ok {
+		hist = &durationHistogram{buckets: make([]uint64, len(queryDurationBuckets))}
+		m.durations[labels] = hist
+	}
+
+	secs := d.Seconds()
+	for i, bound := range queryDurationBuckets {
+		if secs <= bound {
+			hist.buckets[i]++
+		}
+	}
+	hist.count++
+	hist.sum += secs
+}
+
+// write writes the query metrics to the buffer given. Nothing is written before the first query.
+func (m *queryMetrics) write(buf *bytes.Buffer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.counts) == 0 {
+		return
+	}
+
+	countLabels := make([]queryLabels, 0, len(m.counts))
+	for labels := range m.counts {
+		countLabels = append(countLabels, labels)
+	}
+
+	counts := make([]metricSample, 0, len(m.counts))
+	for _, labels := range sortQueryLabels(countLabels) {
+		counts = append(counts, metricSample{labels.String(), float64(m.counts[labels])})
+	}
+	writeMetric(buf, "dolt_queries_total", "counter", "The number of queries run, by statement and whether they succeeded.", counts)
+
+	durationLabels := make([]queryLabels, 0, len(m.durations))
+	for labels := range m.durations {
+		durationLabels = append(durationLabels, labels)
+	}
+
+	name := "dolt_query_duration_seconds"
+	fmt.Fprintf(buf, "# HELP %s The time taken to run queries, by statement.\n# TYPE %s histogram\n", name, name)
+	for _, labels := range sortQueryLabels(durationLabels) {
+		hist := m.durations[labels]
+		for i, bound := range queryDurationBuckets {
+			fmt.Fprintf(buf, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, strconv.FormatFloat(bound, 'g', -1, 64), hist.buckets[i])
+		}
+		fmt.Fprintf(buf, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, hist.count)
+		fmt.Fprintf(buf, "%s_sum{%s} %g\n", name, labels, hist.sum)
+		fmt.Fprintf(buf, "%s_count{%s} %d\n", name, labels, hist.count)
+	}
+}
+
+// String returns the labels in the Prometheus text format.
+func (l queryLabels) String() string {
+	s := fmt.Sprintf(`database="%s",statement="%s"`, escapeLabel(l.database), l.statement)
+	if l.result != "" {
+		s += fmt.Sprintf(`,result="%s"`, l.result)
+	}
+	return s
+}
+
+// sortQueryLabels sorts the labels given and returns them, so that metrics are written in the same order each time.
+func sortQueryLabels(labels []queryLabels) []queryLabels {
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].String() < labels[j].String()
+	})
+
+	return labels
+}
+
+// statementKind returns the kind of statement given, for labeling query metrics. A nil statement is one that failed to
+// parse.
+func statementKind(stmt sqlparser.Statement) string {
+	switch stmt.(type) {
+	case nil:
+		return "invalid"
+	case *sqlparser.Select, *sqlparser.Union, *sqlparser.ParenSelect:
+		return "select"
+	case *sqlparser.Insert:
+		return "insert"
+	case *sqlparser.Update:
+		return "update"
+	case *sqlparser.Delete:
+		return "delete"
+	case *sqlparser.DDL, *sqlparser.DBDDL:
+		return "ddl"
+	case *sqlparser.Show:
+		return "show"
+	default:
+		return "other"
+	}
+}
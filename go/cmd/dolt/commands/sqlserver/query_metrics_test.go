@@ -0,0 +1,94 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestQueryMetrics(t *testing.T) {
+	m := newQueryMetrics()
+	buf := &bytes.Buffer{}
+	m.write(buf)
+	assert.Empty(t, buf.String())
+
+	m.observe("dolt", "select", nil, 2*time.Millisecond)
+	m.observe("dolt", "select", errors.New("failed"), 2*time.Second)
+	m.observe("dolt", "insert", nil, 20*time.Millisecond)
+	m.write(buf)
+
+	assert.Equal(t, `# HELP dolt_queries_total The number of queries run, by statement and whether they succeeded.
+# TYPE dolt_queries_total counter
+dolt_queries_total{database="dolt",statement="insert",result="ok"} 1
+dolt_queries_total{database="dolt",statement="select",result="error"} 1
+dolt_queries_total{database="dolt",statement="select",result="ok"} 1
+# HELP dolt_query_duration_seconds The time taken to run queries, by statement.
+# TYPE dolt_query_duration_seconds histogram
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="0.001"} 0
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="0.005"} 0
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="0.01"} 0
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="0.05"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="0.1"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="0.5"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="1"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="5"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="10"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="60"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="insert",le="+Inf"} 1
+dolt_query_duration_seconds_sum{database="dolt",statement="insert"} 0.02
+dolt_query_duration_seconds_count{database="dolt",statement="insert"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="0.001"} 0
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="0.005"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="0.01"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="0.05"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="0.1"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="0.5"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="1"} 1
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="5"} 2
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="10"} 2
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="60"} 2
+dolt_query_duration_seconds_bucket{database="dolt",statement="select",le="+Inf"} 2
+dolt_query_duration_seconds_sum{database="dolt",statement="select"} 2.002
+dolt_query_duration_seconds_count{database="dolt",statement="select"} 2
+`, buf.String())
+}
+
+func TestStatementKind(t *testing.T) {
+	tests := map[string]string{
+		"select * from people":                "select",
+		"select 1 union select 2":             "select",
+		"insert into people values (1)":       "insert",
+		"update people set age = 1":           "update",
+		"delete from people":                  "delete",
+		"create table t (pk int primary key)": "ddl",
+		"show tables":                         "show",
+		"set autocommit = 1":                  "other",
+	}
+
+	for query, expected := range tests {
+		stmt, err := sqlparser.Parse(query)
+		require.NoError(t, err)
+		assert.Equal(t, expected, statementKind(stmt), query)
+	}
+
+	assert.Equal(t, "invalid", statementKind(nil))
+}
@@ -18,22 +18,27 @@ import (
 	"context"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
-	sqle "github.com/src-d/go-mysql-server"
 	"github.com/src-d/go-mysql-server/auth"
 	"github.com/src-d/go-mysql-server/server"
 	"github.com/src-d/go-mysql-server/sql"
 	"vitess.io/vitess/go/mysql"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/sqlparser"
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
-	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
 )
 
-// Serve starts a MySQL-compatible server. Returns any errors that were encountered.
-func Serve(ctx context.Context, serverConfig *ServerConfig, rootValue *doltdb.RootValue, serverController *ServerController) (startError error, closeError error) {
+// Serve starts a MySQL-compatible server for the working set of the environment given, or for the repositories in the
+// config's directory of databases if it has one. Returns any errors that were encountered.
+func Serve(ctx context.Context, serverConfig *ServerConfig, dEnv *env.DoltEnv, serverController *ServerController) (startError error, closeError error) {
 	if serverConfig == nil {
 		cli.Println("No configuration given, using defaults")
 		serverConfig = DefaultServerConfig()
@@ -74,24 +79,105 @@ func Serve(ctx context.Context, serverConfig *ServerConfig, rootValue *doltdb.Ro
 		permissions = auth.ReadPerm
 	}
 
-	userAuth := auth.NewAudit(auth.NewNativeSingle(serverConfig.User, serverConfig.Password, permissions), auth.NewAuditLog(logrus.StandardLogger()))
-	sqlEngine := sqle.NewDefault()
-	sqlEngine.AddDatabase(dsqle.NewDatabase("dolt", rootValue, nil, nil))
+	var userAuth auth.Auth = auth.NewNativeSingle(serverConfig.User, serverConfig.Password, permissions)
+	if serverConfig.Users != nil {
+		userAuth = newUsersAuth(serverConfig.Users, serverConfig.ReadOnly)
+	}
+	userAuth = auth.NewAudit(userAuth, auth.NewAuditLog(logrus.StandardLogger()))
+
+	var served []*servedDatabase
+	if serverConfig.MultiDBDir != "" {
+		served, startError = loadMultiDBDir(ctx, serverConfig.MultiDBDir)
+	} else {
+		served = []*servedDatabase{{name: "dolt", dEnv: dEnv}}
+	}
+	if startError != nil {
+		cli.PrintErr(startError)
+		return
+	}
+
+	defer func() {
+		for _, sdb := range served {
+			if err := sdb.close(ctx); err != nil {
+				cli.PrintErr(err)
+				if closeError == nil {
+					closeError = err
+				}
+			}
+		}
+	}()
+
+	for _, sdb := range served {
+		if startError = sdb.open(ctx, serverConfig); startError != nil {
+			cli.PrintErr(startError)
+			return
+		}
+	}
+
+	var attached []*dsqle.AttachedDatabase
+	if serverConfig.Attach != "" {
+		attached, startError = commands.AttachedDatabases(ctx, dEnv, serverConfig.Attach)
+		if startError != nil {
+			cli.PrintErr(startError)
+			return
+		}
+	}
+
+	locks := dsqle.NewLockManager()
+	for _, sdb := range served {
+		sdb.newEngine(serverConfig, userAuth, locks, served, attached)
+	}
+
+	queries := newQueryMetrics()
+	if serverConfig.MetricsPort != 0 {
+		var metricsDBs []metricsDatabase
+		for _, sdb := range served {
+			metricsDBs = append(metricsDBs, metricsDatabase{sdb.name, sdb.dEnv.DoltDB, sdb.watcher})
+		}
+
+		metricsServer := newMetricsServer(net.JoinHostPort(serverConfig.Host, strconv.Itoa(serverConfig.MetricsPort)), metricsDBs, queries)
+		var l net.Listener
+		l, startError = net.Listen("tcp", metricsServer.Addr)
+		if startError != nil {
+			cli.PrintErr(startError)
+			return
+		}
+		go metricsServer.Serve(l)
+		defer metricsServer.Close()
+	}
 
 	hostPort := net.JoinHostPort(serverConfig.Host, strconv.Itoa(serverConfig.Port))
-	timeout := time.Second * time.Duration(serverConfig.Timeout)
-	mySQLServer, startError = server.NewServer(
+	readTimeout, writeTimeout := serverConfig.connTimeouts()
+	mySQLServer, startError = newServer(
 		server.Config{
 			Protocol:         "tcp",
 			Address:          hostPort,
 			Auth:             userAuth,
-			ConnReadTimeout:  timeout,
-			ConnWriteTimeout: timeout,
+			ConnReadTimeout:  readTimeout,
+			ConnWriteTimeout: writeTimeout,
 		},
-		sqlEngine,
 		func(conn *mysql.Conn, host string) sql.Session {
-			return sql.NewSession(host, conn.RemoteAddr().String(), conn.User, conn.ConnectionID)
+			sess := sql.NewSession(host, conn.RemoteAddr().String(), conn.User, conn.ConnectionID)
+			dsqle.SetIsolationDefault(sess)
+			limits := serverConfig.QueryLimits
+			if serverConfig.Users != nil {
+				limits = serverConfig.Users.QueryLimits(conn.User, limits)
+			}
+			if limits != (dsqle.QueryLimits{}) {
+				sess = dsqle.NewQueryLimitSession(sess, limits)
+			}
+			if serverConfig.Users != nil {
+				if filters := serverConfig.Users.RowFilters(conn.User); len(filters) > 0 {
+					sess = dsqle.NewRowFilterSession(sess, filters)
+				}
+			}
+			return sess
 		},
+		locks,
+		served,
+		serverConfig.ReadOnly,
+		serverConfig.MaxConns,
+		queries,
 	)
 	if startError != nil {
 		cli.PrintErr(startError)
@@ -105,3 +191,216 @@ func Serve(ctx context.Context, serverConfig *ServerConfig, rootValue *doltdb.Ro
 	}
 	return
 }
+
+// newServer returns a server like server.NewServer does for the engines of the databases given, except that each
+// connection runs its queries with the engine of the database it uses, that the advisory locks and the snapshot of
+// each connection are released when it closes, and that tables can be read AS OF a commit. If readOnly is true, every
+// statement that writes is rejected. If maxConns isn't 0, connections made while that many are open are turned away.
+// Each query is recorded in the query metrics given.
+func newServer(cfg server.Config, sb server.SessionBuilder, locks *dsqle.LockManager, served []*servedDatabase, readOnly bool, maxConns int, queries *queryMetrics) (*server.Server, error) {
+	// Connections keep the same session when they change the database they use, so every handler shares one manager
+	sm := server.NewSessionManager(sb, opentracing.NoopTracer{}, served[0].engine.Catalog.MemoryManager, cfg.Address)
+	handlers := make(map[string]*server.Handler, len(served))
+	for _, sdb := range served {
+		handlers[strings.ToLower(sdb.name)] = server.NewHandler(sdb.engine, sm, cfg.ConnReadTimeout)
+	}
+
+	// The listener gives the network connections it accepts to the handler of the default database, which checks them
+	// for clients that went away while their queries run
+	defaultHandler := handlers[strings.ToLower(served[0].name)]
+	l, err := server.NewListener(cfg.Protocol, cfg.Address, defaultHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	// Connections are turned away below the listener, which hands the handler each connection it accepts
+	if maxConns > 0 {
+		l.Listener = newConnLimitListener(l.Listener, maxConns)
+	}
+
+	h := &doltHandler{
+		handlers:       handlers,
+		defaultHandler: defaultHandler,
+		locks:          locks,
+		served:         served,
+		auth:           cfg.Auth,
+		readOnly:       readOnly,
+		queries:        queries,
+	}
+
+	vtListener, err := mysql.NewFromListener(l, cfg.Auth.Mysql(), h, cfg.ConnReadTimeout, cfg.ConnWriteTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &server.Server{Listener: vtListener}, nil
+}
+
+// doltHandler is a mysql.Handler that runs the queries of each connection with the handler of the database it uses,
+// which is the database it names when it connects or with USE, or the first database served if it doesn't name one.
+// It releases the advisory locks of each connection when it closes, and tells the root watchers and the databases that
+// its session ended. It also rewrites the AS OF clauses of queries, which the parser doesn't support, and ends INSERT,
+// UPDATE and DELETE statements with an OK packet holding their row counts rather than a result set, as MySQL does.
+//
+// Statements that write are rejected before they reach the engine when the server is read-only. Otherwise, the engine
+// checks that the user may write for DML statements, and the handler checks it for the DDL statements the engine
+// doesn't check, such as CREATE TABLE.
+type doltHandler struct {
+	handlers       map[string]*server.Handler // by lower case database name
+	defaultHandler *server.Handler
+	locks          *dsqle.LockManager
+	served         []*servedDatabase
+	auth           auth.Auth
+	readOnly       bool
+	queries        *queryMetrics
+}
+
+// errReadOnlyServer is returned for statements that write when the server is read-only.
+var errReadOnlyServer = mysql.NewSQLError(mysql.EROptionPreventsStatement, mysql.SSUnknownSQLState, "the server is read-only, so it can't execute this statement")
+
+// NewConnection implements mysql.Handler
+func (h *doltHandler) NewConnection(c *mysql.Conn) {
+	h.defaultHandler.NewConnection(c)
+	for _, handler := range h.handlers {
+		if handler != h.defaultHandler {
+			handler.NewConnection(c)
+		}
+	}
+}
+
+// ConnectionClosed implements mysql.Handler
+func (h *doltHandler) ConnectionClosed(c *mysql.Conn) {
+	for _, handler := range h.handlers {
+		handler.ConnectionClosed(c)
+	}
+
+	h.locks.UnlockAll(c.ConnectionID)
+	for _, sdb := range h.served {
+		sdb.watcher.EndSession(c.ConnectionID)
+		sdb.db.EndSession(c.ConnectionID)
+	}
+}
+
+// WarningCount implements mysql.Handler
+func (h *doltHandler) WarningCount(c *mysql.Conn) uint16 {
+	// The handlers share their sessions
+	return h.defaultHandler.WarningCount(c)
+}
+
+// ComQuery implements mysql.Handler
+func (h *doltHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	start := time.Now()
+	query = dsqle.RewriteAsOf(query)
+
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		stmt = nil
+	}
+
+	err = h.runQuery(c, query, stmt, callback)
+	h.queries.observe(h.databaseName(c), statementKind(stmt), err, time.Since(start))
+
+	return err
+}
+
+// runQuery runs the query given, whose parsed statement is stmt, or nil if it failed to parse.
+func (h *doltHandler) runQuery(c *mysql.Conn, query string, stmt sqlparser.Statement, callback func(*sqltypes.Result) error) error {
+	if stmt == nil {
+		// the engine reports the error
+		return h.defaultHandler.ComQuery(c, query, callback)
+	}
+
+	if use, ok := stmt.(*sqlparser.Use); ok {
+		if _, ok := h.handlers[strings.ToLower(use.DBName.String())]; ok {
+			c.SchemaName = use.DBName.String()
+			return callback(&sqltypes.Result{})
+		}
+	}
+
+	switch stmt.(type) {
+	case *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete:
+		if h.readOnly {
+			return errReadOnlyServer
+		}
+	case *sqlparser.DDL, *sqlparser.DBDDL:
+		if h.readOnly {
+			return errReadOnlyServer
+		}
+
+		sess := sql.NewSession("", c.RemoteAddr().String(), c.User, c.ConnectionID)
+		if err := h.auth.Allowed(sql.NewContext(context.Background(), sql.WithSession(sess)), auth.ReadPerm|auth.WritePerm); err != nil {
+			return err
+		}
+	}
+
+	handler, err := h.handler(c)
+	if err != nil {
+		return err
+	}
+
+	switch stmt.(type) {
+	case *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete:
+		foundRows := c.Capabilities&mysql.CapabilityClientFoundRows != 0
+		return handler.ComQuery(c, query, func(res *sqltypes.Result) error {
+			okRes, err := rowCountResult(res, foundRows)
+			if err != nil {
+				return err
+			}
+			return callback(okRes)
+		})
+	default:
+		return handler.ComQuery(c, query, callback)
+	}
+}
+
+// databaseName returns the name of the database the connection given uses, for labeling its query metrics.
+func (h *doltHandler) databaseName(c *mysql.Conn) string {
+	if c.SchemaName == "" || len(h.handlers) == 1 {
+		return h.served[0].name
+	}
+
+	return c.SchemaName
+}
+
+// handler returns the handler of the database the connection given uses. A server of a single database runs every
+// query with its handler, whatever database the connection names.
+func (h *doltHandler) handler(c *mysql.Conn) (*server.Handler, error) {
+	if c.SchemaName == "" || len(h.handlers) == 1 {
+		return h.defaultHandler, nil
+	}
+
+	handler, ok := h.handlers[strings.ToLower(c.SchemaName)]
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(c.SchemaName)
+	}
+
+	return handler, nil
+}
+
+// rowCountResult returns a result without fields, which is sent as an OK packet, whose affected rows are the row count
+// in the result of an INSERT, UPDATE or DELETE statement given. UPDATE results have the number of rows matched followed
+// by the number changed. Following MySQL, the rows affected by an UPDATE are the rows it changed, unless the client
+// asked for the rows found with CLIENT_FOUND_ROWS. Warnings, such as the rows skipped by INSERT IGNORE, are added to
+// the packet by the handler.
+func rowCountResult(res *sqltypes.Result, foundRows bool) (*sqltypes.Result, error) {
+	var affected uint64
+	for _, row := range res.Rows {
+		col := len(row) - 1
+		if foundRows {
+			col = 0
+		}
+
+		if col < 0 {
+			continue
+		}
+
+		n, err := sqltypes.ToUint64(row[col])
+		if err != nil {
+			return nil, err
+		}
+
+		affected += n
+	}
+
+	return &sqltypes.Result{RowsAffected: affected}, nil
+}
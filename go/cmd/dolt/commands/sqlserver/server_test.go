@@ -15,18 +15,22 @@
 package sqlserver
 
 import (
+	"io/ioutil"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/gocraft/dbr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
+	"vitess.io/vitess/go/sqltypes"
 
-	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
 )
@@ -68,6 +72,34 @@ func TestServerArgs(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestServerConfigFile(t *testing.T) {
+	dEnv := createEnvWithSeedData(t)
+	require.NoError(t, dEnv.FS.WriteFile("server.yaml", []byte(`
+user:
+  name: username
+  password: password
+listener:
+  port: 15201
+`)))
+
+	// the port given as a flag overrides the port in the file
+	serverController := CreateServerController()
+	go func() {
+		SqlServerImpl(context.Background(), "dolt sql-server", []string{"--config", "server.yaml", "-P", "15202"}, dEnv, serverController)
+	}()
+	err := serverController.WaitForStart()
+	require.NoError(t, err)
+	conn, err := dbr.Open("mysql", "username:password@tcp(localhost:15202)/dolt", nil)
+	require.NoError(t, err)
+	_, err = conn.NewSession(nil).Select("*").From("people").Load(&[]testPerson{})
+	assert.NoError(t, err)
+	err = conn.Close()
+	require.NoError(t, err)
+	serverController.StopServer()
+	err = serverController.WaitForClose()
+	assert.NoError(t, err)
+}
+
 func TestServerBadArgs(t *testing.T) {
 	env := createEnvWithSeedData(t)
 
@@ -79,6 +111,7 @@ func TestServerBadArgs(t *testing.T) {
 		{"-u", ""},
 		{"-t", "-1"},
 		{"-l", "everything"},
+		{"--max-rows-returned", "-1"},
 	}
 
 	for _, test := range tests {
@@ -99,8 +132,6 @@ func TestServerBadArgs(t *testing.T) {
 
 func TestServerGoodParams(t *testing.T) {
 	env := createEnvWithSeedData(t)
-	root, verr := commands.GetWorkingWithVErr(env)
-	require.NoError(t, verr)
 
 	tests := []*ServerConfig{
 		DefaultServerConfig(),
@@ -115,13 +146,14 @@ func TestServerGoodParams(t *testing.T) {
 		DefaultServerConfig().WithLogLevel(LogLevel_Info).WithPort(15408),
 		DefaultServerConfig().WithReadOnly(true).WithPort(15409),
 		DefaultServerConfig().WithUser("testusernamE").WithPassword("hunter2").WithTimeout(4).WithPort(15410),
+		DefaultServerConfig().WithQueryLimits(dsqle.QueryLimits{MaxRowsReturned: 10, MaxScanRows: 100, MaxJoinSize: 1000}).WithPort(15411),
 	}
 
 	for _, test := range tests {
 		t.Run(test.String(), func(t *testing.T) {
 			sc := CreateServerController()
 			go func(config *ServerConfig, sc *ServerController) {
-				_, _ = Serve(context.Background(), config, root, sc)
+				_, _ = Serve(context.Background(), config, env, sc)
 			}(test, sc)
 			err := sc.WaitForStart()
 			require.NoError(t, err)
@@ -138,14 +170,12 @@ func TestServerGoodParams(t *testing.T) {
 
 func TestServerSelect(t *testing.T) {
 	env := createEnvWithSeedData(t)
-	root, verr := commands.GetWorkingWithVErr(env)
-	require.NoError(t, verr)
 	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15300)
 
 	sc := CreateServerController()
 	defer sc.StopServer()
 	go func() {
-		_, _ = Serve(context.Background(), serverConfig, root, sc)
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
 	}()
 	err := sc.WaitForStart()
 	require.NoError(t, err)
@@ -188,6 +218,448 @@ func TestServerSelect(t *testing.T) {
 	}
 }
 
+func TestServerQueryLimits(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15301).
+		WithQueryLimits(dsqle.QueryLimits{MaxRowsReturned: 2, MaxJoinSize: 5, MaxExecutionTime: 1000})
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	conn, err := dbr.Open("mysql", serverConfig.ConnectionString(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	// Limits are per connection, so the statements below must all run on the same one
+	conn.SetMaxOpenConns(1)
+	sess := conn.NewSession(nil)
+
+	var peoples []testPerson
+	_, err = sess.Select("*").From("people").Where("age > 24").LoadContext(context.Background(), &peoples)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []testPerson{bill, john}, peoples)
+
+	_, err = sess.Select("*").From("people").LoadContext(context.Background(), &peoples)
+	assert.Error(t, err)
+
+	var count int
+	err = sess.SelectBySql("select count(*) from people a, people b").LoadOneContext(context.Background(), &count)
+	assert.Error(t, err)
+
+	err = sess.SelectBySql("select sleep(60)").LoadOneContext(context.Background(), &count)
+	assert.Error(t, err)
+
+	// a connection can lower its limits, but can't raise or remove them
+	_, err = sess.Exec("set max_rows_returned = 0")
+	assert.Error(t, err)
+	_, err = sess.Exec("set max_rows_returned = 3")
+	assert.Error(t, err)
+
+	_, err = sess.Exec("set max_rows_returned = 1")
+	require.NoError(t, err)
+	_, err = sess.Select("*").From("people").Where("age > 24").LoadContext(context.Background(), &peoples)
+	assert.Error(t, err)
+}
+
+func TestServerUserQueryLimits(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	users := &UsersConfig{
+		Users: []UserConfig{
+			{Name: "admin", Password: "secret", Limits: LimitsYAMLConfig{MaxRowsReturned: int64Ptr(10)}},
+			{Name: "analyst", Password: "password"},
+		},
+	}
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15311).WithUsers(users).
+		WithQueryLimits(dsqle.QueryLimits{MaxRowsReturned: 2})
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	tests := []struct {
+		user        string
+		password    string
+		expectedRes []testPerson
+	}{
+		{"admin", "secret", []testPerson{bill, john, rob}},
+		{"analyst", "password", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.user, func(t *testing.T) {
+			conn, err := dbr.Open("mysql", test.user+":"+test.password+"@tcp(localhost:15311)/dolt", nil)
+			require.NoError(t, err)
+			defer conn.Close()
+			conn.SetMaxOpenConns(1)
+			sess := conn.NewSession(nil)
+
+			var peoples []testPerson
+			_, err = sess.Select("*").From("people").LoadContext(context.Background(), &peoples)
+			if test.expectedRes == nil {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.ElementsMatch(t, test.expectedRes, peoples)
+			}
+
+			// a user's limit can't be raised past the one it was given
+			_, err = sess.Exec("set max_rows_returned = 20")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestServerMaxConns(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15310).WithMaxConns(1)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	conn, err := dbr.Open("mysql", serverConfig.ConnectionString(), nil)
+	require.NoError(t, err)
+	conn.SetMaxOpenConns(1)
+	_, err = conn.Exec("select 1")
+	require.NoError(t, err)
+
+	other, err := dbr.Open("mysql", serverConfig.ConnectionString(), nil)
+	require.NoError(t, err)
+	defer other.Close()
+	_, err = other.Exec("select 1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Too many connections")
+
+	// once the first connection closes, another can be opened
+	require.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool {
+		_, err := other.Exec("select 1")
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestServerRowFilters(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	users := &UsersConfig{
+		Users: []UserConfig{
+			{Name: "admin", Password: "secret", Permissions: []string{"read", "write"}},
+			{Name: "analyst", Password: "password", Roles: []string{"young"}},
+		},
+		Roles: []RoleConfig{
+			{Name: "young", RowFilters: map[string]string{"people": "age < 30"}},
+		},
+	}
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15302).WithUsers(users)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	tests := []struct {
+		user        string
+		password    string
+		expectedRes []testPerson
+	}{
+		{"admin", "secret", []testPerson{bill, john, rob}},
+		{"analyst", "password", []testPerson{john, rob}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.user, func(t *testing.T) {
+			conn, err := dbr.Open("mysql", test.user+":"+test.password+"@tcp(localhost:15302)/dolt", nil)
+			require.NoError(t, err)
+			defer conn.Close()
+			sess := conn.NewSession(nil)
+
+			var peoples []testPerson
+			_, err = sess.Select("*").From("people").LoadContext(context.Background(), &peoples)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, test.expectedRes, peoples)
+		})
+	}
+
+	conn, err := dbr.Open("mysql", "analyst:password@tcp(localhost:15302)/dolt", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.NewSession(nil).Exec("delete from people")
+	assert.Error(t, err)
+
+	conn, err = dbr.Open("mysql", "analyst:wrong@tcp(localhost:15302)/dolt", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Error(t, conn.Ping())
+}
+
+func TestServerCommits(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	users := &UsersConfig{
+		Users: []UserConfig{
+			{Name: "admin", Password: "secret", Permissions: []string{"read", "write"}},
+			{Name: "reader", Password: "password"},
+		},
+	}
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15303).WithUsers(users).
+		WithCommits(dsqle.CommitConfig{Policy: dsqle.CommitManual})
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	conn, err := dbr.Open("mysql", "admin:secret@tcp(localhost:15303)/dolt", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	sess := conn.NewSession(nil)
+
+	_, err = sess.InsertInto("people").Columns("id", "name", "age", "is_married", "title").
+		Values("00000000-0000-0000-0000-000000000003", "Homer Simpson", 40, true, "Safety Inspector").Exec()
+	require.NoError(t, err)
+
+	var hash string
+	err = sess.SelectBySql("select dolt_commit('added Homer')").LoadOneContext(context.Background(), &hash)
+	require.NoError(t, err)
+	assert.Len(t, hash, 32)
+
+	headRoot, err := env.HeadRoot(context.Background())
+	require.NoError(t, err)
+	tbl, _, err := headRoot.GetTable(context.Background(), "people")
+	require.NoError(t, err)
+	rowData, err := tbl.GetRowData(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), rowData.Len())
+
+	conn, err = dbr.Open("mysql", "reader:password@tcp(localhost:15303)/dolt", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	err = conn.NewSession(nil).SelectBySql("select dolt_commit('nothing')").LoadOneContext(context.Background(), &hash)
+	assert.Error(t, err)
+}
+
+func TestServerLocks(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15304)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	getLock := func(sess *dbr.Session, timeout int) int {
+		var taken int
+		err := sess.SelectBySql("select get_lock('etl', ?)", timeout).LoadOneContext(context.Background(), &taken)
+		require.NoError(t, err)
+		return taken
+	}
+
+	// locks are held by connections, so each session must use a single one
+	conn1, err := dbr.Open("mysql", serverConfig.ConnectionString(), nil)
+	require.NoError(t, err)
+	conn1.SetMaxOpenConns(1)
+	conn2, err := dbr.Open("mysql", serverConfig.ConnectionString(), nil)
+	require.NoError(t, err)
+	defer conn2.Close()
+	conn2.SetMaxOpenConns(1)
+
+	assert.Equal(t, 1, getLock(conn1.NewSession(nil), 0))
+	assert.Equal(t, 0, getLock(conn2.NewSession(nil), 0))
+
+	// the locks of a connection are released when it closes
+	require.NoError(t, conn1.Close())
+	assert.Equal(t, 1, getLock(conn2.NewSession(nil), 5))
+}
+
+func TestServerViews(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15305)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	conn, err := dbr.Open("mysql", serverConfig.ConnectionString(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	sess := conn.NewSession(nil)
+
+	_, err = sess.Exec("create view married as select * from people where is_married = true")
+	require.NoError(t, err)
+
+	var peoples []testPerson
+	_, err = sess.Select("*").From("married").LoadContext(context.Background(), &peoples)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []testPerson{bill}, peoples)
+}
+
+func TestServerMetrics(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15306).WithMetricsPort(15307)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	conn, err := dbr.Open("mysql", serverConfig.ConnectionString(), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Query("select * from people")
+	require.NoError(t, err)
+	_, err = conn.Query("select * from missing")
+	require.Error(t, err)
+
+	resp, err := http.Get("http://localhost:15307/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "dolt_head_commit_age_seconds{database=\"dolt\",branch=\"master\"}")
+	assert.Contains(t, string(body), "dolt_working_set_dirty{database=\"dolt\",branch=\"master\"} 1\n")
+	assert.Contains(t, string(body), "dolt_queries_total{database=\"dolt\",statement=\"select\",result=\"error\"} 1\n")
+	assert.Contains(t, string(body), "dolt_queries_total{database=\"dolt\",statement=\"select\",result=\"ok\"} 1\n")
+	assert.Contains(t, string(body), "dolt_query_duration_seconds_count{database=\"dolt\",statement=\"select\"} 2\n")
+}
+
+func TestServerRowCounts(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15308)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	rowsAffected := func(query string) int64 {
+		conn, err := dbr.Open("mysql", serverConfig.ConnectionString(), nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		res, err := conn.NewSession(nil).Exec(query)
+		require.NoError(t, err)
+		n, err := res.RowsAffected()
+		require.NoError(t, err)
+		return n
+	}
+
+	assert.Equal(t, int64(1), rowsAffected("update people set age = 25 where age < 30"))
+	assert.Equal(t, int64(0), rowsAffected("update people set age = 25 where age < 30"))
+	assert.Equal(t, int64(1), rowsAffected("insert into people (id, name, age, is_married, title) "+
+		"values ('00000000-0000-0000-0000-000000000003', 'Homer Simpson', 40, true, 'Safety Inspector')"))
+	assert.Equal(t, int64(2), rowsAffected("delete from people where age = 25"))
+}
+
+func TestServerReadOnly(t *testing.T) {
+	env := createEnvWithSeedData(t)
+	users := &UsersConfig{
+		Users: []UserConfig{
+			{Name: "admin", Password: "secret", Permissions: []string{"read", "write"}},
+			{Name: "reader", Password: "password"},
+		},
+	}
+	serverConfig := DefaultServerConfig().WithLogLevel(LogLevel_Fatal).WithPort(15309).WithUsers(users)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	exec := func(user, query string) error {
+		conn, err := dbr.Open("mysql", user+"@tcp(localhost:15309)/dolt", nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.NewSession(nil).Exec(query)
+		return err
+	}
+
+	// users without write permission can't write, whether the engine or the server checks the statement
+	for _, query := range []string{
+		"insert into people (id, name, age, is_married, title) values ('00000000-0000-0000-0000-000000000003', 'Homer Simpson', 40, true, 'Safety Inspector')",
+		"update people set age = 33 where age = 32",
+		"delete from people",
+		"create table t (pk bigint, primary key(pk))",
+		"drop table people",
+		"create view v as select 1",
+	} {
+		assert.Error(t, exec("reader:password", query), query)
+	}
+	assert.NoError(t, exec("reader:password", "select * from people"))
+	assert.NoError(t, exec("admin:secret", "create table t (pk bigint, primary key(pk))"))
+	assert.NoError(t, exec("admin:secret", "insert into t values (1)"))
+	sc.StopServer()
+	require.NoError(t, sc.WaitForClose())
+
+	// a read-only server rejects every statement that writes, whoever runs it
+	serverConfig = serverConfig.WithReadOnly(true)
+	sc = CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), serverConfig, env, sc)
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	for _, query := range []string{
+		"update people set age = 33 where age = 32",
+		"delete from people",
+		"drop table people",
+		"create table u (pk bigint, primary key(pk))",
+	} {
+		err := exec("admin:secret", query)
+		require.Error(t, err, query)
+		assert.Contains(t, err.Error(), "read-only", query)
+	}
+	assert.NoError(t, exec("admin:secret", "select * from people"))
+}
+
+func TestRowCountResult(t *testing.T) {
+	updateRes := &sqltypes.Result{Rows: [][]sqltypes.Value{{sqltypes.NewInt64(3), sqltypes.NewInt64(2)}}}
+	res, err := rowCountResult(updateRes, false)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{RowsAffected: 2}, res)
+
+	res, err = rowCountResult(updateRes, true)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{RowsAffected: 3}, res)
+
+	res, err = rowCountResult(&sqltypes.Result{Rows: [][]sqltypes.Value{{sqltypes.NewInt64(4)}}}, false)
+	require.NoError(t, err)
+	assert.Equal(t, &sqltypes.Result{RowsAffected: 4}, res)
+}
+
 func createEnvWithSeedData(t *testing.T) *env.DoltEnv {
 	dEnv := dtestutils.CreateTestEnv()
 	imt, sch := dtestutils.CreateTestDataTable(true)
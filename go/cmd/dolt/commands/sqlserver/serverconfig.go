@@ -17,6 +17,9 @@ package sqlserver
 import (
 	"fmt"
 	"net"
+	"time"
+
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
 )
 
 // LogLevel defines the available levels of logging for the server.
@@ -32,25 +35,39 @@ const (
 
 // ServerConfig contains all of the configurable options for the MySQL-compatible server.
 type ServerConfig struct {
-	Host     string   // The domain that the server will run on. Accepts an IPv4 or IPv6 address, in addition to localhost.
-	Port     int      // The port that the server will run on. The valid range is [1024, 65535].
-	User     string   // The username that connecting clients must use.
-	Password string   // The password that connecting clients must use.
-	Timeout  int      // The read and write timeouts.
-	ReadOnly bool     // Whether the server will only accept read statements or all statements.
-	LogLevel LogLevel // Specifies the level of logging that the server will use.
+	Host         string             // The domain that the server will run on. Accepts an IPv4 or IPv6 address, in addition to localhost.
+	Port         int                // The port that the server will run on. The valid range is [1024, 65535].
+	User         string             // The username that connecting clients must use.
+	Password     string             // The password that connecting clients must use.
+	Timeout      int                // The read and write timeouts, in seconds, unless ReadTimeout or WriteTimeout are set. 0 means no timeout.
+	ReadOnly     bool               // Whether the server will only accept read statements or all statements.
+	LogLevel     LogLevel           // Specifies the level of logging that the server will use.
+	Collation    dsqle.Collation    // Determines how string values are compared.
+	QueryLimits  dsqle.QueryLimits  // The query limits of each session, which sessions can lower but not raise with SET.
+	Users        *UsersConfig       // The users that may connect and their roles. When set, User and Password aren't used.
+	Commits      dsqle.CommitConfig // When writes are committed. By default they're kept in memory and never committed.
+	PollInterval time.Duration      // How often the working set is checked for changes made outside the server. 0 disables checking.
+	Attach       string             // Other repositories attached as read-only databases, as comma separated name=location pairs.
+	MetricsPort  int                // The port that metrics are served on over HTTP, at /metrics. 0 disables serving metrics.
+	AtCommit     string             // The commit whose tables are served read-only instead of the working set, if not empty.
+	MultiDBDir   string             // A directory whose subdirectories' repositories are each served as a database, if not empty.
+	Parallelism  int                // The number of partitions of a table that a query scans at once. 0 or 1 scans one at a time.
+	ReadTimeout  int                // The read timeout, in seconds, replacing Timeout if it's not 0.
+	WriteTimeout int                // The write timeout, in seconds, replacing Timeout if it's not 0.
+	MaxConns     int                // The number of connections that may be open at once. 0 means no limit.
 }
 
 // DefaultServerConfig creates a `*ServerConfig` that has all of the options set to their default values.
 func DefaultServerConfig() *ServerConfig {
 	return &ServerConfig{
-		Host:     "localhost",
-		Port:     3306,
-		User:     "root",
-		Password: "",
-		Timeout:  30,
-		ReadOnly: false,
-		LogLevel: LogLevel_Info,
+		Host:      "localhost",
+		Port:      3306,
+		User:      "root",
+		Password:  "",
+		Timeout:   30,
+		ReadOnly:  false,
+		LogLevel:  LogLevel_Info,
+		Collation: dsqle.CaseSensitive,
 	}
 }
 
@@ -74,6 +91,38 @@ func (config *ServerConfig) Validate() error {
 	if config.LogLevel.String() == "unknown" {
 		return fmt.Errorf("loglevel is invalid: %v\n", string(config.LogLevel))
 	}
+	if _, err := dsqle.ParseCollation(string(config.Collation)); err != nil {
+		return err
+	}
+	if config.ReadTimeout < 0 || config.WriteTimeout < 0 {
+		return fmt.Errorf("read and write timeouts cannot be less than 0: %v, %v\n", config.ReadTimeout, config.WriteTimeout)
+	}
+	if config.MaxConns < 0 {
+		return fmt.Errorf("max connections cannot be less than 0: %v\n", config.MaxConns)
+	}
+	if config.QueryLimits.MaxRowsReturned < 0 || config.QueryLimits.MaxScanRows < 0 || config.QueryLimits.MaxJoinSize < 0 || config.QueryLimits.MaxExecutionTime < 0 {
+		return fmt.Errorf("query limits cannot be less than 0: %+v\n", config.QueryLimits)
+	}
+	if config.Users != nil {
+		if err := config.Users.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := config.Commits.Validate(); err != nil {
+		return err
+	}
+	if config.PollInterval < 0 {
+		return fmt.Errorf("poll interval cannot be less than 0: %v\n", config.PollInterval)
+	}
+	if config.MetricsPort != 0 && (config.MetricsPort < 1024 || config.MetricsPort > 65535 || config.MetricsPort == config.Port) {
+		return fmt.Errorf("metrics port is not in the range between 1024-65535 or is the server's port: %v\n", config.MetricsPort)
+	}
+	if config.Parallelism < 0 {
+		return fmt.Errorf("parallelism cannot be less than 0: %v\n", config.Parallelism)
+	}
+	if config.MultiDBDir != "" && config.Attach != "" {
+		return fmt.Errorf("databases can't be attached when serving a directory of databases")
+	}
 	return nil
 }
 
@@ -119,6 +168,92 @@ func (config *ServerConfig) WithLogLevel(loglevel LogLevel) *ServerConfig {
 	return config
 }
 
+// WithCollation updates the collation and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithCollation(collation dsqle.Collation) *ServerConfig {
+	config.Collation = collation
+	return config
+}
+
+// WithQueryLimits updates the query limits and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithQueryLimits(limits dsqle.QueryLimits) *ServerConfig {
+	config.QueryLimits = limits
+	return config
+}
+
+// WithUsers updates the users config and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithUsers(users *UsersConfig) *ServerConfig {
+	config.Users = users
+	return config
+}
+
+// WithCommits updates the commit config and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithCommits(commits dsqle.CommitConfig) *ServerConfig {
+	config.Commits = commits
+	return config
+}
+
+// WithPollInterval updates the poll interval and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithPollInterval(interval time.Duration) *ServerConfig {
+	config.PollInterval = interval
+	return config
+}
+
+// WithMetricsPort updates the metrics port and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithMetricsPort(port int) *ServerConfig {
+	config.MetricsPort = port
+	return config
+}
+
+// WithAtCommit updates the commit served and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithAtCommit(spec string) *ServerConfig {
+	config.AtCommit = spec
+	return config
+}
+
+// WithMultiDBDir updates the directory of databases served and returns the called `*ServerConfig`, which is useful for
+// chaining calls.
+func (config *ServerConfig) WithMultiDBDir(dir string) *ServerConfig {
+	config.MultiDBDir = dir
+	return config
+}
+
+// WithParallelism updates the number of partitions scanned at once and returns the called `*ServerConfig`, which is
+// useful for chaining calls.
+func (config *ServerConfig) WithParallelism(parallelism int) *ServerConfig {
+	config.Parallelism = parallelism
+	return config
+}
+
+// WithReadTimeout updates the read timeout and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithReadTimeout(timeout int) *ServerConfig {
+	config.ReadTimeout = timeout
+	return config
+}
+
+// WithWriteTimeout updates the write timeout and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithWriteTimeout(timeout int) *ServerConfig {
+	config.WriteTimeout = timeout
+	return config
+}
+
+// WithMaxConns updates the connection limit and returns the called `*ServerConfig`, which is useful for chaining calls.
+func (config *ServerConfig) WithMaxConns(maxConns int) *ServerConfig {
+	config.MaxConns = maxConns
+	return config
+}
+
+// connTimeouts returns the read and write timeouts of connections.
+func (config *ServerConfig) connTimeouts() (read, write time.Duration) {
+	read, write = time.Duration(config.Timeout)*time.Second, time.Duration(config.Timeout)*time.Second
+	if config.ReadTimeout != 0 {
+		read = time.Duration(config.ReadTimeout) * time.Second
+	}
+	if config.WriteTimeout != 0 {
+		write = time.Duration(config.WriteTimeout) * time.Second
+	}
+	return read, write
+}
+
 // ConnectionString returns a Data Source Name (DSN) to be used by go clients for connecting to a running server.
 func (config *ServerConfig) ConnectionString() string {
 	return fmt.Sprintf("%v:%v@tcp(%v:%v)/dolt", config.User, config.Password, config.Host, config.Port)
@@ -17,10 +17,13 @@ package sqlserver
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
 )
 
@@ -32,6 +35,29 @@ const (
 	timeoutFlag  = "timeout"
 	readonlyFlag = "readonly"
 	logLevelFlag = "loglevel"
+	usersFlag    = "users"
+	configFlag   = "config"
+
+	commitPolicyFlag    = "commit-policy"
+	commitBatchSizeFlag = "commit-batch-size"
+	commitIntervalFlag  = "commit-interval"
+	flushIntervalFlag   = "flush-interval"
+	flushStatementsFlag = "flush-statements"
+	pollIntervalFlag    = "poll-interval"
+	attachFlag          = "attach"
+	metricsPortFlag     = "metrics-port"
+	atFlag              = "at"
+	multiDBDirFlag      = "multi-db-dir"
+	parallelismFlag     = "parallelism"
+
+	maxRowsReturnedFlag = "max-rows-returned"
+	maxScanRowsFlag     = "max-scan-rows"
+	maxJoinSizeFlag     = "max-join-size"
+	maxExecTimeFlag     = "max-execution-time"
+
+	readTimeoutFlag  = "read-timeout"
+	writeTimeoutFlag = "write-timeout"
+	maxConnsFlag     = "max-connections"
 )
 
 var sqlServerShortDesc = "Start a MySQL-compatible server."
@@ -39,9 +65,170 @@ var sqlServerLongDesc = `Start a MySQL-compatible server which can be connected
 
 Currently, only SELECT statements are operational, as support for other statements is
 still being developed.
+
+Queries can be limited to protect the server from runaway queries such as accidental cross products. A query is
+aborted with an error when it returns more than --max-rows-returned rows, reads more than --max-scan-rows rows from
+tables, or contains a join that would examine more than an estimated --max-join-size rows. A limit of 0 means no limit.
+Queries that read are also aborted once they've run for longer than --max-execution-time milliseconds. Each connection
+can lower its own limits with SET max_rows_returned, max_scan_rows, max_join_size or max_execution_time, but setting
+one higher than the server's limit, or to 0, is an error. Aborting a query stops its scans, and statements that write
+aren't aborted, as that would leave part of their writes made.
+
+At most --max-connections connections may be open at once. Clients connecting while that many are open get a Too many
+connections error. Connections are closed when a read from them, such as waiting for the next statement, or a write to
+them takes longer than --timeout seconds, and queries fail when they take that long to produce their next row. The
+timeouts of reads and writes can be set apart with --read-timeout and --write-timeout.
+
+Instead of the single user given by --user and --password, the users that may connect can be defined in a JSON file
+given by --users. Each user has a name, a password, permissions, which are any of "read" and "write" and default to
+read only, and roles. A role restricts the rows that its users can read: it maps tables to SQL expressions that rows
+must satisfy, and its users only see the rows of those tables for which the expression is true. Users can't modify
+tables whose rows are restricted, or read their diff, history, blame and conflicts tables or the materialized views
+computed from them. When more than one of a user's roles
+restricts the same table, the user sees the rows allowed by any of them. A user can also be limited to the hosts it
+connects from, which are IP addresses, networks such as 10.0.0.0/8, or localhost, and given query limits of its own,
+which replace the server's. For example:
+
+	{
+	  "Users": [
+	    {"Name": "admin", "Password": "secret", "Permissions": ["read", "write"], "Hosts": ["localhost"]},
+	    {"Name": "analyst", "Password": "password", "Roles": ["eu"], "Limits": {"MaxRowsReturned": 1000}}
+	  ],
+	  "Roles": [
+	    {"Name": "eu", "RowFilters": {"sales": "region = 'EU'"}}
+	  ]
+	}
+
+By default, changes made through the server are kept in memory and are lost when it stops. With --commit-policy,
+they're written to the working set and committed as the policy dictates:
+
+	transaction  commits after each statement that writes, as each statement is its own transaction
+	statements   commits once every --commit-batch-size statements that write
+	interval     commits the writes made in each --commit-interval seconds
+	manual       only commits when a user with write permission runs SELECT DOLT_COMMIT('message')
+
+The message of each commit made by a policy lists the SHA-256 digest of every statement it includes, along with the
+user and client address that ran it, so that dolt log shows who changed what through the server.
+
+DOLT_COMMIT can be called with any of the policies. Users with write permission can also create a branch with SELECT
+DOLT_BRANCH('name'[, 'start']), check it out with SELECT DOLT_CHECKOUT('name'), and merge it into the branch checked out
+with SELECT DOLT_MERGE('name'), which returns 1 if the merge has conflicts to resolve through the dolt_conflicts tables
+before it's committed. These change the branch served to every connection. They can synchronize with the repository's
+remotes with SELECT DOLT_FETCH(['remote']), SELECT DOLT_PULL(['remote']), which merges the remote's branch into the
+branch checked out like DOLT_MERGE, and SELECT DOLT_PUSH(['remote'[, 'branch']]), which pushes the branch checked out
+unless another is named. The remote defaults to the default remote, as it does for dolt fetch. When the server stops,
+writes that haven't been committed are committed, except with the manual policy, which leaves them in the working set.
+
+Writing the working set after every statement creates a table file and updates the manifest of the repository's
+storage each time, which slows down workloads of many small writes. With --flush-interval, writes are coalesced: they're
+kept in memory, where every statement that follows sees them, and written to the working set at most that many
+milliseconds after they're made, together with the writes made in the meantime, or as soon as --flush-statements
+statements are waiting if it's given. The commit policy is applied when they're written, so the transaction policy
+commits the statements coalesced together. Writes that are waiting are lost if the server is killed rather than
+stopped, and are replaced by changes to the working set found with --poll-interval.
+
+With --at, the tables of the commit given, which can be a commit hash, a branch name or HEAD, followed by ancestor
+references such as ~3, are served instead of the working set, as a historical snapshot. This implies --readonly, and
+statements that would write fail.
+
+Each statement sees the writes made by the statements before it, from any connection. Changes made to the working set
+outside of the server, such as by dolt commands run while it's up, aren't seen unless --poll-interval is given, in which
+case the working set is checked for them every that many seconds. Changes found replace the server's writes that haven't
+been written to the working set.
+
+Every statement is its own transaction, and reads from a snapshot of the database taken when it starts. Its isolation
+level is set for each connection with SET TRANSACTION ISOLATION LEVEL or SET transaction_isolation, and defaults to
+REPEATABLE READ. With READ UNCOMMITTED, READ COMMITTED or REPEATABLE READ, the writes of a statement replace the
+database when it ends, even if another statement wrote to it in the meantime. With SERIALIZABLE, a statement that
+writes fails with a serialization failure if the database changed since it started, and can be run again.
+
+Clients can coordinate their work, such as loading the same tables, through advisory locks taken with GET_LOCK(name,
+timeout) and released with RELEASE_LOCK(name) or RELEASE_ALL_LOCKS(), as in MySQL. By convention, the lock of a table
+is named dolt_table:<table> and the lock of a branch dolt_branch:<branch>. The locks of a connection are released when
+it closes.
+
+With --metrics-port, metrics on the freshness of the data served are served over HTTP at /metrics, in the Prometheus
+text format, so that alerts can be raised when stale data is served:
+
+	dolt_head_commit_age_seconds  the time since the head commit of the branch served was made
+	dolt_working_set_dirty        1 if the data served has writes that haven't been committed, and 0 if it doesn't
+	dolt_replication_lag_seconds  how far the head commit of the branch served trails the head of its upstream branch,
+	                              set with dolt push --set-upstream, as of the last dolt fetch
+
+The queries run by the server and the statistics of the chunk store of each database are served as well, so that a
+long-running server can be monitored:
+
+	dolt_queries_total                     the number of queries run, by statement and whether they succeeded
+	dolt_query_duration_seconds            a histogram of the time taken to run queries, by statement
+	dolt_nbs_chunks                        the number of chunks stored
+	dolt_nbs_<operation>_latency_seconds   the time taken by get, has, put, commit and persist operations of the chunk
+	                                       store, and by reads and writes of its manifest
+	dolt_nbs_chunks_read_total             the number of chunks read
+	dolt_nbs_chunks_persisted_total        the number of chunks written to table files
+	dolt_nbs_bytes_persisted_total         the number of bytes written to table files
+
+With --multi-db-dir, each dolt repository in the subdirectories of the directory given is served as a database named
+after its subdirectory, instead of the repository in the working directory being served as the database dolt.
+Connections choose the database their queries run against by naming it when they connect or with USE, and use the
+first database in name order if they don't. Tables of the other databases can be queried by qualifying them with their
+database's name, as in SELECT * FROM other.people. Functions that work with a repository, such as DOLT_COMMIT and
+DOLT_CHECKOUT, work with the repository of the database the connection uses, and the other options apply to every
+database. Databases can't be attached with --attach.
+
+Rather than giving every option as a flag, the options can be given in a YAML file with --config. Options left out of
+the file keep their defaults, and flags given along with the file override the options it gives. Options that aren't
+known are an error. For example:
+
+	log_level: warning
+	behavior:
+	  read_only: false
+	  at: HEAD~1                  # like --at, implies read_only
+	  parallelism: 4
+	  poll_interval_seconds: 5
+	user:                         # the single user, like --user and --password
+	  name: root
+	  password: secret
+	users:                        # the users file's users and roles, which replace user
+	  - name: analyst
+	    password: password
+	    permissions: [read]
+	    roles: [eu]
+	    hosts: [10.0.0.0/8]
+	    limits:
+	      max_rows_returned: 1000
+	roles:
+	  - name: eu
+	    row_filters:
+	      sales: region = 'EU'
+	listener:
+	  host: 0.0.0.0
+	  port: 3306
+	  timeout_seconds: 30
+	  read_timeout_seconds: 60      # like --read-timeout
+	  write_timeout_seconds: 60     # like --write-timeout
+	  max_connections: 100
+	  metrics_port: 9091
+	commits:
+	  policy: statements
+	  batch_size: 100
+	  interval_seconds: 60
+	  flush_interval_millis: 500
+	  flush_statements: 50
+	limits:
+	  max_rows_returned: 100000
+	  max_scan_rows: 10000000
+	  max_join_size: 1000000
+	  max_execution_time_millis: 30000
+	databases:
+	  multi_db_dir: /var/lib/dolt  # like --multi-db-dir
+	  attach:                     # like --attach
+	    - name: archive
+	      location: file:///var/lib/archive/.dolt/noms
+
+` + commands.AttachHelp + `
 `
 var sqlServerSynopsis = []string{
-	"[-H <host>] [-P <port>] [-u <user>] [-p <password>] [-t <timeout>] [-l <loglevel>] [-r]",
+	"[-H <host>] [-P <port>] [-u <user>] [-p <password>] [-t <timeout>] [-l <loglevel>] [-r] [--users <file>] [--max-rows-returned <n>] [--max-scan-rows <n>] [--max-join-size <n>] [--max-execution-time <milliseconds>] [--max-connections <n>] [--read-timeout <seconds>] [--write-timeout <seconds>] [--commit-policy <policy>] [--commit-batch-size <n>] [--commit-interval <seconds>] [--flush-interval <milliseconds>] [--flush-statements <n>] [--poll-interval <seconds>] [--attach <name>=<location>] [--metrics-port <port>] [--at <commit>] [--multi-db-dir <directory>] [--parallelism <n>] [--config <file>]",
 }
 
 func SqlServer(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
@@ -57,16 +244,48 @@ func SqlServerImpl(ctx context.Context, commandStr string, args []string, dEnv *
 	ap.SupportsString(userFlag, "u", "User", fmt.Sprintf("Defines the server user (default `%v`)", serverConfig.User))
 	ap.SupportsString(passwordFlag, "p", "Password", fmt.Sprintf("Defines the server password (default `%v`)", serverConfig.Password))
 	ap.SupportsInt(timeoutFlag, "t", "Connection timeout", fmt.Sprintf("Defines the timeout, in seconds, used for connections\nA value of `0` represents an infinite timeout (default `%v`)", serverConfig.Timeout))
-	ap.SupportsFlag(readonlyFlag, "r", "Disables modification of the database")
+	ap.SupportsFlag(readonlyFlag, "r", "Disables modification of the database: statements that write, such as INSERT or CREATE TABLE, are rejected")
 	ap.SupportsString(logLevelFlag, "l", "Log level", fmt.Sprintf("Defines the level of logging provided\nOptions are: `debug`, `info`, `warning`, `error`, `fatal` (default `%v`)", serverConfig.LogLevel))
+	ap.SupportsString(usersFlag, "", "Users file", "A JSON file defining the users that may connect and the rows they may read, replacing --user and --password")
+	ap.SupportsInt(maxRowsReturnedFlag, "", "Row count", "Aborts queries that return more than this many rows (default no limit)")
+	ap.SupportsInt(maxScanRowsFlag, "", "Row count", "Aborts queries that read more than this many rows from tables (default no limit)")
+	ap.SupportsInt(maxJoinSizeFlag, "", "Row count", "Aborts queries with a join that would examine more than this many rows (default no limit)")
+	ap.SupportsInt(maxExecTimeFlag, "", "Milliseconds", "Aborts queries that read which run for longer than this (default no limit)")
+	ap.SupportsInt(maxConnsFlag, "", "Connection count", "The number of connections that may be open at once (default no limit)")
+	ap.SupportsInt(readTimeoutFlag, "", "Seconds", "The timeout of reads from connections, replacing --timeout for reads")
+	ap.SupportsInt(writeTimeoutFlag, "", "Seconds", "The timeout of writes to connections, replacing --timeout for writes")
+	ap.SupportsString(commitPolicyFlag, "", "Commit policy", "When writes are committed\nOptions are: `transaction`, `statements`, `interval`, `manual` (default writes are kept in memory)")
+	ap.SupportsInt(commitBatchSizeFlag, "", "Statement count", "The number of statements that write per commit with the `statements` commit policy")
+	ap.SupportsInt(commitIntervalFlag, "", "Seconds", "The number of seconds between commits with the `interval` commit policy")
+	ap.SupportsInt(flushIntervalFlag, "", "Milliseconds", "The longest a write waits to be written to the working set, with a commit policy (default writes are written right away)")
+	ap.SupportsInt(flushStatementsFlag, "", "Statement count", "The number of statements waiting to be written that writes them early, with --flush-interval")
+	ap.SupportsInt(pollIntervalFlag, "", "Seconds", "The number of seconds between checks of the working set for changes made outside the server (default changes aren't checked for)")
+	ap.SupportsString(attachFlag, "", "name=location", "Attaches other dolt repositories as read-only databases, given as a comma separated list")
+	ap.SupportsUint(metricsPortFlag, "", "Port", "Serves metrics over HTTP on this port, at /metrics (default metrics aren't served)")
+	ap.SupportsString(atFlag, "", "Commit", "Serves the tables of this commit read-only instead of the working set")
+	ap.SupportsString(multiDBDirFlag, "", "Directory", "Serves each dolt repository in the subdirectories of this directory as a database named after its subdirectory")
+	ap.SupportsString(configFlag, "", "Config file", "A YAML file of server settings, which flags given with it override")
+	ap.SupportsInt(parallelismFlag, "", "Partition count", "The number of partitions of a table that a query scans in parallel. Rows are then returned in no particular order unless the query orders them (default `1`)")
 	help, usage := cli.HelpAndUsagePrinters(commandStr, sqlServerShortDesc, sqlServerLongDesc, sqlServerSynopsis, ap)
 
 	apr := cli.ParseArgs(ap, args, help)
 	args = apr.Args()
 
-	root, verr := commands.GetWorkingWithVErr(dEnv)
-	if verr != nil {
-		return commands.HandleVErrAndExitCode(verr, usage)
+	if configFile, ok := apr.GetValue(configFlag); ok {
+		yamlConfig, err := LoadYAMLConfig(dEnv.FS, configFile)
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		yamlConfig.Apply(serverConfig)
+	}
+
+	if dir, ok := apr.GetValue(multiDBDirFlag); ok {
+		serverConfig.MultiDBDir = dir
+	}
+	if serverConfig.MultiDBDir == "" {
+		if verr := requireRepo(dEnv); verr != nil {
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
 	}
 
 	if host, ok := apr.GetValue(hostFlag); ok {
@@ -90,7 +309,69 @@ func SqlServerImpl(ctx context.Context, commandStr string, args []string, dEnv *
 	if logLevel, ok := apr.GetValue(logLevelFlag); ok {
 		serverConfig.LogLevel = LogLevel(logLevel)
 	}
-	if startError, closeError := Serve(ctx, serverConfig, root, serverController); startError != nil || closeError != nil {
+	if maxRows, ok := apr.GetInt(maxRowsReturnedFlag); ok {
+		serverConfig.QueryLimits.MaxRowsReturned = int64(maxRows)
+	}
+	if maxScan, ok := apr.GetInt(maxScanRowsFlag); ok {
+		serverConfig.QueryLimits.MaxScanRows = int64(maxScan)
+	}
+	if maxJoin, ok := apr.GetInt(maxJoinSizeFlag); ok {
+		serverConfig.QueryLimits.MaxJoinSize = int64(maxJoin)
+	}
+	if maxExecTime, ok := apr.GetInt(maxExecTimeFlag); ok {
+		serverConfig.QueryLimits.MaxExecutionTime = int64(maxExecTime)
+	}
+	if maxConns, ok := apr.GetInt(maxConnsFlag); ok {
+		serverConfig.MaxConns = maxConns
+	}
+	if timeout, ok := apr.GetInt(readTimeoutFlag); ok {
+		serverConfig.ReadTimeout = timeout
+	}
+	if timeout, ok := apr.GetInt(writeTimeoutFlag); ok {
+		serverConfig.WriteTimeout = timeout
+	}
+	if usersFile, ok := apr.GetValue(usersFlag); ok {
+		users, err := LoadUsersConfig(dEnv.FS, usersFile)
+		if err != nil {
+			return commands.HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		serverConfig.Users = users
+	}
+	if policy, ok := apr.GetValue(commitPolicyFlag); ok {
+		serverConfig.Commits.Policy = dsqle.CommitPolicy(policy)
+	}
+	if batchSize, ok := apr.GetInt(commitBatchSizeFlag); ok {
+		serverConfig.Commits.BatchSize = batchSize
+	}
+	if interval, ok := apr.GetInt(commitIntervalFlag); ok {
+		serverConfig.Commits.Interval = time.Duration(interval) * time.Second
+	}
+	if interval, ok := apr.GetInt(flushIntervalFlag); ok {
+		serverConfig.Commits.FlushInterval = time.Duration(interval) * time.Millisecond
+	}
+	if size, ok := apr.GetInt(flushStatementsFlag); ok {
+		serverConfig.Commits.FlushSize = size
+	}
+	if interval, ok := apr.GetInt(pollIntervalFlag); ok {
+		serverConfig.PollInterval = time.Duration(interval) * time.Second
+	}
+	if attach, ok := apr.GetValue(attachFlag); ok {
+		serverConfig.Attach = attach
+	}
+	if port, ok := apr.GetInt(metricsPortFlag); ok {
+		serverConfig.MetricsPort = port
+	}
+	if parallelism, ok := apr.GetInt(parallelismFlag); ok {
+		serverConfig.Parallelism = parallelism
+	}
+	if spec, ok := apr.GetValue(atFlag); ok {
+		serverConfig.AtCommit = spec
+		serverConfig.ReadOnly = true
+	}
+	if collation := dEnv.Config.GetStringOrDefault(env.SqlCollationKey, ""); len(*collation) > 0 {
+		serverConfig.Collation = dsqle.Collation(*collation)
+	}
+	if startError, closeError := Serve(ctx, serverConfig, dEnv, serverController); startError != nil || closeError != nil {
 		if startError != nil {
 			cli.PrintErrln(startError)
 		}
@@ -102,3 +383,18 @@ func SqlServerImpl(ctx context.Context, commandStr string, args []string, dEnv *
 
 	return 0
 }
+
+// requireRepo returns an error unless the current directory is a repository that can be served. Unlike other
+// commands, sql-server doesn't need one when it's given a directory of repositories to serve.
+func requireRepo(dEnv *env.DoltEnv) errhand.VerboseError {
+	if !dEnv.HasDoltDir() {
+		return errhand.BuildDError("The current directory is not a valid dolt repository.\nrun: dolt init before trying to run this command, or serve a directory of repositories with --%s", multiDBDirFlag).Build()
+	} else if dEnv.RSLoadErr != nil {
+		return errhand.BuildDError("The current directories repository state is invalid").AddCause(dEnv.RSLoadErr).Build()
+	} else if dEnv.DBLoadError != nil {
+		return errhand.BuildDError("Failed to load database.").AddCause(dEnv.DBLoadError).Build()
+	}
+
+	_, verr := commands.GetWorkingWithVErr(dEnv)
+	return verr
+}
@@ -0,0 +1,345 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/auth"
+	"github.com/src-d/go-mysql-server/sql"
+	"vitess.io/vitess/go/mysql"
+
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+// UserConfig is a user that may connect to the server.
+type UserConfig struct {
+	Name        string           `yaml:"name"`
+	Password    string           `yaml:"password"`
+	Permissions []string         `yaml:"permissions"` // Any of "read" and "write". Users without permissions may only read.
+	Roles       []string         `yaml:"roles"`       // The names of the roles whose row filters apply to the user.
+	Hosts       []string         `yaml:"hosts"`       // IP addresses, CIDR networks or "localhost". Users without hosts may connect from any host.
+	Limits      LimitsYAMLConfig `yaml:"limits"`      // The query limits of the user's sessions, each replacing the server's.
+}
+
+// RoleConfig is a role that restricts the rows of tables that its users can read. Each table named in RowFilters maps
+// to a SQL expression, such as "region = 'EU'", that rows of the table must satisfy to be read.
+type RoleConfig struct {
+	Name       string            `yaml:"name"`
+	RowFilters map[string]string `yaml:"row_filters"`
+}
+
+// UsersConfig defines the users that may connect to the server and the roles they have, which allows sharing a
+// single database with users who may only see part of it.
+type UsersConfig struct {
+	Users []UserConfig
+	Roles []RoleConfig
+}
+
+// LoadUsersConfig reads the users config from the JSON file at the path given.
+func LoadUsersConfig(fs filesys.ReadableFS, path string) (*UsersConfig, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users file '%s': %v", path, err)
+	}
+
+	var config UsersConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse users file '%s': %v", path, err)
+	}
+
+	return &config, nil
+}
+
+// Validate returns an error if the config has duplicate or unnamed users or roles, unknown permissions, users with
+// roles that aren't defined, invalid row filters, or negative query limits.
+func (config *UsersConfig) Validate() error {
+	roles := make(map[string]bool)
+	for _, role := range config.Roles {
+		if len(role.Name) == 0 {
+			return fmt.Errorf("role name cannot be empty")
+		} else if roles[role.Name] {
+			return fmt.Errorf("duplicate role: %s", role.Name)
+		}
+
+		if err := dsqle.RowFilters(role.RowFilters).Validate(); err != nil {
+			return fmt.Errorf("role %s: %v", role.Name, err)
+		}
+
+		roles[role.Name] = true
+	}
+
+	users := make(map[string]bool)
+	for _, user := range config.Users {
+		if len(user.Name) == 0 {
+			return fmt.Errorf("user name cannot be empty")
+		} else if users[user.Name] {
+			return fmt.Errorf("duplicate user: %s", user.Name)
+		}
+
+		if _, err := user.permissions(); err != nil {
+			return err
+		}
+
+		if _, err := newHostMatcher(user.Hosts); err != nil {
+			return fmt.Errorf("user %s: %v", user.Name, err)
+		}
+
+		var limits dsqle.QueryLimits
+		user.Limits.apply(&limits)
+		if limits.MaxRowsReturned < 0 || limits.MaxScanRows < 0 || limits.MaxJoinSize < 0 || limits.MaxExecutionTime < 0 {
+			return fmt.Errorf("user %s: query limits cannot be less than 0: %+v", user.Name, limits)
+		}
+
+		for _, role := range user.Roles {
+			if !roles[role] {
+				return fmt.Errorf("user %s has unknown role: %s", user.Name, role)
+			}
+		}
+
+		users[user.Name] = true
+	}
+
+	if len(users) == 0 {
+		return fmt.Errorf("users file must define at least one user")
+	}
+
+	return nil
+}
+
+// RowFilters returns the row filters that apply to the user named. When more than one of the user's roles filters the
+// same table, the user can read the rows that satisfy any of the filters.
+func (config *UsersConfig) RowFilters(userName string) dsqle.RowFilters {
+	roles := make(map[string]RoleConfig)
+	for _, role := range config.Roles {
+		roles[role.Name] = role
+	}
+
+	byTable := make(map[string][]string)
+	for _, user := range config.Users {
+		if user.Name != userName {
+			continue
+		}
+
+		for _, roleName := range user.Roles {
+			for tableName, filter := range roles[roleName].RowFilters {
+				lwrName := strings.ToLower(tableName)
+				byTable[lwrName] = append(byTable[lwrName], filter)
+			}
+		}
+	}
+
+	filters := make(dsqle.RowFilters, len(byTable))
+	for tableName, tableFilters := range byTable {
+		if len(tableFilters) == 1 {
+			filters[tableName] = tableFilters[0]
+			continue
+		}
+
+		sort.Strings(tableFilters)
+		for i, filter := range tableFilters {
+			tableFilters[i] = "(" + filter + ")"
+		}
+		filters[tableName] = strings.Join(tableFilters, " OR ")
+	}
+
+	return filters
+}
+
+// QueryLimits returns the query limits of the sessions of the user named: the server's limits given, with those set
+// for the user replacing them.
+func (config *UsersConfig) QueryLimits(userName string, serverLimits dsqle.QueryLimits) dsqle.QueryLimits {
+	limits := serverLimits
+	for _, user := range config.Users {
+		if user.Name == userName {
+			user.Limits.apply(&limits)
+		}
+	}
+
+	return limits
+}
+
+// permissions returns the permissions granted to the user.
+func (user UserConfig) permissions() (auth.Permission, error) {
+	if len(user.Permissions) == 0 {
+		return auth.DefaultPermissions, nil
+	}
+
+	var perms auth.Permission
+	for _, name := range user.Permissions {
+		perm, ok := auth.PermissionNames[strings.ToLower(name)]
+		if !ok {
+			return 0, fmt.Errorf("user %s has unknown permission: %s", user.Name, name)
+		}
+		perms |= perm
+	}
+
+	return perms, nil
+}
+
+// hostMatcher matches the addresses of clients against the hosts a user may connect from.
+type hostMatcher struct {
+	localhost bool
+	networks  []*net.IPNet
+}
+
+// newHostMatcher returns a matcher for the hosts given, or nil if there are none, as any host matches then.
+func newHostMatcher(hosts []string) (*hostMatcher, error) {
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+
+	m := &hostMatcher{}
+	for _, host := range hosts {
+		if strings.EqualFold(host, "localhost") {
+			m.localhost = true
+		} else if _, network, err := net.ParseCIDR(host); err == nil {
+			m.networks = append(m.networks, network)
+		} else if ip := net.ParseIP(host); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			m.networks = append(m.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		} else {
+			return nil, fmt.Errorf("invalid host: %s", host)
+		}
+	}
+
+	return m, nil
+}
+
+// matches returns whether a client connecting from the address given is connecting from one of the hosts.
+func (m *hostMatcher) matches(addr net.Addr) bool {
+	var ip net.IP
+	switch addr := addr.(type) {
+	case *net.UnixAddr:
+		return m.localhost
+	case *net.TCPAddr:
+		ip = addr.IP
+	default:
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return false
+		}
+		ip = net.ParseIP(host)
+	}
+
+	if ip == nil {
+		return false
+	} else if m.localhost && ip.IsLoopback() {
+		return true
+	}
+
+	for _, network := range m.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// usersAuth authenticates the users of a UsersConfig with mysql_native_password and checks their permissions and the
+// hosts they connect from.
+type usersAuth struct {
+	passwords   map[string]string
+	permissions map[string]auth.Permission
+	hosts       map[string]*hostMatcher
+}
+
+var _ auth.Auth = (*usersAuth)(nil)
+
+// newUsersAuth returns the auth for the users of the config given, which must be valid. If readOnly is true, none of
+// the users may write.
+func newUsersAuth(config *UsersConfig, readOnly bool) *usersAuth {
+	ua := &usersAuth{
+		passwords:   make(map[string]string),
+		permissions: make(map[string]auth.Permission),
+		hosts:       make(map[string]*hostMatcher),
+	}
+	for _, user := range config.Users {
+		perms, _ := user.permissions()
+		if readOnly {
+			perms &= auth.ReadPerm
+		}
+
+		ua.passwords[user.Name] = auth.NativePassword(user.Password)
+		ua.permissions[user.Name] = perms
+		if hosts, _ := newHostMatcher(user.Hosts); hosts != nil {
+			ua.hosts[user.Name] = hosts
+		}
+	}
+
+	return ua
+}
+
+// Mysql implements auth.Auth.
+func (ua *usersAuth) Mysql() mysql.AuthServer {
+	as := mysql.NewAuthServerStatic()
+	for name, password := range ua.passwords {
+		as.Entries[name] = []*mysql.AuthServerStaticEntry{{MysqlNativePassword: password, Password: password}}
+	}
+
+	return &hostsAuthServer{as, ua.hosts}
+}
+
+// Allowed implements auth.Auth.
+func (ua *usersAuth) Allowed(ctx *sql.Context, permission auth.Permission) error {
+	perms, ok := ua.permissions[ctx.Client().User]
+	if !ok || perms&permission != permission {
+		return auth.ErrNotAuthorized.Wrap(auth.ErrNoPermission.New(permission &^ perms))
+	}
+
+	return nil
+}
+
+// hostsAuthServer denies access to users connecting from hosts they may not connect from, and authenticates the others
+// with the auth server it wraps.
+type hostsAuthServer struct {
+	mysql.AuthServer
+	hosts map[string]*hostMatcher
+}
+
+// ValidateHash implements mysql.AuthServer.
+func (as *hostsAuthServer) ValidateHash(salt []byte, user string, authResponse []byte, remoteAddr net.Addr) (mysql.Getter, error) {
+	if err := as.checkHost(user, remoteAddr); err != nil {
+		return &mysql.StaticUserData{}, err
+	}
+
+	return as.AuthServer.ValidateHash(salt, user, authResponse, remoteAddr)
+}
+
+// Negotiate implements mysql.AuthServer.
+func (as *hostsAuthServer) Negotiate(c *mysql.Conn, user string, remoteAddr net.Addr) (mysql.Getter, error) {
+	if err := as.checkHost(user, remoteAddr); err != nil {
+		return &mysql.StaticUserData{}, err
+	}
+
+	return as.AuthServer.Negotiate(c, user, remoteAddr)
+}
+
+func (as *hostsAuthServer) checkHost(user string, remoteAddr net.Addr) error {
+	if hosts, ok := as.hosts[user]; ok && !hosts.matches(remoteAddr) {
+		return mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", user)
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"vitess.io/vitess/go/mysql"
+
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+func TestLoadUsersConfig(t *testing.T) {
+	fs := filesys.NewInMemFS(nil, map[string][]byte{
+		"/users.json": []byte(`{
+			"Users": [{"Name": "analyst", "Password": "password", "Roles": ["eu"]}],
+			"Roles": [{"Name": "eu", "RowFilters": {"sales": "region = 'EU'"}}]
+		}`),
+		"/bad.json": []byte(`{"Users": [`),
+	}, "/")
+
+	config, err := LoadUsersConfig(fs, "/users.json")
+	require.NoError(t, err)
+	assert.Equal(t, &UsersConfig{
+		Users: []UserConfig{{Name: "analyst", Password: "password", Roles: []string{"eu"}}},
+		Roles: []RoleConfig{{Name: "eu", RowFilters: map[string]string{"sales": "region = 'EU'"}}},
+	}, config)
+	assert.NoError(t, config.Validate())
+
+	_, err = LoadUsersConfig(fs, "/bad.json")
+	assert.Error(t, err)
+	_, err = LoadUsersConfig(fs, "/missing.json")
+	assert.Error(t, err)
+}
+
+func TestUsersConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      UsersConfig
+		expectedErr string
+	}{
+		{
+			name:        "no users",
+			config:      UsersConfig{},
+			expectedErr: "users file must define at least one user",
+		},
+		{
+			name:        "duplicate user",
+			config:      UsersConfig{Users: []UserConfig{{Name: "a"}, {Name: "a"}}},
+			expectedErr: "duplicate user: a",
+		},
+		{
+			name:        "unknown permission",
+			config:      UsersConfig{Users: []UserConfig{{Name: "a", Permissions: []string{"admin"}}}},
+			expectedErr: "user a has unknown permission: admin",
+		},
+		{
+			name:        "unknown role",
+			config:      UsersConfig{Users: []UserConfig{{Name: "a", Roles: []string{"eu"}}}},
+			expectedErr: "user a has unknown role: eu",
+		},
+		{
+			name: "invalid row filter",
+			config: UsersConfig{
+				Users: []UserConfig{{Name: "a", Roles: []string{"eu"}}},
+				Roles: []RoleConfig{{Name: "eu", RowFilters: map[string]string{"sales": "region ="}}},
+			},
+			expectedErr: "role eu: invalid row filter for table sales",
+		},
+		{
+			name:        "invalid host",
+			config:      UsersConfig{Users: []UserConfig{{Name: "a", Hosts: []string{"10.0.0.0/33"}}}},
+			expectedErr: "user a: invalid host: 10.0.0.0/33",
+		},
+		{
+			name:        "negative limit",
+			config:      UsersConfig{Users: []UserConfig{{Name: "a", Limits: LimitsYAMLConfig{MaxScanRows: int64Ptr(-1)}}}},
+			expectedErr: "user a: query limits cannot be less than 0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedErr)
+		})
+	}
+}
+
+func TestUsersConfigRowFilters(t *testing.T) {
+	config := &UsersConfig{
+		Users: []UserConfig{
+			{Name: "admin"},
+			{Name: "eu", Roles: []string{"eu"}},
+			{Name: "eu_us", Roles: []string{"eu", "us"}},
+		},
+		Roles: []RoleConfig{
+			{Name: "eu", RowFilters: map[string]string{"Sales": "region = 'EU'", "customers": "eu = true"}},
+			{Name: "us", RowFilters: map[string]string{"sales": "region = 'US'"}},
+		},
+	}
+
+	assert.Equal(t, dsqle.RowFilters{}, config.RowFilters("admin"))
+	assert.Equal(t, dsqle.RowFilters{}, config.RowFilters("unknown"))
+	assert.Equal(t, dsqle.RowFilters{"sales": "region = 'EU'", "customers": "eu = true"}, config.RowFilters("eu"))
+	assert.Equal(t, dsqle.RowFilters{"sales": "(region = 'EU') OR (region = 'US')", "customers": "eu = true"}, config.RowFilters("eu_us"))
+}
+
+func TestUsersConfigQueryLimits(t *testing.T) {
+	config := &UsersConfig{
+		Users: []UserConfig{
+			{Name: "admin", Limits: LimitsYAMLConfig{MaxRowsReturned: int64Ptr(0), MaxExecutionTimeMillis: int64Ptr(60000)}},
+			{Name: "analyst"},
+		},
+	}
+
+	serverLimits := dsqle.QueryLimits{MaxRowsReturned: 100, MaxScanRows: 1000}
+	assert.Equal(t, dsqle.QueryLimits{MaxScanRows: 1000, MaxExecutionTime: 60000}, config.QueryLimits("admin", serverLimits))
+	assert.Equal(t, serverLimits, config.QueryLimits("analyst", serverLimits))
+	assert.Equal(t, serverLimits, config.QueryLimits("unknown", serverLimits))
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestHostMatcher(t *testing.T) {
+	m, err := newHostMatcher(nil)
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	m, err = newHostMatcher([]string{"LocalHost", "10.1.0.0/16", "192.168.0.7", "fd00::1"})
+	require.NoError(t, err)
+
+	tcp := func(ip string) net.Addr {
+		return &net.TCPAddr{IP: net.ParseIP(ip), Port: 3306}
+	}
+	assert.True(t, m.matches(tcp("127.0.0.1")))
+	assert.True(t, m.matches(tcp("::1")))
+	assert.True(t, m.matches(&net.UnixAddr{Name: "/tmp/mysql.sock", Net: "unix"}))
+	assert.True(t, m.matches(tcp("10.1.200.3")))
+	assert.True(t, m.matches(tcp("192.168.0.7")))
+	assert.True(t, m.matches(tcp("fd00::1")))
+	assert.False(t, m.matches(tcp("10.2.0.1")))
+	assert.False(t, m.matches(tcp("192.168.0.8")))
+	assert.False(t, m.matches(tcp("fd00::2")))
+
+	m, err = newHostMatcher([]string{"10.0.0.1"})
+	require.NoError(t, err)
+	assert.False(t, m.matches(tcp("127.0.0.1")))
+	assert.False(t, m.matches(&net.UnixAddr{Name: "/tmp/mysql.sock", Net: "unix"}))
+}
+
+func TestUsersAuthHosts(t *testing.T) {
+	config := &UsersConfig{Users: []UserConfig{
+		{Name: "local", Password: "password", Hosts: []string{"localhost"}},
+		{Name: "anywhere", Password: "password"},
+	}}
+	require.NoError(t, config.Validate())
+	as := newUsersAuth(config, false).Mysql()
+
+	validate := func(user string, ip string) error {
+		salt, err := as.Salt()
+		require.NoError(t, err)
+		_, err = as.ValidateHash(salt, user, mysql.ScramblePassword(salt, []byte("password")), &net.TCPAddr{IP: net.ParseIP(ip), Port: 3306})
+		return err
+	}
+
+	assert.NoError(t, validate("local", "127.0.0.1"))
+	assert.Error(t, validate("local", "10.0.0.1"))
+	assert.NoError(t, validate("anywhere", "127.0.0.1"))
+	assert.NoError(t, validate("anywhere", "10.0.0.1"))
+	assert.Error(t, validate("unknown", "127.0.0.1"))
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+// YAMLConfig is the server config read from a YAML file given with --config. Settings left out of the file keep their
+// defaults, and flags given along with the file override its settings.
+type YAMLConfig struct {
+	LogLevel  *string             `yaml:"log_level"`
+	Behavior  BehaviorYAMLConfig  `yaml:"behavior"`
+	User      UserYAMLConfig      `yaml:"user"`
+	Users     []UserConfig        `yaml:"users"` // When given, replaces user.
+	Roles     []RoleConfig        `yaml:"roles"`
+	Listener  ListenerYAMLConfig  `yaml:"listener"`
+	Commits   CommitsYAMLConfig   `yaml:"commits"`
+	Limits    LimitsYAMLConfig    `yaml:"limits"`
+	Databases DatabasesYAMLConfig `yaml:"databases"`
+}
+
+// BehaviorYAMLConfig holds the settings that change what the server allows and how it serves the database.
+type BehaviorYAMLConfig struct {
+	ReadOnly            *bool   `yaml:"read_only"`
+	At                  *string `yaml:"at"` // Implies read_only.
+	Parallelism         *int    `yaml:"parallelism"`
+	PollIntervalSeconds *int    `yaml:"poll_interval_seconds"`
+}
+
+// UserYAMLConfig is the single user that may connect when users aren't given.
+type UserYAMLConfig struct {
+	Name     *string `yaml:"name"`
+	Password *string `yaml:"password"`
+}
+
+// ListenerYAMLConfig holds the settings of the server's connections.
+type ListenerYAMLConfig struct {
+	Host                *string `yaml:"host"`
+	Port                *int    `yaml:"port"`
+	TimeoutSeconds      *int    `yaml:"timeout_seconds"`
+	ReadTimeoutSeconds  *int    `yaml:"read_timeout_seconds"`
+	WriteTimeoutSeconds *int    `yaml:"write_timeout_seconds"`
+	MaxConnections      *int    `yaml:"max_connections"`
+	MetricsPort         *int    `yaml:"metrics_port"`
+}
+
+// CommitsYAMLConfig holds the commit policy and the settings it uses.
+type CommitsYAMLConfig struct {
+	Policy              *string `yaml:"policy"`
+	BatchSize           *int    `yaml:"batch_size"`
+	IntervalSeconds     *int    `yaml:"interval_seconds"`
+	FlushIntervalMillis *int    `yaml:"flush_interval_millis"`
+	FlushStatements     *int    `yaml:"flush_statements"`
+}
+
+// LimitsYAMLConfig holds the query limits of each session, or of the sessions of a user.
+type LimitsYAMLConfig struct {
+	MaxRowsReturned        *int64 `yaml:"max_rows_returned"`
+	MaxScanRows            *int64 `yaml:"max_scan_rows"`
+	MaxJoinSize            *int64 `yaml:"max_join_size"`
+	MaxExecutionTimeMillis *int64 `yaml:"max_execution_time_millis"`
+}
+
+// apply sets the limits given in the config on the limits given.
+func (cfg LimitsYAMLConfig) apply(limits *dsqle.QueryLimits) {
+	if cfg.MaxRowsReturned != nil {
+		limits.MaxRowsReturned = *cfg.MaxRowsReturned
+	}
+	if cfg.MaxScanRows != nil {
+		limits.MaxScanRows = *cfg.MaxScanRows
+	}
+	if cfg.MaxJoinSize != nil {
+		limits.MaxJoinSize = *cfg.MaxJoinSize
+	}
+	if cfg.MaxExecutionTimeMillis != nil {
+		limits.MaxExecutionTime = *cfg.MaxExecutionTimeMillis
+	}
+}
+
+// DatabasesYAMLConfig holds the databases served besides the repository in the working directory.
+type DatabasesYAMLConfig struct {
+	MultiDBDir *string              `yaml:"multi_db_dir"`
+	Attach     []AttachedYAMLConfig `yaml:"attach"`
+}
+
+// AttachedYAMLConfig is another repository attached as a read-only database.
+type AttachedYAMLConfig struct {
+	Name     string `yaml:"name"`
+	Location string `yaml:"location"`
+}
+
+// LoadYAMLConfig reads the server config from the YAML file at the path given. Settings that aren't known are an
+// error, so that misspelled settings aren't silently ignored.
+func LoadYAMLConfig(fs filesys.ReadableFS, path string) (*YAMLConfig, error) {
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file '%s': %v", path, err)
+	}
+
+	var config YAMLConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %v", path, err)
+	}
+
+	return &config, nil
+}
+
+// Apply sets the settings given in the file on the server config given.
+func (cfg *YAMLConfig) Apply(config *ServerConfig) {
+	if cfg.LogLevel != nil {
+		config.LogLevel = LogLevel(*cfg.LogLevel)
+	}
+
+	if cfg.Behavior.ReadOnly != nil {
+		config.ReadOnly = *cfg.Behavior.ReadOnly
+	}
+	if cfg.Behavior.At != nil {
+		config.AtCommit = *cfg.Behavior.At
+		config.ReadOnly = true
+	}
+	if cfg.Behavior.Parallelism != nil {
+		config.Parallelism = *cfg.Behavior.Parallelism
+	}
+	if cfg.Behavior.PollIntervalSeconds != nil {
+		config.PollInterval = time.Duration(*cfg.Behavior.PollIntervalSeconds) * time.Second
+	}
+
+	if cfg.User.Name != nil {
+		config.User = *cfg.User.Name
+	}
+	if cfg.User.Password != nil {
+		config.Password = *cfg.User.Password
+	}
+	if len(cfg.Users) > 0 || len(cfg.Roles) > 0 {
+		config.Users = &UsersConfig{Users: cfg.Users, Roles: cfg.Roles}
+	}
+
+	if cfg.Listener.Host != nil {
+		config.Host = *cfg.Listener.Host
+	}
+	if cfg.Listener.Port != nil {
+		config.Port = *cfg.Listener.Port
+	}
+	if cfg.Listener.TimeoutSeconds != nil {
+		config.Timeout = *cfg.Listener.TimeoutSeconds
+	}
+	if cfg.Listener.ReadTimeoutSeconds != nil {
+		config.ReadTimeout = *cfg.Listener.ReadTimeoutSeconds
+	}
+	if cfg.Listener.WriteTimeoutSeconds != nil {
+		config.WriteTimeout = *cfg.Listener.WriteTimeoutSeconds
+	}
+	if cfg.Listener.MaxConnections != nil {
+		config.MaxConns = *cfg.Listener.MaxConnections
+	}
+	if cfg.Listener.MetricsPort != nil {
+		config.MetricsPort = *cfg.Listener.MetricsPort
+	}
+
+	if cfg.Commits.Policy != nil {
+		config.Commits.Policy = dsqle.CommitPolicy(*cfg.Commits.Policy)
+	}
+	if cfg.Commits.BatchSize != nil {
+		config.Commits.BatchSize = *cfg.Commits.BatchSize
+	}
+	if cfg.Commits.IntervalSeconds != nil {
+		config.Commits.Interval = time.Duration(*cfg.Commits.IntervalSeconds) * time.Second
+	}
+	if cfg.Commits.FlushIntervalMillis != nil {
+		config.Commits.FlushInterval = time.Duration(*cfg.Commits.FlushIntervalMillis) * time.Millisecond
+	}
+	if cfg.Commits.FlushStatements != nil {
+		config.Commits.FlushSize = *cfg.Commits.FlushStatements
+	}
+
+	cfg.Limits.apply(&config.QueryLimits)
+
+	if cfg.Databases.MultiDBDir != nil {
+		config.MultiDBDir = *cfg.Databases.MultiDBDir
+	}
+	if len(cfg.Databases.Attach) > 0 {
+		pairs := make([]string, len(cfg.Databases.Attach))
+		for i, adb := range cfg.Databases.Attach {
+			pairs[i] = adb.Name + "=" + adb.Location
+		}
+		config.Attach = strings.Join(pairs, ",")
+	}
+}
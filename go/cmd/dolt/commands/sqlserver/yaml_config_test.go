@@ -0,0 +1,109 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dsqle "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+func TestLoadYAMLConfig(t *testing.T) {
+	fs := filesys.NewInMemFS(nil, map[string][]byte{
+		"/server.yaml": []byte(`
+log_level: warning
+behavior:
+  at: HEAD~1
+  parallelism: 4
+  poll_interval_seconds: 5
+users:
+  - name: analyst
+    password: password
+    roles: [eu]
+roles:
+  - name: eu
+    row_filters:
+      sales: region = 'EU'
+listener:
+  host: 0.0.0.0
+  port: 3307
+  timeout_seconds: 0
+  read_timeout_seconds: 60
+  max_connections: 10
+commits:
+  policy: statements
+  batch_size: 10
+  flush_interval_millis: 500
+limits:
+  max_rows_returned: 1000
+  max_execution_time_millis: 500
+databases:
+  attach:
+    - name: a
+      location: /a
+    - name: b
+      location: /b
+`),
+		"/empty.yaml":   []byte(``),
+		"/unknown.yaml": []byte("listener:\n  prot: 3307\n"),
+		"/bad.yaml":     []byte("listener: [\n"),
+	}, "/")
+
+	yamlConfig, err := LoadYAMLConfig(fs, "/server.yaml")
+	require.NoError(t, err)
+	config := DefaultServerConfig()
+	yamlConfig.Apply(config)
+
+	expected := DefaultServerConfig()
+	expected.LogLevel = LogLevel_Warning
+	expected.ReadOnly = true
+	expected.AtCommit = "HEAD~1"
+	expected.Parallelism = 4
+	expected.PollInterval = 5 * time.Second
+	expected.Users = &UsersConfig{
+		Users: []UserConfig{{Name: "analyst", Password: "password", Roles: []string{"eu"}}},
+		Roles: []RoleConfig{{Name: "eu", RowFilters: map[string]string{"sales": "region = 'EU'"}}},
+	}
+	expected.Host = "0.0.0.0"
+	expected.Port = 3307
+	expected.Timeout = 0
+	expected.ReadTimeout = 60
+	expected.MaxConns = 10
+	expected.Commits = dsqle.CommitConfig{Policy: dsqle.CommitPerStatements, BatchSize: 10, FlushInterval: 500 * time.Millisecond}
+	expected.QueryLimits.MaxRowsReturned = 1000
+	expected.QueryLimits.MaxExecutionTime = 500
+	expected.Attach = "a=/a,b=/b"
+	assert.Equal(t, expected, config)
+	assert.NoError(t, config.Validate())
+
+	// settings left out of the file keep their defaults
+	yamlConfig, err = LoadYAMLConfig(fs, "/empty.yaml")
+	require.NoError(t, err)
+	config = DefaultServerConfig()
+	yamlConfig.Apply(config)
+	assert.Equal(t, DefaultServerConfig(), config)
+
+	_, err = LoadYAMLConfig(fs, "/unknown.yaml")
+	assert.Error(t, err)
+	_, err = LoadYAMLConfig(fs, "/bad.yaml")
+	assert.Error(t, err)
+	_, err = LoadYAMLConfig(fs, "/missing.yaml")
+	assert.Error(t, err)
+}
@@ -63,12 +63,14 @@ var tblDiffTypeToLabel = map[actions.TableDiffType]string{
 	actions.ModifiedTable: "modified:",
 	actions.RemovedTable:  "deleted:",
 	actions.AddedTable:    "new table:",
+	actions.RenamedTable:  "renamed:",
 }
 
 var tblDiffTypeToShortLabel = map[actions.TableDiffType]string{
 	actions.ModifiedTable: "M",
 	actions.RemovedTable:  "D",
 	actions.AddedTable:    "N",
+	actions.RenamedTable:  "R",
 }
 
 const (
@@ -110,7 +112,7 @@ func printStagedDiffs(wr io.Writer, staged *actions.TableDiffs, printHelp bool)
 		lines := make([]string, 0, staged.Len())
 		for _, tblName := range staged.Tables {
 			tdt := staged.TableToType[tblName]
-			lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[tdt], tblName))
+			lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[tdt], diffTableName(staged, tblName)))
 		}
 
 		iohelp.WriteLine(wr, color.GreenString(strings.Join(lines, "\n")))
@@ -143,7 +145,7 @@ func printDiffsNotStaged(wr io.Writer, notStaged *actions.TableDiffs, printHelp
 		linesPrinted += len(lines)
 	}
 
-	if notStaged.NumRemoved+notStaged.NumModified-inCnfSet.Size() > 0 {
+	if notStaged.NumRemoved+notStaged.NumModified+notStaged.NumRenamed-inCnfSet.Size() > 0 {
 		if linesPrinted > 0 {
 			cli.Println()
 		}
@@ -159,7 +161,7 @@ func printDiffsNotStaged(wr io.Writer, notStaged *actions.TableDiffs, printHelp
 			tdt := notStaged.TableToType[tblName]
 
 			if tdt != actions.AddedTable && !inCnfSet.Contains(tblName) {
-				lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[tdt], tblName))
+				lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[tdt], diffTableName(notStaged, tblName)))
 			}
 		}
 
@@ -194,6 +196,15 @@ func printDiffsNotStaged(wr io.Writer, notStaged *actions.TableDiffs, printHelp
 	return linesPrinted
 }
 
+// diffTableName returns the name of a table to display for a diff, which includes its old name if it was renamed.
+func diffTableName(diffs *actions.TableDiffs, tblName string) string {
+	if oldName, ok := diffs.RenamedFrom[tblName]; ok {
+		return oldName + " -> " + tblName
+	}
+
+	return tblName
+}
+
 func printStatus(dEnv *env.DoltEnv, staged, notStaged *actions.TableDiffs, workingInConflict []string) {
 	cli.Printf(branchHeader, dEnv.RepoState.Head.Ref.GetPath())
 
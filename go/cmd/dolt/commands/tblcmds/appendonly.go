@@ -0,0 +1,116 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tblcmds
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+)
+
+const offParam = "off"
+
+var appendOnlyShortDesc = "Makes tables append-only, or lists append-only tables"
+var appendOnlyLongDesc = "dolt table append-only marks tables in the working set as append-only.  Rows can be added to " +
+	"an append-only table, but its existing rows can't be updated or deleted, by SQL statements, imports or any other " +
+	"command, which suits tables of events or log entries whose history shouldn't change.  Schema changes, such as " +
+	"adding a column, are still allowed.  As rows are only ever added, merging branches of an append-only table " +
+	"combines the rows added on each, and only conflicts if the same primary key was added with different values.\n" +
+	"\n" +
+	"If <b>--off</b> is given, the tables are no longer append-only.  With no tables given, the append-only tables in " +
+	"the working set are listed.  Like other table changes, these can be staged using <b>dolt add</b> and committed " +
+	"using <b>dolt commit</b>."
+var appendOnlySynopsis = []string{
+	"[--off] <table>...",
+	"",
+}
+
+func AppendOnly(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["table"] = "The tables to make append-only"
+	ap.SupportsFlag(offParam, "", "Make the tables no longer append-only.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, appendOnlyShortDesc, appendOnlyLongDesc, appendOnlySynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	working, verr := commands.GetWorkingWithVErr(dEnv)
+
+	if verr == nil {
+		if apr.NArg() == 0 {
+			if apr.Contains(offParam) {
+				usage()
+				return 1
+			}
+
+			verr = printAppendOnlyTables(ctx, working)
+		} else {
+			verr = commands.ValidateTablesWithVErr(apr.Args(), working)
+
+			if verr == nil {
+				verr = setAppendOnly(ctx, dEnv, working, apr.Args(), !apr.Contains(offParam))
+			}
+		}
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+func printAppendOnlyTables(ctx context.Context, root *doltdb.RootValue) errhand.VerboseError {
+	names, err := root.GetTableNames(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("Unable to read tables").AddCause(err).Build()
+	}
+
+	for _, name := range names {
+		tbl, _, err := root.GetTable(ctx, name)
+
+		if err != nil {
+			return errhand.BuildDError("Unable to read table '%s'", name).AddCause(err).Build()
+		}
+
+		if appendOnly, err := tbl.IsAppendOnly(); err != nil {
+			return errhand.BuildDError("Unable to read table '%s'", name).AddCause(err).Build()
+		} else if appendOnly {
+			cli.Println(name)
+		}
+	}
+
+	return nil
+}
+
+func setAppendOnly(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, tables []string, appendOnly bool) errhand.VerboseError {
+	for _, name := range tables {
+		tbl, _, err := root.GetTable(ctx, name)
+
+		if err == nil {
+			tbl, err = tbl.SetAppendOnly(appendOnly)
+		}
+
+		if err == nil {
+			root, err = root.PutTable(ctx, name, tbl)
+		}
+
+		if err != nil {
+			return errhand.BuildDError("Unable to update table '%s'", name).AddCause(err).Build()
+		}
+	}
+
+	return commands.UpdateWorkingWithVErr(dEnv, root)
+}
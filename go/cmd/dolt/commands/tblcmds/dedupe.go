@@ -0,0 +1,212 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tblcmds
+
+import (
+	"context"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dedupe"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	byParam     = "by"
+	removeParam = "remove"
+)
+
+var dedupeShortDesc = "Finds or removes duplicate rows of a table"
+var dedupeLongDesc = "dolt table dedupe finds the rows of a table in the working set which have the same values as " +
+	"another row for the columns given by <b>--by</b>, or for all the columns besides the primary key if it isn't " +
+	"given.  Of each set of such rows, the row with the lowest primary key is kept, and the others are duplicates.  " +
+	"Rows missing a value are only duplicates of other rows missing the same value.\n" +
+	"\n" +
+	"The primary keys of the duplicate rows are printed.  If <b>--remove</b> is given, the duplicate rows are also " +
+	"removed from the table, and the list of the rows removed can be used in the message of the commit removing them.\n" +
+	"\n" +
+	"Rows are compared by the hashes of their values, which are written to temporary files rather than held in memory, " +
+	"so tables of any size can be checked."
+var dedupeSynopsis = []string{
+	"[--by <column>,...] [--remove] <table>",
+}
+
+func Dedupe(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["table"] = "The table to find duplicate rows in."
+	ap.SupportsString(byParam, "", "columns", "A comma separated list of the columns to compare rows by.")
+	ap.SupportsFlag(removeParam, "", "Remove the duplicate rows from the table.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, dedupeShortDesc, dedupeLongDesc, dedupeSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 1 {
+		usage()
+		return 1
+	}
+
+	tblName := apr.Arg(0)
+	root, tbl, verr := getRootAndTable(ctx, dEnv, tblName)
+
+	if verr == nil {
+		verr = dedupeTable(ctx, dEnv, root, tbl, tblName, apr.GetValueOrDefault(byParam, ""), apr.Contains(removeParam))
+	}
+
+	return commands.HandleVErrAndExitCode(verr, usage)
+}
+
+func dedupeTable(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, tbl *doltdb.Table, tblName, by string, remove bool) errhand.VerboseError {
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to get schema").AddCause(err).Build()
+	}
+
+	cols, verr := dedupeColumns(sch, tblName, by)
+
+	if verr != nil {
+		return verr
+	}
+
+	m, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to get row data").AddCause(err).Build()
+	}
+
+	var tags []uint64
+	var colNames []string
+	for _, col := range cols {
+		tags = append(tags, col.Tag)
+		colNames = append(colNames, col.Name)
+	}
+
+	var keys []string
+	me := m.Edit()
+	count, err := dedupe.FindDuplicates(ctx, dEnv.TempTableFilesDir(), m, sch, tags, func(r row.Row) error {
+		key, err := formatPrimaryKey(ctx, sch, r)
+
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, key)
+		me.Remove(r.NomsMapKey(sch))
+		return nil
+	})
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to find the duplicate rows of %s", tblName).AddCause(err).Build()
+	}
+
+	if count == 0 {
+		cli.Printf("No rows of %s are duplicates by %s\n", tblName, strings.Join(colNames, ", "))
+		return nil
+	}
+
+	if remove {
+		m, err = me.Map(ctx)
+
+		if err == nil {
+			tbl, err = tbl.UpdateRows(ctx, m)
+		}
+
+		if err == doltdb.ErrAppendOnly {
+			return errhand.BuildDError("error: %s is append-only, so its rows can't be removed", tblName).Build()
+		} else if err == nil {
+			root, err = root.PutTable(ctx, tblName, tbl)
+		}
+
+		if err != nil {
+			return errhand.BuildDError("error: failed to update the table").AddCause(err).Build()
+		}
+
+		verr = commands.UpdateWorkingWithVErr(dEnv, root)
+
+		if verr != nil {
+			return verr
+		}
+
+		cli.Printf("Removed %d rows of %s which were duplicates by %s:\n", count, tblName, strings.Join(colNames, ", "))
+	} else {
+		cli.Printf("Found %d rows of %s which are duplicates by %s:\n", count, tblName, strings.Join(colNames, ", "))
+	}
+
+	for _, key := range keys {
+		cli.Println("\t" + key)
+	}
+
+	return nil
+}
+
+// dedupeColumns returns the columns named in the comma separated list given, or the columns of the schema besides its
+// primary key if the list is empty.
+func dedupeColumns(sch schema.Schema, tblName, by string) ([]schema.Column, errhand.VerboseError) {
+	if by == "" {
+		cols := sch.GetNonPKCols().GetColumns()
+
+		if len(cols) == 0 {
+			return nil, errhand.BuildDError("error: %s has no columns besides its primary key. Use --%s to give the columns to compare.", tblName, byParam).Build()
+		}
+
+		return cols, nil
+	}
+
+	var cols []schema.Column
+	for _, name := range strings.Split(by, ",") {
+		col, ok := sch.GetAllCols().GetByName(strings.TrimSpace(name))
+
+		if !ok {
+			return nil, errhand.BuildDError("error: %s has no column '%s'", tblName, strings.TrimSpace(name)).Build()
+		}
+
+		cols = append(cols, col)
+	}
+
+	return cols, nil
+}
+
+// formatPrimaryKey returns the primary key of the row given as a list of the names and values of its columns.
+func formatPrimaryKey(ctx context.Context, sch schema.Schema, r row.Row) (string, error) {
+	var parts []string
+	err := sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		val, ok := r.GetColVal(tag)
+
+		if !ok {
+			val = types.NullValue
+		}
+
+		str, err := types.EncodedValue(ctx, val)
+
+		if err != nil {
+			return true, err
+		}
+
+		parts = append(parts, col.Name+": "+str)
+		return false, nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(parts, ", "), nil
+}
@@ -16,24 +16,53 @@ package tblcmds
 
 import (
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/fatih/color"
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/diff"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
+	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
+const (
+	sinceParam       = "since"
+	deletesFileParam = "deletes-file"
+)
+
+var errPrimaryKeyChanged = errors.New("the primary key of the table has changed")
+
 var exportShortDesc = `Export the contents of a table to a file.`
 var exportLongDesc = `dolt table export will export the contents of <table> to <file>
 
-See the help for <b>dolt table import</b> as the options are the same.`
+See the help for <b>dolt table import</b> as the options are the same.
+
+If <b>--since</b> is given, only the rows added or updated since <commit> are exported to <file>, and the primary keys of
+the rows deleted since then are exported to a separate tombstone file. The tombstone file is named after <file>, with
+<b>.deletes</b> added before its extension, unless a path is given with <b>--deletes-file</b>. Applying both files to a
+copy of the table as it was at <commit> brings it up to date, so large tables can be synced downstream without
+exporting them in full. The primary key of the table must not have changed since <commit>.`
 var exportSynopsis = []string{
 	"[-f] [-pk <field>] [-schema <file>] [-map <file>] [-continue] [-file-type <type>] <table> <file>",
+	"[-f] [-continue] [-file-type <type>] --since <commit> [--deletes-file <file>] <table> <file>",
+}
+
+// incrementalExport holds the options of an export of only the rows changed since a commit.
+type incrementalExport struct {
+	since   string
+	deletes mvdata.DataLocation
 }
 
 // validateExportArgs validates the input from the arg parser, and returns the tuple:
@@ -84,7 +113,46 @@ func validateExportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter)
 	return tableName, tableLoc, destLoc
 }
 
-func parseExportArgs(commandStr string, args []string) (bool, *mvdata.MoveOptions) {
+// parseIncrementalExportArgs returns the options of an incremental export, or nil if the export isn't incremental. ok
+// is false if the arguments are invalid.
+func parseIncrementalExportArgs(apr *argparser.ArgParseResults, destLoc mvdata.DataLocation) (incr *incrementalExport, ok bool) {
+	since, isIncremental := apr.GetValue(sinceParam)
+	deletesPath, hasDeletesPath := apr.GetValue(deletesFileParam)
+
+	if !isIncremental {
+		if hasDeletesPath {
+			cli.PrintErrln(color.RedString("--%s can only be used with --%s", deletesFileParam, sinceParam))
+			return nil, false
+		}
+
+		return nil, true
+	}
+
+	if !hasDeletesPath {
+		fileLoc, isFile := destLoc.(mvdata.FileDataLocation)
+		if !isFile {
+			cli.PrintErrln(color.RedString("--%s is required when exporting the changes since a commit to stdout", deletesFileParam))
+			return nil, false
+		}
+
+		ext := filepath.Ext(fileLoc.Path)
+		deletesPath = strings.TrimSuffix(fileLoc.Path, ext) + ".deletes" + ext
+	}
+
+	fType, _ := apr.GetValue(fileTypeParam)
+	deletesLoc := mvdata.NewDataLocation(deletesPath, fType)
+
+	if fileLoc, isFile := deletesLoc.(mvdata.FileDataLocation); !isFile || fileLoc.Format == mvdata.InvalidDataFormat {
+		cli.PrintErrln(
+			color.RedString("Could not infer type file '%s'\n", deletesPath),
+			"File extensions should match supported file types, or should be explicitly defined via the file-type parameter")
+		return nil, false
+	}
+
+	return &incrementalExport{since: since, deletes: deletesLoc}, true
+}
+
+func parseExportArgs(commandStr string, args []string) (bool, *mvdata.MoveOptions, *incrementalExport) {
 	ap := argparser.NewArgParser()
 	ap.ArgListHelp["table"] = "The table being exported."
 	ap.ArgListHelp["file"] = "The file being output to."
@@ -94,13 +162,20 @@ func parseExportArgs(commandStr string, args []string) (bool, *mvdata.MoveOption
 	ap.SupportsString(mappingFileParam, "m", "mapping_file", "A file that lays out how fields should be mapped from input data to output data.")
 	ap.SupportsString(primaryKeyParam, "pk", "primary_key", "Explicitly define the name of the field in the schema which should be used as the primary key.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
+	ap.SupportsString(sinceParam, "", "commit", "Only export the rows changed since the commit given, writing the primary keys of the deleted rows to a tombstone file.")
+	ap.SupportsString(deletesFileParam, "", "file", "The tombstone file the primary keys of the rows deleted since --since are exported to.")
 
 	help, usage := cli.HelpAndUsagePrinters(commandStr, exportShortDesc, exportLongDesc, exportSynopsis, ap)
 	apr := cli.ParseArgs(ap, args, help)
 	tableName, tableLoc, fileLoc := validateExportArgs(apr, usage)
 
 	if fileLoc == nil || len(tableLoc.Name) == 0 {
-		return false, nil
+		return false, nil, nil
+	}
+
+	incr, ok := parseIncrementalExportArgs(apr, fileLoc)
+	if !ok {
+		return false, nil, nil
 	}
 
 	schemaFile, _ := apr.GetValue(outSchemaParam)
@@ -116,17 +191,22 @@ func parseExportArgs(commandStr string, args []string) (bool, *mvdata.MoveOption
 		PrimaryKey:  primaryKey,
 		Src:         tableLoc,
 		Dest:        fileLoc,
-	}
+	}, incr
 }
 
 func Export(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
-	force, mvOpts := parseExportArgs(commandStr, args)
+	force, mvOpts, incr := parseExportArgs(commandStr, args)
 
 	if mvOpts == nil {
 		return 1
 	}
 
-	result := executeMove(ctx, dEnv, force, mvOpts)
+	var result int
+	if incr != nil {
+		result = exportChanges(ctx, dEnv, force, mvOpts, incr)
+	} else {
+		result = executeMove(ctx, dEnv, force, false, nil, mvOpts)
+	}
 
 	if result == 0 {
 		cli.PrintErrln(color.CyanString("Successfully exported data."))
@@ -134,3 +214,171 @@ func Export(ctx context.Context, commandStr string, args []string, dEnv *env.Dol
 
 	return result
 }
+
+// exportChanges exports the rows of the table added or updated since the commit of the incremental export given to the
+// destination of the move, and the primary keys of the rows deleted since then to the export's tombstone file.
+func exportChanges(ctx context.Context, dEnv *env.DoltEnv, force bool, mvOpts *mvdata.MoveOptions, incr *incrementalExport) int {
+	root, err := dEnv.WorkingRoot(ctx)
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to get the working root value for this data repository."))
+		return 1
+	}
+
+	cm, verr := commands.ResolveCommitWithVErr(dEnv, incr.since, dEnv.RepoState.Head.Ref.String())
+
+	if verr != nil {
+		cli.PrintErrln(verr.Verbose())
+		return 1
+	}
+
+	sinceRoot, err := cm.GetRootValue()
+
+	if err != nil {
+		cli.PrintErrln(color.RedString("Unable to read the root value of '%s'.", incr.since))
+		return 1
+	}
+
+	changedRoot, deletedRoot, err := changedRowRoots(ctx, root, sinceRoot, mvOpts.TableName)
+
+	if err == doltdb.ErrTableNotFound {
+		cli.PrintErrln(color.RedString("Table '%s' not found.", mvOpts.TableName))
+		return 1
+	} else if err == errPrimaryKeyChanged {
+		cli.PrintErrln(color.RedString("The primary key of %s has changed since %s. Export the whole table instead.", mvOpts.TableName, incr.since))
+		return 1
+	} else if err != nil {
+		bdr := errhand.BuildDError("error: failed to find the rows of %s changed since %s", mvOpts.TableName, incr.since)
+		cli.PrintErrln(bdr.AddCause(err).Build().Verbose())
+		return 1
+	}
+
+	if !force {
+		if exists, err := incr.deletes.Exists(ctx, root, dEnv.FS); err != nil {
+			cli.PrintErrln(color.RedString(err.Error()))
+			return 1
+		} else if exists {
+			cli.PrintErrln(color.RedString("Data already exists.  Use -f to overwrite."))
+			return 1
+		}
+	}
+
+	if result := executeMoveFromRoot(ctx, dEnv, changedRoot, force, false, nil, mvOpts); result != 0 {
+		return result
+	}
+
+	deletesOpts := *mvOpts
+	deletesOpts.SchFile = ""
+	deletesOpts.MappingFile = ""
+	deletesOpts.Dest = incr.deletes
+
+	return executeMoveFromRoot(ctx, dEnv, deletedRoot, force, false, nil, &deletesOpts)
+}
+
+// changedRowRoots returns a root in which the table with the name given only holds its rows added or updated since the
+// root since, and a root in which it only holds the primary keys of its rows deleted since then.
+func changedRowRoots(ctx context.Context, root, since *doltdb.RootValue, tblName string) (changedRoot, deletedRoot *doltdb.RootValue, err error) {
+	tbl, ok, err := root.GetTable(ctx, tblName)
+
+	if err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, doltdb.ErrTableNotFound
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sinceData, err := types.NewMap(ctx, root.VRW())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sinceTbl, ok, err := since.GetTable(ctx, tblName)
+
+	if err != nil {
+		return nil, nil, err
+	} else if ok {
+		sinceSch, err := sinceTbl.GetSchema(ctx)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !samePrimaryKey(sch, sinceSch) {
+			return nil, nil, errPrimaryKeyChanged
+		}
+
+		sinceData, err = sinceTbl.GetRowData(ctx)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	changed, removed, err := diff.ChangedRows(ctx, root.VRW(), sinceData, rowData)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changedRoot, err = putTableRows(ctx, root, tblName, sch, changed)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deletedRoot, err = putTableRows(ctx, root, tblName, schema.SchemaFromCols(sch.GetPKCols()), removed)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return changedRoot, deletedRoot, nil
+}
+
+// putTableRows returns the root given with the table of the name given replaced by one with the schema and rows given.
+func putTableRows(ctx context.Context, root *doltdb.RootValue, tblName string, sch schema.Schema, rows types.Map) (*doltdb.RootValue, error) {
+	schVal, err := encoding.MarshalAsNomsValue(ctx, root.VRW(), sch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := doltdb.NewTable(ctx, root.VRW(), schVal, rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return root.PutTable(ctx, tblName, tbl)
+}
+
+// samePrimaryKey returns whether the two schemas have the same primary key columns, in the same order, so that the row
+// keys of tables with either schema can be compared.
+func samePrimaryKey(sch1, sch2 schema.Schema) bool {
+	tags1 := sch1.GetPKCols().Tags
+	tags2 := sch2.GetPKCols().Tags
+
+	if len(tags1) != len(tags2) {
+		return false
+	}
+
+	for i := range tags1 {
+		if tags1[i] != tags2[i] {
+			return false
+		}
+	}
+
+	return true
+}
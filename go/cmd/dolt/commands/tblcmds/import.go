@@ -25,11 +25,15 @@ import (
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/alterschema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/iohelp"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
@@ -47,6 +51,13 @@ const (
 	primaryKeyParam  = "pk"
 	fileTypeParam    = "file-type"
 	delimParam       = "delim"
+	addMissingParam  = "add-missing-columns"
+	parallelParam    = "parallel"
+
+	mappingPresetParam     = "mapping-preset"
+	saveMappingPresetParam = "save-mapping-preset"
+	checkpointRowsParam    = "checkpoint-rows"
+	resumeParam            = "resume"
 )
 
 var SchemaFileHelp = "Schema definition files are json files in the format:" + `
@@ -73,6 +84,11 @@ var MappingFileHelp = "A mapping file is json in the format:" + `
 }
 
 where source_field_name is the name of a field in the file being imported and dest_field_name is the name of a field in the table being imported to.
+
+A mapping file used with <b>--save-mapping-preset <name></b> is stored in the <b>dolt_mapping_presets</b> table under
+that name once the import succeeds.  Later imports can use the stored mapping with <b>--mapping-preset <name></b> in place
+of a mapping file.  Like any other table, the presets table is versioned, so it can be committed, merged and shared with
+the rest of the repository.
 `
 
 var importShortDesc = `Imports data into a dolt table`
@@ -100,25 +116,45 @@ specified.
 If the schema for the existing table does not match the schema for the new file, the import will be aborted by default. To
 overwrite both the table and the schema, use <b>-c -f</b>.
 
+If <b>--add-missing-columns</b> is given along with <b>-u</b> or <b>-r</b>, fields in the file which don't match any
+column of <table> are added to its schema as nullable columns, with types inferred from the file's data, before the
+file is imported. Existing rows will have null values in the new columns. If <table> is staged, the new columns are
+staged as well, so that the schema change can be committed separately from the imported rows. This is supported for
+csv, psv and xlsx files without a mapping file.
+
 A mapping file can be used to map fields between the file being imported and the table being written to.  This can 
 be used when creating a new table, or updating or replacing an existing table.
 
 ` + MappingFileHelp +
 
 	`
+Several files can be imported into <table> in a single operation by giving more than one file, or a glob pattern such
+as <b>data/part-*.csv</b>, in which only the file name may contain wildcards.  The files must all be of the same type
+and have the same fields, though not necessarily in the same order, and are imported as if they were a single file. When
+creating a table, its schema is inferred from the first file.  Files are read one after another, or concurrently if
+<b>--parallel</b> is given, in which case the order in which rows are imported isn't defined.  If the same primary key
+appears in more than one file, the row imported last is kept.
+
+If <b>--checkpoint-rows <n></b> is given, the progress of the import is checkpointed every <n> rows, by writing the table
+with the rows imported so far to the database and recording how many rows of the files have been read.  Nothing is
+written to the working set until the import completes.  If the import is interrupted, or fails, running the same command
+again with <b>--resume</b> continues it from the last checkpoint rather than starting over.  Only one import can be
+resumed at a time, so starting another import with <b>--checkpoint-rows</b> discards the checkpoint of an earlier one.
+Checkpointing isn't supported when importing from stdin or with <b>--parallel</b>.
+
 In create, update, and replace scenarios the file's extension is used to infer the type of the file.  If a file does not 
 have the expected extension then the <b>--file-type</b> parameter should be used to explicitly define the format of 
 the file in one of the supported formats (csv, psv, json, xlsx).  For files separated by a delimiter other than a 
 ',' (type csv) or a '|' (type psv), the --delim parameter can be used to specify a delimeter`
 
 var importSynopsis = []string{
-	"-c [-f] [--pk <field>] [--schema <file>] [--map <file>] [--continue] [--file-type <type>] <table> <file>",
-	"-u [--map <file>] [--continue] [--file-type <type>] <table> <file>",
-	"-r [--map <file>] [--file-type <type>] <table> <file>",
+	"-c [-f] [--pk <field>] [--schema <file>] [--map <file> [--save-mapping-preset <name>] | --mapping-preset <name>] [--continue] [--file-type <type>] [--parallel | --checkpoint-rows <n> [--resume]] <table> <file>...",
+	"-u [--map <file> [--save-mapping-preset <name>] | --mapping-preset <name> | --add-missing-columns] [--continue] [--file-type <type>] [--parallel | --checkpoint-rows <n> [--resume]] <table> <file>...",
+	"-r [--map <file> [--save-mapping-preset <name>] | --mapping-preset <name> | --add-missing-columns] [--file-type <type>] [--parallel | --checkpoint-rows <n> [--resume]] <table> <file>...",
 }
 
 func validateImportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter) (mvdata.MoveOperation, mvdata.TableDataLocation, mvdata.DataLocation, interface{}) {
-	if apr.NArg() == 0 || apr.NArg() > 2 {
+	if apr.NArg() == 0 {
 		usage()
 		return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
 	}
@@ -130,6 +166,11 @@ func validateImportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter)
 		return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
 	} else if apr.Contains(createParam) {
 		mvOp = mvdata.OverwriteOp
+		if apr.Contains(addMissingParam) {
+			cli.PrintErrln("fatal:", addMissingParam+" is only supported for update or replace operations")
+			usage()
+			return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+		}
 	} else {
 		if apr.Contains(replaceParam) {
 			mvOp = mvdata.ReplaceOp
@@ -141,6 +182,36 @@ func validateImportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter)
 			usage()
 			return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
 		}
+		if apr.Contains(addMissingParam) && (apr.Contains(mappingFileParam) || apr.Contains(mappingPresetParam)) {
+			cli.PrintErrln("fatal:", addMissingParam+" is not supported with a mapping file")
+			usage()
+			return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+		}
+	}
+
+	if apr.Contains(mappingPresetParam) && apr.Contains(mappingFileParam) {
+		cli.PrintErrln("fatal:", mappingPresetParam+" and "+mappingFileParam+" can't be used together")
+		usage()
+		return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+	}
+
+	if apr.Contains(saveMappingPresetParam) && !apr.Contains(mappingFileParam) {
+		cli.PrintErrln("fatal:", saveMappingPresetParam+" requires a mapping file")
+		usage()
+		return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+	}
+
+	checkpointing := apr.Contains(checkpointRowsParam) || apr.Contains(resumeParam)
+	if checkpointing && apr.Contains(parallelParam) {
+		cli.PrintErrln("fatal:", checkpointRowsParam+" and "+resumeParam+" are not supported with "+parallelParam)
+		usage()
+		return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+	}
+
+	if rows, ok := apr.GetUint(checkpointRowsParam); ok && rows == 0 {
+		cli.PrintErrln("fatal:", checkpointRowsParam+" must be greater than 0")
+		usage()
+		return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
 	}
 
 	tableName := apr.Arg(0)
@@ -191,7 +262,22 @@ func validateImportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter)
 			srcOpts = mvdata.JSONOptions{TableName: tableName}
 		}
 
+		if val.Format == mvdata.JsonFile && apr.Contains(addMissingParam) {
+			cli.PrintErrln(color.RedString("%s is not supported for json files", addMissingParam))
+			return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+		}
+
 	case mvdata.StreamDataLocation:
+		if apr.Contains(addMissingParam) {
+			cli.PrintErrln(color.RedString("%s is not supported when importing from stdin", addMissingParam))
+			return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+		}
+
+		if checkpointing {
+			cli.PrintErrln(color.RedString("%s and %s are not supported when importing from stdin", checkpointRowsParam, resumeParam))
+			return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+		}
+
 		if val.Format == mvdata.InvalidDataFormat {
 			val = mvdata.StreamDataLocation{Format: mvdata.CsvFile, Reader: os.Stdin, Writer: iohelp.NopWrCloser(cli.CliOut)}
 			srcLoc = val
@@ -208,19 +294,49 @@ func validateImportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter)
 		}
 	}
 
+	if apr.NArg() > 2 || filesys.HasGlobMeta(path) {
+		fileLoc, isFile := srcLoc.(mvdata.FileDataLocation)
+
+		if !isFile {
+			cli.PrintErrln(color.RedString("'%s' is not a file. Only files can be imported together.", path))
+			return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+		}
+
+		paths := apr.Args()[1:]
+		for _, otherPath := range paths[1:] {
+			otherLoc, isFile := mvdata.NewDataLocation(otherPath, fType).(mvdata.FileDataLocation)
+
+			if !isFile || (otherLoc.Format != fileLoc.Format && !(hasDelim && otherLoc.Format == mvdata.InvalidDataFormat)) {
+				cli.PrintErrln(color.RedString("'%s' is not a %s. Files imported together must all have the same type.", otherPath, fileLoc.Format.ReadableStr()))
+				return mvdata.InvalidOp, mvdata.TableDataLocation{}, nil, nil
+			}
+		}
+
+		srcLoc = mvdata.MultiFileDataLocation{Paths: paths, Format: fileLoc.Format, Parallel: apr.Contains(parallelParam)}
+	}
+
 	tableLoc := mvdata.TableDataLocation{Name: tableName}
 
 	return mvOp, tableLoc, srcLoc, srcOpts
 }
 
 func Import(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
-	force, mvOpts := parseCreateArgs(commandStr, args)
+	force, addMissing, savePreset, cpOpts, mvOpts := parseCreateArgs(commandStr, args)
 
 	if mvOpts == nil {
 		return 1
 	}
 
-	res := executeMove(ctx, dEnv, force, mvOpts)
+	res := executeMove(ctx, dEnv, force, addMissing, cpOpts, mvOpts)
+
+	if res == 0 && savePreset != "" {
+		verr := saveMappingPreset(ctx, dEnv, savePreset, mvOpts.MappingFile)
+
+		if verr != nil {
+			cli.PrintErrln(verr.Verbose())
+			return 1
+		}
+	}
 
 	if res == 0 {
 		cli.PrintErrln(color.CyanString("Import completed successfully."))
@@ -229,7 +345,7 @@ func Import(ctx context.Context, commandStr string, args []string, dEnv *env.Dol
 	return res
 }
 
-func parseCreateArgs(commandStr string, args []string) (bool, *mvdata.MoveOptions) {
+func parseCreateArgs(commandStr string, args []string) (bool, bool, string, *checkpointOptions, *mvdata.MoveOptions) {
 	ap := createArgParser()
 
 	help, usage := cli.HelpAndUsagePrinters(commandStr, importShortDesc, importLongDesc, importSynopsis, ap)
@@ -237,29 +353,38 @@ func parseCreateArgs(commandStr string, args []string) (bool, *mvdata.MoveOption
 	moveOp, tableLoc, fileLoc, srcOpts := validateImportArgs(apr, usage)
 
 	if fileLoc == nil || len(tableLoc.Name) == 0 {
-		return false, nil
+		return false, false, "", nil, nil
 	}
 
 	schemaFile, _ := apr.GetValue(outSchemaParam)
 	mappingFile, _ := apr.GetValue(mappingFileParam)
+	mappingPreset, _ := apr.GetValue(mappingPresetParam)
+	savePreset, _ := apr.GetValue(saveMappingPresetParam)
 	primaryKey, _ := apr.GetValue(primaryKeyParam)
 
-	return apr.Contains(forceParam), &mvdata.MoveOptions{
-		Operation:   moveOp,
-		ContOnErr:   apr.Contains(contOnErrParam),
-		SchFile:     schemaFile,
-		MappingFile: mappingFile,
-		PrimaryKey:  primaryKey,
-		Src:         fileLoc,
-		Dest:        tableLoc,
-		SrcOptions:  srcOpts,
+	var cpOpts *checkpointOptions
+	if apr.Contains(checkpointRowsParam) || apr.Contains(resumeParam) {
+		cpRows, _ := apr.GetUint(checkpointRowsParam)
+		cpOpts = &checkpointOptions{rows: int64(cpRows), resume: apr.Contains(resumeParam)}
+	}
+
+	return apr.Contains(forceParam), apr.Contains(addMissingParam), savePreset, cpOpts, &mvdata.MoveOptions{
+		Operation:     moveOp,
+		ContOnErr:     apr.Contains(contOnErrParam),
+		SchFile:       schemaFile,
+		MappingFile:   mappingFile,
+		MappingPreset: mappingPreset,
+		PrimaryKey:    primaryKey,
+		Src:           fileLoc,
+		Dest:          tableLoc,
+		SrcOptions:    srcOpts,
 	}
 }
 
 func createArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
 	ap.ArgListHelp[tableParam] = "The new or existing table being imported to."
-	ap.ArgListHelp[fileParam] = "The file being imported. Supported file types are csv, psv, and nbf."
+	ap.ArgListHelp[fileParam] = "The file being imported. Supported file types are csv, psv, xlsx and json. Several files, or glob patterns matching them, may be given to import them together."
 	ap.SupportsFlag(createParam, "c", "Create a new table, or overwrite an existing table (with the -f flag) from the imported data.")
 	ap.SupportsFlag(updateParam, "u", "Update an existing table with the imported data.")
 	ap.SupportsFlag(forceParam, "f", "If a create operation is being executed, data already exists in the destination, the Force flag will allow the target to be overwritten.")
@@ -267,9 +392,15 @@ func createArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(contOnErrParam, "", "Continue importing when row import errors are encountered.")
 	ap.SupportsString(outSchemaParam, "s", "schema_file", "The schema for the output data.")
 	ap.SupportsString(mappingFileParam, "m", "mapping_file", "A file that lays out how fields should be mapped from input data to output data.")
+	ap.SupportsString(mappingPresetParam, "", "name", "The name of a mapping stored in the dolt_mapping_presets table, used in place of a mapping file.")
+	ap.SupportsString(saveMappingPresetParam, "", "name", "Store the mapping file in the dolt_mapping_presets table under the given name once the import succeeds.")
 	ap.SupportsString(primaryKeyParam, "pk", "primary_key", "Explicitly define the name of the field in the schema which should be used as the primary key.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
 	ap.SupportsString(delimParam, "", "delimiter", "Specify a delimeter for a csv style file with a non-comma delimiter.")
+	ap.SupportsFlag(addMissingParam, "", "When updating or replacing a table, add the fields of the file which are not in the table's schema as new columns.")
+	ap.SupportsFlag(parallelParam, "", "When importing several files, read them concurrently. Rows are then imported in no particular order.")
+	ap.SupportsUint(checkpointRowsParam, "", "rows", "Checkpoint the progress of the import every <rows> rows, so that it can be resumed if it's interrupted.")
+	ap.SupportsFlag(resumeParam, "", "Resume an interrupted import from its last checkpoint.")
 	return ap
 }
 
@@ -282,7 +413,7 @@ func importStatsCB(stats types.AppliedEditStats) {
 	displayStrLen = cli.DeleteAndPrint(displayStrLen, displayStr)
 }
 
-func executeMove(ctx context.Context, dEnv *env.DoltEnv, force bool, mvOpts *mvdata.MoveOptions) int {
+func executeMove(ctx context.Context, dEnv *env.DoltEnv, force, addMissing bool, cpOpts *checkpointOptions, mvOpts *mvdata.MoveOptions) int {
 	root, err := dEnv.WorkingRoot(ctx)
 
 	if err != nil {
@@ -290,6 +421,41 @@ func executeMove(ctx context.Context, dEnv *env.DoltEnv, force bool, mvOpts *mvd
 		return 1
 	}
 
+	return executeMoveFromRoot(ctx, dEnv, root, force, addMissing, cpOpts, mvOpts)
+}
+
+// executeMoveFromRoot moves data as executeMove does, reading any tables of the move from the root given rather than
+// the working root. If checkpoint options are given, the move is an import whose progress is checkpointed.
+func executeMoveFromRoot(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, force, addMissing bool, cpOpts *checkpointOptions, mvOpts *mvdata.MoveOptions) int {
+	var err error
+	var cp *env.ImportCheckpoint
+	if cpOpts != nil {
+		var verr errhand.VerboseError
+		cp, root, verr = beginImportCheckpoint(ctx, dEnv, root, cpOpts, mvOpts)
+
+		if verr != nil {
+			cli.PrintErrln(verr.Verbose())
+			return 1
+		}
+	}
+
+	var addedCols []string
+	if cp != nil && cp.Root != "" {
+		addedCols = cp.AddedColumns
+	} else if addMissing {
+		root, addedCols, err = addMissingColumns(ctx, dEnv.DoltDB, root, dEnv.FS, mvOpts)
+
+		if err != nil {
+			bdr := errhand.BuildDError("Error adding the missing columns of %s to %s.", mvOpts.Src.String(), mvOpts.Dest.String())
+			cli.PrintErrln(bdr.AddCause(err).Build().Verbose())
+			return 1
+		}
+
+		if cp != nil {
+			cp.AddedColumns = addedCols
+		}
+	}
+
 	_, isStdOut := mvOpts.Dest.(mvdata.StreamDataLocation)
 	if !isStdOut && mvOpts.Operation == mvdata.OverwriteOp && !force {
 		if exists, err := mvOpts.Dest.Exists(ctx, root, dEnv.FS); err != nil {
@@ -301,19 +467,31 @@ func executeMove(ctx context.Context, dEnv *env.DoltEnv, force bool, mvOpts *mvd
 		}
 	}
 
-	if srcFileLoc, isFileType := mvOpts.Src.(mvdata.FileDataLocation); isFileType {
-		if srcFileLoc.Format == mvdata.SqlFile {
-			cli.Println(color.RedString("For SQL import, please pipe SQL input files to `dolt sql`"))
-			return 1
-		}
+	srcFormat := mvdata.InvalidDataFormat
+	switch src := mvOpts.Src.(type) {
+	case mvdata.FileDataLocation:
+		srcFormat = src.Format
+	case mvdata.MultiFileDataLocation:
+		srcFormat = src.Format
+	}
 
-		if srcFileLoc.Format == mvdata.JsonFile && mvOpts.Operation == mvdata.OverwriteOp && mvOpts.SchFile == "" {
-			cli.Println(color.RedString("Please specify schema file for .json tables."))
-			return 1
-		}
+	if srcFormat == mvdata.SqlFile {
+		cli.Println(color.RedString("For SQL import, please pipe SQL input files to `dolt sql`"))
+		return 1
+	}
+
+	if srcFormat == mvdata.JsonFile && mvOpts.Operation == mvdata.OverwriteOp && mvOpts.SchFile == "" {
+		cli.Println(color.RedString("Please specify schema file for .json tables."))
+		return 1
+	}
+
+	statsCB := importStatsCB
+	stats := &segmentStats{}
+	if cp != nil {
+		statsCB = stats.statsCB
 	}
 
-	mover, nDMErr := mvdata.NewDataMover(ctx, root, dEnv.FS, mvOpts, importStatsCB)
+	mover, nDMErr := mvdata.NewDataMover(ctx, root, dEnv.FS, mvOpts, statsCB)
 
 	if nDMErr != nil {
 		verr := newDataMoverErrToVerr(mvOpts, nDMErr)
@@ -322,7 +500,20 @@ func executeMove(ctx context.Context, dEnv *env.DoltEnv, force bool, mvOpts *mvd
 	}
 
 	var badCount int64
-	badCount, err = mover.Move(ctx)
+	if cp != nil {
+		if cp.Rows > 0 {
+			cli.PrintErrln(color.CyanString("Resuming the import after %d rows.", cp.Rows))
+		}
+
+		if err = skipImportedRows(ctx, mover.Rd, cp); err == nil {
+			badCount, err = moveWithCheckpoints(ctx, dEnv, root, mover, mvOpts, cp, stats)
+		} else {
+			mover.Rd.Close(ctx)
+			mover.Wr.Close(ctx)
+		}
+	} else {
+		badCount, err = mover.Move(ctx)
+	}
 
 	if displayStrLen > 0 {
 		displayStrLen = 0
@@ -348,6 +539,10 @@ func executeMove(ctx context.Context, dEnv *env.DoltEnv, force bool, mvOpts *mvd
 			cli.PrintErrln("An error occurred moving data:\n", err.Error())
 		}
 
+		if cp != nil && cp.Root != "" {
+			cli.PrintErrln(color.YellowString("The import can be resumed after the %d rows imported as of its last checkpoint by running the same command with --%s.", cp.Rows, resumeParam))
+		}
+
 		return 1
 	}
 
@@ -355,10 +550,35 @@ func executeMove(ctx context.Context, dEnv *env.DoltEnv, force bool, mvOpts *mvd
 		tableDest := mvOpts.Dest.(mvdata.TableDataLocation)
 		err = dEnv.PutTableToWorking(ctx, *nomsWr.GetMap(), nomsWr.GetSchema(), tableDest.Name)
 
-		if err != nil {
+		if err == doltdb.ErrAppendOnly {
+			cli.PrintErrln(color.RedString("%s is append-only. Rows can be added to it, but its existing rows and its schema can't be replaced.", tableDest.Name))
+			return 1
+		} else if err != nil {
 			cli.PrintErrln(color.RedString("Failed to update the working value."))
 			return 1
 		}
+
+		if len(addedCols) > 0 {
+			err = stageAddedColumns(ctx, dEnv, tableDest.Name, nomsWr.GetSchema(), addedCols)
+
+			if err != nil {
+				cli.PrintErrln(color.RedString("Failed to stage the columns added to %s.", tableDest.Name))
+				return 1
+			}
+		}
+
+		if cp != nil {
+			err = env.RemoveImportCheckpoint(dEnv.FS)
+
+			if err != nil {
+				cli.PrintErrln(color.RedString("Failed to remove the import checkpoint."))
+				return 1
+			}
+		}
+	}
+
+	for _, name := range addedCols {
+		cli.PrintErrln(color.YellowString("Added column: %s", name))
 	}
 
 	if badCount > 0 {
@@ -368,6 +588,82 @@ func executeMove(ctx context.Context, dEnv *env.DoltEnv, force bool, mvOpts *mvd
 	return 0
 }
 
+// addMissingColumns adds the fields of the source of the move which are not columns of the destination table to the
+// table's schema as nullable columns, with types inferred from the source's data. It returns the updated root and the
+// names of the columns added.
+func addMissingColumns(ctx context.Context, ddb *doltdb.DoltDB, root *doltdb.RootValue, fs filesys.ReadableFS, mvOpts *mvdata.MoveOptions) (*doltdb.RootValue, []string, error) {
+	tableName := mvOpts.Dest.(mvdata.TableDataLocation).Name
+	tbl, ok, err := root.GetTable(ctx, tableName)
+
+	if err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, doltdb.ErrTableNotFound
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rd, _, err := mvOpts.Src.NewReader(ctx, root, fs, mvOpts.SchFile, mvOpts.SrcOptions)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rd.Close(ctx)
+
+	inferredSch, err := actions.InferSchemaFromTableReader(ctx, rd, sch.GetPKCols().GetColumnNames(), &actions.InferenceArgs{
+		ExistingSch: sch,
+		ColMapper:   actions.IdentityMapper{},
+		KeepTypes:   true,
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var added []string
+	err = inferredSch.GetAllCols().Iter(func(_ uint64, col schema.Column) (stop bool, err error) {
+		if _, ok := sch.GetAllCols().GetByName(col.Name); ok {
+			return false, nil
+		}
+
+		tbl, err = alterschema.AddColumnToTable(ctx, ddb, tbl, schema.AutoGenerateTag(sch), col.Name, col.Kind, alterschema.Null, nil)
+
+		if err != nil {
+			return true, err
+		}
+
+		sch, err = tbl.GetSchema(ctx)
+
+		if err != nil {
+			return true, err
+		}
+
+		added = append(added, col.Name)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(added) == 0 {
+		return root, nil, nil
+	}
+
+	root, err = root.PutTable(ctx, tableName, tbl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return root, added, nil
+}
+
 func newDataMoverErrToVerr(mvOpts *mvdata.MoveOptions, err *mvdata.DataMoverCreationError) errhand.VerboseError {
 	switch err.ErrType {
 	case mvdata.CreateReaderErr:
@@ -390,7 +686,11 @@ func newDataMoverErrToVerr(mvOpts *mvdata.MoveOptions, err *mvdata.DataMoverCrea
 	case mvdata.MappingErr:
 		bdr := errhand.BuildDError("Error determining the mapping from input fields to output fields.")
 		bdr.AddDetails("When attempting to move data from %s to %s, determine the mapping from input fields t, output fields.", mvOpts.Src.String(), mvOpts.Dest.String())
-		bdr.AddDetails(`Mapping File: "%s"`, mvOpts.MappingFile)
+		if mvOpts.MappingPreset != "" {
+			bdr.AddDetails(`Mapping Preset: "%s"`, mvOpts.MappingPreset)
+		} else {
+			bdr.AddDetails(`Mapping File: "%s"`, mvOpts.MappingFile)
+		}
 		return bdr.AddCause(err.Cause).Build()
 
 	case mvdata.ReplacingErr:
@@ -427,3 +727,82 @@ func newDataMoverErrToVerr(mvOpts *mvdata.MoveOptions, err *mvdata.DataMoverCrea
 
 	panic("Unhandled Error type")
 }
+
+// stageAddedColumns adds the columns added to the working schema of a table by addMissingColumns to the staged version
+// of the table, so that the schema change is staged separately from the imported rows. Nothing is staged for tables
+// which aren't staged themselves.
+func stageAddedColumns(ctx context.Context, dEnv *env.DoltEnv, tableName string, workingSch schema.Schema, addedCols []string) error {
+	root, err := dEnv.StagedRoot(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	tbl, ok, err := root.GetTable(ctx, tableName)
+
+	if err != nil || !ok {
+		return err
+	}
+
+	for _, name := range addedCols {
+		sch, err := tbl.GetSchema(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		col, _ := workingSch.GetAllCols().GetByName(name)
+
+		if _, ok := sch.GetAllCols().GetByName(name); ok {
+			continue
+		} else if _, ok := sch.GetAllCols().GetByTag(col.Tag); ok {
+			continue
+		}
+
+		tbl, err = alterschema.AddColumnToTable(ctx, dEnv.DoltDB, tbl, col.Tag, col.Name, col.Kind, alterschema.Null, nil)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	root, err = root.PutTable(ctx, tableName, tbl)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = dEnv.UpdateStagedRoot(ctx, root)
+	return err
+}
+
+// saveMappingPreset stores the contents of the mapping file given in the working root's mapping presets table under the
+// name given, replacing any preset with the same name.
+func saveMappingPreset(ctx context.Context, dEnv *env.DoltEnv, name, mappingFile string) errhand.VerboseError {
+	data, err := dEnv.FS.ReadFile(mappingFile)
+
+	if err != nil {
+		return errhand.BuildDError("Failed to read the mapping file %s.", mappingFile).AddCause(err).Build()
+	}
+
+	root, err := dEnv.WorkingRoot(ctx)
+
+	if err != nil {
+		return errhand.BuildDError("Unable to get the working root.").AddCause(err).Build()
+	}
+
+	root, err = root.PutMappingPreset(ctx, name, string(data))
+
+	if err != nil {
+		return errhand.BuildDError("Failed to save the mapping preset '%s'.", name).AddCause(err).Build()
+	}
+
+	err = dEnv.UpdateWorkingRoot(ctx, root)
+
+	if err != nil {
+		return errhand.BuildDError("Failed to update the working value.").AddCause(err).Build()
+	}
+
+	cli.PrintErrln(color.CyanString("Saved mapping preset '%s'.", name))
+	return nil
+}
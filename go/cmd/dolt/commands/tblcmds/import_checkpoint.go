@@ -0,0 +1,231 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tblcmds
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/mvdata"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// checkpointOptions are the options of an import which checkpoints its progress.
+type checkpointOptions struct {
+	// rows is the number of rows read between checkpoints, or 0 to use the interval of the import being resumed
+	rows int64
+	// resume is true if the import continues from the checkpoint of an earlier import rather than starting over
+	resume bool
+}
+
+var errShortResumeFile = errors.New("the files being imported have fewer rows than had already been imported")
+
+// beginImportCheckpoint returns the checkpoint to be kept by the import given, along with the root the import should
+// start from. When resuming, this is the root of the existing checkpoint, which must be for the same table and files,
+// and the move is changed to update the table with the remaining rows.
+func beginImportCheckpoint(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, cpOpts *checkpointOptions, mvOpts *mvdata.MoveOptions) (*env.ImportCheckpoint, *doltdb.RootValue, errhand.VerboseError) {
+	tableName := mvOpts.Dest.(mvdata.TableDataLocation).Name
+	files := importFiles(mvOpts.Src)
+
+	if !cpOpts.resume {
+		cp := &env.ImportCheckpoint{Table: tableName, Operation: string(mvOpts.Operation), Files: files, Interval: cpOpts.rows}
+		return cp, root, nil
+	}
+
+	cp, ok, err := env.LoadImportCheckpoint(dEnv.FS)
+
+	if err != nil {
+		return nil, nil, errhand.BuildDError("Failed to read the import checkpoint.").AddCause(err).Build()
+	} else if !ok {
+		return nil, nil, errhand.BuildDError("There is no interrupted import to resume.").Build()
+	}
+
+	if cp.Table != tableName || cp.Operation != string(mvOpts.Operation) || !stringsEqual(cp.Files, files) {
+		bdr := errhand.BuildDError("The interrupted import was a different import.")
+		bdr.AddDetails("It imported %s into table %s, and can only be resumed by the same command.", strings.Join(cp.Files, " "), cp.Table)
+		return nil, nil, bdr.Build()
+	}
+
+	if cpOpts.rows > 0 {
+		cp.Interval = cpOpts.rows
+	}
+
+	if cp.Root != "" {
+		root, err = dEnv.DoltDB.ReadRootValue(ctx, cp.RootHash())
+
+		if err != nil {
+			return nil, nil, errhand.BuildDError("Failed to read the rows imported as of the checkpoint.").AddCause(err).Build()
+		}
+
+		mvOpts.Operation = mvdata.UpdateOp
+	}
+
+	return cp, root, nil
+}
+
+// importFiles returns the paths of the files read by the source given.
+func importFiles(src mvdata.DataLocation) []string {
+	switch src := src.(type) {
+	case mvdata.FileDataLocation:
+		return []string{src.Path}
+	case mvdata.MultiFileDataLocation:
+		return src.Paths
+	}
+
+	return nil
+}
+
+func stringsEqual(strs1, strs2 []string) bool {
+	if len(strs1) != len(strs2) {
+		return false
+	}
+
+	for i := range strs1 {
+		if strs1[i] != strs2[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// skipImportedRows reads the rows which had been imported as of the checkpoint given from the reader given.
+func skipImportedRows(ctx context.Context, rd table.TableReader, cp *env.ImportCheckpoint) error {
+	for i := int64(0); i < cp.Rows; i++ {
+		_, err := rd.ReadRow(ctx)
+
+		if err == io.EOF {
+			return errShortResumeFile
+		} else if err != nil && !table.IsBadRow(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moveWithCheckpoints moves the rows of the mover given in segments of the checkpoint's interval. After each segment
+// but the last, the table with the rows moved so far is written to the database as part of a root which isn't
+// referenced by any branch, and the checkpoint is updated to refer to that root and saved. The mover's writer is left
+// holding the complete table once the last segment has been moved.
+func moveWithCheckpoints(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, mover *mvdata.DataMover, mvOpts *mvdata.MoveOptions, cp *env.ImportCheckpoint, stats *segmentStats) (int64, error) {
+	tableName := mvOpts.Dest.(mvdata.TableDataLocation).Name
+	rd := mover.Rd
+	defer rd.Close(ctx)
+
+	badCount := cp.BadRows
+	for {
+		segRd := &segmentReader{TableReadCloser: rd, limit: cp.Interval}
+		mover.Rd = segRd
+
+		segBadCount, err := mover.Move(ctx)
+
+		if err != nil {
+			return badCount, err
+		}
+
+		badCount += segBadCount
+
+		if segRd.eof {
+			return badCount, nil
+		}
+
+		nomsWr := mover.Wr.(noms.NomsMapWriteCloser)
+		root, err = dEnv.PutTableToRoot(ctx, root, *nomsWr.GetMap(), nomsWr.GetSchema(), tableName)
+
+		if err != nil {
+			return badCount, err
+		}
+
+		h, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+
+		if err != nil {
+			return badCount, err
+		}
+
+		cp.Rows += segRd.read
+		cp.BadRows = badCount
+		cp.Root = h.String()
+		err = cp.Save(dEnv.FS)
+
+		if err != nil {
+			return badCount, err
+		}
+
+		stats.nextSegment()
+		mover.Wr, err = mvOpts.Dest.NewUpdatingWriter(ctx, mvOpts, root, dEnv.FS, false, nomsWr.GetSchema(), stats.statsCB)
+
+		if err != nil {
+			return badCount, err
+		}
+	}
+}
+
+// segmentReader reads at most limit rows from the reader it wraps before returning io.EOF, so that the rows of a
+// reader can be moved in several segments. Bad rows count towards the limit. Closing it doesn't close the reader it
+// wraps.
+type segmentReader struct {
+	table.TableReadCloser
+	limit int64
+	read  int64
+	eof   bool
+}
+
+// ReadRow reads a row from the wrapped reader, or returns io.EOF if the limit has been reached.
+func (rd *segmentReader) ReadRow(ctx context.Context) (row.Row, error) {
+	if rd.read >= rd.limit {
+		return nil, io.EOF
+	}
+
+	r, err := rd.TableReadCloser.ReadRow(ctx)
+
+	if err == io.EOF {
+		rd.eof = true
+		return nil, err
+	} else if err == nil || table.IsBadRow(err) {
+		rd.read++
+	}
+
+	return r, err
+}
+
+// Close does nothing, leaving the wrapped reader open for the next segment.
+func (rd *segmentReader) Close(ctx context.Context) error {
+	return nil
+}
+
+// segmentStats reports the stats of the writers of each segment of a move as the running totals of the whole move.
+type segmentStats struct {
+	prev types.AppliedEditStats
+	cur  types.AppliedEditStats
+}
+
+func (s *segmentStats) statsCB(stats types.AppliedEditStats) {
+	s.cur = stats
+	importStatsCB(s.prev.Add(stats))
+}
+
+func (s *segmentStats) nextSegment() {
+	s.prev = s.prev.Add(s.cur)
+	s.cur = types.AppliedEditStats{}
+}
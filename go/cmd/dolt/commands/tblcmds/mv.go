@@ -20,7 +20,9 @@ import (
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/alterschema"
 	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
 )
 
@@ -30,9 +32,9 @@ The dolt table mv command will rename a table. If a table exists with the target
 fail unless the <b>--force|-f</b> flag is provided.  In that case the table at the target location will be overwritten 
 by the table being renamed.
 
-The result is equivalent of running <b>dolt table cp <old> <new></b> followed by <b>dolt table rm <old></b>, resulting 
-in a new table and a deleted table in the working set. These changes can be staged using <b>dolt add</b> and committed
-using <b>dolt commit</b>.`
+This is the same as running <b>RENAME TABLE <old> TO <new></b> with <b>dolt sql</b>. The table keeps its contents, so 
+<b>dolt status</b> and <b>dolt diff</b> report it as renamed. The change can be staged by running <b>dolt add</b> with 
+both table names and committed using <b>dolt commit</b>.`
 
 var tblMvSynopsis = []string{
 	"[-f] <oldtable> <newtable>",
@@ -57,38 +59,31 @@ func Mv(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv
 	if verr == nil {
 		old := apr.Arg(0)
 		new := apr.Arg(1)
-		if verr == nil {
-			tbl, ok, err := working.GetTable(ctx, old)
+
+		if has, err := working.HasTable(ctx, new); err != nil {
+			verr = errhand.BuildDError("error: failed to read tables from working set").AddCause(err).Build()
+		} else if has && force && old != new {
+			working, err = working.RemoveTables(ctx, new)
 
 			if err != nil {
-				verr = errhand.BuildDError("").Build()
-				return commands.HandleVErrAndExitCode(verr, usage)
+				verr = errhand.BuildDError("Unable to remove '%s'", new).Build()
 			}
+		}
 
-			if ok {
-				has, err := working.HasTable(ctx, new)
-
-				if err != nil {
-					verr = errhand.BuildDError("error: failed to read tables from working set").AddCause(err).Build()
-				} else if !force && has {
-					verr = errhand.BuildDError("Data already exists in '%s'.  Use -f to overwrite.", new).Build()
-				} else {
-					working, err = working.PutTable(ctx, new, tbl)
-
-					if err != nil {
-						verr = errhand.BuildDError("error: failed to write table back to database").AddCause(err).Build()
-					} else {
-						working, err := working.RemoveTables(ctx, old)
+		if verr == nil {
+			working, err := alterschema.RenameTable(ctx, dEnv.DoltDB, working, old, new)
 
-						if err != nil {
-							verr = errhand.BuildDError("Unable to remove '%s'", old).Build()
-						} else {
-							verr = commands.UpdateWorkingWithVErr(dEnv, working)
-						}
-					}
-				}
-			} else {
+			switch err {
+			case nil:
+				verr = commands.UpdateWorkingWithVErr(dEnv, working)
+			case doltdb.ErrTableNotFound:
 				verr = errhand.BuildDError("Table '%s' not found.", old).Build()
+			case doltdb.ErrTableExists:
+				verr = errhand.BuildDError("Data already exists in '%s'.  Use -f to overwrite.", new).Build()
+			case doltdb.ErrInvTableName:
+				verr = errhand.BuildDError("'%s' is not a valid table name", new).Build()
+			default:
+				verr = errhand.BuildDError("error: failed to write table back to database").AddCause(err).Build()
 			}
 		}
 	}
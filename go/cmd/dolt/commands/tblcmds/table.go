@@ -29,4 +29,6 @@ var Commands = cli.GenSubCommandHandler([]*cli.Command{
 	{Name: "select", Desc: "Print a selection of a table.", Func: Select, ReqRepo: true, EventType: eventsapi.ClientEventType_TABLE_SELECT},
 	{Name: "put-row", Desc: "Add a row to a table.", Func: PutRow, ReqRepo: true, EventType: eventsapi.ClientEventType_TABLE_PUT_ROW},
 	{Name: "rm-row", Desc: "Remove a row from a table.", Func: RmRow, ReqRepo: true, EventType: eventsapi.ClientEventType_TABLE_RM_ROW},
+	{Name: "append-only", Desc: "Makes tables append-only, or lists append-only tables.", Func: AppendOnly, ReqRepo: true},
+	{Name: "dedupe", Desc: "Finds or removes duplicate rows of a table.", Func: Dedupe, ReqRepo: true},
 })
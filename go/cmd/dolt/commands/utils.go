@@ -108,6 +108,8 @@ func ResolveCommitWithVErr(dEnv *env.DoltEnv, cSpecStr, cwb string) (*doltdb.Com
 			return nil, errhand.BuildDError("'%s' not found", cSpecStr).Build()
 		} else if err == doltdb.ErrFoundHashNotACommit {
 			return nil, errhand.BuildDError("'%s' is not a commit", cSpecStr).Build()
+		} else if err == doltdb.ErrAmbiguousHashPrefix {
+			return nil, errhand.BuildDError("'%s' is the start of more than one commit hash", cSpecStr).Build()
 		} else {
 			return nil, errhand.BuildDError("Unexpected error resolving '%s'", cSpecStr).AddCause(err).Build()
 		}
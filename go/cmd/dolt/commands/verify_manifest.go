@@ -0,0 +1,142 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/creds"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/integrity"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+const keyIDParam = "key-id"
+
+var verifyManifestShortDesc = "Check exported tables against the integrity manifest of a commit"
+var verifyManifestLongDesc = "Checks that the tables exported to <directory> have the data of the commit described by " +
+	"<manifest>, which dolt commit --write-integrity-manifest writes. It doesn't need the repository the commit was " +
+	"made in.\n" +
+	"\n" +
+	"The signature of the manifest is checked first. It only shows that the manifest hasn't changed since it was " +
+	"signed by the key it names, so the key id printed, or given with --key-id, should be checked against that of " +
+	"the publisher.\n" +
+	"\n" +
+	"Each table is then looked for in <directory> as <table>.csv or <table>.psv, as dolt table export writes it, and " +
+	"the hash of its rows is compared with that in the manifest. Its columns may be in any order, but its rows must " +
+	"be in the order they were exported in. Only the tables named are checked, if any are.\n" +
+	"\n" +
+	"The exit code is 0 if the signature and every table checked are valid, and 1 otherwise."
+var verifyManifestSynopsis = []string{
+	"[--key-id <id>] <manifest> <directory> [<table>...]",
+}
+
+func VerifyManifest(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["manifest"] = "The manifest written by dolt commit --write-integrity-manifest."
+	ap.ArgListHelp["directory"] = "The directory the tables were exported to."
+	ap.ArgListHelp["table"] = "The tables to check. Defaults to every table in the manifest."
+	ap.SupportsString(keyIDParam, "", "id", "The id or public key of the key the manifest must be signed with.")
+	help, usage := cli.HelpAndUsagePrinters(commandStr, verifyManifestShortDesc, verifyManifestLongDesc, verifyManifestSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() < 2 {
+		usage()
+		return 1
+	}
+
+	m, err := integrity.Load(dEnv.FS, apr.Arg(0))
+
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: failed to read the manifest").AddCause(err).Build(), usage)
+	}
+
+	tms := m.Tables
+	if apr.NArg() > 2 {
+		tms = nil
+		for _, tblName := range apr.Args()[2:] {
+			tm, ok := m.Table(tblName)
+
+			if !ok {
+				return HandleVErrAndExitCode(errhand.BuildDError("error: the manifest has no table '%s'", tblName).Build(), usage)
+			}
+
+			tms = append(tms, tm)
+		}
+	}
+
+	if err := m.VerifySignature(); err != nil {
+		cli.PrintErrln("error:", err.Error())
+		return 1
+	}
+
+	if keyID, ok := apr.GetValue(keyIDParam); ok {
+		if len(keyID) == creds.B32EncodedPubKeyLen {
+			keyID, _ = creds.PubKeyStrToKIDStr(keyID)
+		}
+
+		if keyID != m.KeyID {
+			cli.PrintErrf("error: the manifest is signed with key %s, not %s\n", m.KeyID, apr.MustGetValue(keyIDParam))
+			return 1
+		}
+	}
+
+	cli.Printf("manifest of commit %s signed with key %s\n", m.Commit, m.KeyID)
+
+	valid := true
+	for _, tm := range tms {
+		path, err := verifyExportedTable(ctx, dEnv.FS, apr.Arg(1), tm)
+
+		if err != nil {
+			valid = false
+			cli.Printf("FAILED\t%s\t%s\n", tm.Name, err.Error())
+		} else {
+			cli.Printf("ok\t%s\t%s\n", tm.Name, path)
+		}
+	}
+
+	if !valid {
+		return 1
+	}
+
+	return 0
+}
+
+// verifyExportedTable checks the export of the table given in the directory given, and returns its path.
+func verifyExportedTable(ctx context.Context, fs filesys.ReadableFS, dir string, tm integrity.TableManifest) (string, error) {
+	for _, export := range []struct {
+		ext   string
+		delim string
+	}{{".csv", ","}, {".psv", "|"}} {
+		path := filepath.Join(dir, tm.Name+export.ext)
+
+		if exists, isDir := fs.Exists(path); !exists || isDir {
+			continue
+		}
+
+		if err := tm.VerifyCSV(ctx, fs, path, export.delim); err != nil {
+			return "", fmt.Errorf("%s %v", path, err)
+		}
+
+		return path, nil
+	}
+
+	return "", fmt.Errorf("no %s.csv or %[1]s.psv in %s", tm.Name, dir)
+}
@@ -0,0 +1,291 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/argparser"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+var verifyRemoteShortDesc = "Compare a local branch with the same branch in a remote"
+var verifyRemoteLongDesc = "Compares the head commit of the local <branch> with the head commit of the branch of the " +
+	"same name in <remote>, to find out whether a push has completed or the two have diverged. No data is fetched.\n" +
+	"\n" +
+	"When the heads differ, the command reports whether one branch is ahead of the other or they have diverged, and " +
+	"lists the tables that differ between the two commits. For each table, the chunks making up its data are checked " +
+	"against the other database, and the number missing from it is reported, which shows what a push or fetch has " +
+	"left to transfer, including the chunks left out by a push that was interrupted.\n" +
+	"\n" +
+	"The exit code is 0 if the heads are the same and 1 otherwise."
+var verifyRemoteSynopsis = []string{
+	"<remote> <branch>",
+}
+
+func VerifyRemote(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp["remote"] = "The name of the remote to compare with."
+	ap.ArgListHelp["branch"] = "The name of the branch to compare."
+	help, usage := cli.HelpAndUsagePrinters(commandStr, verifyRemoteShortDesc, verifyRemoteLongDesc, verifyRemoteSynopsis, ap)
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 2 {
+		usage()
+		return 1
+	}
+
+	remotes, err := dEnv.GetRemotes()
+
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: failed to read remotes from config.").AddCause(err).Build(), usage)
+	}
+
+	remote, ok := remotes[apr.Arg(0)]
+
+	if !ok {
+		return HandleVErrAndExitCode(errhand.BuildDError("error: unknown remote '%s'", apr.Arg(0)).Build(), usage)
+	}
+
+	same, verr := verifyRemoteBranch(ctx, dEnv, remote, apr.Arg(1))
+
+	if verr != nil {
+		return HandleVErrAndExitCode(verr, usage)
+	} else if !same {
+		return 1
+	}
+
+	return 0
+}
+
+// verifyRemoteBranch compares the branch given with the branch of the same name in the remote given, printing how they
+// differ, and returns whether they have the same head.
+func verifyRemoteBranch(ctx context.Context, dEnv *env.DoltEnv, remote env.Remote, branch string) (bool, errhand.VerboseError) {
+	dref := ref.NewBranchRef(branch)
+	localCm, err := resolveBranchHead(ctx, dEnv.DoltDB, dref)
+
+	if err == doltdb.ErrBranchNotFound {
+		return false, errhand.BuildDError("error: unknown branch '%s'", branch).Build()
+	} else if err != nil {
+		return false, errhand.BuildDError("error: failed to resolve '%s'", branch).AddCause(err).Build()
+	}
+
+	remoteDB, err := remote.GetRemoteDB(ctx, dEnv.DoltDB.ValueReadWriter().Format())
+
+	if err != nil {
+		return false, errhand.BuildDError("error: failed to get remote db").AddCause(err).Build()
+	}
+
+	remoteCm, err := resolveBranchHead(ctx, remoteDB, dref)
+
+	if err == doltdb.ErrBranchNotFound {
+		return false, errhand.BuildDError("error: '%s' has no branch '%s'", remote.Name, branch).Build()
+	} else if err != nil {
+		return false, errhand.BuildDError("error: failed to resolve '%s' on '%s'", branch, remote.Name).AddCause(err).Build()
+	}
+
+	localHash, err := localCm.HashOf()
+
+	if err != nil {
+		return false, errhand.BuildDError("error: failed to hash commit").AddCause(err).Build()
+	}
+
+	remoteHash, err := remoteCm.HashOf()
+
+	if err != nil {
+		return false, errhand.BuildDError("error: failed to hash commit").AddCause(err).Build()
+	}
+
+	cli.Printf("local\t%s\t%s\n", localHash.String(), branch)
+	cli.Printf("%s\t%s\t%s\n", remote.Name, remoteHash.String(), branch)
+
+	if localHash == remoteHash {
+		cli.Printf("'%s' is up to date with '%s'\n", branch, remote.Name)
+		return true, nil
+	}
+
+	cli.Println(describeBranchDivergence(ctx, dEnv.DoltDB, remoteDB, localHash, remoteHash, branch, remote.Name))
+
+	verr := printTableChunkDiffs(ctx, dEnv.DoltDB, remoteDB, localCm, remoteCm, remote.Name)
+
+	if verr != nil {
+		return false, verr
+	}
+
+	return false, nil
+}
+
+// resolveBranchHead returns the head commit of the branch given, or doltdb.ErrBranchNotFound if there's no such branch.
+func resolveBranchHead(ctx context.Context, ddb *doltdb.DoltDB, dref ref.DoltRef) (*doltdb.Commit, error) {
+	hasRef, err := ddb.HasRef(ctx, dref)
+
+	if err != nil {
+		return nil, err
+	} else if !hasRef {
+		return nil, doltdb.ErrBranchNotFound
+	}
+
+	cs, err := doltdb.NewCommitSpec("HEAD", dref.String())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ddb.Resolve(ctx, cs)
+}
+
+// describeBranchDivergence describes how the heads of the local and remote branches given are related. Their ancestry
+// is compared in whichever database has both of them. When neither does, or it can't be read, they are only known to
+// differ.
+func describeBranchDivergence(ctx context.Context, localDB, remoteDB *doltdb.DoltDB, localHash, remoteHash hash.Hash, branch, remoteName string) string {
+	for _, ddb := range []*doltdb.DoltDB{localDB, remoteDB} {
+		localCm, err := resolveHash(ctx, ddb, localHash)
+
+		if err != nil {
+			continue
+		}
+
+		remoteCm, err := resolveHash(ctx, ddb, remoteHash)
+
+		if err != nil {
+			continue
+		}
+
+		canFF, err := localCm.CanFastForwardTo(ctx, remoteCm)
+
+		if err == doltdb.ErrIsAhead {
+			return fmt.Sprintf("'%s' has commits that haven't been pushed to '%s'", branch, remoteName)
+		} else if err != nil {
+			break
+		} else if canFF {
+			return fmt.Sprintf("'%s' on '%s' has commits that haven't been fetched", branch, remoteName)
+		}
+
+		return fmt.Sprintf("'%s' and '%s' on '%s' have diverged", branch, branch, remoteName)
+	}
+
+	if _, err := resolveHash(ctx, localDB, remoteHash); err != nil {
+		return fmt.Sprintf("'%s' on '%s' is at a commit that hasn't been fetched", branch, remoteName)
+	}
+
+	return fmt.Sprintf("'%s' and '%s' on '%s' are at different commits", branch, branch, remoteName)
+}
+
+func resolveHash(ctx context.Context, ddb *doltdb.DoltDB, h hash.Hash) (*doltdb.Commit, error) {
+	cs, err := doltdb.NewCommitSpec(h.String(), "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ddb.Resolve(ctx, cs)
+}
+
+// printTableChunkDiffs prints the tables that differ between the local and remote commits given, along with how many of
+// the chunks of each table are missing from the other database.
+func printTableChunkDiffs(ctx context.Context, localDB, remoteDB *doltdb.DoltDB, localCm, remoteCm *doltdb.Commit, remoteName string) errhand.VerboseError {
+	localRoot, err := localCm.GetRootValue()
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to read the local commit").AddCause(err).Build()
+	}
+
+	remoteRoot, err := remoteCm.GetRootValue()
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to read the commit on '%s'", remoteName).AddCause(err).Build()
+	}
+
+	added, modified, removed, err := localRoot.TableDiff(ctx, remoteRoot)
+
+	if err != nil {
+		return errhand.BuildDError("error: failed to compare tables").AddCause(err).Build()
+	}
+
+	if len(added)+len(modified)+len(removed) == 0 {
+		cli.Println("the tables of both commits are the same")
+		return nil
+	}
+
+	status := make(map[string]string)
+	for _, tblName := range added {
+		status[tblName] = "only local:"
+	}
+	for _, tblName := range modified {
+		status[tblName] = "modified:"
+	}
+	for _, tblName := range removed {
+		status[tblName] = fmt.Sprintf("only on %s:", remoteName)
+	}
+
+	tblNames := make([]string, 0, len(status))
+	for tblName := range status {
+		tblNames = append(tblNames, tblName)
+	}
+	sort.Strings(tblNames)
+
+	cli.Println("tables that differ:")
+	for _, tblName := range tblNames {
+		var counts []string
+
+		localCount, verr := tableChunkCount(ctx, localRoot, localDB, remoteDB, tblName)
+
+		if verr != nil {
+			return verr
+		} else if localCount != "" {
+			counts = append(counts, fmt.Sprintf("%s local chunks missing from %s", localCount, remoteName))
+		}
+
+		remoteCount, verr := tableChunkCount(ctx, remoteRoot, remoteDB, localDB, tblName)
+
+		if verr != nil {
+			return verr
+		} else if remoteCount != "" {
+			counts = append(counts, fmt.Sprintf("%s chunks on %s missing locally", remoteCount, remoteName))
+		}
+
+		cli.Printf("\t%-16s %s (%s)\n", status[tblName], tblName, strings.Join(counts, ", "))
+	}
+
+	return nil
+}
+
+// tableChunkCount returns how many of the chunks of the table given in root, which is stored in ddb, are missing from
+// otherDB, as "missing of total". It returns an empty string if the table isn't in root.
+func tableChunkCount(ctx context.Context, root *doltdb.RootValue, ddb, otherDB *doltdb.DoltDB, tblName string) (string, errhand.VerboseError) {
+	h, ok, err := root.GetTableHash(ctx, tblName)
+
+	if err != nil {
+		return "", errhand.BuildDError("error: failed to read table '%s'", tblName).AddCause(err).Build()
+	} else if !ok {
+		return "", nil
+	}
+
+	total, missing, err := ddb.CountMissingChunks(ctx, h, otherDB)
+
+	if err != nil {
+		return "", errhand.BuildDError("error: failed to check the chunks of table '%s'", tblName).AddCause(err).Build()
+	}
+
+	return fmt.Sprintf("%d of %d", missing, total), nil
+}
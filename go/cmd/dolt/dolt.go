@@ -19,15 +19,20 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/pkg/profile"
 
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/cli"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands/admincmds"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands/automationcmds"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands/cnfcmds"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands/credcmds"
+	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands/docscmds"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands/schcmds"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands/sqlserver"
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/commands/tblcmds"
@@ -43,6 +48,9 @@ const (
 	Version = "0.12.0"
 )
 
+// commandLogFile is the name of the file in the global dolt directory that commands are recorded to by default.
+const commandLogFile = "command_log.jsonl"
+
 var doltCommand = cli.GenSubCommandHandler([]*cli.Command{
 	{Name: "init", Desc: "Create an empty Dolt data repository.", Func: commands.Init, ReqRepo: false, EventType: eventsapi.ClientEventType_INIT},
 	{Name: "status", Desc: "Show the working tree status.", Func: commands.Status, ReqRepo: true, EventType: eventsapi.ClientEventType_STATUS},
@@ -50,10 +58,11 @@ var doltCommand = cli.GenSubCommandHandler([]*cli.Command{
 	{Name: "reset", Desc: "Remove table changes from the list of staged table changes.", Func: commands.Reset, ReqRepo: true, EventType: eventsapi.ClientEventType_RESET},
 	{Name: "commit", Desc: "Record changes to the repository.", Func: commands.Commit, ReqRepo: true, EventType: eventsapi.ClientEventType_COMMIT},
 	{Name: "sql", Desc: "Run a SQL query against tables in repository.", Func: commands.Sql, ReqRepo: true, EventType: eventsapi.ClientEventType_SQL},
-	{Name: "sql-server", Desc: "Starts a MySQL-compatible server.", Func: sqlserver.SqlServer, ReqRepo: true, EventType: eventsapi.ClientEventType_SQL_SERVER},
+	{Name: "sql-server", Desc: "Starts a MySQL-compatible server.", Func: sqlserver.SqlServer, ReqRepo: false, EventType: eventsapi.ClientEventType_SQL_SERVER},
 	{Name: "log", Desc: "Show commit logs.", Func: commands.Log, ReqRepo: true, EventType: eventsapi.ClientEventType_LOG},
 	{Name: "diff", Desc: "Diff a table.", Func: commands.Diff, ReqRepo: true, EventType: eventsapi.ClientEventType_DIFF},
 	{Name: "blame", Desc: "Show what revision and author last modified each row of a table.", Func: commands.Blame, ReqRepo: true, EventType: eventsapi.ClientEventType_BLAME},
+	{Name: "grep", Desc: "Search table cell values for a pattern.", Func: commands.Grep, ReqRepo: true},
 	{Name: "merge", Desc: "Merge a branch.", Func: commands.Merge, ReqRepo: true, EventType: eventsapi.ClientEventType_MERGE},
 	{Name: "branch", Desc: "Create, list, edit, delete branches.", Func: commands.Branch, ReqRepo: true, EventType: eventsapi.ClientEventType_BRANCH},
 	{Name: "checkout", Desc: "Checkout a branch or overwrite a table from HEAD.", Func: commands.Checkout, ReqRepo: true, EventType: eventsapi.ClientEventType_CHECKOUT},
@@ -61,6 +70,9 @@ var doltCommand = cli.GenSubCommandHandler([]*cli.Command{
 	{Name: "push", Desc: "Push to a dolt remote.", Func: commands.Push, ReqRepo: true, EventType: eventsapi.ClientEventType_PUSH},
 	{Name: "pull", Desc: "Fetch from a dolt remote data repository and merge.", Func: commands.Pull, ReqRepo: true, EventType: eventsapi.ClientEventType_PULL},
 	{Name: "fetch", Desc: "Update the database from a remote data repository.", Func: commands.Fetch, ReqRepo: true, EventType: eventsapi.ClientEventType_FETCH},
+	{Name: "verify-remote", Desc: "Compare a local branch with the same branch in a remote.", Func: commands.VerifyRemote, ReqRepo: true},
+	{Name: "verify-manifest", Desc: "Check exported tables against the integrity manifest of a commit.", Func: commands.VerifyManifest, ReqRepo: false},
+	{Name: "ls-remote", Desc: "List references in a remote repository.", Func: commands.LsRemote, ReqRepo: false},
 	{Name: "clone", Desc: "Clone from a remote data repository.", Func: commands.Clone, ReqRepo: false, EventType: eventsapi.ClientEventType_CLONE},
 	{Name: "creds", Desc: "Commands for managing credentials.", Func: credcmds.Commands, ReqRepo: false},
 	{Name: "login", Desc: "Login to a dolt remote host.", Func: commands.Login, ReqRepo: false, EventType: eventsapi.ClientEventType_LOGIN},
@@ -70,6 +82,9 @@ var doltCommand = cli.GenSubCommandHandler([]*cli.Command{
 	{Name: "schema", Desc: "Commands for showing, and modifying table schemas.", Func: schcmds.Commands, ReqRepo: true, EventType: eventsapi.ClientEventType_SCHEMA},
 	{Name: "table", Desc: "Commands for creating, reading, updating, and deleting tables.", Func: tblcmds.Commands, ReqRepo: false},
 	{Name: "conflicts", Desc: "Commands for viewing and resolving merge conflicts.", Func: cnfcmds.Commands, ReqRepo: false},
+	{Name: "docs", Desc: "Commands for reading and editing repository documents such as README.md and LICENSE.md.", Func: docscmds.Commands, ReqRepo: false},
+	{Name: "admin", Desc: "Commands for inspecting how a repository is stored.", Func: admincmds.Commands, ReqRepo: false},
+	{Name: "automation", Desc: "Commands for running scheduled imports, queries, commits and pushes.", Func: automationcmds.Commands, ReqRepo: false},
 	{Name: commands.SendMetricsCommand, Desc: "Send events logs to server.", Func: commands.SendMetrics, ReqRepo: false, HideFromHelp: true},
 })
 
@@ -173,7 +188,51 @@ func runMain() int {
 		return 1
 	}
 
-	return doltCommand(context.Background(), "dolt", args, dEnv)
+	info := &events.CommandInfo{Name: "dolt"}
+	start := time.Now()
+	res := doltCommand(events.NewContextForCommandInfo(context.Background(), info), "dolt", args, dEnv)
+	recordCommand(root, dEnv, info, start)
+
+	return res
+}
+
+// recordCommand writes the record of the command that was run to the command log when it has been turned on with
+// the metrics.command_log config key.  The send-metrics command that dolt runs in the background isn't recorded, and
+// failing to write the record doesn't fail the command.
+func recordCommand(root string, dEnv *env.DoltEnv, info *events.CommandInfo, start time.Time) {
+	d := time.Since(start)
+
+	if info.Name == "dolt "+commands.SendMetricsCommand {
+		return
+	}
+
+	enabled, err := strconv.ParseBool(*dEnv.Config.GetStringOrDefault(env.CommandLogEnabled, "false"))
+
+	if err != nil || !enabled {
+		return
+	}
+
+	repoSize := int64(-1)
+	if dEnv.HasDoltDir() {
+		repoSize = 0
+		_ = dEnv.FS.Iter(dbfactory.DoltDataDir, true, func(path string, size int64, isDir bool) (stop bool) {
+			repoSize += size
+			return false
+		})
+	}
+
+	cl := events.CommandLog{
+		Path:    *dEnv.Config.GetStringOrDefault(env.CommandLogFile, filepath.Join(root, dbfactory.DoltDir, commandLogFile)),
+		SinkURL: *dEnv.Config.GetStringOrDefault(env.CommandLogSink, ""),
+	}
+
+	_ = cl.Record(context.Background(), events.CommandRecord{
+		Time:       start.UTC(),
+		Command:    info.Name,
+		DurationMs: int64(d / time.Millisecond),
+		RepoSize:   events.RepoSizeClass(repoSize),
+		Error:      info.ErrorCategory,
+	})
 }
 
 // processEventsDir runs the dolt send-metrics command in a new process
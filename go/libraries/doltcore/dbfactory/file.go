@@ -32,6 +32,18 @@ const (
 
 	// DataDir is the directory internal to the DoltDir which holds the noms files.
 	DataDir = "noms"
+
+	// ChunkJournalEnv is the environment variable which, when set, causes small writes to local databases to be
+	// appended to a chunk journal rather than written to new table files.
+	ChunkJournalEnv = "DOLT_ENABLE_CHUNK_JOURNAL"
+
+	// DisableMmapParam is a creation parameter which, when "true", causes the table files of local databases to be read
+	// without mmap, for platforms and filesystems where mapping many large files is unreliable.
+	DisableMmapParam = "disable-mmap"
+
+	// DisableMmapEnv is the environment variable which, when set, causes the table files of local databases to be read
+	// without mmap whatever DisableMmapParam is set to.
+	DisableMmapEnv = "DOLT_DISABLE_MMAP"
 )
 
 // DoltDataDir is the directory where noms files will be stored
@@ -43,7 +55,7 @@ type FileFactory struct {
 
 // CreateDB creates an local filesys backed database
 func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat, urlObj *url.URL, params map[string]string) (datas.Database, error) {
-	path := urlObj.Host + urlObj.Path
+	path := fileURLToPath(urlObj)
 
 	info, err := os.Stat(path)
 
@@ -53,7 +65,14 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 		return nil, filesys.ErrIsFile
 	}
 
-	st, err := nbs.NewLocalStore(ctx, nbf.VersionString(), path, defaultMemTableSize)
+	var opts nbs.LocalStoreOptions
+	_, opts.JournalWrites = os.LookupEnv(ChunkJournalEnv)
+	opts.PureIO = params[DisableMmapParam] == "true"
+	if _, ok := os.LookupEnv(DisableMmapEnv); ok {
+		opts.PureIO = true
+	}
+
+	st, err := nbs.NewLocalStoreWithOptions(ctx, nbf.VersionString(), path, defaultMemTableSize, opts)
 
 	if err != nil {
 		return nil, err
@@ -62,3 +81,15 @@ func (fact FileFactory) CreateDB(ctx context.Context, nbf *types.NomsBinFormat,
 	return datas.NewDatabase(st), nil
 
 }
+
+// fileURLToPath returns the filesystem path referenced by a file url. On Windows, the leading slash of absolute paths
+// like file:///C:/dolt/data is dropped and separators are converted, giving C:\dolt\data.
+func fileURLToPath(urlObj *url.URL) string {
+	path := urlObj.Host + urlObj.Path
+
+	if len(path) > 1 && path[0] == '/' && filepath.VolumeName(path[1:]) != "" {
+		path = path[1:]
+	}
+
+	return filepath.FromSlash(path)
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestFileURLToPath(t *testing.T) {
+	tests := []struct {
+		urlStr   string
+		expected string
+	}{
+		{"file://./.dolt/noms", "./.dolt/noms"},
+		{"file:///tmp/dolt/noms", "/tmp/dolt/noms"},
+		{"file://relative/path", "relative/path"},
+		{"file:///path%20with%20spaces/noms", "/path with spaces/noms"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.urlStr, func(t *testing.T) {
+			urlObj, err := url.Parse(test.urlStr)
+			require.NoError(t, err)
+			assert.Equal(t, filepath.FromSlash(test.expected), fileURLToPath(urlObj))
+		})
+	}
+}
+
+func TestCreateFileDB(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	dir, err = filepath.Abs(dir)
+	require.NoError(t, err)
+
+	urlStr := "file://" + filepath.ToSlash(dir)
+	if filepath.VolumeName(dir) != "" {
+		urlStr = "file:///" + filepath.ToSlash(dir)
+	}
+
+	// the database can be reopened, including without mmap
+	for _, params := range []map[string]string{nil, {DisableMmapParam: "true"}} {
+		db, err := CreateDB(ctx, types.Format_Default, urlStr, params)
+		require.NoError(t, err)
+
+		datasets, err := db.Datasets(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), datasets.Len())
+		require.NoError(t, db.Close())
+	}
+}
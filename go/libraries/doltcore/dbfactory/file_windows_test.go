@@ -0,0 +1,42 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbfactory
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileURLToPathWindows(t *testing.T) {
+	tests := []struct {
+		urlStr   string
+		expected string
+	}{
+		{"file:///C:/dolt/noms", `C:\dolt\noms`},
+		{"file://C:/dolt/noms", `C:\dolt\noms`},
+		{"file://./.dolt/noms", `.\.dolt\noms`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.urlStr, func(t *testing.T) {
+			urlObj, err := url.Parse(test.urlStr)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, fileURLToPath(urlObj))
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupe
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// numBuckets is the number of files the hashes of the rows are partitioned into. Only the hashes of a single bucket are
+// held in memory at a time.
+const numBuckets = 256
+
+// recordSize is the size of the records written to the bucket files, which hold the hash of a row's values followed by
+// the row's position in the table.
+const recordSize = hash.ByteLen + 8
+
+// FindDuplicates calls cb with each row of the table data given which has the same values for the columns with the tags
+// given as a row before it in primary key order, and returns the number of such rows. A row missing a value is only
+// a duplicate of rows missing the same value. Rows are compared by the hashes of their values, which are partitioned
+// into files in a temporary directory created in tmpDir, so that tables of any size can be checked. The rows are
+// passed to cb in primary key order.
+func FindDuplicates(ctx context.Context, tmpDir string, m types.Map, sch schema.Schema, tags []uint64, cb func(r row.Row) error) (int64, error) {
+	dir, err := ioutil.TempDir(tmpDir, "dedupe")
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	err = writeBuckets(ctx, dir, m, sch, tags)
+
+	if err != nil {
+		return 0, err
+	}
+
+	dupPositions, err := findDuplicatePositions(dir)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pos := range dupPositions {
+		itr, err := m.IteratorAt(ctx, pos)
+
+		if err != nil {
+			return 0, err
+		}
+
+		k, v, err := itr.Next(ctx)
+
+		if err != nil {
+			return 0, err
+		}
+
+		r, err := row.FromNoms(sch, k.(types.Tuple), v.(types.Tuple))
+
+		if err != nil {
+			return 0, err
+		}
+
+		err = cb(r)
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(dupPositions)), nil
+}
+
+func bucketPath(dir string, bucket int) string {
+	return filepath.Join(dir, fmt.Sprintf("%02x", bucket))
+}
+
+// writeBuckets writes a record for each row of the table data given to the bucket file chosen by the hash of the row's
+// values for the columns with the tags given. The records of each bucket are written in primary key order.
+func writeBuckets(ctx context.Context, dir string, m types.Map, sch schema.Schema, tags []uint64) (err error) {
+	files := make([]*os.File, numBuckets)
+	writers := make([]*bufio.Writer, numBuckets)
+
+	defer func() {
+		for _, f := range files {
+			if f != nil {
+				closeErr := f.Close()
+
+				if err == nil {
+					err = closeErr
+				}
+			}
+		}
+	}()
+
+	for i := range files {
+		files[i], err = os.Create(bucketPath(dir, i))
+
+		if err != nil {
+			return err
+		}
+
+		writers[i] = bufio.NewWriter(files[i])
+	}
+
+	nbf := m.Format()
+	vals := make([]types.Value, len(tags))
+	var rec [recordSize]byte
+	var pos uint64
+	err = m.Iter(ctx, func(k, v types.Value) (bool, error) {
+		r, err := row.FromNoms(sch, k.(types.Tuple), v.(types.Tuple))
+
+		if err != nil {
+			return true, err
+		}
+
+		for i, tag := range tags {
+			val, ok := r.GetColVal(tag)
+
+			if !ok {
+				val = types.NullValue
+			}
+
+			vals[i] = val
+		}
+
+		tpl, err := types.NewTuple(nbf, vals...)
+
+		if err != nil {
+			return true, err
+		}
+
+		h, err := tpl.Hash(nbf)
+
+		if err != nil {
+			return true, err
+		}
+
+		copy(rec[:], h[:])
+		binary.BigEndian.PutUint64(rec[hash.ByteLen:], pos)
+		pos++
+
+		_, err = writers[int(h[0])%numBuckets].Write(rec[:])
+
+		if err != nil {
+			return true, err
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, wr := range writers {
+		err = wr.Flush()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// findDuplicatePositions reads the bucket files one at a time and returns the sorted positions of the rows whose hash
+// was seen earlier in the same bucket.
+func findDuplicatePositions(dir string) ([]uint64, error) {
+	var dupPositions []uint64
+	for i := 0; i < numBuckets; i++ {
+		data, err := ioutil.ReadFile(bucketPath(dir, i))
+
+		if err != nil {
+			return nil, err
+		}
+
+		seen := make(map[hash.Hash]struct{}, len(data)/recordSize)
+		for ; len(data) >= recordSize; data = data[recordSize:] {
+			var h hash.Hash
+			copy(h[:], data[:hash.ByteLen])
+
+			if _, ok := seen[h]; ok {
+				dupPositions = append(dupPositions, binary.BigEndian.Uint64(data[hash.ByteLen:recordSize]))
+			} else {
+				seen[h] = struct{}{}
+			}
+		}
+	}
+
+	sort.Slice(dupPositions, func(i, j int) bool {
+		return dupPositions[i] < dupPositions[j]
+	})
+
+	return dupPositions, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupe
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	idTag uint64 = iota
+	nameTag
+	cityTag
+)
+
+var testSch = dtestutils.CreateSchema(
+	schema.NewColumn("id", idTag, types.IntKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn("name", nameTag, types.StringKind, false),
+	schema.NewColumn("city", cityTag, types.StringKind, false),
+)
+
+func TestFindDuplicates(t *testing.T) {
+	ctx := context.Background()
+	db, err := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_7_18, nil, nil)
+	require.NoError(t, err)
+
+	rows := []row.Row{
+		dtestutils.NewRow(testSch, types.Int(1), types.String("ann"), types.String("paris")),
+		dtestutils.NewRow(testSch, types.Int(2), types.String("bob"), types.String("paris")),
+		dtestutils.NewRow(testSch, types.Int(3), types.String("ann"), types.String("paris")),
+		dtestutils.NewRow(testSch, types.Int(4), types.String("ann"), types.String("rome")),
+		dtestutils.NewRow(testSch, types.Int(5), types.String("cat")),
+		dtestutils.NewRow(testSch, types.Int(6), types.String("cat")),
+		dtestutils.NewRow(testSch, types.Int(7), types.String("ann"), types.String("paris")),
+	}
+
+	m, err := types.NewMap(ctx, db)
+	require.NoError(t, err)
+	ed := m.Edit()
+	for _, r := range rows {
+		ed.Set(r.NomsMapKey(testSch), r.NomsMapValue(testSch))
+	}
+	m, err = ed.Map(ctx)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		tags     []uint64
+		expected []int64
+	}{
+		{"all columns but the key", []uint64{nameTag, cityTag}, []int64{3, 6, 7}},
+		{"one column", []uint64{nameTag}, []int64{3, 4, 6, 7}},
+		{"column with missing values", []uint64{cityTag}, []int64{2, 3, 6, 7}},
+		{"key", []uint64{idTag}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpDir, err := ioutil.TempDir("", "dedupe_test")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpDir)
+
+			var ids []int64
+			count, err := FindDuplicates(ctx, tmpDir, m, testSch, test.tags, func(r row.Row) error {
+				id, _ := r.GetColVal(idTag)
+				ids = append(ids, int64(id.(types.Int)))
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(test.expected)), count)
+			assert.Equal(t, test.expected, ids)
+
+			entries, err := ioutil.ReadDir(tmpDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/store/atomicerr"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ChangedRows returns the rows of the map to that were added or modified since the map from, and the keys of the rows
+// of from that were removed, mapped to empty tuples.
+func ChangedRows(ctx context.Context, vrw types.ValueReadWriter, from, to types.Map) (changed types.Map, removed types.Map, err error) {
+	empty, err := types.NewMap(ctx, vrw)
+	if err != nil {
+		return types.EmptyMap, types.EmptyMap, err
+	}
+
+	changedEd := empty.Edit()
+	removedEd := empty.Edit()
+	emptyVal := types.EmptyTuple(vrw.Format())
+
+	ae := atomicerr.New()
+	changeChan := make(chan types.ValueChanged, 32)
+	stopChan := make(chan struct{})
+
+	go func() {
+		defer close(changeChan)
+		to.Diff(ctx, from, ae, changeChan, stopChan)
+	}()
+
+	for change := range changeChan {
+		switch change.ChangeType {
+		case types.DiffChangeAdded, types.DiffChangeModified:
+			changedEd.Set(change.Key, change.NewValue)
+		case types.DiffChangeRemoved:
+			removedEd.Set(change.Key, emptyVal)
+		}
+	}
+
+	if err := ae.Get(); err != nil {
+		return types.EmptyMap, types.EmptyMap, err
+	}
+
+	changed, err = changedEd.Map(ctx)
+	if err != nil {
+		return types.EmptyMap, types.EmptyMap, err
+	}
+
+	removed, err = removedEd.Map(ctx)
+	if err != nil {
+		return types.EmptyMap, types.EmptyMap, err
+	}
+
+	return changed, removed, nil
+}
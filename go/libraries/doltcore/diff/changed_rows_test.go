@@ -0,0 +1,62 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestChangedRows(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_7_18, doltdb.InMemDoltDB)
+	require.NoError(t, err)
+	vrw := ddb.ValueReadWriter()
+
+	newMap := func(kvs ...types.Value) types.Map {
+		m, err := types.NewMap(ctx, vrw, kvs...)
+		require.NoError(t, err)
+		return m
+	}
+
+	from := newMap(
+		types.Int(1), types.String("unchanged"),
+		types.Int(2), types.String("old"),
+		types.Int(3), types.String("removed"),
+	)
+	to := newMap(
+		types.Int(1), types.String("unchanged"),
+		types.Int(2), types.String("new"),
+		types.Int(4), types.String("added"),
+	)
+
+	changed, removed, err := ChangedRows(ctx, vrw, from, to)
+	require.NoError(t, err)
+
+	expectedChanged := newMap(
+		types.Int(2), types.String("new"),
+		types.Int(4), types.String("added"),
+	)
+	expectedRemoved := newMap(types.Int(3), types.EmptyTuple(vrw.Format()))
+
+	assert.True(t, expectedChanged.Equals(changed))
+	assert.True(t, expectedRemoved.Equals(removed))
+}
@@ -27,7 +27,8 @@ const (
 	SchDiffColAdded
 	// SchDiffRemoved is the SchemaChangeType when a column is in the old schema but not the new
 	SchDiffColRemoved
-	// SchDiffModified is the SchemaChangeType for two columns with the same tag that are different
+	// SchDiffModified is the SchemaChangeType for two columns with the same tag that are different, including when
+	// only their comments differ
 	SchDiffColModified
 )
 
@@ -53,7 +54,7 @@ func DiffSchemas(sch1, sch2 schema.Schema) (map[uint64]SchemaDifference, error)
 			diffs[tag] = SchemaDifference{SchDiffColAdded, tag, nil, colPair[1]}
 		} else if colPair[1] == nil {
 			diffs[tag] = SchemaDifference{SchDiffColRemoved, tag, colPair[0], nil}
-		} else if !colPair[0].Equals(*colPair[1]) {
+		} else if !colPair[0].Equals(*colPair[1]) || colPair[0].Comment != colPair[1].Comment {
 			diffs[tag] = SchemaDifference{SchDiffColModified, tag, colPair[0], colPair[1]}
 		} else {
 			diffs[tag] = SchemaDifference{SchDiffNone, tag, colPair[0], colPair[1]}
@@ -0,0 +1,113 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+)
+
+// SchemaDrift is a change to the schema of a table between two versions of it. A change is breaking if readers of the
+// old version may be unable to read the new one, such as when a column is removed, renamed or changes type.
+type SchemaDrift struct {
+	Column   string // The name of the column in the old schema, or in the new one if it was added
+	Desc     string
+	Breaking bool
+}
+
+// DriftSchemas returns the changes made to the columns of a table between its old and new schemas, in the order of the
+// columns. Columns are paired by tag, and a column removed and added back with the same name is treated as a change to
+// the column, as readers refer to columns by name.
+func DriftSchemas(oldSch, newSch schema.Schema) ([]SchemaDrift, error) {
+	diffs, err := DiffSchemas(oldSch, newSch)
+	if err != nil {
+		return nil, err
+	}
+
+	added := make(map[string]uint64)
+	for tag, dff := range diffs {
+		if dff.DiffType == SchDiffColAdded {
+			added[dff.New.Name] = tag
+		}
+	}
+
+	for tag, dff := range diffs {
+		if dff.DiffType != SchDiffColRemoved {
+			continue
+		}
+
+		if newTag, ok := added[dff.Old.Name]; ok {
+			diffs[tag] = SchemaDifference{SchDiffColModified, tag, dff.Old, diffs[newTag].New}
+			delete(diffs, newTag)
+		}
+	}
+
+	tags := make([]uint64, 0, len(diffs))
+	for tag := range diffs {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+	var drift []SchemaDrift
+	for _, tag := range tags {
+		dff := diffs[tag]
+		switch dff.DiffType {
+		case SchDiffColAdded:
+			desc := fmt.Sprintf("added column %s %s", dff.New.Name, dff.New.KindString())
+			// a new primary key column changes how rows are identified
+			drift = append(drift, SchemaDrift{dff.New.Name, desc, dff.New.IsPartOfPK})
+		case SchDiffColRemoved:
+			drift = append(drift, SchemaDrift{dff.Old.Name, "removed column " + dff.Old.Name, true})
+		case SchDiffColModified:
+			drift = append(drift, columnDrift(*dff.Old, *dff.New)...)
+		}
+	}
+
+	return drift, nil
+}
+
+// columnDrift returns the changes made to a column.
+func columnDrift(old, new schema.Column) []SchemaDrift {
+	var drift []SchemaDrift
+	if old.Name != new.Name {
+		drift = append(drift, SchemaDrift{old.Name, fmt.Sprintf("renamed column %s to %s", old.Name, new.Name), true})
+	}
+
+	if old.Kind != new.Kind {
+		desc := fmt.Sprintf("changed the type of column %s from %s to %s", old.Name, old.KindString(), new.KindString())
+		drift = append(drift, SchemaDrift{old.Name, desc, true})
+	}
+
+	if old.IsPartOfPK != new.IsPartOfPK {
+		desc := fmt.Sprintf("added column %s to the primary key", old.Name)
+		if old.IsPartOfPK {
+			desc = fmt.Sprintf("removed column %s from the primary key", old.Name)
+		}
+		drift = append(drift, SchemaDrift{old.Name, desc, true})
+	}
+
+	if old.IsNullable() != new.IsNullable() {
+		// readers of the old schema don't expect nulls in a column that couldn't be null
+		if new.IsNullable() {
+			drift = append(drift, SchemaDrift{old.Name, fmt.Sprintf("column %s can now be null", old.Name), true})
+		} else {
+			drift = append(drift, SchemaDrift{old.Name, fmt.Sprintf("column %s can no longer be null", old.Name), false})
+		}
+	}
+
+	return drift
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestDriftSchemas(t *testing.T) {
+	oldCols := []schema.Column{
+		schema.NewColumn("unchanged", 0, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("removed", 1, types.StringKind, false),
+		schema.NewColumn("renamed", 2, types.StringKind, false),
+		schema.NewColumn("type_changed", 3, types.StringKind, false),
+		schema.NewColumn("moved_to_pk", 4, types.StringKind, false),
+		schema.NewColumn("constraint_added", 5, types.StringKind, false),
+		schema.NewColumn("constraint_removed", 6, types.StringKind, false, schema.NotNullConstraint{}),
+		schema.NewColumn("readded", 7, types.IntKind, false),
+	}
+
+	newCols := []schema.Column{
+		schema.NewColumn("unchanged", 0, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("renamed_new", 2, types.StringKind, false),
+		schema.NewColumn("type_changed", 3, types.IntKind, false),
+		schema.NewColumn("moved_to_pk", 4, types.StringKind, true),
+		schema.NewColumn("constraint_added", 5, types.StringKind, false, schema.NotNullConstraint{}),
+		schema.NewColumn("constraint_removed", 6, types.StringKind, false),
+		schema.NewColumn("added", 8, types.StringKind, false),
+		schema.NewColumn("readded", 9, types.StringKind, false),
+	}
+
+	oldColColl, _ := schema.NewColCollection(oldCols...)
+	newColColl, _ := schema.NewColCollection(newCols...)
+
+	drift, err := DriftSchemas(schema.SchemaFromCols(oldColColl), schema.SchemaFromCols(newColColl))
+	require.NoError(t, err)
+
+	expected := []SchemaDrift{
+		{"removed", "removed column removed", true},
+		{"renamed", "renamed column renamed to renamed_new", true},
+		{"type_changed", "changed the type of column type_changed from string to int", true},
+		{"moved_to_pk", "added column moved_to_pk to the primary key", true},
+		{"constraint_added", "column constraint_added can no longer be null", false},
+		{"constraint_removed", "column constraint_removed can now be null", true},
+		{"readded", "changed the type of column readded from int to string", true},
+		{"added", "added column added string", false},
+	}
+	assert.Equal(t, expected, drift)
+
+	drift, err = DriftSchemas(schema.SchemaFromCols(oldColColl), schema.SchemaFromCols(oldColColl))
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+}
@@ -128,7 +128,7 @@ func PrintSqlTableDiffs(ctx context.Context, r1, r2 *doltdb.RootValue, wr io.Wri
 		return err
 	}
 
-	creates, drops, renames, err := findRenames(ctx, r1, r2, creates, drops)
+	renames, creates, drops, err := r1.TableRenames(ctx, r2, creates, drops)
 
 	if err != nil {
 		return err
@@ -200,48 +200,3 @@ func PrintSqlTableDiffs(ctx context.Context, r1, r2 *doltdb.RootValue, wr io.Wri
 	}
 	return err
 }
-
-func findRenames(ctx context.Context, r1, r2 *doltdb.RootValue, adds []string, drops []string) (added, dropped []string, renamed map[string]string, err error) {
-
-	renames := make(map[string]string, 0)
-	for i, add := range adds {
-		for j, drop := range drops {
-			addHash, foundAdd, err := r1.GetTableHash(ctx, add)
-
-			if err != nil {
-				return nil, nil, nil, err
-			}
-
-			dropHash, foundDrop, err := r2.GetTableHash(ctx, drop)
-
-			if err != nil {
-				return nil, nil, nil, err
-			}
-
-			if foundAdd && foundDrop {
-				if addHash.Equal(dropHash) {
-					renames[drop] = add
-					// mark tables as consumed
-					adds[i] = ""
-					drops[j] = ""
-				}
-			}
-		}
-	}
-
-	outAdds := make([]string, 0)
-	for _, tbl := range adds {
-		if tbl != "" {
-			outAdds = append(outAdds, tbl)
-		}
-	}
-
-	outDrops := make([]string, 0)
-	for _, tbl := range drops {
-		if tbl != "" {
-			outDrops = append(outDrops, tbl)
-		}
-	}
-
-	return outAdds, outDrops, renames, nil
-}
@@ -60,7 +60,7 @@ func TestSqlTableDiffAdd(t *testing.T) {
 	dtestutils.CreateTestTable(t, dEnv, "addTable", sch, []row.Row{}...)
 	newRoot, _ := dEnv.WorkingRoot(ctx)
 	a, _, rm, _ := newRoot.TableDiff(ctx, oldRoot)
-	adds, removed, renamed, _ := findRenames(ctx, newRoot, oldRoot, a, rm)
+	renamed, adds, removed, _ := newRoot.TableRenames(ctx, oldRoot, a, rm)
 	assert.Equal(t, []string{"addTable"}, adds)
 	assert.Equal(t, []string{}, removed)
 	assert.Equal(t, map[string]string{}, renamed)
@@ -81,7 +81,7 @@ func TestSqlTableDiffAddThenInsert(t *testing.T) {
 	newRoot, _ := dEnv.WorkingRoot(ctx)
 	newRoot, _ = dtestutils.AddRowToRoot(dEnv, ctx, newRoot, "addTable", r)
 	a, _, rm, _ := newRoot.TableDiff(ctx, oldRoot)
-	added, removed, renamed, _ := findRenames(ctx, newRoot, oldRoot, a, rm)
+	renamed, added, removed, _ := newRoot.TableRenames(ctx, oldRoot, a, rm)
 	assert.Equal(t, []string{"addTable"}, added)
 	assert.Equal(t, []string{}, removed)
 	assert.Equal(t, map[string]string{}, renamed)
@@ -102,7 +102,7 @@ func TestSqlTableDiffsDrop(t *testing.T) {
 	oldRoot, _ := dEnv.WorkingRoot(ctx)
 	newRoot, _ := oldRoot.RemoveTables(ctx, []string{"dropTable"}...)
 	a, _, rm, _ := newRoot.TableDiff(ctx, oldRoot)
-	added, drops, renamed, _ := findRenames(ctx, newRoot, oldRoot, a, rm)
+	renamed, added, drops, _ := newRoot.TableRenames(ctx, oldRoot, a, rm)
 	assert.Equal(t, []string{"dropTable"}, drops)
 	assert.Equal(t, []string{}, added)
 	assert.Equal(t, map[string]string{}, renamed)
@@ -119,7 +119,7 @@ func TestSqlTableDiffRename(t *testing.T) {
 	oldRoot, _ := dEnv.WorkingRoot(ctx)
 	newRoot, _ := alterschema.RenameTable(ctx, dEnv.DoltDB, oldRoot, "renameTable", "newTableName")
 	a, _, rm, _ := newRoot.TableDiff(ctx, oldRoot)
-	added, removed, renames, _ := findRenames(ctx, newRoot, oldRoot, a, rm)
+	renames, added, removed, _ := newRoot.TableRenames(ctx, oldRoot, a, rm)
 	assert.Equal(t, map[string]string{"renameTable": "newTableName"}, renames)
 	assert.Equal(t, []string{}, removed)
 	assert.Equal(t, []string{}, added)
@@ -140,7 +140,7 @@ func TestSqlTableDiffRenameChangedTable(t *testing.T) {
 	r := dtestutils.NewTypedRow(id, "Big Billy", 77, false, strPointer("Doctor"))
 	newRoot, _ = dtestutils.AddRowToRoot(dEnv, ctx, newRoot, "newTableName", r)
 	a, _, rm, _ := newRoot.TableDiff(ctx, oldRoot)
-	added, removed, renamed, _ := findRenames(ctx, newRoot, oldRoot, a, rm)
+	renamed, added, removed, _ := newRoot.TableRenames(ctx, oldRoot, a, rm)
 	assert.Equal(t, []string{"renameTable"}, removed)
 	assert.Equal(t, []string{"newTableName"}, added)
 	assert.Equal(t, map[string]string{}, renamed)
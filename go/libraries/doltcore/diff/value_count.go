@@ -0,0 +1,123 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/atomicerr"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// rowMatcher returns whether the row with the key and value given holds the value being searched for
+type rowMatcher func(key, val types.Value) (bool, error)
+
+// ValueCountChange returns the number of rows of the map to whose column with the name given holds the value given,
+// less the number of rows of the map from that do, found by diffing the two maps. Each map's rows are read with its
+// own schema, which is nil if the table didn't exist. Rows read with a schema that has no such column, or whose column
+// the value can't be converted to, never hold the value.
+func ValueCountChange(ctx context.Context, from, to types.Map, fromSch, toSch schema.Schema, colName, val string) (int64, error) {
+	fromMatcher := newRowMatcher(fromSch, colName, val)
+	toMatcher := newRowMatcher(toSch, colName, val)
+
+	if fromMatcher == nil && toMatcher == nil {
+		return 0, nil
+	}
+
+	ae := atomicerr.New()
+	changeChan := make(chan types.ValueChanged, 32)
+	stopChan := make(chan struct{})
+
+	go func() {
+		defer close(changeChan)
+		to.Diff(ctx, from, ae, changeChan, stopChan)
+	}()
+
+	var count int64
+	for change := range changeChan {
+		if ae.IsSet() {
+			break
+		}
+
+		if change.ChangeType == types.DiffChangeAdded || change.ChangeType == types.DiffChangeModified {
+			ok, err := match(toMatcher, change.Key, change.NewValue)
+			if ae.SetIfError(err) {
+				break
+			} else if ok {
+				count++
+			}
+		}
+
+		if change.ChangeType == types.DiffChangeRemoved || change.ChangeType == types.DiffChangeModified {
+			ok, err := match(fromMatcher, change.Key, change.OldValue)
+			if ae.SetIfError(err) {
+				break
+			} else if ok {
+				count--
+			}
+		}
+	}
+
+	if ae.IsSet() {
+		close(stopChan)
+		for range changeChan {
+		}
+	}
+
+	if err := ae.Get(); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func match(m rowMatcher, key, val types.Value) (bool, error) {
+	if m == nil {
+		return false, nil
+	}
+
+	return m(key, val)
+}
+
+// newRowMatcher returns a matcher for rows of the schema given whose column with the name given holds the value given,
+// or nil if no row can hold it.
+func newRowMatcher(sch schema.Schema, colName, val string) rowMatcher {
+	if sch == nil {
+		return nil
+	}
+
+	col, ok := sch.GetAllCols().GetByName(colName)
+	if !ok {
+		return nil
+	}
+
+	nomsVal, err := doltcore.StringToValue(val, col.Kind)
+	if err != nil || types.IsNull(nomsVal) {
+		return nil
+	}
+
+	return func(key, val types.Value) (bool, error) {
+		r, err := row.FromNoms(sch, key.(types.Tuple), val.(types.Tuple))
+		if err != nil {
+			return false, err
+		}
+
+		colVal, ok := r.GetColVal(col.Tag)
+		return ok && nomsVal.Equals(colVal), nil
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestValueCountChange(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := doltdb.LoadDoltDB(ctx, types.Format_7_18, doltdb.InMemDoltDB)
+	require.NoError(t, err)
+	vrw := ddb.ValueReadWriter()
+
+	colColl, _ := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.IntKind, true),
+		schema.NewColumn("last", 1, types.StringKind, false),
+	)
+	sch := schema.SchemaFromCols(colColl)
+
+	newMap := func(lastNames ...string) types.Map {
+		m, err := types.NewMap(ctx, vrw)
+		require.NoError(t, err)
+
+		me := m.Edit()
+		for i, last := range lastNames {
+			r, err := row.New(vrw.Format(), sch, row.TaggedValues{0: types.Int(i), 1: types.String(last)})
+			require.NoError(t, err)
+			me.Set(r.NomsMapKey(sch), r.NomsMapValue(sch))
+		}
+
+		m, err = me.Map(ctx)
+		require.NoError(t, err)
+		return m
+	}
+
+	empty := newMap()
+	from := newMap("Simpson", "Szyslak", "Flanders")
+
+	tests := []struct {
+		name     string
+		from     types.Map
+		to       types.Map
+		fromSch  schema.Schema
+		toSch    schema.Schema
+		col      string
+		val      string
+		expected int64
+	}{
+		{"unchanged", from, from, sch, sch, "last", "Szyslak", 0},
+		{"added", from, newMap("Simpson", "Szyslak", "Flanders", "Szyslak"), sch, sch, "last", "Szyslak", 1},
+		{"removed", from, newMap("Simpson"), sch, sch, "last", "Szyslak", -1},
+		{"modified to", from, newMap("Szyslak", "Szyslak", "Flanders"), sch, sch, "last", "Szyslak", 1},
+		{"modified from", from, newMap("Simpson", "Gumble", "Flanders"), sch, sch, "last", "Szyslak", -1},
+		{"modified other rows", from, newMap("Simpson", "Szyslak", "Wiggum"), sch, sch, "last", "Szyslak", 0},
+		{"table created", empty, from, nil, sch, "last", "Szyslak", 1},
+		{"table dropped", from, empty, sch, nil, "last", "Szyslak", -1},
+		{"pk column", from, newMap("Simpson"), sch, sch, "id", "2", -1},
+		{"no such column", from, newMap("Simpson"), sch, sch, "first", "Szyslak", 0},
+		{"value of the wrong type", from, newMap("Simpson"), sch, sch, "id", "Szyslak", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, err := ValueCountChange(ctx, test.from, test.to, test.fromSch, test.toSch, test.col, test.val)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, n)
+		})
+	}
+}
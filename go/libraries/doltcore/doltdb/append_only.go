@@ -0,0 +1,126 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liquidata-inc/dolt/go/store/atomicerr"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ErrAppendOnly is returned when the existing rows of an append-only table are changed or removed.
+var ErrAppendOnly = errors.New("the existing rows of an append-only table can't be updated or deleted")
+
+// IsAppendOnly returns whether the table is append-only. Rows can be added to an append-only table, but its existing
+// rows can't be updated or deleted, which suits tables of events or log entries whose history shouldn't change.
+func (t *Table) IsAppendOnly() (bool, error) {
+	val, ok, err := t.tableStruct.MaybeGet(appendOnlyKey)
+
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return bool(val.(types.Bool)), nil
+}
+
+// SetAppendOnly returns a copy of the table which is append-only or not, as given.
+func (t *Table) SetAppendOnly(appendOnly bool) (*Table, error) {
+	var updatedSt types.Struct
+	var err error
+
+	if appendOnly {
+		updatedSt, err = t.tableStruct.Set(appendOnlyKey, types.Bool(true))
+	} else {
+		updatedSt, err = t.tableStruct.Delete(appendOnlyKey)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Table{t.vrw, updatedSt}, nil
+}
+
+// CopyAppendOnly returns a copy of the table which is append-only if the table given is. It's used to keep the property
+// when a new table is built from an existing one, such as when its schema is altered.
+func (t *Table) CopyAppendOnly(from *Table) (*Table, error) {
+	appendOnly, err := from.IsAppendOnly()
+
+	if err != nil || !appendOnly {
+		return t, err
+	}
+
+	return t.SetAppendOnly(true)
+}
+
+// checkAppendOnly returns ErrAppendOnly if the table is append-only and any of its rows are changed or removed in the
+// updated rows given.
+func (t *Table) checkAppendOnly(ctx context.Context, updatedRows types.Map) error {
+	if appendOnly, err := t.IsAppendOnly(); err != nil || !appendOnly {
+		return err
+	}
+
+	oldRows, err := t.GetRowData(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	onlyAdded, err := OnlyAddsRows(ctx, oldRows, updatedRows)
+
+	if err != nil {
+		return err
+	} else if !onlyAdded {
+		return ErrAppendOnly
+	}
+
+	return nil
+}
+
+// OnlyAddsRows returns whether the rows given differ from the old rows given only by added rows.
+func OnlyAddsRows(ctx context.Context, oldRows, rows types.Map) (bool, error) {
+	if rows.Len() < oldRows.Len() {
+		return false, nil
+	}
+
+	ae := atomicerr.New()
+	changeChan := make(chan types.ValueChanged, 32)
+	stopChan := make(chan struct{})
+
+	go func() {
+		defer close(changeChan)
+		rows.Diff(ctx, oldRows, ae, changeChan, stopChan)
+	}()
+
+	defer func() {
+		close(stopChan)
+		for range changeChan {
+		}
+	}()
+
+	for change := range changeChan {
+		if change.ChangeType != types.DiffChangeAdded {
+			return false, nil
+		}
+	}
+
+	if err := ae.Get(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
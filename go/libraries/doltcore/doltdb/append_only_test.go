@@ -0,0 +1,87 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestAppendOnly(t *testing.T) {
+	ctx := context.Background()
+	db, _ := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_7_18, nil, nil)
+
+	tSchema := createTestSchema()
+	rowData, rows := createTestRowData(t, db, tSchema)
+	fewerRows, err := rowData.Edit().Remove(rows[3].NomsMapKey(tSchema)).Map(ctx)
+	require.NoError(t, err)
+
+	tbl, err := createTestTable(db, tSchema, fewerRows)
+	require.NoError(t, err)
+
+	appendOnly, err := tbl.IsAppendOnly()
+	require.NoError(t, err)
+	assert.False(t, appendOnly)
+
+	tbl, err = tbl.SetAppendOnly(true)
+	require.NoError(t, err)
+
+	appendOnly, err = tbl.IsAppendOnly()
+	require.NoError(t, err)
+	assert.True(t, appendOnly)
+
+	// rows can be added
+	added, err := tbl.UpdateRows(ctx, rowData)
+	require.NoError(t, err)
+
+	appendOnly, err = added.IsAppendOnly()
+	require.NoError(t, err)
+	assert.True(t, appendOnly)
+
+	// but existing rows can't be removed or changed
+	removed, err := fewerRows.Edit().Remove(rows[0].NomsMapKey(tSchema)).Map(ctx)
+	require.NoError(t, err)
+	_, err = tbl.UpdateRows(ctx, removed)
+	assert.Equal(t, ErrAppendOnly, err)
+
+	changedRow, err := rows[0].SetColVal(ageTag, types.Uint(99), tSchema)
+	require.NoError(t, err)
+	changed, err := rowData.Edit().Set(changedRow.NomsMapKey(tSchema), changedRow.NomsMapValue(tSchema)).Map(ctx)
+	require.NoError(t, err)
+	_, err = tbl.UpdateRows(ctx, changed)
+	assert.Equal(t, ErrAppendOnly, err)
+
+	_, err = tbl.Truncate(ctx)
+	assert.Equal(t, ErrAppendOnly, err)
+
+	// the property is kept by tables built from the table
+	copied, err := createTestTable(db, tSchema, fewerRows)
+	require.NoError(t, err)
+	copied, err = copied.CopyAppendOnly(tbl)
+	require.NoError(t, err)
+	_, err = copied.UpdateRows(ctx, removed)
+	assert.Equal(t, ErrAppendOnly, err)
+
+	tbl, err = tbl.SetAppendOnly(false)
+	require.NoError(t, err)
+	_, err = tbl.UpdateRows(ctx, removed)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	// AutomationTableName is the name of the system table holding the jobs run by dolt automation. It is stored in the
+	// root like any other table, so a dataset's update jobs are versioned, merged and cloned along with its data.
+	AutomationTableName = "dolt_automation"
+
+	AutomationNameCol     = "name"
+	AutomationScheduleCol = "schedule"
+	AutomationStepsCol    = "steps"
+)
+
+const (
+	automationNameTag uint64 = iota
+	automationScheduleTag
+	automationStepsTag
+)
+
+var ErrBadAutomationSchema = errors.New("the " + AutomationTableName + " table does not have the expected schema")
+
+// AutomationSchema is the schema of the automation table, keyed by job name.
+var AutomationSchema = mustAutomationSchema()
+
+var automationTable = systemTable{AutomationTableName, AutomationSchema, ErrBadAutomationSchema}
+
+func mustAutomationSchema() schema.Schema {
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn(AutomationNameCol, automationNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(AutomationScheduleCol, automationScheduleTag, types.StringKind, false, schema.NotNullConstraint{}),
+		schema.NewColumn(AutomationStepsCol, automationStepsTag, types.StringKind, false),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return schema.SchemaFromCols(colColl)
+}
+
+// AutomationJob is a job stored in the automation table. Schedule is the interval between runs of the job, and Steps
+// is the json list of the steps it runs. Neither is interpreted by doltdb.
+type AutomationJob struct {
+	Name     string
+	Schedule string
+	Steps    string
+}
+
+// GetAutomationJobs returns the jobs in the automation table, sorted by name.
+func (root *RootValue) GetAutomationJobs(ctx context.Context) ([]AutomationJob, error) {
+	var jobs []AutomationJob
+	err := automationTable.iterRows(ctx, root, func(r row.Row) (bool, error) {
+		jobs = append(jobs, automationJobFromRow(r))
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// PutAutomationJob stores the job given, replacing any existing job with the same name. The automation table is
+// created if it does not already exist.
+func (root *RootValue) PutAutomationJob(ctx context.Context, job AutomationJob) (*RootValue, error) {
+	return automationTable.putRow(ctx, root, row.TaggedValues{
+		automationNameTag:     types.String(job.Name),
+		automationScheduleTag: types.String(job.Schedule),
+		automationStepsTag:    types.String(job.Steps),
+	})
+}
+
+// RemoveAutomationJob removes the job with the name given, returning false if it did not exist.
+func (root *RootValue) RemoveAutomationJob(ctx context.Context, name string) (*RootValue, bool, error) {
+	return automationTable.removeRow(ctx, root, row.TaggedValues{automationNameTag: types.String(name)})
+}
+
+func automationJobFromRow(r row.Row) AutomationJob {
+	return AutomationJob{
+		Name:     rowString(r, automationNameTag),
+		Schedule: rowString(r, automationScheduleTag),
+		Steps:    rowString(r, automationStepsTag),
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestAutomationJobs(t *testing.T) {
+	ctx := context.Background()
+	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	err := ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("head", "master")
+	cm, _ := ddb.Resolve(ctx, cs)
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	jobs, err := root.GetAutomationJobs(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	nightly := AutomationJob{"nightly", "24h", `[{"type":"commit","message":"nightly"}]`}
+	hourly := AutomationJob{"hourly", "1h", `[{"type":"sql","query":"delete from events"}]`}
+
+	for _, job := range []AutomationJob{nightly, hourly} {
+		root, err = root.PutAutomationJob(ctx, job)
+		require.NoError(t, err)
+	}
+
+	jobs, err = root.GetAutomationJobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []AutomationJob{hourly, nightly}, jobs)
+
+	hourly.Schedule = "30m"
+	root, err = root.PutAutomationJob(ctx, hourly)
+	require.NoError(t, err)
+
+	root, ok, err := root.RemoveAutomationJob(ctx, "nightly")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	root, ok, err = root.RemoveAutomationJob(ctx, "nightly")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	jobs, err = root.GetAutomationJobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []AutomationJob{hourly}, jobs)
+}
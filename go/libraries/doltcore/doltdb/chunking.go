@@ -0,0 +1,120 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	// ChunkingTableName is the name of the system table holding the parameters that the repository's values are split
+	// into chunks with. It is stored in the root like any other table, so that every clone of the repository, and
+	// every repository it's pushed to or pulled from, chunks the same rows into the same chunks.
+	ChunkingTableName = "dolt_chunking"
+
+	ChunkingParamCol = "param"
+	ChunkingValueCol = "value"
+
+	// ChunkTargetSizeParam is the name of the parameter holding the ChunkingConfig's TargetSize
+	ChunkTargetSizeParam = "target_size"
+	// ChunkWindowParam is the name of the parameter holding the ChunkingConfig's Window
+	ChunkWindowParam = "window"
+)
+
+const (
+	chunkingParamTag uint64 = iota
+	chunkingValueTag
+)
+
+var ErrBadChunkingSchema = errors.New("the " + ChunkingTableName + " table does not have the expected schema")
+
+// ChunkingSchema is the schema of the chunking table, keyed by parameter name.
+var ChunkingSchema = mustChunkingSchema()
+
+var chunkingTable = systemTable{ChunkingTableName, ChunkingSchema, ErrBadChunkingSchema}
+
+func mustChunkingSchema() schema.Schema {
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn(ChunkingParamCol, chunkingParamTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(ChunkingValueCol, chunkingValueTag, types.UintKind, false, schema.NotNullConstraint{}),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return schema.SchemaFromCols(colColl)
+}
+
+// GetChunkingConfig returns the chunking parameters stored in the root, with the defaults for the ones that aren't
+// set. Returns an error if the parameters stored aren't valid.
+func (root *RootValue) GetChunkingConfig(ctx context.Context) (types.ChunkingConfig, error) {
+	cfg := types.DefaultChunkingConfig
+	params := map[string]*uint32{
+		ChunkTargetSizeParam: &cfg.TargetSize,
+		ChunkWindowParam:     &cfg.Window,
+	}
+
+	err := chunkingTable.iterRows(ctx, root, func(r row.Row) (bool, error) {
+		if val, ok := params[rowString(r, chunkingParamTag)]; ok {
+			if v, ok := r.GetColVal(chunkingValueTag); ok {
+				*val = uint32(v.(types.Uint))
+			}
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return types.ChunkingConfig{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return types.ChunkingConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// PutChunkingConfig stores the chunking parameters given in the root, creating the chunking table if it does not
+// already exist. The parameters aren't validated.
+func (root *RootValue) PutChunkingConfig(ctx context.Context, cfg types.ChunkingConfig) (*RootValue, error) {
+	params := []struct {
+		name string
+		val  uint32
+	}{
+		{ChunkTargetSizeParam, cfg.TargetSize},
+		{ChunkWindowParam, cfg.Window},
+	}
+
+	var err error
+	for _, param := range params {
+		root, err = chunkingTable.putRow(ctx, root, row.TaggedValues{
+			chunkingParamTag: types.String(param.name),
+			chunkingValueTag: types.Uint(param.val),
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestChunkingConfig(t *testing.T) {
+	ctx := context.Background()
+	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	err := ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("head", "master")
+	cm, _ := ddb.Resolve(ctx, cs)
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	cfg, err := root.GetChunkingConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, types.DefaultChunkingConfig, cfg)
+
+	newCfg := types.ChunkingConfig{TargetSize: 1 << 16, Window: 131}
+	root, err = root.PutChunkingConfig(ctx, newCfg)
+	require.NoError(t, err)
+
+	has, err := root.HasTable(ctx, ChunkingTableName)
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	cfg, err = root.GetChunkingConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, newCfg, cfg)
+
+	root, err = root.PutChunkingConfig(ctx, types.ChunkingConfig{TargetSize: 3000, Window: 131})
+	require.NoError(t, err)
+
+	_, err = root.GetChunkingConfig(ctx)
+	assert.Error(t, err)
+}
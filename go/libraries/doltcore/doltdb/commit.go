@@ -227,6 +227,20 @@ func getCommitAncestorRef(ctx context.Context, ref1, ref2 types.Ref, vrw types.V
 	return ancestorRef, nil
 }
 
+// IsAncestorOf returns whether this commit is reachable from the commit given, i.e. whether it is the same commit or one
+// of its ancestors.
+func (c *Commit) IsAncestorOf(ctx context.Context, descendant *Commit) (bool, error) {
+	ancestor, err := GetCommitAncestor(ctx, c, descendant)
+
+	if err == ErrNoCommonAncestor {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return ancestor.commitSt.Equals(c.commitSt), nil
+}
+
 func (c *Commit) CanFastForwardTo(ctx context.Context, new *Commit) (bool, error) {
 	ancestor, err := GetCommitAncestor(ctx, c, new)
 
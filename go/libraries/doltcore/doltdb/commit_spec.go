@@ -29,6 +29,10 @@ func (s stringer) String() string {
 }
 
 var hashRegex = regexp.MustCompile(`^[0-9a-v]{32}$`)
+var hashPrefixRegex = regexp.MustCompile(`^[0-9a-v]{4,31}$`)
+
+// MinHashPrefixLen is the length of the shortest abbreviated commit hash a commit can be referenced by.
+const MinHashPrefixLen = 4
 
 const head string = "head"
 
@@ -38,6 +42,12 @@ func IsValidUserBranchName(name string) bool {
 	return name != head && !hashRegex.MatchString(name) && ref.IsValidBranchName(name)
 }
 
+// IsHashPrefix returns true if name could be an abbreviated commit hash: a proper prefix of a hash at least
+// MinHashPrefixLen characters long. Such names are also valid branch names, and a branch with the name takes precedence.
+func IsHashPrefix(name string) bool {
+	return hashPrefixRegex.MatchString(name)
+}
+
 func IsValidBranchRef(dref ref.DoltRef) bool {
 	return dref.GetType() == ref.BranchRefType && IsValidUserBranchName(dref.GetPath())
 }
@@ -51,6 +61,8 @@ const (
 
 // CommitSpec handles three different types of string representations of commits.  Commits can either be represented
 // by the hash of the commit, a branch name, or using "head" to represent the latest commit of the current branch.
+// A hash can be abbreviated to any prefix of it that no other commit shares, which is parsed as a branch name and
+// resolved as a hash if there is no branch with that name.
 // An Ancestor spec can be appended to the end of any of these in order to reach commits that are in the ancestor tree
 // of the referenced commit.
 type CommitSpec struct {
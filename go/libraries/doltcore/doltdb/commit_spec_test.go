@@ -36,6 +36,27 @@ func TestCommitRegex(t *testing.T) {
 	}
 }
 
+func TestIsHashPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		isPrefix bool
+	}{
+		{"abcd", true},
+		{"0123456789abcdefghijklmnopqrstu", true},
+		{"abc", false},
+		{"0123456789abcdefghijklmnopqrstuv", false},
+		{"abcw", false},
+		{"ABCD", false},
+		{"ab-cd", false},
+	}
+
+	for _, test := range tests {
+		if IsHashPrefix(test.name) != test.isPrefix {
+			t.Error(test.name, "expected IsHashPrefix to be", test.isPrefix)
+		}
+	}
+}
+
 func TestNewCommitSpec(t *testing.T) {
 	tests := []struct {
 		inputStr        string
@@ -0,0 +1,102 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	// DocTableName is the name of the system table holding repository level documents such as the README and LICENSE.
+	// It is stored in the root like any other table, so documents are versioned along with the data they describe.
+	DocTableName = "dolt_docs"
+
+	DocNameCol = "doc_name"
+	DocTextCol = "doc_text"
+
+	// ReadmeDoc is the name of the document describing the dataset
+	ReadmeDoc = "README.md"
+	// LicenseDoc is the name of the document holding the license the dataset is distributed under
+	LicenseDoc = "LICENSE.md"
+)
+
+const (
+	docNameTag uint64 = iota
+	docTextTag
+)
+
+var ErrBadDocsSchema = errors.New("the " + DocTableName + " table does not have the expected schema")
+
+// DocsSchema is the schema of the docs table, keyed by document name.
+var DocsSchema = mustDocsSchema()
+
+var docsTable = systemTable{DocTableName, DocsSchema, ErrBadDocsSchema}
+
+func mustDocsSchema() schema.Schema {
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn(DocNameCol, docNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(DocTextCol, docTextTag, types.StringKind, false),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return schema.SchemaFromCols(colColl)
+}
+
+// GetDocNames returns the names of all documents in the root, in sorted order.
+func (root *RootValue) GetDocNames(ctx context.Context) ([]string, error) {
+	var names []string
+	err := docsTable.iterRows(ctx, root, func(r row.Row) (bool, error) {
+		names = append(names, rowString(r, docNameTag))
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// GetDoc returns the text of the document with the name given, and whether it exists.
+func (root *RootValue) GetDoc(ctx context.Context, docName string) (string, bool, error) {
+	r, ok, err := docsTable.getRow(ctx, root, row.TaggedValues{docNameTag: types.String(docName)})
+
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	return rowString(r, docTextTag), true, nil
+}
+
+// PutDoc sets the text of the document with the name given, creating the docs table if it does not already exist.
+func (root *RootValue) PutDoc(ctx context.Context, docName, text string) (*RootValue, error) {
+	return docsTable.putRow(ctx, root, row.TaggedValues{
+		docNameTag: types.String(docName),
+		docTextTag: types.String(text),
+	})
+}
+
+// RemoveDoc removes the document with the name given, returning false if it did not exist.
+func (root *RootValue) RemoveDoc(ctx context.Context, docName string) (*RootValue, bool, error) {
+	return docsTable.removeRow(ctx, root, row.TaggedValues{docNameTag: types.String(docName)})
+}
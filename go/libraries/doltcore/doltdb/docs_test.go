@@ -0,0 +1,77 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestDocs(t *testing.T) {
+	ctx := context.Background()
+	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	err := ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("head", "master")
+	cm, _ := ddb.Resolve(ctx, cs)
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	names, err := root.GetDocNames(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	_, ok, err := root.GetDoc(ctx, ReadmeDoc)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	root, err = root.PutDoc(ctx, ReadmeDoc, "# Simpsons\nCharacters and episodes.")
+	require.NoError(t, err)
+	root, err = root.PutDoc(ctx, LicenseDoc, "CC0")
+	require.NoError(t, err)
+
+	names, err = root.GetDocNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{LicenseDoc, ReadmeDoc}, names)
+
+	text, ok, err := root.GetDoc(ctx, ReadmeDoc)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "# Simpsons\nCharacters and episodes.", text)
+
+	root, err = root.PutDoc(ctx, LicenseDoc, "ODbL")
+	require.NoError(t, err)
+	text, _, err = root.GetDoc(ctx, LicenseDoc)
+	require.NoError(t, err)
+	assert.Equal(t, "ODbL", text)
+
+	root, ok, err = root.RemoveDoc(ctx, LicenseDoc)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	root, ok, err = root.RemoveDoc(ctx, LicenseDoc)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	names, err = root.GetDocNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{ReadmeDoc}, names)
+}
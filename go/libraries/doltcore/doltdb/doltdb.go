@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/utils/pantoerr"
 	"github.com/liquidata-inc/dolt/go/store/datas"
 	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/nbs"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -226,6 +228,41 @@ func getCommitStForHash(ctx context.Context, db datas.Database, c string) (types
 	return valSt, nil
 }
 
+// getCommitStForHashPrefix returns the commit reachable from any ref whose hash starts with the prefix given. It returns
+// ErrHashNotFound if there is no such commit, and ErrAmbiguousHashPrefix if there's more than one.
+func (ddb *DoltDB) getCommitStForHashPrefix(ctx context.Context, prefix string) (types.Struct, error) {
+	itr, err := CommitItrForAllBranches(ctx, ddb)
+
+	if err != nil {
+		return types.EmptyStruct(ddb.Format()), err
+	}
+
+	var match *Commit
+	for {
+		h, cm, err := itr.Next(ctx)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return types.EmptyStruct(ddb.Format()), err
+		}
+
+		if strings.HasPrefix(h.String(), prefix) {
+			if match != nil {
+				return types.EmptyStruct(ddb.Format()), ErrAmbiguousHashPrefix
+			}
+
+			match = cm
+		}
+	}
+
+	if match == nil {
+		return types.EmptyStruct(ddb.Format()), ErrHashNotFound
+	}
+
+	return match.commitSt, nil
+}
+
 func walkAncestorSpec(ctx context.Context, db datas.Database, commitSt types.Struct, aSpec *AncestorSpec) (types.Struct, error) {
 	if aSpec == nil || len(aSpec.Instructions) == 0 {
 		return commitSt, nil
@@ -271,7 +308,12 @@ func (ddb *DoltDB) Resolve(ctx context.Context, cs *CommitSpec) (*Commit, error)
 	if cs.CSType == HashCommitSpec {
 		commitSt, err = getCommitStForHash(ctx, ddb.db, cs.CommitStringer.String())
 	} else if cs.CSType == RefCommitSpec {
-		commitSt, err = getCommitStForRef(ctx, ddb.db, cs.CommitStringer.(ref.DoltRef))
+		dref := cs.CommitStringer.(ref.DoltRef)
+		commitSt, err = getCommitStForRef(ctx, ddb.db, dref)
+
+		if err == ErrBranchNotFound && dref.GetType() == ref.BranchRefType && IsHashPrefix(dref.GetPath()) {
+			commitSt, err = ddb.getCommitStForHashPrefix(ctx, dref.GetPath())
+		}
 	}
 
 	if err != nil {
@@ -325,6 +367,12 @@ func (ddb *DoltDB) ReadRootValue(ctx context.Context, h hash.Hash) (*RootValue,
 	return nil, errors.New("there is no dolt root value at that hash")
 }
 
+// Rebase brings this DoltDB's view of the database in line with its storage, picking up the values and refs written
+// to it by other processes.
+func (ddb *DoltDB) Rebase(ctx context.Context) error {
+	return ddb.db.Rebase(ctx)
+}
+
 // Commit will update a branch's head value to be that of a previously committed root value hash
 func (ddb *DoltDB) Commit(ctx context.Context, valHash hash.Hash, dref ref.DoltRef, cm *CommitMeta) (*Commit, error) {
 	if dref.GetType() != ref.BranchRefType {
@@ -478,6 +526,13 @@ func (ddb *DoltDB) ValueReadWriter() types.ValueReadWriter {
 	return ddb.db
 }
 
+// SetChunkingConfig sets the parameters that the trees of the values written to this database, such as row data, are
+// chunked with. Values already written keep the chunks they were written with until they are rewritten with
+// Table.Rechunk.
+func (ddb *DoltDB) SetChunkingConfig(cfg types.ChunkingConfig) error {
+	return types.SetChunkingConfig(ddb.db, cfg)
+}
+
 func (ddb *DoltDB) Format() *types.NomsBinFormat {
 	return ddb.db.Format()
 }
@@ -615,6 +670,27 @@ func (ddb *DoltDB) NewBranchAtCommit(ctx context.Context, dref ref.DoltRef, comm
 	return err
 }
 
+// SetRef points the ref given at the commit given, creating the ref if it doesn't exist. Unlike NewBranchAtCommit it
+// sets refs of any type, and is meant for repairing repositories, so callers are responsible for checking that the ref
+// is valid for its type.
+func (ddb *DoltDB) SetRef(ctx context.Context, dref ref.DoltRef, commit *Commit) error {
+	ds, err := ddb.db.GetDataset(ctx, dref.String())
+
+	if err != nil {
+		return err
+	}
+
+	rf, err := types.NewRef(commit.commitSt, ddb.db.Format())
+
+	if err != nil {
+		return err
+	}
+
+	_, err = ddb.db.SetHead(ctx, ds, rf)
+
+	return err
+}
+
 // DeleteBranch deletes the branch given, returning an error if it doesn't exist.
 func (ddb *DoltDB) DeleteBranch(ctx context.Context, dref ref.DoltRef) error {
 	ds, err := ddb.db.GetDataset(ctx, dref.String())
@@ -682,3 +758,113 @@ func (ddb *DoltDB) PullChunks(ctx context.Context, tempDir string, srcDB *DoltDB
 func (ddb *DoltDB) Clone(ctx context.Context, destDB *DoltDB, eventCh chan<- datas.TableFileEvent) error {
 	return datas.Clone(ctx, ddb.db, destDB.db, eventCh)
 }
+
+// StorageReport describes the table files holding the chunks of this database, how much of their data is duplicated
+// between them and how much is unreachable from any ref. |roots| are the hashes of root values referenced from outside
+// of the database, such as the working and staged roots, which are not counted as garbage.
+func (ddb *DoltDB) StorageReport(ctx context.Context, roots []hash.Hash) (nbs.StorageReport, error) {
+	return datas.StorageReport(ctx, ddb.db, roots)
+}
+
+// StoreStats returns the statistics of the NomsBlockStore this database is stored in, such as the latencies of its
+// reads and writes, and whether it's stored in one.
+func (ddb *DoltDB) StoreStats() (nbs.Stats, bool) {
+	stats, ok := ddb.db.Stats().(nbs.Stats)
+	return stats, ok
+}
+
+// ChunkCount returns the number of chunks stored in this database. Not all databases support this.
+func (ddb *DoltDB) ChunkCount() (uint32, error) {
+	return datas.ChunkCount(ddb.db)
+}
+
+// maxMissingChunksBatchSize is the number of chunks read and checked against the other database at a time by
+// CountMissingChunks.
+const maxMissingChunksBatchSize = 4 * 1024
+
+// CountMissingChunks walks the chunks reachable from the value with hash |h| in this database and checks them against
+// |otherDB|, returning how many chunks there are and how many of them |otherDB| doesn't have. Every reachable chunk is
+// checked, so chunks missing beneath chunks that |otherDB| has, as left by an interrupted push, are counted too.
+func (ddb *DoltDB) CountMissingChunks(ctx context.Context, h hash.Hash, otherDB *DoltDB) (total, missing int, err error) {
+	visited := hash.NewHashSet(h)
+	level := hash.HashSlice{h}
+
+	for len(level) > 0 {
+		var next hash.HashSlice
+		for st := 0; st < len(level); st += maxMissingChunksBatchSize {
+			end := st + maxMissingChunksBatchSize
+			if end > len(level) {
+				end = len(level)
+			}
+
+			batch := level[st:end]
+			absent, err := datas.MissingChunks(ctx, otherDB.db, hash.NewHashSet(batch...))
+
+			if err != nil {
+				return 0, 0, err
+			}
+
+			total += len(batch)
+			missing += len(absent)
+
+			vals, err := ddb.db.ReadManyValues(ctx, batch)
+
+			if err != nil {
+				return 0, 0, err
+			}
+
+			for i, val := range vals {
+				if val == nil {
+					return 0, 0, fmt.Errorf("chunk %s is missing", batch[i].String())
+				}
+
+				err = val.WalkRefs(ddb.db.Format(), func(r types.Ref) error {
+					if th := r.TargetHash(); !visited.Has(th) {
+						visited.Insert(th)
+						next = append(next, th)
+					}
+
+					return nil
+				})
+
+				if err != nil {
+					return 0, 0, err
+				}
+			}
+		}
+
+		level = next
+	}
+
+	return total, missing, nil
+}
+
+// CheckComplete walks every chunk reachable from the refs of this database and returns an error if any of them is
+// missing, as they may be from a database written by a clone or push that didn't complete.
+func (ddb *DoltDB) CheckComplete(ctx context.Context) error {
+	dss, err := ddb.db.Datasets(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if dss.Empty() {
+		return nil
+	}
+
+	h, err := dss.Hash(ddb.db.Format())
+
+	if err != nil {
+		return err
+	}
+
+	_, missing, err := ddb.CountMissingChunks(ctx, h, ddb)
+
+	if err != nil {
+		return err
+	} else if missing > 0 {
+		return fmt.Errorf("%d chunks are missing", missing)
+	}
+
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
@@ -84,6 +85,158 @@ func TestEmptyInMemoryRepoCreation(t *testing.T) {
 	}
 }
 
+func TestResolveHashPrefix(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	err = ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("HEAD", "master")
+	first, err := ddb.Resolve(ctx, cs)
+	require.NoError(t, err)
+	root, err := first.GetRootValue()
+	require.NoError(t, err)
+	rootHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "second commit")
+	require.NoError(t, err)
+	second, err := ddb.Commit(ctx, rootHash, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+
+	for _, cm := range []*Commit{first, second} {
+		h, err := cm.HashOf()
+		require.NoError(t, err)
+
+		cs, err := NewCommitSpec(h.String()[:MinHashPrefixLen+2], "master")
+		require.NoError(t, err)
+		resolved, err := ddb.Resolve(ctx, cs)
+		require.NoError(t, err)
+		resolvedHash, err := resolved.HashOf()
+		require.NoError(t, err)
+		assert.Equal(t, h, resolvedHash)
+	}
+
+	// a prefix matching every commit is ambiguous
+	_, err = ddb.getCommitStForHashPrefix(ctx, "")
+	assert.Equal(t, ErrAmbiguousHashPrefix, err)
+
+	secondHash, err := second.HashOf()
+	require.NoError(t, err)
+	missing := "0000"
+	if secondHash.String()[:4] == missing {
+		missing = "1111"
+	}
+	cs, _ = NewCommitSpec(missing, "master")
+	_, err = ddb.Resolve(ctx, cs)
+	assert.True(t, IsNotFoundErr(err))
+
+	// branches take precedence over hash prefixes with the same name
+	branchName := secondHash.String()[:6]
+	err = ddb.NewBranchAtCommit(ctx, ref.NewBranchRef(branchName), first)
+	require.NoError(t, err)
+	cs, _ = NewCommitSpec(branchName, "master")
+	resolved, err := ddb.Resolve(ctx, cs)
+	require.NoError(t, err)
+	resolvedHash, err := resolved.HashOf()
+	require.NoError(t, err)
+	firstHash, err := first.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, firstHash, resolvedHash)
+}
+
+func TestSetRef(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	err = ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("HEAD", "master")
+	first, err := ddb.Resolve(ctx, cs)
+	require.NoError(t, err)
+	firstHash, err := first.HashOf()
+	require.NoError(t, err)
+
+	// refs of any type can be created
+	remoteRef, err := ref.Parse("refs/remotes/origin/master")
+	require.NoError(t, err)
+	for _, dref := range []ref.DoltRef{remoteRef, ref.NewInternalRef("repair"), ref.NewBranchRef("other")} {
+		require.NoError(t, ddb.SetRef(ctx, dref, first))
+		cs, err := NewCommitSpec(dref.String(), "master")
+		require.NoError(t, err)
+		resolved, err := ddb.Resolve(ctx, cs)
+		require.NoError(t, err)
+		resolvedHash, err := resolved.HashOf()
+		require.NoError(t, err)
+		assert.Equal(t, firstHash, resolvedHash, dref.String())
+	}
+
+	refs, err := ddb.GetRefs(ctx)
+	require.NoError(t, err)
+	var refStrs []string
+	for _, dref := range refs {
+		refStrs = append(refStrs, dref.String())
+	}
+	assert.Subset(t, refStrs, []string{"refs/heads/master", "refs/heads/other", "refs/internal/repair", "refs/remotes/origin/master"})
+}
+
+func TestCountMissingChunks(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	err = ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+	otherDB, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+
+	// the second commit refers to the first as its parent
+	cs, _ := NewCommitSpec("HEAD", "master")
+	first, err := ddb.Resolve(ctx, cs)
+	require.NoError(t, err)
+	root, err := first.GetRootValue()
+	require.NoError(t, err)
+	rootHash, err := ddb.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "second commit")
+	require.NoError(t, err)
+	commit, err := ddb.Commit(ctx, rootHash, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+	h, err := commit.HashOf()
+	require.NoError(t, err)
+
+	total, missing, err := ddb.CountMissingChunks(ctx, h, otherDB)
+	require.NoError(t, err)
+	assert.True(t, total > 1)
+	assert.Equal(t, total, missing)
+
+	// only the second commit is missing once the first has been pushed
+	err = otherDB.PushChunks(ctx, "", ddb, first, nil, nil)
+	require.NoError(t, err)
+	_, missing, err = ddb.CountMissingChunks(ctx, h, otherDB)
+	require.NoError(t, err)
+	assert.Equal(t, 1, missing)
+
+	err = otherDB.PushChunks(ctx, "", ddb, commit, nil, nil)
+	require.NoError(t, err)
+
+	pushedTotal, missing, err := ddb.CountMissingChunks(ctx, h, otherDB)
+	require.NoError(t, err)
+	assert.Equal(t, total, pushedTotal)
+	assert.Equal(t, 0, missing)
+}
+
+func TestCheckComplete(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	assert.NoError(t, ddb.CheckComplete(ctx))
+
+	err = ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+	assert.NoError(t, ddb.CheckComplete(ctx))
+}
+
 func TestLoadNonExistentLocalFSRepo(t *testing.T) {
 	_, err := test.ChangeToTestDir("TestLoadRepo")
 
@@ -0,0 +1,152 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// HasDroppedTag returns whether a column with the tag given was dropped from the table. Dropping a column doesn't
+// rewrite the table's rows, so rows can still have values for the tags of dropped columns.
+func (t *Table) HasDroppedTag(ctx context.Context, tag uint64) (bool, error) {
+	tags, ok, err := t.getDroppedTags(ctx)
+
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return tags.Has(ctx, types.Uint(tag))
+}
+
+// SetDroppedTag returns a copy of the table which records that a column with the tag given was dropped, or not, as
+// given.
+func (t *Table) SetDroppedTag(ctx context.Context, tag uint64, dropped bool) (*Table, error) {
+	tags, ok, err := t.getDroppedTags(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		if !dropped {
+			return t, nil
+		}
+
+		tags, err = types.NewSet(ctx, t.vrw)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var se *types.SetEditor
+	if dropped {
+		se, err = tags.Edit().Insert(types.Uint(tag))
+	} else {
+		se, err = tags.Edit().Remove(types.Uint(tag))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err = se.Set(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.setDroppedTags(ctx, tags)
+}
+
+// CopyDroppedTags returns a copy of the table which also records the dropped tags of the table given. It's used to keep
+// them when a new table is built from an existing one, such as when its schema is altered.
+func (t *Table) CopyDroppedTags(ctx context.Context, from *Table) (*Table, error) {
+	fromTags, ok, err := from.getDroppedTags(ctx)
+
+	if err != nil || !ok || fromTags.Empty() {
+		return t, err
+	}
+
+	tags, ok, err := t.getDroppedTags(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return t.setDroppedTags(ctx, fromTags)
+	}
+
+	se := tags.Edit()
+	err = fromTags.IterAll(ctx, func(v types.Value) error {
+		_, err := se.Insert(v)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err = se.Set(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.setDroppedTags(ctx, tags)
+}
+
+func (t *Table) getDroppedTags(ctx context.Context) (types.Set, bool, error) {
+	val, ok, err := t.tableStruct.MaybeGet(droppedTagsKey)
+
+	if err != nil || !ok {
+		return types.EmptySet, false, err
+	}
+
+	val, err = val.(types.Ref).TargetValue(ctx, t.vrw)
+
+	if err != nil {
+		return types.EmptySet, false, err
+	}
+
+	return val.(types.Set), true, nil
+}
+
+func (t *Table) setDroppedTags(ctx context.Context, tags types.Set) (*Table, error) {
+	var updatedSt types.Struct
+	var err error
+
+	if tags.Empty() {
+		updatedSt, err = t.tableStruct.Delete(droppedTagsKey)
+	} else {
+		var tagsRef types.Ref
+		tagsRef, err = writeValAndGetRef(ctx, t.vrw, tags)
+
+		if err != nil {
+			return nil, err
+		}
+
+		updatedSt, err = t.tableStruct.Set(droppedTagsKey, tagsRef)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Table{t.vrw, updatedSt}, nil
+}
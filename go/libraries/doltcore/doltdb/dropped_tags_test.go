@@ -0,0 +1,64 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestDroppedTags(t *testing.T) {
+	ctx := context.Background()
+	db, _ := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_7_18, nil, nil)
+
+	tSchema := createTestSchema()
+	rowData, _ := createTestRowData(t, db, tSchema)
+	tbl, err := createTestTable(db, tSchema, rowData)
+	require.NoError(t, err)
+
+	dropped, err := tbl.HasDroppedTag(ctx, ageTag)
+	require.NoError(t, err)
+	assert.False(t, dropped)
+
+	tbl, err = tbl.SetDroppedTag(ctx, ageTag, true)
+	require.NoError(t, err)
+
+	dropped, err = tbl.HasDroppedTag(ctx, ageTag)
+	require.NoError(t, err)
+	assert.True(t, dropped)
+
+	// the dropped tags are kept by tables built from the table
+	copied, err := createTestTable(db, tSchema, rowData)
+	require.NoError(t, err)
+	copied, err = copied.CopyDroppedTags(ctx, tbl)
+	require.NoError(t, err)
+
+	dropped, err = copied.HasDroppedTag(ctx, ageTag)
+	require.NoError(t, err)
+	assert.True(t, dropped)
+
+	tbl, err = tbl.SetDroppedTag(ctx, ageTag, false)
+	require.NoError(t, err)
+
+	dropped, err = tbl.HasDroppedTag(ctx, ageTag)
+	require.NoError(t, err)
+	assert.False(t, dropped)
+}
@@ -0,0 +1,180 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// The expiry of an ephemeral branch is recorded by an internal ref named expires/<unix time>/<branch>, which points at
+// a commit of the branch. Keeping it out of the branch's own ref lets the branch be used like any other, and lets the
+// expiry be pushed to remotes along with it.
+const expiryRefPrefix = "expires/"
+
+// EphemeralBranch is a branch that expires, such as one holding a snapshot of the data generated by a CI job, which is
+// deleted once it has expired unless it's made permanent first.
+type EphemeralBranch struct {
+	Branch  ref.BranchRef
+	Expires time.Time
+}
+
+// Expired returns whether the branch has expired at the time given.
+func (eb EphemeralBranch) Expired(now time.Time) bool {
+	return !now.Before(eb.Expires)
+}
+
+func expiryRef(branch ref.DoltRef, expires time.Time) ref.DoltRef {
+	return ref.NewInternalRef(expiryRefPrefix + strconv.FormatInt(expires.Unix(), 10) + "/" + branch.GetPath())
+}
+
+// parseExpiryRef returns the branch and expiry recorded by the internal ref path given, and false if the path isn't
+// that of an expiry.
+func parseExpiryRef(path string) (EphemeralBranch, bool) {
+	if !strings.HasPrefix(path, expiryRefPrefix) {
+		return EphemeralBranch{}, false
+	}
+
+	parts := strings.SplitN(path[len(expiryRefPrefix):], "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return EphemeralBranch{}, false
+	}
+
+	secs, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return EphemeralBranch{}, false
+	}
+
+	return EphemeralBranch{ref.NewBranchRef(parts[1]), time.Unix(secs, 0)}, true
+}
+
+// GetEphemeralBranches returns the branches of the database that expire, in the order they expire. Only branches that
+// exist are returned.
+func (ddb *DoltDB) GetEphemeralBranches(ctx context.Context) ([]EphemeralBranch, error) {
+	ebs, err := ddb.getExpiries(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dss, err := ddb.db.Datasets(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []EphemeralBranch
+	for _, eb := range ebs {
+		if ok, err := dss.Has(ctx, types.String(eb.Branch.String())); err != nil {
+			return nil, err
+		} else if ok {
+			existing = append(existing, eb)
+		}
+	}
+
+	sort.Slice(existing, func(i, j int) bool {
+		if existing[i].Expires.Equal(existing[j].Expires) {
+			return existing[i].Branch.GetPath() < existing[j].Branch.GetPath()
+		}
+		return existing[i].Expires.Before(existing[j].Expires)
+	})
+
+	return existing, nil
+}
+
+// GetBranchExpiry returns when the branch given expires, and false if it doesn't.
+func (ddb *DoltDB) GetBranchExpiry(ctx context.Context, branch ref.DoltRef) (time.Time, bool, error) {
+	ebs, err := ddb.getExpiries(ctx)
+
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	for _, eb := range ebs {
+		if ref.Equals(eb.Branch, branch) {
+			return eb.Expires, true, nil
+		}
+	}
+
+	return time.Time{}, false, nil
+}
+
+// SetBranchExpiry makes the branch given ephemeral, expiring at the time given, replacing any expiry it had.
+func (ddb *DoltDB) SetBranchExpiry(ctx context.Context, branch ref.DoltRef, expires time.Time) error {
+	cs, err := NewCommitSpec("HEAD", branch.String())
+
+	if err != nil {
+		return err
+	}
+
+	cm, err := ddb.Resolve(ctx, cs)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := ddb.ClearBranchExpiry(ctx, branch); err != nil {
+		return err
+	}
+
+	return ddb.SetRef(ctx, expiryRef(branch, expires), cm)
+}
+
+// ClearBranchExpiry makes the branch given permanent, and returns whether it was ephemeral.
+func (ddb *DoltDB) ClearBranchExpiry(ctx context.Context, branch ref.DoltRef) (bool, error) {
+	ebs, err := ddb.getExpiries(ctx)
+
+	if err != nil {
+		return false, err
+	}
+
+	cleared := false
+	for _, eb := range ebs {
+		if !ref.Equals(eb.Branch, branch) {
+			continue
+		}
+
+		if err := ddb.DeleteBranch(ctx, expiryRef(eb.Branch, eb.Expires)); err != nil {
+			return false, err
+		}
+		cleared = true
+	}
+
+	return cleared, nil
+}
+
+// getExpiries returns all the expiries recorded in the database, including those of branches that no longer exist.
+func (ddb *DoltDB) getExpiries(ctx context.Context) ([]EphemeralBranch, error) {
+	refs, err := ddb.GetRefsOfType(ctx, map[ref.RefType]struct{}{ref.InternalRefType: {}})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var ebs []EphemeralBranch
+	for _, r := range refs {
+		if eb, ok := parseExpiryRef(r.GetPath()); ok {
+			ebs = append(ebs, eb)
+		}
+	}
+
+	return ebs, nil
+}
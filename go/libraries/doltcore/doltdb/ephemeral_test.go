@@ -0,0 +1,85 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestBranchExpiry(t *testing.T) {
+	ctx := context.Background()
+	ddb, err := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	require.NoError(t, err)
+	err = ddb.WriteEmptyRepo(ctx, "Bill Billerson", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("HEAD", "master")
+	cm, err := ddb.Resolve(ctx, cs)
+	require.NoError(t, err)
+
+	ci := ref.NewBranchRef("ci/build-1")
+	other := ref.NewBranchRef("other")
+	for _, branch := range []ref.DoltRef{ci, other} {
+		require.NoError(t, ddb.NewBranchAtCommit(ctx, branch, cm))
+	}
+
+	_, ephemeral, err := ddb.GetBranchExpiry(ctx, ci)
+	require.NoError(t, err)
+	assert.False(t, ephemeral)
+
+	expires := time.Unix(1700000000, 0)
+	require.NoError(t, ddb.SetBranchExpiry(ctx, ci, expires.Add(time.Hour)))
+	require.NoError(t, ddb.SetBranchExpiry(ctx, ci, expires))
+	require.NoError(t, ddb.SetBranchExpiry(ctx, other, expires.Add(-time.Hour)))
+
+	actual, ephemeral, err := ddb.GetBranchExpiry(ctx, ci)
+	require.NoError(t, err)
+	assert.True(t, ephemeral)
+	assert.True(t, expires.Equal(actual))
+
+	ebs, err := ddb.GetEphemeralBranches(ctx)
+	require.NoError(t, err)
+	require.Len(t, ebs, 2)
+	assert.Equal(t, "other", ebs[0].Branch.GetPath())
+	assert.Equal(t, "ci/build-1", ebs[1].Branch.GetPath())
+	assert.False(t, ebs[1].Expired(expires.Add(-time.Second)))
+	assert.True(t, ebs[1].Expired(expires))
+
+	// expiries aren't branches
+	branches, err := ddb.GetBranches(ctx)
+	require.NoError(t, err)
+	assert.Len(t, branches, 3)
+
+	cleared, err := ddb.ClearBranchExpiry(ctx, ci)
+	require.NoError(t, err)
+	assert.True(t, cleared)
+	cleared, err = ddb.ClearBranchExpiry(ctx, ci)
+	require.NoError(t, err)
+	assert.False(t, cleared)
+
+	// the expiries of branches that don't exist are ignored
+	require.NoError(t, ddb.DeleteBranch(ctx, other))
+	ebs, err = ddb.GetEphemeralBranches(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, ebs)
+}
@@ -26,8 +26,10 @@ var ErrFoundHashNotACommit = errors.New("the value retrieved for this hash is no
 
 var ErrHashNotFound = errors.New("could not find a value for this hash")
 var ErrBranchNotFound = errors.New("branch not found")
+var ErrAmbiguousHashPrefix = errors.New("hash prefix matches more than one commit")
 var ErrTableNotFound = errors.New("table not found")
 var ErrTableExists = errors.New("table already exists")
+var ErrIndexExists = errors.New("index already exists")
 var ErrAlreadyOnBranch = errors.New("Already on branch")
 
 var ErrNomsIO = errors.New("error reading from or writing to noms")
@@ -0,0 +1,589 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/atomicerr"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	indexStructName = "index"
+
+	indexTagsKey = "tags"
+	indexDataKey = "data"
+)
+
+// Index is a secondary index of a table: a named, ordered list of the columns it indexes.
+//
+// The data of an index is a map whose keys are tuples of the tags and values of the indexed columns of a row, followed
+// by the row's primary key tuple, so that rows with the same indexed values have distinct keys. Entries are ordered by
+// the indexed values, which lets rows be looked up by value or by range. The map's values are the primary key tuples of
+// the rows. Missing values are stored as null, so every row has an entry.
+type Index struct {
+	Name string
+	Tags []uint64
+}
+
+// GetIndexes returns the secondary indexes of the table, ordered by name.
+func (t *Table) GetIndexes(ctx context.Context) ([]Index, error) {
+	indexes, err := t.getIndexMap(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Index
+	err = indexes.IterAll(ctx, func(k, v types.Value) error {
+		tags, err := indexTags(v.(types.Struct))
+
+		if err != nil {
+			return err
+		}
+
+		result = append(result, Index{string(k.(types.String)), tags})
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetIndexData returns the data of the index with the name given, and whether it exists.
+func (t *Table) GetIndexData(ctx context.Context, name string) (types.Map, bool, error) {
+	indexes, err := t.getIndexMap(ctx)
+
+	if err != nil {
+		return types.EmptyMap, false, err
+	}
+
+	idxVal, ok, err := indexes.MaybeGet(ctx, types.String(name))
+
+	if err != nil || !ok {
+		return types.EmptyMap, false, err
+	}
+
+	dataVal, _, err := idxVal.(types.Struct).MaybeGet(indexDataKey)
+
+	if err != nil {
+		return types.EmptyMap, false, err
+	}
+
+	data, err := dataVal.(types.Ref).TargetValue(ctx, t.vrw)
+
+	if err != nil {
+		return types.EmptyMap, false, err
+	}
+
+	return data.(types.Map), true, nil
+}
+
+// CreateIndex returns a copy of the table with a new index of the columns with the tags given, built from the table's
+// rows. Returns ErrIndexExists if the table already has an index with the name given, and schema.ErrColNotFound if any
+// of the tags isn't in the table's schema.
+func (t *Table) CreateIndex(ctx context.Context, name string, tags []uint64) (*Table, error) {
+	indexes, err := t.getIndexMap(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if has, err := indexes.Has(ctx, types.String(name)); err != nil {
+		return nil, err
+	} else if has {
+		return nil, ErrIndexExists
+	}
+
+	if err := t.checkIndexTags(ctx, tags); err != nil {
+		return nil, err
+	}
+
+	rowData, err := t.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	emptyMap, err := types.NewMap(ctx, t.vrw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := updateIndexData(ctx, tags, emptyMap, emptyMap, rowData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	idxStruct, err := t.newIndexStruct(ctx, tags, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err = indexes.Edit().Set(types.String(name), idxStruct).Map(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.setIndexMap(ctx, indexes)
+}
+
+// CopyIndexes returns a copy of the table with the indexes of the table given, rebuilt for this table's rows. Indexes
+// with names this table already has, and indexes of columns that aren't in this table's schema, aren't copied. It's
+// used to keep the indexes of a table when a new table is built from it, such as when its schema is altered.
+func (t *Table) CopyIndexes(ctx context.Context, from *Table) (*Table, error) {
+	fromIndexes, err := from.getIndexMap(ctx)
+
+	if err != nil || fromIndexes.Len() == 0 {
+		return t, err
+	}
+
+	indexes, err := t.getIndexMap(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fromRows, err := from.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err := t.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	me := indexes.Edit()
+	err = fromIndexes.IterAll(ctx, func(k, v types.Value) error {
+		if has, err := indexes.Has(ctx, k); err != nil || has {
+			return err
+		}
+
+		tags, err := indexTags(v.(types.Struct))
+
+		if err != nil {
+			return err
+		}
+
+		if err := t.checkIndexTags(ctx, tags); err == schema.ErrColNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		idxStruct, err := t.updateIndex(ctx, v.(types.Struct), fromRows, rowData)
+
+		if err != nil {
+			return err
+		}
+
+		me.Set(k, idxStruct)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err = me.Map(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.setIndexMap(ctx, indexes)
+}
+
+// updateIndexes updates all the indexes in the table struct given, which are built from the old rows given, for the
+// new rows given.
+func (t *Table) updateIndexes(ctx context.Context, st types.Struct, oldRows, newRows types.Map) (types.Struct, error) {
+	indexes, err := t.getIndexMap(ctx)
+
+	if err != nil || indexes.Len() == 0 {
+		return st, err
+	}
+
+	me := indexes.Edit()
+	err = indexes.IterAll(ctx, func(k, v types.Value) error {
+		idxStruct, err := t.updateIndex(ctx, v.(types.Struct), oldRows, newRows)
+
+		if err != nil {
+			return err
+		}
+
+		me.Set(k, idxStruct)
+		return nil
+	})
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	indexes, err = me.Map(ctx)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	indexesRef, err := writeValAndGetRef(ctx, t.vrw, indexes)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	return st.Set(indexesKey, indexesRef)
+}
+
+// emptyIndexes returns the table struct given with the table's indexes replaced by indexes of the same columns without
+// any entries.
+func (t *Table) emptyIndexes(ctx context.Context, st types.Struct) (types.Struct, error) {
+	indexes, err := t.getIndexMap(ctx)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	emptyData, err := types.NewMap(ctx, t.vrw)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	me := indexes.Edit()
+	err = indexes.IterAll(ctx, func(k, v types.Value) error {
+		tags, err := indexTags(v.(types.Struct))
+
+		if err != nil {
+			return err
+		}
+
+		idxStruct, err := t.newIndexStruct(ctx, tags, emptyData)
+
+		if err != nil {
+			return err
+		}
+
+		me.Set(k, idxStruct)
+		return nil
+	})
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	indexes, err = me.Map(ctx)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	indexesRef, err := writeValAndGetRef(ctx, t.vrw, indexes)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	return st.Set(indexesKey, indexesRef)
+}
+
+// updateIndex returns the index struct given, which is built from the old rows given, updated for the new rows given.
+func (t *Table) updateIndex(ctx context.Context, idxStruct types.Struct, oldRows, newRows types.Map) (types.Struct, error) {
+	tags, err := indexTags(idxStruct)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	dataVal, _, err := idxStruct.MaybeGet(indexDataKey)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	data, err := dataVal.(types.Ref).TargetValue(ctx, t.vrw)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	updated, err := updateIndexData(ctx, tags, data.(types.Map), oldRows, newRows)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	return t.newIndexStruct(ctx, tags, updated)
+}
+
+func (t *Table) newIndexStruct(ctx context.Context, tags []uint64, data types.Map) (types.Struct, error) {
+	dataRef, err := writeValAndGetRef(ctx, t.vrw, data)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	tagVals := make([]types.Value, len(tags))
+	for i, tag := range tags {
+		tagVals[i] = types.Uint(tag)
+	}
+
+	tagsTpl, err := types.NewTuple(t.vrw.Format(), tagVals...)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	return types.NewStruct(t.vrw.Format(), indexStructName, types.StructData{
+		indexTagsKey: tagsTpl,
+		indexDataKey: dataRef,
+	})
+}
+
+// rechunkIndexes returns a copy of the table with the data of each of its indexes, and the map holding them, rebuilt
+// from scratch with the chunking parameters of the table's database.
+func (t *Table) rechunkIndexes(ctx context.Context) (*Table, error) {
+	indexes, err := t.getIndexMap(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var kvs []types.Value
+	err = indexes.IterAll(ctx, func(k, v types.Value) error {
+		idxStruct := v.(types.Struct)
+		tags, err := indexTags(idxStruct)
+
+		if err != nil {
+			return err
+		}
+
+		dataVal, _, err := idxStruct.MaybeGet(indexDataKey)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := dataVal.(types.Ref).TargetValue(ctx, t.vrw)
+
+		if err != nil {
+			return err
+		}
+
+		rechunked, err := data.(types.Map).Rechunk(ctx, t.vrw)
+
+		if err != nil {
+			return err
+		}
+
+		idxStruct, err = t.newIndexStruct(ctx, tags, rechunked)
+
+		if err != nil {
+			return err
+		}
+
+		kvs = append(kvs, k, idxStruct)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err = types.NewMap(ctx, t.vrw, kvs...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.setIndexMap(ctx, indexes)
+}
+
+// getIndexMap returns the map from the names of the table's indexes to their structs.
+func (t *Table) getIndexMap(ctx context.Context) (types.Map, error) {
+	val, ok, err := t.tableStruct.MaybeGet(indexesKey)
+
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	if !ok {
+		return types.NewMap(ctx, t.vrw)
+	}
+
+	val, err = val.(types.Ref).TargetValue(ctx, t.vrw)
+
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	return val.(types.Map), nil
+}
+
+func (t *Table) setIndexMap(ctx context.Context, indexes types.Map) (*Table, error) {
+	indexesRef, err := writeValAndGetRef(ctx, t.vrw, indexes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedSt, err := t.tableStruct.Set(indexesKey, indexesRef)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Table{t.vrw, updatedSt}, nil
+}
+
+// checkIndexTags returns schema.ErrColNotFound if any of the tags given isn't in the table's schema.
+func (t *Table) checkIndexTags(ctx context.Context, tags []uint64) error {
+	sch, err := t.GetSchema(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if _, ok := sch.GetAllCols().GetByTag(tag); !ok {
+			return schema.ErrColNotFound
+		}
+	}
+
+	return nil
+}
+
+func indexTags(idxStruct types.Struct) ([]uint64, error) {
+	tagsVal, _, err := idxStruct.MaybeGet(indexTagsKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []uint64
+	err = tagsVal.(types.Tuple).IterFields(func(_ uint64, v types.Value) (stop bool, err error) {
+		tags = append(tags, uint64(v.(types.Uint)))
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// updateIndexData applies the changes from the old rows to the new rows given to the index data given, which is built
+// from the old rows.
+func updateIndexData(ctx context.Context, tags []uint64, data, oldRows, newRows types.Map) (types.Map, error) {
+	ae := atomicerr.New()
+	changeChan := make(chan types.ValueChanged, 32)
+	stopChan := make(chan struct{})
+
+	go func() {
+		defer close(changeChan)
+		newRows.Diff(ctx, oldRows, ae, changeChan, stopChan)
+	}()
+
+	defer func() {
+		close(stopChan)
+		for range changeChan {
+		}
+	}()
+
+	nbf := data.Format()
+	me := data.Edit()
+	for change := range changeChan {
+		var oldKey, newKey types.Tuple
+		var err error
+
+		if change.ChangeType != types.DiffChangeAdded {
+			oldKey, err = IndexKey(nbf, tags, change.Key.(types.Tuple), change.OldValue.(types.Tuple))
+
+			if err != nil {
+				return types.EmptyMap, err
+			}
+		}
+
+		if change.ChangeType != types.DiffChangeRemoved {
+			newKey, err = IndexKey(nbf, tags, change.Key.(types.Tuple), change.NewValue.(types.Tuple))
+
+			if err != nil {
+				return types.EmptyMap, err
+			}
+		}
+
+		if change.ChangeType == types.DiffChangeModified && oldKey.Equals(newKey) {
+			continue
+		}
+
+		if change.ChangeType != types.DiffChangeAdded {
+			me.Remove(oldKey)
+		}
+
+		if change.ChangeType != types.DiffChangeRemoved {
+			me.Set(newKey, change.Key)
+		}
+	}
+
+	if err := ae.Get(); err != nil {
+		return types.EmptyMap, err
+	}
+
+	return me.Map(ctx)
+}
+
+// IndexKey returns the key of the row with the key and value tuples given in the data of an index of the columns with
+// the tags given.
+func IndexKey(nbf *types.NomsBinFormat, tags []uint64, key, val types.Tuple) (types.Tuple, error) {
+	vals, err := row.ParseTaggedValues(val)
+
+	if err != nil {
+		return types.Tuple{}, err
+	}
+
+	keyVals, err := row.ParseTaggedValues(key)
+
+	if err != nil {
+		return types.Tuple{}, err
+	}
+
+	for tag, v := range keyVals {
+		vals[tag] = v
+	}
+
+	idxVals := make([]types.Value, 0, 2*len(tags)+1)
+	for _, tag := range tags {
+		v, ok := vals[tag]
+		if !ok {
+			v = types.NullValue
+		}
+		idxVals = append(idxVals, types.Uint(tag), v)
+	}
+
+	return types.NewTuple(nbf, append(idxVals, key)...)
+}
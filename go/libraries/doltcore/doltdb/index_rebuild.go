@@ -0,0 +1,232 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// IndexDiscrepancy describes how the data of an index differed from the data rebuilt for it from the table's rows.
+type IndexDiscrepancy struct {
+	Name string
+
+	// Missing is the number of rows that had no entry in the index.
+	Missing int
+
+	// Extra is the number of entries in the index for rows that don't exist or have other values for the indexed
+	// columns.
+	Extra int
+
+	// Mismatched is the number of entries in the index with the right key but the wrong primary key as their value.
+	Mismatched int
+}
+
+// RebuildReport describes what was found to be wrong with the indexes and zone maps of a table when they were rebuilt.
+type RebuildReport struct {
+	// Indexes holds the discrepancies of each index that didn't match the table's rows, ordered by index name.
+	Indexes []IndexDiscrepancy
+
+	// StaleZoneMaps is the number of leaf chunks of the table's row data whose zone map was missing or wrong.
+	StaleZoneMaps int
+}
+
+// IsConsistent returns true if nothing was found to be wrong.
+func (r RebuildReport) IsConsistent() bool {
+	return len(r.Indexes) == 0 && r.StaleZoneMaps == 0
+}
+
+// RebuildIndexes returns a copy of the table with the data of each of its indexes, and its zone maps, rebuilt from the
+// table's rows, along with a report of the differences between what was stored and what was rebuilt. Returns
+// schema.ErrColNotFound if an index refers to a column that isn't in the table's schema.
+func (t *Table) RebuildIndexes(ctx context.Context) (*Table, RebuildReport, error) {
+	var report RebuildReport
+	rowData, err := t.GetRowData(ctx)
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	emptyMap, err := types.NewMap(ctx, t.vrw)
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	indexes, err := t.getIndexMap(ctx)
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	var kvs []types.Value
+	err = indexes.IterAll(ctx, func(k, v types.Value) error {
+		name := string(k.(types.String))
+		tags, err := indexTags(v.(types.Struct))
+
+		if err != nil {
+			return err
+		}
+
+		if err := t.checkIndexTags(ctx, tags); err != nil {
+			return err
+		}
+
+		data, _, err := t.GetIndexData(ctx, name)
+
+		if err != nil {
+			return err
+		}
+
+		rebuilt, err := updateIndexData(ctx, tags, emptyMap, emptyMap, rowData)
+
+		if err != nil {
+			return err
+		}
+
+		discrepancy, err := compareIndexData(ctx, name, data, rebuilt)
+
+		if err != nil {
+			return err
+		}
+
+		if discrepancy != nil {
+			report.Indexes = append(report.Indexes, *discrepancy)
+		}
+
+		idxStruct, err := t.newIndexStruct(ctx, tags, rebuilt)
+
+		if err != nil {
+			return err
+		}
+
+		kvs = append(kvs, k, idxStruct)
+		return nil
+	})
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	rebuiltTbl := t
+
+	if len(kvs) > 0 {
+		indexes, err = types.NewMap(ctx, t.vrw, kvs...)
+
+		if err != nil {
+			return nil, report, err
+		}
+
+		rebuiltTbl, err = t.setIndexMap(ctx, indexes)
+
+		if err != nil {
+			return nil, report, err
+		}
+	}
+
+	oldZoneMaps, err := getZoneMaps(ctx, t.vrw, t.tableStruct)
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	updatedSt, err := rebuiltTbl.tableStruct.Delete(zoneMapsKey)
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	updatedSt, err = updateZoneMaps(ctx, t.vrw, updatedSt, rowData)
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	newZoneMaps, err := getZoneMaps(ctx, t.vrw, updatedSt)
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	err = newZoneMaps.IterAll(ctx, func(k, v types.Value) error {
+		old, ok, err := oldZoneMaps.MaybeGet(ctx, k)
+
+		if err != nil {
+			return err
+		}
+
+		if !ok || !old.Equals(v) {
+			report.StaleZoneMaps++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, report, err
+	}
+
+	return &Table{t.vrw, updatedSt}, report, nil
+}
+
+// compareIndexData returns the discrepancy between the data of the index with the name given and the data rebuilt for
+// it, or nil if they match.
+func compareIndexData(ctx context.Context, name string, data, rebuilt types.Map) (*IndexDiscrepancy, error) {
+	if data.Equals(rebuilt) {
+		return nil, nil
+	}
+
+	discrepancy := IndexDiscrepancy{Name: name}
+	err := rebuilt.IterAll(ctx, func(k, v types.Value) error {
+		stored, ok, err := data.MaybeGet(ctx, k)
+
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			discrepancy.Missing++
+		} else if !stored.Equals(v) {
+			discrepancy.Mismatched++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = data.IterAll(ctx, func(k, v types.Value) error {
+		has, err := rebuilt.Has(ctx, k)
+
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			discrepancy.Extra++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &discrepancy, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestRebuildIndexes(t *testing.T) {
+	ctx := context.Background()
+	db, _ := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_7_18, nil, nil)
+
+	sch := createTestSchema()
+	rowData, rows := createTestRowData(t, db, sch)
+	tbl, err := createTestTable(db, sch, rowData)
+	require.NoError(t, err)
+
+	tbl, err = tbl.CreateIndex(ctx, "age_idx", []uint64{ageTag})
+	require.NoError(t, err)
+
+	// an index that matches the rows is left as it is
+	rebuilt, report, err := tbl.RebuildIndexes(ctx)
+	require.NoError(t, err)
+	assert.True(t, report.IsConsistent())
+	assert.Equal(t, indexedIds(t, tbl, "age_idx"), indexedIds(t, rebuilt, "age_idx"))
+
+	// corrupt the index by removing the entry of one row and adding one for a row with a value it doesn't have
+	data, _, err := tbl.GetIndexData(ctx, "age_idx")
+	require.NoError(t, err)
+
+	indexKey := func(r row.Row) (types.Tuple, types.Tuple) {
+		key, err := r.NomsMapKey(sch).Value(ctx)
+		require.NoError(t, err)
+		val, err := r.NomsMapValue(sch).Value(ctx)
+		require.NoError(t, err)
+		idxKey, err := IndexKey(db.Format(), []uint64{ageTag}, key.(types.Tuple), val.(types.Tuple))
+		require.NoError(t, err)
+		return idxKey, key.(types.Tuple)
+	}
+
+	removedKey, _ := indexKey(rows[0])
+	older, err := rows[1].SetColVal(ageTag, types.Uint(99), sch)
+	require.NoError(t, err)
+	extraKey, olderKey := indexKey(older)
+
+	data, err = data.Edit().Remove(removedKey).Set(extraKey, olderKey).Map(ctx)
+	require.NoError(t, err)
+	idxStruct, err := tbl.newIndexStruct(ctx, []uint64{ageTag}, data)
+	require.NoError(t, err)
+	indexes, err := types.NewMap(ctx, db, types.String("age_idx"), idxStruct)
+	require.NoError(t, err)
+	corrupt, err := tbl.setIndexMap(ctx, indexes)
+	require.NoError(t, err)
+
+	rebuilt, report, err = corrupt.RebuildIndexes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []IndexDiscrepancy{{Name: "age_idx", Missing: 1, Extra: 1}}, report.Indexes)
+	assert.Equal(t, indexedIds(t, tbl, "age_idx"), indexedIds(t, rebuilt, "age_idx"))
+
+	_, report, err = rebuilt.RebuildIndexes(ctx)
+	require.NoError(t, err)
+	assert.True(t, report.IsConsistent())
+}
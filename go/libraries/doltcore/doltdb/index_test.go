@@ -0,0 +1,112 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestIndexes(t *testing.T) {
+	ctx := context.Background()
+	db, _ := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_7_18, nil, nil)
+
+	sch := createTestSchema()
+	rowData, rows := createTestRowData(t, db, sch)
+	tbl, err := createTestTable(db, sch, rowData)
+	require.NoError(t, err)
+
+	tbl, err = tbl.CreateIndex(ctx, "age_idx", []uint64{ageTag})
+	require.NoError(t, err)
+
+	indexes, err := tbl.GetIndexes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Index{{"age_idx", []uint64{ageTag}}}, indexes)
+
+	_, err = tbl.CreateIndex(ctx, "age_idx", []uint64{firstTag})
+	assert.Equal(t, ErrIndexExists, err)
+	_, err = tbl.CreateIndex(ctx, "bad_idx", []uint64{1234})
+	assert.Equal(t, schema.ErrColNotFound, err)
+
+	// rows 0 and 2 have the same age, so they're ordered by primary key
+	sameAge := []uuid.UUID{id0, id2}
+	if bytes.Compare(id2[:], id0[:]) < 0 {
+		sameAge = []uuid.UUID{id2, id0}
+	}
+	assert.Equal(t, append([]uuid.UUID{id1, id3}, sameAge...), indexedIds(t, tbl, "age_idx"))
+
+	older, err := rows[1].SetColVal(ageTag, types.Uint(60), sch)
+	require.NoError(t, err)
+	rowData, err = rowData.Edit().
+		Set(older.NomsMapKey(sch), older.NomsMapValue(sch)).
+		Remove(rows[3].NomsMapKey(sch)).
+		Map(ctx)
+	require.NoError(t, err)
+
+	tbl, err = tbl.UpdateRows(ctx, rowData)
+	require.NoError(t, err)
+	assert.Equal(t, append(sameAge, id1), indexedIds(t, tbl, "age_idx"))
+
+	// copying the indexes to a table with the same rows gives the same index
+	copied, err := createTestTable(db, sch, rowData)
+	require.NoError(t, err)
+	copied, err = copied.CopyIndexes(ctx, tbl)
+	require.NoError(t, err)
+	assert.Equal(t, indexedIds(t, tbl, "age_idx"), indexedIds(t, copied, "age_idx"))
+
+	// indexes of columns that aren't in the new table's schema aren't copied
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("id", idTag, types.UUIDKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("first", firstTag, types.StringKind, false, schema.NotNullConstraint{}),
+	)
+	require.NoError(t, err)
+	copied, err = createTestTable(db, schema.SchemaFromCols(colColl), rowData)
+	require.NoError(t, err)
+	copied, err = copied.CopyIndexes(ctx, tbl)
+	require.NoError(t, err)
+	indexes, err = copied.GetIndexes(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, indexes)
+}
+
+// indexedIds returns the ids of the rows in the index named, in index order.
+func indexedIds(t *testing.T, tbl *Table, name string) []uuid.UUID {
+	data, ok, err := tbl.GetIndexData(context.Background(), name)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	var ids []uuid.UUID
+	err = data.IterAll(context.Background(), func(k, v types.Value) error {
+		id, err := v.(types.Tuple).Get(1)
+		if err != nil {
+			return err
+		}
+
+		ids = append(ids, uuid.UUID(id.(types.UUID)))
+		return nil
+	})
+	require.NoError(t, err)
+
+	return ids
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	// MappingPresetsTableName is the name of the system table holding named import mappings, which map the fields of
+	// files being imported to the columns of tables. It is stored in the root like any other table, so that everyone
+	// working with the repository imports recurring files the same way.
+	MappingPresetsTableName = "dolt_mapping_presets"
+
+	MappingPresetNameCol    = "name"
+	MappingPresetMappingCol = "mapping"
+)
+
+const (
+	mappingPresetNameTag uint64 = iota
+	mappingPresetMappingTag
+)
+
+var ErrBadMappingPresetsSchema = errors.New("the " + MappingPresetsTableName + " table does not have the expected schema")
+
+// MappingPresetsSchema is the schema of the mapping presets table, keyed by preset name. Mappings are stored as the
+// json text of a mapping file.
+var MappingPresetsSchema = mustMappingPresetsSchema()
+
+var mappingPresetsTable = systemTable{MappingPresetsTableName, MappingPresetsSchema, ErrBadMappingPresetsSchema}
+
+func mustMappingPresetsSchema() schema.Schema {
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn(MappingPresetNameCol, mappingPresetNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(MappingPresetMappingCol, mappingPresetMappingTag, types.StringKind, false),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return schema.SchemaFromCols(colColl)
+}
+
+// GetMappingPreset returns the mapping of the preset with the name given, and whether it exists.
+func (root *RootValue) GetMappingPreset(ctx context.Context, name string) (string, bool, error) {
+	r, ok, err := mappingPresetsTable.getRow(ctx, root, row.TaggedValues{mappingPresetNameTag: types.String(name)})
+
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	return rowString(r, mappingPresetMappingTag), true, nil
+}
+
+// PutMappingPreset sets the mapping of the preset with the name given, creating the mapping presets table if it does
+// not already exist.
+func (root *RootValue) PutMappingPreset(ctx context.Context, name, mapping string) (*RootValue, error) {
+	return mappingPresetsTable.putRow(ctx, root, row.TaggedValues{
+		mappingPresetNameTag:    types.String(name),
+		mappingPresetMappingTag: types.String(mapping),
+	})
+}
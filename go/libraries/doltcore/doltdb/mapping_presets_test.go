@@ -0,0 +1,62 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestMappingPresets(t *testing.T) {
+	ctx := context.Background()
+	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	err := ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("head", "master")
+	cm, _ := ddb.Resolve(ctx, cs)
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	_, ok, err := root.GetMappingPreset(ctx, "vendor_a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	root, err = root.PutMappingPreset(ctx, "vendor_a", `{"fname":"first"}`)
+	require.NoError(t, err)
+	root, err = root.PutMappingPreset(ctx, "vendor_b", `{"given_name":"first"}`)
+	require.NoError(t, err)
+
+	mapping, ok, err := root.GetMappingPreset(ctx, "vendor_a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, `{"fname":"first"}`, mapping)
+
+	root, err = root.PutMappingPreset(ctx, "vendor_a", `{"first_name":"first"}`)
+	require.NoError(t, err)
+
+	mapping, _, err = root.GetMappingPreset(ctx, "vendor_a")
+	require.NoError(t, err)
+	assert.Equal(t, `{"first_name":"first"}`, mapping)
+
+	mapping, _, err = root.GetMappingPreset(ctx, "vendor_b")
+	require.NoError(t, err)
+	assert.Equal(t, `{"given_name":"first"}`, mapping)
+}
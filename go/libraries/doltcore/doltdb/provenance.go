@@ -0,0 +1,143 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	// ProvenanceTableName is the name of the system table holding provenance metadata for tables and columns. It is
+	// stored in the root like any other table, so it is versioned along with the data it describes.
+	ProvenanceTableName = "dolt_provenance"
+
+	ProvenanceTableCol     = "table_name"
+	ProvenanceColumnCol    = "column_name"
+	ProvenanceSourceURLCol = "source_url"
+	ProvenanceLicenseCol   = "license"
+	ProvenanceExtractedCol = "extracted"
+)
+
+const (
+	provenanceTableTag uint64 = iota
+	provenanceColumnTag
+	provenanceSourceURLTag
+	provenanceLicenseTag
+	provenanceExtractedTag
+)
+
+var ErrBadProvenanceSchema = errors.New("the " + ProvenanceTableName + " table does not have the expected schema")
+
+// ProvenanceSchema is the schema of the provenance table. Entries are keyed by table and column name, with an empty
+// column name used for metadata that applies to the table as a whole.
+var ProvenanceSchema = mustProvenanceSchema()
+
+var provenanceTable = systemTable{ProvenanceTableName, ProvenanceSchema, ErrBadProvenanceSchema}
+
+func mustProvenanceSchema() schema.Schema {
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn(ProvenanceTableCol, provenanceTableTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(ProvenanceColumnCol, provenanceColumnTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(ProvenanceSourceURLCol, provenanceSourceURLTag, types.StringKind, false),
+		schema.NewColumn(ProvenanceLicenseCol, provenanceLicenseTag, types.StringKind, false),
+		schema.NewColumn(ProvenanceExtractedCol, provenanceExtractedTag, types.StringKind, false),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return schema.SchemaFromCols(colColl)
+}
+
+// Provenance describes where the data in a table, or in a single column of a table, came from.
+type Provenance struct {
+	TableName  string
+	ColumnName string
+	SourceURL  string
+	License    string
+	Extracted  string
+}
+
+// IsTableLevel returns true if this provenance applies to the whole table rather than one of its columns.
+func (p Provenance) IsTableLevel() bool {
+	return p.ColumnName == ""
+}
+
+// GetProvenance returns the provenance entries recorded for the table given, ordered with the table level entry first
+// followed by column entries sorted by column name. Returns an empty slice if nothing has been recorded.
+func (root *RootValue) GetProvenance(ctx context.Context, tblName string) ([]Provenance, error) {
+	start := row.TaggedValues{provenanceTableTag: types.String(tblName), provenanceColumnTag: types.String("")}
+
+	var provs []Provenance
+	err := provenanceTable.iterRowsFrom(ctx, root, start, func(r row.Row) (bool, error) {
+		p := provenanceFromRow(r)
+
+		if p.TableName != tblName {
+			return true, nil
+		}
+
+		provs = append(provs, p)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provs, nil
+}
+
+// PutProvenance records the provenance given, replacing any existing entry for the same table and column. The
+// provenance table is created if it does not already exist.
+func (root *RootValue) PutProvenance(ctx context.Context, p Provenance) (*RootValue, error) {
+	return provenanceTable.putRow(ctx, root, provenanceToTaggedValues(p))
+}
+
+func provenanceToTaggedValues(p Provenance) row.TaggedValues {
+	taggedVals := row.TaggedValues{
+		provenanceTableTag:  types.String(p.TableName),
+		provenanceColumnTag: types.String(p.ColumnName),
+	}
+
+	if p.SourceURL != "" {
+		taggedVals[provenanceSourceURLTag] = types.String(p.SourceURL)
+	}
+
+	if p.License != "" {
+		taggedVals[provenanceLicenseTag] = types.String(p.License)
+	}
+
+	if p.Extracted != "" {
+		taggedVals[provenanceExtractedTag] = types.String(p.Extracted)
+	}
+
+	return taggedVals
+}
+
+func provenanceFromRow(r row.Row) Provenance {
+	return Provenance{
+		TableName:  rowString(r, provenanceTableTag),
+		ColumnName: rowString(r, provenanceColumnTag),
+		SourceURL:  rowString(r, provenanceSourceURLTag),
+		License:    rowString(r, provenanceLicenseTag),
+		Extracted:  rowString(r, provenanceExtractedTag),
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestProvenance(t *testing.T) {
+	ctx := context.Background()
+	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	err := ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("head", "master")
+	cm, _ := ddb.Resolve(ctx, cs)
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	provs, err := root.GetProvenance(ctx, "people")
+	require.NoError(t, err)
+	assert.Empty(t, provs)
+
+	peopleTbl := Provenance{TableName: "people", SourceURL: "https://example.com/people.csv", License: "CC0"}
+	peopleAge := Provenance{TableName: "people", ColumnName: "age", SourceURL: "https://example.com/ages.csv", Extracted: "2019-12-01"}
+	places := Provenance{TableName: "places", License: "MIT"}
+	pets := Provenance{TableName: "pets", ColumnName: "name", License: "MIT"}
+
+	for _, p := range []Provenance{peopleAge, places, peopleTbl, pets} {
+		root, err = root.PutProvenance(ctx, p)
+		require.NoError(t, err)
+	}
+
+	has, err := root.HasTable(ctx, ProvenanceTableName)
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	provs, err = root.GetProvenance(ctx, "people")
+	require.NoError(t, err)
+	assert.Equal(t, []Provenance{peopleTbl, peopleAge}, provs)
+	assert.True(t, provs[0].IsTableLevel())
+	assert.False(t, provs[1].IsTableLevel())
+
+	provs, err = root.GetProvenance(ctx, "places")
+	require.NoError(t, err)
+	assert.Equal(t, []Provenance{places}, provs)
+
+	peopleTbl.License = "ODbL"
+	root, err = root.PutProvenance(ctx, peopleTbl)
+	require.NoError(t, err)
+
+	provs, err = root.GetProvenance(ctx, "people")
+	require.NoError(t, err)
+	assert.Equal(t, []Provenance{peopleTbl, peopleAge}, provs)
+}
+
+func TestProvenanceBadSchema(t *testing.T) {
+	ctx := context.Background()
+	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	err := ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("head", "master")
+	cm, _ := ddb.Resolve(ctx, cs)
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	m, err := types.NewMap(ctx, ddb.ValueReadWriter())
+	require.NoError(t, err)
+	tbl, err := createTestTable(ddb.ValueReadWriter(), createTestSchema(), m)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, ProvenanceTableName, tbl)
+	require.NoError(t, err)
+
+	_, err = root.GetProvenance(ctx, "people")
+	assert.Equal(t, ErrBadProvenanceSchema, err)
+
+	_, err = root.PutProvenance(ctx, Provenance{TableName: "people", License: "MIT"})
+	assert.Equal(t, ErrBadProvenanceSchema, err)
+}
@@ -399,6 +399,48 @@ func (root *RootValue) TableDiff(ctx context.Context, other *RootValue) (added,
 	return added, modified, removed, nil
 }
 
+// TableRenames pairs up the tables added and removed when compared with another root value, as returned by TableDiff,
+// whose contents are identical. Returns the tables renamed as a map from their names in the other root value to their
+// names in this one, along with the tables added and removed that weren't renamed.
+func (root *RootValue) TableRenames(ctx context.Context, other *RootValue, added, removed []string) (renamed map[string]string, stillAdded, stillRemoved []string, err error) {
+	removedByHash := make(map[hash.Hash][]string)
+	for _, tblName := range removed {
+		h, ok, err := other.GetTableHash(ctx, tblName)
+
+		if err != nil {
+			return nil, nil, nil, err
+		} else if ok {
+			removedByHash[h] = append(removedByHash[h], tblName)
+		}
+	}
+
+	renamed = make(map[string]string)
+	stillAdded = make([]string, 0, len(added))
+	for _, tblName := range added {
+		h, ok, err := root.GetTableHash(ctx, tblName)
+
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if candidates := removedByHash[h]; ok && len(candidates) > 0 {
+			renamed[candidates[0]] = tblName
+			removedByHash[h] = candidates[1:]
+		} else {
+			stillAdded = append(stillAdded, tblName)
+		}
+	}
+
+	stillRemoved = make([]string, 0, len(removed))
+	for _, tblName := range removed {
+		if _, ok := renamed[tblName]; !ok {
+			stillRemoved = append(stillRemoved, tblName)
+		}
+	}
+
+	return renamed, stillAdded, stillRemoved, nil
+}
+
 func (root *RootValue) UpdateTablesFromOther(ctx context.Context, tblNames []string, other *RootValue) (*RootValue, error) {
 	tableMap, err := root.getTableMap()
 
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
@@ -98,3 +99,51 @@ func TestTableDiff(t *testing.T) {
 		t.Error("Bad table diff after adding a second table")
 	}
 }
+
+func TestTableRenames(t *testing.T) {
+	ctx := context.Background()
+	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse")
+
+	cs, _ := NewCommitSpec("head", "master")
+	cm, _ := ddb.Resolve(ctx, cs)
+
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	sch := createTestSchema()
+	m, err := types.NewMap(ctx, ddb.ValueReadWriter())
+	require.NoError(t, err)
+	rowData, rows := createTestRowData(t, ddb.ValueReadWriter(), sch)
+	fewerRows, err := rowData.Edit().Remove(rows[0].NomsMapKey(sch)).Map(ctx)
+	require.NoError(t, err)
+
+	emptyTbl, err := createTestTable(ddb.ValueReadWriter(), sch, m)
+	require.NoError(t, err)
+	tbl, err := createTestTable(ddb.ValueReadWriter(), sch, rowData)
+	require.NoError(t, err)
+	otherTbl, err := createTestTable(ddb.ValueReadWriter(), sch, fewerRows)
+	require.NoError(t, err)
+
+	older, err := root.PutTable(ctx, "people", tbl)
+	require.NoError(t, err)
+	older, err = older.PutTable(ctx, "dropped", emptyTbl)
+	require.NoError(t, err)
+
+	// people is renamed, dropped is removed, and the table added matches neither
+	newer, err := older.RemoveTables(ctx, "people", "dropped")
+	require.NoError(t, err)
+	newer, err = newer.PutTable(ctx, "persons", tbl)
+	require.NoError(t, err)
+	newer, err = newer.PutTable(ctx, "added", otherTbl)
+	require.NoError(t, err)
+
+	added, _, removed, err := newer.TableDiff(ctx, older)
+	require.NoError(t, err)
+	renamed, added, removed, err := newer.TableRenames(ctx, older, added, removed)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"people": "persons"}, renamed)
+	assert.Equal(t, []string{"added"}, added)
+	assert.Equal(t, []string{"dropped"}, removed)
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	// SchemasTableName is the name of the system table holding schema fragments, such as the definitions of views. It
+	// is stored in the root like any other table, so fragments are versioned along with the tables they refer to.
+	SchemasTableName = "dolt_schemas"
+
+	SchemasTypeCol     = "type"
+	SchemasNameCol     = "name"
+	SchemasFragmentCol = "fragment"
+
+	// ViewFragmentType is the type of the fragments defining views, whose text is the view's select statement
+	ViewFragmentType = "view"
+	// MaterializedViewFragmentType is the type of the fragments defining materialized views, whose text is the select
+	// statement whose results are stored in the table with the view's name
+	MaterializedViewFragmentType = "materialized view"
+)
+
+const (
+	schemasTypeTag uint64 = iota
+	schemasNameTag
+	schemasFragmentTag
+)
+
+var ErrBadSchemasSchema = errors.New("the " + SchemasTableName + " table does not have the expected schema")
+
+// SchemasSchema is the schema of the schemas table, keyed by fragment type and name.
+var SchemasSchema = mustSchemasSchema()
+
+var schemasTable = systemTable{SchemasTableName, SchemasSchema, ErrBadSchemasSchema}
+
+func mustSchemasSchema() schema.Schema {
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn(SchemasTypeCol, schemasTypeTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(SchemasNameCol, schemasNameTag, types.StringKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn(SchemasFragmentCol, schemasFragmentTag, types.StringKind, false),
+	)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return schema.SchemaFromCols(colColl)
+}
+
+// SchemaFragment is a named piece of schema stored in the schemas table, such as a view definition.
+type SchemaFragment struct {
+	Type     string
+	Name     string
+	Fragment string
+}
+
+// GetSchemaFragments returns the schema fragments of the type given, sorted by name.
+func (root *RootValue) GetSchemaFragments(ctx context.Context, fragType string) ([]SchemaFragment, error) {
+	var frags []SchemaFragment
+	err := schemasTable.iterRows(ctx, root, func(r row.Row) (bool, error) {
+		frag := schemaFragmentFromRow(r)
+
+		if frag.Type == fragType {
+			frags = append(frags, frag)
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return frags, nil
+}
+
+// GetSchemaFragment returns the schema fragment with the type and name given, and whether it exists.
+func (root *RootValue) GetSchemaFragment(ctx context.Context, fragType, name string) (SchemaFragment, bool, error) {
+	r, ok, err := schemasTable.getRow(ctx, root, schemaFragmentKey(fragType, name))
+
+	if err != nil || !ok {
+		return SchemaFragment{}, false, err
+	}
+
+	return schemaFragmentFromRow(r), true, nil
+}
+
+// PutSchemaFragment stores the schema fragment given, replacing any existing fragment with the same type and name. The
+// schemas table is created if it does not already exist.
+func (root *RootValue) PutSchemaFragment(ctx context.Context, frag SchemaFragment) (*RootValue, error) {
+	taggedVals := schemaFragmentKey(frag.Type, frag.Name)
+	taggedVals[schemasFragmentTag] = types.String(frag.Fragment)
+
+	return schemasTable.putRow(ctx, root, taggedVals)
+}
+
+// RemoveSchemaFragment removes the schema fragment with the type and name given, returning false if it did not exist.
+func (root *RootValue) RemoveSchemaFragment(ctx context.Context, fragType, name string) (*RootValue, bool, error) {
+	return schemasTable.removeRow(ctx, root, schemaFragmentKey(fragType, name))
+}
+
+func schemaFragmentKey(fragType, name string) row.TaggedValues {
+	return row.TaggedValues{schemasTypeTag: types.String(fragType), schemasNameTag: types.String(name)}
+}
+
+func schemaFragmentFromRow(r row.Row) SchemaFragment {
+	return SchemaFragment{
+		Type:     rowString(r, schemasTypeTag),
+		Name:     rowString(r, schemasNameTag),
+		Fragment: rowString(r, schemasFragmentTag),
+	}
+}
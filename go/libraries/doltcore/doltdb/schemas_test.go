@@ -0,0 +1,79 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestSchemaFragments(t *testing.T) {
+	ctx := context.Background()
+	ddb, _ := LoadDoltDB(ctx, types.Format_7_18, InMemDoltDB)
+	err := ddb.WriteEmptyRepo(ctx, "billy bob", "bigbillieb@fake.horse")
+	require.NoError(t, err)
+
+	cs, _ := NewCommitSpec("head", "master")
+	cm, _ := ddb.Resolve(ctx, cs)
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+
+	frags, err := root.GetSchemaFragments(ctx, ViewFragmentType)
+	require.NoError(t, err)
+	assert.Empty(t, frags)
+
+	adults := SchemaFragment{ViewFragmentType, "adults", "select * from people where age >= 18"}
+	minors := SchemaFragment{ViewFragmentType, "minors", "select * from people where age < 18"}
+	other := SchemaFragment{"trigger", "adults", "insert into log values (1)"}
+
+	for _, frag := range []SchemaFragment{minors, adults, other} {
+		root, err = root.PutSchemaFragment(ctx, frag)
+		require.NoError(t, err)
+	}
+
+	frags, err = root.GetSchemaFragments(ctx, ViewFragmentType)
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaFragment{adults, minors}, frags)
+
+	adults.Fragment = "select * from people where age >= 21"
+	root, err = root.PutSchemaFragment(ctx, adults)
+	require.NoError(t, err)
+
+	frag, ok, err := root.GetSchemaFragment(ctx, ViewFragmentType, "adults")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, adults, frag)
+
+	root, ok, err = root.RemoveSchemaFragment(ctx, ViewFragmentType, "adults")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	root, ok, err = root.RemoveSchemaFragment(ctx, ViewFragmentType, "adults")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = root.GetSchemaFragment(ctx, ViewFragmentType, "adults")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	frags, err = root.GetSchemaFragments(ctx, "trigger")
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaFragment{other}, frags)
+}
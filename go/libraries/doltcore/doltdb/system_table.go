@@ -0,0 +1,244 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// systemTable is a table with a fixed schema that dolt stores in the root like any other table to hold repository
+// metadata, such as documents or view definitions, so that the metadata is versioned along with the data.
+type systemTable struct {
+	name string
+	sch  schema.Schema
+
+	// errBadSchema is returned when the table in the root does not have the expected schema
+	errBadSchema error
+}
+
+// get returns the table from the root given, and false if it does not exist.
+func (st systemTable) get(ctx context.Context, root *RootValue) (*Table, bool, error) {
+	tbl, ok, err := root.GetTable(ctx, st.name)
+
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if eq, err := schema.SchemasAreEqual(sch, st.sch); err != nil {
+		return nil, false, err
+	} else if !eq {
+		return nil, false, st.errBadSchema
+	}
+
+	return tbl, true, nil
+}
+
+// getRow returns the row with the primary key values given, and whether it exists.
+func (st systemTable) getRow(ctx context.Context, root *RootValue, pkVals row.TaggedValues) (row.Row, bool, error) {
+	tbl, ok, err := st.get(ctx, root)
+
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return tbl.GetRowByPKVals(ctx, pkVals, st.sch)
+}
+
+// iterRows calls cb with each row of the table in primary key order, until cb returns true or an error.
+func (st systemTable) iterRows(ctx context.Context, root *RootValue, cb func(r row.Row) (stop bool, err error)) error {
+	return st.iterRowsFrom(ctx, root, nil, cb)
+}
+
+// iterRowsFrom calls cb with each row of the table in primary key order, starting at the first row whose primary key
+// is not less than the primary key values given, until cb returns true or an error. Iteration starts at the first row
+// if startPKVals is nil.
+func (st systemTable) iterRowsFrom(ctx context.Context, root *RootValue, startPKVals row.TaggedValues, cb func(r row.Row) (stop bool, err error)) error {
+	tbl, ok, err := st.get(ctx, root)
+
+	if err != nil || !ok {
+		return err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	var itr types.MapIterator
+	if startPKVals == nil {
+		itr, err = rowData.Iterator(ctx)
+	} else {
+		var startKey types.Value
+		startKey, err = startPKVals.NomsTupleForTags(root.VRW().Format(), st.sch.GetPKCols().Tags, true).Value(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		itr, err = rowData.IteratorFrom(ctx, startKey)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for {
+		k, v, err := itr.Next(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		if k == nil {
+			return nil
+		}
+
+		r, err := row.FromNoms(st.sch, k.(types.Tuple), v.(types.Tuple))
+
+		if err != nil {
+			return err
+		}
+
+		if stop, err := cb(r); err != nil || stop {
+			return err
+		}
+	}
+}
+
+// putRow stores a row with the values given, replacing any existing row with the same primary key. The table is
+// created if it does not already exist.
+func (st systemTable) putRow(ctx context.Context, root *RootValue, taggedVals row.TaggedValues) (*RootValue, error) {
+	tbl, ok, err := st.get(ctx, root)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		tbl, err = newEmptyTable(ctx, root.VRW(), st.sch)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := row.New(root.VRW().Format(), st.sch, taggedVals)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err = rowData.Edit().Set(r.NomsMapKey(st.sch), r.NomsMapValue(st.sch)).Map(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err = tbl.UpdateRows(ctx, rowData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return root.PutTable(ctx, st.name, tbl)
+}
+
+// removeRow removes the row with the primary key values given, returning false if it did not exist.
+func (st systemTable) removeRow(ctx context.Context, root *RootValue, pkVals row.TaggedValues) (*RootValue, bool, error) {
+	tbl, ok, err := st.get(ctx, root)
+
+	if err != nil || !ok {
+		return root, false, err
+	}
+
+	key, err := pkVals.NomsTupleForTags(root.VRW().Format(), st.sch.GetPKCols().Tags, true).Value(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	if has, err := rowData.Has(ctx, key); err != nil || !has {
+		return root, false, err
+	}
+
+	rowData, err = rowData.Edit().Remove(key).Map(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	tbl, err = tbl.UpdateRows(ctx, rowData)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	root, err = root.PutTable(ctx, st.name, tbl)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return root, true, nil
+}
+
+func newEmptyTable(ctx context.Context, vrw types.ValueReadWriter, sch schema.Schema) (*Table, error) {
+	schVal, err := encoding.MarshalAsNomsValue(ctx, vrw, sch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := types.NewMap(ctx, vrw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTable(ctx, vrw, schVal, m)
+}
+
+// rowString returns the value of the string column with the tag given, or "" if it's missing or NULL.
+func rowString(r row.Row, tag uint64) string {
+	if v, ok := r.GetColVal(tag); ok && !types.IsNull(v) {
+		return string(v.(types.String))
+	}
+
+	return ""
+}
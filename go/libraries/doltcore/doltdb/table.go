@@ -33,6 +33,10 @@ const (
 	tableRowsKey       = "rows"
 	conflictsKey       = "conflicts"
 	conflictSchemasKey = "conflict_schemas"
+	indexesKey         = "indexes"
+	zoneMapsKey        = "zone_maps"
+	appendOnlyKey      = "append_only"
+	droppedTagsKey     = "dropped_tags"
 
 	// TableNameRegexStr is the regular expression that valid tables must match.
 	TableNameRegexStr = `^[a-zA-Z]{1}$|^[a-zA-Z]+[-_0-9a-zA-Z]*[0-9a-zA-Z]+$`
@@ -77,6 +81,12 @@ func NewTable(ctx context.Context, vrw types.ValueReadWriter, schema types.Value
 		return nil, err
 	}
 
+	tableStruct, err = updateZoneMaps(ctx, vrw, tableStruct, rowData)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &Table{vrw, tableStruct}, nil
 }
 
@@ -390,8 +400,14 @@ func (t *Table) GetRows(ctx context.Context, pkItr PKItr, numPKs int, sch schema
 }
 
 // UpdateRows replaces the current row data and returns and updated Table.  Calls to UpdateRows will not be written to the
-// database.  The root must be updated with the updated table, and the root must be committed or written.
+// database.  The root must be updated with the updated table, and the root must be committed or written. The table's
+// indexes and zone maps are updated for the changed rows. If the table is append-only, ErrAppendOnly is returned if any
+// of its existing rows are changed or removed.
 func (t *Table) UpdateRows(ctx context.Context, updatedRows types.Map) (*Table, error) {
+	if err := t.checkAppendOnly(ctx, updatedRows); err != nil {
+		return nil, err
+	}
+
 	rowDataRef, err := writeValAndGetRef(ctx, t.vrw, updatedRows)
 
 	if err != nil {
@@ -404,9 +420,129 @@ func (t *Table) UpdateRows(ctx context.Context, updatedRows types.Map) (*Table,
 		return nil, err
 	}
 
+	if _, ok, err := t.tableStruct.MaybeGet(indexesKey); err != nil {
+		return nil, err
+	} else if ok {
+		oldRows, err := t.GetRowData(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		updatedSt, err = t.updateIndexes(ctx, updatedSt, oldRows, updatedRows)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updatedSt, err = updateZoneMaps(ctx, t.vrw, updatedSt, updatedRows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Table{t.vrw, updatedSt}, nil
+}
+
+// Truncate returns a copy of the table with the same schema and indexes but no rows. Unlike UpdateRows with an empty
+// map, the old rows aren't read to update the indexes, which are replaced with empty ones. Append-only tables with rows
+// can't be truncated.
+func (t *Table) Truncate(ctx context.Context) (*Table, error) {
+	if appendOnly, err := t.IsAppendOnly(); err != nil {
+		return nil, err
+	} else if appendOnly {
+		rowData, err := t.GetRowData(ctx)
+
+		if err != nil {
+			return nil, err
+		} else if rowData.Len() > 0 {
+			return nil, ErrAppendOnly
+		}
+	}
+
+	emptyRows, err := types.NewMap(ctx, t.vrw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowDataRef, err := writeValAndGetRef(ctx, t.vrw, emptyRows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedSt, err := t.tableStruct.Set(tableRowsKey, rowDataRef)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok, err := t.tableStruct.MaybeGet(indexesKey); err != nil {
+		return nil, err
+	} else if ok {
+		updatedSt, err = t.emptyIndexes(ctx, updatedSt)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	updatedSt, err = updatedSt.Delete(zoneMapsKey)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &Table{t.vrw, updatedSt}, nil
 }
 
+// Rechunk returns a copy of the table whose row data and index data are rebuilt from scratch, so that they're chunked
+// with the chunking parameters of the table's database rather than the ones they were written with. The rows and
+// indexes of the copy are the same, but their hashes differ if the parameters do.
+func (t *Table) Rechunk(ctx context.Context) (*Table, error) {
+	rowData, err := t.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err = rowData.Rechunk(ctx, t.vrw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowDataRef, err := writeValAndGetRef(ctx, t.vrw, rowData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedSt, err := t.tableStruct.Set(tableRowsKey, rowDataRef)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedSt, err = updateZoneMaps(ctx, t.vrw, updatedSt, rowData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rechunked := &Table{t.vrw, updatedSt}
+
+	if _, ok, err := t.tableStruct.MaybeGet(indexesKey); err != nil {
+		return nil, err
+	} else if ok {
+		return rechunked.rechunkIndexes(ctx)
+	}
+
+	return rechunked, nil
+}
+
 // GetRowData retrieves the underlying map which is a map from a primary key to a list of field values.
 func (t *Table) GetRowData(ctx context.Context) (types.Map, error) {
 	val, _, err := t.tableStruct.MaybeGet(tableRowsKey)
@@ -427,6 +563,18 @@ func (t *Table) GetRowData(ctx context.Context) (types.Map, error) {
 	return rowMap, nil
 }
 
+// GetRowDataSubtree returns the rows of the subtree of the table's row data whose top chunk has the hash given, such
+// as one of the hashes returned by the row data's SubtreeHashes.
+func (t *Table) GetRowDataSubtree(ctx context.Context, h hash.Hash) (types.Map, error) {
+	val, err := t.vrw.ReadValue(ctx, h)
+
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	return val.(types.Map), nil
+}
+
 /*func (t *Table) ResolveConflicts(keys []map[uint64]string) (invalid, notFound []types.Value, tbl *Table, err error) {
 	sch := t.GetSchema()
 	pkCols := sch.GetPKCols()
@@ -20,6 +20,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
@@ -143,3 +144,76 @@ func TestTables(t *testing.T) {
 		}
 	}
 }
+
+func TestTruncate(t *testing.T) {
+	ctx := context.Background()
+	db, _ := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_7_18, nil, nil)
+
+	tSchema := createTestSchema()
+	rowData, _ := createTestRowData(t, db, tSchema)
+	tbl, err := createTestTable(db, tSchema, rowData)
+	require.NoError(t, err)
+	tbl, err = tbl.CreateIndex(ctx, "age_idx", []uint64{ageTag})
+	require.NoError(t, err)
+
+	truncated, err := tbl.Truncate(ctx)
+	require.NoError(t, err)
+
+	truncatedRows, err := truncated.GetRowData(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), truncatedRows.Len())
+
+	sameSchema, err := truncated.HasTheSameSchema(tbl)
+	require.NoError(t, err)
+	assert.True(t, sameSchema)
+
+	indexes, err := truncated.GetIndexes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Index{{"age_idx", []uint64{ageTag}}}, indexes)
+	assert.Empty(t, indexedIds(t, truncated, "age_idx"))
+
+	// the truncated table can be filled again
+	refilled, err := truncated.UpdateRows(ctx, rowData)
+	require.NoError(t, err)
+	assert.Equal(t, indexedIds(t, tbl, "age_idx"), indexedIds(t, refilled, "age_idx"))
+}
+
+func TestRechunk(t *testing.T) {
+	ctx := context.Background()
+	db, _ := dbfactory.MemFactory{}.CreateDB(ctx, types.Format_7_18, nil, nil)
+
+	tSchema := createTestSchema()
+	rowData, _ := createTestRowData(t, db, tSchema)
+	tbl, err := createTestTable(db, tSchema, rowData)
+	require.NoError(t, err)
+	tbl, err = tbl.CreateIndex(ctx, "age_idx", []uint64{ageTag})
+	require.NoError(t, err)
+
+	// rechunking with the parameters the table was written with changes nothing
+	rechunked, err := tbl.Rechunk(ctx)
+	require.NoError(t, err)
+	tblHash, err := tbl.HashOf()
+	require.NoError(t, err)
+	rechunkedHash, err := rechunked.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, tblHash, rechunkedHash)
+
+	err = types.SetChunkingConfig(db, types.ChunkingConfig{TargetSize: 1 << 16, Window: 131})
+	require.NoError(t, err)
+
+	rechunked, err = tbl.Rechunk(ctx)
+	require.NoError(t, err)
+
+	rechunkedRows, err := rechunked.GetRowData(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, rowData.Len(), rechunkedRows.Len())
+	err = rowData.IterAll(ctx, func(k, v types.Value) error {
+		rechunkedVal, ok, err := rechunkedRows.MaybeGet(ctx, k)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, v.Equals(rechunkedVal))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, indexedIds(t, tbl, "age_idx"), indexedIds(t, rechunked, "age_idx"))
+}
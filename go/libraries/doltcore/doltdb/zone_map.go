@@ -0,0 +1,255 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"context"
+	"sort"
+
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ZoneMap holds the smallest and largest value of each column in one of the leaf chunks of a table's row data, which
+// lets a scan for a range of values skip the chunks that can't hold any, without reading them. Null values are not
+// counted, and columns without a non-null value in the chunk have no entry.
+//
+// The zone maps of a table are stored in a map from the hash of each leaf chunk to a tuple of the tag, smallest value
+// and largest value of each column, ordered by tag. As chunks are addressed by their contents, entries for chunks that
+// are unchanged by an update are kept as they are. Tables whose rows fit in a single chunk have no zone maps.
+type ZoneMap struct {
+	Min map[uint64]types.Value
+	Max map[uint64]types.Value
+}
+
+// RowDataLeaf is one of the leaf chunks of a table's row data, with its zone map, or nil if it doesn't have one.
+type RowDataLeaf struct {
+	Hash hash.Hash
+	Zone *ZoneMap
+}
+
+// GetRowDataLeaves returns the leaf chunks of the table's row data, in primary key order, with their zone maps.
+func (t *Table) GetRowDataLeaves(ctx context.Context) ([]RowDataLeaf, error) {
+	rowData, err := t.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := rowData.LeafHashes(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	zoneMaps, err := getZoneMaps(ctx, t.vrw, t.tableStruct)
+
+	if err != nil {
+		return nil, err
+	}
+
+	leaves := make([]RowDataLeaf, len(hashes))
+	for i, h := range hashes {
+		leaves[i].Hash = h
+
+		val, ok, err := zoneMaps.MaybeGet(ctx, types.String(h.String()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			leaves[i].Zone, err = zoneMapFromTuple(val.(types.Tuple))
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return leaves, nil
+}
+
+// GetRowDataLeaf returns the rows in the leaf chunk of the table's row data with the hash given.
+func (t *Table) GetRowDataLeaf(ctx context.Context, h hash.Hash) (types.Map, error) {
+	return t.GetRowDataSubtree(ctx, h)
+}
+
+// getZoneMaps returns the zone maps of the table struct given, which are empty if it doesn't have any.
+func getZoneMaps(ctx context.Context, vrw types.ValueReadWriter, st types.Struct) (types.Map, error) {
+	val, ok, err := st.MaybeGet(zoneMapsKey)
+
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	if !ok {
+		return types.NewMap(ctx, vrw)
+	}
+
+	val, err = val.(types.Ref).TargetValue(ctx, vrw)
+
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	return val.(types.Map), nil
+}
+
+// updateZoneMaps returns the table struct given with zone maps for the row data given. The zone maps already in the
+// struct are reused for the chunks they cover, and only the chunks without one are read.
+func updateZoneMaps(ctx context.Context, vrw types.ValueReadWriter, st types.Struct, rowData types.Map) (types.Struct, error) {
+	hashes, err := rowData.LeafHashes(ctx)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	if len(hashes) < 2 {
+		return st.Delete(zoneMapsKey)
+	}
+
+	oldZoneMaps, err := getZoneMaps(ctx, vrw, st)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	kvs := make([]types.Value, 0, 2*len(hashes))
+	for _, h := range hashes {
+		k := types.String(h.String())
+		v, ok, err := oldZoneMaps.MaybeGet(ctx, k)
+
+		if err != nil {
+			return types.Struct{}, err
+		}
+
+		if !ok {
+			v, err = computeZoneMap(ctx, vrw, h)
+
+			if err != nil {
+				return types.Struct{}, err
+			}
+		}
+
+		kvs = append(kvs, k, v)
+	}
+
+	zoneMaps, err := types.NewMap(ctx, vrw, kvs...)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	zoneMapsRef, err := writeValAndGetRef(ctx, vrw, zoneMaps)
+
+	if err != nil {
+		return types.Struct{}, err
+	}
+
+	return st.Set(zoneMapsKey, zoneMapsRef)
+}
+
+// computeZoneMap reads the leaf chunk of row data with the hash given and returns the tuple of its zone map.
+func computeZoneMap(ctx context.Context, vrw types.ValueReadWriter, h hash.Hash) (types.Tuple, error) {
+	val, err := vrw.ReadValue(ctx, h)
+
+	if err != nil {
+		return types.EmptyTuple(vrw.Format()), err
+	}
+
+	nbf := vrw.Format()
+	zm := ZoneMap{make(map[uint64]types.Value), make(map[uint64]types.Value)}
+	addField := func(tag uint64, v types.Value) error {
+		if types.IsNull(v) {
+			return nil
+		}
+
+		if min, ok := zm.Min[tag]; !ok {
+			zm.Min[tag], zm.Max[tag] = v, v
+		} else if less, err := v.Less(nbf, min); err != nil {
+			return err
+		} else if less {
+			zm.Min[tag] = v
+		} else if less, err := zm.Max[tag].Less(nbf, v); err != nil {
+			return err
+		} else if less {
+			zm.Max[tag] = v
+		}
+
+		return nil
+	}
+
+	err = val.(types.Map).IterAll(ctx, func(k, v types.Value) error {
+		for _, tpl := range []types.Value{k, v} {
+			var tag uint64
+			err := tpl.(types.Tuple).IterFields(func(i uint64, field types.Value) (bool, error) {
+				if i%2 == 0 {
+					tag = uint64(field.(types.Uint))
+					return false, nil
+				}
+
+				return false, addField(tag, field)
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return types.EmptyTuple(nbf), err
+	}
+
+	tags := make([]uint64, 0, len(zm.Min))
+	for tag := range zm.Min {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+	fields := make([]types.Value, 0, 3*len(tags))
+	for _, tag := range tags {
+		fields = append(fields, types.Uint(tag), zm.Min[tag], zm.Max[tag])
+	}
+
+	return types.NewTuple(nbf, fields...)
+}
+
+func zoneMapFromTuple(tpl types.Tuple) (*ZoneMap, error) {
+	zm := &ZoneMap{make(map[uint64]types.Value), make(map[uint64]types.Value)}
+
+	var tag uint64
+	err := tpl.IterFields(func(i uint64, field types.Value) (bool, error) {
+		switch i % 3 {
+		case 0:
+			tag = uint64(field.(types.Uint))
+		case 1:
+			zm.Min[tag] = field
+		case 2:
+			zm.Max[tag] = field
+		}
+
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return zm, nil
+}
@@ -17,6 +17,7 @@ package actions
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
 
@@ -40,6 +41,23 @@ func MoveBranch(ctx context.Context, dEnv *env.DoltEnv, oldBranch, newBranch str
 		return err
 	}
 
+	// A renamed ephemeral branch keeps its expiry
+	expires, ephemeral, err := dEnv.DoltDB.GetBranchExpiry(ctx, oldRef)
+
+	if err != nil {
+		return err
+	}
+
+	if ephemeral {
+		err = dEnv.DoltDB.SetBranchExpiry(ctx, newRef, expires)
+	} else {
+		_, err = dEnv.DoltDB.ClearBranchExpiry(ctx, newRef)
+	}
+
+	if err != nil {
+		return err
+	}
+
 	if ref.Equals(dEnv.RepoState.Head.Ref, oldRef) {
 		dEnv.RepoState.Head = ref.MarshalableRef{Ref: newRef}
 		err = dEnv.RepoState.Save(dEnv.FS)
@@ -139,7 +157,50 @@ func DeleteBranchOnDB(ctx context.Context, ddb *doltdb.DoltDB, dref ref.DoltRef,
 		}
 	}
 
-	return ddb.DeleteBranch(ctx, dref)
+	if err := ddb.DeleteBranch(ctx, dref); err != nil {
+		return err
+	}
+
+	_, err = ddb.ClearBranchExpiry(ctx, dref)
+	return err
+}
+
+// CreateEphemeralBranch creates a branch like CreateBranch, which expires after the ttl given.
+func CreateEphemeralBranch(ctx context.Context, dEnv *env.DoltEnv, newBranch, startingPoint string, force bool, ttl time.Duration) error {
+	if err := CreateBranch(ctx, dEnv, newBranch, startingPoint, force); err != nil {
+		return err
+	}
+
+	return dEnv.DoltDB.SetBranchExpiry(ctx, ref.NewBranchRef(newBranch), time.Now().Add(ttl))
+}
+
+// DeleteExpiredBranches deletes the ephemeral branches of the database given that have expired at the time given,
+// whether or not they're merged, except for the branch given, which is checked out and can be nil. It returns the
+// branches deleted.
+func DeleteExpiredBranches(ctx context.Context, ddb *doltdb.DoltDB, now time.Time, checkedOut ref.DoltRef) ([]doltdb.EphemeralBranch, error) {
+	ebs, err := ddb.GetEphemeralBranches(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []doltdb.EphemeralBranch
+	for _, eb := range ebs {
+		if !eb.Expired(now) || ref.Equals(eb.Branch, checkedOut) {
+			continue
+		}
+
+		// Unlike DeleteBranchOnDB, this doesn't need a master branch, which a remote may not have
+		if err := ddb.DeleteBranch(ctx, eb.Branch); err != nil {
+			return deleted, err
+		} else if _, err := ddb.ClearBranchExpiry(ctx, eb.Branch); err != nil {
+			return deleted, err
+		}
+
+		deleted = append(deleted, eb)
+	}
+
+	return deleted, nil
 }
 
 func CreateBranch(ctx context.Context, dEnv *env.DoltEnv, newBranch, startingPoint string, force bool) error {
@@ -0,0 +1,83 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestEphemeralBranches(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+
+	require.NoError(t, CreateEphemeralBranch(ctx, dEnv, "ci/expired", "HEAD", false, time.Millisecond))
+	require.NoError(t, CreateEphemeralBranch(ctx, dEnv, "ci/current", "HEAD", false, time.Hour))
+	require.NoError(t, CreateEphemeralBranch(ctx, dEnv, "ci/renamed", "HEAD", false, time.Millisecond))
+	require.NoError(t, MoveBranch(ctx, dEnv, "ci/renamed", "ci/moved", false))
+	require.NoError(t, CreateEphemeralBranch(ctx, dEnv, "ci/deleted", "HEAD", false, time.Millisecond))
+	require.NoError(t, DeleteBranch(ctx, dEnv, "ci/deleted", true))
+
+	remoteDB, err := doltdb.LoadDoltDB(ctx, types.Format_7_18, doltdb.InMemDoltDB)
+	require.NoError(t, err)
+	require.NoError(t, CopyBranchOnDB(ctx, dEnv.DoltDB, "ci/current", "promoted", false))
+	cs, _ := doltdb.NewCommitSpec("HEAD", "master")
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+	require.NoError(t, err)
+	for _, branch := range []string{"ci/expired", "promoted"} {
+		dest := ref.NewBranchRef(branch)
+		require.NoError(t, MirrorBranch(ctx, dEnv, dest, dEnv.DoltDB, remoteDB, cm, nil, nil))
+		require.NoError(t, PushBranchExpiry(ctx, ref.NewBranchRef(branch), dest, dEnv.DoltDB, remoteDB))
+	}
+	require.NoError(t, remoteDB.SetBranchExpiry(ctx, ref.NewBranchRef("promoted"), time.Now()))
+	require.NoError(t, PushBranchExpiry(ctx, ref.NewBranchRef("promoted"), ref.NewBranchRef("promoted"), dEnv.DoltDB, remoteDB))
+
+	time.Sleep(2 * time.Millisecond)
+	now := time.Now().Add(time.Second)
+
+	deleted, err := DeleteExpiredBranches(ctx, dEnv.DoltDB, now, ref.NewBranchRef("ci/moved"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci/expired"}, ephemeralBranchNames(deleted))
+
+	ebs, err := dEnv.DoltDB.GetEphemeralBranches(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci/moved", "ci/current"}, ephemeralBranchNames(ebs))
+
+	// the remote has no master branch, and keeps the branch made permanent
+	deleted, err = DeleteExpiredBranches(ctx, remoteDB, now, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci/expired"}, ephemeralBranchNames(deleted))
+	branches, err := remoteDB.GetBranches(ctx)
+	require.NoError(t, err)
+	require.Len(t, branches, 1)
+	assert.Equal(t, "promoted", branches[0].GetPath())
+}
+
+func ephemeralBranchNames(ebs []doltdb.EphemeralBranch) []string {
+	var names []string
+	for _, eb := range ebs {
+		names = append(names, eb.Branch.GetPath())
+	}
+	return names
+}
@@ -28,14 +28,19 @@ const (
 	AddedTable TableDiffType = iota
 	ModifiedTable
 	RemovedTable
+	RenamedTable
 )
 
 type TableDiffs struct {
 	NumAdded    int
 	NumModified int
 	NumRemoved  int
+	NumRenamed  int
 	TableToType map[string]TableDiffType
 	Tables      []string
+
+	// RenamedFrom maps the new names of the tables renamed to their old names
+	RenamedFrom map[string]string
 }
 
 func NewTableDiffs(ctx context.Context, newer, older *doltdb.RootValue) (*TableDiffs, error) {
@@ -45,10 +50,24 @@ func NewTableDiffs(ctx context.Context, newer, older *doltdb.RootValue) (*TableD
 		return nil, err
 	}
 
+	renamed, added, removed, err := newer.TableRenames(ctx, older, added, removed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	renamedFrom := make(map[string]string, len(renamed))
+	for oldName, newName := range renamed {
+		renamedFrom[newName] = oldName
+	}
+
 	var tbls []string
 	tbls = append(tbls, added...)
 	tbls = append(tbls, modified...)
 	tbls = append(tbls, removed...)
+	for newName := range renamedFrom {
+		tbls = append(tbls, newName)
+	}
 	sort.Strings(tbls)
 
 	tblToType := make(map[string]TableDiffType)
@@ -64,7 +83,11 @@ func NewTableDiffs(ctx context.Context, newer, older *doltdb.RootValue) (*TableD
 		tblToType[tbl] = RemovedTable
 	}
 
-	return &TableDiffs{len(added), len(modified), len(removed), tblToType, tbls}, err
+	for tbl := range renamedFrom {
+		tblToType[tbl] = RenamedTable
+	}
+
+	return &TableDiffs{len(added), len(modified), len(removed), len(renamedFrom), tblToType, tbls, renamedFrom}, err
 }
 
 func (td *TableDiffs) Len() int {
@@ -0,0 +1,171 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"sync"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+// RefUpdate describes the move of a ref made by a push or a fetch.
+type RefUpdate struct {
+	// Ref is the ref being moved.  For a push it is the branch in the remote database, and for a fetch it is the remote
+	// tracking ref in the local database.
+	Ref ref.DoltRef
+
+	// Old is the hash of the commit the ref pointed to before the update, or the empty hash if the ref didn't exist.
+	Old hash.Hash
+
+	// New is the hash of the commit the ref points to after the update.
+	New hash.Hash
+}
+
+// IsNew returns true if the ref didn't exist before the update.
+func (u RefUpdate) IsNew() bool {
+	return u.Old.IsEmpty()
+}
+
+// PrePushHook is called by Push before any data is sent to the remote.  Returning an error aborts the push, and the
+// error is returned by Push.
+type PrePushHook func(ctx context.Context, dEnv *env.DoltEnv, update RefUpdate) error
+
+// PostPullHook is called by Fetch once the commits of a remote branch have been pulled into the local database and
+// the remote tracking ref has been moved to them, as done by dolt fetch and dolt pull.  An error from a hook is
+// returned by Fetch, but the fetched data and the ref update are kept.
+type PostPullHook func(ctx context.Context, dEnv *env.DoltEnv, update RefUpdate) error
+
+var hooksMu = &sync.Mutex{}
+var prePushHooks []*PrePushHook
+var postPullHooks []*PostPullHook
+
+// RegisterPrePushHook adds a hook that is called before every push, and returns a function that removes it.  Hooks are
+// called in the order they were registered, and the first to return an error stops the push.
+func RegisterPrePushHook(hook PrePushHook) (unregister func()) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	h := &hook
+	prePushHooks = append(prePushHooks, h)
+
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+
+		for i := range prePushHooks {
+			if prePushHooks[i] == h {
+				prePushHooks = append(prePushHooks[:i:i], prePushHooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// RegisterPostPullHook adds a hook that is called after every fetch of a remote branch, and returns a function that
+// removes it.  Hooks are called in the order they were registered, and the first to return an error stops the
+// remaining hooks from running.
+func RegisterPostPullHook(hook PostPullHook) (unregister func()) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	h := &hook
+	postPullHooks = append(postPullHooks, h)
+
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+
+		for i := range postPullHooks {
+			if postPullHooks[i] == h {
+				postPullHooks = append(postPullHooks[:i:i], postPullHooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func runPrePushHooks(ctx context.Context, dEnv *env.DoltEnv, update RefUpdate) error {
+	hooksMu.Lock()
+	hooks := prePushHooks
+	hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		err := (*hook)(ctx, dEnv, update)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runPostPullHooks(ctx context.Context, dEnv *env.DoltEnv, update RefUpdate) error {
+	hooksMu.Lock()
+	hooks := postPullHooks
+	hooksMu.Unlock()
+
+	for _, hook := range hooks {
+		err := (*hook)(ctx, dEnv, update)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newRefUpdate builds the update moving dref in ddb to commit, looking up the commit dref currently points to.
+func newRefUpdate(ctx context.Context, ddb *doltdb.DoltDB, dref ref.DoltRef, commit *doltdb.Commit) (RefUpdate, error) {
+	newHash, err := commit.HashOf()
+
+	if err != nil {
+		return RefUpdate{}, err
+	}
+
+	update := RefUpdate{Ref: dref, New: newHash}
+	hasRef, err := ddb.HasRef(ctx, dref)
+
+	if err != nil {
+		return RefUpdate{}, err
+	} else if !hasRef {
+		return update, nil
+	}
+
+	cs, err := doltdb.NewCommitSpec("HEAD", dref.String())
+
+	if err != nil {
+		return RefUpdate{}, err
+	}
+
+	cm, err := ddb.Resolve(ctx, cs)
+
+	if err != nil {
+		return RefUpdate{}, err
+	}
+
+	update.Old, err = cm.HashOf()
+
+	if err != nil {
+		return RefUpdate{}, err
+	}
+
+	return update, nil
+}
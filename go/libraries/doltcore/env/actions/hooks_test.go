@@ -0,0 +1,114 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestPushAndPullHooks(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	remoteDB, err := doltdb.LoadDoltDB(ctx, types.Format_7_18, doltdb.InMemDoltDB)
+	require.NoError(t, err)
+
+	cs, err := doltdb.NewCommitSpec("HEAD", "master")
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+	require.NoError(t, err)
+	cmHash, err := cm.HashOf()
+	require.NoError(t, err)
+
+	destRef := ref.NewBranchRef("master")
+	remoteRef := ref.NewRemoteRef("origin", "master")
+
+	var pushes []RefUpdate
+	defer RegisterPrePushHook(func(ctx context.Context, dEnv *env.DoltEnv, update RefUpdate) error {
+		pushes = append(pushes, update)
+		return nil
+	})()
+
+	rejected := errors.New("rejected")
+	defer RegisterPrePushHook(func(ctx context.Context, dEnv *env.DoltEnv, update RefUpdate) error {
+		if update.Ref.GetPath() == "protected" {
+			return rejected
+		}
+
+		return nil
+	})()
+
+	var pulls []RefUpdate
+	defer RegisterPostPullHook(func(ctx context.Context, dEnv *env.DoltEnv, update RefUpdate) error {
+		pulls = append(pulls, update)
+		return nil
+	})()
+
+	err = Push(ctx, dEnv, ref.NewBranchRef("protected"), ref.NewRemoteRef("origin", "protected"), dEnv.DoltDB, remoteDB, cm, nil, nil)
+	assert.Equal(t, rejected, err)
+
+	hasRef, err := remoteDB.HasRef(ctx, ref.NewBranchRef("protected"))
+	require.NoError(t, err)
+	assert.False(t, hasRef, "a rejected push should not update the remote")
+
+	err = Push(ctx, dEnv, destRef, remoteRef, dEnv.DoltDB, remoteDB, cm, nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, pushes, 2)
+	assert.Equal(t, RefUpdate{Ref: destRef, New: cmHash}, pushes[1])
+	assert.True(t, pushes[1].IsNew())
+	assert.Empty(t, pulls)
+
+	// the push already moved the tracking ref, so fetching the same commit reports it as both old and new
+	err = Fetch(ctx, dEnv, remoteRef, remoteDB, dEnv.DoltDB, cm, nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, pulls, 1)
+	assert.Equal(t, RefUpdate{Ref: remoteRef, Old: cmHash, New: cmHash}, pulls[0])
+
+	// errors from post pull hooks are returned after the ref was moved
+	failed := errors.New("failed")
+	unregister := RegisterPostPullHook(func(ctx context.Context, dEnv *env.DoltEnv, update RefUpdate) error {
+		return failed
+	})
+
+	fetchedRef := ref.NewRemoteRef("origin", "fetched")
+	err = Fetch(ctx, dEnv, fetchedRef, remoteDB, dEnv.DoltDB, cm, nil, nil)
+	assert.Equal(t, failed, err)
+
+	require.Len(t, pulls, 2)
+	assert.Equal(t, RefUpdate{Ref: fetchedRef, Old: hash.Hash{}, New: cmHash}, pulls[1])
+
+	hasRef, err = dEnv.DoltDB.HasRef(ctx, fetchedRef)
+	require.NoError(t, err)
+	assert.True(t, hasRef)
+
+	// once unregistered, a hook is no longer called
+	unregister()
+	err = Fetch(ctx, dEnv, fetchedRef, remoteDB, dEnv.DoltDB, cm, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, pulls, 3)
+}
@@ -16,12 +16,17 @@ package actions
 
 import (
 	"context"
+	"errors"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/merge"
 )
 
+// ErrChunkingMismatch is returned when merging commits whose roots store different chunking parameters. The merged
+// tables would mix chunks written with both, so one side has to be rewritten with the parameters of the other first.
+var ErrChunkingMismatch = errors.New("commits have different chunking parameters; run `dolt admin rechunk` on one of them with the parameters of the other before merging")
+
 func MergeCommits(ctx context.Context, ddb *doltdb.DoltDB, cm1, cm2 *doltdb.Commit) (*doltdb.RootValue, map[string]*merge.MergeStats, error) {
 	merger, err := merge.NewMerger(ctx, cm1, cm2, ddb.ValueReadWriter())
 
@@ -41,6 +46,21 @@ func MergeCommits(ctx context.Context, ddb *doltdb.DoltDB, cm1, cm2 *doltdb.Comm
 		return nil, nil, err
 	}
 
+	return mergeRoots(ctx, merger, root, rv)
+}
+
+// MergeRoots merges the tables of mergeRoot into those of root, where ancRoot is the root both were changed from, and
+// returns the merged root along with the stats of the merge of each table.
+func MergeRoots(ctx context.Context, ddb *doltdb.DoltDB, root, mergeRoot, ancRoot *doltdb.RootValue) (*doltdb.RootValue, map[string]*merge.MergeStats, error) {
+	merger := merge.NewRootMerger(root, mergeRoot, ancRoot, ddb.ValueReadWriter())
+	return mergeRoots(ctx, merger, root, mergeRoot)
+}
+
+func mergeRoots(ctx context.Context, merger *merge.Merger, root, rv *doltdb.RootValue) (*doltdb.RootValue, map[string]*merge.MergeStats, error) {
+	if err := checkChunkingConfigs(ctx, root, rv); err != nil {
+		return nil, nil, err
+	}
+
 	tblNames, err := AllTables(ctx, root, rv)
 
 	if err != nil {
@@ -124,3 +144,23 @@ func GetTablesInConflict(ctx context.Context, dEnv *env.DoltEnv) (workingInConfl
 
 	return workingInConflict, stagedInConflict, headInConflict, err
 }
+
+func checkChunkingConfigs(ctx context.Context, root, mergeRoot *doltdb.RootValue) error {
+	cfg, err := root.GetChunkingConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	mergeCfg, err := mergeRoot.GetChunkingConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if cfg != mergeCfg {
+		return ErrChunkingMismatch
+	}
+
+	return nil
+}
@@ -30,7 +30,7 @@ var ErrCantFF = errors.New("can't fast forward merge")
 // This is accomplished first by verifying that the remote tracking reference for the source database can be updated to
 // the given commit via a fast forward merge.  If this is the case, an attempt will be made to update the branch in the
 // destination db to the given commit via fast forward move.  If that succeeds the tracking branch is updated in the
-// source db.
+// source db.  Registered PrePushHooks are run before anything is sent to the destination, and can abort the push.
 func Push(ctx context.Context, dEnv *env.DoltEnv, destRef ref.BranchRef, remoteRef ref.RemoteRef, srcDB, destDB *doltdb.DoltDB, commit *doltdb.Commit, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
 	canFF, err := srcDB.CanFastForward(ctx, remoteRef, commit)
 
@@ -40,6 +40,18 @@ func Push(ctx context.Context, dEnv *env.DoltEnv, destRef ref.BranchRef, remoteR
 		return ErrCantFF
 	}
 
+	update, err := newRefUpdate(ctx, destDB, destRef, commit)
+
+	if err != nil {
+		return err
+	}
+
+	err = runPrePushHooks(ctx, dEnv, update)
+
+	if err != nil {
+		return err
+	}
+
 	err = destDB.PushChunks(ctx, dEnv.TempTableFilesDir(), srcDB, commit, progChan, pullerEventCh)
 
 	if err != nil {
@@ -57,6 +69,51 @@ func Push(ctx context.Context, dEnv *env.DoltEnv, destRef ref.BranchRef, remoteR
 	return err
 }
 
+// MirrorBranch sets a branch in a destination database to the given commit, sending the chunks the commit needs,
+// whether or not the commit is a fast forward of the branch's current commit. It is used to make the branches of a
+// remote match the local branches exactly. Registered PrePushHooks are run before anything is sent to the destination.
+// ErrUpToDate is returned if the branch already points at the commit.
+func MirrorBranch(ctx context.Context, dEnv *env.DoltEnv, destRef ref.BranchRef, srcDB, destDB *doltdb.DoltDB, commit *doltdb.Commit, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
+	update, err := newRefUpdate(ctx, destDB, destRef, commit)
+
+	if err != nil {
+		return err
+	} else if update.Old == update.New {
+		return doltdb.ErrUpToDate
+	}
+
+	err = runPrePushHooks(ctx, dEnv, update)
+
+	if err != nil {
+		return err
+	}
+
+	err = destDB.PushChunks(ctx, dEnv.TempTableFilesDir(), srcDB, commit, progChan, pullerEventCh)
+
+	if err != nil {
+		return err
+	}
+
+	return destDB.SetRef(ctx, destRef, commit)
+}
+
+// PushBranchExpiry gives the branch of the destination database given the expiry that the branch of the source
+// database given has, or makes it permanent if the source branch is, after the source branch was pushed to it.
+func PushBranchExpiry(ctx context.Context, srcRef, destRef ref.DoltRef, srcDB, destDB *doltdb.DoltDB) error {
+	expires, ephemeral, err := srcDB.GetBranchExpiry(ctx, srcRef)
+
+	if err != nil {
+		return err
+	}
+
+	if ephemeral {
+		return destDB.SetBranchExpiry(ctx, destRef, expires)
+	}
+
+	_, err = destDB.ClearBranchExpiry(ctx, destRef)
+	return err
+}
+
 // DeleteRemoteBranch validates targetRef is a branch on the remote database, and then deletes it, then deletes the
 // remote tracking branch from the local database.
 func DeleteRemoteBranch(ctx context.Context, targetRef ref.BranchRef, remoteRef ref.RemoteRef, localDB, remoteDB *doltdb.DoltDB) error {
@@ -74,6 +131,12 @@ func DeleteRemoteBranch(ctx context.Context, targetRef ref.BranchRef, remoteRef
 		return err
 	}
 
+	_, err = remoteDB.ClearBranchExpiry(ctx, targetRef)
+
+	if err != nil {
+		return err
+	}
+
 	err = localDB.DeleteBranch(ctx, remoteRef)
 
 	if err != nil {
@@ -83,14 +146,28 @@ func DeleteRemoteBranch(ctx context.Context, targetRef ref.BranchRef, remoteRef
 	return nil
 }
 
+// Fetch pulls the chunks of the given commit from srcDB into destDB and fast forwards destRef to it.  Registered
+// PostPullHooks are run once the ref has been updated.
 func Fetch(ctx context.Context, dEnv *env.DoltEnv, destRef ref.DoltRef, srcDB, destDB *doltdb.DoltDB, commit *doltdb.Commit, progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
-	err := destDB.PullChunks(ctx, dEnv.TempTableFilesDir(), srcDB, commit, progChan, pullerEventCh)
+	update, err := newRefUpdate(ctx, destDB, destRef, commit)
+
+	if err != nil {
+		return err
+	}
+
+	err = destDB.PullChunks(ctx, dEnv.TempTableFilesDir(), srcDB, commit, progChan, pullerEventCh)
+
+	if err != nil {
+		return err
+	}
+
+	err = destDB.FastForward(ctx, destRef, commit)
 
 	if err != nil {
 		return err
 	}
 
-	return destDB.FastForward(ctx, destRef, commit)
+	return runPostPullHooks(ctx, dEnv, update)
 }
 
 func Clone(ctx context.Context, srcDB, destDB *doltdb.DoltDB, eventCh chan<- datas.TableFileEvent) error {
@@ -0,0 +1,75 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"encoding/json"
+
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+// CloneState records that a clone into the repository hasn't completed, so that running the clone again resumes it
+// rather than starting over. The table files downloaded before the clone stopped are kept in the repository's
+// database. It is stored in the .dolt directory, and is removed once the clone completes.
+type CloneState struct {
+	// Remote is the remote being cloned
+	Remote Remote `json:"remote"`
+}
+
+// LoadCloneState returns the clone state of the repository, and whether there is one.
+func LoadCloneState(fs filesys.ReadableFS) (*CloneState, bool, error) {
+	path := getCloneStateFile()
+
+	if exists, _ := fs.Exists(path); !exists {
+		return nil, false, nil
+	}
+
+	data, err := fs.ReadFile(path)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var cs CloneState
+	err = json.Unmarshal(data, &cs)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &cs, true, nil
+}
+
+// Save writes the clone state to the repository, replacing any existing clone state.
+func (cs *CloneState) Save(fs filesys.WritableFS) error {
+	data, err := json.MarshalIndent(cs, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return fs.WriteFile(getCloneStateFile(), data)
+}
+
+// RemoveCloneState removes the clone state of the repository, if there is one.
+func RemoveCloneState(fs filesys.ReadWriteFS) error {
+	path := getCloneStateFile()
+
+	if exists, _ := fs.Exists(path); !exists {
+		return nil
+	}
+
+	return fs.DeleteFile(path)
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneState(t *testing.T) {
+	dEnv := createTestEnv(true, true)
+
+	_, ok, err := LoadCloneState(dEnv.FS)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	cs := &CloneState{Remote: NewRemote("origin", "file:///remotes/test", map[string]string{})}
+	require.NoError(t, cs.Save(dEnv.FS))
+
+	loaded, ok, err := LoadCloneState(dEnv.FS)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, cs, loaded)
+
+	require.NoError(t, RemoveCloneState(dEnv.FS))
+	_, ok, err = LoadCloneState(dEnv.FS)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, RemoveCloneState(dEnv.FS))
+}
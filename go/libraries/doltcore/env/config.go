@@ -17,6 +17,7 @@ package env
 import (
 	"errors"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
@@ -38,6 +39,8 @@ const (
 
 	DoltEditor = "core.editor"
 
+	HashAbbrevLenKey = "core.abbrev"
+
 	RemotesApiHostKey     = "remotes.default_host"
 	RemotesApiHostPortKey = "remotes.default_port"
 
@@ -47,6 +50,16 @@ const (
 	MetricsHost     = "metrics.host"
 	MetricsPort     = "metrics.port"
 	MetricsInsecure = "metrics.insecure"
+
+	CommandLogEnabled = "metrics.command_log"
+	CommandLogFile    = "metrics.command_log_file"
+	CommandLogSink    = "metrics.command_log_sink"
+
+	SqlCollationKey = "sql.collation"
+
+	// StoreDisableMmapKey, when "true", causes the table files of the repository's database to be read without mmap.
+	// The DOLT_DISABLE_MMAP environment variable does the same whatever the config says.
+	StoreDisableMmapKey = "store.disable_mmap"
 )
 
 var LocalConfigWhitelist = set.NewStrSet([]string{UserNameKey, UserEmailKey})
@@ -147,6 +160,20 @@ func (dcc *DoltCliConfig) createLocalConfigAt(dir string, vals map[string]string
 	return nil
 }
 
+// storeParams returns the parameters for opening the repository's database that are set in the config.
+func (dcc *DoltCliConfig) storeParams() map[string]string {
+	if dcc == nil {
+		return nil
+	}
+
+	params := make(map[string]string)
+	if disable, err := strconv.ParseBool(*dcc.GetStringOrDefault(StoreDisableMmapKey, "false")); err == nil && disable {
+		params[dbfactory.DisableMmapParam] = "true"
+	}
+
+	return params
+}
+
 // GetConfig retrieves a specific element of the config hierarchy.
 func (dcc *DoltCliConfig) GetConfig(element DoltConfigElement) (config.ReadWriteConfig, bool) {
 	return dcc.ch.GetConfig(element.String())
@@ -14,7 +14,13 @@
 
 package env
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dbfactory"
+)
 
 const (
 	email = "bigbillieb@fake.horse"
@@ -50,3 +56,15 @@ func TestConfig(t *testing.T) {
 		t.Error("Should return empty string")
 	}
 }
+
+func TestStoreParams(t *testing.T) {
+	dEnv := createTestEnv(true, true)
+	assert.Empty(t, dEnv.Config.storeParams())
+
+	lCfg, _ := dEnv.Config.GetConfig(LocalConfig)
+	lCfg.SetStrings(map[string]string{StoreDisableMmapKey: "true"})
+	assert.Equal(t, map[string]string{dbfactory.DisableMmapParam: "true"}, dEnv.Config.storeParams())
+
+	lCfg.SetStrings(map[string]string{StoreDisableMmapKey: "false"})
+	assert.Empty(t, dEnv.Config.storeParams())
+}
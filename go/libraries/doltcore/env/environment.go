@@ -18,7 +18,6 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -73,7 +72,7 @@ type DoltEnv struct {
 func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr string) *DoltEnv {
 	config, cfgErr := loadDoltCliConfig(hdp, fs)
 	repoState, rsErr := LoadRepoState(fs)
-	ddb, dbLoadErr := doltdb.LoadDoltDB(ctx, types.Format_Default, urlStr)
+	ddb, dbLoadErr := doltdb.LoadDoltDBWithParams(ctx, types.Format_Default, urlStr, config.storeParams())
 
 	dEnv := &DoltEnv{
 		config,
@@ -87,15 +86,20 @@ func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr s
 		hdp,
 	}
 
-	if dbLoadErr == nil && dEnv.HasDoltDir() {
+	if dbLoadErr == nil && rsErr == nil && dEnv.HasDoltDir() {
+		dEnv.DBLoadError = dEnv.applyChunkingConfig(ctx)
+	}
+
+	if dEnv.DBLoadError == nil && dEnv.HasDoltDir() {
 		if !dEnv.HasDoltTempTableDir() {
-			err := os.Mkdir(dEnv.TempTableFilesDir(), os.ModePerm)
+			err := fs.MkDirs(dEnv.TempTableFilesDir())
 			dEnv.DBLoadError = err
 		} else {
 			// fire and forget cleanup routine.  Will delete as many old temp files as it can during the main commands execution.
 			// The process will not wait for this to finish so this may not always complete.
+			tempTableFilesDir := dEnv.TempTableFilesDir()
 			go func() {
-				_ = fs.Iter(dEnv.TempTableFilesDir(), true, func(path string, size int64, isDir bool) (stop bool) {
+				_ = fs.Iter(tempTableFilesDir, true, func(path string, size int64, isDir bool) (stop bool) {
 					if !isDir {
 						lm, exists := fs.LastModified(path)
 
@@ -115,6 +119,33 @@ func Load(ctx context.Context, hdp HomeDirProvider, fs filesys.Filesys, urlStr s
 	return dEnv
 }
 
+// applyChunkingConfig sets the chunking parameters of the repository's database to the ones stored in the working
+// root's chunking table, if any.
+func (dEnv *DoltEnv) applyChunkingConfig(ctx context.Context) error {
+	if dEnv.RepoState.WorkingHash().IsEmpty() {
+		// There's no working root until a clone into the repository completes
+		return nil
+	}
+
+	root, err := dEnv.WorkingRoot(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	cfg, err := root.GetChunkingConfig(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if cfg == types.DefaultChunkingConfig {
+		return nil
+	}
+
+	return dEnv.DoltDB.SetChunkingConfig(cfg)
+}
+
 // HasDoltDir returns true if the .dolt directory exists and is a valid directory
 func (dEnv *DoltEnv) HasDoltDir() bool {
 	return dEnv.hasDoltDir("./")
@@ -211,7 +242,7 @@ func (dEnv *DoltEnv) InitRepoWithNoData(ctx context.Context, nbf *types.NomsBinF
 		return err
 	}
 
-	dEnv.DoltDB, err = doltdb.LoadDoltDB(ctx, nbf, dEnv.urlStr)
+	dEnv.DoltDB, err = doltdb.LoadDoltDBWithParams(ctx, nbf, dEnv.urlStr, dEnv.Config.storeParams())
 
 	return err
 }
@@ -248,7 +279,7 @@ func (dEnv *DoltEnv) initDBAndState(ctx context.Context, nbf *types.NomsBinForma
 
 func (dEnv *DoltEnv) initDBAndStateWithTime(ctx context.Context, nbf *types.NomsBinFormat, name, email string, t time.Time) error {
 	var err error
-	dEnv.DoltDB, err = doltdb.LoadDoltDB(ctx, nbf, dEnv.urlStr)
+	dEnv.DoltDB, err = doltdb.LoadDoltDBWithParams(ctx, nbf, dEnv.urlStr, dEnv.Config.storeParams())
 
 	if err != nil {
 		return err
@@ -281,6 +312,10 @@ func (dEnv *DoltEnv) initDBAndStateWithTime(ctx context.Context, nbf *types.Noms
 		return ErrStateUpdate
 	}
 
+	// The repository didn't exist when the environment was loaded, which is no longer an error
+	dEnv.RSLoadErr = nil
+	dEnv.DBLoadError = nil
+
 	return nil
 }
 
@@ -344,38 +379,85 @@ func (dEnv *DoltEnv) PutTableToWorking(ctx context.Context, rows types.Map, sch
 		return doltdb.ErrNomsIO
 	}
 
+	newRoot, err := dEnv.PutTableToRoot(ctx, root, rows, sch, tableName)
+
+	if err != nil {
+		return err
+	}
+
+	rootHash, err := root.HashOf()
+
+	if err != nil {
+		return err
+	}
+
+	newRootHash, err := newRoot.HashOf()
+
+	if rootHash == newRootHash {
+		return nil
+	}
+
+	return dEnv.UpdateWorkingRoot(ctx, newRoot)
+}
+
+// PutTableToRoot returns the root given with the table of the name given replaced by one with the rows and schema
+// given, in the same way PutTableToWorking does for the working root.
+func (dEnv *DoltEnv) PutTableToRoot(ctx context.Context, root *doltdb.RootValue, rows types.Map, sch schema.Schema, tableName string) (*doltdb.RootValue, error) {
 	vrw := dEnv.DoltDB.ValueReadWriter()
 	schVal, err := encoding.MarshalAsNomsValue(ctx, vrw, sch)
 
 	if err != nil {
-		return ErrMarshallingSchema
+		return nil, ErrMarshallingSchema
 	}
 
 	tbl, err := doltdb.NewTable(ctx, vrw, schVal, rows)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	newRoot, err := root.PutTable(ctx, tableName, tbl)
+	tbl, err = keepAppendOnly(ctx, root, tableName, tbl, sch)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	rootHash, err := root.HashOf()
+	return root.PutTable(ctx, tableName, tbl)
+}
+
+// keepAppendOnly returns the table given, with the rows of the table being replaced, as the table with the name given in
+// the root given if that table is append-only. Its rows are only replaced if none of its existing rows are changed or
+// removed, and its schema can't be replaced. Otherwise doltdb.ErrAppendOnly is returned.
+func keepAppendOnly(ctx context.Context, root *doltdb.RootValue, tableName string, tbl *doltdb.Table, sch schema.Schema) (*doltdb.Table, error) {
+	existing, ok, err := root.GetTable(ctx, tableName)
+
+	if err != nil || !ok {
+		return tbl, err
+	}
+
+	if appendOnly, err := existing.IsAppendOnly(); err != nil || !appendOnly {
+		return tbl, err
+	}
+
+	existingSch, err := existing.GetSchema(ctx)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	newRootHash, err := newRoot.HashOf()
+	if eq, err := schema.SchemasAreEqual(existingSch, sch); err != nil {
+		return nil, err
+	} else if !eq {
+		return nil, doltdb.ErrAppendOnly
+	}
 
-	if rootHash == newRootHash {
-		return nil
+	rows, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return dEnv.UpdateWorkingRoot(ctx, newRoot)
+	return existing.UpdateRows(ctx, rows)
 }
 
 func (dEnv *DoltEnv) IsMergeActive() bool {
@@ -418,6 +500,18 @@ func (dEnv *DoltEnv) CredsDir() (string, error) {
 }
 
 func (dEnv *DoltEnv) getRPCCreds() (credentials.PerRPCCredentials, error) {
+	dCreds, ok, err := dEnv.UserCreds()
+
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return dCreds, nil
+}
+
+// UserCreds returns the credentials the user has configured with user.creds, which is their key id or public key,
+// and false if there aren't any.
+func (dEnv *DoltEnv) UserCreds() (creds.DoltCreds, bool, error) {
 	kid, err := dEnv.Config.GetString(UserCreds)
 
 	if err == nil && kid != "" {
@@ -428,16 +522,22 @@ func (dEnv *DoltEnv) getRPCCreds() (credentials.PerRPCCredentials, error) {
 			panic(err)
 		}
 
-		dCreds, err := creds.JWKCredsReadFromFile(dEnv.FS, filepath.Join(dir, kid+".jwk"))
+		path, err := dEnv.FindCreds(dir, kid)
 
 		if err != nil {
-			return nil, ErrInvalidCredsFile
+			return creds.DoltCreds{}, false, ErrInvalidCredsFile
 		}
 
-		return dCreds, nil
+		dCreds, err := creds.JWKCredsReadFromFile(dEnv.FS, path)
+
+		if err != nil {
+			return creds.DoltCreds{}, false, ErrInvalidCredsFile
+		}
+
+		return dCreds, true, nil
 	}
 
-	return nil, nil
+	return creds.DoltCreds{}, false, nil
 }
 
 func (dEnv *DoltEnv) GrpcConnWithCreds(hostAndPort string, insecure bool, rpcCreds credentials.PerRPCCredentials) (*grpc.ClientConn, error) {
@@ -614,6 +714,13 @@ func (dEnv *DoltEnv) GetUserHomeDir() (string, error) {
 	return getHomeDir(dEnv.hdp)
 }
 
+// TempTableFilesDir returns the directory that temporary table files are written to, which is absolute so that it can
+// be used by code that doesn't access files through the environment's filesystem.
 func (dEnv *DoltEnv) TempTableFilesDir() string {
-	return filepath.Join(dEnv.GetDoltDir(), tempTablesDir)
+	dir := filepath.Join(dEnv.GetDoltDir(), tempTablesDir)
+	if absDir, err := dEnv.FS.Abs(dir); err == nil {
+		return absDir
+	}
+
+	return dir
 }
@@ -0,0 +1,91 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"encoding/json"
+
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+// ImportCheckpoint records the progress of a table import, so that an import which is interrupted can be resumed from
+// its last checkpoint rather than starting over. It is stored in the .dolt directory, and there is at most one.
+type ImportCheckpoint struct {
+	Table     string   `json:"table"`
+	Operation string   `json:"operation"`
+	Files     []string `json:"files"`
+	// Interval is the number of rows read between checkpoints
+	Interval int64 `json:"interval"`
+	// Rows is the number of rows read from the files that have been imported into the table
+	Rows int64 `json:"rows"`
+	// BadRows is the number of the rows read which were skipped as bad rows
+	BadRows int64 `json:"bad_rows"`
+	// AddedColumns are the names of the columns added to the table for the fields of the files it was missing
+	AddedColumns []string `json:"added_columns"`
+	// Root is the hash of the root value holding the table with the rows imported so far
+	Root string `json:"root"`
+}
+
+// LoadImportCheckpoint returns the import checkpoint of the repository, and whether there is one.
+func LoadImportCheckpoint(fs filesys.ReadableFS) (*ImportCheckpoint, bool, error) {
+	path := getImportCheckpointFile()
+
+	if exists, _ := fs.Exists(path); !exists {
+		return nil, false, nil
+	}
+
+	data, err := fs.ReadFile(path)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var cp ImportCheckpoint
+	err = json.Unmarshal(data, &cp)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &cp, true, nil
+}
+
+// RootHash returns the hash of the root value holding the rows imported as of the checkpoint.
+func (cp *ImportCheckpoint) RootHash() hash.Hash {
+	return hash.Parse(cp.Root)
+}
+
+// Save writes the checkpoint to the repository, replacing any existing checkpoint.
+func (cp *ImportCheckpoint) Save(fs filesys.WritableFS) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return fs.WriteFile(getImportCheckpointFile(), data)
+}
+
+// RemoveImportCheckpoint removes the import checkpoint of the repository, if there is one.
+func RemoveImportCheckpoint(fs filesys.ReadWriteFS) error {
+	path := getImportCheckpointFile()
+
+	if exists, _ := fs.Exists(path); !exists {
+		return nil
+	}
+
+	return fs.DeleteFile(path)
+}
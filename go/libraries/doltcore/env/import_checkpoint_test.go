@@ -0,0 +1,58 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+func TestImportCheckpoint(t *testing.T) {
+	dEnv := createTestEnv(true, true)
+
+	_, ok, err := LoadImportCheckpoint(dEnv.FS)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	rootHash := hash.Of([]byte("root"))
+	cp := &ImportCheckpoint{
+		Table:        "test",
+		Operation:    "update",
+		Files:        []string{"part-1.csv", "part-2.csv"},
+		Interval:     1000,
+		Rows:         3000,
+		BadRows:      2,
+		AddedColumns: []string{"c6"},
+		Root:         rootHash.String(),
+	}
+	require.NoError(t, cp.Save(dEnv.FS))
+
+	loaded, ok, err := LoadImportCheckpoint(dEnv.FS)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, cp, loaded)
+	assert.Equal(t, rootHash, loaded.RootHash())
+
+	require.NoError(t, RemoveImportCheckpoint(dEnv.FS))
+	_, ok, err = LoadImportCheckpoint(dEnv.FS)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, RemoveImportCheckpoint(dEnv.FS))
+}
@@ -30,6 +30,10 @@ const (
 	globalConfig = "config_global.json"
 
 	repoStateFile = "repo_state.json"
+
+	importCheckpointFile = "import_checkpoint.json"
+
+	cloneStateFile = "clone_state.json"
 )
 
 // HomeDirProvider is a function that returns the users home directory.  This is where global dolt state is stored for
@@ -77,6 +81,14 @@ func getRepoStateFile() string {
 	return filepath.Join(dbfactory.DoltDir, repoStateFile)
 }
 
+func getImportCheckpointFile() string {
+	return filepath.Join(dbfactory.DoltDir, importCheckpointFile)
+}
+
+func getCloneStateFile() string {
+	return filepath.Join(dbfactory.DoltDir, cloneStateFile)
+}
+
 func getHomeDir(hdp HomeDirProvider) (string, error) {
 	homeDir, err := hdp()
 	if err != nil {
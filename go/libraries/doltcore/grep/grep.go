@@ -0,0 +1,272 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grep
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// Match is a cell value that matched a search.
+type Match struct {
+	Table  string
+	Key    string    // The primary key of the row, formatted as (name=value, ...)
+	Column string    // The name of the column
+	Value  string    // The value, formatted as a string
+	Commit hash.Hash // The commit the value was found in, or the empty hash if it was found in a working root
+}
+
+// MatchCallback is called with each match of a search. Returning an error stops the search.
+type MatchCallback func(m Match) error
+
+// Searcher finds the cell values of tables that match a regular expression. The leaf chunks of each table's row data
+// are read and searched in parallel.
+//
+// A Searcher can search many roots, such as those of each commit in a branch's history. Leaf chunks it has already
+// searched in an earlier root are skipped, as are matches it has already reported, so each distinct value of a cell is
+// only reported for the first root it's found in.
+type Searcher struct {
+	re          *regexp.Regexp
+	parallelism int
+
+	searched map[string]bool // The leaf chunks already searched, by table, schema and chunk hash
+	reported map[string]bool // The matches already reported, by table, key, column and value hash
+}
+
+// NewSearcher returns a Searcher for cell values that match the regular expression given.
+func NewSearcher(re *regexp.Regexp) *Searcher {
+	return &Searcher{
+		re:          re,
+		parallelism: runtime.NumCPU(),
+		searched:    make(map[string]bool),
+		reported:    make(map[string]bool),
+	}
+}
+
+// SearchRoot searches the tables with the names given in the root given, or all of its tables if none are given, and
+// calls cb with each match in table, then primary key, order. commit is the hash of the commit the root belongs to,
+// which is the empty hash for working roots. Tables that aren't in the root are skipped.
+func (s *Searcher) SearchRoot(ctx context.Context, root *doltdb.RootValue, commit hash.Hash, tblNames []string, cb MatchCallback) error {
+	if len(tblNames) == 0 {
+		var err error
+		tblNames, err = root.GetTableNames(ctx)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, tblName := range tblNames {
+		tbl, ok, err := root.GetTable(ctx, tblName)
+
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		if err := s.searchTable(ctx, tblName, tbl, commit, cb); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Searcher) searchTable(ctx context.Context, tblName string, tbl *doltdb.Table, commit hash.Hash, cb MatchCallback) error {
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	schRef, err := tbl.GetSchemaRef()
+
+	if err != nil {
+		return err
+	}
+
+	leaves, err := tbl.GetRowDataLeaves(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	var toSearch []hash.Hash
+	for _, leaf := range leaves {
+		id := tblName + ":" + schRef.TargetHash().String() + ":" + leaf.Hash.String()
+
+		if !s.searched[id] {
+			s.searched[id] = true
+			toSearch = append(toSearch, leaf.Hash)
+		}
+	}
+
+	results := s.searchLeaves(ctx, tblName, tbl, sch, toSearch)
+
+	for _, res := range results {
+		if res.err != nil {
+			return res.err
+		}
+
+		for _, m := range res.matches {
+			m.Commit = commit
+			id := tblName + ":" + m.id
+
+			if s.reported[id] {
+				continue
+			}
+
+			s.reported[id] = true
+
+			if err := cb(m.Match); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type leafMatch struct {
+	Match
+	id string // Identifies the key, column and value of the match within its table
+}
+
+type leafResult struct {
+	matches []leafMatch
+	err     error
+}
+
+// searchLeaves searches the leaf chunks of the table's row data with the hashes given in parallel, and returns the
+// results of each chunk in the order of the hashes given.
+func (s *Searcher) searchLeaves(ctx context.Context, tblName string, tbl *doltdb.Table, sch schema.Schema, leaves []hash.Hash) []leafResult {
+	results := make([]leafResult, len(leaves))
+	indexes := make(chan int, len(leaves))
+
+	for i := range leaves {
+		indexes <- i
+	}
+
+	close(indexes)
+
+	wg := &sync.WaitGroup{}
+	for w := 0; w < s.parallelism && w < len(leaves); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				results[i].matches, results[i].err = s.searchLeaf(ctx, tblName, tbl, sch, leaves[i])
+			}
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+func (s *Searcher) searchLeaf(ctx context.Context, tblName string, tbl *doltdb.Table, sch schema.Schema, leaf hash.Hash) ([]leafMatch, error) {
+	rows, err := tbl.GetRowDataLeaf(ctx, leaf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []leafMatch
+	err = rows.IterAll(ctx, func(k, v types.Value) error {
+		r, err := row.FromNoms(sch, k.(types.Tuple), v.(types.Tuple))
+
+		if err != nil {
+			return err
+		}
+
+		var key string
+		return sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+			val, ok := r.GetColVal(tag)
+
+			if !ok {
+				return false, nil
+			}
+
+			str := valueString(val)
+
+			if !s.re.MatchString(str) {
+				return false, nil
+			}
+
+			if key == "" {
+				key = keyString(sch, r)
+			}
+
+			valHash, err := val.Hash(rows.Format())
+
+			if err != nil {
+				return false, err
+			}
+
+			matches = append(matches, leafMatch{
+				Match: Match{Table: tblName, Key: key, Column: col.Name, Value: str},
+				id:    key + ":" + col.Name + ":" + valHash.String(),
+			})
+
+			return false, nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// keyString formats the primary key of the row given as (name=value, ...).
+func keyString(sch schema.Schema, r row.Row) string {
+	var strs []string
+	for _, col := range sch.GetPKCols().GetColumns() {
+		val, _ := r.GetColVal(col.Tag)
+		strs = append(strs, col.Name+"="+valueString(val))
+	}
+
+	return "(" + strings.Join(strs, ", ") + ")"
+}
+
+// valueString returns the value given as it's matched against the pattern of a search.
+func valueString(val types.Value) string {
+	if types.IsNull(val) {
+		return "NULL"
+	}
+
+	str := fmt.Sprintf("%v", val)
+	if toStr, err := doltcore.GetConvFunc(val.Kind(), types.StringKind); err == nil {
+		if strVal, err := toStr(val); err == nil && !types.IsNull(strVal) {
+			str = string(strVal.(types.String))
+		}
+	}
+
+	return str
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grep
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+func search(t *testing.T, s *Searcher, ctx context.Context, commit hash.Hash) []Match {
+	dEnv := dtestutils.CreateTestEnv()
+	dtestutils.CreateTestTable(t, dEnv, "people", dtestutils.TypedSchema, dtestutils.TypedRows...)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	var matches []Match
+	err = s.SearchRoot(ctx, root, commit, nil, func(m Match) error {
+		matches = append(matches, m)
+		return nil
+	})
+	require.NoError(t, err)
+
+	return matches
+}
+
+func TestSearchRoot(t *testing.T) {
+	ctx := context.Background()
+	s := NewSearcher(regexp.MustCompile("Dufus|^25$"))
+
+	matches := search(t, s, ctx, hash.Hash{})
+	assert.Equal(t, []Match{
+		{Table: "people", Key: "(id=00000000-0000-0000-0000-000000000000)", Column: "title", Value: "Senior Dufus"},
+		{Table: "people", Key: "(id=00000000-0000-0000-0000-000000000001)", Column: "age", Value: "25"},
+		{Table: "people", Key: "(id=00000000-0000-0000-0000-000000000001)", Column: "title", Value: "Dufus"},
+	}, matches)
+
+	// values that were already reported aren't reported again
+	matches = search(t, s, ctx, hash.Of([]byte("commit")))
+	assert.Empty(t, matches)
+}
+
+func TestSearchRootNoMatches(t *testing.T) {
+	ctx := context.Background()
+	s := NewSearcher(regexp.MustCompile("nobody"))
+
+	matches := search(t, s, ctx, hash.Hash{})
+	assert.Empty(t, matches)
+}
@@ -0,0 +1,367 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/creds"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+var ErrNotSigned = errors.New("the manifest isn't signed")
+var ErrBadSignature = errors.New("the manifest's signature doesn't match its contents")
+
+// Manifest lists a content hash for each table of a commit, computed from the values of its rows as they're
+// exported, so that an export of the commit's tables can be checked against it without the repository. It's signed
+// with the credentials of the user who wrote it.
+type Manifest struct {
+	Commit    string          `json:"commit"`
+	Tables    []TableManifest `json:"tables"`
+	KeyID     string          `json:"key_id"`
+	PublicKey string          `json:"public_key"`
+	Signature string          `json:"signature"`
+}
+
+// TableManifest is the content hash of a table. Rows are hashed in primary key order, which is the order dolt table
+// export writes them in, and each value is hashed as the string it's exported as.
+type TableManifest struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    uint64   `json:"rows"`
+	SHA256  string   `json:"sha256"`
+}
+
+// NewManifest returns the unsigned manifest of the commit given.
+func NewManifest(ctx context.Context, cm *doltdb.Commit) (*Manifest, error) {
+	h, err := cm.HashOf()
+
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := cm.GetRootValue()
+
+	if err != nil {
+		return nil, err
+	}
+
+	tblNames, err := root.GetTableNames(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(tblNames)
+
+	m := &Manifest{Commit: h.String(), Tables: make([]TableManifest, 0, len(tblNames))}
+	for _, tblName := range tblNames {
+		tbl, _, err := root.GetTable(ctx, tblName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tm, err := newTableManifest(ctx, tblName, tbl)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash table '%s': %v", tblName, err)
+		}
+
+		m.Tables = append(m.Tables, tm)
+	}
+
+	return m, nil
+}
+
+func newTableManifest(ctx context.Context, tblName string, tbl *doltdb.Table) (TableManifest, error) {
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return TableManifest{}, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return TableManifest{}, err
+	}
+
+	allCols := sch.GetAllCols()
+	tm := TableManifest{Name: tblName, Columns: make([]string, 0, allCols.Size())}
+	tags := make([]uint64, 0, allCols.Size())
+	_ = allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		tm.Columns = append(tm.Columns, col.Name)
+		tags = append(tags, tag)
+		return false, nil
+	})
+
+	rh := newRowHasher()
+	vals := make([]*string, len(tags))
+	err = rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+		r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+
+		if err != nil {
+			return true, err
+		}
+
+		for i, tag := range tags {
+			vals[i], err = exportedString(ctx, r, tag)
+
+			if err != nil {
+				return true, err
+			}
+		}
+
+		rh.add(vals)
+		return false, nil
+	})
+
+	if err != nil {
+		return TableManifest{}, err
+	}
+
+	tm.Rows, tm.SHA256 = rh.sum()
+	return tm, nil
+}
+
+// exportedString returns the value of the column given as dolt table export writes it, or nil if it's NULL.
+func exportedString(ctx context.Context, r row.Row, tag uint64) (*string, error) {
+	val, ok := r.GetColVal(tag)
+
+	if !ok || types.IsNull(val) {
+		return nil, nil
+	}
+
+	var str string
+	if val.Kind() == types.StringKind {
+		str = string(val.(types.String))
+	} else {
+		var err error
+		str, err = types.EncodedValue(ctx, val)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &str, nil
+}
+
+// rowHasher hashes rows of values. Each value is written as a 0 byte if it's NULL, and otherwise as a 1 byte followed
+// by its length as a big endian uint64 and its bytes. An empty value is the same as a NULL, as a CSV file can't tell
+// them apart.
+type rowHasher struct {
+	h    hash.Hash
+	rows uint64
+}
+
+func newRowHasher() *rowHasher {
+	return &rowHasher{h: sha256.New()}
+}
+
+func (rh *rowHasher) add(vals []*string) {
+	var lenBytes [8]byte
+	for _, val := range vals {
+		if val == nil || *val == "" {
+			_, _ = rh.h.Write([]byte{0})
+			continue
+		}
+
+		binary.BigEndian.PutUint64(lenBytes[:], uint64(len(*val)))
+		_, _ = rh.h.Write([]byte{1})
+		_, _ = rh.h.Write(lenBytes[:])
+		_, _ = io.WriteString(rh.h, *val)
+	}
+
+	rh.rows++
+}
+
+func (rh *rowHasher) sum() (uint64, string) {
+	return rh.rows, hex.EncodeToString(rh.h.Sum(nil))
+}
+
+// Sign signs the manifest with the credentials given.
+func (m *Manifest) Sign(dc creds.DoltCreds) error {
+	if !dc.IsPrivKeyValid() {
+		return errors.New("the credentials have no valid private key to sign with")
+	}
+
+	m.KeyID = dc.KeyIDBase32Str()
+	m.PublicKey = dc.PubKeyBase32Str()
+	m.Signature = ""
+
+	data, err := json.Marshal(m)
+
+	if err != nil {
+		return err
+	}
+
+	m.Signature = creds.B32CredsEncoding.EncodeToString(dc.Sign(data))
+	return nil
+}
+
+// VerifySignature checks that the manifest is signed by the key it names, and hasn't changed since it was signed. It
+// doesn't say who the key belongs to, which callers must check against the key id.
+func (m *Manifest) VerifySignature() error {
+	if m.Signature == "" {
+		return ErrNotSigned
+	}
+
+	pub, err := creds.B32CredsEncoding.DecodeString(m.PublicKey)
+
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return errors.New("the manifest's public key is invalid")
+	} else if creds.PubKeyToKIDStr(pub) != m.KeyID {
+		return errors.New("the manifest's key id isn't that of its public key")
+	}
+
+	sig, err := creds.B32CredsEncoding.DecodeString(m.Signature)
+
+	if err != nil {
+		return ErrBadSignature
+	}
+
+	unsigned := *m
+	unsigned.Signature = ""
+	data, err := json.Marshal(&unsigned)
+
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(pub, data, sig) {
+		return ErrBadSignature
+	}
+
+	return nil
+}
+
+// Table returns the manifest of the table named, and false if the manifest doesn't list it.
+func (m *Manifest) Table(tblName string) (TableManifest, bool) {
+	for _, tm := range m.Tables {
+		if tm.Name == tblName {
+			return tm, true
+		}
+	}
+
+	return TableManifest{}, false
+}
+
+// Write writes the manifest as JSON to the path given.
+func (m *Manifest) Write(fs filesys.WritableFS, path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return fs.WriteFile(path, append(data, '\n'))
+}
+
+// Load reads the manifest at the path given.
+func Load(fs filesys.ReadableFS, path string) (*Manifest, error) {
+	data, err := fs.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("'%s' isn't a valid manifest: %v", path, err)
+	}
+
+	return &m, nil
+}
+
+// VerifyCSV checks that the CSV file at the path given, which is delimited by delim, has the table's rows. Its
+// columns may be in any order, but its rows must be in the order they were exported in.
+func (tm TableManifest) VerifyCSV(ctx context.Context, fs filesys.ReadableFS, path string, delim string) error {
+	rd, err := csv.OpenCSVReader(types.Format_Default, path, fs, csv.NewCSVInfo().SetDelim(delim))
+
+	if err != nil {
+		return err
+	}
+
+	defer rd.Close(ctx)
+
+	fileCols := rd.GetSchema().GetAllCols()
+	if fileCols.Size() != len(tm.Columns) {
+		return fmt.Errorf("has %d columns instead of %d", fileCols.Size(), len(tm.Columns))
+	}
+
+	tags := make([]uint64, len(tm.Columns))
+	for i, colName := range tm.Columns {
+		col, ok := fileCols.GetByName(colName)
+
+		if !ok {
+			return fmt.Errorf("has no column '%s'", colName)
+		}
+
+		tags[i] = col.Tag
+	}
+
+	rh := newRowHasher()
+	vals := make([]*string, len(tags))
+	for {
+		r, err := rd.ReadRow(ctx)
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			if table.IsBadRow(err) {
+				return fmt.Errorf("has a bad row after %d rows: %v", rh.rows, err)
+			}
+			return err
+		}
+
+		for i, tag := range tags {
+			vals[i], err = exportedString(ctx, r, tag)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		rh.add(vals)
+	}
+
+	rows, sum := rh.sum()
+	if rows != tm.Rows {
+		return fmt.Errorf("has %d rows instead of %d", rows, tm.Rows)
+	} else if sum != tm.SHA256 {
+		return errors.New("has rows that differ from those of the table")
+	}
+
+	return nil
+}
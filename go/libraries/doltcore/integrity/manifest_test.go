@@ -0,0 +1,194 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrity
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/creds"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestManifest(t *testing.T) {
+	ctx := context.Background()
+	cm := commitTestData(t)
+
+	m, err := NewManifest(ctx, cm)
+	require.NoError(t, err)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, h.String(), m.Commit)
+	require.Len(t, m.Tables, 1)
+	tm := m.Tables[0]
+	assert.Equal(t, "people", tm.Name)
+	assert.Equal(t, []string{"id", "name", "age", "is_married", "title"}, tm.Columns)
+	assert.Equal(t, uint64(len(dtestutils.TypedRows)), tm.Rows)
+
+	assert.Equal(t, ErrNotSigned, m.VerifySignature())
+	dc, err := creds.GenerateCredentials()
+	require.NoError(t, err)
+	require.NoError(t, m.Sign(dc))
+	assert.Equal(t, dc.KeyIDBase32Str(), m.KeyID)
+	assert.NoError(t, m.VerifySignature())
+
+	fs := filesys.NewInMemFS(nil, nil, "/")
+	require.NoError(t, m.Write(fs, "/manifest.json"))
+	loaded, err := Load(fs, "/manifest.json")
+	require.NoError(t, err)
+	assert.Equal(t, m, loaded)
+
+	loaded.Tables[0].Rows++
+	assert.Equal(t, ErrBadSignature, loaded.VerifySignature())
+}
+
+func TestVerifyCSV(t *testing.T) {
+	ctx := context.Background()
+	cm := commitTestData(t)
+	m, err := NewManifest(ctx, cm)
+	require.NoError(t, err)
+	tm := m.Tables[0]
+
+	fs := filesys.NewInMemFS(nil, nil, "/")
+	exportTable(t, cm, "people", fs, "/people.csv", ",")
+	assert.NoError(t, tm.VerifyCSV(ctx, fs, "/people.csv", ","))
+	exportTable(t, cm, "people", fs, "/people.psv", "|")
+	assert.NoError(t, tm.VerifyCSV(ctx, fs, "/people.psv", "|"))
+
+	exported, err := fs.ReadFile("/people.csv")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		csv         string
+		expectedErr string
+	}{
+		{
+			name:        "missing column",
+			csv:         "id,name,age,is_married\n",
+			expectedErr: "has 4 columns instead of 5",
+		},
+		{
+			name:        "other column",
+			csv:         "id,name,age,is_married,job_title\n",
+			expectedErr: "has no column 'title'",
+		},
+		{
+			name:        "missing rows",
+			csv:         "id,name,age,is_married,title\n",
+			expectedErr: "has 0 rows instead of 3",
+		},
+		{
+			name:        "changed row",
+			csv:         strings.Replace(string(exported), "Bill", "Will", 1),
+			expectedErr: "has rows that differ from those of the table",
+		},
+		{
+			name:        "reordered rows",
+			csv:         reorderLines(string(exported), 0, 2, 1, 3),
+			expectedErr: "has rows that differ from those of the table",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.NoError(t, fs.WriteFile("/test.csv", []byte(test.csv)))
+			err := tm.VerifyCSV(ctx, fs, "/test.csv", ",")
+			require.Error(t, err)
+			assert.Equal(t, test.expectedErr, err.Error())
+		})
+	}
+
+	// the columns can be in any order
+	var reordered []string
+	for _, line := range strings.Split(strings.TrimSpace(string(exported)), "\n") {
+		fields := strings.Split(line, ",")
+		reordered = append(reordered, strings.Join(append(fields[1:], fields[0]), ","))
+	}
+	require.NoError(t, fs.WriteFile("/reordered.csv", []byte(strings.Join(reordered, "\n"))))
+	assert.NoError(t, tm.VerifyCSV(ctx, fs, "/reordered.csv", ","))
+}
+
+// reorderLines returns the lines of the text given in the order of the indexes given.
+func reorderLines(text string, indexes ...int) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	reordered := make([]string, len(indexes))
+	for i, idx := range indexes {
+		reordered[i] = lines[idx]
+	}
+	return strings.Join(reordered, "\n")
+}
+
+// commitTestData commits the test data table of dtestutils as people, and returns the commit.
+func commitTestData(t *testing.T) *doltdb.Commit {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	imt, sch := dtestutils.CreateTestDataTable(true)
+
+	rd := table.NewInMemTableReader(imt)
+	wr := noms.NewNomsMapCreator(ctx, dEnv.DoltDB.ValueReadWriter(), sch)
+	_, _, err := table.PipeRows(ctx, rd, wr, false)
+	require.NoError(t, err)
+	require.NoError(t, rd.Close(ctx))
+	require.NoError(t, wr.Close(ctx))
+	require.NoError(t, dEnv.PutTableToWorking(ctx, *wr.GetMap(), wr.GetSchema(), "people"))
+
+	require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+	require.NoError(t, actions.CommitStaged(ctx, dEnv, "people", time.Now(), false))
+
+	cs, err := doltdb.NewCommitSpec("HEAD", "master")
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+	require.NoError(t, err)
+	return cm
+}
+
+// exportTable writes the rows of the table of the commit given to a CSV file, as dolt table export does.
+func exportTable(t *testing.T, cm *doltdb.Commit, tblName string, fs filesys.WritableFS, path string, delim string) {
+	ctx := context.Background()
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+	tbl, _, err := root.GetTable(ctx, tblName)
+	require.NoError(t, err)
+	sch, err := tbl.GetSchema(ctx)
+	require.NoError(t, err)
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+
+	wr, err := csv.OpenCSVWriter(path, fs, sch, csv.NewCSVInfo().SetDelim(delim))
+	require.NoError(t, err)
+	err = rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+		r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+		if err != nil {
+			return true, err
+		}
+		return false, wr.WriteRow(ctx, r)
+	})
+	require.NoError(t, err)
+	require.NoError(t, wr.Close(ctx))
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"io"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	// ConflictOpCol is the name of the column holding the change each version of a conflicting row made, when
+	// conflicts are written as csv.
+	ConflictOpCol = "op"
+	// ConflictSourceCol is the name of the column holding the version of a conflicting row, when conflicts are
+	// written as csv.
+	ConflictSourceCol = "source"
+
+	// ConflictOpRemoved is the op of a version of a row that removed it.
+	ConflictOpRemoved = "removed"
+)
+
+var mergeVersionToCSVLabel = map[MergeVersion]string{
+	OurVersion:   "ours",
+	TheirVersion: "theirs",
+	BaseVersion:  "base",
+}
+var diffTypeToCSVOp = map[types.DiffChangeType]string{
+	types.DiffChangeAdded:    "added",
+	types.DiffChangeRemoved:  ConflictOpRemoved,
+	types.DiffChangeModified: "modified",
+}
+
+// ConflictCSVSink writes the rows read by a ConflictReader as csv, with the op and source of each row in the first two
+// columns.
+type ConflictCSVSink struct {
+	sch   schema.Schema
+	csvWr *csv.CSVWriter
+}
+
+// NewConflictCSVSink creates a ConflictCSVSink that writes to the WriteCloser given. sch is the schema of the
+// ConflictReader being written.
+func NewConflictCSVSink(wr io.WriteCloser, sch schema.Schema) (*ConflictCSVSink, error) {
+	_, additionalCols := untyped.NewUntypedSchemaWithFirstTag(opColTag, ConflictOpCol, ConflictSourceCol)
+	outSch, err := untyped.UntypedSchemaUnion(additionalCols, sch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	csvWr, err := csv.NewCSVWriter(wr, outSch, csv.NewCSVInfo())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConflictCSVSink{outSch, csvWr}, nil
+}
+
+// GetSchema gets the schema of the rows that this writer writes
+func (cs *ConflictCSVSink) GetSchema() schema.Schema {
+	return cs.sch
+}
+
+// ProcRowWithProps writes a row read by a ConflictReader, along with its op and source.
+func (cs *ConflictCSVSink) ProcRowWithProps(r row.Row, props pipeline.ReadableMap) error {
+	taggedVals := make(row.TaggedValues)
+
+	mergeVersion, _ := props.Get(mergeVersionProp)
+	taggedVals[sourceColTag] = types.String(mergeVersionToCSVLabel[mergeVersion.(MergeVersion)])
+
+	if mergeRowOp, ok := props.Get(mergeRowOperation); ok {
+		taggedVals[opColTag] = types.String(diffTypeToCSVOp[mergeRowOp.(types.DiffChangeType)])
+	}
+
+	_, err := r.IterCols(func(tag uint64, val types.Value) (stop bool, err error) {
+		taggedVals[tag] = val
+		return false, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	r, err = row.New(r.Format(), cs.sch, taggedVals)
+
+	if err != nil {
+		return err
+	}
+
+	return cs.csvWr.WriteRow(context.TODO(), r)
+}
+
+// Close flushes the rows written and releases the resources being held
+func (cs *ConflictCSVSink) Close(ctx context.Context) error {
+	return cs.csvWr.Close(ctx)
+}
@@ -162,7 +162,7 @@ func (cr *ConflictReader) NextConflict(ctx context.Context) (row.Row, pipeline.I
 				return nil, pipeline.ImmutableProperties{}, err
 			}
 
-			mergeRow, err := createRow(keyTpl, conflict.MergeValue.(types.Tuple), cr.mergeConv)
+			mergeRow, err := createRow(keyTpl, conflict.MergeValue, cr.mergeConv)
 
 			if err != nil {
 				return nil, pipeline.ImmutableProperties{}, err
@@ -32,12 +32,13 @@ import (
 
 var ErrFastForward = errors.New("fast forward")
 var ErrSameTblAddedTwice = errors.New("table with same name added in 2 commits can't be merged")
+var ErrTblDeletedAndModified = errors.New("table deleted on one side and modified on the other can't be merged")
 
 type Merger struct {
-	commit      *doltdb.Commit
-	mergeCommit *doltdb.Commit
-	ancestor    *doltdb.Commit
-	vrw         types.ValueReadWriter
+	root      *doltdb.RootValue
+	mergeRoot *doltdb.RootValue
+	ancRoot   *doltdb.RootValue
+	vrw       types.ValueReadWriter
 }
 
 func NewMerger(ctx context.Context, commit, mergeCommit *doltdb.Commit, vrw types.ValueReadWriter) (*Merger, error) {
@@ -53,28 +54,37 @@ func NewMerger(ctx context.Context, commit, mergeCommit *doltdb.Commit, vrw type
 	} else if ff {
 		return nil, ErrFastForward
 	}
-	return &Merger{commit, mergeCommit, ancestor, vrw}, nil
-}
 
-func (merger *Merger) MergeTable(ctx context.Context, tblName string) (*doltdb.Table, *MergeStats, error) {
-	root, err := merger.commit.GetRootValue()
+	root, err := commit.GetRootValue()
 
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	mergeRoot, err := merger.mergeCommit.GetRootValue()
+	mergeRoot, err := mergeCommit.GetRootValue()
 
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	ancRoot, err := merger.ancestor.GetRootValue()
+	ancRoot, err := ancestor.GetRootValue()
 
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
+	return &Merger{root, mergeRoot, ancRoot, vrw}, nil
+}
+
+// NewRootMerger returns a Merger which merges the tables of mergeRoot into those of root, where ancRoot is the root both
+// were changed from.
+func NewRootMerger(root, mergeRoot, ancRoot *doltdb.RootValue, vrw types.ValueReadWriter) *Merger {
+	return &Merger{root, mergeRoot, ancRoot, vrw}
+}
+
+func (merger *Merger) MergeTable(ctx context.Context, tblName string) (*doltdb.Table, *MergeStats, error) {
+	root, mergeRoot, ancRoot := merger.root, merger.mergeRoot, merger.ancRoot
+
 	tbl, ok, err := root.GetTable(ctx, tblName)
 
 	if err != nil {
@@ -138,6 +148,8 @@ func (merger *Merger) MergeTable(ctx context.Context, tblName string) (*doltdb.T
 		return mergeTbl, &MergeStats{Operation: TableModified}, nil
 	} else if mh == anch {
 		return tbl, &MergeStats{Operation: TableUnmodified}, nil
+	} else if !ok || !mergeOk {
+		return nil, nil, ErrTblDeletedAndModified
 	}
 
 	tblSchema, err := tbl.GetSchema(ctx)
@@ -194,6 +206,45 @@ func (merger *Merger) MergeTable(ctx context.Context, tblName string) (*doltdb.T
 		return nil, nil, err
 	}
 
+	// The merged table has the indexes of both tables. If both have an index with the same name, ours is kept.
+	mergedTable, err = mergedTable.CopyIndexes(ctx, tbl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mergedTable, err = mergedTable.CopyIndexes(ctx, mergeTbl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Rows are only added to append-only tables, so their merge is the union of the rows added on each side. The merged
+	// table is append-only if either table is.
+	mergedTable, err = mergedTable.CopyAppendOnly(tbl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mergedTable, err = mergedTable.CopyAppendOnly(mergeTbl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mergedTable, err = mergedTable.CopyDroppedTags(ctx, tbl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mergedTable, err = mergedTable.CopyDroppedTags(ctx, mergeTbl)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if conflicts.Len() > 0 {
 
 		if err != nil {
@@ -427,10 +478,10 @@ func rowMerge(ctx context.Context, nbf *types.NomsBinFormat, sch schema.Schema,
 		return nil, false, err
 	}
 
-	processTagFunc := func(tag uint64) (resultVal types.Value, isConflict bool) {
-		baseVal, _ := baseVals.Get(tag)
-		val, _ := rowVals.Get(tag)
-		mergeVal, _ := mergeVals.Get(tag)
+	processTagFunc := func(col schema.Column) (resultVal types.Value, isConflict bool) {
+		baseVal, _ := baseVals.Get(col.Tag)
+		val, _ := rowVals.Get(col.Tag)
+		mergeVal, _ := mergeVals.Get(col.Tag)
 
 		if valutil.NilSafeEqCheck(val, mergeVal) {
 			return val, false
@@ -439,7 +490,7 @@ func rowMerge(ctx context.Context, nbf *types.NomsBinFormat, sch schema.Schema,
 			mergeModified := !valutil.NilSafeEqCheck(mergeVal, baseVal)
 			switch {
 			case modified && mergeModified:
-				return nil, true
+				return mergeByStrategy(col.MergeStrategy, val, mergeVal, baseVal)
 			case modified:
 				return val, false
 			default:
@@ -452,9 +503,9 @@ func rowMerge(ctx context.Context, nbf *types.NomsBinFormat, sch schema.Schema,
 	resultVals := make(row.TaggedValues)
 
 	var isConflict bool
-	err = sch.GetNonPKCols().Iter(func(tag uint64, _ schema.Column) (stop bool, err error) {
+	err = sch.GetNonPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		var val types.Value
-		val, isConflict = processTagFunc(tag)
+		val, isConflict = processTagFunc(col)
 		resultVals[tag] = val
 
 		return isConflict, nil
@@ -0,0 +1,129 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// mergeByStrategy merges the values of a column that were changed on both sides of a merge, as the column's merge
+// strategy dictates. The base value is nil if the row was added on both sides. Returns true if the values conflict,
+// which they always do with the default strategy, and do with the others when either side set the value to NULL or the
+// values aren't of the type the strategy expects.
+func mergeByStrategy(strategy schema.MergeStrategy, val, mergeVal, baseVal types.Value) (types.Value, bool) {
+	if val == nil || mergeVal == nil {
+		return nil, true
+	}
+
+	switch strategy {
+	case schema.MergeCounter:
+		return mergeCounters(val, mergeVal, baseVal)
+	case schema.MergeSet:
+		return mergeSets(val, mergeVal, baseVal)
+	}
+
+	return nil, true
+}
+
+// mergeCounters adds the change made on each side to the base value, which counts as 0 if it's nil.
+func mergeCounters(val, mergeVal, baseVal types.Value) (types.Value, bool) {
+	switch v := val.(type) {
+	case types.Int:
+		mv, ok := mergeVal.(types.Int)
+		bv, baseOk := baseVal.(types.Int)
+		if !ok || (!baseOk && baseVal != nil) {
+			return nil, true
+		}
+		return v + mv - bv, false
+
+	case types.Float:
+		mv, ok := mergeVal.(types.Float)
+		bv, baseOk := baseVal.(types.Float)
+		if !ok || (!baseOk && baseVal != nil) {
+			return nil, true
+		}
+		return v + mv - bv, false
+
+	case types.Uint:
+		mv, ok := mergeVal.(types.Uint)
+		bv, baseOk := baseVal.(types.Uint)
+		if !ok || (!baseOk && baseVal != nil) {
+			return nil, true
+		}
+
+		// Adding the change made on the merge side in the order that keeps the result from wrapping, unless it can't
+		// be represented at all
+		if mv >= bv {
+			if v+(mv-bv) < v {
+				return nil, true
+			}
+			return v + (mv - bv), false
+		} else if v >= bv-mv {
+			return v - (bv - mv), false
+		}
+		return nil, true
+	}
+
+	return nil, true
+}
+
+// mergeSets keeps the elements of either side, except those of the base value that either side removed. The elements
+// of the result are sorted.
+func mergeSets(val, mergeVal, baseVal types.Value) (types.Value, bool) {
+	v, ok := val.(types.String)
+	mv, mergeOk := mergeVal.(types.String)
+	bv, baseOk := baseVal.(types.String)
+	if !ok || !mergeOk || (!baseOk && baseVal != nil) {
+		return nil, true
+	}
+
+	elems, mergeElems, baseElems := setElements(string(v)), setElements(string(mv)), setElements(string(bv))
+
+	merged := make(map[string]bool)
+	for elem := range elems {
+		merged[elem] = true
+	}
+	for elem := range mergeElems {
+		merged[elem] = true
+	}
+	for elem := range baseElems {
+		if !elems[elem] || !mergeElems[elem] {
+			delete(merged, elem)
+		}
+	}
+
+	result := make([]string, 0, len(merged))
+	for elem := range merged {
+		result = append(result, elem)
+	}
+	sort.Strings(result)
+
+	return types.String(strings.Join(result, schema.SetElementSeparator)), false
+}
+
+// setElements returns the elements of the value of a set column.
+func setElements(str string) map[string]bool {
+	elems := make(map[string]bool)
+	for _, elem := range strings.Split(str, schema.SetElementSeparator) {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			elems[elem] = true
+		}
+	}
+	return elems
+}
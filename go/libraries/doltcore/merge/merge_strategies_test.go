@@ -0,0 +1,112 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestRowMergeStrategies(t *testing.T) {
+	counters := []schema.MergeStrategy{schema.MergeCounter, schema.MergeCounter, schema.MergeCounter}
+	sets := []schema.MergeStrategy{schema.MergeSet, schema.MergeDefault}
+
+	tests := []RowMergeTest{
+		withStrategies(counters, createRowMergeStruct(
+			"counters incremented on both sides",
+			[]types.Value{types.Int(12), types.Uint(7), types.Float(1.5)},
+			[]types.Value{types.Int(15), types.Uint(3), types.Float(2.5)},
+			[]types.Value{types.Int(10), types.Uint(5), types.Float(1)},
+			[]types.Value{types.Int(17), types.Uint(5), types.Float(3)},
+			false,
+		)),
+		withStrategies(counters, createRowMergeStruct(
+			"counters added on both sides",
+			[]types.Value{types.Int(2), types.Uint(3), types.Float(1)},
+			[]types.Value{types.Int(-5), types.Uint(4), types.Float(2)},
+			nil,
+			[]types.Value{types.Int(-3), types.Uint(7), types.Float(3)},
+			false,
+		)),
+		withStrategies(counters, createRowMergeStruct(
+			"unsigned counter decremented below zero",
+			[]types.Value{types.Int(1), types.Uint(1), types.Float(1)},
+			[]types.Value{types.Int(1), types.Uint(0), types.Float(1)},
+			[]types.Value{types.Int(1), types.Uint(2), types.Float(1)},
+			nil,
+			true,
+		)),
+		withStrategies(counters, createRowMergeStruct(
+			"counter set to null",
+			[]types.Value{types.Int(12), types.Uint(5), types.Float(1)},
+			[]types.Value{types.NullValue, types.Uint(5), types.Float(1)},
+			[]types.Value{types.Int(10), types.Uint(5), types.Float(1)},
+			nil,
+			true,
+		)),
+		withStrategies(sets, createRowMergeStruct(
+			"elements added and removed on both sides",
+			[]types.Value{types.String("a,b,d"), types.String("x")},
+			[]types.Value{types.String("c,b,e"), types.String("x")},
+			[]types.Value{types.String("a,b,c"), types.String("x")},
+			[]types.Value{types.String("b,d,e"), types.String("x")},
+			false,
+		)),
+		withStrategies(sets, createRowMergeStruct(
+			"sets added on both sides",
+			[]types.Value{types.String("b, a"), types.String("x")},
+			[]types.Value{types.String("c,a"), types.String("x")},
+			nil,
+			[]types.Value{types.String("a,b,c"), types.String("x")},
+			false,
+		)),
+		withStrategies(sets, createRowMergeStruct(
+			"set merged and default column conflicts",
+			[]types.Value{types.String("a"), types.String("y")},
+			[]types.Value{types.String("b"), types.String("z")},
+			[]types.Value{types.String(""), types.String("x")},
+			nil,
+			true,
+		)),
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actualResult, isConflict, err := rowMerge(context.Background(), types.Format_7_18, test.sch, test.row, test.mergeRow, test.ancRow)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectConflict, isConflict)
+			assert.True(t, test.expectedResult == nil && actualResult == nil || test.expectedResult.Equals(actualResult),
+				"expected "+mustString(types.EncodedValue(context.Background(), test.expectedResult))+" got "+mustString(types.EncodedValue(context.Background(), actualResult)))
+		})
+	}
+}
+
+// withStrategies sets the merge strategies of the non-key columns of the test's schema, in order.
+func withStrategies(strategies []schema.MergeStrategy, test RowMergeTest) RowMergeTest {
+	cols := test.sch.GetAllCols().GetColumns()
+	for i := range strategies {
+		cols[i+1].MergeStrategy = strategies[i]
+	}
+
+	colColl, _ := schema.NewColCollection(cols...)
+	test.sch = schema.SchemaFromCols(colColl)
+	return test
+}
@@ -16,11 +16,16 @@ package merge
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -122,6 +127,24 @@ func ResolveTable(ctx context.Context, vrw types.ValueReadWriter, tbl *doltdb.Ta
 		return nil, err
 	}
 
+	newTbl, err = newTbl.CopyIndexes(ctx, tbl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newTbl, err = newTbl.CopyAppendOnly(tbl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newTbl, err = newTbl.CopyDroppedTags(ctx, tbl)
+
+	if err != nil {
+		return nil, err
+	}
+
 	m, err = types.NewMap(ctx, vrw)
 
 	if err != nil {
@@ -136,3 +159,175 @@ func ResolveTable(ctx context.Context, vrw types.ValueReadWriter, tbl *doltdb.Ta
 
 	return newTbl, nil
 }
+
+// ResolutionError is returned by ResolveTableWithRows when the rows given don't resolve each of the table's conflicts
+// exactly once. Keys are written as their primary key values separated by commas.
+type ResolutionError struct {
+	Unresolved   []string // The keys of conflicts that no row resolves
+	Duplicated   []string // The keys of conflicts that more than one row resolves
+	NotConflicts []string // The keys of rows that aren't in conflict
+}
+
+func (re *ResolutionError) Error() string {
+	return fmt.Sprintf("%d conflicts not resolved, %d conflicts resolved more than once, %d rows not in conflict",
+		len(re.Unresolved), len(re.Duplicated), len(re.NotConflicts))
+}
+
+// ResolveTableWithRows resolves all of a table's conflicts using the rows given, which must be rows of the table's
+// schema. Rows are matched to conflicts by primary key, and the conflicts matching the rows in removed are resolved by
+// removing the row. If every conflict isn't resolved by exactly one row, or a row isn't in conflict, a
+// *ResolutionError is returned and nothing is changed.
+func ResolveTableWithRows(ctx context.Context, vrw types.ValueReadWriter, tbl *doltdb.Table, rows, removed []row.Row) (*doltdb.Table, error) {
+	if has, err := tbl.HasConflicts(); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, doltdb.ErrNoConflicts
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resolutions := make(map[hash.Hash]types.Value)
+	rowKeys := make(map[hash.Hash]string)
+	duplicated := make(map[hash.Hash]bool)
+	resErr := &ResolutionError{}
+	addResolution := func(r row.Row, val types.Value) error {
+		if has, err := row.IsValid(r, sch); err != nil {
+			return err
+		} else if !has {
+			return table.NewBadRow(r)
+		}
+
+		key, err := r.NomsMapKey(sch).Value(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		h, err := key.Hash(vrw.Format())
+
+		if err != nil {
+			return err
+		}
+
+		if _, ok := resolutions[h]; ok {
+			if !duplicated[h] {
+				resErr.Duplicated = append(resErr.Duplicated, rowKeys[h])
+				duplicated[h] = true
+			}
+			return nil
+		}
+
+		keyStr, err := keyString(ctx, sch, r)
+
+		if err != nil {
+			return err
+		}
+
+		resolutions[h] = val
+		rowKeys[h] = keyStr
+		return nil
+	}
+
+	for _, r := range rows {
+		val, err := r.NomsMapValue(sch).Value(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = addResolution(r, val); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range removed {
+		if err = addResolution(r, types.NullValue); err != nil {
+			return nil, err
+		}
+	}
+
+	_, conflicts, err := tbl.GetConflicts(ctx)
+
+	if err != nil {
+		return nil, err
+	} else if conflicts.Len() == 0 {
+		return nil, doltdb.ErrNoConflicts
+	}
+
+	// rowKeys is left holding the keys of the rows that didn't match a conflict
+	err = conflicts.IterAll(ctx, func(key, _ types.Value) error {
+		h, err := key.Hash(vrw.Format())
+
+		if err != nil {
+			return err
+		}
+
+		if _, ok := resolutions[h]; !ok {
+			r, err := row.FromNoms(sch, key.(types.Tuple), types.EmptyTuple(vrw.Format()))
+
+			if err != nil {
+				return err
+			}
+
+			keyStr, err := keyString(ctx, sch, r)
+
+			if err != nil {
+				return err
+			}
+
+			resErr.Unresolved = append(resErr.Unresolved, keyStr)
+		}
+
+		delete(rowKeys, h)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, keyStr := range rowKeys {
+		resErr.NotConflicts = append(resErr.NotConflicts, keyStr)
+	}
+
+	if len(resErr.Unresolved) > 0 || len(resErr.Duplicated) > 0 || len(resErr.NotConflicts) > 0 {
+		sort.Strings(resErr.NotConflicts)
+		return nil, resErr
+	}
+
+	return ResolveTable(ctx, vrw, tbl, func(key types.Value, _ doltdb.Conflict) (types.Value, error) {
+		h, err := key.Hash(vrw.Format())
+
+		if err != nil {
+			return nil, err
+		}
+
+		return resolutions[h], nil
+	})
+}
+
+// keyString returns the primary key values of the row given, separated by commas.
+func keyString(ctx context.Context, sch schema.Schema, r row.Row) (string, error) {
+	var vals []string
+	err := sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		val, _ := r.GetColVal(tag)
+		str, err := types.EncodedValue(ctx, val)
+
+		if err != nil {
+			return false, err
+		}
+
+		vals = append(vals, str)
+		return false, nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(vals, ","), nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestResolveTableWithRows(t *testing.T) {
+	ctx := context.Background()
+	vrw, commit, mergeCommit, _, _ := setupMergeTest()
+	merger, err := NewMerger(ctx, commit, mergeCommit, vrw)
+	require.NoError(t, err)
+	merged, _, err := merger.MergeTable(ctx, tableName)
+	require.NoError(t, err)
+
+	// the conflicts are on rows 8 and 12
+	newRow := func(i int, name string) row.Row {
+		r, err := row.New(types.Format_7_18, sch, row.TaggedValues{idTag: uuids[i], nameTag: types.String(name)})
+		require.NoError(t, err)
+		return r
+	}
+	resolved := newRow(8, "person 9")
+	removed := newRow(12, "")
+	notConflict := newRow(0, "person 1")
+	noName, err := row.New(types.Format_7_18, sch, row.TaggedValues{idTag: uuids[8]})
+	require.NoError(t, err)
+
+	_, err = ResolveTableWithRows(ctx, vrw, merged, []row.Row{resolved, resolved, notConflict}, nil)
+	require.Error(t, err)
+	assert.Equal(t, &ResolutionError{
+		Unresolved:   []string{"00000000-0000-0000-0000-00000000000c"},
+		Duplicated:   []string{"00000000-0000-0000-0000-000000000008"},
+		NotConflicts: []string{"00000000-0000-0000-0000-000000000000"},
+	}, err)
+
+	_, err = ResolveTableWithRows(ctx, vrw, merged, []row.Row{noName}, []row.Row{removed})
+	assert.True(t, table.IsBadRow(err))
+
+	resTbl, err := ResolveTableWithRows(ctx, vrw, merged, []row.Row{resolved}, []row.Row{removed})
+	require.NoError(t, err)
+
+	numConflicts, err := resTbl.NumRowsInConflict(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), numConflicts)
+
+	rowData, err := resTbl.GetRowData(ctx)
+	require.NoError(t, err)
+	r, ok, err := resTbl.GetRow(ctx, keyTuples[8], sch)
+	require.NoError(t, err)
+	require.True(t, ok)
+	name, _ := r.GetColVal(nameTag)
+	assert.Equal(t, types.String("person 9"), name)
+	has, err := rowData.Has(ctx, keyTuples[12])
+	require.NoError(t, err)
+	assert.False(t, has)
+}
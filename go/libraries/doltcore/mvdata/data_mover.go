@@ -17,6 +17,7 @@ package mvdata
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"sync/atomic"
 
@@ -60,10 +61,12 @@ type MoveOptions struct {
 	SchFile     string
 	TableName   string
 	MappingFile string
-	PrimaryKey  string
-	Src         DataLocation
-	Dest        DataLocation
-	SrcOptions  interface{}
+	// MappingPreset names a mapping stored in the root's mapping presets table, used in place of a mapping file
+	MappingPreset string
+	PrimaryKey    string
+	Src           DataLocation
+	Dest          DataLocation
+	SrcOptions    interface{}
 }
 
 type DataMover struct {
@@ -121,7 +124,7 @@ func NewDataMover(ctx context.Context, root *doltdb.RootValue, fs filesys.Filesy
 		return nil, &DataMoverCreationError{SchemaErr, err}
 	}
 
-	if mvOpts.Operation == ReplaceOp && mvOpts.MappingFile == "" {
+	if mvOpts.Operation == ReplaceOp && mvOpts.MappingFile == "" && mvOpts.MappingPreset == "" {
 		fileMatchesSchema, err := rd.VerifySchema(outSch)
 		if err != nil {
 			return nil, &DataMoverCreationError{ReplacingErr, err}
@@ -135,6 +138,8 @@ func NewDataMover(ctx context.Context, root *doltdb.RootValue, fs filesys.Filesy
 	var mapping *rowconv.FieldMapping
 	if mvOpts.MappingFile != "" {
 		mapping, err = rowconv.MappingFromFile(mvOpts.MappingFile, fs, rd.GetSchema(), outSch)
+	} else if mvOpts.MappingPreset != "" {
+		mapping, err = mappingFromPreset(ctx, root, mvOpts.MappingPreset, rd.GetSchema(), outSch)
 	} else if mapByTag(mvOpts.Src, mvOpts.Dest) {
 		mapping, err = rowconv.TagMapping(rd.GetSchema(), outSch)
 	} else {
@@ -208,6 +213,19 @@ func (imp *DataMover) Move(ctx context.Context) (badRowCount int64, err error) {
 	return badCount, nil
 }
 
+// mappingFromPreset reads a FieldMapping from the mapping preset stored in the root with the name given.
+func mappingFromPreset(ctx context.Context, root *doltdb.RootValue, name string, inSch, outSch schema.Schema) (*rowconv.FieldMapping, error) {
+	data, ok, err := root.GetMappingPreset(ctx, name)
+
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("mapping preset '%s' not found", name)
+	}
+
+	return rowconv.MappingFromJSON([]byte(data), inSch, outSch)
+}
+
 func maybeMapFields(transforms *pipeline.TransformCollection, mapping *rowconv.FieldMapping) error {
 	rconv, err := rowconv.NewRowConverter(mapping)
 
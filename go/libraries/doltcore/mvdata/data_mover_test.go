@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
@@ -161,3 +162,43 @@ func TestDataMover(t *testing.T) {
 		}
 	}
 }
+
+func TestDataMoverMappingPreset(t *testing.T) {
+	ctx := context.Background()
+	_, root, fs := createRootAndFS()
+
+	schJSON := `{"columns": [
+		{"name": "key", "kind": "string", "tag": 0, "is_part_of_pk": true, "col_constraints":[{"constraint_type": "not_null"}]},
+		{"name": "value", "kind": "int", "tag": 1}
+	]}`
+	err := fs.WriteFile(schemaFile, []byte(schJSON))
+	require.NoError(t, err)
+
+	mvOpts := &MoveOptions{
+		Operation:     OverwriteOp,
+		SchFile:       schemaFile,
+		MappingPreset: "vendor_a",
+		Src:           NewDataLocation("data.csv", ""),
+		Dest:          NewDataLocation("table-name", ""),
+	}
+
+	seedWr, err := mvOpts.Src.NewCreatingWriter(ctx, mvOpts, root, fs, true, fakeSchema, nil)
+	require.NoError(t, err)
+	_, _, err = table.PipeRows(ctx, table.NewInMemTableReader(imt), seedWr, false)
+	require.NoError(t, err)
+	require.NoError(t, seedWr.Close(ctx))
+
+	_, dmErr := NewDataMover(ctx, root, fs, mvOpts, nil)
+	require.NotNil(t, dmErr)
+	assert.Equal(t, MappingErr, dmErr.ErrType)
+
+	root, err = root.PutMappingPreset(ctx, "vendor_a", `{"a":"key","b":"value"}`)
+	require.NoError(t, err)
+
+	dm, dmErr := NewDataMover(ctx, root, fs, mvOpts, nil)
+	require.Nil(t, dmErr)
+
+	badCount, err := dm.Move(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), badCount)
+}
@@ -0,0 +1,433 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/rowconv"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/liquidata-inc/dolt/go/libraries/utils/filesys"
+)
+
+// MultiFileDataLocation is a set of files of the same format that are imported together as if they were a single
+// file.  The files may list their fields in any order, but they must all have the same fields.
+type MultiFileDataLocation struct {
+	// Paths are the paths of the files.  Each of them may be a glob pattern matching any number of files.
+	Paths []string
+
+	// Format is the DataFormat of the files
+	Format DataFormat
+
+	// Parallel is true if the files should be read concurrently, in which case the rows of the different files are
+	// read in no particular order.
+	Parallel bool
+}
+
+// String returns a string representation of the data location.
+func (dl MultiFileDataLocation) String() string {
+	return dl.Format.ReadableStr() + ":" + strings.Join(dl.Paths, ",")
+}
+
+// Exists returns true if the DataLocation already exists
+func (dl MultiFileDataLocation) Exists(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS) (bool, error) {
+	paths, err := dl.expandPaths(fs)
+
+	if err != nil {
+		return false, nil
+	}
+
+	for _, path := range paths {
+		if exists, _ := fs.Exists(path); !exists {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// NewReader creates a TableReadCloser for the DataLocation
+func (dl MultiFileDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS, schPath string, opts interface{}) (rdCl table.TableReadCloser, sorted bool, err error) {
+	paths, err := dl.expandPaths(fs)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	open := func(path string) (table.TableReadCloser, error) {
+		rd, _, err := FileDataLocation{Path: path, Format: dl.Format}.NewReader(ctx, root, fs, schPath, opts)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+
+		return rd, nil
+	}
+
+	rd, err := newMultiFileReader(ctx, paths, open, dl.Parallel)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return rd, false, nil
+}
+
+// expandPaths returns the paths of all the files of the data location, with each glob pattern replaced by the files
+// it matches.  It is an error for a pattern not to match any file.
+func (dl MultiFileDataLocation) expandPaths(fs filesys.ReadableFS) ([]string, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, pattern := range dl.Paths {
+		matches := []string{pattern}
+
+		if filesys.HasGlobMeta(pattern) {
+			walkable, ok := fs.(filesys.WalkableFS)
+
+			if !ok {
+				return nil, errors.New("glob patterns are not supported by this filesystem")
+			}
+
+			var err error
+			matches, err = filesys.Glob(walkable, pattern)
+
+			if err != nil {
+				return nil, err
+			} else if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match '%s'", pattern)
+			}
+		}
+
+		for _, path := range matches {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// NewCreatingWriter will create a TableWriteCloser for a DataLocation that will create a new table, or overwrite
+// an existing table.
+func (dl MultiFileDataLocation) NewCreatingWriter(ctx context.Context, mvOpts *MoveOptions, root *doltdb.RootValue, fs filesys.WritableFS, sortedInput bool, outSch schema.Schema, statsCB noms.StatsCB) (table.TableWriteCloser, error) {
+	panic("Writing to multiple files is not supported")
+}
+
+// NewUpdatingWriter will create a TableWriteCloser for a DataLocation that will update and append rows based on
+// their primary key.
+func (dl MultiFileDataLocation) NewUpdatingWriter(ctx context.Context, mvOpts *MoveOptions, root *doltdb.RootValue, fs filesys.WritableFS, srcIsSorted bool, outSch schema.Schema, statsCB noms.StatsCB) (table.TableWriteCloser, error) {
+	panic("Updating of files is not supported")
+}
+
+// NewReplacingWriter will create a TableWriteCloser for a DataLocation that will overwrite an existing table while
+// preserving schema
+func (dl MultiFileDataLocation) NewReplacingWriter(ctx context.Context, mvOpts *MoveOptions, root *doltdb.RootValue, fs filesys.WritableFS, srcIsSorted bool, outSch schema.Schema, statsCB noms.StatsCB) (table.TableWriteCloser, error) {
+	panic("Replacing files is not supported")
+}
+
+// multiFileReader is a TableReadCloser reading the rows of a number of files, one after another or concurrently.
+// Rows are returned in the schema of the first file.  Files are only opened once the files before them have been read,
+// or, when reading in parallel, once a reader is free to read them.
+type multiFileReader struct {
+	sch   schema.Schema
+	paths []string
+	open  func(path string) (table.TableReadCloser, error)
+
+	// used when reading sequentially
+	curr *fileRowReader
+	next int
+
+	// used when reading in parallel
+	results  chan readResult
+	done     chan struct{}
+	wg       *sync.WaitGroup
+	stopOnce *sync.Once
+}
+
+type readResult struct {
+	r   row.Row
+	err error
+}
+
+func newMultiFileReader(ctx context.Context, paths []string, open func(path string) (table.TableReadCloser, error), parallel bool) (*multiFileReader, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no files to read")
+	}
+
+	first, err := open(paths[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	mfr := &multiFileReader{
+		sch:   first.GetSchema(),
+		paths: paths,
+		open:  open,
+		curr:  &fileRowReader{rd: first},
+		next:  1,
+	}
+
+	if parallel {
+		mfr.startParallelReads(ctx)
+	}
+
+	return mfr, nil
+}
+
+// openFile opens the file at the path given, and sets up the conversion of its rows to the schema of the first file.
+func (mfr *multiFileReader) openFile(path string) (*fileRowReader, error) {
+	rd, err := mfr.open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fileSch := rd.GetSchema()
+	if !sameFieldNames(fileSch, mfr.sch) {
+		_ = rd.Close(context.Background())
+		return nil, fmt.Errorf("the fields of %s (%s) don't match the fields of %s (%s)", path,
+			strings.Join(fileSch.GetAllCols().GetColumnNames(), ", "), mfr.paths[0],
+			strings.Join(mfr.sch.GetAllCols().GetColumnNames(), ", "))
+	}
+
+	mapping, err := rowconv.NameMapping(fileSch, mfr.sch)
+
+	if err == nil {
+		var conv *rowconv.RowConverter
+		conv, err = rowconv.NewRowConverter(mapping)
+
+		if err == nil {
+			return &fileRowReader{rd: rd, conv: conv}, nil
+		}
+	}
+
+	_ = rd.Close(context.Background())
+	return nil, err
+}
+
+func sameFieldNames(sch1, sch2 schema.Schema) bool {
+	cols1 := sch1.GetAllCols()
+	cols2 := sch2.GetAllCols()
+
+	if cols1.Size() != cols2.Size() {
+		return false
+	}
+
+	for _, name := range cols1.GetColumnNames() {
+		if _, ok := cols2.GetByName(name); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (mfr *multiFileReader) GetSchema() schema.Schema {
+	return mfr.sch
+}
+
+// ReadRow reads a row from a table.  If there is a bad row the returned error will be non nil, and calling
+// IsBadRow(err) will be return true. This is a potentially non-fatal error and callers can decide if they want to
+// continue on a bad row, or fail.
+func (mfr *multiFileReader) ReadRow(ctx context.Context) (row.Row, error) {
+	if mfr.results != nil {
+		res, ok := <-mfr.results
+
+		if !ok {
+			return nil, io.EOF
+		}
+
+		return res.r, res.err
+	}
+
+	for {
+		if mfr.curr == nil {
+			if mfr.next == len(mfr.paths) {
+				return nil, io.EOF
+			}
+
+			var err error
+			mfr.curr, err = mfr.openFile(mfr.paths[mfr.next])
+			mfr.next++
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		r, err := mfr.curr.readRow(ctx)
+
+		if err == io.EOF {
+			err = mfr.curr.rd.Close(ctx)
+			mfr.curr = nil
+
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		return r, err
+	}
+}
+
+// startParallelReads starts the goroutines reading the files concurrently, which send the rows they read to the
+// results channel.  The number of files read at once is limited by the number of CPUs.
+func (mfr *multiFileReader) startParallelReads(ctx context.Context) {
+	mfr.results = make(chan readResult, 1024)
+	mfr.done = make(chan struct{})
+	mfr.wg = &sync.WaitGroup{}
+	mfr.stopOnce = &sync.Once{}
+
+	files := make(chan func() (*fileRowReader, error), len(mfr.paths))
+
+	first := mfr.curr
+	files <- func() (*fileRowReader, error) { return first, nil }
+	for _, path := range mfr.paths[1:] {
+		path := path
+		files <- func() (*fileRowReader, error) { return mfr.openFile(path) }
+	}
+
+	close(files)
+	mfr.curr = nil
+
+	send := func(res readResult) bool {
+		select {
+		case mfr.results <- res:
+			return true
+		case <-mfr.done:
+			return false
+		}
+	}
+
+	numReaders := runtime.NumCPU()
+	if numReaders > len(mfr.paths) {
+		numReaders = len(mfr.paths)
+	}
+
+	for i := 0; i < numReaders; i++ {
+		mfr.wg.Add(1)
+		go func() {
+			defer mfr.wg.Done()
+
+			for openFile := range files {
+				select {
+				case <-mfr.done:
+					return
+				default:
+				}
+
+				frr, err := openFile()
+
+				if err != nil {
+					send(readResult{err: err})
+					return
+				}
+
+				ok := readAll(ctx, frr, send)
+				err = frr.rd.Close(ctx)
+
+				if !ok {
+					return
+				} else if err != nil {
+					send(readResult{err: err})
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		mfr.wg.Wait()
+		close(mfr.results)
+	}()
+}
+
+// readAll sends all the rows of the file given, returning false if the reads should stop.
+func readAll(ctx context.Context, frr *fileRowReader, send func(readResult) bool) bool {
+	for {
+		r, err := frr.readRow(ctx)
+
+		if err == io.EOF {
+			return true
+		}
+
+		if !send(readResult{r, err}) {
+			return false
+		} else if err != nil && !table.IsBadRow(err) {
+			return false
+		}
+	}
+}
+
+// VerifySchema checks that the incoming schema matches the schema from the existing table
+func (mfr *multiFileReader) VerifySchema(outSch schema.Schema) (bool, error) {
+	return schema.VerifyInSchema(mfr.sch, outSch)
+}
+
+// Close should release resources being held
+func (mfr *multiFileReader) Close(ctx context.Context) error {
+	if mfr.done != nil {
+		mfr.stopOnce.Do(func() { close(mfr.done) })
+		mfr.wg.Wait()
+		return nil
+	}
+
+	if mfr.curr != nil {
+		err := mfr.curr.rd.Close(ctx)
+		mfr.curr = nil
+		return err
+	}
+
+	return nil
+}
+
+// fileRowReader reads the rows of one of the files of a multiFileReader, converting them to the schema of the first
+// file if needed.
+type fileRowReader struct {
+	rd   table.TableReadCloser
+	conv *rowconv.RowConverter
+}
+
+func (frr *fileRowReader) readRow(ctx context.Context) (row.Row, error) {
+	r, err := frr.rd.ReadRow(ctx)
+
+	if err != nil || frr.conv == nil {
+		return r, err
+	}
+
+	converted, err := frr.conv.Convert(r)
+
+	if err != nil {
+		return nil, table.NewBadRow(r, err.Error())
+	}
+
+	return converted, nil
+}
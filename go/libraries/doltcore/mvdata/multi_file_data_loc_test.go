@@ -0,0 +1,135 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/table"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestMultiFileDataLocation(t *testing.T) {
+	files := map[string]string{
+		"part-1.csv": "id,name\n1,one\n2,two\n",
+		"part-2.csv": "name,id\nthree,3\n",
+		"part-3.csv": "id,name\n4,four\n5,five\n6,six\n",
+		"other.csv":  "id,name\n7,seven\n",
+		"bad.csv":    "id,title\n8,eight\n",
+	}
+
+	tests := []struct {
+		name        string
+		paths       []string
+		expectedIDs []string
+		expectedErr string
+	}{
+		{
+			name:        "glob",
+			paths:       []string{"part-*.csv"},
+			expectedIDs: []string{"1", "2", "3", "4", "5", "6"},
+		},
+		{
+			name:        "paths and globs",
+			paths:       []string{"other.csv", "part-[12].csv", "part-1.csv"},
+			expectedIDs: []string{"1", "2", "3", "7"},
+		},
+		{
+			name:        "no matches",
+			paths:       []string{"other.csv", "nothing-*.csv"},
+			expectedErr: "no files match 'nothing-*.csv'",
+		},
+		{
+			name:        "mismatched fields",
+			paths:       []string{"part-1.csv", "bad.csv"},
+			expectedErr: "the fields of bad.csv (id, title) don't match the fields of part-1.csv (id, name)",
+		},
+	}
+
+	for _, parallel := range []bool{false, true} {
+		for _, test := range tests {
+			name := test.name
+			if parallel {
+				name += " parallel"
+			}
+
+			t.Run(name, func(t *testing.T) {
+				ctx := context.Background()
+				_, root, fs := createRootAndFS()
+				for name, data := range files {
+					require.NoError(t, fs.WriteFile(name, []byte(data)))
+				}
+
+				dl := MultiFileDataLocation{Paths: test.paths, Format: CsvFile, Parallel: parallel}
+				rd, _, err := dl.NewReader(ctx, root, fs, "", nil)
+
+				if err == nil {
+					var ids []string
+					ids, err = readIDs(ctx, rd)
+					assert.NoError(t, rd.Close(ctx))
+
+					if err == nil {
+						sort.Strings(ids)
+						assert.Equal(t, test.expectedIDs, ids)
+					}
+				}
+
+				if test.expectedErr == "" {
+					assert.NoError(t, err)
+				} else {
+					require.Error(t, err)
+					assert.Equal(t, test.expectedErr, err.Error())
+				}
+			})
+		}
+	}
+}
+
+var namesByID = map[string]string{
+	"1": "one", "2": "two", "3": "three", "4": "four", "5": "five", "6": "six", "7": "seven",
+}
+
+func readIDs(ctx context.Context, rd table.TableReadCloser) ([]string, error) {
+	idCol, _ := rd.GetSchema().GetAllCols().GetByName("id")
+	nameCol, _ := rd.GetSchema().GetAllCols().GetByName("name")
+
+	var ids []string
+	for {
+		r, err := rd.ReadRow(ctx)
+
+		if err == io.EOF {
+			return ids, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		id, _ := r.GetColVal(idCol.Tag)
+		name, _ := r.GetColVal(nameCol.Tag)
+
+		// fields listed in a different order than in the first file must still end up in the right columns
+		if expected := namesByID[string(id.(types.String))]; string(name.(types.String)) != expected {
+			return nil, fmt.Errorf("expected name %s for id %s, got %s", expected, id, name)
+		}
+
+		ids = append(ids, string(id.(types.String)))
+	}
+}
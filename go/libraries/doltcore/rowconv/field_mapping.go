@@ -197,8 +197,13 @@ func MappingFromFile(mappingFile string, fs filesys.ReadableFS, inSch, outSch sc
 		return nil, ErrMappingFileRead
 	}
 
+	return MappingFromJSON(data, inSch, outSch)
+}
+
+// MappingFromJSON reads a FieldMapping from the contents of a json mapping file
+func MappingFromJSON(data []byte, inSch, outSch schema.Schema) (*FieldMapping, error) {
 	var inNameToOutName map[string]string
-	err = json.Unmarshal(data, &inNameToOutName)
+	err := json.Unmarshal(data, &inNameToOutName)
 
 	if err != nil {
 		return nil, ErrUnmarshallingMapping
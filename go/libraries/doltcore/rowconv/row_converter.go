@@ -56,7 +56,7 @@ func NewRowConverter(mapping *FieldMapping) (*RowConverter, error) {
 			return nil, fmt.Errorf("Could not find column being mapped. src tag: %d, dest tag: %d", srcTag, destTag)
 		}
 
-		convFunc, err := doltcore.GetConvFunc(srcCol.Kind, destCol.Kind)
+		convFunc, err := getColConvFunc(srcCol, destCol)
 
 		if err != nil {
 			return nil, fmt.Errorf("Unsupported conversion from type %s to %s", srcCol.KindString(), destCol.KindString())
@@ -100,6 +100,67 @@ func (rc *RowConverter) Convert(inRow row.Row) (row.Row, error) {
 	return row.New(inRow.Format(), rc.DestSch, outTaggedVals)
 }
 
+// getColConvFunc returns a function that converts values of the source column given to values of the destination
+// column given. Values converted to or from a custom type are converted through their string representation using the
+// functions the type is registered with.
+func getColConvFunc(srcCol, destCol schema.Column) (types.MarshalCallback, error) {
+	srcType, srcIsCustom := srcCol.CustomType()
+	destType, destIsCustom := destCol.CustomType()
+
+	if (!srcIsCustom && !destIsCustom) || srcCol.TypeName == destCol.TypeName {
+		return doltcore.GetConvFunc(srcCol.Kind, destCol.Kind)
+	}
+
+	toStr := func(val types.Value) (types.Value, error) {
+		return val, nil
+	}
+
+	if srcIsCustom {
+		toStr = func(val types.Value) (types.Value, error) {
+			str, err := srcType.Format(val)
+			return types.String(str), err
+		}
+	} else if srcCol.Kind != types.StringKind {
+		var err error
+		toStr, err = doltcore.GetConvFunc(srcCol.Kind, types.StringKind)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fromStr := func(val types.Value) (types.Value, error) {
+		return val, nil
+	}
+
+	if destIsCustom {
+		fromStr = func(val types.Value) (types.Value, error) {
+			return destType.Parse(string(val.(types.String)))
+		}
+	} else if destCol.Kind != types.StringKind {
+		var err error
+		fromStr, err = doltcore.GetConvFunc(types.StringKind, destCol.Kind)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(val types.Value) (types.Value, error) {
+		if types.IsNull(val) {
+			return val, nil
+		}
+
+		str, err := toStr(val)
+
+		if err != nil || types.IsNull(str) {
+			return str, err
+		}
+
+		return fromStr(str)
+	}, nil
+}
+
 func isNecessary(srcSch, destSch schema.Schema, destToSrc map[uint64]uint64) (bool, error) {
 	srcCols := srcSch.GetAllCols()
 	destCols := destSch.GetAllCols()
@@ -124,7 +185,7 @@ func isNecessary(srcSch, destSch schema.Schema, destToSrc map[uint64]uint64) (bo
 			return true, nil
 		}
 
-		if srcCol.Kind != destCol.Kind {
+		if srcCol.Kind != destCol.Kind || srcCol.TypeName != destCol.TypeName {
 			return true, nil
 		}
 	}
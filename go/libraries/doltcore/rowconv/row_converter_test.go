@@ -16,11 +16,14 @@ package rowconv
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
@@ -101,3 +104,64 @@ func TestUnneccessaryConversion(t *testing.T) {
 		t.Error("expected identity converter")
 	}
 }
+
+// ipv4Type is a custom type of IPv4 addresses, stored as unsigned integers
+var ipv4Type = schema.CustomType{
+	Name: "ipv4",
+	Kind: types.UintKind,
+	Parse: func(str string) (types.Value, error) {
+		ip := net.ParseIP(str).To4()
+		if ip == nil {
+			return nil, fmt.Errorf("'%s' is not an IPv4 address", str)
+		}
+		return types.Uint(uint64(ip[0])<<24 | uint64(ip[1])<<16 | uint64(ip[2])<<8 | uint64(ip[3])), nil
+	},
+	Format: func(val types.Value) (string, error) {
+		n := uint64(val.(types.Uint))
+		return net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n)).String(), nil
+	},
+}
+
+func TestCustomTypeConversion(t *testing.T) {
+	require.NoError(t, schema.RegisterCustomType(ipv4Type))
+
+	ipCol := schema.NewColumn("ip", 0, types.UintKind, true)
+	ipCol.TypeName = ipv4Type.Name
+	typedCols, _ := schema.NewColCollection(ipCol, schema.NewColumn("n", 1, types.UintKind, false))
+	typedSch := schema.SchemaFromCols(typedCols)
+
+	// values of a custom type are formatted when untyped, and parsed when typed again
+	toUntyped, err := TypedToUntypedMapping(typedSch)
+	require.NoError(t, err)
+	rConv, err := NewRowConverter(toUntyped)
+	require.NoError(t, err)
+	assert.False(t, rConv.IdentityConverter)
+
+	typedRow, err := row.New(types.Format_7_18, typedSch, row.TaggedValues{0: types.Uint(0x0a000102), 1: types.Uint(7)})
+	require.NoError(t, err)
+	untypedRow, err := rConv.Convert(typedRow)
+	require.NoError(t, err)
+	ip, _ := untypedRow.GetColVal(0)
+	n, _ := untypedRow.GetColVal(1)
+	assert.Equal(t, types.String("10.0.1.2"), ip)
+	assert.Equal(t, types.String("7"), n)
+
+	rConv, err = NewRowConverter(InvertMapping(toUntyped))
+	require.NoError(t, err)
+	converted, err := rConv.Convert(untypedRow)
+	require.NoError(t, err)
+	assert.True(t, row.AreEqual(typedRow, converted, typedSch))
+
+	badRow, err := row.New(types.Format_7_18, toUntyped.DestSch, row.TaggedValues{0: types.String("10.0.1"), 1: types.String("7")})
+	require.NoError(t, err)
+	_, err = rConv.Convert(badRow)
+	assert.Error(t, err)
+
+	// values of the kind a custom type is stored as are converted through their string representation
+	uintCols, _ := schema.NewColCollection(schema.NewColumn("ip", 0, types.UintKind, true))
+	mapping, err := TagMapping(schema.SchemaFromCols(uintCols), typedSch)
+	require.NoError(t, err)
+	rConv, err = NewRowConverter(mapping)
+	require.NoError(t, err)
+	assert.False(t, rConv.IdentityConverter)
+}
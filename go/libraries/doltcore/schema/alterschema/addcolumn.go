@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
 	"github.com/liquidata-inc/dolt/go/store/types"
@@ -53,12 +54,12 @@ func AddColumnToTable(ctx context.Context, db *doltdb.DoltDB, tbl *doltdb.Table,
 		return nil, err
 	}
 
-	return updateTableWithNewSchema(ctx, db, tbl, tag, newSchema, defaultVal)
+	return updateTableWithNewSchema(ctx, db, tbl, tag, sch, newSchema, defaultVal)
 }
 
 // updateTableWithNewSchema updates the existing table with a new schema and new values for the new column as necessary,
 // and returns the new table.
-func updateTableWithNewSchema(ctx context.Context, db *doltdb.DoltDB, tbl *doltdb.Table, tag uint64, newSchema schema.Schema, defaultVal types.Value) (*doltdb.Table, error) {
+func updateTableWithNewSchema(ctx context.Context, db *doltdb.DoltDB, tbl *doltdb.Table, tag uint64, oldSchema, newSchema schema.Schema, defaultVal types.Value) (*doltdb.Table, error) {
 	vrw := db.ValueReadWriter()
 	newSchemaVal, err := encoding.MarshalAsNomsValue(ctx, vrw, newSchema)
 	if err != nil {
@@ -72,7 +73,22 @@ func updateTableWithNewSchema(ctx context.Context, db *doltdb.DoltDB, tbl *doltd
 	}
 
 	if defaultVal == nil {
-		return doltdb.NewTable(ctx, vrw, newSchemaVal, rowData)
+		// Rows only need to be rewritten if a column with the same tag was dropped, and they still have its values
+		reused, err := tbl.HasDroppedTag(ctx, tag)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if reused {
+			rowData, err = clearColumnValues(ctx, rowData, tag, oldSchema)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return newTableFrom(ctx, vrw, tbl, newSchemaVal, rowData, tag)
 	}
 
 	me := rowData.Edit()
@@ -102,7 +118,75 @@ func updateTableWithNewSchema(ctx context.Context, db *doltdb.DoltDB, tbl *doltd
 		return nil, err
 	}
 
-	return doltdb.NewTable(ctx, vrw, newSchemaVal, m)
+	return newTableFrom(ctx, vrw, tbl, newSchemaVal, m, tag)
+}
+
+// newTableFrom returns a new table with the schema and rows given, which keeps the indexes and dropped tags of the table
+// given and is append-only if it is. The tags of columns being added are no longer recorded as dropped.
+func newTableFrom(ctx context.Context, vrw types.ValueReadWriter, tbl *doltdb.Table, schemaVal types.Value, rowData types.Map, addedTags ...uint64) (*doltdb.Table, error) {
+	newTable, err := doltdb.NewTable(ctx, vrw, schemaVal, rowData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newTable, err = newTable.CopyIndexes(ctx, tbl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	newTable, err = newTable.CopyDroppedTags(ctx, tbl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tag := range addedTags {
+		newTable, err = newTable.SetDroppedTag(ctx, tag, false)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newTable.CopyAppendOnly(tbl)
+}
+
+// clearColumnValues removes any values for the tag given from the rows given. Dropping a column doesn't rewrite the
+// table's rows, so they can still have values for a dropped column, which must not reappear when a column with the
+// same tag is added. Only the rows with such a value are rewritten, but every row is read, so it's only called when the
+// table records that a column with the tag was dropped.
+func clearColumnValues(ctx context.Context, rowData types.Map, tag uint64, sch schema.Schema) (types.Map, error) {
+	var me *types.MapEditor
+	err := rowData.Iter(ctx, func(k, v types.Value) (stop bool, err error) {
+		vals, err := row.ParseTaggedValues(v.(types.Tuple))
+
+		if err != nil {
+			return true, err
+		}
+
+		if _, ok := vals[tag]; !ok {
+			return false, nil
+		}
+
+		if me == nil {
+			me = rowData.Edit()
+		}
+
+		me.Set(k, vals.NomsTupleForTags(rowData.Format(), sch.GetNonPKCols().SortedTags, false))
+		return false, nil
+	})
+
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	if me == nil {
+		return rowData, nil
+	}
+
+	return me.Map(ctx)
 }
 
 // createNewSchema Creates a new schema with a column as specified by the params.
@@ -119,7 +203,7 @@ func createNewSchema(sch schema.Schema, tag uint64, newColName string, colKind t
 		return nil, err
 	}
 
-	return schema.SchemaFromCols(updatedCols), nil
+	return schema.SchemaWithComment(schema.SchemaFromCols(updatedCols), sch.GetComment()), nil
 }
 
 // validateNewColumn returns an error if the column as specified cannot be added to the schema given.
@@ -276,6 +276,27 @@ func TestAddColumnToTable(t *testing.T) {
 	}
 }
 
+func TestAddColumnLeavesRowsUntouched(t *testing.T) {
+	dEnv := createEnvWithSeedData(t)
+	ctx := context.Background()
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	tbl, _, err := root.GetTable(ctx, tableName)
+	require.NoError(t, err)
+
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+
+	// no column with the tag was ever dropped, so adding a column without a default doesn't need to rewrite any rows
+	updated, err := AddColumnToTable(ctx, dEnv.DoltDB, tbl, 1234, "newCol", types.StringKind, Null, nil)
+	require.NoError(t, err)
+
+	updatedRowData, err := updated.GetRowData(ctx)
+	require.NoError(t, err)
+	assert.True(t, rowData.Equals(updatedRowData))
+}
+
 func createEnvWithSeedData(t *testing.T) *env.DoltEnv {
 	dEnv := dtestutils.CreateTestEnv()
 	imt, sch := dtestutils.CreateTestDataTable(true)
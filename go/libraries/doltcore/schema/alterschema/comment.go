@@ -0,0 +1,108 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alterschema
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+)
+
+// SetTableComment sets the comment documenting a table, replacing any comment it had. An empty comment removes the
+// table's comment. Only the schema of the table is rewritten.
+func SetTableComment(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, comment string) (*doltdb.Table, error) {
+	if tbl == nil || doltDB == nil {
+		panic("invalid parameters")
+	}
+
+	tblSch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updateSchema(ctx, doltDB, tbl, schema.SchemaWithComment(tblSch, comment))
+}
+
+// SetColumnComment sets the comment documenting the column named, replacing any comment it had. An empty comment
+// removes the column's comment. Only the schema of the table is rewritten.
+func SetColumnComment(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, colName, comment string) (*doltdb.Table, error) {
+	if tbl == nil || doltDB == nil {
+		panic("invalid parameters")
+	}
+
+	return updateColumn(ctx, doltDB, tbl, colName, func(col *schema.Column) error {
+		col.Comment = comment
+		return nil
+	})
+}
+
+// updateColumn returns a new table with the rows of the table given, and its schema with the column named updated by
+// the function given. Returns schema.ErrColNotFound if the table has no such column.
+func updateColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, colName string, update func(col *schema.Column) error) (*doltdb.Table, error) {
+	tblSch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allCols := tblSch.GetAllCols()
+
+	if _, ok := allCols.GetByName(colName); !ok {
+		return nil, schema.ErrColNotFound
+	}
+
+	cols := make([]schema.Column, 0)
+	err = allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if col.Name == colName {
+			if err := update(&col); err != nil {
+				return true, err
+			}
+		}
+		cols = append(cols, col)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	colColl, err := schema.NewColCollection(cols...)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateSchema(ctx, doltDB, tbl, schema.SchemaWithComment(schema.SchemaFromCols(colColl), tblSch.GetComment()))
+}
+
+// updateSchema returns a new table with the schema given and the rows of the table given.
+func updateSchema(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, newSch schema.Schema) (*doltdb.Table, error) {
+	vrw := doltDB.ValueReadWriter()
+	schemaVal, err := encoding.MarshalAsNomsValue(ctx, vrw, newSch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newTableFrom(ctx, vrw, tbl, schemaVal, rowData)
+}
@@ -23,7 +23,9 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
 )
 
-// DropColumn drops a column from a table. No existing rows are modified, but a new schema entry is written.
+// DropColumn drops a column from a table. No existing rows are modified, but a new schema entry is written. The dropped
+// column's values stay in the rows, where they're ignored when reading rows with the new schema. The table records the
+// dropped column's tag, so that the values are cleared if a column with the same tag is added to the table again.
 func DropColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, colName string) (*doltdb.Table, error) {
 	if tbl == nil || doltDB == nil {
 		panic("invalid parameters")
@@ -37,9 +39,11 @@ func DropColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, c
 
 	allCols := tblSch.GetAllCols()
 
-	if col, ok := allCols.GetByName(colName); !ok {
+	droppedCol, ok := allCols.GetByName(colName)
+
+	if !ok {
 		return nil, schema.ErrColNotFound
-	} else if col.IsPartOfPK {
+	} else if droppedCol.IsPartOfPK {
 		return nil, errors.New("Cannot drop column in primary key")
 	}
 
@@ -60,7 +64,7 @@ func DropColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, c
 		return nil, err
 	}
 
-	newSch := schema.SchemaFromCols(colColl)
+	newSch := schema.SchemaWithComment(schema.SchemaFromCols(colColl), tblSch.GetComment())
 
 	vrw := doltDB.ValueReadWriter()
 	schemaVal, err := encoding.MarshalAsNomsValue(ctx, vrw, newSch)
@@ -75,11 +79,11 @@ func DropColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, c
 		return nil, err
 	}
 
-	newTable, err := doltdb.NewTable(ctx, vrw, schemaVal, rd)
+	newTbl, err := newTableFrom(ctx, vrw, tbl, schemaVal, rd)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return newTable, nil
+	return newTbl.SetDroppedTag(ctx, droppedCol.Tag, true)
 }
@@ -98,3 +98,43 @@ func TestDropColumn(t *testing.T) {
 		})
 	}
 }
+
+func TestDropColumnThenAddColumn(t *testing.T) {
+	dEnv := createEnvWithSeedData(t)
+	ctx := context.Background()
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	tbl, _, err := root.GetTable(ctx, tableName)
+	require.NoError(t, err)
+
+	tbl, err = DropColumn(ctx, dEnv.DoltDB, tbl, "age")
+	require.NoError(t, err)
+
+	dropped, err := tbl.HasDroppedTag(ctx, dtestutils.AgeTag)
+	require.NoError(t, err)
+	assert.True(t, dropped)
+
+	// the dropped column's values must not come back when a column with the same tag is added
+	tbl, err = AddColumnToTable(ctx, dEnv.DoltDB, tbl, dtestutils.AgeTag, "age", types.UintKind, Null, nil)
+	require.NoError(t, err)
+
+	dropped, err = tbl.HasDroppedTag(ctx, dtestutils.AgeTag)
+	require.NoError(t, err)
+	assert.False(t, dropped)
+
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+
+	expectedRows := dtestutils.ConvertToSchema(dtestutils.RemoveColumnFromSchema(dtestutils.TypedSchema, dtestutils.AgeTag), dtestutils.TypedRows...)
+
+	var foundRows []row.Row
+	err = rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+		r, err := row.FromNoms(dtestutils.TypedSchema, key.(types.Tuple), value.(types.Tuple))
+		foundRows = append(foundRows, r)
+		return false, err
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRows, foundRows)
+}
@@ -0,0 +1,39 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alterschema
+
+import (
+	"context"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+)
+
+// SetColumnMergeStrategy sets the strategy used to merge the changes made to the values of the column named on two
+// branches. Returns an error if the column can't have the strategy given. Only the schema of the table is rewritten.
+func SetColumnMergeStrategy(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, colName string, strategy schema.MergeStrategy) (*doltdb.Table, error) {
+	if tbl == nil || doltDB == nil {
+		panic("invalid parameters")
+	}
+
+	return updateColumn(ctx, doltDB, tbl, colName, func(col *schema.Column) error {
+		if err := col.ValidateMergeStrategy(strategy); err != nil {
+			return err
+		}
+
+		col.MergeStrategy = strategy
+		return nil
+	})
+}
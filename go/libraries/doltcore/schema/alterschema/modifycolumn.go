@@ -0,0 +1,218 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alterschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// maxReportedRows is the number of rows that can't be converted which are listed in the error returned by
+// ModifyColumn.
+const maxReportedRows = 10
+
+// ModifyColumn replaces the column named in a table with the column given, which may have a different name, kind and
+// constraints. The column keeps its tag, so its history and diffs stay intact. If the kind changes, the column's value
+// in each row is converted to the new kind; if the column becomes NOT NULL, every row must have a value for it. All the
+// rows are checked before any are changed, and if any can't be converted the error returned lists their primary keys.
+//
+// Primary key columns can't be modified.
+func ModifyColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table, colName string, newCol schema.Column) (*doltdb.Table, error) {
+	if tbl == nil || doltDB == nil {
+		panic("invalid parameters")
+	}
+
+	tblSch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allCols := tblSch.GetAllCols()
+	oldCol, ok := allCols.GetByName(colName)
+
+	if !ok {
+		return nil, schema.ErrColNotFound
+	} else if oldCol.IsPartOfPK {
+		return nil, errors.New("Cannot modify column in primary key")
+	} else if newCol.IsPartOfPK {
+		return nil, errors.New("Cannot add column to primary key")
+	}
+
+	newCol.Tag = oldCol.Tag
+
+	// Merge strategies aren't part of column definitions, so the column keeps its strategy if it still can
+	if newCol.MergeStrategy == schema.MergeDefault && newCol.ValidateMergeStrategy(oldCol.MergeStrategy) == nil {
+		newCol.MergeStrategy = oldCol.MergeStrategy
+	}
+
+	if existing, ok := allCols.GetByNameCaseInsensitive(newCol.Name); ok && existing.Tag != oldCol.Tag {
+		return nil, schema.ErrColNameCollision
+	}
+
+	cols := make([]schema.Column, 0)
+	err = allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if tag == oldCol.Tag {
+			col = newCol
+		}
+		cols = append(cols, col)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	colColl, err := schema.NewColCollection(cols...)
+	if err != nil {
+		return nil, err
+	}
+
+	newSch := schema.SchemaWithComment(schema.SchemaFromCols(colColl), tblSch.GetComment())
+
+	vrw := doltDB.ValueReadWriter()
+	schemaVal, err := encoding.MarshalAsNomsValue(ctx, vrw, newSch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if newCol.Kind != oldCol.Kind || (oldCol.IsNullable() && !newCol.IsNullable()) {
+		rowData, err = convertColumnValues(ctx, rowData, tblSch, newSch, oldCol, newCol)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newTableFrom(ctx, vrw, tbl, schemaVal, rowData)
+}
+
+// convertColumnValues converts the values of the old column in the rows given to the kind of the new column, and
+// checks that every row has a value if the new column is NOT NULL. If any rows can't be converted, none are changed
+// and the error returned lists the primary keys of the first of them.
+func convertColumnValues(ctx context.Context, rowData types.Map, oldSch, newSch schema.Schema, oldCol, newCol schema.Column) (types.Map, error) {
+	var conv types.MarshalCallback
+	if newCol.Kind != oldCol.Kind {
+		var err error
+		conv, err = doltcore.GetConvFunc(oldCol.Kind, newCol.Kind)
+
+		if err != nil {
+			return types.EmptyMap, err
+		}
+	}
+
+	var badKeys []string
+	badCount := 0
+	me := rowData.Edit()
+	err := rowData.Iter(ctx, func(k, v types.Value) (stop bool, err error) {
+		vals, err := row.ParseTaggedValues(v.(types.Tuple))
+
+		if err != nil {
+			return true, err
+		}
+
+		val := vals[oldCol.Tag]
+		if types.IsNull(val) {
+			if !newCol.IsNullable() {
+				badCount++
+				badKeys, err = appendKeyString(badKeys, k.(types.Tuple), oldSch)
+			}
+			return false, err
+		}
+
+		if conv == nil {
+			return false, nil
+		}
+
+		newVal, convErr := conv(val)
+		if convErr != nil || (types.IsNull(newVal) && !newCol.IsNullable()) {
+			badCount++
+			badKeys, err = appendKeyString(badKeys, k.(types.Tuple), oldSch)
+			return false, err
+		}
+
+		if types.IsNull(newVal) {
+			delete(vals, oldCol.Tag)
+		} else {
+			vals[oldCol.Tag] = newVal
+		}
+		me.Set(k, vals.NomsTupleForTags(rowData.Format(), newSch.GetNonPKCols().SortedTags, false))
+		return false, nil
+	})
+
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	if badCount > 0 {
+		keys := strings.Join(badKeys, ", ")
+		if badCount > len(badKeys) {
+			keys += ", ..."
+		}
+
+		target := types.KindToString[newCol.Kind]
+		if !newCol.IsNullable() {
+			target += " NOT NULL"
+		}
+
+		return types.EmptyMap, fmt.Errorf("Cannot modify column %s: %d rows have values that can't be converted to %s, with primary keys %s", oldCol.Name, badCount, target, keys)
+	}
+
+	return me.Map(ctx)
+}
+
+// appendKeyString appends the primary key values in the key tuple given to the strings given, as long as there are
+// fewer than maxReportedRows of them.
+func appendKeyString(keys []string, key types.Tuple, sch schema.Schema) ([]string, error) {
+	if len(keys) >= maxReportedRows {
+		return keys, nil
+	}
+
+	vals, err := row.ParseTaggedValues(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var strs []string
+	for _, tag := range sch.GetPKCols().Tags {
+		val := vals[tag]
+		str := fmt.Sprintf("%v", val)
+		if toStr, err := doltcore.GetConvFunc(val.Kind(), types.StringKind); err == nil {
+			if strVal, err := toStr(val); err == nil && !types.IsNull(strVal) {
+				str = string(strVal.(types.String))
+			}
+		}
+		strs = append(strs, str)
+	}
+
+	return append(keys, "("+strings.Join(strs, ", ")+")"), nil
+}
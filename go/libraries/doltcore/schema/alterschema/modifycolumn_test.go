@@ -0,0 +1,167 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alterschema
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestModifyColumn(t *testing.T) {
+	var agesAsStrings []types.Value
+	for _, age := range dtestutils.Ages {
+		agesAsStrings = append(agesAsStrings, types.String(strconv.FormatUint(age, 10)))
+	}
+
+	tests := []struct {
+		name           string
+		colName        string
+		newCol         schema.Column
+		expectedSchema schema.Schema
+		expectedVals   []types.Value
+		expectedErr    string
+	}{
+		{
+			name:    "uint to string",
+			colName: "age",
+			newCol:  schema.NewColumn("age", schema.InvalidTag, types.StringKind, false, schema.NotNullConstraint{}),
+			expectedSchema: dtestutils.CreateSchema(
+				schema.NewColumn("id", dtestutils.IdTag, types.UUIDKind, true, schema.NotNullConstraint{}),
+				schema.NewColumn("name", dtestutils.NameTag, types.StringKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("age", dtestutils.AgeTag, types.StringKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("is_married", dtestutils.IsMarriedTag, types.BoolKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("title", dtestutils.TitleTag, types.StringKind, false),
+			),
+			expectedVals: agesAsStrings,
+		},
+		{
+			name:    "rename and make nullable",
+			colName: "age",
+			newCol:  schema.NewColumn("years", 1234, types.UintKind, false),
+			expectedSchema: dtestutils.CreateSchema(
+				schema.NewColumn("id", dtestutils.IdTag, types.UUIDKind, true, schema.NotNullConstraint{}),
+				schema.NewColumn("name", dtestutils.NameTag, types.StringKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("years", dtestutils.AgeTag, types.UintKind, false),
+				schema.NewColumn("is_married", dtestutils.IsMarriedTag, types.BoolKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("title", dtestutils.TitleTag, types.StringKind, false),
+			),
+			expectedVals: []types.Value{types.Uint(32), types.Uint(25), types.Uint(21)},
+		},
+		{
+			name:        "values can't be converted",
+			colName:     "name",
+			newCol:      schema.NewColumn("name", schema.InvalidTag, types.IntKind, false, schema.NotNullConstraint{}),
+			expectedErr: "Cannot modify column name: 3 rows have values that can't be converted to Int NOT NULL, with primary keys (00000000-0000-0000-0000-000000000000), (00000000-0000-0000-0000-000000000001), (00000000-0000-0000-0000-000000000002)",
+		},
+		{
+			name:        "primary key",
+			colName:     "id",
+			newCol:      schema.NewColumn("id", schema.InvalidTag, types.StringKind, false, schema.NotNullConstraint{}),
+			expectedErr: "Cannot modify column in primary key",
+		},
+		{
+			name:        "column not found",
+			colName:     "not found",
+			newCol:      schema.NewColumn("not found", schema.InvalidTag, types.StringKind, false),
+			expectedErr: "column not found",
+		},
+		{
+			name:        "name collision",
+			colName:     "age",
+			newCol:      schema.NewColumn("Title", schema.InvalidTag, types.UintKind, false),
+			expectedErr: "two different columns with the same name exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := createEnvWithSeedData(t)
+			ctx := context.Background()
+
+			root, err := dEnv.WorkingRoot(ctx)
+			assert.NoError(t, err)
+			tbl, _, err := root.GetTable(ctx, tableName)
+			require.NoError(t, err)
+
+			updatedTable, err := ModifyColumn(ctx, dEnv.DoltDB, tbl, tt.colName, tt.newCol)
+			if len(tt.expectedErr) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			} else {
+				require.NoError(t, err)
+			}
+
+			sch, err := updatedTable.GetSchema(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSchema, sch)
+
+			rowData, err := updatedTable.GetRowData(ctx)
+			require.NoError(t, err)
+
+			var foundVals []types.Value
+			err = rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+				r, err := row.FromNoms(tt.expectedSchema, key.(types.Tuple), value.(types.Tuple))
+
+				if err != nil {
+					return false, err
+				}
+
+				val, _ := r.GetColVal(dtestutils.AgeTag)
+				foundVals = append(foundVals, val)
+				return false, nil
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedVals, foundVals)
+		})
+	}
+}
+
+func TestModifyColumnNotNull(t *testing.T) {
+	dEnv := createEnvWithSeedData(t)
+	ctx := context.Background()
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	tbl, _, err := root.GetTable(ctx, tableName)
+	require.NoError(t, err)
+
+	// every row has a title, so the column can become NOT NULL
+	_, err = ModifyColumn(ctx, dEnv.DoltDB, tbl, "title", schema.NewColumn("title", schema.InvalidTag, types.StringKind, false, schema.NotNullConstraint{}))
+	require.NoError(t, err)
+
+	r, err := dtestutils.TypedRows[1].SetColVal(dtestutils.TitleTag, types.NullValue, dtestutils.TypedSchema)
+	require.NoError(t, err)
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+	rowData, err = rowData.Edit().Set(r.NomsMapKey(dtestutils.TypedSchema), r.NomsMapValue(dtestutils.TypedSchema)).Map(ctx)
+	require.NoError(t, err)
+	tbl, err = tbl.UpdateRows(ctx, rowData)
+	require.NoError(t, err)
+
+	_, err = ModifyColumn(ctx, dEnv.DoltDB, tbl, "title", schema.NewColumn("title", schema.InvalidTag, types.StringKind, false, schema.NotNullConstraint{}))
+	require.Error(t, err)
+	assert.Equal(t, "Cannot modify column title: 1 rows have values that can't be converted to String NOT NULL, with primary keys (00000000-0000-0000-0000-000000000001)", err.Error())
+}
@@ -64,7 +64,7 @@ func RenameColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table,
 		return nil, err
 	}
 
-	newSch := schema.SchemaFromCols(colColl)
+	newSch := schema.SchemaWithComment(schema.SchemaFromCols(colColl), tblSch.GetComment())
 
 	vrw := doltDB.ValueReadWriter()
 	schemaVal, err := encoding.MarshalAsNomsValue(ctx, vrw, newSch)
@@ -79,11 +79,5 @@ func RenameColumn(ctx context.Context, doltDB *doltdb.DoltDB, tbl *doltdb.Table,
 		return nil, err
 	}
 
-	newTable, err := doltdb.NewTable(ctx, vrw, schemaVal, rd)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return newTable, nil
+	return newTableFrom(ctx, vrw, tbl, schemaVal, rd)
 }
@@ -20,12 +20,15 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 )
 
-// RenameTable renames a table with in a RootValue and returns the updated root.
+// RenameTable renames a table with in a RootValue and returns the updated root. The table keeps its contents, so diffs
+// between the roots report it as renamed rather than as dropped and added.
 func RenameTable(ctx context.Context, doltDb *doltdb.DoltDB, root *doltdb.RootValue, oldName, newName string) (*doltdb.RootValue, error) {
 	if newName == oldName {
 		return root, nil
 	} else if root == nil {
 		panic("invalid parameters")
+	} else if !doltdb.IsValidTableName(newName) {
+		return nil, doltdb.ErrInvTableName
 	}
 
 	tbl, ok, err := root.GetTable(ctx, oldName)
@@ -58,6 +58,12 @@ func TestRenameTable(t *testing.T) {
 			newTableName: otherTable,
 			expectedErr:  doltdb.ErrTableExists.Error(),
 		},
+		{
+			name:         "invalid table name",
+			tableName:    "people",
+			newTableName: "new people",
+			expectedErr:  doltdb.ErrInvTableName.Error(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -59,6 +59,15 @@ type Column struct {
 
 	// Constraints are rules that can be checked on each column to say if the columns value is valid
 	Constraints []ColConstraint
+
+	// Comment is the user supplied documentation for the column
+	Comment string
+
+	// MergeStrategy determines how the changes made to the column's values on two branches are merged
+	MergeStrategy MergeStrategy
+
+	// TypeName is the name of the custom type of the column's values, or empty for columns of a built in type
+	TypeName string
 }
 
 // NewColumn creates a Column instance
@@ -75,6 +84,9 @@ func NewColumn(name string, tag uint64, kind types.NomsKind, partOfPK bool, cons
 		kind,
 		partOfPK,
 		constraints,
+		"",
+		MergeDefault,
+		"",
 	}
 }
 
@@ -88,11 +100,13 @@ func (c Column) IsNullable() bool {
 	return true
 }
 
-// Equals tests equality between two columns.
+// Equals tests equality between two columns. Comments are documentation only, and merge strategies only matter to
+// merges, so neither is compared.
 func (c Column) Equals(other Column) bool {
 	return c.Name == other.Name &&
 		c.Tag == other.Tag &&
 		c.Kind == other.Kind &&
+		c.TypeName == other.TypeName &&
 		c.IsPartOfPK == other.IsPartOfPK &&
 		ColConstraintsAreEqual(c.Constraints, other.Constraints)
 }
@@ -0,0 +1,136 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// CustomType is a column type defined outside of dolt, such as an IP address or an amount of currency. Values of a
+// custom type are stored as values of its Kind, and are parsed from strings, formatted as strings and compared using the
+// functions the type is registered with. Imports, SQL queries and the tabular output of tables all use them for
+// columns of the type.
+type CustomType struct {
+	// Name is the name columns refer to the type by. Names are case insensitive.
+	Name string
+
+	// Kind is the types.NomsKind that values of the type are stored as
+	Kind types.NomsKind
+
+	// Parse converts the string representation of a value of the type to the value stored. Strings representing values
+	// that compare as equal should be parsed as the same value.
+	Parse func(str string) (types.Value, error)
+
+	// Format converts a stored value of the type to its string representation
+	Format func(val types.Value) (string, error)
+
+	// Compare returns a negative number, zero or a positive number when the first value is less than, equal to or
+	// greater than the second. When nil, values are ordered as other values of Kind are.
+	Compare func(a, b types.Value) (int, error)
+}
+
+var customTypesMu = &sync.RWMutex{}
+var customTypes = make(map[string]*CustomType)
+
+// RegisterCustomType registers the custom type given so that columns can be declared with its name. Types are
+// typically registered in the init function of the package defining them.
+func RegisterCustomType(ct CustomType) error {
+	name := strings.ToLower(strings.TrimSpace(ct.Name))
+
+	if name == "" {
+		return errors.New("custom types must have a name")
+	} else if _, ok := LwrStrToKind[name]; ok {
+		return fmt.Errorf("'%s' is the name of a built in type", ct.Name)
+	} else if _, ok := KindToLwrStr[ct.Kind]; !ok || ct.Kind == types.NullKind {
+		return fmt.Errorf("custom type '%s' can't be stored as values of kind %v", ct.Name, ct.Kind)
+	} else if ct.Parse == nil || ct.Format == nil {
+		return fmt.Errorf("custom type '%s' must have Parse and Format functions", ct.Name)
+	}
+
+	customTypesMu.Lock()
+	defer customTypesMu.Unlock()
+
+	if _, ok := customTypes[name]; ok {
+		return fmt.Errorf("custom type '%s' is already registered", ct.Name)
+	}
+
+	ct.Name = name
+	customTypes[name] = &ct
+	return nil
+}
+
+// GetCustomType returns the registered custom type with the name given.
+func GetCustomType(name string) (*CustomType, bool) {
+	customTypesMu.RLock()
+	defer customTypesMu.RUnlock()
+
+	ct, ok := customTypes[strings.ToLower(name)]
+	return ct, ok
+}
+
+// CompareValues compares two values of the type using its Compare function, or the order of the values of its Kind
+// when it doesn't have one.
+func (ct *CustomType) CompareValues(a, b types.Value) (int, error) {
+	if ct.Compare != nil {
+		return ct.Compare(a, b)
+	}
+
+	if a.Equals(b) {
+		return 0, nil
+	}
+
+	less, err := a.Less(types.Format_Default, b)
+
+	if err != nil {
+		return 0, err
+	} else if less {
+		return -1, nil
+	}
+
+	return 1, nil
+}
+
+// CustomType returns the custom type of the column, if it has one that is registered. Columns whose custom type isn't
+// registered hold values of their Kind like any other column.
+func (c Column) CustomType() (*CustomType, bool) {
+	if c.TypeName == "" {
+		return nil, false
+	}
+
+	return GetCustomType(c.TypeName)
+}
+
+// ValidateCustomType returns an error if the column has a custom type that isn't registered, or that isn't stored as
+// values of the column's kind.
+func (c Column) ValidateCustomType() error {
+	if c.TypeName == "" {
+		return nil
+	}
+
+	ct, ok := c.CustomType()
+
+	if !ok {
+		return fmt.Errorf("unknown custom type '%s' of column '%s'", c.TypeName, c.Name)
+	} else if ct.Kind != c.Kind {
+		return fmt.Errorf("column '%s' of type %s can't have the custom type '%s', which is stored as %s", c.Name, c.KindString(), ct.Name, KindToLwrStr[ct.Kind])
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// versionType is a custom type of dotted version numbers, which are ordered by their parts rather than as strings
+var versionType = CustomType{
+	Name: "Version",
+	Kind: types.StringKind,
+	Parse: func(str string) (types.Value, error) {
+		for _, part := range strings.Split(str, ".") {
+			if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+				return nil, errors.New("not a version")
+			}
+		}
+		return types.String(str), nil
+	},
+	Format: func(val types.Value) (string, error) {
+		return string(val.(types.String)), nil
+	},
+	Compare: func(a, b types.Value) (int, error) {
+		aParts, bParts := strings.Split(string(a.(types.String)), "."), strings.Split(string(b.(types.String)), ".")
+		for i := 0; i < len(aParts) && i < len(bParts); i++ {
+			aPart, _ := strconv.ParseUint(aParts[i], 10, 64)
+			bPart, _ := strconv.ParseUint(bParts[i], 10, 64)
+			if aPart != bPart {
+				if aPart < bPart {
+					return -1, nil
+				}
+				return 1, nil
+			}
+		}
+		return len(aParts) - len(bParts), nil
+	},
+}
+
+func TestCustomTypes(t *testing.T) {
+	require.NoError(t, RegisterCustomType(versionType))
+
+	noop := func(str string) (types.Value, error) { return types.String(str), nil }
+	format := func(val types.Value) (string, error) { return "", nil }
+	for _, ct := range []CustomType{
+		versionType,
+		{Name: "VERSION", Kind: types.StringKind, Parse: noop, Format: format},
+		{Name: " ", Kind: types.StringKind, Parse: noop, Format: format},
+		{Name: "string", Kind: types.StringKind, Parse: noop, Format: format},
+		{Name: "nothing", Kind: types.NullKind, Parse: noop, Format: format},
+		{Name: "unformatted", Kind: types.StringKind, Parse: noop},
+	} {
+		assert.Error(t, RegisterCustomType(ct), ct.Name)
+	}
+
+	ct, ok := GetCustomType("VERSION")
+	require.True(t, ok)
+	assert.Equal(t, "version", ct.Name)
+	_, ok = GetCustomType("unknown")
+	assert.False(t, ok)
+
+	cmp, err := ct.CompareValues(types.String("1.10"), types.String("1.9"))
+	require.NoError(t, err)
+	assert.True(t, cmp > 0)
+
+	// types without a compare function order values as their kind does
+	cmp, err = (&CustomType{Kind: types.StringKind}).CompareValues(types.String("1.10"), types.String("1.9"))
+	require.NoError(t, err)
+	assert.True(t, cmp < 0)
+	cmp, err = (&CustomType{Kind: types.StringKind}).CompareValues(types.String("1.9"), types.String("1.9"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, cmp)
+
+	col := NewColumn("v", 0, types.StringKind, false)
+	_, ok = col.CustomType()
+	assert.False(t, ok)
+	assert.NoError(t, col.ValidateCustomType())
+
+	col.TypeName = "version"
+	colType, ok := col.CustomType()
+	assert.True(t, ok)
+	assert.Equal(t, ct, colType)
+	assert.NoError(t, col.ValidateCustomType())
+	assert.False(t, col.Equals(NewColumn("v", 0, types.StringKind, false)))
+
+	col.Kind = types.UintKind
+	assert.Error(t, col.ValidateCustomType())
+
+	col.TypeName = "unknown"
+	_, ok = col.CustomType()
+	assert.False(t, ok)
+	assert.Error(t, col.ValidateCustomType())
+}
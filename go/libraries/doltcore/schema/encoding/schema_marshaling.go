@@ -36,6 +36,13 @@ type encodedColumn struct {
 	IsPartOfPK bool `noms:"is_part_of_pk" json:"is_part_of_pk"`
 
 	Constraints []encodedConstraint `noms:"col_constraints" json:"col_constraints"`
+
+	Comment string `noms:"comment,omitempty" json:"comment,omitempty"`
+
+	MergeStrategy string `noms:"merge_strategy,omitempty" json:"merge_strategy,omitempty"`
+
+	// TypeName is the name of the custom type of the field, if it has one
+	TypeName string `noms:"type_name,omitempty" json:"type_name,omitempty"`
 }
 
 func encodeAllColConstraints(constraints []schema.ColConstraint) []encodedConstraint {
@@ -69,12 +76,19 @@ func encodeColumn(col schema.Column) encodedColumn {
 		col.Name,
 		col.KindString(),
 		col.IsPartOfPK,
-		encodeAllColConstraints(col.Constraints)}
+		encodeAllColConstraints(col.Constraints),
+		col.Comment,
+		string(col.MergeStrategy),
+		col.TypeName}
 }
 
 func (nfd encodedColumn) decodeColumn() schema.Column {
 	colConstraints := decodeAllColConstraint(nfd.Constraints)
-	return schema.NewColumn(nfd.Name, nfd.Tag, schema.LwrStrToKind[nfd.Kind], nfd.IsPartOfPK, colConstraints...)
+	col := schema.NewColumn(nfd.Name, nfd.Tag, schema.LwrStrToKind[nfd.Kind], nfd.IsPartOfPK, colConstraints...)
+	col.Comment = nfd.Comment
+	col.MergeStrategy = schema.MergeStrategy(nfd.MergeStrategy)
+	col.TypeName = nfd.TypeName
+	return col
 }
 
 type encodedConstraint struct {
@@ -92,6 +106,7 @@ func (encCnst encodedConstraint) decodeColConstraint() schema.ColConstraint {
 
 type schemaData struct {
 	Columns []encodedColumn `noms:"columns" json:"columns"`
+	Comment string          `noms:"comment,omitempty" json:"comment,omitempty"`
 }
 
 func toSchemaData(sch schema.Schema) (schemaData, error) {
@@ -110,7 +125,7 @@ func toSchemaData(sch schema.Schema) (schemaData, error) {
 		return schemaData{}, err
 	}
 
-	return schemaData{encCols}, nil
+	return schemaData{encCols, sch.GetComment()}, nil
 }
 
 func (sd schemaData) decodeSchema() (schema.Schema, error) {
@@ -127,7 +142,13 @@ func (sd schemaData) decodeSchema() (schema.Schema, error) {
 		return nil, err
 	}
 
-	return schema.SchemaFromCols(colColl), nil
+	sch := schema.SchemaFromCols(colColl)
+
+	if sd.Comment != "" {
+		sch = schema.SchemaWithComment(sch, sd.Comment)
+	}
+
+	return sch, nil
 }
 
 // MarshalAsNomsValue takes a Schema and converts it to a types.Value
@@ -189,5 +210,20 @@ func UnmarshalJson(jsonStr string) (schema.Schema, error) {
 		return nil, err
 	}
 
-	return sd.decodeSchema()
+	sch, err := sd.decodeSchema()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// custom types named in a schema file must be registered, unlike those of schemas that are already stored
+	err = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		return false, col.ValidateCustomType()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sch, nil
 }
@@ -89,3 +89,136 @@ func TestJSONMarshalling(t *testing.T) {
 		t.Error("Value different after marshalling and unmarshalling.")
 	}
 }
+
+func TestMarshallingCommentsAndMergeStrategies(t *testing.T) {
+	tSchema := createTestSchema()
+	cols := tSchema.GetAllCols().GetColumns()
+	cols[1].Comment = "The first name"
+	cols[2].MergeStrategy = schema.MergeSet
+	colColl, _ := schema.NewColCollection(cols...)
+	commented := schema.SchemaWithComment(schema.SchemaFromCols(colColl), "The people")
+
+	db, err := dbfactory.MemFactory{}.CreateDB(context.Background(), types.Format_7_18, nil, nil)
+
+	if err != nil {
+		t.Fatal("Could not create in mem noms db.")
+	}
+
+	val, err := MarshalAsNomsValue(context.Background(), db, commented)
+
+	if err != nil {
+		t.Fatal("Failed to marshal Schema as a types.Value.")
+	}
+
+	unMarshalled, err := UnmarshalNomsValue(context.Background(), types.Format_7_18, val)
+
+	if err != nil {
+		t.Fatal("Failed to unmarshal types.Value as Schema")
+	}
+
+	if !reflect.DeepEqual(commented, unMarshalled) {
+		t.Error("Value different after marshalling and unmarshalling.")
+	}
+
+	jsonStr, err := MarshalAsJson(commented)
+
+	if err != nil {
+		t.Fatal("Failed to marshal Schema as json.")
+	}
+
+	jsonUnmarshalled, err := UnmarshalJson(jsonStr)
+
+	if err != nil {
+		t.Fatal("Failed to unmarshal json as Schema")
+	}
+
+	if !reflect.DeepEqual(commented, jsonUnmarshalled) {
+		t.Error("Value different after marshalling and unmarshalling.")
+	}
+
+	// Schemas without comments are encoded as they were before comments were supported
+	val, err = MarshalAsNomsValue(context.Background(), db, tSchema)
+
+	if err != nil {
+		t.Fatal("Failed to marshal Schema as a types.Value.")
+	}
+
+	if _, ok, err := val.(types.Struct).MaybeGet("comment"); err != nil || ok {
+		t.Error("Schema without a comment has a comment field.")
+	}
+}
+
+func TestMarshallingCustomTypes(t *testing.T) {
+	err := schema.RegisterCustomType(schema.CustomType{
+		Name:   "name",
+		Kind:   types.StringKind,
+		Parse:  func(str string) (types.Value, error) { return types.String(str), nil },
+		Format: func(val types.Value) (string, error) { return string(val.(types.String)), nil },
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tSchema := createTestSchema()
+	cols := tSchema.GetAllCols().GetColumns()
+	cols[1].TypeName = "name"
+	colColl, _ := schema.NewColCollection(cols...)
+	typed := schema.SchemaFromCols(colColl)
+
+	db, err := dbfactory.MemFactory{}.CreateDB(context.Background(), types.Format_7_18, nil, nil)
+
+	if err != nil {
+		t.Fatal("Could not create in mem noms db.")
+	}
+
+	val, err := MarshalAsNomsValue(context.Background(), db, typed)
+
+	if err != nil {
+		t.Fatal("Failed to marshal Schema as a types.Value.")
+	}
+
+	unMarshalled, err := UnmarshalNomsValue(context.Background(), types.Format_7_18, val)
+
+	if err != nil {
+		t.Fatal("Failed to unmarshal types.Value as Schema")
+	}
+
+	if !reflect.DeepEqual(typed, unMarshalled) {
+		t.Error("Value different after marshalling and unmarshalling.")
+	}
+
+	jsonStr, err := MarshalAsJson(typed)
+
+	if err != nil {
+		t.Fatal("Failed to marshal Schema as json.")
+	}
+
+	jsonUnmarshalled, err := UnmarshalJson(jsonStr)
+
+	if err != nil {
+		t.Fatal("Failed to unmarshal json as Schema")
+	}
+
+	if !reflect.DeepEqual(typed, jsonUnmarshalled) {
+		t.Error("Value different after marshalling and unmarshalling.")
+	}
+
+	// Schema files can only name registered custom types, of the kind they're stored as
+	cols[1].TypeName = "unknown"
+	colColl, _ = schema.NewColCollection(cols...)
+	jsonStr, _ = MarshalAsJson(schema.SchemaFromCols(colColl))
+
+	if _, err := UnmarshalJson(jsonStr); err == nil {
+		t.Error("Schema with an unknown custom type was unmarshalled.")
+	}
+
+	cols[1].TypeName = "name"
+	cols[1].Kind = types.IntKind
+	colColl, _ = schema.NewColCollection(cols...)
+	jsonStr, _ = MarshalAsJson(schema.SchemaFromCols(colColl))
+
+	if _, err := UnmarshalJson(jsonStr); err == nil {
+		t.Error("Schema with a custom type of the wrong kind was unmarshalled.")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// MergeStrategy determines how the changes made to the value of a column in the same row on two branches are merged.
+type MergeStrategy string
+
+const (
+	// MergeDefault keeps the value of the side that changed it, and conflicts if both sides changed it to different
+	// values.
+	MergeDefault MergeStrategy = ""
+	// MergeCounter treats values as counters and adds up the changes made on both sides, so that the increments and
+	// decrements made on each side are all kept. Only numeric columns can be counters.
+	MergeCounter MergeStrategy = "counter"
+	// MergeSet treats values as sets of elements separated by SetElementSeparator, and keeps the elements added on
+	// either side except for those removed on either side. Only string columns can be sets.
+	MergeSet MergeStrategy = "set"
+)
+
+// SetElementSeparator separates the elements of the values of columns with the MergeSet strategy.
+const SetElementSeparator = ","
+
+// ParseMergeStrategy returns the merge strategy with the name given, which is one of default, counter or set.
+func ParseMergeStrategy(str string) (MergeStrategy, error) {
+	switch strategy := MergeStrategy(strings.ToLower(strings.TrimSpace(str))); strategy {
+	case "default":
+		return MergeDefault, nil
+	case MergeCounter, MergeSet:
+		return strategy, nil
+	}
+
+	return MergeDefault, fmt.Errorf("invalid merge strategy '%s', valid strategies are 'default', '%s' and '%s'", str, MergeCounter, MergeSet)
+}
+
+// String returns the name of the strategy.
+func (ms MergeStrategy) String() string {
+	if ms == MergeDefault {
+		return "default"
+	}
+	return string(ms)
+}
+
+// ValidateMergeStrategy returns an error if the column given can't have the merge strategy given. Primary key columns
+// can only have the default strategy.
+func (c Column) ValidateMergeStrategy(strategy MergeStrategy) error {
+	switch strategy {
+	case MergeDefault:
+		return nil
+	case MergeCounter:
+		if !c.IsPartOfPK && (c.Kind == types.IntKind || c.Kind == types.UintKind || c.Kind == types.FloatKind) {
+			return nil
+		}
+	case MergeSet:
+		if !c.IsPartOfPK && c.Kind == types.StringKind {
+			return nil
+		}
+	default:
+		return fmt.Errorf("invalid merge strategy '%s'", strategy)
+	}
+
+	return fmt.Errorf("column '%s' of type %s can't have the %s merge strategy", c.Name, c.KindString(), strategy)
+}
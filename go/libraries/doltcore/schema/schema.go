@@ -15,8 +15,12 @@
 package schema
 
 import (
+	"crypto/sha512"
+	"encoding/binary"
 	"math/rand"
 	"time"
+
+	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
 // Schema is an interface for retrieving the columns that make up a schema
@@ -29,6 +33,9 @@ type Schema interface {
 
 	// GetAllCols gets the collection of all columns (pk and non-pk)
 	GetAllCols() *ColCollection
+
+	// GetComment returns the comment documenting the table the schema belongs to.
+	GetComment() string
 }
 
 // ColFromTag returns a schema.Column from a schema and a tag
@@ -140,3 +147,30 @@ func AutoGenerateTag(sch Schema) uint64 {
 
 	return randTag
 }
+
+// maxDeterministicTag is the size of the space that DeterministicTag picks tags from. It's large enough that columns
+// added independently on different branches are very unlikely to get the same tag.
+const maxDeterministicTag uint64 = 1 << 32
+
+// DeterministicTag returns a tag for a new column with the name and kind given in the table named. Unlike
+// AutoGenerateTag, the tag is derived from a hash of the table name, column name and kind, so the same column added to
+// the same table on two different branches gets the same tag and the branches merge cleanly, while different columns
+// added on different branches are very unlikely to collide. If the tag is already used in the schema given, the next
+// unused tag is returned instead.
+func DeterministicTag(sch Schema, tableName, colName string, kind types.NomsKind) uint64 {
+	h := sha512.New()
+	h.Write([]byte(tableName))
+	h.Write([]byte{0})
+	h.Write([]byte(colName))
+	h.Write([]byte{0, byte(kind)})
+
+	allCols := sch.GetAllCols()
+	tag := binary.BigEndian.Uint64(h.Sum(nil)) % maxDeterministicTag
+	for {
+		if _, ok := allCols.GetByTag(tag); !ok {
+			return tag
+		}
+
+		tag = (tag + 1) % maxDeterministicTag
+	}
+}
@@ -24,10 +24,12 @@ var EmptySchema = &schemaImpl{
 	EmptyColColl,
 	EmptyColColl,
 	EmptyColColl,
+	"",
 }
 
 type schemaImpl struct {
 	pkCols, nonPKCols, allCols *ColCollection
+	comment                    string
 }
 
 // SchemaFromCols creates a Schema from a collection of columns
@@ -51,7 +53,7 @@ func SchemaFromCols(allCols *ColCollection) Schema {
 	nonPKColColl, _ := NewColCollection(nonPKCols...)
 
 	return &schemaImpl{
-		pkColColl, nonPKColColl, allCols,
+		pkColColl, nonPKColColl, allCols, "",
 	}
 }
 
@@ -89,6 +91,13 @@ func ValidateForInsert(allCols *ColCollection) error {
 	return err
 }
 
+// SchemaWithComment returns a copy of the given schema with its table comment set to the one given.
+func SchemaWithComment(sch Schema, comment string) Schema {
+	return &schemaImpl{
+		sch.GetPKCols(), sch.GetNonPKCols(), sch.GetAllCols(), comment,
+	}
+}
+
 // UnkeyedSchemaFromCols creates a schema without any primary keys to be used for displaying to users, tests, etc. Such
 // unkeyed schemas are not suitable to be inserted into storage.
 func UnkeyedSchemaFromCols(allCols *ColCollection) Schema {
@@ -104,7 +113,7 @@ func UnkeyedSchemaFromCols(allCols *ColCollection) Schema {
 	nonPKColColl, _ := NewColCollection(nonPKCols...)
 
 	return &schemaImpl{
-		pkColColl, nonPKColColl, nonPKColColl,
+		pkColColl, nonPKColColl, nonPKColColl, "",
 	}
 }
 
@@ -138,7 +147,7 @@ func SchemaFromPKAndNonPKCols(pkCols, nonPKCols *ColCollection) (Schema, error)
 	}
 
 	return &schemaImpl{
-		pkCols, nonPKCols, allColColl,
+		pkCols, nonPKCols, allColColl, "",
 	}, nil
 }
 
@@ -157,6 +166,11 @@ func (si *schemaImpl) GetPKCols() *ColCollection {
 	return si.pkCols
 }
 
+// GetComment returns the comment documenting the table the schema belongs to.
+func (si *schemaImpl) GetComment() string {
+	return si.comment
+}
+
 func (si *schemaImpl) String() string {
 	var b strings.Builder
 	writeColFn := func(tag uint64, col Column) (stop bool, err error) {
@@ -46,14 +46,14 @@ var ageVal = types.Uint(53)
 var titleVal = types.NullValue
 
 var pkCols = []Column{
-	{lnColName, lnColTag, types.StringKind, true, nil},
-	{fnColName, fnColTag, types.StringKind, true, nil},
+	{lnColName, lnColTag, types.StringKind, true, nil, "", "", ""},
+	{fnColName, fnColTag, types.StringKind, true, nil, "", "", ""},
 }
 var nonPkCols = []Column{
-	{addrColName, addrColTag, types.StringKind, false, nil},
-	{ageColName, ageColTag, types.UintKind, false, nil},
-	{titleColName, titleColTag, types.StringKind, false, nil},
-	{reservedColName, reservedColTag, types.StringKind, false, nil},
+	{addrColName, addrColTag, types.StringKind, false, nil, "", "", ""},
+	{ageColName, ageColTag, types.UintKind, false, nil, "", "", ""},
+	{titleColName, titleColTag, types.StringKind, false, nil, "", "", ""},
+	{reservedColName, reservedColTag, types.StringKind, false, nil, "", "", ""},
 }
 
 var allCols = append(append([]Column(nil), pkCols...), nonPkCols...)
@@ -97,7 +97,7 @@ func TestValidateForInsert(t *testing.T) {
 	})
 
 	t.Run("Name collision", func(t *testing.T) {
-		cols := append(allCols, Column{titleColName, 100, types.StringKind, false, nil})
+		cols := append(allCols, Column{titleColName, 100, types.StringKind, false, nil, "", "", ""})
 		colColl, err := NewColCollection(cols...)
 		require.NoError(t, err)
 
@@ -150,6 +150,27 @@ func testSchema(method string, sch Schema, t *testing.T) {
 	}
 }
 
+func TestDeterministicTag(t *testing.T) {
+	colColl, err := NewColCollection(NewColumn("id", 0, types.UintKind, true, NotNullConstraint{}))
+	require.NoError(t, err)
+	sch := SchemaFromCols(colColl)
+
+	tag := DeterministicTag(sch, "people", "age", types.IntKind)
+	assert.Equal(t, tag, DeterministicTag(sch, "people", "age", types.IntKind))
+	assert.True(t, tag < maxDeterministicTag)
+
+	assert.NotEqual(t, tag, DeterministicTag(sch, "people", "Age", types.IntKind))
+	assert.NotEqual(t, tag, DeterministicTag(sch, "people", "age", types.StringKind))
+	assert.NotEqual(t, tag, DeterministicTag(sch, "places", "age", types.IntKind))
+	assert.NotEqual(t, tag, DeterministicTag(sch, "peopleage", "", types.IntKind))
+
+	// a tag already used in the schema is skipped
+	colColl, err = colColl.Append(NewColumn("other", tag, types.StringKind, false))
+	require.NoError(t, err)
+	sch = SchemaFromCols(colColl)
+	assert.Equal(t, (tag+1)%maxDeterministicTag, DeterministicTag(sch, "people", "age", types.IntKind))
+}
+
 func validateCols(t *testing.T, cols []Column, colColl *ColCollection, msg string) {
 	if !reflect.DeepEqual(cols, colColl.cols) {
 		t.Error()
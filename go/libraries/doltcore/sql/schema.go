@@ -16,6 +16,7 @@ package sql
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	dtypes "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle/types"
@@ -48,9 +49,19 @@ func FmtColWithNameAndType(indent, nameWidth, typeWidth int, colName, typeStr st
 		}
 	}
 
+	if col.Comment != "" {
+		return colStr + fmt.Sprintf(" COMMENT '%s tag:%d'", escapeSingleQuoted(col.Comment), col.Tag)
+	}
+
 	return colStr + fmt.Sprintf(" COMMENT 'tag:%d'", col.Tag)
 }
 
+// escapeSingleQuoted escapes the string given for use inside a single quoted SQL string literal.
+func escapeSingleQuoted(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	return strings.ReplaceAll(s, "'", "''")
+}
+
 // FmtColPrimaryKey creates a string representing a primary key constraint within a sql create table statement with a
 // given indent.
 func FmtColPrimaryKey(indent int, colStr string) string {
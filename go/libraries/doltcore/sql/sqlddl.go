@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -43,6 +44,21 @@ const (
 var ErrNoPrimaryKeyColumns = errors.New("at least one primary key column must be specified")
 var tagCommentPrefix = "tag:"
 
+// The sql parser doesn't parse the column definitions of MODIFY COLUMN and CHANGE COLUMN statements, so they're
+// matched here and the column definition is parsed separately. columnNameRegex splits a column name, which may be
+// quoted, from the text following it.
+var modifyColumnRegex = regexp.MustCompile("(?is)^\\s*alter\\s+table\\s+(?:`[^`]+`|\\S+)\\s+(modify|change)\\s+(?:column\\s+)?(.+?)[\\s;]*$")
+var columnNameRegex = regexp.MustCompile("(?s)^(`[^`]+`|\\S+)\\s+(.+)$")
+
+// The sql parser doesn't parse the index name and columns of CREATE INDEX statements either.
+var createIndexRegex = regexp.MustCompile("(?is)^\\s*create\\s+(unique\\s+)?index\\s+(`[^`]+`|\\S+)\\s+on\\s+(?:`[^`]+`|[^\\s(]+)\\s*\\(([^)]*)\\)[\\s;]*$")
+
+// The sql parser keeps the table options of a CREATE TABLE statement as unparsed text, with the quotes of string
+// values removed, and doesn't parse the options of an ALTER TABLE statement at all. A table comment is matched in
+// either of them here.
+var tableOptionCommentRegex = regexp.MustCompile("(?is)(?:^|\\s)comment\\s*=?\\s*'(.*)'")
+var alterTableCommentRegex = regexp.MustCompile("(?is)^\\s*alter\\s+table\\s+(?:`[^`]+`|\\S+)\\s+comment\\s*=?\\s*'((?:[^'\\\\]|''|\\\\.)*)'[\\s;]*$")
+
 // ExecuteAlter executes the given alter table statement and returns the new root value of the database.
 func ExecuteAlter(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, ddl *sqlparser.DDL, query string) (*doltdb.RootValue, error) {
 	// Unlike other SQL statements, DDL statements can have an error but still return a statement from Parse().
@@ -79,6 +95,8 @@ func executeRename(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValu
 		if root, err = alterschema.RenameTable(ctx, db, root, fromTable.Name.String(), toTable.Name.String()); err != nil {
 			if err == doltdb.ErrTableExists {
 				return nil, errFmt("A table with the name '%v' already exists", toTable.Name.String())
+			} else if err == doltdb.ErrInvTableName {
+				return nil, errFmt("Invalid table name: '%v'", toTable.Name.String())
 			}
 			return nil, err
 		}
@@ -117,6 +135,18 @@ func executeAlter(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue
 	case sqlparser.RenameStr:
 		return renameColumn(ctx, db, root, tableName, ddl.Column, ddl.ToColumn)
 	default:
+		if matches := createIndexRegex.FindStringSubmatch(query); matches != nil {
+			if len(matches[1]) > 0 {
+				return nil, errFmt("Unique indexes are not supported: '%v'", query)
+			}
+			return createIndex(ctx, root, tableName, strings.Trim(matches[2], "`"), matches[3])
+		}
+		if matches := modifyColumnRegex.FindStringSubmatch(query); matches != nil {
+			return modifyColumn(ctx, db, root, tableName, strings.ToLower(matches[1]), matches[2])
+		}
+		if matches := alterTableCommentRegex.FindStringSubmatch(query); matches != nil {
+			return setTableComment(ctx, db, root, tableName, unescapeSingleQuoted(matches[1]))
+		}
 		return nil, errFmt("Unsupported alter table statement: '%v'", query)
 	}
 }
@@ -129,7 +159,19 @@ func renameColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue
 		return nil, err
 	}
 
-	updatedTable, err := alterschema.RenameColumn(ctx, db, table, fromCol.String(), toCol.String())
+	sch, err := table.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Column names are case insensitive in SQL
+	colName := fromCol.String()
+	if existing, ok := sch.GetAllCols().GetByNameCaseInsensitive(colName); ok {
+		colName = existing.Name
+	}
+
+	updatedTable, err := alterschema.RenameColumn(ctx, db, table, colName, toCol.String())
 	if err != nil {
 		if err == schema.ErrColNotFound {
 			return nil, errFmt(UnknownColumnErrFmt, fromCol.String())
@@ -142,6 +184,161 @@ func renameColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue
 	return root.PutTable(ctx, tableName, updatedTable)
 }
 
+// modifyColumn replaces a column of the table named with a new definition, given by the text following MODIFY COLUMN
+// or CHANGE COLUMN in an alter table statement. MODIFY keeps the column's name, while CHANGE names the column to
+// replace before the new definition. The column keeps its tag, and its values are converted to the new type. Returns
+// the new root value, or an error if one occurs, including when any of the column's values can't be converted.
+func modifyColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, tableName, action, colSpec string) (*doltdb.RootValue, error) {
+	table, _, err := root.GetTable(ctx, tableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sch, err := table.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	oldColName, colDefStr := "", colSpec
+	if action == "change" {
+		matches := columnNameRegex.FindStringSubmatch(colSpec)
+		if matches == nil {
+			return nil, errFmt("Invalid change column statement: '%v'", colSpec)
+		}
+		oldColName, colDefStr = strings.Trim(matches[1], "`"), matches[2]
+	}
+
+	// Parse the column definition as if it were being added to the table, quoting the column name so that names
+	// which are keywords, like uuid, can be used
+	matches := columnNameRegex.FindStringSubmatch(colDefStr)
+	if matches == nil {
+		return nil, errFmt("Invalid column definition: '%v'", colDefStr)
+	}
+	stmt, err := sqlparser.ParseStrictDDL("alter table t add column `" + strings.Trim(matches[1], "`") + "` " + matches[2])
+	if err != nil {
+		return nil, errFmt("Invalid column definition: '%v'", colDefStr)
+	}
+
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.TableSpec == nil || len(ddl.TableSpec.Columns) != 1 {
+		return nil, errFmt("Invalid column definition: '%v'", colDefStr)
+	}
+
+	col, _, err := getColumn(ddl.TableSpec.Columns[0], ddl.TableSpec.Indexes, schema.InvalidTag)
+	if err != nil {
+		return nil, err
+	}
+
+	if action == "modify" {
+		oldColName = col.Name
+	}
+
+	// Column names are case insensitive in SQL
+	existing, ok := sch.GetAllCols().GetByNameCaseInsensitive(oldColName)
+	if !ok {
+		return nil, errFmt(UnknownColumnErrFmt, oldColName)
+	}
+
+	updatedTable, err := alterschema.ModifyColumn(ctx, db, table, existing.Name, col)
+	if err != nil {
+		if err == schema.ErrColNotFound {
+			return nil, errFmt(UnknownColumnErrFmt, oldColName)
+		} else if err == schema.ErrColNameCollision {
+			return nil, errFmt("A column with the name '%v' already exists", col.Name)
+		}
+		return nil, err
+	}
+
+	return root.PutTable(ctx, tableName, updatedTable)
+}
+
+// setTableComment sets the comment documenting the table named. Returns the new root value, or an error if one
+// occurs.
+func setTableComment(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, tableName, comment string) (*doltdb.RootValue, error) {
+	table, _, err := root.GetTable(ctx, tableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updatedTable, err := alterschema.SetTableComment(ctx, db, table, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return root.PutTable(ctx, tableName, updatedTable)
+}
+
+// unescapeSingleQuoted returns the value of the text of a single quoted SQL string literal.
+func unescapeSingleQuoted(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if (s[i] == '\\' || s[i] == '\'') && i+1 < len(s) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// createIndex creates an index with the name given of the columns in the comma-separated list given on the table
+// named. Returns the new root value, or an error if one occurs.
+func createIndex(ctx context.Context, root *doltdb.RootValue, tableName, indexName, colList string) (*doltdb.RootValue, error) {
+	table, _, err := root.GetTable(ctx, tableName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sch, err := table.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Index names are case insensitive in SQL
+	indexes, err := table.GetIndexes(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, idx := range indexes {
+		if strings.EqualFold(idx.Name, indexName) {
+			return nil, errFmt("An index with the name '%v' already exists on table '%v'", indexName, tableName)
+		}
+	}
+
+	var tags []uint64
+	seen := make(map[uint64]bool)
+	for _, colName := range strings.Split(colList, ",") {
+		colName = strings.TrimSpace(colName)
+		if strings.ContainsAny(colName, " \t\n(") {
+			return nil, errFmt("Unsupported index column: '%v'", colName)
+		}
+
+		colName = strings.Trim(colName, "`")
+		col, ok := sch.GetAllCols().GetByNameCaseInsensitive(colName)
+		if !ok {
+			return nil, errFmt(UnknownColumnErrFmt, colName)
+		} else if seen[col.Tag] {
+			return nil, errFmt("Duplicate column name '%v' in index", colName)
+		}
+
+		seen[col.Tag] = true
+		tags = append(tags, col.Tag)
+	}
+
+	updatedTable, err := table.CreateIndex(ctx, indexName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	return root.PutTable(ctx, tableName, updatedTable)
+}
+
 // dropColumn drops the column named from the table named. Returns the new root value and new schema, or an error if one occurs.
 func dropColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, tableName string, col sqlparser.ColIdent) (*doltdb.RootValue, error) {
 	table, _, err := root.GetTable(ctx, tableName)
@@ -150,7 +347,19 @@ func dropColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue,
 		return nil, err
 	}
 
-	updatedTable, err := alterschema.DropColumn(ctx, db, table, col.String())
+	sch, err := table.GetSchema(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Column names are case insensitive in SQL
+	colName := col.String()
+	if existing, ok := sch.GetAllCols().GetByNameCaseInsensitive(colName); ok {
+		colName = existing.Name
+	}
+
+	updatedTable, err := alterschema.DropColumn(ctx, db, table, colName)
 	if err != nil {
 		if err == schema.ErrColNotFound {
 			return nil, errFmt(UnknownColumnErrFmt, col.String())
@@ -161,7 +370,10 @@ func dropColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue,
 	return root.PutTable(ctx, tableName, updatedTable)
 }
 
-// addColumn adds the column given to the table named. Returns the new root value and new schema, or an error if one occurs.
+// addColumn adds the column given to the table named. Returns the new root value and new schema, or an error if one
+// occurs. Existing rows get the column's default value, if it has one. Unless the column definition specifies a tag,
+// the new column gets a tag derived from the table name and the column's name and type, so that the same column added
+// on different branches has the same tag when the branches are merged.
 func addColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, tableName string, spec *sqlparser.TableSpec) (*doltdb.RootValue, error) {
 	table, _, err := root.GetTable(ctx, tableName)
 
@@ -175,16 +387,20 @@ func addColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, t
 		return nil, err
 	}
 
-	tag := schema.AutoGenerateTag(sch)
-
 	colDef := spec.Columns[0]
-	col, defaultVal, err := getColumn(colDef, spec.Indexes, tag)
+	col, defaultVal, err := getColumn(colDef, spec.Indexes, schema.InvalidTag)
 	if err != nil {
 		return nil, err
 	}
 	if col.IsPartOfPK {
 		return nil, errFmt("Adding primary keys is not supported")
 	}
+	if _, ok := sch.GetAllCols().GetByNameCaseInsensitive(col.Name); ok {
+		return nil, errFmt("A column with the name '%v' already exists", col.Name)
+	}
+	if col.Tag == schema.InvalidTag {
+		col.Tag = schema.DeterministicTag(sch, tableName, col.Name, col.Kind)
+	}
 
 	nullable := alterschema.NotNull
 	if col.IsNullable() {
@@ -196,6 +412,13 @@ func addColumn(ctx context.Context, db *doltdb.DoltDB, root *doltdb.RootValue, t
 		return nil, err
 	}
 
+	if col.Comment != "" {
+		updatedTable, err = alterschema.SetColumnComment(ctx, db, updatedTable, col.Name, col.Comment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return root.PutTable(ctx, tableName, updatedTable)
 }
 
@@ -226,7 +449,7 @@ func getSchema(spec *sqlparser.TableSpec) (schema.Schema, error) {
 		return nil, err
 	}
 
-	return schema.SchemaFromCols(colColl), nil
+	return schema.SchemaWithComment(schema.SchemaFromCols(colColl), TableCommentFromOptions(spec.Options)), nil
 }
 
 // fakeResolver satisfies the TagResolver interface to let us fetch a value from a RowValGetter, without needing an
@@ -324,6 +547,7 @@ func getColumn(colDef *sqlparser.ColumnDefinition, indexes []*sqlparser.IndexDef
 	}
 
 	column := schema.NewColumn(colDef.Name.String(), tag, colKind, isPkey, constraints...)
+	column.Comment = extractComment(columnType)
 
 	if colDef.Type.Default == nil {
 		return column, nil, nil
@@ -370,27 +594,51 @@ func getColumn(colDef *sqlparser.ColumnDefinition, indexes []*sqlparser.IndexDef
 
 // Extracts the optional comment tag from a column type defn, or InvalidTag if it can't be extracted
 func extractTag(columnType sqlparser.ColumnType) uint64 {
+	_, tag := splitComment(columnType)
+	return tag
+}
+
+// Extracts the comment documenting a column from a column type defn, which is the text of its comment preceding the
+// tag, if it has one.
+func extractComment(columnType sqlparser.ColumnType) string {
+	comment, _ := splitComment(columnType)
+	return comment
+}
+
+// splitComment splits the comment of a column type defn into the text documenting the column and the column's tag,
+// which is InvalidTag if the comment doesn't end with one.
+func splitComment(columnType sqlparser.ColumnType) (string, uint64) {
 	if columnType.Comment == nil {
-		return schema.InvalidTag
+		return "", schema.InvalidTag
 	}
 
 	sqlVal := columnType.Comment
 	if sqlVal.Type != sqlparser.StrVal {
-		return schema.InvalidTag
+		return "", schema.InvalidTag
 	}
 
 	commentString := string(sqlVal.Val)
-	i := strings.Index(commentString, tagCommentPrefix)
+	i := strings.LastIndex(commentString, tagCommentPrefix)
 	if i >= 0 {
 		startIdx := i + len(tagCommentPrefix)
 		tag, err := strconv.ParseUint(commentString[startIdx:], 10, 64)
 		if err != nil {
-			return schema.InvalidTag
+			return commentString, schema.InvalidTag
 		}
-		return tag
+		return strings.TrimSpace(commentString[:i]), tag
 	}
 
-	return schema.InvalidTag
+	return commentString, schema.InvalidTag
+}
+
+// TableCommentFromOptions returns the comment given in the table options of a create table statement, or the empty
+// string if there isn't one.
+func TableCommentFromOptions(options string) string {
+	matches := tableOptionCommentRegex.FindStringSubmatch(options)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
 }
 
 func errColumn(errFmt string, args ...interface{}) (schema.Column, types.Value, error) {
@@ -18,10 +18,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"vitess.io/vitess/go/vt/sqlparser"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
@@ -87,6 +89,30 @@ func TestAddColumn(t *testing.T) {
 				schema.NewColumn("newColumn", 100, types.StringKind, false)),
 			expectedRows: AllPeopleRows,
 		},
+		{
+			name:  "alter add column nullable with default",
+			query: "alter table people add (newColumn bigint default 42 comment 'tag:100')",
+			expectedSchema: dtestutils.AddColumnToSchema(PeopleTestSchema,
+				schema.NewColumn("newColumn", 100, types.IntKind, false)),
+			expectedRows: dtestutils.AddColToRows(t, AllPeopleRows, 100, types.Int(42)),
+		},
+		{
+			name:  "alter add column without tag",
+			query: "alter table people add column newColumn varchar(80)",
+			expectedSchema: dtestutils.AddColumnToSchema(PeopleTestSchema,
+				schema.NewColumn("newColumn", newColumnTag(types.StringKind), types.StringKind, false)),
+			expectedRows: AllPeopleRows,
+		},
+		{
+			name:        "alter add column with name conflict",
+			query:       "alter table people add (AGE int comment 'tag:100')",
+			expectedErr: "A column with the name 'AGE' already exists",
+		},
+		{
+			name:        "alter add primary key column",
+			query:       "alter table people add (newColumn int primary key comment 'tag:100')",
+			expectedErr: "Adding primary keys is not supported",
+		},
 	}
 
 	for _, tt := range tests {
@@ -138,6 +164,11 @@ func TestAddColumn(t *testing.T) {
 	}
 }
 
+// newColumnTag returns the tag that a column named newColumn of the kind given gets when added to the people table.
+func newColumnTag(kind types.NomsKind) uint64 {
+	return schema.DeterministicTag(PeopleTestSchema, PeopleTableName, "newColumn", kind)
+}
+
 func TestUnsupportedAlterStatements(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -150,9 +181,9 @@ func TestUnsupportedAlterStatements(t *testing.T) {
 			expectedErr: "Unsupported",
 		},
 		{
-			name:        "create index",
-			query:       "create index myidx on people (id, first)",
-			expectedErr: "Unsupported",
+			name:        "create unique index",
+			query:       "create unique index myidx on people (id, first)",
+			expectedErr: "Unique indexes are not supported",
 		},
 		{
 			name:        "alter drop index",
@@ -164,11 +195,6 @@ func TestUnsupportedAlterStatements(t *testing.T) {
 			query:       "drop index myidx on people",
 			expectedErr: "Unsupported",
 		},
-		{
-			name:        "alter change column",
-			query:       "alter table people change id newId (varchar(80) not null)",
-			expectedErr: "Unsupported",
-		},
 		{
 			name:        "alter add foreign key",
 			query:       "alter table appearances add constraint people_id_ref foreign key (id) references people (id)",
@@ -215,6 +241,12 @@ func TestDropColumn(t *testing.T) {
 			expectedSchema: dtestutils.RemoveColumnFromSchema(PeopleTestSchema, RatingTag),
 			expectedRows:   dtestutils.ConvertToSchema(dtestutils.RemoveColumnFromSchema(PeopleTestSchema, RatingTag), AllPeopleRows...),
 		},
+		{
+			name:           "alter drop column case insensitive",
+			query:          "alter table people drop column RATING",
+			expectedSchema: dtestutils.RemoveColumnFromSchema(PeopleTestSchema, RatingTag),
+			expectedRows:   dtestutils.ConvertToSchema(dtestutils.RemoveColumnFromSchema(PeopleTestSchema, RatingTag), AllPeopleRows...),
+		},
 		{
 			name:        "drop primary key",
 			query:       "alter table people drop column id",
@@ -403,6 +435,320 @@ func TestRenameColumn(t *testing.T) {
 	}
 }
 
+func TestModifyColumn(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedSchema schema.Schema
+		expectedRows   []row.Row
+		expectedErr    string
+	}{
+		{
+			name:  "alter modify column type",
+			query: "alter table people modify column uuid varchar(40)",
+			expectedSchema: dtestutils.CreateSchema(
+				schema.NewColumn("id", IdTag, types.IntKind, true, schema.NotNullConstraint{}),
+				schema.NewColumn("first", FirstTag, types.StringKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("last", LastTag, types.StringKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("is_married", IsMarriedTag, types.BoolKind, false),
+				schema.NewColumn("age", AgeTag, types.IntKind, false),
+				schema.NewColumn("rating", RatingTag, types.FloatKind, false),
+				schema.NewColumn("uuid", UuidTag, types.StringKind, false),
+				schema.NewColumn("num_episodes", NumEpisodesTag, types.UintKind, false),
+			),
+			expectedRows: convertUuids(AllPeopleRows),
+		},
+		{
+			name:  "alter change column",
+			query: "alter table people change column AGE years bigint not null",
+			expectedSchema: dtestutils.CreateSchema(
+				schema.NewColumn("id", IdTag, types.IntKind, true, schema.NotNullConstraint{}),
+				schema.NewColumn("first", FirstTag, types.StringKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("last", LastTag, types.StringKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("is_married", IsMarriedTag, types.BoolKind, false),
+				schema.NewColumn("years", AgeTag, types.IntKind, false, schema.NotNullConstraint{}),
+				schema.NewColumn("rating", RatingTag, types.FloatKind, false),
+				schema.NewColumn("uuid", UuidTag, types.UUIDKind, false),
+				schema.NewColumn("num_episodes", NumEpisodesTag, types.UintKind, false),
+			),
+			expectedRows: AllPeopleRows,
+		},
+		{
+			name:        "values can't be converted",
+			query:       "alter table people modify first int",
+			expectedErr: "Cannot modify column first: 6 rows have values that can't be converted to Int, with primary keys (0), (1), (2), (3), (4), (5)",
+		},
+		{
+			name:        "null values with not null",
+			query:       "alter table people modify num_episodes bigint unsigned not null",
+			expectedErr: "Cannot modify column num_episodes: 1 rows have values that can't be converted to Uint NOT NULL, with primary keys (0)",
+		},
+		{
+			name:        "primary key column",
+			query:       "alter table people change id newId varchar(80) not null",
+			expectedErr: "Cannot modify column in primary key",
+		},
+		{
+			name:        "column not found",
+			query:       "alter table people modify column notFound int",
+			expectedErr: "Unknown column: 'notFound'",
+		},
+		{
+			name:        "column name collision",
+			query:       "alter table people change column age first int",
+			expectedErr: "A column with the name 'first' already exists",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			ctx := context.Background()
+			root, _ := dEnv.WorkingRoot(ctx)
+
+			sqlStatement, err := sqlparser.Parse(tt.query)
+			require.NoError(t, err)
+
+			s := sqlStatement.(*sqlparser.DDL)
+
+			updatedRoot, err := ExecuteAlter(ctx, dEnv.DoltDB, root, s, tt.query)
+
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			require.NotNil(t, updatedRoot)
+			updatedTable, ok, err := updatedRoot.GetTable(ctx, PeopleTableName)
+			assert.NoError(t, err)
+			require.True(t, ok)
+			sch, err := updatedTable.GetSchema(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSchema, sch)
+
+			rowData, err := updatedTable.GetRowData(ctx)
+			assert.NoError(t, err)
+			var foundRows []row.Row
+			err = rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+				r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+				assert.NoError(t, err)
+				foundRows = append(foundRows, r)
+				return false, nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRows, foundRows)
+		})
+	}
+}
+
+// convertUuids returns the rows given with their uuid values converted to strings
+func convertUuids(rows []row.Row) []row.Row {
+	converted := make([]row.Row, len(rows))
+	for i, r := range rows {
+		converted[i] = r
+		if val, ok := r.GetColVal(UuidTag); ok {
+			converted[i], _ = r.SetColVal(UuidTag, types.String(uuid.UUID(val.(types.UUID)).String()), PeopleTestSchema)
+		}
+	}
+	return converted
+}
+
+func TestCreateIndex(t *testing.T) {
+	tests := []struct {
+		name            string
+		queries         []string
+		expectedIndexes []doltdb.Index
+		expectedErr     string
+	}{
+		{
+			name:            "create index",
+			queries:         []string{"create index myidx on people (last, FIRST)"},
+			expectedIndexes: []doltdb.Index{{Name: "myidx", Tags: []uint64{LastTag, FirstTag}}},
+		},
+		{
+			name:            "quoted names",
+			queries:         []string{"create index `my idx` on `people` (`age`);"},
+			expectedIndexes: []doltdb.Index{{Name: "my idx", Tags: []uint64{AgeTag}}},
+		},
+		{
+			name:    "two indexes",
+			queries: []string{"create index idx2 on people (rating)", "create index idx1 on people (age, rating)"},
+			expectedIndexes: []doltdb.Index{
+				{Name: "idx1", Tags: []uint64{AgeTag, RatingTag}},
+				{Name: "idx2", Tags: []uint64{RatingTag}},
+			},
+		},
+		{
+			name:        "duplicate name",
+			queries:     []string{"create index myidx on people (age)", "create index MYIDX on people (rating)"},
+			expectedErr: "An index with the name 'MYIDX' already exists on table 'people'",
+		},
+		{
+			name:        "column not found",
+			queries:     []string{"create index myidx on people (age, notFound)"},
+			expectedErr: "Unknown column: 'notFound'",
+		},
+		{
+			name:        "duplicate column",
+			queries:     []string{"create index myidx on people (age, AGE)"},
+			expectedErr: "Duplicate column name 'AGE' in index",
+		},
+		{
+			name:        "table not found",
+			queries:     []string{"create index myidx on notFound (age)"},
+			expectedErr: "Unknown table: 'notFound'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			ctx := context.Background()
+			root, _ := dEnv.WorkingRoot(ctx)
+
+			var err error
+			for _, query := range tt.queries {
+				sqlStatement, parseErr := sqlparser.Parse(query)
+				require.NoError(t, parseErr)
+
+				var updatedRoot *doltdb.RootValue
+				updatedRoot, err = ExecuteAlter(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.DDL), query)
+				if err != nil {
+					break
+				}
+				root = updatedRoot
+			}
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			table, ok, err := root.GetTable(ctx, PeopleTableName)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			indexes, err := table.GetIndexes(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIndexes, indexes)
+
+			for _, idx := range indexes {
+				data, ok, err := table.GetIndexData(ctx, idx.Name)
+				require.NoError(t, err)
+				require.True(t, ok)
+				assert.Equal(t, uint64(len(AllPeopleRows)), data.Len())
+			}
+		})
+	}
+}
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		name                 string
+		queries              []string
+		expectedTableComment string
+		expectedColComments  map[string]string
+		expectedErr          string
+	}{
+		{
+			name:                 "table comment",
+			queries:              []string{"alter table people comment='The people'"},
+			expectedTableComment: "The people",
+		},
+		{
+			name:                 "table comment without equals",
+			queries:              []string{"alter table `people` comment 'It''s \\\\ the people';"},
+			expectedTableComment: "It's \\ the people",
+		},
+		{
+			name:                 "replaced table comment",
+			queries:              []string{"alter table people comment='The people'", "alter table people comment=''"},
+			expectedTableComment: "",
+		},
+		{
+			name:                 "table comment kept by other alter statements",
+			queries:              []string{"alter table people comment='The people'", "alter table people add column nickname varchar(20)", "alter table people drop column rating"},
+			expectedTableComment: "The people",
+		},
+		{
+			name:                "added column comment",
+			queries:             []string{"alter table people add column nickname varchar(20) comment 'What friends call them'"},
+			expectedColComments: map[string]string{"nickname": "What friends call them"},
+		},
+		{
+			name:                "added column comment with tag",
+			queries:             []string{"alter table people add column nickname varchar(20) comment 'What friends call them tag:1234'"},
+			expectedColComments: map[string]string{"nickname": "What friends call them"},
+		},
+		{
+			name:                "modified column comment",
+			queries:             []string{"alter table people modify column age bigint comment 'In years'"},
+			expectedColComments: map[string]string{"age": "In years"},
+		},
+		{
+			name:                "renamed column keeps comment",
+			queries:             []string{"alter table people modify column age bigint comment 'In years'", "alter table people rename column age to years"},
+			expectedColComments: map[string]string{"years": "In years"},
+		},
+		{
+			name:        "table not found",
+			queries:     []string{"alter table notFound comment='comment'"},
+			expectedErr: "Unknown table: 'notFound'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			ctx := context.Background()
+			root, _ := dEnv.WorkingRoot(ctx)
+
+			var err error
+			for _, query := range tt.queries {
+				sqlStatement, parseErr := sqlparser.Parse(query)
+				require.NoError(t, parseErr)
+
+				var updatedRoot *doltdb.RootValue
+				updatedRoot, err = ExecuteAlter(ctx, dEnv.DoltDB, root, sqlStatement.(*sqlparser.DDL), query)
+				if err != nil {
+					break
+				}
+				root = updatedRoot
+			}
+
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			table, ok, err := root.GetTable(ctx, PeopleTableName)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			sch, err := table.GetSchema(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTableComment, sch.GetComment())
+
+			for colName, comment := range tt.expectedColComments {
+				col, ok := sch.GetAllCols().GetByName(colName)
+				require.True(t, ok)
+				assert.Equal(t, comment, col.Comment)
+			}
+		})
+	}
+}
+
 func TestRenameTable(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -447,6 +793,11 @@ func TestRenameTable(t *testing.T) {
 			query:       "rename table people to appearances",
 			expectedErr: "A table with the name 'appearances' already exists",
 		},
+		{
+			name:        "invalid table name",
+			query:       "rename table people to `new people`",
+			expectedErr: "Invalid table name: 'new people'",
+		},
 	}
 
 	for _, tt := range tests {
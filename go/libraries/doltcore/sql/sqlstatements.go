@@ -67,7 +67,13 @@ func SchemaAsCreateStmt(tableName string, sch schema.Schema) string {
 		panic(err)
 	}
 
-	sb.WriteString(")\n);")
+	sb.WriteString(")\n)")
+
+	if comment := sch.GetComment(); comment != "" {
+		fmt.Fprintf(sb, " COMMENT='%s'", escapeSingleQuoted(comment))
+	}
+
+	sb.WriteString(";")
 	return sb.String()
 }
 
@@ -97,6 +103,16 @@ func AlterTableAddColStmt(tableName string, newColDef string) string {
 	return b.String()
 }
 
+func AlterTableModifyColStmt(tableName string, newColDef string) string {
+	var b strings.Builder
+	b.WriteString("ALTER TABLE ")
+	b.WriteString(QuoteIdentifier(tableName))
+	b.WriteString(" MODIFY COLUMN ")
+	b.WriteString(newColDef)
+	b.WriteRune(';')
+	return b.String()
+}
+
 func AlterTableDropColStmt(tableName string, oldColName string) string {
 	var b strings.Builder
 	b.WriteString("ALTER TABLE ")
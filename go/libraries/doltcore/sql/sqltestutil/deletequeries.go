@@ -112,6 +112,20 @@ var BasicDeleteTests = []DeleteTest{
 		ExpectedRows:   CompressRows(PeopleTestSchema, Moe, Barney),
 		ExpectedSchema: CompressSchema(PeopleTestSchema),
 	},
+	{
+		Name:           "delete where is_married false",
+		DeleteQuery:    "delete from people where is_married = false",
+		SelectQuery:    "select * from people",
+		ExpectedRows:   CompressRows(PeopleTestSchema, Homer, Marge),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:           "delete where compound filter",
+		DeleteQuery:    "delete from people where is_married = false and (age < 10 or last <> 'Simpson')",
+		SelectQuery:    "select * from people",
+		ExpectedRows:   CompressRows(PeopleTestSchema, Homer, Marge, Bart),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
 	{
 		Name:           "delete order by",
 		DeleteQuery:    "delete from people order by id",
@@ -52,6 +52,15 @@ var BasicInsertTests = []InsertTest{
 		ExpectedRows:   CompressRows(PeopleTestSchema, Bart),
 		ExpectedSchema: CompressSchema(PeopleTestSchema),
 	},
+	{
+		Name: "insert no columns multiple rows",
+		InsertQuery: `insert into people values
+					(2, 'Bart', 'Simpson', false, 10, 9, '00000000-0000-0000-0000-000000000002', 222),
+					(3, 'Lisa', 'Simpson', false, 8, 10, '00000000-0000-0000-0000-000000000003', 333)`,
+		SelectQuery:    "select * from people where id in (2, 3) order by id",
+		ExpectedRows:   CompressRows(PeopleTestSchema, Bart, Lisa),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
 	{
 		Name:        "insert no columns too few values",
 		InsertQuery: "insert into people values (2, 'Bart', 'Simpson', false, 10, 9, '00000000-0000-0000-0000-000000000002')",
@@ -105,6 +114,13 @@ var BasicInsertTests = []InsertTest{
 		ExpectedRows:   Rs(NewResultSetRow(types.Int(2), types.String("Bart"), types.String("Simpson"))),
 		ExpectedSchema: NewResultSetSchema("id", types.IntKind, "first", types.StringKind, "last", types.StringKind),
 	},
+	{
+		Name:           "insert partial columns omitted columns null",
+		InsertQuery:    "insert into people (id, first, last) values (2, 'Bart', 'Simpson')",
+		SelectQuery:    "select * from people where id = 2",
+		ExpectedRows:   Rs(NewResultSetRow(types.Int(2), types.String("Bart"), types.String("Simpson"))),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
 	{
 		Name:        "insert partial columns duplicate column",
 		InsertQuery: "insert into people (id, first, last, first) values (2, 'Bart', 'Simpson', 'Bart')",
@@ -118,7 +134,7 @@ var BasicInsertTests = []InsertTest{
 	{
 		Name:        "insert missing non-nullable column",
 		InsertQuery: "insert into people (id, first) values (2, 'Bart')",
-		ExpectedErr: "column <last> received nil but is non-nullable",
+		ExpectedErr: "column name 'last' is non-nullable but attempted to set default value of null",
 	},
 	{
 		Name:        "insert partial columns mismatch too many values",
@@ -164,6 +180,18 @@ var BasicInsertTests = []InsertTest{
 		ExpectedSchema: NewResultSetSchema("id", types.IntKind, "first", types.StringKind, "last", types.StringKind,
 			"is_married", types.BoolKind, "age", types.IntKind, "rating", types.FloatKind),
 	},
+	{
+		Name: "insert partial columns from select",
+		AdditionalSetup: CreateTableFn("temppeople",
+			NewSchema("id", types.IntKind, "first", types.StringKind, "last", types.StringKind),
+			NewRow(types.Int(2), types.String("Bart"), types.String("Simpson")),
+			NewRow(types.Int(3), types.String("Lisa"), types.String("Simpson"))),
+		InsertQuery: "insert into people (id, first, last) select id, first, last from temppeople",
+		SelectQuery: "select id, first, last from people where id > 1 order by id",
+		ExpectedRows: Rs(NewResultSetRow(types.Int(2), types.String("Bart"), types.String("Simpson")),
+			NewResultSetRow(types.Int(3), types.String("Lisa"), types.String("Simpson"))),
+		ExpectedSchema: NewResultSetSchema("id", types.IntKind, "first", types.StringKind, "last", types.StringKind),
+	},
 	{
 		Name: "insert ignore partial columns multiple rows null constraint failure",
 		InsertQuery: `insert ignore into people (id, first, last, is_married, age, rating) values
@@ -176,7 +204,8 @@ var BasicInsertTests = []InsertTest{
 		SkipOnSqlEngine: true,
 	},
 	{
-		Name: "insert ignore partial columns multiple rows existing pk",
+		Name:            "insert ignore partial columns multiple rows existing pk",
+		AdditionalSetup: CreateTableFn(PeopleTableName, PeopleTestSchema, Homer),
 		InsertQuery: `insert ignore into people (id, first, last, is_married, age, rating) values
 					(0, "Homer", "Simpson", true, 45, 100),
 					(8, "Milhouse", "Van Houten", false, 8, 8.5)`,
@@ -187,7 +216,6 @@ var BasicInsertTests = []InsertTest{
 		),
 		ExpectedSchema: NewResultSetSchema("id", types.IntKind, "first", types.StringKind, "last", types.StringKind,
 			"is_married", types.BoolKind, "age", types.IntKind, "rating", types.FloatKind),
-		SkipOnSqlEngine: true,
 	},
 	{
 		Name: "insert ignore partial columns multiple rows duplicate pk",
@@ -198,12 +226,41 @@ var BasicInsertTests = []InsertTest{
 		ExpectedRows: CompressRows(PeopleTestSchema, NewPeopleRow(7, "Maggie", "Simpson", false, 1, 5.1)),
 		ExpectedSchema: NewResultSetSchema("id", types.IntKind, "first", types.StringKind, "last", types.StringKind,
 			"is_married", types.BoolKind, "age", types.IntKind, "rating", types.FloatKind),
-		SkipOnSqlEngine: true,
+	},
+	{
+		Name:            "insert on duplicate key update existing pk",
+		AdditionalSetup: CreateTableFn(PeopleTableName, PeopleTestSchema, Homer),
+		InsertQuery: `insert into people (id, first, last, is_married, age, rating) values
+					(0, "Homer", "Simpson", true, 45, 100),
+					(8, "Milhouse", "Van Houten", false, 8, 3.5)
+					on duplicate key update age = values(age), rating = rating + 1`,
+		SelectQuery: "select id, first, last, is_married, age, rating from people order by id",
+		ExpectedRows: CompressRows(PeopleTestSchema,
+			MutateRow(Homer, AgeTag, 45, RatingTag, 9.5),
+			NewPeopleRow(8, "Milhouse", "Van Houten", false, 8, 3.5),
+		),
+		ExpectedSchema: NewResultSetSchema("id", types.IntKind, "first", types.StringKind, "last", types.StringKind,
+			"is_married", types.BoolKind, "age", types.IntKind, "rating", types.FloatKind),
+	},
+	{
+		Name: "insert on duplicate key update duplicate pk",
+		InsertQuery: `insert into people (id, first, last) values
+					(7, "Maggie", "Simpson"),
+					(7, "Milhouse", "Van Houten")
+					on duplicate key update first = values(first), last = values(last)`,
+		SelectQuery:    "select id, first, last from people where id = 7",
+		ExpectedRows:   Rs(NewResultSetRow(types.Int(7), types.String("Milhouse"), types.String("Van Houten"))),
+		ExpectedSchema: NewResultSetSchema("id", types.IntKind, "first", types.StringKind, "last", types.StringKind),
+	},
+	{
+		Name:        "insert on duplicate key update from select",
+		InsertQuery: "insert into people (id, first, last) select id, first, last from people on duplicate key update age = 1",
+		ExpectedErr: "ON DUPLICATE KEY UPDATE is only supported for inserts with a VALUES list",
 	},
 	{
 		Name:        "insert partial columns multiple rows null pk",
 		InsertQuery: "insert into people (id, first, last) values (0, 'Bart', 'Simpson'), (1, 'Homer', null)",
-		ExpectedErr: "column <last> received nil but is non-nullable",
+		ExpectedErr: "column name 'last' is non-nullable but attempted to set a value of null",
 	},
 	{
 		Name:        "insert partial columns multiple rows duplicate",
@@ -126,7 +126,7 @@ var BasicReplaceTests = []ReplaceTest{
 	{
 		Name:         "replace missing non-nullable column",
 		ReplaceQuery: "replace into people (id, first) values (2, 'Bart')",
-		ExpectedErr:  "column <last> received nil but is non-nullable",
+		ExpectedErr:  "column name 'last' is non-nullable but attempted to set default value of null",
 	},
 	{
 		Name:         "replace partial columns mismatch too many values",
@@ -175,7 +175,7 @@ var BasicReplaceTests = []ReplaceTest{
 	{
 		Name:         "replace partial columns multiple rows null pk",
 		ReplaceQuery: "replace into people (id, first, last) values (0, 'Bart', 'Simpson'), (1, 'Homer', null)",
-		ExpectedErr:  "column <last> received nil but is non-nullable",
+		ExpectedErr:  "column name 'last' is non-nullable but attempted to set a value of null",
 	},
 	{
 		Name:           "replace partial columns multiple rows duplicate",
@@ -213,7 +213,7 @@ var BasicReplaceTests = []ReplaceTest{
 					(0, "Homer", "Simpson", true, 45, 100),
 					(8, "Milhouse", "Van Houten", false, 8, 3.5),
 					(7, "Maggie", null, false, 1, 5.1)`,
-		ExpectedErr: "Constraint failed for column 'last': Not null",
+		ExpectedErr: "column name 'last' is non-nullable but attempted to set a value of null",
 	},
 	{
 		Name: "type mismatch int -> string",
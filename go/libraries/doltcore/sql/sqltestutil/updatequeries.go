@@ -157,6 +157,31 @@ var BasicUpdateTests = []UpdateTest{
 		),
 		ExpectedSchema: CompressSchema(PeopleTestSchema),
 	},
+	{
+		Name:        "update increment age where clause",
+		UpdateQuery: `update people set age = age + 1 where last = 'Simpson'`,
+		SelectQuery: `select * from people`,
+		ExpectedRows: CompressRows(PeopleTestSchema,
+			MutateRow(Homer, AgeTag, 41),
+			MutateRow(Marge, AgeTag, 39),
+			MutateRow(Bart, AgeTag, 11),
+			MutateRow(Lisa, AgeTag, 9),
+			Moe,
+			Barney,
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
+	{
+		Name:        "update columns from expressions of several columns",
+		UpdateQuery: `update people set first = concat(first, ' ', last), age = age + id where id < 2`,
+		SelectQuery: `select * from people where id < 3`,
+		ExpectedRows: CompressRows(PeopleTestSchema,
+			MutateRow(Homer, FirstTag, "Homer Simpson", AgeTag, 40),
+			MutateRow(Marge, FirstTag, "Marge Simpson", AgeTag, 39),
+			Bart,
+		),
+		ExpectedSchema: CompressSchema(PeopleTestSchema),
+	},
 	{
 		Name:        "update reverse rating",
 		UpdateQuery: `update people set rating = -rating`,
@@ -282,7 +307,7 @@ var BasicUpdateTests = []UpdateTest{
 	{
 		Name:        "null constraint failure",
 		UpdateQuery: `update people set first = null where id = 0`,
-		ExpectedErr: "Constraint failed for column 'first': Not null",
+		ExpectedErr: "column name 'first' is non-nullable but attempted to set a value of null",
 	},
 	{
 		Name:        "type mismatch list -> string",
@@ -0,0 +1,219 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/plan"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+)
+
+const aliasAsOfTablesRuleName = "dolt_alias_as_of_tables"
+
+// AsOfSeparator separates a table name from the commit it's read as of, in the names of the tables that queries read
+// AS OF a commit are rewritten to read. Table names can't contain it.
+const AsOfSeparator = "@"
+
+var _ sql.Table = (*AsOfTable)(nil)
+
+// AsOfTable is a read-only sql.Table giving access to the rows of a table as of a past commit.
+type AsOfTable struct {
+	table *DoltTable
+	spec  string
+	alias string
+}
+
+// Name returns the name of the table, without the commit it's read as of, so that queries can refer to its columns the
+// way they do for the current table. Tables given an alias by the query are named by their alias instead (see
+// aliasAsOfTables).
+func (t *AsOfTable) Name() string {
+	if t.alias != "" {
+		return t.alias
+	}
+
+	return t.table.Name()
+}
+
+// TableName returns the name of the table read, regardless of any alias it's given.
+func (t *AsOfTable) TableName() string {
+	return t.table.Name()
+}
+
+// String returns the name of the table and the commit it's read as of.
+func (t *AsOfTable) String() string {
+	return t.table.Name() + AsOfSeparator + t.spec
+}
+
+// Schema returns the schema of the table as of the commit. The columns of aliased tables have their alias as their
+// source.
+func (t *AsOfTable) Schema() sql.Schema {
+	sch := t.table.Schema()
+	if t.alias == "" {
+		return sch
+	}
+
+	aliased := make(sql.Schema, len(sch))
+	for i, col := range sch {
+		aliasedCol := *col
+		aliasedCol.Source = t.alias
+		aliased[i] = &aliasedCol
+	}
+
+	return aliased
+}
+
+// Partitions implements sql.Table
+func (t *AsOfTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	return t.table.Partitions(ctx)
+}
+
+// PartitionRows returns the rows of the table as of the commit.
+func (t *AsOfTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	return t.table.PartitionRows(ctx, part)
+}
+
+// aliasAsOfTables is an analyzer rule that resolves the tables read AS OF a commit which the query gives an alias, and
+// names them by their alias. The engine resolves the columns of an aliased table by the name of the table it aliases,
+// so without this the columns of the same table read as of two commits, as in a self-join comparing them, would all
+// resolve to the columns of one of them.
+func aliasAsOfTables(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	return plan.TransformUp(n, func(node sql.Node) (sql.Node, error) {
+		ta, ok := node.(*plan.TableAlias)
+		if !ok {
+			return node, nil
+		}
+
+		ut, ok := ta.Child.(*plan.UnresolvedTable)
+		if !ok || !strings.Contains(ut.Name(), AsOfSeparator) {
+			return node, nil
+		}
+
+		dbName := ut.Database
+		if dbName == "" {
+			dbName = a.Catalog.CurrentDatabase()
+		}
+
+		db, err := a.Catalog.Database(dbName)
+		if err != nil {
+			return nil, err
+		}
+
+		tbl, ok, err := db.GetTableInsensitive(ctx, ut.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		asOf, isAsOf := tbl.(*AsOfTable)
+		if !ok || !isAsOf {
+			return node, nil
+		}
+
+		aliased := *asOf
+		aliased.alias = ta.Name()
+		return plan.NewTableAlias(ta.Name(), plan.NewResolvedTable(&aliased)), nil
+	})
+}
+
+// getAsOfTable returns the table with the name given as of the commit given by the spec, which may be a commit hash, a
+// branch name or HEAD, followed by ancestor references such as ~3.
+func (db *Database) getAsOfTable(ctx context.Context, tblName, spec string) (sql.Table, bool, error) {
+	if db.ddb == nil || db.rs == nil {
+		return nil, false, fmt.Errorf("table %s can't be read as of '%s', as the database has no commits", tblName, spec)
+	}
+
+	if root, ok := db.pinnedRoot(spec); ok {
+		return tableAsOf(ctx, root, tblName, spec)
+	}
+
+	root, err := rootAsOf(ctx, db.ddb, db.rs.Head.Ref.String(), spec)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return tableAsOf(ctx, root, tblName, spec)
+}
+
+// rootAsOf returns the root of the commit given by the spec, in which HEAD refers to the branch given.
+func rootAsOf(ctx context.Context, ddb *doltdb.DoltDB, branch, spec string) (*doltdb.RootValue, error) {
+	cs, err := doltdb.NewCommitSpec(spec, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	cm, err := ddb.Resolve(ctx, cs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve '%s': %v", spec, err)
+	}
+
+	return cm.GetRootValue()
+}
+
+// tableAsOf returns a read-only table for the table with the name given in the root of the commit given by the spec.
+func tableAsOf(ctx context.Context, root *doltdb.RootValue, tblName, spec string) (sql.Table, bool, error) {
+	tableNames, err := root.GetTableNames(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	exactName, ok := sql.GetTableNameInsensitive(tblName, tableNames)
+	if !ok {
+		return nil, false, nil
+	}
+
+	tbl, _, err := root.GetTable(ctx, exactName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &AsOfTable{table: &DoltTable{name: exactName, table: tbl, sch: sch}, spec: spec}, true, nil
+}
+
+// asOfRegex matches tables read AS OF a commit, along with the string literals and comments that are skipped so that
+// the AS OF clauses they contain aren't rewritten.
+var asOfRegex = regexp.MustCompile(strings.Join([]string{
+	`'(?:[^'\\]|\\.|'')*'`,
+	`"(?:[^"\\]|\\.|"")*"`,
+	`--[^\n]*`,
+	`/\*(?s:.*?)\*/`,
+	"(`[^`]+`|\\b[a-zA-Z_][a-zA-Z0-9_]*)\\s+(?i:as)\\s+(?i:of)\\s+('[^'\\\\`]*'|\"[^\"\\\\`]*\")",
+}, "|"))
+
+// RewriteAsOf rewrites the tables read AS OF a commit in the query given, such as people AS OF 'HEAD~3', into the
+// tables named people@HEAD~3 that the parser accepts and dolt databases resolve to the table as of the commit. The
+// commit can be given as a single or double quoted string. Queries without AS OF clauses are returned unchanged.
+func RewriteAsOf(query string) string {
+	return asOfRegex.ReplaceAllStringFunc(query, func(match string) string {
+		groups := asOfRegex.FindStringSubmatch(match)
+		if groups[1] == "" {
+			return match
+		}
+
+		tblName := strings.Trim(groups[1], "`")
+		spec := groups[2][1 : len(groups[2])-1]
+		return "`" + tblName + AsOfSeparator + spec + "`"
+	})
+}
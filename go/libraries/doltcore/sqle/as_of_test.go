@@ -0,0 +1,152 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestRewriteAsOf(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"select * from people", "select * from people"},
+		{"select * from people as of 'HEAD~3'", "select * from `people@HEAD~3`"},
+		{"select * from `people` AS OF \"master\" where id = 1", "select * from `people@master` where id = 1"},
+		{"select * from dolt.people as of 'HEAD' p join people q on p.id = q.id", "select * from dolt.`people@HEAD` p join people q on p.id = q.id"},
+		{"select * from people as of 'HEAD~1' a, people as of 'HEAD~2' b", "select * from `people@HEAD~1` a, `people@HEAD~2` b"},
+		{"select * from people where first = 'as of'", "select * from people where first = 'as of'"},
+		{"select id as of from people", "select id as of from people"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			assert.Equal(t, test.expected, RewriteAsOf(test.query))
+		})
+	}
+}
+
+func TestAsOf(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+
+	commit := func(query string) {
+		root, err := dEnv.WorkingRoot(ctx)
+		require.NoError(t, err)
+		root, err = ExecuteSql(dEnv, root, query)
+		require.NoError(t, err)
+		require.NoError(t, dEnv.UpdateWorkingRoot(ctx, root))
+		require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+		require.NoError(t, actions.CommitStaged(ctx, dEnv, query, time.Now(), false))
+	}
+
+	commit("insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	commit("insert into people (id, first, last) values (11, 'Maude', 'Flanders')")
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+
+	query := func(q string) ([]sql.Row, error) {
+		_, iter, err := engine.Query(sql.NewEmptyContext(), RewriteAsOf(q))
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowIterToRows(iter)
+	}
+
+	rows, err := query("select first from people as of 'HEAD~1' where id >= 10")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"Ned"}}, rows)
+
+	rows, err = query("select first from people as of 'HEAD' where id >= 10 order by id")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"Ned"}, {"Maude"}}, rows)
+
+	rows, err = query("select p.first from `people` AS OF \"master~1\" p where p.id >= 10")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"Ned"}}, rows)
+
+	// the table didn't exist before the first commit
+	_, err = query("select * from people as of 'HEAD~2'")
+	assert.Error(t, err)
+
+	// tables read as of a commit can't be written to
+	_, err = query("insert into `people@HEAD~1` (id, first, last) values (12, 'Rod', 'Flanders')")
+	assert.Error(t, err)
+
+	_, err = query("select * from people as of 'nosuchbranch'")
+	assert.Error(t, err)
+}
+
+func TestAsOfSelfJoin(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+
+	commit := func(query string) {
+		root, err := dEnv.WorkingRoot(ctx)
+		require.NoError(t, err)
+		root, err = ExecuteSql(dEnv, root, query)
+		require.NoError(t, err)
+		require.NoError(t, dEnv.UpdateWorkingRoot(ctx, root))
+		require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+		require.NoError(t, actions.CommitStaged(ctx, dEnv, query, time.Now(), false))
+	}
+
+	commit("insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	commit("insert into people (id, first, last) values (11, 'Maude', 'Flanders')")
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+	queryRows(t, engine, "update people set first = 'Homie' where id = 0")
+
+	query := func(q string) []sql.Row {
+		return queryRows(t, engine, RewriteAsOf(q))
+	}
+
+	// rows added between two commits
+	assert.Equal(t, []sql.Row{{int64(11)}}, query("select n.id from people as of 'HEAD' n "+
+		"left join people as of 'HEAD~1' o on n.id = o.id where o.id is null"))
+
+	// rows changed since a commit, comparing it with the working set
+	assert.Equal(t, []sql.Row{{"Homer", "Homie"}}, query("select o.first, n.first from people as of 'HEAD' as o "+
+		"join people n on o.id = n.id where o.first <> n.first"))
+
+	assert.Equal(t, []sql.Row{{int64(7)}}, query("select count(*) from people as of 'HEAD~1' o "+
+		"join people as of 'HEAD' n on o.id = n.id"))
+
+	// row filters apply to aliased tables read as of a commit
+	sqlCtx := sql.NewContext(ctx, sql.WithSession(NewRowFilterSession(sql.NewBaseSession(), RowFilters{"people": "last = 'Flanders'"})))
+	rows, err := queryRowCount(sqlCtx, engine.Query, RewriteAsOf("select * from people as of 'HEAD~1' o "+
+		"join people as of 'HEAD' n on o.id = n.id"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, rows)
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+)
+
+var _ sql.Database = (*AttachedDatabase)(nil)
+
+// AttachedDatabase is a read-only sql.Database for the tables of another dolt repository, which lets queries join them
+// with the tables of the repository they're run in, as in SELECT * FROM people JOIN other.addresses. Its tables are
+// read as of the head of one of the repository's branches, and can be read as of its other commits with AS OF. The
+// repository's chunks are read as they're needed, so a remote repository isn't cloned to be attached.
+type AttachedDatabase struct {
+	name   string
+	ddb    *doltdb.DoltDB
+	branch string
+	root   *doltdb.RootValue
+}
+
+// NewAttachedDatabase returns a database with the name given for the tables of the head of the branch given.
+func NewAttachedDatabase(ctx context.Context, name string, ddb *doltdb.DoltDB, branch string) (*AttachedDatabase, error) {
+	root, err := rootAsOf(ctx, ddb, branch, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttachedDatabase{name: name, ddb: ddb, branch: branch, root: root}, nil
+}
+
+// Name returns the name of the database.
+func (db *AttachedDatabase) Name() string {
+	return db.name
+}
+
+// GetTableInsensitive returns the table with the name given as of the head of the branch, or as of the commit the name
+// gives after AsOfSeparator.
+func (db *AttachedDatabase) GetTableInsensitive(ctx context.Context, tblName string) (sql.Table, bool, error) {
+	if i := strings.Index(tblName, AsOfSeparator); i > 0 {
+		spec := tblName[i+len(AsOfSeparator):]
+		root, err := rootAsOf(ctx, db.ddb, db.branch, spec)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return tableAsOf(ctx, root, tblName[:i], spec)
+	}
+
+	return tableAsOf(ctx, db.root, tblName, db.branch)
+}
+
+// GetTableNames returns the names of the tables at the head of the branch.
+func (db *AttachedDatabase) GetTableNames(ctx context.Context) ([]string, error) {
+	return db.root.GetTableNames(ctx)
+}
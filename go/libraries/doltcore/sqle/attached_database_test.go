@@ -0,0 +1,90 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestAttachedDatabase(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+
+	otherEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(otherEnv, t)
+	for _, query := range []string{
+		"insert into people (id, first, last) values (10, 'Ned', 'Flanders')",
+		"insert into people (id, first, last) values (11, 'Maude', 'Flanders')",
+	} {
+		root, err := otherEnv.WorkingRoot(ctx)
+		require.NoError(t, err)
+		root, err = ExecuteSql(otherEnv, root, query)
+		require.NoError(t, err)
+		require.NoError(t, otherEnv.UpdateWorkingRoot(ctx, root))
+		require.NoError(t, actions.StageAllTables(ctx, otherEnv, false))
+		require.NoError(t, actions.CommitStaged(ctx, otherEnv, query, time.Now(), false))
+	}
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	attached, err := NewAttachedDatabase(ctx, "other", otherEnv.DoltDB, doltdb.MasterBranch)
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+	engine.AddDatabase(attached)
+
+	query := func(q string) ([]sql.Row, error) {
+		_, iter, err := engine.Query(sql.NewEmptyContext(), RewriteAsOf(q))
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowIterToRows(iter)
+	}
+
+	rows, err := query("select first from people where id >= 10")
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+
+	rows, err = query("select first from other.people where id >= 10 order by id")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"Ned"}, {"Maude"}}, rows)
+
+	rows, err = query("select o.first from other.people o join appearances a on o.id = a.character_id where a.episode_id = 1 order by o.id")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"Homer"}, {"Marge"}}, rows)
+
+	rows, err = query("select first from other.people as of 'HEAD~1' where id >= 10")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"Ned"}}, rows)
+
+	// attached databases are read-only
+	_, err = query("insert into other.people (id, first, last) values (12, 'Rod', 'Flanders')")
+	assert.Error(t, err)
+	_, err = query("delete from other.people")
+	assert.Error(t, err)
+}
@@ -0,0 +1,217 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+
+	"github.com/src-d/go-mysql-server/sql"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	sqlTypes "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle/types"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	// DoltBlameTablePrefix is the name prefix for each blame table
+	DoltBlameTablePrefix = "dolt_blame_"
+
+	// BlameEmailCol is the name of the column containing the email address of the committer in the result set
+	BlameEmailCol = "email"
+
+	// BlameMessageCol is the name of the column containing the commit message in the result set
+	BlameMessageCol = "message"
+)
+
+var _ sql.Table = (*BlameTable)(nil)
+
+// BlameTable is a system table that shows, for each row of a table as of the head of the current branch, the commit
+// which last modified the row. Rows are identified by their primary key.
+type BlameTable struct {
+	name   string
+	ddb    *doltdb.DoltDB
+	cm     *doltdb.Commit
+	tbl    *doltdb.Table
+	sch    schema.Schema
+	sqlSch sql.Schema
+}
+
+// NewBlameTable creates a blame table for the table with the name given, returning false if the table doesn't exist
+// as of the head of the current branch.
+func NewBlameTable(ctx context.Context, name string, ddb *doltdb.DoltDB, rs *env.RepoState) (*BlameTable, bool, error) {
+	cs, err := doltdb.NewCommitSpec("HEAD", rs.Head.Ref.GetPath())
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	cm, err := ddb.Resolve(ctx, cs)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	root, err := cm.GetRootValue()
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	tbl, ok, err := root.GetTable(ctx, name)
+
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	tblName := DoltBlameTablePrefix + name
+	var sqlSch sql.Schema
+	err = sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		sqlCol, err := doltColToSqlCol(tblName, col)
+
+		if err != nil {
+			return true, err
+		}
+
+		sqlSch = append(sqlSch, sqlCol)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	sqlSch = append(sqlSch,
+		&sql.Column{Name: CommitHashCol, Type: sql.Text, Source: tblName},
+		&sql.Column{Name: CommitterCol, Type: sql.Text, Source: tblName},
+		&sql.Column{Name: BlameEmailCol, Type: sql.Text, Source: tblName},
+		&sql.Column{Name: CommitDateCol, Type: sql.Timestamp, Source: tblName},
+		&sql.Column{Name: BlameMessageCol, Type: sql.Text, Source: tblName},
+	)
+
+	return &BlameTable{name, ddb, cm, tbl, sch, sqlSch}, true, nil
+}
+
+// Name returns the name of the table
+func (bt *BlameTable) Name() string {
+	return DoltBlameTablePrefix + bt.name
+}
+
+// String returns the name of the table
+func (bt *BlameTable) String() string {
+	return DoltBlameTablePrefix + bt.name
+}
+
+// Schema returns the schema of the table, which has the primary key columns of the table being blamed followed by the
+// columns describing the commit which last modified each row.
+func (bt *BlameTable) Schema() sql.Schema {
+	return bt.sqlSch
+}
+
+// Partitions returns a single partition, as the data isn't partitioned
+func (bt *BlameTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return &doltTablePartitionIter{}, nil
+}
+
+// PartitionRows returns an iterator over the rows of the table, in primary key order. The history of the table is
+// walked to find the commit which last modified each row when this is called.
+func (bt *BlameTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	bg, err := actions.BlameGraphFromCommit(ctx, bt.ddb, bt.cm, bt.name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := bt.tbl.GetRowData(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	itr, err := rows.Iterator(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &blameRowItr{ctx, bt.sch, bg, itr}, nil
+}
+
+type blameRowItr struct {
+	ctx *sql.Context
+	sch schema.Schema
+	bg  *actions.BlameGraph
+	itr types.MapIterator
+}
+
+// Next returns the primary key of the next row of the table along with the commit which last modified the row
+func (itr *blameRowItr) Next() (sql.Row, error) {
+	key, _, err := itr.itr.Next(itr.ctx)
+
+	if err != nil {
+		return nil, err
+	} else if key == nil {
+		return nil, io.EOF
+	}
+
+	nbf := key.(types.Tuple).Format()
+	h, err := key.Hash(nbf)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info := (*itr.bg)[h]
+	r, err := row.FromNoms(itr.sch, key.(types.Tuple), types.EmptyTuple(nbf))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var sqlRow sql.Row
+	err = itr.sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		val, _ := r.GetColVal(tag)
+		sqlVal, err := sqlTypes.NomsValToSqlVal(val)
+
+		if err != nil {
+			return true, err
+		}
+
+		sqlRow = append(sqlRow, sqlVal)
+		return false, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sqlRow = append(sqlRow, info.CommitHash, info.Author, info.Email, info.TimestampTime(), info.Description)
+	return sqlRow, nil
+}
+
+// Close closes the iterator
+func (itr *blameRowItr) Close() error {
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+)
+
+func TestBlameTable(t *testing.T) {
+	ctx := context.Background()
+	dEnv := branchFunctionsEnv(t)
+	created := headHash(t, dEnv, "master")
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	engine, db := viewsEngine(dEnv, root)
+	queryRows(t, engine, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	queryRows(t, engine, "update people set age = 99 where id = 0")
+	queryRows(t, engine, "delete from people where id = 1")
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, db.Root()))
+	require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+	require.NoError(t, actions.CommitStaged(ctx, dEnv, "changed people", time.Now(), false))
+	changed := headHash(t, dEnv, "master")
+
+	rows := queryRows(t, engine, "select id, commit_hash, message from dolt_blame_people where id in (0, 1, 2, 10)")
+	assert.Equal(t, []sql.Row{
+		{int64(0), changed, "changed people"},
+		{int64(2), created, "created tables"},
+		{int64(10), changed, "changed people"},
+	}, rows)
+
+	rows = queryRows(t, engine, "select count(*) from dolt_blame_people")
+	assert.Equal(t, []sql.Row{{int64(6)}}, rows)
+
+	_, _, err = engine.Query(sql.NewEmptyContext(), "select * from dolt_blame_no_such_table")
+	assert.Error(t, err)
+}
@@ -0,0 +1,354 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/auth"
+	"github.com/src-d/go-mysql-server/sql"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+)
+
+// BranchFunctions returns the functions that create, check out and merge the branches of the environment given, which
+// the database given must serve:
+//
+//	DOLT_BRANCH(name[, start])  creates a branch at the commit start resolves to, or at HEAD if it's not given. Returns
+//	                            the hash of the commit the branch points to.
+//	DOLT_CHECKOUT(name)         checks out the branch, carrying the uncommitted writes to the database over like dolt
+//	                            checkout does, and makes its working root the root of the database, starting with the
+//	                            next query. Returns the hash of the branch's head commit.
+//	DOLT_MERGE(name)            merges the branch into the branch checked out, like dolt merge does, and makes the
+//	                            merged working root the root of the database. There must be no uncommitted writes.
+//	                            Returns 1 if the merge has conflicts, which are listed by the dolt_conflicts tables and
+//	                            must be resolved before the merge is committed with DOLT_COMMIT, and 0 if it doesn't.
+//
+// If a is not nil, only users with write permission may call them.
+func BranchFunctions(dEnv *env.DoltEnv, db *Database, a auth.Auth) []sql.Function {
+	return []sql.Function{
+		sql.FunctionN{Name: "dolt_branch", Fn: func(args ...sql.Expression) (sql.Expression, error) {
+			if len(args) < 1 || len(args) > 2 {
+				return nil, sql.ErrInvalidArgumentNumber.New("DOLT_BRANCH", "1 or 2", len(args))
+			}
+			return &BranchFunc{"DOLT_BRANCH", sql.Text, dEnv, db, a, args, createBranch}, nil
+		}},
+		sql.Function1{Name: "dolt_checkout", Fn: func(name sql.Expression) sql.Expression {
+			return &BranchFunc{"DOLT_CHECKOUT", sql.Text, dEnv, db, a, []sql.Expression{name}, checkoutBranch}
+		}},
+		sql.Function1{Name: "dolt_merge", Fn: func(name sql.Expression) sql.Expression {
+			return &BranchFunc{"DOLT_MERGE", sql.Int64, dEnv, db, a, []sql.Expression{name}, mergeBranch}
+		}},
+	}
+}
+
+// branchFuncImpl is the implementation of a branch function, called with the values of its arguments converted to
+// strings. The functions aren't called when an argument is NULL.
+type branchFuncImpl func(ctx *sql.Context, dEnv *env.DoltEnv, db *Database, args []string) (interface{}, error)
+
+// BranchFunc is one of the functions returned by BranchFunctions.
+type BranchFunc struct {
+	name string
+	typ  sql.Type
+	dEnv *env.DoltEnv
+	db   *Database
+	auth auth.Auth
+	args []sql.Expression
+	impl branchFuncImpl
+}
+
+var _ sql.Expression = (*BranchFunc)(nil)
+
+// Children implements sql.Expression
+func (f *BranchFunc) Children() []sql.Expression { return f.args }
+
+// Type implements sql.Expression
+func (f *BranchFunc) Type() sql.Type { return f.typ }
+
+// Resolved implements sql.Expression
+func (f *BranchFunc) Resolved() bool {
+	for _, arg := range f.args {
+		if !arg.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNullable implements sql.Expression
+func (f *BranchFunc) IsNullable() bool { return true }
+
+// WithChildren implements sql.Expression
+func (f *BranchFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != len(f.args) {
+		return nil, sql.ErrInvalidChildrenNumber.New(f, len(children), len(f.args))
+	}
+	return &BranchFunc{f.name, f.typ, f.dEnv, f.db, f.auth, children, f.impl}, nil
+}
+
+// String implements fmt.Stringer
+func (f *BranchFunc) String() string {
+	args := make([]string, len(f.args))
+	for i, arg := range f.args {
+		args[i] = arg.String()
+	}
+	return fmt.Sprintf("%s(%s)", f.name, strings.Join(args, ", "))
+}
+
+// Eval implements sql.Expression
+func (f *BranchFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	if f.auth != nil {
+		if err := f.auth.Allowed(ctx, auth.WritePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	args := make([]string, len(f.args))
+	for i, arg := range f.args {
+		val, err := arg.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		} else if val == nil {
+			return nil, nil
+		}
+
+		str, err := sql.Text.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = str.(string)
+	}
+
+	return f.impl(ctx, f.dEnv, f.db, args)
+}
+
+func createBranch(ctx *sql.Context, dEnv *env.DoltEnv, _ *Database, args []string) (interface{}, error) {
+	start := "HEAD"
+	if len(args) > 1 {
+		start = args[1]
+	}
+
+	if err := actions.CreateBranch(ctx, dEnv, args[0], start, false); err != nil {
+		if err == actions.ErrAlreadyExists {
+			return "", fmt.Errorf("a branch named '%s' already exists", args[0])
+		}
+		return "", err
+	}
+
+	return branchHead(ctx, dEnv, args[0])
+}
+
+func checkoutBranch(ctx *sql.Context, dEnv *env.DoltEnv, db *Database, args []string) (interface{}, error) {
+	err := changeWorking(ctx, dEnv, db, func() error {
+		err := actions.CheckoutBranch(ctx, dEnv, args[0])
+		if err != nil && err != doltdb.ErrAlreadyOnBranch {
+			if actions.IsCheckoutWouldOverwrite(err) {
+				tbls := actions.CheckoutWouldOverwriteTables(err)
+				return fmt.Errorf("checking out '%s' would overwrite the uncommitted changes to tables: %s", args[0], strings.Join(tbls, ", "))
+			}
+			return err
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return branchHead(ctx, dEnv, args[0])
+}
+
+func mergeBranch(ctx *sql.Context, dEnv *env.DoltEnv, db *Database, args []string) (interface{}, error) {
+	var hasConflicts bool
+	err := changeWorking(ctx, dEnv, db, func() error {
+		var err error
+		hasConflicts, err = mergeIntoHead(ctx, dEnv, args[0])
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return boolToInt64(hasConflicts), nil
+}
+
+// mergeIntoHead merges the branch named into the branch checked out, as dolt merge does, and returns whether the merge
+// has conflicts. The merge is a fast-forward if it can be, and is otherwise written to the working set, to be committed
+// once its conflicts are resolved.
+func mergeIntoHead(ctx context.Context, dEnv *env.DoltEnv, branch string) (bool, error) {
+	dref, err := dEnv.FindRef(ctx, branch)
+	if err == doltdb.ErrBranchNotFound {
+		return false, fmt.Errorf("unknown branch: %s", branch)
+	} else if err != nil {
+		return false, err
+	}
+
+	return mergeRefIntoHead(ctx, dEnv, dref)
+}
+
+// mergeRefIntoHead merges the branch or remote tracking branch given into the branch checked out, like mergeIntoHead.
+func mergeRefIntoHead(ctx context.Context, dEnv *env.DoltEnv, dref ref.DoltRef) (bool, error) {
+	if dEnv.IsMergeActive() {
+		return false, errors.New("merging is not possible because the active merge hasn't been committed")
+	}
+
+	if unchanged, err := dEnv.IsUnchangedFromHead(ctx); err != nil {
+		return false, err
+	} else if !unchanged {
+		return false, errors.New("merging is not possible because there are uncommitted changes, which must be committed first")
+	}
+
+	cs, err := doltdb.NewCommitSpec("HEAD", dEnv.RepoState.Head.Ref.String())
+	if err != nil {
+		return false, err
+	}
+
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+	if err != nil {
+		return false, err
+	}
+
+	cs, err = doltdb.NewCommitSpec("HEAD", dref.String())
+	if err != nil {
+		return false, err
+	}
+
+	mergeCm, err := dEnv.DoltDB.Resolve(ctx, cs)
+	if err != nil {
+		return false, err
+	}
+
+	canFF, err := cm.CanFastForwardTo(ctx, mergeCm)
+	if err == doltdb.ErrUpToDate || err == doltdb.ErrIsAhead {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	} else if canFF {
+		return false, fastForwardHead(ctx, dEnv, mergeCm)
+	}
+
+	mergedRoot, tblToStats, err := actions.MergeCommits(ctx, dEnv.DoltDB, cm, mergeCm)
+	if err != nil {
+		return false, err
+	}
+
+	h, err := mergeCm.HashOf()
+	if err != nil {
+		return false, err
+	}
+
+	if err := dEnv.RepoState.StartMerge(dref, h.String(), dEnv.FS); err != nil {
+		return false, err
+	}
+
+	if err := dEnv.UpdateWorkingRoot(ctx, mergedRoot); err != nil {
+		return false, err
+	}
+
+	for _, stats := range tblToStats {
+		if stats.Conflicts > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// fastForwardHead moves the branch checked out to the commit given, along with its working and staged roots.
+func fastForwardHead(ctx context.Context, dEnv *env.DoltEnv, cm *doltdb.Commit) error {
+	root, err := cm.GetRootValue()
+	if err != nil {
+		return err
+	}
+
+	h, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	if err != nil {
+		return err
+	}
+
+	if err := dEnv.DoltDB.FastForward(ctx, dEnv.RepoState.Head.Ref, cm); err != nil {
+		return err
+	}
+
+	dEnv.RepoState.Working = h.String()
+	dEnv.RepoState.Staged = h.String()
+	return dEnv.RepoState.Save(dEnv.FS)
+}
+
+// changeWorking writes the root of the database to the working set of the environment, and then changes the working
+// set with the function given, such as by checking out or merging a branch. The new working root becomes the root of
+// the database, and of the other databases watching the environment.
+func changeWorking(ctx *sql.Context, dEnv *env.DoltEnv, db *Database, change func() error) error {
+	// Writes made before the change must not be committed after it by a concurrent commit
+	if db.committer != nil {
+		db.committer.mu.Lock()
+		defer db.committer.mu.Unlock()
+	}
+
+	update := func() error {
+		if err := dEnv.UpdateWorkingRoot(ctx, db.Root()); err != nil {
+			return err
+		}
+		if db.committer != nil {
+			db.committer.stopFlushing()
+		}
+
+		return change()
+	}
+
+	if db.watcher != nil {
+		return db.watcher.changeWorking(ctx, db, update)
+	}
+
+	if err := update(); err != nil {
+		return err
+	}
+
+	root, err := dEnv.WorkingRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	db.replaceRoot(root)
+	return nil
+}
+
+// branchHead returns the hash of the head commit of the branch given.
+func branchHead(ctx context.Context, dEnv *env.DoltEnv, branch string) (string, error) {
+	cs, err := doltdb.NewCommitSpec("HEAD", branch)
+	if err != nil {
+		return "", err
+	}
+
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+	if err != nil {
+		return "", err
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return "", err
+	}
+
+	return h.String(), nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestBranchFunctions(t *testing.T) {
+	ctx := context.Background()
+	dEnv := branchFunctionsEnv(t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine, db := viewsEngine(dEnv, root)
+	engine.Catalog.MustRegister(BranchFunctions(dEnv, db, nil)...)
+
+	head := headHash(t, dEnv, "master")
+	assert.Equal(t, []sql.Row{{head}}, queryRows(t, engine, "select dolt_branch('feature')"))
+	assert.Equal(t, head, headHash(t, dEnv, "feature"))
+
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "select dolt_branch('feature')")
+	assert.Error(t, err)
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "select dolt_checkout('no_such_branch')")
+	assert.Error(t, err)
+	assert.Equal(t, "master", dEnv.RepoState.Head.Ref.GetPath())
+
+	// uncommitted writes are carried over to the branch checked out
+	queryRows(t, engine, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	assert.Equal(t, []sql.Row{{head}}, queryRows(t, engine, "select dolt_checkout('feature')"))
+	assert.Equal(t, "feature", dEnv.RepoState.Head.Ref.GetPath())
+	assert.Equal(t, []sql.Row{{"Flanders"}}, queryRows(t, engine, "select last from people where id = 10"))
+
+	working, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, db.Root(), working)
+
+	// a branch can be created from another
+	require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+	require.NoError(t, actions.CommitStaged(ctx, dEnv, "added Ned", time.Now(), false))
+	assert.Equal(t, []sql.Row{{head}}, queryRows(t, engine, "select dolt_branch('other', 'master')"))
+	assert.Equal(t, []sql.Row{{head}}, queryRows(t, engine, "select dolt_checkout('other')"))
+	assert.Empty(t, queryRows(t, engine, "select last from people where id = 10"))
+}
+
+func TestCheckoutWithRootWatcher(t *testing.T) {
+	ctx := context.Background()
+	dEnv := branchFunctionsEnv(t)
+	require.NoError(t, actions.CreateBranch(ctx, dEnv, "feature", "HEAD", false))
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	watcher, err := NewRootWatcher(ctx, dEnv)
+	require.NoError(t, err)
+
+	engine, db := viewsEngine(dEnv, root)
+	watcher.Watch(db)
+	engine.Catalog.MustRegister(BranchFunctions(dEnv, db, nil)...)
+	other, otherDB := viewsEngine(dEnv, root)
+	watcher.Watch(otherDB)
+
+	queryRows(t, other, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	queryRows(t, engine, "select dolt_checkout('feature')")
+	assert.Equal(t, "feature", dEnv.RepoState.Head.Ref.GetPath())
+
+	// the writes of the other database are carried over and it follows the checkout
+	assert.Equal(t, []sql.Row{{"Flanders"}}, queryRows(t, other, "select last from people where id = 10"))
+	assert.Equal(t, db.Root(), otherDB.Root())
+
+	// the checkout isn't mistaken for an external change
+	require.NoError(t, watcher.Poll(ctx))
+	assert.Equal(t, db.Root(), otherDB.Root())
+}
+
+func TestMergeFunction(t *testing.T) {
+	ctx := context.Background()
+	dEnv := branchFunctionsEnv(t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine, db := viewsEngine(dEnv, root)
+	engine.Catalog.MustRegister(BranchFunctions(dEnv, db, nil)...)
+	commit := func(msg string) {
+		require.NoError(t, dEnv.UpdateWorkingRoot(ctx, db.Root()))
+		require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+		require.NoError(t, actions.CommitStaged(ctx, dEnv, msg, time.Now(), false))
+	}
+
+	// a fast-forward
+	queryRows(t, engine, "select dolt_branch('feature')")
+	queryRows(t, engine, "select dolt_checkout('feature')")
+	queryRows(t, engine, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	commit("added Ned")
+	queryRows(t, engine, "select dolt_checkout('master')")
+	assert.Equal(t, []sql.Row{{int64(0)}}, queryRows(t, engine, "select dolt_merge('feature')"))
+	assert.Equal(t, headHash(t, dEnv, "feature"), headHash(t, dEnv, "master"))
+	assert.Equal(t, []sql.Row{{"Flanders"}}, queryRows(t, engine, "select last from people where id = 10"))
+	assert.Equal(t, []sql.Row{{int64(0)}}, queryRows(t, engine, "select dolt_merge('feature')"))
+
+	// uncommitted writes prevent merging
+	queryRows(t, engine, "update people set first = 'Homer' where id = 10")
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "select dolt_merge('feature')")
+	assert.Error(t, err)
+
+	// a merge with conflicts
+	commit("renamed Ned on master")
+	queryRows(t, engine, "select dolt_checkout('feature')")
+	queryRows(t, engine, "update people set first = 'Maude' where id = 10")
+	commit("renamed Ned on feature")
+	queryRows(t, engine, "select dolt_checkout('master')")
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "select dolt_merge('no_such_branch')")
+	assert.Error(t, err)
+	assert.Equal(t, []sql.Row{{int64(1)}}, queryRows(t, engine, "select dolt_merge('feature')"))
+	assert.True(t, dEnv.IsMergeActive())
+	assert.Equal(t, []sql.Row{{"people", uint64(1)}}, queryRows(t, engine, "select * from dolt_conflicts"))
+
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "select dolt_merge('feature')")
+	assert.Error(t, err)
+}
+
+// branchFunctionsEnv returns an environment with the test database committed to master.
+func branchFunctionsEnv(t *testing.T) *env.DoltEnv {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+	require.NoError(t, actions.CommitStaged(ctx, dEnv, "created tables", time.Now(), false))
+	return dEnv
+}
+
+// headHash returns the hash of the head commit of the branch given.
+func headHash(t *testing.T, dEnv *env.DoltEnv, branch string) string {
+	cs, err := doltdb.NewCommitSpec("HEAD", branch)
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Resolve(context.Background(), cs)
+	require.NoError(t, err)
+	h, err := cm.HashOf()
+	require.NoError(t, err)
+	return h.String()
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sqle "github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/src-d/go-mysql-server/sql/expression/function"
+	"github.com/src-d/go-mysql-server/sql/plan"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+)
+
+// Collation determines how string values are compared by the SQL engine.
+type Collation string
+
+const (
+	// CaseSensitive compares strings byte-wise. This is the default.
+	CaseSensitive Collation = "case_sensitive"
+	// CaseInsensitive compares strings without regard to letter case.
+	CaseInsensitive Collation = "case_insensitive"
+)
+
+const collationRuleName = "dolt_collation"
+
+// ParseCollation returns the Collation named by the string given, or an error if it isn't a known collation.
+func ParseCollation(str string) (Collation, error) {
+	switch Collation(strings.ToLower(strings.TrimSpace(str))) {
+	case CaseSensitive, "":
+		return CaseSensitive, nil
+	case CaseInsensitive:
+		return CaseInsensitive, nil
+	default:
+		return "", fmt.Errorf("unknown collation '%s', valid values are '%s' and '%s'", str, CaseSensitive, CaseInsensitive)
+	}
+}
+
+// NewEngine returns a new SQL engine that compares strings according to the collation given. The engine's catalog
+// includes dolt's UUID functions and UNNEST along with the standard ones, and queries are subject to the limits set in
+// their session (see QueryLimits) and to the session's row filters, if it has any (see RowFilters). Each query starts
+// from the latest root of the databases that watch a RootWatcher, with the isolation level set by the session's SET
+// TRANSACTION ISOLATION LEVEL (see IsolationLevel), and views created in dolt databases are stored in their
+// dolt_schemas tables and listed by SHOW TABLES. The session variables @@dolt_head and @@dolt_working report the head
+// commit and the root read by each query, and setting them pins the session to a commit or root (see DoltHeadVar).
+// NULL values follow the three-valued logic of standard SQL in comparisons, IN, joins and aggregates, and ORDER BY
+// places them as @@dolt_null_ordering says (see NullOrderingVar). Columns of a custom type (see schema.CustomType) hold
+// the string representations of their values, which are compared by the type. Statements that would write to a
+// database opened at a commit fail with ErrReadOnly. The catalog has an INFORMATION_SCHEMA database, and the comments
+// of dolt tables and their columns are given by it and by SHOW CREATE TABLE.
+func NewEngine(collation Collation) *sqle.Engine {
+	c := sql.NewCatalog()
+	c.MustRegister(uuidFunctions...)
+	c.MustRegister(unnestFunctions...)
+	builder := analyzer.NewBuilder(c).AddPreAnalyzeRule(rejectWritesRuleName, rejectWrites)
+	builder = builder.AddPreAnalyzeRule(refreshRootsRuleName, refreshRoots)
+	builder = builder.AddPreAnalyzeRule(loadViewsRuleName, loadViews)
+	builder = builder.AddPreAnalyzeRule(comparisonsRuleName, normalizeComparisons)
+	builder = builder.AddPreAnalyzeRule(isolationRuleName, setIsolation)
+	builder = builder.AddPreAnalyzeRule(sessionRootsRuleName, setSessionRoots)
+	builder = builder.AddPreAnalyzeRule(aliasAsOfTablesRuleName, aliasAsOfTables)
+	builder = builder.AddPreValidationRule(customTypesRuleName, convertToCustomTypes)
+	if collation == CaseInsensitive {
+		builder = builder.AddPreValidationRule(collationRuleName, foldCase)
+	}
+	builder = builder.AddPostValidationRule(persistViewsRuleName, persistViews)
+	builder = builder.AddPostValidationRule(showViewsRuleName, showViews)
+	builder = builder.AddPostValidationRule(zoneMapsRuleName, applyZoneMaps)
+	builder = builder.AddPostValidationRule(rowFiltersRuleName, applyRowFilters)
+	builder = builder.AddPostValidationRule(nullsRuleName, applyNullSemantics)
+	builder = builder.AddPostValidationRule(queryGuardsRuleName, applyQueryGuards)
+	builder = builder.AddPostValidationRule(showCreateTableRuleName, showCreateTable)
+
+	// The first database added to the catalog becomes its current database, which must not be INFORMATION_SCHEMA
+	c.AddDatabase(NewInformationSchemaDatabase(c))
+	c.SetCurrentDatabase("")
+
+	return sqle.New(c, builder.Build(), nil)
+}
+
+// ExecuteSelectIter executes the select statement given against the root given and returns the schema of the result
+// along with an iterator over its rows. Rows are produced as Next() is called rather than being materialized up front,
+// so arbitrarily large results can be processed in constant memory. Next() returns io.EOF after the last row. Callers
+// must call Close() on the iterator when they are done with it.
+func ExecuteSelectIter(ctx context.Context, root *doltdb.RootValue, query string) (sql.Schema, sql.RowIter, error) {
+	db := NewDatabase("dolt", root, nil, nil)
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(db)
+	engine.Catalog.RegisterIndexDriver(NewDoltIndexDriver(db))
+	_ = engine.Init()
+
+	return engine.Query(sql.NewContext(ctx), query)
+}
+
+// foldCase is an analyzer rule that rewrites string comparisons, LIKE patterns, ORDER BY and GROUP BY expressions to
+// operate on lower-cased values, making them case insensitive.
+func foldCase(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	n, err := plan.TransformUp(n, func(node sql.Node) (sql.Node, error) {
+		switch node := node.(type) {
+		case *plan.Sort:
+			fields := make([]plan.SortField, len(node.SortFields))
+			for i, sf := range node.SortFields {
+				fields[i] = sf
+				fields[i].Column = lowerIfText(sf.Column)
+			}
+			return plan.NewSort(fields, node.Child), nil
+		case *plan.GroupBy:
+			grouping := make([]sql.Expression, len(node.Grouping))
+			for i, e := range node.Grouping {
+				grouping[i] = lowerIfText(e)
+			}
+			return plan.NewGroupBy(node.Aggregate, grouping, node.Child), nil
+		default:
+			return node, nil
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.TransformExpressionsUp(n, func(e sql.Expression) (sql.Expression, error) {
+		switch e.(type) {
+		case *expression.Equals, *expression.GreaterThan, *expression.GreaterThanOrEqual, *expression.LessThan,
+			*expression.LessThanOrEqual, *expression.In, *expression.NotIn, *expression.Like:
+			children := e.Children()
+			lowered := make([]sql.Expression, len(children))
+			for i, child := range children {
+				lowered[i] = lowerIfText(child)
+			}
+			return e.WithChildren(lowered...)
+		default:
+			return e, nil
+		}
+	})
+}
+
+// lowerIfText wraps the expression given in a call to LOWER if it is a string expression. Tuples have each of their
+// elements lowered.
+func lowerIfText(e sql.Expression) sql.Expression {
+	switch e := e.(type) {
+	case *function.Lower:
+		return e
+	case expression.Tuple:
+		lowered := make([]sql.Expression, len(e))
+		for i, child := range e {
+			lowered[i] = lowerIfText(child)
+		}
+		return expression.NewTuple(lowered...)
+	default:
+		if sql.IsText(e.Type()) {
+			return function.NewLower(e)
+		}
+		return e
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestParseCollation(t *testing.T) {
+	c, err := ParseCollation("")
+	require.NoError(t, err)
+	assert.Equal(t, CaseSensitive, c)
+
+	c, err = ParseCollation("Case_Insensitive")
+	require.NoError(t, err)
+	assert.Equal(t, CaseInsensitive, c)
+
+	_, err = ParseCollation("utf8mb4_klingon_ci")
+	assert.Error(t, err)
+}
+
+func TestCollation(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		collation   Collation
+		expectedIds []int64
+	}{
+		{
+			name:        "equals case sensitive",
+			query:       "select id from people where first = 'homer'",
+			collation:   CaseSensitive,
+			expectedIds: nil,
+		},
+		{
+			name:        "equals case insensitive",
+			query:       "select id from people where first = 'homer'",
+			collation:   CaseInsensitive,
+			expectedIds: []int64{0},
+		},
+		{
+			name:        "in case insensitive",
+			query:       "select id from people where first in ('HOMER', 'bart') order by id",
+			collation:   CaseInsensitive,
+			expectedIds: []int64{0, 2},
+		},
+		{
+			name:        "like case sensitive",
+			query:       "select id from people where first like 'b%' order by id",
+			collation:   CaseSensitive,
+			expectedIds: nil,
+		},
+		{
+			name:        "like case insensitive",
+			query:       "select id from people where first like 'b%' order by id",
+			collation:   CaseInsensitive,
+			expectedIds: []int64{2, 5},
+		},
+		{
+			name:        "group by case insensitive",
+			query:       "select count(*) as c from people group by case when id < 2 then upper(last) else last end order by c desc",
+			collation:   CaseInsensitive,
+			expectedIds: []int64{4, 1, 1},
+		},
+		{
+			name:        "group by case sensitive",
+			query:       "select count(*) as c from people group by case when id < 2 then upper(last) else last end order by c desc",
+			collation:   CaseSensitive,
+			expectedIds: []int64{2, 2, 1, 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			root, err := dEnv.WorkingRoot(context.Background())
+			require.NoError(t, err)
+
+			engine := NewEngine(test.collation)
+			engine.AddDatabase(NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+
+			_, iter, err := engine.Query(sql.NewEmptyContext(), test.query)
+			require.NoError(t, err)
+
+			var ids []int64
+			var r sql.Row
+			for r, err = iter.Next(); err == nil; r, err = iter.Next() {
+				ids = append(ids, r[0].(int64))
+			}
+			require.Equal(t, io.EOF, err)
+
+			assert.Equal(t, test.expectedIds, ids)
+		})
+	}
+}
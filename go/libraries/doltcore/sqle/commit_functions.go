@@ -0,0 +1,199 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+)
+
+// CommitFunctions returns the functions that let queries refer to the commits of the database given symbolically:
+//
+//	HASHOF(spec)          returns the hash of the commit the spec resolves to. Specs are resolved as they are by
+//	                      dolt log and AS OF, so they may be branch names, HEAD or commit hashes, followed by ancestor
+//	                      references such as ~2, with HEAD referring to the branch checked out.
+//	COMMIT_DATE(spec)     returns the date of the commit the spec resolves to.
+//	COMMIT_AUTHOR(spec)   returns the author of the commit the spec resolves to, as name <email>.
+//	COMMIT_MESSAGE(spec)  returns the message of the commit the spec resolves to.
+//	ACTIVE_BRANCH()       returns the name of the branch checked out.
+func CommitFunctions(db *Database) []sql.Function {
+	return []sql.Function{
+		sql.Function1{Name: "hashof", Fn: func(spec sql.Expression) sql.Expression {
+			return &CommitFunc{"HASHOF", sql.Text, db, []sql.Expression{spec}, commitHash}
+		}},
+		sql.Function1{Name: "commit_date", Fn: func(spec sql.Expression) sql.Expression {
+			return &CommitFunc{"COMMIT_DATE", sql.Timestamp, db, []sql.Expression{spec}, commitDate}
+		}},
+		sql.Function1{Name: "commit_author", Fn: func(spec sql.Expression) sql.Expression {
+			return &CommitFunc{"COMMIT_AUTHOR", sql.Text, db, []sql.Expression{spec}, commitAuthor}
+		}},
+		sql.Function1{Name: "commit_message", Fn: func(spec sql.Expression) sql.Expression {
+			return &CommitFunc{"COMMIT_MESSAGE", sql.Text, db, []sql.Expression{spec}, commitMessage}
+		}},
+		sql.Function0{Name: "active_branch", Fn: func() sql.Expression {
+			return &CommitFunc{"ACTIVE_BRANCH", sql.Text, db, nil, activeBranch}
+		}},
+	}
+}
+
+// commitFuncImpl is the implementation of a commit function, called with the values of its arguments converted to
+// strings. The functions aren't called when an argument is NULL.
+type commitFuncImpl func(ctx *sql.Context, db *Database, args []string) (interface{}, error)
+
+// CommitFunc is one of the functions returned by CommitFunctions.
+type CommitFunc struct {
+	name string
+	typ  sql.Type
+	db   *Database
+	args []sql.Expression
+	impl commitFuncImpl
+}
+
+var _ sql.Expression = (*CommitFunc)(nil)
+
+// Children implements sql.Expression
+func (f *CommitFunc) Children() []sql.Expression { return f.args }
+
+// Type implements sql.Expression
+func (f *CommitFunc) Type() sql.Type { return f.typ }
+
+// Resolved implements sql.Expression
+func (f *CommitFunc) Resolved() bool {
+	for _, arg := range f.args {
+		if !arg.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNullable implements sql.Expression
+func (f *CommitFunc) IsNullable() bool { return len(f.args) > 0 }
+
+// WithChildren implements sql.Expression
+func (f *CommitFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != len(f.args) {
+		return nil, sql.ErrInvalidChildrenNumber.New(f, len(children), len(f.args))
+	}
+	return &CommitFunc{f.name, f.typ, f.db, children, f.impl}, nil
+}
+
+// String implements fmt.Stringer
+func (f *CommitFunc) String() string {
+	args := make([]string, len(f.args))
+	for i, arg := range f.args {
+		args[i] = arg.String()
+	}
+	return fmt.Sprintf("%s(%s)", f.name, strings.Join(args, ", "))
+}
+
+// Eval implements sql.Expression
+func (f *CommitFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	if f.db.ddb == nil || f.db.rs == nil {
+		return nil, fmt.Errorf("%s can't be used, as the database has no commits", f.name)
+	}
+
+	args := make([]string, len(f.args))
+	for i, arg := range f.args {
+		val, err := arg.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		} else if val == nil {
+			return nil, nil
+		}
+
+		str, err := sql.Text.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = str.(string)
+	}
+
+	return f.impl(ctx, f.db, args)
+}
+
+// resolveCommit returns the commit the spec given resolves to, in which HEAD refers to the branch checked out.
+func resolveCommit(ctx *sql.Context, db *Database, spec string) (*doltdb.Commit, error) {
+	cs, err := doltdb.NewCommitSpec(spec, db.rs.Head.Ref.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit '%s': %v", spec, err)
+	}
+
+	cm, err := db.ddb.Resolve(ctx, cs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve '%s': %v", spec, err)
+	}
+
+	return cm, nil
+}
+
+// commitMeta returns the metadata of the commit the spec given resolves to.
+func commitMeta(ctx *sql.Context, db *Database, spec string) (*doltdb.CommitMeta, error) {
+	cm, err := resolveCommit(ctx, db, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return cm.GetCommitMeta()
+}
+
+func commitHash(ctx *sql.Context, db *Database, args []string) (interface{}, error) {
+	cm, err := resolveCommit(ctx, db, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	return h.String(), nil
+}
+
+func commitDate(ctx *sql.Context, db *Database, args []string) (interface{}, error) {
+	meta, err := commitMeta(ctx, db, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return meta.Time().UTC(), nil
+}
+
+func commitAuthor(ctx *sql.Context, db *Database, args []string) (interface{}, error) {
+	meta, err := commitMeta(ctx, db, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return fmt.Sprintf("%s <%s>", meta.Name, meta.Email), nil
+}
+
+func commitMessage(ctx *sql.Context, db *Database, args []string) (interface{}, error) {
+	meta, err := commitMeta(ctx, db, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return meta.Description, nil
+}
+
+func activeBranch(_ *sql.Context, db *Database, _ []string) (interface{}, error) {
+	return db.rs.Head.Ref.GetPath(), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestCommitFunctions(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+	commitTime := time.Date(2019, 12, 1, 10, 30, 0, 0, time.UTC)
+	require.NoError(t, actions.CommitStaged(ctx, dEnv, "created tables", commitTime, false))
+	require.NoError(t, actions.CreateBranch(ctx, dEnv, "feature", "HEAD", false))
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	engine, db := viewsEngine(dEnv, root)
+	engine.Catalog.MustRegister(CommitFunctions(db)...)
+
+	head := headHash(t, dEnv, "master")
+	assert.Equal(t, []sql.Row{{head, head, head}}, queryRows(t, engine, "select hashof('HEAD'), hashof('master'), hashof('feature')"))
+	assert.Equal(t, []sql.Row{{head}}, queryRows(t, engine, "select hashof('"+head[:8]+"')"))
+	assert.NotEqual(t, []sql.Row{{head}}, queryRows(t, engine, "select hashof('HEAD~1')"))
+	assert.Equal(t, []sql.Row{{nil}}, queryRows(t, engine, "select hashof(null)"))
+
+	assert.Equal(t, []sql.Row{{commitTime, "billy bob <bigbillieb@fake.horse>", "created tables"}},
+		queryRows(t, engine, "select commit_date('HEAD'), commit_author('HEAD'), commit_message('"+head+"')"))
+	assert.Equal(t, []sql.Row{{"master"}}, queryRows(t, engine, "select active_branch()"))
+
+	// commits can be compared with the ones recorded by the system tables
+	assert.Equal(t, []sql.Row{{"created tables"}}, queryRows(t, engine, "select message from dolt_log where commit_hash = hashof('master')"))
+
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "select hashof('no_such_branch')")
+	assert.Error(t, err)
+
+	require.NoError(t, actions.CheckoutBranch(ctx, dEnv, "feature"))
+	assert.Equal(t, []sql.Row{{"feature"}}, queryRows(t, engine, "select active_branch()"))
+}
@@ -0,0 +1,407 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/src-d/go-mysql-server/sql"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+)
+
+// CommitPolicy determines when the writes made through SQL become dolt commits.
+type CommitPolicy string
+
+const (
+	// CommitNone keeps writes in memory: they aren't written to the working set or committed.
+	CommitNone CommitPolicy = ""
+	// CommitPerTransaction commits after each statement that writes. Every statement is its own transaction.
+	CommitPerTransaction CommitPolicy = "transaction"
+	// CommitPerStatements commits once every CommitConfig.BatchSize statements that write.
+	CommitPerStatements CommitPolicy = "statements"
+	// CommitOnInterval commits the writes made in each CommitConfig.Interval, if there are any.
+	CommitOnInterval CommitPolicy = "interval"
+	// CommitManual only commits when DOLT_COMMIT is called.
+	CommitManual CommitPolicy = "manual"
+)
+
+var ErrNothingToCommit = errors.New("nothing to commit")
+
+// CommitConfig configures when a Committer commits.
+//
+// By default, the working set is written after each statement that writes, which creates a table file and updates the
+// manifest of the repository's storage every time. With a FlushInterval, writes are coalesced instead: they're kept in
+// memory, where the statements that follow still see them, and the working set is written once for all of the
+// statements written in the interval, or as soon as FlushSize statements are waiting if it's greater than 0. The
+// policy is applied when the working set is written, so CommitPerTransaction commits the statements coalesced together.
+type CommitConfig struct {
+	Policy        CommitPolicy
+	BatchSize     int           // The number of statements per commit with CommitPerStatements.
+	Interval      time.Duration // The time between commits with CommitOnInterval.
+	FlushInterval time.Duration // The longest a write waits to be written to the working set. 0 writes it right away.
+	FlushSize     int           // The number of waiting statements that writes the working set early. 0 means no limit.
+}
+
+// Validate returns an error if the policy is unknown or is missing the batch size or interval it needs, or if the
+// writes are coalesced without a valid flush interval.
+func (config CommitConfig) Validate() error {
+	if config.FlushInterval < 0 {
+		return fmt.Errorf("flush interval cannot be less than 0: %v", config.FlushInterval)
+	} else if config.FlushSize < 0 {
+		return fmt.Errorf("flush size cannot be less than 0: %d", config.FlushSize)
+	} else if config.FlushSize > 0 && config.FlushInterval == 0 {
+		return errors.New("a flush size requires a flush interval greater than 0")
+	} else if config.FlushInterval > 0 && config.Policy == CommitNone {
+		return errors.New("writes can only be flushed with a commit policy, as they're kept in memory without one")
+	}
+
+	switch config.Policy {
+	case CommitNone, CommitPerTransaction, CommitManual:
+	case CommitPerStatements:
+		if config.BatchSize <= 0 {
+			return fmt.Errorf("commit policy '%s' requires a batch size greater than 0", config.Policy)
+		}
+	case CommitOnInterval:
+		if config.Interval <= 0 {
+			return fmt.Errorf("commit policy '%s' requires an interval greater than 0", config.Policy)
+		}
+	default:
+		return fmt.Errorf("unknown commit policy '%s', valid values are '%s', '%s', '%s' and '%s'", config.Policy,
+			CommitPerTransaction, CommitPerStatements, CommitOnInterval, CommitManual)
+	}
+
+	return nil
+}
+
+// Committer writes the changes made to a database through SQL to the working set of a dolt environment, and commits
+// them as its policy dictates. It's notified by the database after each statement that writes.
+type Committer struct {
+	dEnv   *env.DoltEnv
+	db     *Database
+	config CommitConfig
+
+	mu         sync.Mutex
+	pending    int          // The number of statements written since the last commit
+	unflushed  int          // The number of statements written since the working set was last written
+	flushTimer *time.Timer  // Writes the working set when the oldest unflushed statement has waited FlushInterval
+	audit      []auditEntry // Who wrote the statements written since the last commit, in the order first written
+	stop       chan struct{}
+	stopped    chan struct{}
+}
+
+// NewCommitter returns a committer for the writes made to the database given, which must have been created for the
+// environment given, and sets it as the database's committer. The config must be valid and its policy can't be
+// CommitNone. Close must be called when the database is no longer used.
+func NewCommitter(dEnv *env.DoltEnv, db *Database, config CommitConfig) *Committer {
+	c := &Committer{dEnv: dEnv, db: db, config: config}
+	db.committer = c
+
+	if config.Policy == CommitOnInterval {
+		c.stop = make(chan struct{})
+		c.stopped = make(chan struct{})
+		go c.commitOnInterval()
+	}
+
+	return c
+}
+
+// written writes the database's root to the working set, and commits if the policy calls for it. If writes are
+// coalesced, the root is only broadcast to the database's root watcher, and the working set is written once the flush
+// interval has passed or enough statements are waiting.
+func (c *Committer) written(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.config.FlushInterval <= 0 {
+		if err := c.updateWorkingRoot(ctx); err != nil {
+			return err
+		}
+		c.pending++
+		c.recordAudit(ctx)
+
+		return c.commitDue(ctx)
+	}
+
+	if c.db.watcher != nil {
+		if err := c.db.watcher.written(ctx, c.db, nil); err != nil {
+			return err
+		}
+	}
+	c.pending++
+	c.unflushed++
+	c.recordAudit(ctx)
+
+	if c.config.FlushSize > 0 && c.unflushed >= c.config.FlushSize {
+		return c.flush(ctx)
+	}
+	if c.flushTimer == nil {
+		c.flushTimer = time.AfterFunc(c.config.FlushInterval, c.flushOnTimer)
+	}
+
+	return nil
+}
+
+// commitDue commits the pending statements if the policy calls for it. The caller must hold c.mu.
+func (c *Committer) commitDue(ctx context.Context) error {
+	switch c.config.Policy {
+	case CommitPerTransaction:
+		return c.commitPending(ctx)
+	case CommitPerStatements:
+		if c.pending >= c.config.BatchSize {
+			return c.commitPending(ctx)
+		}
+	}
+
+	return nil
+}
+
+// Commit commits all the writes made to the database with the message given, regardless of the policy, and returns
+// the hash of the new commit. Returns ErrNothingToCommit if nothing has changed since the last commit.
+func (c *Committer) Commit(ctx context.Context, msg string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.updateWorkingRoot(ctx); err != nil {
+		return "", err
+	}
+	c.stopFlushing()
+
+	if err := c.commit(ctx, msg); err != nil {
+		if actions.IsNothingStaged(err) {
+			return "", ErrNothingToCommit
+		}
+		return "", err
+	}
+	c.pending = 0
+	c.audit = nil
+
+	cs, err := doltdb.NewCommitSpec("HEAD", c.dEnv.RepoState.Head.Ref.String())
+	if err != nil {
+		return "", err
+	}
+
+	cm, err := c.dEnv.DoltDB.Resolve(ctx, cs)
+	if err != nil {
+		return "", err
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return "", err
+	}
+
+	return h.String(), nil
+}
+
+// Close stops the committer, first writing any coalesced writes to the working set and committing any writes that
+// haven't been committed unless the policy is CommitManual, in which case they're left in the working set.
+func (c *Committer) Close(ctx context.Context) error {
+	if c.stop != nil {
+		close(c.stop)
+		<-c.stopped
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.flush(ctx); err != nil {
+		return err
+	}
+
+	if c.config.Policy == CommitManual {
+		return nil
+	}
+
+	return c.commitPending(ctx)
+}
+
+// updateWorkingRoot writes the database's root to the working set, through the database's root watcher if it has one.
+func (c *Committer) updateWorkingRoot(ctx context.Context) error {
+	write := func() error {
+		return c.dEnv.UpdateWorkingRoot(ctx, c.db.Root())
+	}
+
+	if c.db.watcher != nil {
+		return c.db.watcher.written(ctx, c.db, write)
+	}
+
+	return write()
+}
+
+// flush writes the database's root to the working set if statements are waiting to be written, and commits if the
+// policy calls for it. Changes to the working set made outside of the process that the database's root watcher found
+// since the last statement replace the waiting writes, as they would have if the writes had been made before them.
+// The caller must hold c.mu.
+func (c *Committer) flush(ctx context.Context) error {
+	if c.unflushed == 0 {
+		c.stopFlushing()
+		return nil
+	}
+
+	if c.db.watcher != nil {
+		c.db.refreshRoot()
+	}
+
+	if err := c.updateWorkingRoot(ctx); err != nil {
+		return err
+	}
+	c.stopFlushing()
+
+	return c.commitDue(ctx)
+}
+
+// flushOnTimer flushes the waiting writes when the flush interval of the oldest has passed.
+func (c *Committer) flushOnTimer() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.flush(context.Background()); err != nil {
+		logrus.Errorf("failed to write SQL writes to the working set: %v", err)
+	}
+}
+
+// stopFlushing records that the database's root has been written to the working set, so that no statements are
+// waiting to be written. The caller must hold c.mu.
+func (c *Committer) stopFlushing() {
+	if c.flushTimer != nil {
+		c.flushTimer.Stop()
+		c.flushTimer = nil
+	}
+	c.unflushed = 0
+}
+
+// commitOnInterval commits the pending writes once every interval until the committer is closed.
+func (c *Committer) commitOnInterval() {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			err := c.flush(context.Background())
+			if err == nil {
+				err = c.commitPending(context.Background())
+			}
+			c.mu.Unlock()
+
+			if err != nil {
+				logrus.Errorf("failed to commit SQL writes: %v", err)
+			}
+		}
+	}
+}
+
+// commitPending commits the statements written since the last commit, if there are any. The caller must hold c.mu.
+func (c *Committer) commitPending(ctx context.Context) error {
+	if c.pending == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("SQL auto-commit of %d statements", c.pending)
+	if c.pending == 1 {
+		msg = "SQL auto-commit of 1 statement"
+	}
+
+	if len(c.audit) > 0 {
+		lines := make([]string, len(c.audit))
+		for i, entry := range c.audit {
+			lines[i] = entry.String()
+		}
+
+		msg += "\n\n" + strings.Join(lines, "\n")
+	}
+
+	// Statements that didn't change anything leave nothing to commit
+	if err := c.commit(ctx, msg); err != nil && !actions.IsNothingStaged(err) {
+		return err
+	}
+	c.pending = 0
+	c.audit = nil
+
+	return nil
+}
+
+// auditEntry records that a SQL user wrote a statement through a client connection, so that auto-commits say who
+// changed what. Statements are identified by the digest of their text.
+type auditEntry struct {
+	user    string
+	address string
+	digest  string
+	count   int
+}
+
+func (e auditEntry) String() string {
+	s := fmt.Sprintf("Statement %s by %s from %s", e.digest, e.user, e.address)
+	if e.count > 1 {
+		s += fmt.Sprintf(" (%d times)", e.count)
+	}
+
+	return s
+}
+
+// recordAudit records the user, client address and statement digest of the statement that was just written, if the
+// context given is the SQL context of a query. Repeats of the same statement by the same client are counted rather than
+// recorded again. The caller must hold c.mu.
+func (c *Committer) recordAudit(ctx context.Context) {
+	sqlCtx, ok := ctx.(*sql.Context)
+
+	if !ok || sqlCtx.Query() == "" {
+		return
+	}
+
+	client := sqlCtx.Session.Client()
+	digest := sha256.Sum256([]byte(strings.TrimSpace(sqlCtx.Query())))
+	entry := auditEntry{user: client.User, address: client.Address, digest: hex.EncodeToString(digest[:])}
+
+	for i := range c.audit {
+		if c.audit[i].user == entry.user && c.audit[i].address == entry.address && c.audit[i].digest == entry.digest {
+			c.audit[i].count++
+			return
+		}
+	}
+
+	entry.count = 1
+	c.audit = append(c.audit, entry)
+}
+
+// commit stages all the tables in the working set, refreshes their materialized views and commits them with the
+// message given.
+func (c *Committer) commit(ctx context.Context, msg string) error {
+	if err := actions.StageAllTables(ctx, c.dEnv, false); err != nil {
+		return err
+	}
+
+	if err := RefreshStagedMaterializedViews(ctx, c.dEnv); err != nil {
+		return err
+	}
+
+	return actions.CommitStaged(ctx, c.dEnv, msg, time.Now(), false)
+}
@@ -0,0 +1,295 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestCommitPolicies(t *testing.T) {
+	inserts := []string{
+		"insert into people (id, first, last) values (10, 'Ned', 'Flanders')",
+		"insert into people (id, first, last) values (11, 'Maude', 'Flanders')",
+		"insert into people (id, first, last) values (12, 'Rod', 'Flanders')",
+	}
+
+	tests := []struct {
+		name            string
+		config          CommitConfig
+		queries         []string
+		expectedMsgs    []string
+		expectedOnClose []string
+	}{
+		{
+			name:         "per transaction",
+			config:       CommitConfig{Policy: CommitPerTransaction},
+			queries:      append(inserts, "select * from people", "delete from people where id = 10"),
+			expectedMsgs: []string{"SQL auto-commit of 1 statement", "SQL auto-commit of 1 statement", "SQL auto-commit of 1 statement", "SQL auto-commit of 1 statement"},
+		},
+		{
+			name:            "per statements",
+			config:          CommitConfig{Policy: CommitPerStatements, BatchSize: 2},
+			queries:         inserts,
+			expectedMsgs:    []string{"SQL auto-commit of 2 statements"},
+			expectedOnClose: []string{"SQL auto-commit of 1 statement"},
+		},
+		{
+			name:         "manual",
+			config:       CommitConfig{Policy: CommitManual},
+			queries:      append(inserts[:2], "select dolt_commit('added the Flanders')", inserts[2]),
+			expectedMsgs: []string{"added the Flanders"},
+		},
+		{
+			name:         "dolt_commit with another policy",
+			config:       CommitConfig{Policy: CommitPerStatements, BatchSize: 10},
+			queries:      append(inserts, "select DOLT_COMMIT('added the Flanders')"),
+			expectedMsgs: []string{"added the Flanders"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			root, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+
+			engine := NewEngine(CaseSensitive)
+			db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+			engine.AddDatabase(db)
+			committer := NewCommitter(dEnv, db, test.config)
+			engine.Catalog.MustRegister(DoltCommitFunction(committer, nil))
+
+			for _, query := range test.queries {
+				_, err := queryRowCount(sql.NewEmptyContext(), engine.Query, query)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedMsgs, commitMessages(t, dEnv))
+
+			// every write is in the working set, whether it's been committed or not
+			working, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, db.Root(), working)
+
+			require.NoError(t, committer.Close(ctx))
+			assert.Equal(t, append(test.expectedMsgs, test.expectedOnClose...), commitMessages(t, dEnv))
+		})
+	}
+}
+
+func TestCommitOnInterval(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	engine.AddDatabase(db)
+	committer := NewCommitter(dEnv, db, CommitConfig{Policy: CommitOnInterval, Interval: 10 * time.Millisecond})
+	defer committer.Close(ctx)
+
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		msgs := commitMessages(t, dEnv)
+		return len(msgs) == 1 && msgs[0] == "SQL auto-commit of 1 statement"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWriteCoalescing(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	engine.AddDatabase(db)
+	committer := NewCommitter(dEnv, db, CommitConfig{Policy: CommitPerTransaction, FlushInterval: time.Hour, FlushSize: 3})
+
+	insert := func(id int) {
+		query := fmt.Sprintf("insert into people (id, first, last) values (%d, 'Ned', 'Flanders')", id)
+		_, err := queryRowCount(sql.NewEmptyContext(), engine.Query, query)
+		require.NoError(t, err)
+	}
+
+	insert(10)
+	insert(11)
+
+	// the writes are seen by the statements that follow, but are waiting to be written to the working set
+	count, err := queryRowCount(sql.NewEmptyContext(), engine.Query, "select * from people where id >= 10")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	working, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, root, working)
+	assert.Empty(t, commitMessages(t, dEnv))
+
+	insert(12)
+	working, err = dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, db.Root(), working)
+	assert.Equal(t, []string{"SQL auto-commit of 3 statements"}, commitMessages(t, dEnv))
+
+	insert(13)
+	require.NoError(t, committer.Close(ctx))
+	working, err = dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, db.Root(), working)
+	assert.Equal(t, []string{"SQL auto-commit of 3 statements", "SQL auto-commit of 1 statement"}, commitMessages(t, dEnv))
+}
+
+func TestFlushInterval(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	engine.AddDatabase(db)
+	committer := NewCommitter(dEnv, db, CommitConfig{Policy: CommitManual, FlushInterval: 10 * time.Millisecond})
+	defer committer.Close(ctx)
+
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	require.NoError(t, err)
+
+	expected, err := db.Root().HashOf()
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		committer.mu.Lock()
+		defer committer.mu.Unlock()
+		return dEnv.RepoState.WorkingHash() == expected
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, commitMessages(t, dEnv))
+}
+
+func TestAutoCommitAuditTrail(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	engine.AddDatabase(db)
+	committer := NewCommitter(dEnv, db, CommitConfig{Policy: CommitPerStatements, BatchSize: 3})
+
+	queries := []struct {
+		user    string
+		address string
+		query   string
+	}{
+		{"homer", "10.0.0.1:50000", "delete from people where id = 0"},
+		{"marge", "10.0.0.2:50000", "update people set age = 40 where id = 1"},
+		{"homer", "10.0.0.1:50000", "delete from people where id = 0"},
+	}
+
+	for _, q := range queries {
+		sqlCtx := sql.NewContext(ctx, sql.WithSession(sql.NewSession("server", q.address, q.user, 1)), sql.WithQuery(q.query))
+		_, err := queryRowCount(sqlCtx, engine.Query, q.query)
+		require.NoError(t, err)
+	}
+
+	digest := func(query string) string {
+		h := sha256.Sum256([]byte(query))
+		return hex.EncodeToString(h[:])
+	}
+
+	expected := "SQL auto-commit of 3 statements\n\n" +
+		"Statement " + digest(queries[0].query) + " by homer from 10.0.0.1:50000 (2 times)\n" +
+		"Statement " + digest(queries[1].query) + " by marge from 10.0.0.2:50000"
+	assert.Equal(t, []string{expected}, commitMessages(t, dEnv))
+	require.NoError(t, committer.Close(ctx))
+}
+
+func TestDoltCommitNothingToCommit(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	require.NoError(t, actions.StageAllTables(context.Background(), dEnv, false))
+	require.NoError(t, actions.CommitStaged(context.Background(), dEnv, "created tables", time.Now(), false))
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	engine.AddDatabase(db)
+	engine.Catalog.MustRegister(DoltCommitFunction(NewCommitter(dEnv, db, CommitConfig{Policy: CommitManual}), nil))
+
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "select dolt_commit('nothing')")
+	require.Error(t, err)
+	assert.Equal(t, ErrNothingToCommit.Error(), err.Error())
+}
+
+func TestCommitConfigValidate(t *testing.T) {
+	assert.NoError(t, CommitConfig{}.Validate())
+	assert.NoError(t, CommitConfig{Policy: CommitManual}.Validate())
+	assert.NoError(t, CommitConfig{Policy: CommitOnInterval, Interval: time.Second}.Validate())
+	assert.Error(t, CommitConfig{Policy: CommitOnInterval}.Validate())
+	assert.Error(t, CommitConfig{Policy: CommitPerStatements}.Validate())
+	assert.Error(t, CommitConfig{Policy: "sometimes"}.Validate())
+	assert.NoError(t, CommitConfig{Policy: CommitPerTransaction, FlushInterval: time.Second, FlushSize: 100}.Validate())
+	assert.Error(t, CommitConfig{Policy: CommitPerTransaction, FlushSize: 100}.Validate())
+	assert.Error(t, CommitConfig{Policy: CommitPerTransaction, FlushInterval: -time.Second}.Validate())
+	assert.Error(t, CommitConfig{FlushInterval: time.Second}.Validate())
+}
+
+// commitMessages returns the messages of the commits made since the repository was initialized, oldest first.
+func commitMessages(t *testing.T, dEnv *env.DoltEnv) []string {
+	ctx := context.Background()
+	cs, err := doltdb.NewCommitSpec("HEAD", dEnv.RepoState.Head.Ref.String())
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+	require.NoError(t, err)
+
+	var msgs []string
+	for {
+		numParents, err := cm.NumParents()
+		require.NoError(t, err)
+		if numParents == 0 {
+			return msgs
+		}
+
+		meta, err := cm.GetCommitMeta()
+		require.NoError(t, err)
+		msgs = append([]string{meta.Description}, msgs...)
+
+		cm, err = dEnv.DoltDB.ResolveParent(ctx, cm, 0)
+		require.NoError(t, err)
+	}
+}
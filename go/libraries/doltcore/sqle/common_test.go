@@ -18,8 +18,8 @@ import (
 	"context"
 	"io"
 
-	sqle "github.com/src-d/go-mysql-server"
 	"github.com/src-d/go-mysql-server/sql"
+	"vitess.io/vitess/go/vt/sqlparser"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
@@ -28,11 +28,22 @@ import (
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
+// parseInsert returns the insert statement parsed from the query given, if it is one.
+func parseInsert(query string) (*sqlparser.Insert, bool) {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return nil, false
+	}
+
+	ins, ok := stmt.(*sqlparser.Insert)
+	return ins, ok
+}
+
 // Runs the query given and returns the result. The schema result of the query's execution is currently ignored, and
 // the targetSchema given is used to prepare all rows.
 func executeSelect(ctx context.Context, dEnv *env.DoltEnv, targetSch schema.Schema, root *doltdb.RootValue, query string) ([]row.Row, schema.Schema, error) {
 	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
-	engine := sqle.NewDefault()
+	engine := NewEngine(CaseSensitive)
 	engine.AddDatabase(db)
 	engine.Catalog.RegisterIndexDriver(&DoltIndexDriver{db})
 	engine.Init()
@@ -70,10 +81,16 @@ func executeSelect(ctx context.Context, dEnv *env.DoltEnv, targetSch schema.Sche
 // Runs the query given and returns the error (if any).
 func executeModify(ctx context.Context, root *doltdb.RootValue, query string) (*doltdb.RootValue, error) {
 	db := NewDatabase("dolt", root, nil, nil)
-	engine := sqle.NewDefault()
+	engine := NewEngine(CaseSensitive)
 	engine.AddDatabase(db)
 	engine.Init()
 	sqlCtx := sql.NewContext(ctx)
-	_, _, err := engine.Query(sqlCtx, query)
+
+	var err error
+	if ins, ok := parseInsert(query); ok {
+		_, _, err = ExecuteInsert(sqlCtx, engine, db, query, ins)
+	} else {
+		_, _, err = engine.Query(sqlCtx, query)
+	}
 	return db.Root(), err
 }
@@ -0,0 +1,531 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/plan"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/rowconv"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	// ConflictsTableName is the name of the system table which lists the tables with merge conflicts
+	ConflictsTableName = "dolt_conflicts"
+
+	// DoltConflictsTablePrefix is the name prefix for the system table of each table's merge conflicts
+	DoltConflictsTablePrefix = "dolt_conflicts_"
+
+	baseVersion  = "base"
+	ourVersion   = "our"
+	theirVersion = "their"
+)
+
+var ErrConflictsUpdateFmt = "only the our_ columns of %s can be updated"
+var ErrConflictsKeyUpdateFmt = "the primary key of a conflict in %s can't be changed"
+
+var _ sql.Table = (*ConflictsTable)(nil)
+
+// ConflictsTable is a sql.Table implementation of the system table which lists each table with merge conflicts, and
+// the number of rows in conflict. Tables whose conflicts have all been resolved aren't listed.
+type ConflictsTable struct {
+	root *doltdb.RootValue
+}
+
+// NewConflictsTable creates a ConflictsTable for the tables of the root given
+func NewConflictsTable(root *doltdb.RootValue) *ConflictsTable {
+	return &ConflictsTable{root}
+}
+
+// Name is a sql.Table interface function which returns the name of the table
+func (ct *ConflictsTable) Name() string {
+	return ConflictsTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table
+func (ct *ConflictsTable) String() string {
+	return ConflictsTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the conflicts system table.
+func (ct *ConflictsTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "table", Type: sql.Text, Source: ConflictsTableName, PrimaryKey: true},
+		{Name: "num_conflicts", Type: sql.Uint64, Source: ConflictsTableName, PrimaryKey: false},
+	}
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently the data is unpartitioned.
+func (ct *ConflictsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return &doltTablePartitionIter{}, nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (ct *ConflictsTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	tblNames, err := ct.root.TablesInConflict(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for _, tblName := range tblNames {
+		tbl, _, err := ct.root.GetTable(ctx, tblName)
+
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := tbl.NumRowsInConflict(ctx)
+
+		if err != nil {
+			return nil, err
+		} else if n == 0 {
+			continue
+		}
+
+		rows = append(rows, sql.NewRow(tblName, n))
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+var _ sql.Table = (*TableConflictsTable)(nil)
+var _ sql.UpdatableTable = (*TableConflictsTable)(nil)
+var _ sql.DeletableTable = (*TableConflictsTable)(nil)
+
+// TableConflictsTable is a sql.Table implementation of the system table of a table's merge conflicts. Each row is a
+// conflict, with a base_, our_ and their_ column for each column of the table as it was in the merge base, in the
+// working set, and in the commit merged.
+//
+// Deleting a row resolves the conflict, keeping the table's row as it is. Once every conflict is resolved the table is
+// no longer in conflict. Updating the our_ columns of a row changes
+// the table's row to match them, so that a conflict can be resolved by updating it, then deleting it.
+type TableConflictsTable struct {
+	name   string
+	db     *Database
+	joiner *rowconv.Joiner
+	sqlSch sql.Schema
+}
+
+// NewTableConflictsTable creates a TableConflictsTable for the conflicts of the table with the name given. ok is false
+// if the table doesn't exist. The table name is matched case insensitively.
+func NewTableConflictsTable(ctx context.Context, name string, db *Database) (*TableConflictsTable, bool, error) {
+	root := db.rootFor(ctx)
+	tblNames, err := root.GetTableNames(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	name, ok := sql.GetTableNameInsensitive(name, tblNames)
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	tbl, ok, err := root.GetTable(ctx, name)
+
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var base, sch, mergeSch schema.Schema
+	if has, err := tbl.HasConflicts(); err != nil {
+		return nil, false, err
+	} else if has {
+		base, sch, mergeSch, err = tbl.GetConflictSchemas(ctx)
+	} else {
+		sch, err = tbl.GetSchema(ctx)
+		base, mergeSch = sch, sch
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	j, err := rowconv.NewJoiner(
+		[]rowconv.NamedSchema{{Name: baseVersion, Sch: base}, {Name: ourVersion, Sch: sch}, {Name: theirVersion, Sch: mergeSch}},
+		map[string]rowconv.ColNamingFunc{
+			baseVersion:  versionNamer(baseVersion),
+			ourVersion:   versionNamer(ourVersion),
+			theirVersion: versionNamer(theirVersion),
+		})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	sqlSch, err := doltSchemaToSqlSchema(DoltConflictsTablePrefix+name, j.GetSchema())
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &TableConflictsTable{name, db, j, sqlSch}, true, nil
+}
+
+func versionNamer(version string) rowconv.ColNamingFunc {
+	return func(name string) string {
+		return version + "_" + name
+	}
+}
+
+// Name is a sql.Table interface function which returns the name of the table
+func (ct *TableConflictsTable) Name() string {
+	return DoltConflictsTablePrefix + ct.name
+}
+
+// String is a sql.Table interface function which returns the name of the table
+func (ct *TableConflictsTable) String() string {
+	return DoltConflictsTablePrefix + ct.name
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the table's conflicts.
+func (ct *TableConflictsTable) Schema() sql.Schema {
+	return ct.sqlSch
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently the data is unpartitioned.
+func (ct *TableConflictsTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return &doltTablePartitionIter{}, nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (ct *TableConflictsTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	tbl, _, err := ct.db.rootFor(ctx).GetTable(ctx, ct.name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if has, err := tbl.HasConflicts(); err != nil {
+		return nil, err
+	} else if !has {
+		return sql.RowsToRowIter(), nil
+	}
+
+	_, confData, err := tbl.GetConflicts(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	itr, err := confData.Iterator(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &conflictsRowItr{ctx, ct.joiner, itr}, nil
+}
+
+// Updater implements sql.UpdatableTable
+func (ct *TableConflictsTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return &conflictsEditor{ct: ct}
+}
+
+// Deleter implements sql.DeletableTable
+func (ct *TableConflictsTable) Deleter(ctx *sql.Context) sql.RowDeleter {
+	return &conflictsEditor{ct: ct}
+}
+
+// conflictsRowItr is a sql.RowIter which joins the versions of each conflict of a table into a row.
+type conflictsRowItr struct {
+	ctx    context.Context
+	joiner *rowconv.Joiner
+	itr    types.MapIterator
+}
+
+// Next returns the next row. It will return io.EOF if it's the last row.
+func (itr *conflictsRowItr) Next() (sql.Row, error) {
+	key, val, err := itr.itr.Next(itr.ctx)
+
+	if err != nil {
+		return nil, err
+	} else if key == nil {
+		return nil, io.EOF
+	}
+
+	cnf, err := doltdb.ConflictFromTuple(val.(types.Tuple))
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make(map[string]row.Row)
+	versions := map[string]types.Value{baseVersion: cnf.Base, ourVersion: cnf.Value, theirVersion: cnf.MergeValue}
+	for version, v := range versions {
+		if types.IsNull(v) {
+			continue
+		}
+
+		rows[version], err = row.FromNoms(itr.joiner.SchemaForName(version), key.(types.Tuple), v.(types.Tuple))
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := itr.joiner.Join(rows)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return doltRowToSqlRow(r, itr.joiner.GetSchema())
+}
+
+// Close closes the iterator.
+func (itr *conflictsRowItr) Close() error {
+	return nil
+}
+
+// conflictEdit is a change to a conflict, and to the table's row with the same key. If resolved is true the conflict is
+// removed, otherwise the table's row, and the our_ version of the conflict, are set to ours, which is nil if the row
+// should be removed.
+type conflictEdit struct {
+	key      types.Value
+	ours     types.Value
+	resolved bool
+}
+
+// conflictsEditor is the sql.RowUpdater and sql.RowDeleter for a TableConflictsTable. Edits are applied to the table
+// when the editor is closed.
+type conflictsEditor struct {
+	ct    *TableConflictsTable
+	edits []conflictEdit
+}
+
+var _ sql.RowUpdater = (*conflictsEditor)(nil)
+var _ sql.RowDeleter = (*conflictsEditor)(nil)
+
+// Delete resolves the conflict given, keeping the table's row as it is.
+func (ce *conflictsEditor) Delete(ctx *sql.Context, sqlRow sql.Row) error {
+	_, key, err := ce.splitRow(ctx, sqlRow)
+
+	if err != nil {
+		return err
+	}
+
+	ce.edits = append(ce.edits, conflictEdit{key: key, resolved: true})
+	return nil
+}
+
+// Update sets the table's row with the key of the conflict given to the new our_ columns.
+func (ce *conflictsEditor) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.Row) error {
+	oldRows, key, err := ce.splitRow(ctx, oldRow)
+
+	if err != nil {
+		return err
+	}
+
+	newRows, _, err := ce.splitRow(ctx, newRow)
+
+	if err != nil {
+		return err
+	}
+
+	for _, version := range []string{baseVersion, theirVersion} {
+		sch := ce.ct.joiner.SchemaForName(version)
+		oldR, newR := oldRows[version], newRows[version]
+
+		if (oldR == nil) != (newR == nil) || (oldR != nil && !row.AreEqual(oldR, newR, sch)) {
+			return fmt.Errorf(ErrConflictsUpdateFmt, ce.ct.Name())
+		}
+	}
+
+	ours, ok := newRows[ourVersion]
+
+	if !ok {
+		ce.edits = append(ce.edits, conflictEdit{key: key})
+		return nil
+	}
+
+	sch := ce.ct.joiner.SchemaForName(ourVersion)
+	oursKey, err := ours.NomsMapKey(sch).Value(ctx)
+
+	if err != nil {
+		return err
+	} else if !oursKey.Equals(key) {
+		return fmt.Errorf(ErrConflictsKeyUpdateFmt, ce.ct.Name())
+	}
+
+	if col, err := row.GetInvalidCol(ours, sch); err != nil {
+		return err
+	} else if col != nil {
+		return plan.ErrInsertIntoNonNullableProvidedNull.New(versionNamer(ourVersion)(col.Name))
+	}
+
+	oursVal, err := ours.NomsMapValue(sch).Value(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	ce.edits = append(ce.edits, conflictEdit{key: key, ours: oursVal})
+	return nil
+}
+
+// splitRow splits a row of the conflicts table into the versions of the conflict's row, and returns them with the
+// conflict's key.
+func (ce *conflictsEditor) splitRow(ctx context.Context, sqlRow sql.Row) (map[string]row.Row, types.Value, error) {
+	r, err := SqlRowToDoltRow(ce.ct.db.Root().VRW().Format(), sqlRow, ce.ct.joiner.GetSchema())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := ce.ct.joiner.Split(r)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, version := range []string{baseVersion, ourVersion, theirVersion} {
+		if r, ok := rows[version]; ok {
+			key, err := r.NomsMapKey(ce.ct.joiner.SchemaForName(version)).Value(ctx)
+			return rows, key, err
+		}
+	}
+
+	return nil, nil, fmt.Errorf("row of %s has no values", ce.ct.Name())
+}
+
+// Close implements sql.Closer, applying the edits made to the table and its conflicts.
+func (ce *conflictsEditor) Close(ctx *sql.Context) error {
+	if len(ce.edits) == 0 {
+		return nil
+	}
+
+	// edits to the table batched in the database have to be written before the table is read
+	db := ce.ct.db
+	tables := db.tablesFor(ctx)
+	if t, ok := tables[ce.ct.name]; ok {
+		if err := t.flushBatchedEdits(ctx); err != nil {
+			return err
+		}
+
+		delete(tables, ce.ct.name)
+	}
+
+	root := db.rootFor(ctx)
+	tbl, _, err := root.GetTable(ctx, ce.ct.name)
+
+	if err != nil {
+		return err
+	}
+
+	schemas, confData, err := tbl.GetConflicts(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	confEd := confData.Edit()
+	rowEd := rowData.Edit()
+	rowsEdited := false
+	for _, edit := range ce.edits {
+		if edit.resolved {
+			confEd.Remove(edit.key)
+			continue
+		}
+
+		val, ok, err := confData.MaybeGet(ctx, edit.key)
+
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		cnf, err := doltdb.ConflictFromTuple(val.(types.Tuple))
+
+		if err != nil {
+			return err
+		}
+
+		cnf = doltdb.NewConflict(cnf.Base, edit.ours, cnf.MergeValue)
+		cnfTpl, err := cnf.ToNomsList(root.VRW())
+
+		if err != nil {
+			return err
+		}
+
+		confEd.Set(edit.key, cnfTpl)
+		rowsEdited = true
+
+		if edit.ours == nil {
+			rowEd.Remove(edit.key)
+		} else {
+			rowEd.Set(edit.key, edit.ours)
+		}
+	}
+
+	confData, err = confEd.Map(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if rowsEdited {
+		rowData, err = rowEd.Map(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		tbl, err = tbl.UpdateRows(ctx, rowData)
+
+		if err == doltdb.ErrAppendOnly {
+			return fmt.Errorf(ErrAppendOnlyFmt, ce.ct.name)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if confData.Len() == 0 {
+		tbl, err = tbl.ClearConflicts()
+	} else {
+		tbl, err = tbl.SetConflicts(ctx, schemas, confData)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	root, err = root.PutTable(ctx, ce.ct.name, tbl)
+
+	if err != nil {
+		return err
+	}
+
+	db.setRootFor(ctx, root)
+	return db.written(ctx)
+}
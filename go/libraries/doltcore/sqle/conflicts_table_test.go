@@ -0,0 +1,135 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// conflictsTestRoot returns a root with a table other, and a table t whose rows conflict with those of a merged commit. Row 1 was changed
+// in both, and row 2 was changed in ours and deleted in theirs.
+func conflictsTestRoot(t *testing.T) *doltdb.RootValue {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, "create table t (pk bigint primary key, v bigint);\n"+
+		"create table other (pk bigint primary key);\n"+
+		"insert into t values (1, 10), (2, 20)")
+	require.NoError(t, err)
+
+	tbl, _, err := root.GetTable(ctx, "t")
+	require.NoError(t, err)
+	sch, err := tbl.GetSchema(ctx)
+	require.NoError(t, err)
+	pkCol, _ := sch.GetAllCols().GetByName("pk")
+	vCol, _ := sch.GetAllCols().GetByName("v")
+
+	rowVal := func(pk, v int64) (types.Value, types.Value) {
+		r, err := row.New(types.Format_7_18, sch, row.TaggedValues{pkCol.Tag: types.Int(pk), vCol.Tag: types.Int(v)})
+		require.NoError(t, err)
+		key, err := r.NomsMapKey(sch).Value(ctx)
+		require.NoError(t, err)
+		val, err := r.NomsMapValue(sch).Value(ctx)
+		require.NoError(t, err)
+		return key, val
+	}
+
+	key1, base1 := rowVal(1, 1)
+	_, ours1 := rowVal(1, 10)
+	_, theirs1 := rowVal(1, 100)
+	key2, base2 := rowVal(2, 2)
+	_, ours2 := rowVal(2, 20)
+
+	cnf1, err := doltdb.NewConflict(base1, ours1, theirs1).ToNomsList(root.VRW())
+	require.NoError(t, err)
+	cnf2, err := doltdb.NewConflict(base2, ours2, nil).ToNomsList(root.VRW())
+	require.NoError(t, err)
+	confData, err := types.NewMap(ctx, root.VRW(), key1, cnf1, key2, cnf2)
+	require.NoError(t, err)
+
+	schRef, err := tbl.GetSchemaRef()
+	require.NoError(t, err)
+	tbl, err = tbl.SetConflicts(ctx, doltdb.NewConflict(schRef, schRef, schRef), confData)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, "t", tbl)
+	require.NoError(t, err)
+
+	return root
+}
+
+func TestConflictsTables(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	engine, db := viewsEngine(dEnv, conflictsTestRoot(t))
+
+	assert.Equal(t, []sql.Row{{"t", uint64(2)}}, queryRows(t, engine, "select * from dolt_conflicts"))
+	assert.Equal(t, []sql.Row{
+		{int64(1), int64(1), int64(10), int64(100), int64(1)},
+		{int64(2), int64(2), int64(20), nil, int64(2)},
+	}, queryRows(t, engine, "select base_pk, base_v, our_v, their_v, our_pk from dolt_conflicts_t order by base_pk"))
+
+	// updating the our_ columns of a conflict updates the table's row
+	queryRows(t, engine, "update dolt_conflicts_t set our_v = their_v where base_pk = 1")
+	assert.Equal(t, []sql.Row{{int64(1), int64(100)}, {int64(2), int64(20)}}, queryRows(t, engine, "select * from t order by pk"))
+	assert.Equal(t, []sql.Row{{int64(100)}, {int64(20)}}, queryRows(t, engine, "select our_v from dolt_conflicts_t order by base_pk"))
+
+	// but the base_ and their_ columns, and the primary key, can't be updated
+	for _, query := range []string{
+		"update dolt_conflicts_t set their_v = 5",
+		"update dolt_conflicts_t set base_v = 5",
+		"update dolt_conflicts_t set our_pk = 5",
+	} {
+		_, iter, err := engine.Query(sql.NewContext(ctx, sql.WithQuery(query)), query)
+		if err == nil {
+			_, err = sql.RowIterToRows(iter)
+		}
+		assert.Error(t, err, query)
+	}
+
+	// deleting a conflict resolves it, keeping the table's row
+	queryRows(t, engine, "delete from dolt_conflicts_t where base_pk = 2")
+	assert.Equal(t, []sql.Row{{"t", uint64(1)}}, queryRows(t, engine, "select * from dolt_conflicts"))
+	assert.Equal(t, []sql.Row{{int64(1), int64(100)}, {int64(2), int64(20)}}, queryRows(t, engine, "select * from t order by pk"))
+
+	// setting every our_ column to null deletes the table's row
+	queryRows(t, engine, "update dolt_conflicts_t set our_pk = null, our_v = null")
+	assert.Equal(t, []sql.Row{{int64(2), int64(20)}}, queryRows(t, engine, "select * from t"))
+
+	has, err := db.Root().HasConflicts(ctx)
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	// once every conflict is resolved the table is no longer in conflict
+	queryRows(t, engine, "delete from dolt_conflicts_t")
+	assert.Empty(t, queryRows(t, engine, "select * from dolt_conflicts"))
+	has, err = db.Root().HasConflicts(ctx)
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	// tables without conflicts have empty conflicts tables
+	assert.Empty(t, queryRows(t, engine, "select * from dolt_conflicts_other"))
+}
@@ -0,0 +1,273 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/src-d/go-mysql-server/sql/plan"
+	"vitess.io/vitess/go/sqltypes"
+	"vitess.io/vitess/go/vt/proto/query"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	sqlTypes "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle/types"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const customTypesRuleName = "dolt_custom_types"
+
+// customSqlType is the SQL type of columns of a custom type (see schema.CustomType). Values of the type are given to
+// the engine as their string representations, are parsed when written to a table, and are compared with the type's
+// compare function.
+type customSqlType struct {
+	ct *schema.CustomType
+}
+
+var _ sql.Type = customSqlType{}
+
+// Compare implements sql.Type
+func (t customSqlType) Compare(a interface{}, b interface{}) (int, error) {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0, nil
+		case a == nil:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	}
+
+	aVal, err := t.toValue(a)
+	if err != nil {
+		return 0, err
+	}
+
+	bVal, err := t.toValue(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.ct.CompareValues(aVal, bVal)
+}
+
+// Convert implements sql.Type. Values are converted to the string representation of the value they're parsed as.
+func (t customSqlType) Convert(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	val, err := t.toValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.ct.Format(val)
+}
+
+// MustConvert implements sql.Type
+func (t customSqlType) MustConvert(v interface{}) interface{} {
+	converted, err := t.Convert(v)
+	if err != nil {
+		panic(err)
+	}
+	return converted
+}
+
+// SQL implements sql.Type
+func (t customSqlType) SQL(v interface{}) (sqltypes.Value, error) {
+	if v == nil {
+		return sqltypes.NULL, nil
+	}
+
+	converted, err := t.Convert(v)
+	if err != nil {
+		return sqltypes.Value{}, err
+	}
+
+	return sqltypes.MakeTrusted(sqltypes.Text, []byte(converted.(string))), nil
+}
+
+// Type implements sql.Type
+func (t customSqlType) Type() query.Type {
+	return sqltypes.Text
+}
+
+// Zero implements sql.Type
+func (t customSqlType) Zero() interface{} {
+	return ""
+}
+
+// String implements fmt.Stringer
+func (t customSqlType) String() string {
+	return strings.ToUpper(t.ct.Name)
+}
+
+// toValue parses the string representation of the SQL value given as a value of the type.
+func (t customSqlType) toValue(v interface{}) (types.Value, error) {
+	str, err := sql.Text.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := t.ct.Parse(str.(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value '%s': %v", t.ct.Name, str, err)
+	}
+
+	return val, nil
+}
+
+// colSqlType returns the SQL type of the column given.
+func colSqlType(col schema.Column) (sql.Type, error) {
+	if ct, ok := col.CustomType(); ok {
+		return customSqlType{ct}, nil
+	}
+
+	return sqlTypes.NomsKindToSqlType(col.Kind)
+}
+
+// colValToSqlVal returns the SQL value of the value given of the column given.
+func colValToSqlVal(col schema.Column, val types.Value) (interface{}, error) {
+	if ct, ok := col.CustomType(); ok && !types.IsNull(val) {
+		return ct.Format(val)
+	}
+
+	return sqlTypes.NomsValToSqlVal(val)
+}
+
+// sqlValToColVal returns the value of the column given for the SQL value given.
+func sqlValToColVal(col schema.Column, val interface{}) (types.Value, error) {
+	if ct, ok := col.CustomType(); ok && val != nil {
+		return customSqlType{ct}.toValue(val)
+	}
+
+	return sqlTypes.SqlValToNomsVal(val, col.Kind)
+}
+
+// convertToCustomTypes is an analyzer rule that converts the values compared with columns of a custom type to the custom
+// type, so that the comparisons are made by the type's compare function. Literals are converted in place, so that they
+// can still be used for index lookups.
+func convertToCustomTypes(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	return plan.TransformExpressionsUp(n, func(e sql.Expression) (sql.Expression, error) {
+		switch e.(type) {
+		case *expression.Equals, *expression.GreaterThan, *expression.GreaterThanOrEqual, *expression.LessThan,
+			*expression.LessThanOrEqual, *expression.In, *expression.NotIn:
+		default:
+			return e, nil
+		}
+
+		children := e.Children()
+
+		var typ customSqlType
+		for _, child := range children {
+			if t, ok := child.Type().(customSqlType); ok {
+				typ = t
+				break
+			}
+		}
+
+		if typ.ct == nil {
+			return e, nil
+		}
+
+		converted := make([]sql.Expression, len(children))
+		for i, child := range children {
+			var err error
+			converted[i], err = convertToCustomType(child, typ)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return e.WithChildren(converted...)
+	})
+}
+
+// convertToCustomType returns the expression given converted to the custom type given. Tuples have each of their
+// elements converted.
+func convertToCustomType(e sql.Expression, typ customSqlType) (sql.Expression, error) {
+	if e.Type() == typ {
+		return e, nil
+	}
+
+	switch e := e.(type) {
+	case *expression.Literal:
+		val, err := typ.Convert(e.Value())
+		if err != nil {
+			return nil, err
+		}
+		return expression.NewLiteral(val, typ), nil
+	case expression.Tuple:
+		converted := make([]sql.Expression, len(e))
+		for i, child := range e {
+			var err error
+			converted[i], err = convertToCustomType(child, typ)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return expression.NewTuple(converted...), nil
+	default:
+		return &customTypeConversion{e, typ}, nil
+	}
+}
+
+// customTypeConversion is an expression that converts the values of its child to a custom type.
+type customTypeConversion struct {
+	child sql.Expression
+	typ   customSqlType
+}
+
+var _ sql.Expression = (*customTypeConversion)(nil)
+
+// Children implements sql.Expression
+func (c *customTypeConversion) Children() []sql.Expression { return []sql.Expression{c.child} }
+
+// Type implements sql.Expression
+func (c *customTypeConversion) Type() sql.Type { return c.typ }
+
+// Resolved implements sql.Expression
+func (c *customTypeConversion) Resolved() bool { return c.child.Resolved() }
+
+// IsNullable implements sql.Expression
+func (c *customTypeConversion) IsNullable() bool { return c.child.IsNullable() }
+
+// WithChildren implements sql.Expression
+func (c *customTypeConversion) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(c, len(children), 1)
+	}
+	return &customTypeConversion{children[0], c.typ}, nil
+}
+
+// String implements fmt.Stringer
+func (c *customTypeConversion) String() string {
+	return fmt.Sprintf("CONVERT(%s, %s)", c.child, c.typ)
+}
+
+// Eval implements sql.Expression
+func (c *customTypeConversion) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := c.child.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.typ.Convert(val)
+}
@@ -0,0 +1,176 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func init() {
+	for _, ct := range []schema.CustomType{versionType, ipv4Type} {
+		if err := schema.RegisterCustomType(ct); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// versionType is a custom type of dotted version numbers, which are ordered by their parts rather than as strings
+var versionType = schema.CustomType{
+	Name: "version",
+	Kind: types.StringKind,
+	Parse: func(str string) (types.Value, error) {
+		for _, part := range strings.Split(str, ".") {
+			if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+				return nil, errors.New("not a version")
+			}
+		}
+		return types.String(str), nil
+	},
+	Format: func(val types.Value) (string, error) {
+		return string(val.(types.String)), nil
+	},
+	Compare: func(a, b types.Value) (int, error) {
+		aParts, bParts := strings.Split(string(a.(types.String)), "."), strings.Split(string(b.(types.String)), ".")
+		for i := 0; i < len(aParts) && i < len(bParts); i++ {
+			aPart, _ := strconv.ParseUint(aParts[i], 10, 64)
+			bPart, _ := strconv.ParseUint(bParts[i], 10, 64)
+			if aPart != bPart {
+				if aPart < bPart {
+					return -1, nil
+				}
+				return 1, nil
+			}
+		}
+		return len(aParts) - len(bParts), nil
+	},
+}
+
+// ipv4Type is a custom type of IPv4 addresses, stored as unsigned integers
+var ipv4Type = schema.CustomType{
+	Name: "ipv4",
+	Kind: types.UintKind,
+	Parse: func(str string) (types.Value, error) {
+		ip := net.ParseIP(str).To4()
+		if ip == nil {
+			return nil, fmt.Errorf("'%s' is not an IPv4 address", str)
+		}
+		return types.Uint(uint64(ip[0])<<24 | uint64(ip[1])<<16 | uint64(ip[2])<<8 | uint64(ip[3])), nil
+	},
+	Format: func(val types.Value) (string, error) {
+		n := uint64(val.(types.Uint))
+		return net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n)).String(), nil
+	},
+}
+
+func TestCustomTypes(t *testing.T) {
+	engine, root := customTypesEngine(t)
+	sqlCtx := sql.NewContext(context.Background())
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []sql.Row
+	}{
+		{"select", "select * from t order by pk", []sql.Row{{int64(1), "1.9", "10.0.0.2"}, {int64(2), "1.10", "10.0.0.10"}, {int64(3), "1.2", nil}}},
+		{"order by", "select pk from t order by v", []sql.Row{{int64(3)}, {int64(1)}, {int64(2)}}},
+		{"order by desc", "select pk from t order by ip desc", []sql.Row{{int64(2)}, {int64(1)}, {int64(3)}}},
+		{"greater than", "select pk from t where v > '1.9'", []sql.Row{{int64(2)}}},
+		{"literal on the left", "select pk from t where '1.9' < v", []sql.Row{{int64(2)}}},
+		{"equals", "select pk from t where ip = '10.0.0.10'", []sql.Row{{int64(2)}}},
+		{"less than", "select pk from t where ip < '10.0.0.9'", []sql.Row{{int64(1)}}},
+		{"in", "select pk from t where v in ('1.10', '1.2') order by pk", []sql.Row{{int64(2)}, {int64(3)}}},
+		{"not in", "select pk from t where v not in ('1.10', '1.2')", []sql.Row{{int64(1)}}},
+		{"columns", "select t.pk, u.v from t join u on t.v < u.v order by t.pk, u.v", []sql.Row{{int64(1), "1.10"}, {int64(3), "1.9"}, {int64(3), "1.10"}}},
+		{"primary key", "select v from u where v = '1.10'", []sql.Row{{"1.10"}}},
+		{"primary key range", "select v from u where v >= '1.9' order by v", []sql.Row{{"1.9"}, {"1.10"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, iter, err := engine.Query(sqlCtx, test.query)
+			require.NoError(t, err)
+			rows, err := sql.RowIterToRows(iter)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, rows)
+		})
+	}
+
+	// values are stored as the kinds of their types
+	tbl, _, err := root.GetTable(context.Background(), "t")
+	require.NoError(t, err)
+	sch, err := tbl.GetSchema(context.Background())
+	require.NoError(t, err)
+	r, ok, err := tbl.GetRowByPKVals(context.Background(), row.TaggedValues{0: types.Int(2)}, sch)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ip, _ := r.GetColVal(2)
+	assert.Equal(t, types.Uint(0x0a00000a), ip)
+
+	_, err = executeModify(context.Background(), root, "insert into t values (4, '1.x', null)")
+	assert.Error(t, err)
+	_, err = executeModify(context.Background(), root, "update t set ip = '10.0.0' where pk = 1")
+	assert.Error(t, err)
+	_, _, err = engine.Query(sqlCtx, "select pk from t where v = 'latest'")
+	assert.Error(t, err)
+}
+
+// customTypesEngine returns an engine for a database whose tables t and u have columns of custom types, and the root
+// of the database.
+func customTypesEngine(t *testing.T) (*sqle.Engine, *doltdb.RootValue) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+
+	v := schema.NewColumn("v", 1, types.StringKind, false)
+	v.TypeName = versionType.Name
+	ip := schema.NewColumn("ip", 2, types.UintKind, false)
+	ip.TypeName = ipv4Type.Name
+	dtestutils.CreateTestTable(t, dEnv, "t", dtestutils.CreateSchema(schema.NewColumn("pk", 0, types.IntKind, true), v, ip))
+
+	v = schema.NewColumn("v", 0, types.StringKind, true)
+	v.TypeName = versionType.Name
+	dtestutils.CreateTestTable(t, dEnv, "u", dtestutils.CreateSchema(v))
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	for _, query := range []string{
+		"insert into t values (1, '1.9', '10.0.0.2'), (2, '1.10', '10.0.0.10'), (3, '1.2', null)",
+		"insert into u values ('1.9'), ('1.10'), ('1.2')",
+	} {
+		root, err = executeModify(ctx, root, query)
+		require.NoError(t, err, query)
+	}
+
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, nil, nil))
+	return engine, root
+}
@@ -18,12 +18,17 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/src-d/go-mysql-server/sql"
+	"vitess.io/vitess/go/vt/sqlparser"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	dsql "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -39,11 +44,29 @@ const (
 // Database implements sql.Database for a dolt DB.
 type Database struct {
 	name      string
-	root      *doltdb.RootValue
 	ddb       *doltdb.DoltDB
 	rs        *env.RepoState
 	batchMode batchMode
-	tables    map[string]*DoltTable
+	committer *Committer
+	watcher   *RootWatcher
+
+	rootMu      sync.Mutex
+	root        *doltdb.RootValue
+	tables      map[string]*DoltTable
+	rootVersion uint64 // The version of the watcher's root that the database's root is based on
+
+	writeMu sync.Mutex // Held while a statement's writes are merged into the root and published
+
+	stmtsMu sync.Mutex
+	stmts   map[uint32]*statementRoot // The root of the statement each session is running, by session id
+
+	viewsHash   hash.Hash // The hash of the dolt_schemas table the database's views were last loaded from
+	viewsLoaded bool
+
+	sessionsMu sync.Mutex
+	sessions   map[uint32]*sessionRoots // The dolt_head and dolt_working state of each session, by session id
+
+	atCommit string // The hash of the commit a read-only database was opened at, or empty if it's writable
 }
 
 // NewDatabase returns a new dolt database to use in queries.
@@ -55,6 +78,8 @@ func NewDatabase(name string, root *doltdb.RootValue, ddb *doltdb.DoltDB, rs *en
 		rs:        rs,
 		batchMode: single,
 		tables:    make(map[string]*DoltTable),
+		stmts:     make(map[uint32]*statementRoot),
+		sessions:  make(map[uint32]*sessionRoots),
 	}
 }
 
@@ -68,7 +93,42 @@ func NewBatchedDatabase(name string, root *doltdb.RootValue, ddb *doltdb.DoltDB,
 		rs:        rs,
 		batchMode: batched,
 		tables:    make(map[string]*DoltTable),
+		stmts:     make(map[uint32]*statementRoot),
+		sessions:  make(map[uint32]*sessionRoots),
+	}
+}
+
+// NewCommitDatabase returns a read-only dolt database for the root of the commit the spec given resolves to, in which
+// HEAD refers to the head of the branch checked out. Statements that would write to it fail with ErrReadOnly.
+func NewCommitDatabase(ctx context.Context, name string, ddb *doltdb.DoltDB, rs *env.RepoState, spec string) (*Database, error) {
+	cs, err := doltdb.NewCommitSpec(spec, rs.Head.Ref.String())
+	if err != nil {
+		return nil, err
+	}
+
+	cm, err := ddb.Resolve(ctx, cs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve '%s': %v", spec, err)
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := cm.GetRootValue()
+	if err != nil {
+		return nil, err
 	}
+
+	db := NewDatabase(name, root, ddb, rs)
+	db.atCommit = h.String()
+	return db, nil
+}
+
+// ReadOnly returns whether the database was opened at a commit, so statements that would write to it fail.
+func (db *Database) ReadOnly() bool {
+	return db.atCommit != ""
 }
 
 // Name returns the name of this database, set at creation time.
@@ -100,11 +160,42 @@ func (db *Database) GetTableInsensitive(ctx context.Context, tblName string) (sq
 		return dh, true, nil
 	}
 
+	if strings.HasPrefix(lwrName, DoltBlameTablePrefix) {
+		tblName = tblName[len(DoltBlameTablePrefix):]
+		bt, ok, err := NewBlameTable(ctx, tblName, db.ddb, db.rs)
+
+		if err != nil || !ok {
+			return nil, false, err
+		}
+
+		return bt, true, nil
+	}
+
 	if lwrName == LogTableName {
 		return NewLogTable(db.ddb, db.rs), true, nil
 	}
 
-	tableNames, err := db.root.GetTableNames(ctx)
+	if lwrName == ConflictsTableName {
+		return NewConflictsTable(db.rootFor(ctx)), true, nil
+	}
+
+	if strings.HasPrefix(lwrName, DoltConflictsTablePrefix) {
+		tblName = tblName[len(DoltConflictsTablePrefix):]
+		ct, ok, err := NewTableConflictsTable(ctx, tblName, db)
+
+		if err != nil || !ok {
+			return nil, false, err
+		}
+
+		return ct, true, nil
+	}
+
+	if i := strings.Index(tblName, AsOfSeparator); i > 0 {
+		return db.getAsOfTable(ctx, tblName[:i], tblName[i+len(AsOfSeparator):])
+	}
+
+	root := db.rootFor(ctx)
+	tableNames, err := root.GetTableNames(ctx)
 
 	if err != nil {
 		return nil, false, err
@@ -116,11 +207,12 @@ func (db *Database) GetTableInsensitive(ctx context.Context, tblName string) (sq
 		return nil, false, nil
 	}
 
-	if table, ok := db.tables[exactName]; ok {
+	tables := db.tablesFor(ctx)
+	if table, ok := tables[exactName]; ok {
 		return table, true, nil
 	}
 
-	tbl, ok, err := db.root.GetTable(ctx, exactName)
+	tbl, ok, err := root.GetTable(ctx, exactName)
 
 	if err != nil {
 		return nil, false, err
@@ -135,29 +227,35 @@ func (db *Database) GetTableInsensitive(ctx context.Context, tblName string) (sq
 	}
 
 	table := &DoltTable{name: exactName, table: tbl, sch: sch, db: db}
-	db.tables[exactName] = table
+	tables[exactName] = table
 	return table, true, nil
 }
 
 func (db *Database) GetTableNames(ctx context.Context) ([]string, error) {
-	return db.root.GetTableNames(ctx)
+	return db.rootFor(ctx).GetTableNames(ctx)
 }
 
 // Root returns the root value for the database.
 func (db *Database) Root() *doltdb.RootValue {
+	db.rootMu.Lock()
+	defer db.rootMu.Unlock()
+
 	return db.root
 }
 
 // Set a new root value for the database. Can be used if the dolt working
 // set value changes outside of the basic SQL execution engine.
 func (db *Database) SetRoot(newRoot *doltdb.RootValue) {
-	// TODO: races
+	db.rootMu.Lock()
+	defer db.rootMu.Unlock()
+
 	db.root = newRoot
 }
 
 // DropTable drops the table with the name given
 func (db *Database) DropTable(ctx *sql.Context, tableName string) error {
-	tableExists, err := db.root.HasTable(ctx, tableName)
+	root := db.rootFor(ctx)
+	tableExists, err := root.HasTable(ctx, tableName)
 	if err != nil {
 		return err
 	}
@@ -166,61 +264,188 @@ func (db *Database) DropTable(ctx *sql.Context, tableName string) error {
 		return sql.ErrTableNotFound.New(tableName)
 	}
 
-	newRoot, err := db.root.RemoveTables(ctx, tableName)
+	newRoot, err := root.RemoveTables(ctx, tableName)
 	if err != nil {
 		return err
 	}
 
-	delete(db.tables, tableName)
+	delete(db.tablesFor(ctx), tableName)
 
-	db.SetRoot(newRoot)
+	db.setRootFor(ctx, newRoot)
 
-	return nil
+	return db.written(ctx)
+}
+
+// TruncateTable removes all the rows of the table with the name given, keeping its schema and indexes.
+func (db *Database) TruncateTable(ctx *sql.Context, tableName string) error {
+	root := db.rootFor(ctx)
+	tbl, ok, err := root.GetTable(ctx, tableName)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return sql.ErrTableNotFound.New(tableName)
+	}
+
+	truncated, err := tbl.Truncate(ctx)
+	if err == doltdb.ErrAppendOnly {
+		return fmt.Errorf(ErrAppendOnlyFmt, tableName)
+	} else if err != nil {
+		return err
+	}
+
+	newRoot, err := root.PutTable(ctx, tableName, truncated)
+	if err != nil {
+		return err
+	}
+
+	delete(db.tablesFor(ctx), tableName)
+
+	db.setRootFor(ctx, newRoot)
+
+	return db.written(ctx)
 }
 
 // CreateTable creates a table with the name and schema given.
-func (db *Database) CreateTable(ctx *sql.Context, tableName string, schema sql.Schema) error {
+func (db *Database) CreateTable(ctx *sql.Context, tableName string, sqlSch sql.Schema) error {
 
 	if !doltdb.IsValidTableName(tableName) {
 		return fmt.Errorf("Invalid table name: '%v'", tableName)
 	}
 
-	if exists, err := db.root.HasTable(ctx, tableName); err != nil {
+	root := db.rootFor(ctx)
+	if exists, err := root.HasTable(ctx, tableName); err != nil {
 		return err
 	} else if exists {
 		return sql.ErrTableAlreadyExists.New(tableName)
 	}
 
-	doltSch, err := SqlSchemaToDoltSchema(schema)
+	doltSch, err := SqlSchemaToDoltSchema(sqlSch)
 	if err != nil {
 		return err
 	}
 
-	schVal, err := encoding.MarshalAsNomsValue(ctx, db.root.VRW(), doltSch)
+	// The engine doesn't pass the table options along, so the table's comment is read from the query itself
+	if comment := tableCommentFromQuery(ctx.Query()); comment != "" {
+		doltSch = schema.SchemaWithComment(doltSch, comment)
+	}
+
+	schVal, err := encoding.MarshalAsNomsValue(ctx, root.VRW(), doltSch)
 	if err != nil {
 		return err
 	}
 
-	m, err := types.NewMap(ctx, db.root.VRW())
+	m, err := types.NewMap(ctx, root.VRW())
 	if err != nil {
 		return err
 	}
 
-	tbl, err := doltdb.NewTable(ctx, db.root.VRW(), schVal, m)
+	tbl, err := doltdb.NewTable(ctx, root.VRW(), schVal, m)
 	if err != nil {
 		return err
 	}
 
-	newRoot, err := db.root.PutTable(ctx, tableName, tbl)
+	newRoot, err := root.PutTable(ctx, tableName, tbl)
 	if err != nil {
 		return err
 	}
 
-	db.SetRoot(newRoot)
+	db.setRootFor(ctx, newRoot)
+
+	return db.written(ctx)
+}
+
+// tableCommentFromQuery returns the table comment given by the create table statement given, or the empty string if
+// it doesn't have one.
+func tableCommentFromQuery(query string) string {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return ""
+	}
+
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.TableSpec == nil {
+		return ""
+	}
+
+	return dsql.TableCommentFromOptions(ddl.TableSpec.Options)
+}
 
+// written is called after each statement that writes to the database. It merges the statement's writes into the
+// database's root, and notifies the database's committer and root watcher, if it has them. Writes made by a query run
+// as part of a larger statement are left in the statement's root until the statement is done.
+func (db *Database) written(ctx context.Context) error {
+	if isStatementPart(ctx) {
+		return nil
+	}
+
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	// The statement's writes are merged with those published by other databases watching the same environment
+	if db.watcher != nil && db.statement(ctx) != nil {
+		db.refreshRoot()
+	}
+
+	if err := db.mergeStatement(ctx); err != nil {
+		return err
+	}
+
+	if db.committer != nil {
+		return db.committer.written(ctx)
+	}
+	if db.watcher != nil {
+		return db.watcher.written(ctx, db, nil)
+	}
 	return nil
 }
 
+// refreshRoot sets the database's root to the latest root of its watcher, if it has changed since the database's root
+// was last set from or published to the watcher, and returns the version of the watcher's root it's based on.
+func (db *Database) refreshRoot() uint64 {
+	db.rootMu.Lock()
+	version := db.rootVersion
+	db.rootMu.Unlock()
+
+	// The watcher calls setRoot while holding its lock, so it mustn't be called while holding rootMu
+	if root, latest, changed := db.watcher.latest(version); changed {
+		db.setRoot(root, latest)
+		return latest
+	}
+
+	return version
+}
+
+// setRoot replaces the database's root with a version of its watcher's root, dropping the tables loaded from the old
+// root.
+func (db *Database) setRoot(root *doltdb.RootValue, version uint64) {
+	db.rootMu.Lock()
+	defer db.rootMu.Unlock()
+
+	db.root = root
+	db.rootVersion = version
+	db.tables = make(map[string]*DoltTable)
+}
+
+// replaceRoot replaces the database's root with one based on the same version of its watcher's root, dropping the
+// tables loaded from the old root.
+func (db *Database) replaceRoot(root *doltdb.RootValue) {
+	db.rootMu.Lock()
+	defer db.rootMu.Unlock()
+
+	db.root = root
+	db.tables = make(map[string]*DoltTable)
+}
+
+// setRootVersion records that the database's root is the version of its watcher's root given.
+func (db *Database) setRootVersion(version uint64) {
+	db.rootMu.Lock()
+	defer db.rootMu.Unlock()
+
+	db.rootVersion = version
+}
+
 // Flushes the current batch of outstanding changes and returns any errors.
 func (db *Database) Flush(ctx context.Context) error {
 	for name, table := range db.tables {
@@ -16,6 +16,7 @@ package sqle
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/src-d/go-mysql-server/sql"
@@ -24,6 +25,7 @@ import (
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/diff"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/rowconv"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/store/types"
@@ -33,6 +35,13 @@ const (
 	DoltDiffTablePrefix = "dolt_diff_"
 	toCommit            = "to_commit"
 	fromCommit          = "from_commit"
+
+	// diffTypeCol is the name of the column giving whether a row was added, removed or modified between the commits
+	diffTypeCol = "diff_type"
+
+	diffTypeAdded    = "added"
+	diffTypeRemoved  = "removed"
+	diffTypeModified = "modified"
 )
 
 var _ sql.FilteredTable = (*DiffTable)(nil)
@@ -48,6 +57,7 @@ type DiffTable struct {
 	fromCommitVal string
 	toCommitVal   string
 	filters       []sql.Expression
+	err           error
 }
 
 func NewDiffTable(ctx context.Context, name string, ddb *doltdb.DoltDB, rs *env.RepoState) (*DiffTable, error) {
@@ -89,7 +99,7 @@ func NewDiffTable(ctx context.Context, name string, ddb *doltdb.DoltDB, rs *env.
 		return nil, err
 	}
 
-	return &DiffTable{name, ddb, rs, ss, j, root2, root1, "current", "HEAD", nil}, nil
+	return &DiffTable{name, ddb, rs, ss, j, root2, root1, "current", "HEAD", nil, nil}, nil
 }
 
 func (dt *DiffTable) Name() string {
@@ -108,7 +118,7 @@ func (dt *DiffTable) Schema() sql.Schema {
 		panic(err)
 	}
 
-	return sqlSch
+	return append(sqlSch, &sql.Column{Name: diffTypeCol, Type: sql.Text, Source: dt.Name()})
 }
 
 func toNamer(name string) string {
@@ -151,6 +161,10 @@ func tableData(ctx *sql.Context, root *doltdb.RootValue, tblName string, ddb *do
 }
 
 func (dt *DiffTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	if dt.err != nil {
+		return nil, dt.err
+	}
+
 	fromData, fromSch, err := tableData(ctx, dt.fromRoot, dt.name, dt.ddb)
 
 	if err != nil {
@@ -196,6 +210,7 @@ var _ sql.RowIter = (*diffRowItr)(nil)
 type diffRowItr struct {
 	ad      *diff.AsyncDiffer
 	diffSrc *diff.RowDiffSource
+	joiner  *rowconv.Joiner
 	sch     schema.Schema
 	to      string
 	from    string
@@ -210,7 +225,7 @@ func newDiffRowItr(ctx context.Context, joiner *rowconv.Joiner, rowDataFrom, row
 	src := diff.NewRowDiffSource(ad, joiner)
 	src.AddInputRowConversion(convFrom, convTo)
 
-	return &diffRowItr{ad, src, joiner.GetSchema(), to, from, fromTag, toTag}
+	return &diffRowItr{ad, src, joiner, joiner.GetSchema(), to, from, fromTag, toTag}
 }
 
 // Next returns the next row
@@ -221,6 +236,12 @@ func (itr *diffRowItr) Next() (sql.Row, error) {
 		return nil, err
 	}
 
+	diffType, err := itr.diffType(r)
+
+	if err != nil {
+		return nil, err
+	}
+
 	r, err = r.SetColVal(itr.toTag, types.String(itr.to), itr.sch)
 
 	if err != nil {
@@ -233,7 +254,35 @@ func (itr *diffRowItr) Next() (sql.Row, error) {
 		return nil, err
 	}
 
-	return doltRowToSqlRow(r, itr.sch)
+	sqlRow, err := doltRowToSqlRow(r, itr.sch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(sqlRow, diffType), nil
+}
+
+// diffType returns whether the joined diff row given was added, removed or modified, from which of its sides have
+// values.
+func (itr *diffRowItr) diffType(r row.Row) (string, error) {
+	rows, err := itr.joiner.Split(r)
+
+	if err != nil {
+		return "", err
+	}
+
+	_, hasFrom := rows[diff.From]
+	_, hasTo := rows[diff.To]
+
+	switch {
+	case !hasFrom:
+		return diffTypeAdded, nil
+	case !hasTo:
+		return diffTypeRemoved, nil
+	default:
+		return diffTypeModified, nil
+	}
 }
 
 // Close closes the iterator
@@ -300,22 +349,12 @@ func (dt *DiffTable) WithFilters(filters []sql.Expression) sql.Table {
 
 		value = strings.Trim(value, " \t\n\r\"")
 
-		cs, err := doltdb.NewCommitSpec(value, dt.rs.Head.Ref.String())
-
-		if err != nil {
-			panic(err)
-		}
-
-		cm, err := dt.ddb.Resolve(ctx, cs)
+		root, err := dt.resolveRoot(ctx, value)
 
 		if err != nil {
-			panic(err)
-		}
-
-		root, err := cm.GetRootValue()
-
-		if err != nil {
-			panic(err)
+			// returned by PartitionRows, since filters can't fail to apply
+			dt.err = err
+			break
 		}
 
 		switch fieldName {
@@ -332,6 +371,24 @@ func (dt *DiffTable) WithFilters(filters []sql.Expression) sql.Table {
 	return dt
 }
 
+// resolveRoot returns the root of the commit given by the ref or commit spec given, which is resolved relative to the
+// current branch.
+func (dt *DiffTable) resolveRoot(ctx context.Context, value string) (*doltdb.RootValue, error) {
+	cs, err := doltdb.NewCommitSpec(value, dt.rs.Head.Ref.String())
+
+	if err != nil {
+		return nil, err
+	}
+
+	cm, err := dt.ddb.Resolve(ctx, cs)
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve '%s': %v", value, err)
+	}
+
+	return cm.GetRootValue()
+}
+
 // Filters returns the list of filters that are applied to this table.
 func (dt *DiffTable) Filters() []sql.Expression {
 	return dt.filters
@@ -0,0 +1,62 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+)
+
+func TestDiffTableBetweenRefs(t *testing.T) {
+	ctx := context.Background()
+	dEnv := branchFunctionsEnv(t)
+	require.NoError(t, actions.CreateBranch(ctx, dEnv, "feature", "HEAD", false))
+	require.NoError(t, actions.CheckoutBranch(ctx, dEnv, "feature"))
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine, db := viewsEngine(dEnv, root)
+	queryRows(t, engine, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	queryRows(t, engine, "update people set age = 99 where id = 0")
+	queryRows(t, engine, "delete from people where id = 1")
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, db.Root()))
+	require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+	require.NoError(t, actions.CommitStaged(ctx, dEnv, "changed people", time.Now(), false))
+
+	rows := queryRows(t, engine, `select to_id, from_id, from_age, to_age, diff_type from dolt_diff_people
+		where from_commit = 'master' and to_commit = 'feature' order by coalesce(to_id, from_id)`)
+	assert.Equal(t, []sql.Row{
+		{int64(0), int64(0), int64(40), int64(99), "modified"},
+		{nil, int64(1), int64(38), nil, "removed"},
+		{int64(10), nil, nil, nil, "added"},
+	}, rows)
+
+	rows = queryRows(t, engine, `select to_id from dolt_diff_people
+		where from_commit = 'master' and to_commit = 'feature' and diff_type = 'added'`)
+	assert.Equal(t, []sql.Row{{int64(10)}}, rows)
+
+	_, iter, err := engine.Query(sql.NewEmptyContext(), `select * from dolt_diff_people where from_commit = 'no_such_branch'`)
+	if err == nil {
+		_, err = sql.RowIterToRows(iter)
+	}
+	assert.Error(t, err)
+}
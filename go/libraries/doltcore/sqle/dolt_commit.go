@@ -0,0 +1,92 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/src-d/go-mysql-server/auth"
+	"github.com/src-d/go-mysql-server/sql"
+)
+
+// DoltCommitFuncName is the name of the DOLT_COMMIT function.
+const DoltCommitFuncName = "dolt_commit"
+
+// DoltCommitFunction returns the DOLT_COMMIT(message) function, which commits the writes made through the committer
+// given and returns the hash of the new commit. If a is not nil, only users with write permission may call it.
+func DoltCommitFunction(c *Committer, a auth.Auth) sql.Function {
+	return sql.Function1{
+		Name: DoltCommitFuncName,
+		Fn: func(msg sql.Expression) sql.Expression {
+			return &DoltCommitFunc{committer: c, auth: a, msg: msg}
+		},
+	}
+}
+
+// DoltCommitFunc is the DOLT_COMMIT(message) function.
+type DoltCommitFunc struct {
+	committer *Committer
+	auth      auth.Auth
+	msg       sql.Expression
+}
+
+var _ sql.Expression = (*DoltCommitFunc)(nil)
+
+// Children implements sql.Expression
+func (f *DoltCommitFunc) Children() []sql.Expression { return []sql.Expression{f.msg} }
+
+// Type implements sql.Expression
+func (f *DoltCommitFunc) Type() sql.Type { return sql.Text }
+
+// Resolved implements sql.Expression
+func (f *DoltCommitFunc) Resolved() bool { return f.msg.Resolved() }
+
+// IsNullable implements sql.Expression
+func (f *DoltCommitFunc) IsNullable() bool { return false }
+
+// WithChildren implements sql.Expression
+func (f *DoltCommitFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(f, len(children), 1)
+	}
+	return &DoltCommitFunc{committer: f.committer, auth: f.auth, msg: children[0]}, nil
+}
+
+// String implements fmt.Stringer
+func (f *DoltCommitFunc) String() string { return fmt.Sprintf("DOLT_COMMIT(%s)", f.msg) }
+
+// Eval implements sql.Expression
+func (f *DoltCommitFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	if f.auth != nil {
+		if err := f.auth.Allowed(ctx, auth.WritePerm); err != nil {
+			return nil, err
+		}
+	}
+
+	msg, err := f.msg.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err = sql.Text.Convert(msg)
+	if err != nil {
+		return nil, err
+	} else if msg == nil || msg.(string) == "" {
+		return nil, errors.New("DOLT_COMMIT requires a commit message")
+	}
+
+	return f.committer.Commit(ctx, msg.(string))
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+var ErrDropSystemTableFmt = "table %s is a system table and cannot be dropped"
+
+// ExecuteDropTable executes the DROP TABLE statement given, removing each of the tables named from the database's
+// root. Table names are matched case insensitively. All of the tables are checked before any are dropped, so that the
+// statement drops either all of them or none: a table that doesn't exist is an error unless IF EXISTS is given, in
+// which case it's skipped. Generated system tables, such as dolt_log and the diff and history tables, can't be
+// dropped.
+//
+// The engine's DROP TABLE node doesn't report errors returned when dropping a table, which is why this is handled
+// here rather than by the engine.
+func ExecuteDropTable(ctx *sql.Context, db *Database, ddl *sqlparser.DDL) error {
+	var toDrop []string
+	seen := make(map[string]bool)
+	for _, tableName := range ddl.FromTables {
+		name := tableName.Name.String()
+		tbl, ok, err := db.GetTableInsensitive(ctx, name)
+
+		if err != nil {
+			return err
+		} else if !ok {
+			if ddl.IfExists {
+				continue
+			}
+			return sql.ErrTableNotFound.New(name)
+		}
+
+		if _, ok := tbl.(*DoltTable); !ok {
+			return fmt.Errorf(ErrDropSystemTableFmt, tbl.Name())
+		}
+
+		if !seen[tbl.Name()] {
+			seen[tbl.Name()] = true
+			toDrop = append(toDrop, tbl.Name())
+		}
+	}
+
+	for _, name := range toDrop {
+		if err := db.DropTable(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
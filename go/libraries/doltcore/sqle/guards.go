@@ -0,0 +1,536 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/src-d/go-mysql-server/sql/plan"
+)
+
+// Session variables holding the limits applied to each query run in a session. A value of 0, or an unset variable,
+// means no limit. Sessions created with NewQueryLimitSession can only use these to lower the limits they were given.
+const (
+	// MaxRowsReturnedVar limits the number of rows a query may return.
+	MaxRowsReturnedVar = "max_rows_returned"
+	// MaxScanRowsVar limits the number of rows a query may read from tables.
+	MaxScanRowsVar = "max_scan_rows"
+	// MaxJoinSizeVar limits the estimated number of row combinations a join may examine.
+	MaxJoinSizeVar = "max_join_size"
+	// MaxExecutionTimeVar limits the number of milliseconds a query that only reads may run, as in MySQL.
+	MaxExecutionTimeVar = "max_execution_time"
+)
+
+const queryGuardsRuleName = "dolt_query_guards"
+
+var ErrMaxRowsReturnedFmt = "query aborted: it returned more than " + MaxRowsReturnedVar + " (%d) rows"
+var ErrMaxScanRowsFmt = "query aborted: it read more than " + MaxScanRowsVar + " (%d) rows from tables"
+var ErrMaxJoinSizeFmt = "query aborted: the join would examine an estimated %d rows, more than " + MaxJoinSizeVar + " (%d)"
+var ErrMaxExecutionTimeFmt = "query aborted: it ran for longer than " + MaxExecutionTimeVar + " (%d ms)"
+var ErrQueryLimitRaisedFmt = "%s can't be set to %d, as the session's limit is %d"
+
+// QueryLimits are the limits that abort queries which would return, read or join too many rows, or run for too long.
+// They protect a shared server from accidental cross products and other runaway queries. A zero limit means no limit.
+type QueryLimits struct {
+	MaxRowsReturned  int64
+	MaxScanRows      int64
+	MaxJoinSize      int64
+	MaxExecutionTime int64 // In milliseconds
+}
+
+// SetSessionDefaults sets the session variables for each of the non-zero limits, so that they apply to the queries run
+// in the session unless changed with SET.
+func (l QueryLimits) SetSessionDefaults(sess sql.Session) {
+	for name, limit := range map[string]int64{
+		MaxRowsReturnedVar:  l.MaxRowsReturned,
+		MaxScanRowsVar:      l.MaxScanRows,
+		MaxJoinSizeVar:      l.MaxJoinSize,
+		MaxExecutionTimeVar: l.MaxExecutionTime,
+	} {
+		if limit != 0 {
+			sess.Set(name, sql.Int64, limit)
+		}
+	}
+}
+
+// byVar returns pointers to each of the limits, keyed by the name of the session variable holding it.
+func (l *QueryLimits) byVar() map[string]*int64 {
+	return map[string]*int64{
+		MaxRowsReturnedVar:  &l.MaxRowsReturned,
+		MaxScanRowsVar:      &l.MaxScanRows,
+		MaxJoinSizeVar:      &l.MaxJoinSize,
+		MaxExecutionTimeVar: &l.MaxExecutionTime,
+	}
+}
+
+// queryLimitSession is a session whose queries can't exceed the limits it was created with. The limits are held by the
+// session rather than in session variables so that they can't be raised or removed with SET.
+type queryLimitSession struct {
+	sql.Session
+	limits QueryLimits
+}
+
+// NewQueryLimitSession returns a session that wraps the one given and whose queries are subject to the limits given.
+// The session variables for each limit can still lower it with SET, but an attempt to raise a limit or remove it by
+// setting it to 0 is an error.
+func NewQueryLimitSession(sess sql.Session, limits QueryLimits) sql.Session {
+	return &queryLimitSession{Session: sess, limits: limits}
+}
+
+// sessionLimits returns the limits that the session given was created with, if it was created by NewQueryLimitSession
+// or wraps a session that was.
+func sessionLimits(sess sql.Session) QueryLimits {
+	for {
+		switch s := sess.(type) {
+		case *queryLimitSession:
+			return s.limits
+		case *rowFilterSession:
+			sess = s.Session
+		default:
+			return QueryLimits{}
+		}
+	}
+}
+
+// queryLimitsFromSession returns the limits of the session given: the lower of each limit it was created with and the
+// one set in its session variables.
+func queryLimitsFromSession(sess sql.Session) (QueryLimits, error) {
+	limits := sessionLimits(sess)
+	for name, dest := range limits.byVar() {
+		_, val := sess.Get(name)
+		if val == nil {
+			continue
+		}
+
+		limit, err := sql.Int64.Convert(val)
+		if err != nil || limit.(int64) < 0 {
+			return QueryLimits{}, fmt.Errorf("invalid value '%v' for %s, expected a non-negative integer", val, name)
+		}
+
+		if limit := limit.(int64); limit > 0 && (*dest == 0 || limit < *dest) {
+			*dest = limit
+		}
+	}
+
+	return limits, nil
+}
+
+// checkLimitsNotRaised returns an error if the node given is a SET statement that would raise or remove one of the
+// limits the session was created with.
+func checkLimitsNotRaised(ctx *sql.Context, n sql.Node) error {
+	set, ok := n.(*plan.Set)
+	if !ok {
+		return nil
+	}
+
+	limits := sessionLimits(ctx.Session)
+	byVar := limits.byVar()
+	for _, v := range set.Variables {
+		name := strings.ToLower(strings.TrimLeft(v.Name, "@"))
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "session."), "global.")
+		dest, ok := byVar[name]
+		if !ok || *dest == 0 {
+			continue
+		}
+
+		// SET to DEFAULT leaves the limits unchanged, as they have no default in the session config
+		if _, ok := v.Value.(*expression.DefaultColumn); ok {
+			continue
+		}
+
+		val, err := v.Value.Eval(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		limit, err := sql.Int64.Convert(val)
+		if err != nil || limit.(int64) < 0 {
+			return fmt.Errorf("invalid value '%v' for %s, expected a non-negative integer", val, name)
+		}
+
+		if limit := limit.(int64); limit == 0 || limit > *dest {
+			return fmt.Errorf(ErrQueryLimitRaisedFmt, name, limit, *dest)
+		}
+	}
+
+	return nil
+}
+
+// applyQueryGuards is an analyzer rule that enforces the query limits of the session, and rejects SET statements that
+// would raise the limits the session was created with. Joins whose estimated size is
+// over the limit are rejected before they are run, and the root of the query and its tables are wrapped in nodes that
+// abort the query once too many rows have been returned or read, or once it has run for too long. Only queries that
+// read are limited in the rows they return and the time they run for, as aborting a write part way would leave some
+// of its rows written.
+//
+// Subqueries are analyzed on their own before the query containing them, so their tables are already guarded, and
+// the guards given to their roots are removed here: only the rows returned by the outermost query count towards its
+// limit, and it's the outermost query that is timed.
+func applyQueryGuards(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	if err := checkLimitsNotRaised(ctx, n); err != nil {
+		return nil, err
+	}
+
+	limits, err := queryLimitsFromSession(ctx.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	if limits == (QueryLimits{}) {
+		return n, nil
+	}
+
+	if limits.MaxJoinSize > 0 {
+		if err := checkJoinSizes(ctx, n, limits.MaxJoinSize); err != nil {
+			return nil, err
+		}
+	}
+
+	scanned := &scanCounter{limit: limits.MaxScanRows}
+	n, err = plan.TransformUp(n, func(node sql.Node) (sql.Node, error) {
+		switch node := node.(type) {
+		case *plan.SubqueryAlias:
+			if child, ok := withoutRootGuards(node.Child); ok {
+				return plan.NewSubqueryAlias(node.Name(), child), nil
+			}
+			return node, nil
+		case *plan.ResolvedTable:
+			if scanned.limit > 0 {
+				return plan.NewResolvedTable(&scanGuardedTable{Table: node.Table, scanned: scanned}), nil
+			}
+			return node, nil
+		default:
+			return node, nil
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	n, err = plan.TransformExpressionsUp(n, func(e sql.Expression) (sql.Expression, error) {
+		if s, ok := e.(*expression.Subquery); ok {
+			if query, ok := withoutRootGuards(s.Query); ok {
+				return s.WithQuery(query), nil
+			}
+		}
+		return e, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.(type) {
+	case *plan.InsertInto, *plan.Update, *plan.DeleteFrom:
+		return n, nil
+	}
+
+	if limits.MaxRowsReturned > 0 {
+		n = &rowsReturnedGuard{UnaryNode: plan.UnaryNode{Child: n}, limit: limits.MaxRowsReturned}
+	}
+
+	if limits.MaxExecutionTime > 0 {
+		n = &executionTimeGuard{UnaryNode: plan.UnaryNode{Child: n}, limit: limits.MaxExecutionTime}
+	}
+
+	return n, nil
+}
+
+// withoutRootGuards returns the analyzed subquery given with the guards at its root removed, or false if it doesn't
+// have any.
+func withoutRootGuards(n sql.Node) (sql.Node, bool) {
+	if qp, ok := n.(*plan.QueryProcess); ok {
+		if child, ok := withoutRootGuards(qp.Child); ok {
+			return plan.NewQueryProcess(child, qp.Notify), true
+		}
+		return n, false
+	}
+
+	removed := false
+	for {
+		switch guard := n.(type) {
+		case *rowsReturnedGuard:
+			n = guard.Child
+		case *executionTimeGuard:
+			n = guard.Child
+		default:
+			return n, removed
+		}
+		removed = true
+	}
+}
+
+// checkJoinSizes returns an error if any of the joins in the node given would examine more than limit rows, estimated
+// as the product of the row counts of the tables being joined.
+func checkJoinSizes(ctx *sql.Context, n sql.Node, limit int64) error {
+	var err error
+	plan.Inspect(n, func(node sql.Node) bool {
+		if err != nil {
+			return false
+		}
+
+		switch node.(type) {
+		case *plan.CrossJoin, *plan.InnerJoin, *plan.LeftJoin, *plan.RightJoin:
+		default:
+			return true
+		}
+
+		estimate := int64(1)
+		plan.Inspect(node, func(joined sql.Node) bool {
+			if err != nil {
+				return false
+			}
+
+			rt, ok := joined.(*plan.ResolvedTable)
+			if !ok {
+				return true
+			}
+
+			var count int64
+			count, err = estimatedRowCount(ctx, rt.Table)
+			if err != nil {
+				return false
+			}
+
+			if count > 0 && estimate > math.MaxInt64/count {
+				estimate = math.MaxInt64
+			} else {
+				estimate *= count
+			}
+			return false
+		})
+
+		if err == nil && estimate > limit {
+			err = fmt.Errorf(ErrMaxJoinSizeFmt, estimate, limit)
+		}
+
+		// Joins nested in this one have been counted as part of it
+		return false
+	})
+
+	return err
+}
+
+// estimatedRowCount returns the number of rows that reading the table given is expected to produce. Tables that
+// don't expose their size count as a single row.
+func estimatedRowCount(ctx *sql.Context, table sql.Table) (int64, error) {
+	switch t := table.(type) {
+	case *DoltTable:
+		rowData, err := t.table.GetRowData(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return int64(rowData.Len()), nil
+	case *IndexedDoltTable:
+		return int64(len(t.indexLookup.keys)), nil
+	case sql.TableWrapper:
+		return estimatedRowCount(ctx, t.Underlying())
+	default:
+		return 1, nil
+	}
+}
+
+// rowsReturnedGuard is the root of a query run with a limit on the number of rows returned.
+type rowsReturnedGuard struct {
+	plan.UnaryNode
+	limit int64
+}
+
+var _ sql.Node = (*rowsReturnedGuard)(nil)
+
+// RowIter implements sql.Node
+func (g *rowsReturnedGuard) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	iter, err := g.Child.RowIter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rowsReturnedIter{iter: iter, limit: g.limit}, nil
+}
+
+// WithChildren implements sql.Node
+func (g *rowsReturnedGuard) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(g, len(children), 1)
+	}
+	return &rowsReturnedGuard{UnaryNode: plan.UnaryNode{Child: children[0]}, limit: g.limit}, nil
+}
+
+// String implements fmt.Stringer
+func (g *rowsReturnedGuard) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("RowsReturnedGuard(%d)", g.limit)
+	_ = pr.WriteChildren(g.Child.String())
+	return pr.String()
+}
+
+type rowsReturnedIter struct {
+	iter     sql.RowIter
+	limit    int64
+	returned int64
+}
+
+// Next implements sql.RowIter
+func (itr *rowsReturnedIter) Next() (sql.Row, error) {
+	r, err := itr.iter.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	itr.returned++
+	if itr.returned > itr.limit {
+		return nil, fmt.Errorf(ErrMaxRowsReturnedFmt, itr.limit)
+	}
+
+	return r, nil
+}
+
+// Close implements sql.RowIter
+func (itr *rowsReturnedIter) Close() error {
+	return itr.iter.Close()
+}
+
+// executionTimeGuard is the root of a query run with a limit on the time it runs for. The query is run with a context
+// that is canceled once the limit is reached, which stops the scans of its tables and anything else waiting on it.
+type executionTimeGuard struct {
+	plan.UnaryNode
+	limit int64 // In milliseconds
+}
+
+var _ sql.Node = (*executionTimeGuard)(nil)
+
+// RowIter implements sql.Node
+func (g *executionTimeGuard) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx.Context, time.Duration(g.limit)*time.Millisecond)
+	iter, err := g.Child.RowIter(ctx.WithContext(timeoutCtx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &executionTimeIter{iter: iter, ctx: timeoutCtx, cancel: cancel, limit: g.limit}, nil
+}
+
+// WithChildren implements sql.Node
+func (g *executionTimeGuard) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(g, len(children), 1)
+	}
+	return &executionTimeGuard{UnaryNode: plan.UnaryNode{Child: children[0]}, limit: g.limit}, nil
+}
+
+// String implements fmt.Stringer
+func (g *executionTimeGuard) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("ExecutionTimeGuard(%d ms)", g.limit)
+	_ = pr.WriteChildren(g.Child.String())
+	return pr.String()
+}
+
+type executionTimeIter struct {
+	iter   sql.RowIter
+	ctx    context.Context
+	cancel context.CancelFunc
+	limit  int64
+}
+
+// Next implements sql.RowIter
+func (itr *executionTimeIter) Next() (sql.Row, error) {
+	r, err := itr.iter.Next()
+
+	// The error the query was stopped with, if any, is that of whatever noticed the context was canceled
+	if itr.ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf(ErrMaxExecutionTimeFmt, itr.limit)
+	}
+
+	return r, err
+}
+
+// Close implements sql.RowIter
+func (itr *executionTimeIter) Close() error {
+	itr.cancel()
+	return itr.iter.Close()
+}
+
+// scanCounter counts the rows read from all the tables of a query.
+type scanCounter struct {
+	limit   int64
+	scanned int64
+}
+
+func (c *scanCounter) add() error {
+	if atomic.AddInt64(&c.scanned, 1) > c.limit {
+		return fmt.Errorf(ErrMaxScanRowsFmt, c.limit)
+	}
+	return nil
+}
+
+// scanGuardedTable wraps a table read by a query with a limit on the number of rows scanned. The rows read from it are
+// counted towards the limit, including those read again when the table is scanned more than once, such as for the
+// inner table of a join.
+type scanGuardedTable struct {
+	sql.Table
+	scanned *scanCounter
+}
+
+var _ sql.TableWrapper = (*scanGuardedTable)(nil)
+
+// Underlying implements sql.TableWrapper
+func (t *scanGuardedTable) Underlying() sql.Table {
+	return t.Table
+}
+
+// PartitionRows implements sql.Table
+func (t *scanGuardedTable) PartitionRows(ctx *sql.Context, p sql.Partition) (sql.RowIter, error) {
+	iter, err := t.Table.PartitionRows(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scanGuardedIter{iter: iter, scanned: t.scanned}, nil
+}
+
+type scanGuardedIter struct {
+	iter    sql.RowIter
+	scanned *scanCounter
+}
+
+// Next implements sql.RowIter
+func (itr *scanGuardedIter) Next() (sql.Row, error) {
+	r, err := itr.iter.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := itr.scanned.add(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Close implements sql.RowIter
+func (itr *scanGuardedIter) Close() error {
+	return itr.iter.Close()
+}
@@ -0,0 +1,246 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestQueryGuards(t *testing.T) {
+	tests := []struct {
+		name        string
+		limits      QueryLimits
+		query       string
+		expectedErr string
+		rows        int
+	}{
+		{
+			name:  "no limits",
+			query: "select * from people, episodes, appearances",
+			rows:  240,
+		},
+		{
+			name:   "rows returned under limit",
+			limits: QueryLimits{MaxRowsReturned: 6},
+			query:  "select * from people",
+			rows:   6,
+		},
+		{
+			name:        "rows returned over limit",
+			limits:      QueryLimits{MaxRowsReturned: 5},
+			query:       "select * from people",
+			expectedErr: fmt.Sprintf(ErrMaxRowsReturnedFmt, 5),
+		},
+		{
+			name:   "rows returned by subquery not limited",
+			limits: QueryLimits{MaxRowsReturned: 1},
+			query:  "select count(*) from (select * from people) p",
+			rows:   1,
+		},
+		{
+			name:   "rows returned by expression subquery not limited",
+			limits: QueryLimits{MaxRowsReturned: 6},
+			query:  "select first from people where id in (select character_id from appearances)",
+			rows:   6,
+		},
+		{
+			name:   "scan rows under limit",
+			limits: QueryLimits{MaxScanRows: 6},
+			query:  "select * from people where age > 30",
+			rows:   4,
+		},
+		{
+			name:        "scan rows over limit",
+			limits:      QueryLimits{MaxScanRows: 5},
+			query:       "select first from people where age > 100",
+			expectedErr: fmt.Sprintf(ErrMaxScanRowsFmt, 5),
+		},
+		{
+			name:        "scan rows counts rescans of joined tables",
+			limits:      QueryLimits{MaxScanRows: 20},
+			query:       "select * from people, episodes",
+			expectedErr: fmt.Sprintf(ErrMaxScanRowsFmt, 20),
+		},
+		{
+			name:   "join size under limit",
+			limits: QueryLimits{MaxJoinSize: 24},
+			query:  "select * from people, episodes",
+			rows:   24,
+		},
+		{
+			name:        "join size over limit",
+			limits:      QueryLimits{MaxJoinSize: 100},
+			query:       "select * from people, episodes, appearances",
+			expectedErr: fmt.Sprintf(ErrMaxJoinSizeFmt, 240, 100),
+		},
+		{
+			name:        "join size over limit inner join",
+			limits:      QueryLimits{MaxJoinSize: 50},
+			query:       "select * from people p join appearances a on p.id = a.character_id",
+			expectedErr: fmt.Sprintf(ErrMaxJoinSizeFmt, 60, 50),
+		},
+		{
+			name:   "single table not limited by join size",
+			limits: QueryLimits{MaxJoinSize: 1},
+			query:  "select * from appearances",
+			rows:   10,
+		},
+		{
+			name:   "execution time under limit",
+			limits: QueryLimits{MaxExecutionTime: 60000},
+			query:  "select * from people where id in (select character_id from appearances)",
+			rows:   6,
+		},
+		{
+			name:        "execution time over limit",
+			limits:      QueryLimits{MaxExecutionTime: 50},
+			query:       "select sleep(10) from people",
+			expectedErr: fmt.Sprintf(ErrMaxExecutionTimeFmt, 50),
+		},
+		{
+			name:        "execution time over limit in subquery",
+			limits:      QueryLimits{MaxExecutionTime: 50},
+			query:       "select count(*) from (select sleep(10) from people) p",
+			expectedErr: fmt.Sprintf(ErrMaxExecutionTimeFmt, 50),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			root, err := dEnv.WorkingRoot(context.Background())
+			require.NoError(t, err)
+
+			engine := NewEngine(CaseSensitive)
+			engine.AddDatabase(NewDatabase("dolt", root, nil, nil))
+			sqlCtx := sql.NewContext(context.Background())
+			test.limits.SetSessionDefaults(sqlCtx.Session)
+
+			rows, err := queryRowCount(sqlCtx, engine.Query, test.query)
+			if len(test.expectedErr) > 0 {
+				require.Error(t, err)
+				assert.Equal(t, test.expectedErr, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.rows, rows)
+		})
+	}
+}
+
+func TestQueryGuardsSetInSession(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, nil, nil))
+	sqlCtx := sql.NewContext(context.Background())
+	QueryLimits{MaxRowsReturned: 2}.SetSessionDefaults(sqlCtx.Session)
+
+	_, err = queryRowCount(sqlCtx, engine.Query, "select * from people")
+	assert.Error(t, err)
+
+	_, err = queryRowCount(sqlCtx, engine.Query, "set max_rows_returned = 0")
+	require.NoError(t, err)
+
+	rows, err := queryRowCount(sqlCtx, engine.Query, "select * from people")
+	require.NoError(t, err)
+	assert.Equal(t, 6, rows)
+
+	_, err = queryRowCount(sqlCtx, engine.Query, "set max_rows_returned = -1")
+	require.NoError(t, err)
+
+	_, err = queryRowCount(sqlCtx, engine.Query, "select * from people")
+	assert.Error(t, err)
+}
+
+func TestQueryLimitSession(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, nil, nil))
+	sess := NewQueryLimitSession(sql.NewBaseSession(), QueryLimits{MaxRowsReturned: 5, MaxScanRows: 100})
+	sqlCtx := sql.NewContext(context.Background(), sql.WithSession(sess))
+
+	_, err = queryRowCount(sqlCtx, engine.Query, "select * from people")
+	assert.Error(t, err)
+
+	// the session's limits can't be raised or removed
+	for _, query := range []string{"set max_rows_returned = 0", "set max_rows_returned = 6", "set @@session.max_scan_rows = 1000"} {
+		_, err = queryRowCount(sqlCtx, engine.Query, query)
+		require.Error(t, err, query)
+		assert.Contains(t, err.Error(), "can't be set to")
+	}
+
+	_, err = queryRowCount(sqlCtx, engine.Query, "select * from people")
+	assert.Error(t, err)
+
+	// but they can be lowered, and raised again up to the session's limit
+	_, err = queryRowCount(sqlCtx, engine.Query, "set max_rows_returned = 1")
+	require.NoError(t, err)
+	_, err = queryRowCount(sqlCtx, engine.Query, "select * from people where last = 'Simpson'")
+	assert.Error(t, err)
+
+	_, err = queryRowCount(sqlCtx, engine.Query, "set max_rows_returned = 5")
+	require.NoError(t, err)
+	rows, err := queryRowCount(sqlCtx, engine.Query, "select * from people where last = 'Simpson'")
+	require.NoError(t, err)
+	assert.Equal(t, 4, rows)
+
+	// a limit the session wasn't given can be set and removed
+	_, err = queryRowCount(sqlCtx, engine.Query, "set max_join_size = 1")
+	require.NoError(t, err)
+	_, err = queryRowCount(sqlCtx, engine.Query, "select * from people a, appearances b")
+	assert.Error(t, err)
+	_, err = queryRowCount(sqlCtx, engine.Query, "set max_join_size = 0")
+	require.NoError(t, err)
+}
+
+func queryRowCount(ctx *sql.Context, query func(*sql.Context, string) (sql.Schema, sql.RowIter, error), q string) (int, error) {
+	_, iter, err := query(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	defer iter.Close()
+
+	count := 0
+	for {
+		_, err := iter.Next()
+		if err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return 0, err
+		}
+		count++
+	}
+}
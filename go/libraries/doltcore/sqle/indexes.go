@@ -19,14 +19,53 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/src-d/go-mysql-server/sql/plan"
 
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
+const comparisonsRuleName = "dolt_normalize_comparisons"
+
+// normalizeComparisons is an analyzer rule that rewrites comparisons with a literal on the left, like 5 < a, to put the
+// literal on the right: a > 5. The engine assumes that form when it uses an index for a range comparison, and would
+// otherwise look up the opposite range.
+func normalizeComparisons(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	return plan.TransformExpressionsUp(n, func(e sql.Expression) (sql.Expression, error) {
+		c, ok := e.(expression.Comparer)
+		if !ok {
+			return e, nil
+		}
+
+		_, leftLiteral := c.Left().(*expression.Literal)
+		_, rightLiteral := c.Right().(*expression.Literal)
+		if !leftLiteral || rightLiteral {
+			return e, nil
+		}
+
+		switch e.(type) {
+		case *expression.GreaterThan:
+			return expression.NewLessThan(c.Right(), c.Left()), nil
+		case *expression.GreaterThanOrEqual:
+			return expression.NewLessThanOrEqual(c.Right(), c.Left()), nil
+		case *expression.LessThan:
+			return expression.NewGreaterThan(c.Right(), c.Left()), nil
+		case *expression.LessThanOrEqual:
+			return expression.NewGreaterThanOrEqual(c.Right(), c.Left()), nil
+		default:
+			return e, nil
+		}
+	})
+}
+
 // IndexDriver implementation. Not ready for prime time.
 
 type DoltIndexDriver struct {
@@ -58,7 +97,7 @@ func (i *DoltIndexDriver) LoadAll(db, table string) ([]sql.Index, error) {
 		panic("Unexpected db: " + db)
 	}
 
-	tbl, ok, err := i.db.root.GetTable(context.TODO(), table)
+	tbl, ok, err := i.db.Root().GetTable(context.TODO(), table)
 
 	if err != nil {
 		return nil, err
@@ -74,99 +113,312 @@ func (i *DoltIndexDriver) LoadAll(db, table string) ([]sql.Index, error) {
 		return nil, err
 	}
 
-	return []sql.Index{&doltIndex{sch, table, i.db, i}}, nil
+	indexes := []sql.Index{&doltIndex{sch: sch, tableName: table, db: i.db, driver: i}}
+
+	secondaryIndexes, err := tbl.GetIndexes(context.TODO())
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, idx := range secondaryIndexes {
+		cols := make([]schema.Column, len(idx.Tags))
+		for j, tag := range idx.Tags {
+			col, ok := sch.GetAllCols().GetByTag(tag)
+
+			if !ok {
+				return nil, fmt.Errorf("index %s on table %s refers to column with tag %d, which is not in the table's schema", idx.Name, table, tag)
+			}
+
+			cols[j] = col
+		}
+
+		indexes = append(indexes, &doltIndex{sch: sch, tableName: table, db: i.db, driver: i, name: idx.Name, cols: cols})
+	}
+
+	return indexes, nil
 }
 
+// doltIndex is either the primary key index of a table, or one of its secondary indexes. Secondary indexes can be used
+// to look up rows by a range of values as well as by value.
 type doltIndex struct {
 	sch       schema.Schema
 	tableName string
 	db        *Database
 	driver    *DoltIndexDriver
+
+	// The name of the secondary index and the columns it indexes, which are empty for the primary key index
+	name string
+	cols []schema.Column
 }
 
+var _ sql.AscendIndex = (*doltIndex)(nil)
+var _ sql.DescendIndex = (*doltIndex)(nil)
+
+// Get returns a lookup of the rows with the key given: the primary key for the primary key index, or the values of the
+// indexed columns for a secondary index. If the key values can't be converted to the types of the columns, no lookup is
+// returned and the engine falls back to scanning the table.
 func (di *doltIndex) Get(key ...interface{}) (sql.IndexLookup, error) {
-	taggedVals, err := keyColsToTuple(di.sch, key)
+	if di.name != "" {
+		return di.rangeLookup(key, true, key, true)
+	}
+
+	taggedVals, ok, err := keyColsToTuple(di.sch, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	ctx := context.TODO()
+	keyTpl, err := taggedVals.NomsTupleForTags(di.db.Root().VRW().Format(), di.sch.GetPKCols().Tags, true).Value(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return &doltIndexLookup{di, taggedVals}, nil
+	return &doltIndexLookup{idx: di, keys: []types.Tuple{keyTpl.(types.Tuple)}}, nil
 }
 
-func keyColsToTuple(sch schema.Schema, key []interface{}) (row.TaggedValues, error) {
+// Returns the tagged values for the primary key given, and whether all of its values could be converted to the types
+// of the primary key columns.
+func keyColsToTuple(sch schema.Schema, key []interface{}) (row.TaggedValues, bool, error) {
 	if sch.GetPKCols().Size() != len(key) {
-		return nil, errors.New("key must specify all columns")
+		return nil, false, errors.New("key must specify all columns")
 	}
 
 	var i int
+	ok := true
 	taggedVals := make(row.TaggedValues)
 	err := sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-		taggedVals[tag] = keyColToValue(key[i], col)
+		var val types.Value
+		val, ok = keyColToValue(key[i], col)
+		if !ok {
+			return true, nil
+		}
+
+		taggedVals[tag] = val
 		i++
 		return false, nil
 	})
 
 	if err != nil {
+		return nil, false, err
+	}
+
+	return taggedVals, ok, nil
+}
+
+// Converts the value given to the type of the column given, returning false if it cannot be converted.
+func keyColToValue(v interface{}, column schema.Column) (types.Value, bool) {
+	val, err := sqlValToColVal(column, v)
+	if err != nil || val == nil {
+		return nil, false
+	}
+
+	return val, true
+}
+
+// AscendGreaterOrEqual implements sql.AscendIndex. Only secondary indexes support range lookups.
+func (di *doltIndex) AscendGreaterOrEqual(keys ...interface{}) (sql.IndexLookup, error) {
+	return di.rangeLookup(keys, true, nil, false)
+}
+
+// AscendLessThan implements sql.AscendIndex. Only secondary indexes support range lookups.
+func (di *doltIndex) AscendLessThan(keys ...interface{}) (sql.IndexLookup, error) {
+	return di.rangeLookup(nil, false, keys, false)
+}
+
+// AscendRange implements sql.AscendIndex. Only secondary indexes support range lookups.
+func (di *doltIndex) AscendRange(greaterOrEqual, lessThan []interface{}) (sql.IndexLookup, error) {
+	return di.rangeLookup(greaterOrEqual, true, lessThan, false)
+}
+
+// DescendGreater implements sql.DescendIndex. Only secondary indexes support range lookups.
+func (di *doltIndex) DescendGreater(keys ...interface{}) (sql.IndexLookup, error) {
+	return di.rangeLookup(keys, false, nil, false)
+}
+
+// DescendLessOrEqual implements sql.DescendIndex. Only secondary indexes support range lookups.
+func (di *doltIndex) DescendLessOrEqual(keys ...interface{}) (sql.IndexLookup, error) {
+	return di.rangeLookup(nil, false, keys, true)
+}
+
+// DescendRange implements sql.DescendIndex. Only secondary indexes support range lookups.
+func (di *doltIndex) DescendRange(lessOrEqual, greaterThan []interface{}) (sql.IndexLookup, error) {
+	return di.rangeLookup(greaterThan, false, lessOrEqual, true)
+}
+
+// rangeLookup returns a lookup of the rows whose values of the indexed columns are between the bounds given, which are
+// inclusive or exclusive as specified. A nil bound leaves the range open on that side. Rows with null values in any of
+// the indexed columns are never matched. No lookup is returned, so that the engine falls back to scanning the table,
+// for the primary key index, if the bounds can't be converted to the types of the indexed columns, or if the index has
+// changed since it was loaded.
+func (di *doltIndex) rangeLookup(lower []interface{}, lowerInclusive bool, upper []interface{}, upperInclusive bool) (sql.IndexLookup, error) {
+	if di.name == "" {
+		return nil, nil
+	}
+
+	ctx := context.TODO()
+	tbl, ok, err := di.db.Root().GetTable(ctx, di.tableName)
+
+	if err != nil || !ok {
 		return nil, err
 	}
 
-	return taggedVals, nil
-}
-
-func keyColToValue(v interface{}, column schema.Column) types.Value {
-	// TODO: type conversion
-	switch column.Kind {
-	case types.BoolKind:
-		return types.Bool(v.(bool))
-	case types.IntKind:
-		switch i := v.(type) {
-		case int:
-			return types.Int(i)
-		case int8:
-			return types.Int(i)
-		case int16:
-			return types.Int(i)
-		case int32:
-			return types.Int(i)
-		case int64:
-			return types.Int(i)
-		default:
-			panic(fmt.Sprintf("unhandled type %T", i))
-		}
-	case types.FloatKind:
-		return types.Float(v.(float64))
-	case types.UintKind:
-		switch i := v.(type) {
-		case int:
-			return types.Uint(i)
-		case int8:
-			return types.Uint(i)
-		case int16:
-			return types.Uint(i)
-		case int32:
-			return types.Uint(i)
-		case int64:
-			return types.Uint(i)
-		case uint:
-			return types.Uint(i)
-		case uint8:
-			return types.Uint(i)
-		case uint16:
-			return types.Uint(i)
-		case uint32:
-			return types.Uint(i)
-		case uint64:
-			return types.Uint(i)
-		default:
-			panic(fmt.Sprintf("unhandled type %T", i))
+	if current, err := di.isCurrent(ctx, tbl); err != nil || !current {
+		return nil, err
+	}
+
+	data, ok, err := tbl.GetIndexData(ctx, di.name)
+
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	nbf := data.Format()
+	lowerTpl, ok, err := di.boundTuple(nbf, lower)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	upperTpl, ok, err := di.boundTuple(nbf, upper)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var itr types.MapIterator
+	if lower != nil {
+		itr, err = data.IteratorFrom(ctx, lowerTpl)
+	} else {
+		itr, err = data.Iterator(ctx)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	numFields := uint64(2 * len(di.cols))
+	var keys []types.Tuple
+	entries := make(map[hash.Hash]types.Tuple)
+	for {
+		k, v, err := itr.Next(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if k == nil {
+			break
+		}
+
+		fields := make([]types.Value, numFields)
+		hasNull := false
+		for i := uint64(0); i < numFields; i++ {
+			if fields[i], err = k.(types.Tuple).Get(i); err != nil {
+				return nil, err
+			}
+			hasNull = hasNull || types.IsNull(fields[i])
+		}
+
+		if hasNull {
+			continue
+		}
+
+		vals, err := types.NewTuple(nbf, fields...)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if lower != nil && !lowerInclusive && vals.Equals(lowerTpl) {
+			continue
+		}
+
+		if upper != nil {
+			if vals.Equals(upperTpl) {
+				if !upperInclusive {
+					break
+				}
+			} else if past, err := upperTpl.Less(nbf, vals); err != nil {
+				return nil, err
+			} else if past {
+				break
+			}
+		}
+
+		h, err := v.Hash(nbf)
+
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, v.(types.Tuple))
+		entries[h] = k.(types.Tuple)
+	}
+
+	return &doltIndexLookup{idx: di, keys: keys, entries: entries}, nil
+}
+
+// hasCol returns whether the column with the tag given is one of the indexed columns.
+func (di *doltIndex) hasCol(tag uint64) bool {
+	for _, col := range di.cols {
+		if col.Tag == tag {
+			return true
 		}
-	case types.UUIDKind:
-		panic("Implement me")
-	case types.StringKind:
-		return types.String(v.(string))
-	default:
-		panic(fmt.Sprintf("unhandled type %T", v))
 	}
+
+	return false
+}
+
+// isCurrent returns whether the table given still has the index, with the same columns of the same types, that this
+// index was loaded from.
+func (di *doltIndex) isCurrent(ctx context.Context, tbl *doltdb.Table) (bool, error) {
+	sch, err := tbl.GetSchema(ctx)
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, col := range di.cols {
+		current, ok := sch.GetAllCols().GetByTag(col.Tag)
+		if !ok || current.Kind != col.Kind {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// boundTuple returns the tuple of the tags of the indexed columns and the values given, converted to the types of the
+// columns, which is compared to the keys of the index's data. Returns false if the values can't be converted.
+func (di *doltIndex) boundTuple(nbf *types.NomsBinFormat, vals []interface{}) (types.Tuple, bool, error) {
+	if vals == nil {
+		return types.EmptyTuple(nbf), true, nil
+	}
+
+	if len(vals) != len(di.cols) {
+		return types.Tuple{}, false, nil
+	}
+
+	fields := make([]types.Value, 0, 2*len(vals))
+	for i, col := range di.cols {
+		// the index is ordered as noms orders values, which custom types may compare differently
+		if ct, ok := col.CustomType(); ok && ct.Compare != nil {
+			return types.Tuple{}, false, nil
+		}
+
+		val, ok := keyColToValue(vals[i], col)
+		if !ok {
+			return types.Tuple{}, false, nil
+		}
+
+		fields = append(fields, types.Uint(col.Tag), val)
+	}
+
+	tpl, err := types.NewTuple(nbf, fields...)
+	return tpl, true, err
 }
 
 func (*doltIndex) Has(partition sql.Partition, key ...interface{}) (bool, error) {
@@ -175,6 +427,9 @@ func (*doltIndex) Has(partition sql.Partition, key ...interface{}) (bool, error)
 }
 
 func (di *doltIndex) ID() string {
+	if di.name != "" {
+		return fmt.Sprintf("%s:%s", di.tableName, di.name)
+	}
 	return fmt.Sprintf("%s:primaryKey", di.tableName)
 }
 
@@ -187,6 +442,14 @@ func (di *doltIndex) Table() string {
 }
 
 func (di *doltIndex) Expressions() []string {
+	if di.name != "" {
+		strs := make([]string, len(di.cols))
+		for i, col := range di.cols {
+			strs[i] = di.tableName + "." + col.Name
+		}
+		return strs
+	}
+
 	strs, err := primaryKeytoIndexStrings(di.tableName, di.sch)
 
 	// TODO: fix panics
@@ -205,7 +468,7 @@ func primaryKeytoIndexStrings(tableName string, sch schema.Schema) ([]string, er
 	err := sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		colNames[i] = tableName + "." + col.Name
 		i++
-		return true, nil
+		return false, nil
 	})
 
 	if err != nil {
@@ -239,8 +502,14 @@ func (idt *IndexedDoltTable) Name() string {
 	return idt.table.Name()
 }
 
+// String returns the name of the table and the lookup used to read it, which is shown in query plans.
 func (idt *IndexedDoltTable) String() string {
-	return idt.table.String()
+	if idt.isIndexOnly() {
+		return fmt.Sprintf("%s (index-only scan of %s, %d keys)", idt.table.String(), idt.indexLookup.idx.name, len(idt.indexLookup.keys))
+	} else if name := idt.indexLookup.idx.name; name != "" {
+		return fmt.Sprintf("%s (index %s lookup, %d keys)", idt.table.String(), name, len(idt.indexLookup.keys))
+	}
+	return fmt.Sprintf("%s (primary key lookup, %d keys)", idt.table.String(), len(idt.indexLookup.keys))
 }
 
 func (idt *IndexedDoltTable) Schema() sql.Schema {
@@ -251,17 +520,54 @@ func (idt *IndexedDoltTable) IndexLookup() sql.IndexLookup {
 	return idt.indexLookup
 }
 
+// Partitions implements sql.Table. The rows of an index lookup are returned as a single partition.
 func (idt *IndexedDoltTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
-	return idt.table.Partitions(ctx)
+	return &doltTablePartitionIter{}, nil
 }
 
 func (idt *IndexedDoltTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	if idt.isIndexOnly() {
+		return idt.indexLookup.indexOnlyRowIter(ctx)
+	}
 	return idt.indexLookup.RowIter(ctx)
 }
 
+// isIndexOnly returns whether the query reading the table only needs columns that are in the secondary index being
+// looked up, either as indexed columns or as primary key columns. Such queries are answered from the index's data alone,
+// without reading the rows.
+func (idt *IndexedDoltTable) isIndexOnly() bool {
+	idx := idt.indexLookup.idx
+	projection := idt.table.projection
+	if idx.name == "" || len(projection) == 0 {
+		return false
+	}
+
+	for _, colName := range projection {
+		col, ok := idx.sch.GetAllCols().GetByNameCaseInsensitive(colName)
+		if !ok {
+			return false
+		}
+
+		if !col.IsPartOfPK && !idx.hasCol(col.Tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// doltIndexLookup is a lookup of the rows with any of a set of primary keys. Lookups of the same index can be combined
+// with set operations, which lets the engine turn expressions like `pk IN (1, 2, 3)` into point lookups.
 type doltIndexLookup struct {
-	idx *doltIndex
-	key row.TaggedValues
+	idx  *doltIndex
+	keys []types.Tuple
+
+	// The entries of a secondary index for the keys, by the hash of the key, which are used for index-only scans
+	entries map[hash.Hash]types.Tuple
+
+	// The error hit while combining lookups, which the set operations have no way to return. It is returned when rows
+	// are read from the lookup instead.
+	err error
 }
 
 func (il *doltIndexLookup) Indexes() []string {
@@ -275,40 +581,346 @@ func (il *doltIndexLookup) Values(p sql.Partition) (sql.IndexValueIter, error) {
 	panic("implement me")
 }
 
-// RowIter returns a row iterator for this index lookup. The iterator will return the single matching row for the index.
+// IsMergeable implements sql.Mergeable. Only lookups of the same index can be merged.
+func (il *doltIndexLookup) IsMergeable(lookup sql.IndexLookup) bool {
+	other, ok := lookup.(*doltIndexLookup)
+	return ok && other.idx.ID() == il.idx.ID()
+}
+
+// Intersection implements sql.SetOperations, returning a lookup of the keys present in this lookup and all the given
+// ones.
+func (il *doltIndexLookup) Intersection(lookups ...sql.IndexLookup) sql.IndexLookup {
+	if err := il.firstErr(lookups); err != nil {
+		return &doltIndexLookup{idx: il.idx, err: err}
+	}
+
+	keys := il.keys
+	for _, lookup := range lookups {
+		other, err := il.keySet(lookup.(*doltIndexLookup).keys)
+
+		if err != nil {
+			return &doltIndexLookup{idx: il.idx, err: err}
+		}
+
+		var intersection []types.Tuple
+		for _, k := range keys {
+			h, err := il.keyHash(k)
+
+			if err != nil {
+				return &doltIndexLookup{idx: il.idx, err: err}
+			}
+
+			if _, ok := other[h]; ok {
+				intersection = append(intersection, k)
+			}
+		}
+		keys = intersection
+	}
+
+	return &doltIndexLookup{idx: il.idx, keys: keys, entries: il.entries}
+}
+
+// Union implements sql.SetOperations, returning a lookup of the keys present in this lookup or any of the given ones.
+func (il *doltIndexLookup) Union(lookups ...sql.IndexLookup) sql.IndexLookup {
+	if err := il.firstErr(lookups); err != nil {
+		return &doltIndexLookup{idx: il.idx, err: err}
+	}
+
+	keys := append([]types.Tuple(nil), il.keys...)
+	seen, err := il.keySet(keys)
+
+	if err != nil {
+		return &doltIndexLookup{idx: il.idx, err: err}
+	}
+
+	for _, lookup := range lookups {
+		for _, k := range lookup.(*doltIndexLookup).keys {
+			h, err := il.keyHash(k)
+
+			if err != nil {
+				return &doltIndexLookup{idx: il.idx, err: err}
+			}
+
+			if _, ok := seen[h]; !ok {
+				seen[h] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	return &doltIndexLookup{idx: il.idx, keys: keys, entries: il.mergedEntries(lookups)}
+}
+
+// Difference implements sql.SetOperations, returning a lookup of the keys present in this lookup but none of the given
+// ones.
+func (il *doltIndexLookup) Difference(lookups ...sql.IndexLookup) sql.IndexLookup {
+	if err := il.firstErr(lookups); err != nil {
+		return &doltIndexLookup{idx: il.idx, err: err}
+	}
+
+	excluded := make(map[hash.Hash]struct{})
+	for _, lookup := range lookups {
+		set, err := il.keySet(lookup.(*doltIndexLookup).keys)
+
+		if err != nil {
+			return &doltIndexLookup{idx: il.idx, err: err}
+		}
+
+		for h := range set {
+			excluded[h] = struct{}{}
+		}
+	}
+
+	var keys []types.Tuple
+	for _, k := range il.keys {
+		h, err := il.keyHash(k)
+
+		if err != nil {
+			return &doltIndexLookup{idx: il.idx, err: err}
+		}
+
+		if _, ok := excluded[h]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	return &doltIndexLookup{idx: il.idx, keys: keys, entries: il.entries}
+}
+
+// firstErr returns the error of this lookup or of the first of the given ones that failed, if any did.
+func (il *doltIndexLookup) firstErr(lookups []sql.IndexLookup) error {
+	if il.err != nil {
+		return il.err
+	}
+
+	for _, lookup := range lookups {
+		if err := lookup.(*doltIndexLookup).err; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergedEntries returns the index entries of this lookup and the given ones.
+func (il *doltIndexLookup) mergedEntries(lookups []sql.IndexLookup) map[hash.Hash]types.Tuple {
+	if il.entries == nil {
+		return nil
+	}
+
+	entries := make(map[hash.Hash]types.Tuple, len(il.entries))
+	for h, entry := range il.entries {
+		entries[h] = entry
+	}
+
+	for _, lookup := range lookups {
+		for h, entry := range lookup.(*doltIndexLookup).entries {
+			entries[h] = entry
+		}
+	}
+
+	return entries
+}
+
+func (il *doltIndexLookup) keySet(keys []types.Tuple) (map[hash.Hash]struct{}, error) {
+	set := make(map[hash.Hash]struct{}, len(keys))
+	for _, k := range keys {
+		h, err := il.keyHash(k)
+
+		if err != nil {
+			return nil, err
+		}
+
+		set[h] = struct{}{}
+	}
+
+	return set, nil
+}
+
+func (il *doltIndexLookup) keyHash(key types.Tuple) (hash.Hash, error) {
+	return key.Hash(il.idx.db.Root().VRW().Format())
+}
+
+// RowIter returns a row iterator for this index lookup. The iterator returns the rows matching the keys of the lookup
+// in primary key order.
 func (il *doltIndexLookup) RowIter(ctx *sql.Context) (sql.RowIter, error) {
-	return &indexLookupRowIterAdapter{indexLookup: il, ctx: ctx}, nil
+	if il.err != nil {
+		return nil, il.err
+	}
+
+	keys, err := il.sortedKeys()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &indexLookupRowIterAdapter{indexLookup: il, ctx: ctx, keys: keys}, nil
+}
+
+// indexOnlyRowIter returns a row iterator for this lookup of a secondary index that builds rows from the index's
+// entries, without reading the table's rows. Only the indexed and primary key columns of the rows are set. Rows are
+// returned in primary key order.
+func (il *doltIndexLookup) indexOnlyRowIter(ctx *sql.Context) (sql.RowIter, error) {
+	if il.err != nil {
+		return nil, il.err
+	}
+
+	keys, err := il.sortedKeys()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &indexLookupRowIterAdapter{indexLookup: il, ctx: ctx, keys: keys, indexOnly: true}, nil
+}
+
+// sortedKeys returns the keys of this lookup in primary key order.
+func (il *doltIndexLookup) sortedKeys() ([]types.Tuple, error) {
+	nbf := il.idx.db.Root().VRW().Format()
+	keys := append([]types.Tuple(nil), il.keys...)
+
+	var err error
+	sort.Slice(keys, func(i, j int) bool {
+		less, lessErr := keys[i].Less(nbf, keys[j])
+		if lessErr != nil {
+			err = lessErr
+		}
+		return less
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
 }
 
 type indexLookupRowIterAdapter struct {
 	indexLookup *doltIndexLookup
 	ctx         *sql.Context
-	i           int
+	keys        []types.Tuple
+	rowData     *types.Map
+	indexOnly   bool
 }
 
+// Next returns the next row matching the index lookup, or io.EOF if there are no more.
 func (i *indexLookupRowIterAdapter) Next() (sql.Row, error) {
-	if i.i > 0 {
-		return nil, io.EOF
+	for len(i.keys) > 0 {
+		select {
+		case <-i.ctx.Done():
+			return nil, i.ctx.Err()
+		default:
+		}
+
+		if i.indexOnly {
+			key := i.keys[0]
+			i.keys = i.keys[1:]
+			return i.indexEntryToSqlRow(key)
+		}
+
+		if i.rowData == nil {
+			table, ok, err := i.indexLookup.idx.db.rootFor(i.ctx).GetTable(i.ctx, i.indexLookup.idx.tableName)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				return nil, io.EOF
+			}
+
+			rowData, err := table.GetRowData(i.ctx.Context)
+
+			if err != nil {
+				return nil, err
+			}
+
+			i.rowData = &rowData
+		}
+
+		key := i.keys[0]
+		i.keys = i.keys[1:]
+
+		val, ok, err := i.rowData.MaybeGet(i.ctx.Context, key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		r, err := row.FromNoms(i.indexLookup.idx.sch, key, val.(types.Tuple))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return doltRowToSqlRow(r, i.indexLookup.idx.sch)
 	}
 
-	i.i++
-	table, _, err := i.indexLookup.idx.db.root.GetTable(i.ctx.Context, i.indexLookup.idx.tableName)
+	return nil, io.EOF
+}
+
+// indexEntryToSqlRow returns the row with the key given built from its entry in the secondary index being looked up.
+// The entry holds the tags and values of the indexed columns followed by the key.
+func (i *indexLookupRowIterAdapter) indexEntryToSqlRow(key types.Tuple) (sql.Row, error) {
+	idx := i.indexLookup.idx
+	nbf := idx.db.Root().VRW().Format()
+
+	h, err := key.Hash(nbf)
 
 	if err != nil {
 		return nil, err
 	}
 
-	r, ok, err := table.GetRowByPKVals(i.ctx.Context, i.indexLookup.key, i.indexLookup.idx.sch)
+	entry, ok := i.indexLookup.entries[h]
+
+	if !ok {
+		return nil, fmt.Errorf("no entry in index %s for key", idx.name)
+	}
+
+	taggedVals := make(row.TaggedValues)
+	if err := addTaggedVals(taggedVals, entry, uint64(2*len(idx.cols))); err != nil {
+		return nil, err
+	}
+
+	if err := addTaggedVals(taggedVals, key, key.Len()); err != nil {
+		return nil, err
+	}
+
+	r, err := row.New(nbf, idx.sch, taggedVals)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if !ok {
-		return nil, io.EOF
+	return doltRowToSqlRow(r, idx.sch)
+}
+
+// addTaggedVals adds the non-null values of the first n fields of the tuple given, which alternate between tags and
+// values, to taggedVals.
+func addTaggedVals(taggedVals row.TaggedValues, tpl types.Tuple, n uint64) error {
+	for i := uint64(0); i+1 < n; i += 2 {
+		tag, err := tpl.Get(i)
+
+		if err != nil {
+			return err
+		}
+
+		val, err := tpl.Get(i + 1)
+
+		if err != nil {
+			return err
+		}
+
+		if !types.IsNull(val) {
+			taggedVals[uint64(tag.(types.Uint))] = val
+		}
 	}
 
-	return doltRowToSqlRow(r, i.indexLookup.idx.sch)
+	return nil
 }
 
 func (*indexLookupRowIterAdapter) Close() error {
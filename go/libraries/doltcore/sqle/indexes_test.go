@@ -0,0 +1,282 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	sqle "github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/parse"
+	"github.com/src-d/go-mysql-server/sql/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestPrimaryKeyLookups(t *testing.T) {
+	tests := []struct {
+		query   string
+		indexed bool
+	}{
+		{"select * from people where id = 3", true},
+		{"select * from people where id in (1, 3, 5)", true},
+		{"select * from people where id = 1 or id = 2", true},
+		{"select * from people where id in (1, 2) and age > 30", true},
+		{"select * from people where id = 'one'", false},
+		{"select * from people where id > 3", false},
+		{"select * from people where age = 40", false},
+	}
+
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	db := NewDatabase("dolt", root, nil, nil)
+	engine := sqle.NewDefault()
+	engine.AddDatabase(db)
+	engine.Catalog.RegisterIndexDriver(NewDoltIndexDriver(db))
+	require.NoError(t, engine.Init())
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			sqlCtx := sql.NewContext(ctx)
+			parsed, err := parse.Parse(sqlCtx, test.query)
+			require.NoError(t, err)
+
+			analyzed, err := engine.Analyzer.Analyze(sqlCtx, parsed)
+			require.NoError(t, err)
+
+			var indexed bool
+			plan.Inspect(analyzed, func(n sql.Node) bool {
+				if rt, ok := n.(*plan.ResolvedTable); ok {
+					if pit, ok := rt.Table.(*plan.ProcessIndexableTable); ok {
+						_, indexed = pit.IndexableTable.(*IndexedDoltTable)
+					}
+				}
+				return true
+			})
+
+			assert.Equal(t, test.indexed, indexed)
+		})
+	}
+}
+
+func TestSecondaryIndexLookups(t *testing.T) {
+	tests := []struct {
+		query       string
+		indexed     bool
+		expectedIds []int64
+	}{
+		{"select id from people where age = 40 order by id", true, []int64{0, 5}},
+		{"select id from people where age > 38 order by id", true, []int64{0, 4, 5}},
+		{"select id from people where age >= 38 order by id", true, []int64{0, 1, 4, 5}},
+		{"select id from people where age < 38 order by id", true, []int64{2, 3}},
+		{"select id from people where age <= 38 order by id", true, []int64{1, 2, 3}},
+		{"select id from people where 40 < age order by id", true, []int64{4}},
+		{"select id from people where age between 10 and 40 order by id", true, []int64{0, 1, 2, 5}},
+		{"select id from people where age in (8, 48) order by id", true, []int64{3, 4}},
+		{"select id from people where age = 8 or age = 10 order by id", true, []int64{2, 3}},
+		{"select id from people where age > 9 and age < 39 order by id", true, []int64{1, 2}},
+		{"select id from people where age = 40.5 order by id", false, nil},
+		{"select id from people where num_episodes < 300 order by id", true, []int64{1, 2}},
+		{"select id from people where last = 'Simpson' and first = 'Bart' order by id", true, []int64{2}},
+		{"select id from people where first = 'Bart' order by id", false, []int64{2}},
+		{"select id from people where rating > 8 order by id", false, []int64{0, 2, 3}},
+	}
+
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `create index age_idx on people (age);
+create index episodes_idx on people (num_episodes);
+create index name_idx on people (last, first)`)
+	require.NoError(t, err)
+
+	db := NewDatabase("dolt", root, nil, nil)
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(db)
+	engine.Catalog.RegisterIndexDriver(NewDoltIndexDriver(db))
+	require.NoError(t, engine.Init())
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			sqlCtx := sql.NewContext(ctx)
+			parsed, err := parse.Parse(sqlCtx, test.query)
+			require.NoError(t, err)
+
+			analyzed, err := engine.Analyzer.Analyze(sqlCtx, parsed)
+			require.NoError(t, err)
+
+			var indexed bool
+			plan.Inspect(analyzed, func(n sql.Node) bool {
+				if rt, ok := n.(*plan.ResolvedTable); ok {
+					if pit, ok := rt.Table.(*plan.ProcessIndexableTable); ok {
+						_, indexed = pit.IndexableTable.(*IndexedDoltTable)
+					}
+				}
+				return true
+			})
+			assert.Equal(t, test.indexed, indexed)
+
+			rows, err := ExecuteSelect(root, test.query)
+			require.NoError(t, err)
+
+			var ids []int64
+			for _, r := range rows {
+				ids = append(ids, r[0].(int64))
+			}
+			assert.Equal(t, test.expectedIds, ids)
+		})
+	}
+}
+
+func TestIndexOnlyScans(t *testing.T) {
+	tests := []struct {
+		query        string
+		indexOnly    bool
+		expectedRows []sql.Row
+	}{
+		{"select id, age from people where age > 38 order by id", true, []sql.Row{{int64(0), int64(40)}, {int64(4), int64(48)}, {int64(5), int64(40)}}},
+		{"select age from people where age = 40", true, []sql.Row{{int64(40)}, {int64(40)}}},
+		{"select count(*) from people where age > 38", true, []sql.Row{{int64(3)}}},
+		{"select first from people where last = 'Simpson' and first = 'Bart'", true, []sql.Row{{"Bart"}}},
+		{"select id, first from people where age = 40 order by id", false, []sql.Row{{int64(0), "Homer"}, {int64(5), "Barney"}}},
+		{"select id from people where id = 3", false, []sql.Row{{int64(3)}}},
+	}
+
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `create index age_idx on people (age);
+create index name_idx on people (last, first)`)
+	require.NoError(t, err)
+
+	db := NewDatabase("dolt", root, nil, nil)
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(db)
+	engine.Catalog.RegisterIndexDriver(NewDoltIndexDriver(db))
+	require.NoError(t, engine.Init())
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			sqlCtx := sql.NewContext(ctx)
+			parsed, err := parse.Parse(sqlCtx, test.query)
+			require.NoError(t, err)
+
+			analyzed, err := engine.Analyzer.Analyze(sqlCtx, parsed)
+			require.NoError(t, err)
+
+			var indexOnly bool
+			plan.Inspect(analyzed, func(n sql.Node) bool {
+				if rt, ok := n.(*plan.ResolvedTable); ok {
+					if pit, ok := rt.Table.(*plan.ProcessIndexableTable); ok {
+						if idt, ok := pit.IndexableTable.(*IndexedDoltTable); ok {
+							indexOnly = strings.Contains(idt.String(), "index-only scan")
+						}
+					}
+				}
+				return true
+			})
+			assert.Equal(t, test.indexOnly, indexOnly)
+
+			_, iter, err := engine.Query(sql.NewContext(ctx), test.query)
+			require.NoError(t, err)
+			rows, err := sql.RowIterToRows(iter)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRows, rows)
+		})
+	}
+}
+
+func TestSecondaryIndexesUpdatedOnWrite(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, "create index age_idx on people (age)")
+	require.NoError(t, err)
+
+	db := NewDatabase("dolt", root, nil, nil)
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(db)
+	for _, query := range []string{
+		"insert into people (id, first, last, age) values (10, 'Ned', 'Flanders', 40)",
+		"update people set age = 41 where id = 0",
+		"delete from people where id = 5",
+	} {
+		_, err := queryRowCount(sql.NewEmptyContext(), engine.Query, query)
+		require.NoError(t, err)
+	}
+
+	root, err = ExecuteSql(dEnv, db.Root(), "alter table people add column nickname varchar(20)")
+	require.NoError(t, err)
+
+	rows, err := ExecuteSelect(root, "select id from people where age >= 40 order by id")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(0)}, {int64(4)}, {int64(10)}}, rows)
+
+	root, err = ExecuteSql(dEnv, root, "alter table people drop column age")
+	require.NoError(t, err)
+	table, _, err := root.GetTable(ctx, PeopleTableName)
+	require.NoError(t, err)
+	indexes, err := table.GetIndexes(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, indexes)
+}
+
+func TestCombinedIndexLookupErrors(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	db := NewDatabase("dolt", root, nil, nil)
+	idx := &doltIndex{sch: PeopleTestSchema, tableName: PeopleTableName, db: db}
+	lookupErr := errors.New("lookup failed")
+
+	good, err := idx.Get(int64(1))
+	require.NoError(t, err)
+	bad := &doltIndexLookup{idx: idx, err: lookupErr}
+
+	combined := map[string]sql.IndexLookup{
+		"union":        good.(*doltIndexLookup).Union(bad),
+		"intersection": bad.Intersection(good),
+		"difference":   good.(*doltIndexLookup).Difference(bad),
+	}
+
+	for name, lookup := range combined {
+		t.Run(name, func(t *testing.T) {
+			_, err := lookup.(*doltIndexLookup).RowIter(sql.NewContext(ctx))
+			assert.Equal(t, lookupErr, err)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+)
+
+// The positions of the columns of the engine's INFORMATION_SCHEMA tables and columns tables that are read and filled
+// in for dolt tables. Both tables start with the table_catalog, table_schema and table_name columns.
+const (
+	tableSchemaIdx          = 1
+	tableNameIdx            = 2
+	tablesTableCommentIdx   = 20
+	columnsColumnNameIdx    = 3
+	columnsColumnCommentIdx = 19
+)
+
+// informationSchemaDatabase wraps the engine's INFORMATION_SCHEMA database, which doesn't know about the comments of
+// tables and columns, to fill in the comments of the tables of dolt databases.
+type informationSchemaDatabase struct {
+	sql.Database
+	catalog *sql.Catalog
+}
+
+// NewInformationSchemaDatabase returns the INFORMATION_SCHEMA database for the catalog given. Its tables and columns
+// tables give the comments of the tables and columns of the dolt databases in the catalog.
+func NewInformationSchemaDatabase(cat *sql.Catalog) sql.Database {
+	return &informationSchemaDatabase{sql.NewInformationSchemaDatabase(cat), cat}
+}
+
+// GetTableInsensitive returns the INFORMATION_SCHEMA table with the name given.
+func (db *informationSchemaDatabase) GetTableInsensitive(ctx context.Context, tblName string) (sql.Table, bool, error) {
+	tbl, ok, err := db.Database.GetTableInsensitive(ctx, tblName)
+	if err != nil || !ok {
+		return tbl, ok, err
+	}
+
+	switch strings.ToLower(tbl.Name()) {
+	case sql.TablesTableName:
+		return &commentsTable{tbl, db.catalog, fillTableComment}, true, nil
+	case sql.ColumnsTableName:
+		return &commentsTable{tbl, db.catalog, fillColumnComment}, true, nil
+	default:
+		return tbl, true, nil
+	}
+}
+
+// commentsTable is an INFORMATION_SCHEMA table whose rows are filled in with the comments of dolt tables.
+type commentsTable struct {
+	sql.Table
+	catalog *sql.Catalog
+	fill    func(row sql.Row, dt *DoltTable)
+}
+
+// PartitionRows returns the rows of the partition given, with the comments of dolt tables filled in.
+func (t *commentsTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	iter, err := t.Table.PartitionRows(ctx, part)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sql.RowIterToRows(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range rows {
+		dt, ok, err := t.doltTable(ctx, r[tableSchemaIdx].(string), r[tableNameIdx].(string))
+		if err != nil {
+			return nil, err
+		} else if ok {
+			t.fill(r, dt)
+		}
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// doltTable returns the dolt table with the name given in the database named, if there is one.
+func (t *commentsTable) doltTable(ctx context.Context, dbName, tblName string) (*DoltTable, bool, error) {
+	db, err := t.catalog.Database(dbName)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	tbl, ok, err := db.GetTableInsensitive(ctx, tblName)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	dt, ok := tbl.(*DoltTable)
+	return dt, ok, nil
+}
+
+func fillTableComment(row sql.Row, dt *DoltTable) {
+	row[tablesTableCommentIdx] = dt.sch.GetComment()
+}
+
+func fillColumnComment(row sql.Row, dt *DoltTable) {
+	if col, ok := dt.sch.GetAllCols().GetByName(row[columnsColumnNameIdx].(string)); ok {
+		row[columnsColumnCommentIdx] = col.Comment
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestComments(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine, _ := viewsEngine(dEnv, root)
+
+	queryRows(t, engine, "create table pets (id int primary key comment 'The id tag:10', name varchar(20) comment 'It''s called tag:11') comment='The pets'")
+
+	expectedCreate := "CREATE TABLE `pets` (\n" +
+		"  `id` BIGINT NOT NULL COMMENT 'The id tag:10',\n" +
+		"  `name` TEXT COMMENT 'It''s called tag:11',\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") COMMENT='The pets';"
+	assert.Equal(t, []sql.Row{{"pets", expectedCreate}}, queryRows(t, engine, "show create table pets"))
+
+	// the statement recreates the table with the same schema
+	queryRows(t, engine, "drop table pets")
+	queryRows(t, engine, expectedCreate)
+	assert.Equal(t, []sql.Row{{"pets", expectedCreate}}, queryRows(t, engine, "show create table PETS"))
+
+	assert.Equal(t, []sql.Row{{"people", ""}, {"pets", "The pets"}},
+		queryRows(t, engine, "select table_name, table_comment from information_schema.tables where table_name in ('people', 'pets') order by table_name"))
+	assert.Equal(t, []sql.Row{{"id", "The id"}, {"name", "It's called"}},
+		queryRows(t, engine, "select column_name, column_comment from information_schema.columns where table_name = 'pets' order by column_name"))
+
+	// system tables aren't dolt tables, and get the engine's statement
+	rows := queryRows(t, engine, "show create table dolt_log")
+	require.Len(t, rows, 1)
+	assert.Contains(t, rows[0][1], "ENGINE=InnoDB")
+}
@@ -0,0 +1,371 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	sqle "github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/plan"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// MySQL error code reported in warnings for rows skipped by INSERT IGNORE
+const mysqlErrDupEntry = 1062
+
+var ErrOnDupRequiresValues = errors.New("ON DUPLICATE KEY UPDATE is only supported for inserts with a VALUES list")
+
+type insertIgnoreKey struct{}
+
+// insertIgnoreStats tracks the rows skipped by an INSERT IGNORE statement, so that they can be left out of the
+// statement's result.
+type insertIgnoreStats struct {
+	ignored int64
+}
+
+// ExecuteInsert executes the insert statement given. Plain inserts and replaces are passed to the engine unchanged.
+// INSERT IGNORE and INSERT ... ON DUPLICATE KEY UPDATE aren't supported by the engine's parser, so they are handled
+// here:
+//   - For INSERT IGNORE, rows whose primary key already exists in the table, or that duplicate the key of an earlier
+//     row in the same statement, are skipped and reported as warnings on the context's session.
+//   - For ON DUPLICATE KEY UPDATE, each row of the VALUES list is executed as an UPDATE of the row with a matching
+//     primary key, followed by an INSERT of the row if there wasn't one. VALUES(col) in the update expressions refers
+//     to the value the row would have inserted. The rows are all written to the statement's root, which is published
+//     once every row has been, so a row that fails leaves none of them written. Only VALUES lists are supported as a
+//     row source.
+//
+// Primary key columns of the UUID kind that are omitted from the insert's column list are given a new value from
+// UUID() for each row, so that keyless source data can be given surrogate keys.
+//
+// The result is a single row holding the number of rows affected, as for any other insert.
+func ExecuteInsert(ctx *sql.Context, engine *sqle.Engine, db *Database, query string, ins *sqlparser.Insert) (sql.Schema, sql.RowIter, error) {
+	ins, changed, err := defaultUUIDKeys(ctx, db, ins)
+	if err != nil {
+		return nil, nil, err
+	} else if changed {
+		query = sqlparser.String(ins)
+	}
+
+	if len(ins.OnDup) > 0 {
+		return executeInsertOnDup(ctx, engine, db, ins)
+	}
+
+	if len(ins.Ignore) == 0 {
+		return engine.Query(ctx, query)
+	}
+
+	stripped := *ins
+	stripped.Ignore = ""
+
+	stats := &insertIgnoreStats{}
+	sch, rowIter, err := engine.Query(withInsertIgnore(ctx, stats), sqlparser.String(&stripped))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inserted, err := drainAffectedRows(rowIter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sch, sql.RowsToRowIter(sql.NewRow(inserted - stats.ignored)), nil
+}
+
+// defaultUUIDKeys returns a copy of the insert given that supplies UUID() as the value of each UUID primary key column
+// missing from its column list, or false if there are no such columns. Inserts without a column list, and inserts
+// whose rows come from a union or a parenthesized select, are returned unchanged.
+func defaultUUIDKeys(ctx *sql.Context, db *Database, ins *sqlparser.Insert) (*sqlparser.Insert, bool, error) {
+	if len(ins.Columns) == 0 || !ins.Table.Qualifier.IsEmpty() {
+		return ins, false, nil
+	}
+
+	tbl, ok, err := db.GetTableInsensitive(ctx, ins.Table.Name.String())
+	if err != nil || !ok {
+		// A missing table is reported by the engine when the insert is executed
+		return ins, false, err
+	}
+
+	dt, ok := tbl.(*DoltTable)
+	if !ok {
+		return ins, false, nil
+	}
+
+	var missing sqlparser.Columns
+	err = dt.sch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		colIdent := sqlparser.NewColIdent(col.Name)
+		if col.Kind == types.UUIDKind && ins.Columns.FindColumn(colIdent) < 0 {
+			missing = append(missing, colIdent)
+		}
+		return false, nil
+	})
+
+	if err != nil || len(missing) == 0 {
+		return ins, false, err
+	}
+
+	uuidExprs := make([]sqlparser.Expr, len(missing))
+	for i := range missing {
+		uuidExprs[i] = &sqlparser.FuncExpr{Name: sqlparser.NewColIdent("uuid")}
+	}
+
+	withKeys := *ins
+	withKeys.Columns = append(append(sqlparser.Columns{}, ins.Columns...), missing...)
+
+	switch rows := ins.Rows.(type) {
+	case sqlparser.Values:
+		values := make(sqlparser.Values, len(rows))
+		for i, tuple := range rows {
+			values[i] = append(append(sqlparser.ValTuple{}, tuple...), uuidExprs...)
+		}
+		withKeys.Rows = values
+	case *sqlparser.Select:
+		sel := *rows
+		sel.SelectExprs = append(sqlparser.SelectExprs{}, rows.SelectExprs...)
+		for _, expr := range uuidExprs {
+			sel.SelectExprs = append(sel.SelectExprs, &sqlparser.AliasedExpr{Expr: expr})
+		}
+		withKeys.Rows = &sel
+	default:
+		return ins, false, nil
+	}
+
+	return &withKeys, true, nil
+}
+
+func withInsertIgnore(ctx *sql.Context, stats *insertIgnoreStats) *sql.Context {
+	return ctx.WithContext(context.WithValue(ctx.Context, insertIgnoreKey{}, stats))
+}
+
+// insertIgnoreFromContext returns the stats for the INSERT IGNORE statement being executed with the context given, or
+// nil if the context isn't executing an INSERT IGNORE.
+func insertIgnoreFromContext(ctx context.Context) *insertIgnoreStats {
+	stats, _ := ctx.Value(insertIgnoreKey{}).(*insertIgnoreStats)
+	return stats
+}
+
+func executeInsertOnDup(ctx *sql.Context, engine *sqle.Engine, db *Database, ins *sqlparser.Insert) (sql.Schema, sql.RowIter, error) {
+	values, ok := ins.Rows.(sqlparser.Values)
+	if !ok {
+		return nil, nil, ErrOnDupRequiresValues
+	}
+
+	tableName := ins.Table.Name.String()
+	tbl, ok, err := db.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, sql.ErrTableNotFound.New(tableName)
+	}
+
+	dt, ok := tbl.(*DoltTable)
+	if !ok {
+		return nil, nil, plan.ErrInsertIntoNotSupported.New()
+	}
+
+	colNames := make([]string, len(ins.Columns))
+	for i, col := range ins.Columns {
+		colNames[i] = col.Lowered()
+	}
+
+	if len(colNames) == 0 {
+		for _, col := range dt.sqlSchema() {
+			colNames = append(colNames, strings.ToLower(col.Name))
+		}
+	}
+
+	var pkCols []string
+	for _, pkCol := range dt.sch.GetPKCols().GetColumnNames() {
+		pkCols = append(pkCols, strings.ToLower(pkCol))
+	}
+
+	// Batched databases are written directly rather than through a statement root, so their root is restored if a row
+	// fails
+	var snapshot *doltdb.RootValue
+	if db.batchMode == batched {
+		if err := db.Flush(ctx); err != nil {
+			return nil, nil, err
+		}
+		snapshot = db.Root()
+	} else {
+		db.beginStatement(ctx)
+	}
+
+	affected, err := insertOnDupRows(asStatementPart(ctx), engine, db, ins, values, colNames, pkCols)
+	if snapshot != nil {
+		if err != nil {
+			db.replaceRoot(snapshot)
+			return nil, nil, err
+		}
+	} else {
+		// The statement's writes are dropped along with its root if a row failed
+		defer db.endStatements(ctx.ID())
+		if err == nil {
+			err = db.written(ctx)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return new(plan.InsertInto).Schema(), sql.RowsToRowIter(sql.NewRow(affected)), nil
+}
+
+// insertOnDupRows executes the UPDATE or INSERT for each of the rows of the ON DUPLICATE KEY UPDATE insert given, and
+// returns the number of rows affected.
+func insertOnDupRows(ctx *sql.Context, engine *sqle.Engine, db *Database, ins *sqlparser.Insert, values sqlparser.Values, colNames, pkCols []string) (int64, error) {
+	var affected int64
+	for _, tuple := range values {
+		if len(tuple) != len(colNames) {
+			return 0, plan.ErrInsertIntoMismatchValueCount.New()
+		}
+
+		vals := make(map[string]sqlparser.Expr, len(colNames))
+		for i, colName := range colNames {
+			vals[colName] = tuple[i]
+		}
+
+		update, ok := onDupUpdateQuery(ins, pkCols, vals)
+
+		var matched, updated int64
+		if ok {
+			var err error
+			matched, updated, err = executeRowCountQuery(ctx, engine, db, update)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if matched > 0 {
+			// Following MySQL, an existing row that was changed counts as two affected rows
+			affected += 2 * updated
+			continue
+		}
+
+		insertRow := &sqlparser.Insert{
+			Action:  ins.Action,
+			Table:   ins.Table,
+			Columns: ins.Columns,
+			Rows:    sqlparser.Values{tuple},
+		}
+
+		inserted, _, err := executeRowCountQuery(ctx, engine, db, sqlparser.String(insertRow))
+		if err != nil {
+			return 0, err
+		}
+
+		affected += inserted
+	}
+
+	return affected, nil
+}
+
+// onDupUpdateQuery returns the UPDATE statement that applies the ON DUPLICATE KEY UPDATE expressions of the insert
+// given to the existing row with the primary key in vals. Returns false if vals doesn't include every primary key
+// column, in which case there can't be an existing row to update.
+func onDupUpdateQuery(ins *sqlparser.Insert, pkCols []string, vals map[string]sqlparser.Expr) (string, bool) {
+	var where sqlparser.Expr
+	for _, pkCol := range pkCols {
+		val, ok := vals[pkCol]
+		if !ok {
+			return "", false
+		}
+
+		cmp := &sqlparser.ComparisonExpr{
+			Operator: sqlparser.EqualStr,
+			Left:     &sqlparser.ColName{Name: sqlparser.NewColIdent(pkCol)},
+			Right:    val,
+		}
+
+		if where == nil {
+			where = cmp
+		} else {
+			where = &sqlparser.AndExpr{Left: where, Right: cmp}
+		}
+	}
+
+	buf := sqlparser.NewTrackedBuffer(func(buf *sqlparser.TrackedBuffer, node sqlparser.SQLNode) {
+		if valuesFunc, ok := node.(*sqlparser.ValuesFuncExpr); ok {
+			if val, ok := vals[valuesFunc.Name.Name.Lowered()]; ok {
+				buf.Myprintf("%v", val)
+			} else {
+				buf.Myprintf("null")
+			}
+			return
+		}
+		node.Format(buf)
+	})
+	buf.Myprintf("update %v set %v where %v", ins.Table, sqlparser.UpdateExprs(ins.OnDup), where)
+
+	return buf.String(), true
+}
+
+// executeRowCountQuery executes the insert or update query given and returns the counts in its result row. Batched
+// edits are flushed after the query so that the next query sees its effects.
+func executeRowCountQuery(ctx *sql.Context, engine *sqle.Engine, db *Database, query string) (int64, int64, error) {
+	_, rowIter, err := engine.Query(ctx, query)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer rowIter.Close()
+
+	row, err := rowIter.Next()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if db.batchMode == batched {
+		if err := db.Flush(ctx); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	var counts [2]int64
+	for i := 0; i < len(row) && i < len(counts); i++ {
+		n, ok := row[i].(int64)
+		if !ok {
+			return 0, 0, fmt.Errorf("unexpected result %v for query '%s'", row, query)
+		}
+		counts[i] = n
+	}
+
+	return counts[0], counts[1], nil
+}
+
+// drainAffectedRows reads the single count row returned by an insert and closes the iterator.
+func drainAffectedRows(rowIter sql.RowIter) (int64, error) {
+	defer rowIter.Close()
+
+	var affected int64
+	for {
+		row, err := rowIter.Next()
+		if err == io.EOF {
+			return affected, nil
+		} else if err != nil {
+			return 0, err
+		}
+
+		affected += row[0].(int64)
+	}
+}
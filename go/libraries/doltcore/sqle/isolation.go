@@ -0,0 +1,136 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/src-d/go-mysql-server/sql/plan"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// TransactionIsolationVar is the session variable holding the isolation level of the statements run in a session.
+const TransactionIsolationVar = "transaction_isolation"
+
+const isolationRuleName = "dolt_transaction_isolation"
+
+// IsolationLevel is the isolation level of the statements run in a session. Every statement is its own transaction.
+type IsolationLevel string
+
+const (
+	// ReadUncommitted, ReadCommitted and RepeatableRead all read from a snapshot: each statement reads the latest root
+	// as of when it started, and its writes are merged into the root when it ends. If another statement wrote to the
+	// same rows in between, the statement fails with ErrWriteConflict.
+	ReadUncommitted IsolationLevel = "READ-UNCOMMITTED"
+	ReadCommitted   IsolationLevel = "READ-COMMITTED"
+	RepeatableRead  IsolationLevel = "REPEATABLE-READ"
+	// Serializable reads from a snapshot like the others, but the writes of a statement are validated when it ends and
+	// fail with ErrSerializationFailure if the root has changed since the statement started.
+	Serializable IsolationLevel = "SERIALIZABLE"
+)
+
+// DefaultIsolationLevel is the isolation level of sessions that haven't set one.
+const DefaultIsolationLevel = RepeatableRead
+
+var ErrSerializationFailure = errors.New("serialization failure: the database was changed by another statement while this one ran, try it again")
+var ErrUnknownIsolationLevelFmt = "unknown isolation level '%s', valid values are '%s', '%s', '%s' and '%s'"
+
+// ParseIsolationLevel returns the isolation level with the name given, which may be written with spaces or hyphens
+// and in any case.
+func ParseIsolationLevel(name string) (IsolationLevel, error) {
+	level := IsolationLevel(strings.ToUpper(strings.Join(strings.Fields(strings.Replace(name, "-", " ", -1)), "-")))
+	switch level {
+	case ReadUncommitted, ReadCommitted, RepeatableRead, Serializable:
+		return level, nil
+	}
+
+	return "", fmt.Errorf(ErrUnknownIsolationLevelFmt, name, ReadUncommitted, ReadCommitted, RepeatableRead, Serializable)
+}
+
+// SetIsolationDefault sets the session's isolation level to the default one.
+func SetIsolationDefault(sess sql.Session) {
+	sess.Set(TransactionIsolationVar, sql.Text, string(DefaultIsolationLevel))
+}
+
+// isolationLevel returns the isolation level set in the session given, or the default one if it's unset or invalid.
+func isolationLevel(sess sql.Session) IsolationLevel {
+	_, val := sess.Get(TransactionIsolationVar)
+	name, ok := val.(string)
+	if !ok {
+		return DefaultIsolationLevel
+	}
+
+	level, err := ParseIsolationLevel(name)
+	if err != nil {
+		return DefaultIsolationLevel
+	}
+
+	return level
+}
+
+// setIsolation is an analyzer rule that rewrites SET TRANSACTION ISOLATION LEVEL, which the parser turns into a
+// variable named transaction, into a SET of the transaction_isolation variable, and validates the isolation levels
+// given to SET transaction_isolation.
+func setIsolation(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	set, ok := n.(*plan.Set)
+	if !ok {
+		return n, nil
+	}
+
+	const isolationLevelPrefix = "isolation level "
+	variables := make([]plan.SetVariable, len(set.Variables))
+	for i, v := range set.Variables {
+		variables[i] = v
+
+		name := strings.ToLower(strings.TrimPrefix(strings.TrimLeft(v.Name, "@"), sqlparser.SessionStr+"."))
+		if name != sqlparser.TransactionStr && name != TransactionIsolationVar {
+			continue
+		}
+
+		lit, ok := v.Value.(*expression.Literal)
+		if !ok {
+			continue
+		}
+
+		val, ok := lit.Value().(string)
+		if !ok {
+			continue
+		}
+
+		if name == sqlparser.TransactionStr {
+			if !strings.HasPrefix(val, isolationLevelPrefix) {
+				continue
+			}
+			val = val[len(isolationLevelPrefix):]
+		}
+
+		level, err := ParseIsolationLevel(val)
+		if err != nil {
+			return nil, err
+		}
+
+		variables[i] = plan.SetVariable{
+			Name:  TransactionIsolationVar,
+			Value: expression.NewLiteral(string(level), sql.Text),
+		}
+	}
+
+	return plan.NewSet(variables...), nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestParseIsolationLevel(t *testing.T) {
+	for name, expected := range map[string]IsolationLevel{
+		"READ-UNCOMMITTED": ReadUncommitted,
+		"read committed":   ReadCommitted,
+		"Repeatable-Read":  RepeatableRead,
+		"serializable":     Serializable,
+	} {
+		level, err := ParseIsolationLevel(name)
+		require.NoError(t, err)
+		assert.Equal(t, expected, level)
+	}
+
+	_, err := ParseIsolationLevel("snapshot")
+	assert.Error(t, err)
+}
+
+func TestSetIsolation(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected IsolationLevel
+	}{
+		{"SET TRANSACTION ISOLATION LEVEL SERIALIZABLE", Serializable},
+		{"SET SESSION TRANSACTION ISOLATION LEVEL READ COMMITTED", ReadCommitted},
+		{"SET TRANSACTION READ WRITE, ISOLATION LEVEL REPEATABLE READ", RepeatableRead},
+		{"SET transaction_isolation = 'serializable'", Serializable},
+		{"SET @@session.transaction_isolation = 'read-uncommitted'", ReadUncommitted},
+	}
+
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			ctx := sql.NewEmptyContext()
+			_, err := queryRowCount(ctx, engine.Query, test.query)
+			require.NoError(t, err)
+
+			_, val := ctx.Get(TransactionIsolationVar)
+			assert.Equal(t, string(test.expected), val)
+			assert.Equal(t, test.expected, isolationLevel(ctx.Session))
+		})
+	}
+
+	_, err = queryRowCount(sql.NewEmptyContext(), engine.Query, "SET transaction_isolation = 'snapshot'")
+	assert.Error(t, err)
+}
+
+func TestSerializableIsolation(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      string
+		expected error
+	}{
+		{"default", "", nil},
+		{"repeatable read", "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ", nil},
+		{"serializable", "SET TRANSACTION ISOLATION LEVEL SERIALIZABLE", ErrSerializationFailure},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+
+			watcher, err := NewRootWatcher(ctx, dEnv)
+			require.NoError(t, err)
+			engine1, db1 := watchedEngine(t, dEnv, watcher)
+			engine2, _ := watchedEngine(t, dEnv, watcher)
+
+			ctx1 := sql.NewContext(ctx, sql.WithSession(sql.NewSession("", "", "", 1)))
+			ctx2 := sql.NewContext(ctx, sql.WithSession(sql.NewSession("", "", "", 2)))
+			if test.set != "" {
+				_, err = queryRowCount(ctx1, engine1.Query, test.set)
+				require.NoError(t, err)
+			}
+
+			// session 1 starts a statement, then session 2 writes before the statement of session 1 writes
+			_, err = queryRowCount(ctx1, engine1.Query, "select * from people")
+			require.NoError(t, err)
+			people := countPeople(t, engine2)
+			_, err = queryRowCount(ctx2, engine2.Query, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+			require.NoError(t, err)
+
+			sch := sql.Schema{{Name: "id", Type: sql.Int64, PrimaryKey: true, Source: "t"}}
+			err = db1.CreateTable(ctx1, "t", sch)
+			assert.Equal(t, test.expected, err)
+
+			_, exists, err := db1.GetTableInsensitive(ctx1, "t")
+			require.NoError(t, err)
+			if test.expected == nil {
+				// the statement's snapshot didn't include the insert, which its write was merged with
+				assert.True(t, exists)
+				assert.Equal(t, people+1, countPeople(t, engine2))
+			} else {
+				// the write was rejected and the insert kept
+				assert.False(t, exists)
+				assert.Equal(t, people+1, countPeople(t, engine2))
+			}
+
+			// the next statement starts from the latest root, so it can write
+			_, err = queryRowCount(ctx1, engine1.Query, "insert into people (id, first, last) values (11, 'Maude', 'Flanders')")
+			require.NoError(t, err)
+		})
+	}
+}
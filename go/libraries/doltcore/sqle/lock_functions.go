@@ -0,0 +1,187 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+)
+
+// LockFunctions returns the functions that take and release the advisory locks of the lock manager given, as in
+// MySQL:
+//
+//	GET_LOCK(name, timeout)  takes the lock, waiting up to timeout seconds, or forever if it's negative. Returns 1 if
+//	                         the lock was taken and 0 if it timed out.
+//	RELEASE_LOCK(name)       releases the lock. Returns 1 if it was released, 0 if another session holds it and NULL
+//	                         if no session does.
+//	RELEASE_ALL_LOCKS()      releases all of the session's locks. Returns the number of locks released.
+//	IS_FREE_LOCK(name)       returns 1 if no session holds the lock, and 0 otherwise.
+//	IS_USED_LOCK(name)       returns the connection ID of the session holding the lock, or NULL if no session does.
+//
+// The locks of tables and branches are named by TableLockName and BranchLockName.
+func LockFunctions(lm *LockManager) []sql.Function {
+	return []sql.Function{
+		sql.Function2{Name: "get_lock", Fn: func(name, timeout sql.Expression) sql.Expression {
+			return &LockFunc{"GET_LOCK", lm, []sql.Expression{name, timeout}, getLock}
+		}},
+		sql.Function1{Name: "release_lock", Fn: func(name sql.Expression) sql.Expression {
+			return &LockFunc{"RELEASE_LOCK", lm, []sql.Expression{name}, releaseLock}
+		}},
+		sql.Function0{Name: "release_all_locks", Fn: func() sql.Expression {
+			return &LockFunc{"RELEASE_ALL_LOCKS", lm, nil, releaseAllLocks}
+		}},
+		sql.Function1{Name: "is_free_lock", Fn: func(name sql.Expression) sql.Expression {
+			return &LockFunc{"IS_FREE_LOCK", lm, []sql.Expression{name}, isFreeLock}
+		}},
+		sql.Function1{Name: "is_used_lock", Fn: func(name sql.Expression) sql.Expression {
+			return &LockFunc{"IS_USED_LOCK", lm, []sql.Expression{name}, isUsedLock}
+		}},
+	}
+}
+
+// lockFuncImpl is the implementation of a lock function, called with the values of its arguments. The values of lock
+// names are strings, and the functions aren't called when a name is NULL.
+type lockFuncImpl func(ctx *sql.Context, lm *LockManager, args []interface{}) (interface{}, error)
+
+// LockFunc is one of the functions returned by LockFunctions.
+type LockFunc struct {
+	name  string
+	locks *LockManager
+	args  []sql.Expression
+	impl  lockFuncImpl
+}
+
+var _ sql.Expression = (*LockFunc)(nil)
+
+// Children implements sql.Expression
+func (f *LockFunc) Children() []sql.Expression { return f.args }
+
+// Type implements sql.Expression
+func (f *LockFunc) Type() sql.Type { return sql.Int64 }
+
+// Resolved implements sql.Expression
+func (f *LockFunc) Resolved() bool {
+	for _, arg := range f.args {
+		if !arg.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNullable implements sql.Expression
+func (f *LockFunc) IsNullable() bool { return true }
+
+// WithChildren implements sql.Expression
+func (f *LockFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != len(f.args) {
+		return nil, sql.ErrInvalidChildrenNumber.New(f, len(children), len(f.args))
+	}
+	return &LockFunc{f.name, f.locks, children, f.impl}, nil
+}
+
+// String implements fmt.Stringer
+func (f *LockFunc) String() string {
+	args := make([]string, len(f.args))
+	for i, arg := range f.args {
+		args[i] = arg.String()
+	}
+	return fmt.Sprintf("%s(%s)", f.name, strings.Join(args, ", "))
+}
+
+// Eval implements sql.Expression
+func (f *LockFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	vals := make([]interface{}, len(f.args))
+	for i, arg := range f.args {
+		val, err := arg.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+
+	// The first argument of the functions that have any is the lock name
+	if len(vals) > 0 {
+		if vals[0] == nil {
+			return nil, nil
+		}
+
+		name, err := sql.Text.Convert(vals[0])
+		if err != nil {
+			return nil, err
+		}
+		vals[0] = name
+	}
+
+	return f.impl(ctx, f.locks, vals)
+}
+
+func getLock(ctx *sql.Context, lm *LockManager, args []interface{}) (interface{}, error) {
+	timeout := time.Duration(-1)
+	if args[1] != nil {
+		secs, err := sql.Float64.Convert(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if secs.(float64) >= 0 {
+			timeout = time.Duration(secs.(float64) * float64(time.Second))
+		}
+	}
+
+	ok, err := lm.Lock(ctx, args[0].(string), ctx.ID(), timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return boolToInt64(ok), nil
+}
+
+func releaseLock(ctx *sql.Context, lm *LockManager, args []interface{}) (interface{}, error) {
+	released, exists := lm.Unlock(args[0].(string), ctx.ID())
+	if !exists {
+		return nil, nil
+	}
+
+	return boolToInt64(released), nil
+}
+
+func releaseAllLocks(ctx *sql.Context, lm *LockManager, _ []interface{}) (interface{}, error) {
+	return int64(lm.UnlockAll(ctx.ID())), nil
+}
+
+func isFreeLock(ctx *sql.Context, lm *LockManager, args []interface{}) (interface{}, error) {
+	_, held := lm.Holder(args[0].(string))
+	return boolToInt64(!held), nil
+}
+
+func isUsedLock(ctx *sql.Context, lm *LockManager, args []interface{}) (interface{}, error) {
+	session, held := lm.Holder(args[0].(string))
+	if !held {
+		return nil, nil
+	}
+
+	return int64(session), nil
+}
+
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
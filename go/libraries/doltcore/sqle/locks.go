@@ -0,0 +1,190 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	tableLockPrefix  = "dolt_table:"
+	branchLockPrefix = "dolt_branch:"
+)
+
+var ErrEmptyLockName = errors.New("lock names cannot be empty")
+
+// TableLockName returns the name of the lock taken by LockTable for the table given, which SQL clients can take with
+// GET_LOCK to serialize their access to the table with other clients and with dolt.
+func TableLockName(table string) string {
+	return tableLockPrefix + table
+}
+
+// BranchLockName returns the name of the lock taken by LockBranch for the branch given, which SQL clients can take with
+// GET_LOCK to serialize their access to the branch with other clients and with dolt.
+func BranchLockName(branch string) string {
+	return branchLockPrefix + branch
+}
+
+// LockManager holds the named advisory locks taken by sessions, through the GET_LOCK family of functions or its own
+// methods. Locks don't prevent any reads or writes: they only let the clients that agree to take them serialize their
+// work, such as ETL jobs loading the same tables.
+//
+// A lock is held by one session at a time, which may take it more than once and must release it as many times. Lock
+// names are case insensitive. Integrators must call UnlockAll when a session ends, to release the locks it still holds.
+type LockManager struct {
+	mu    sync.Mutex
+	locks map[string]*advisoryLock
+}
+
+type advisoryLock struct {
+	session  uint32
+	count    int
+	released chan struct{} // Closed when the lock is released
+}
+
+// NewLockManager returns a new LockManager without any locks.
+func NewLockManager() *LockManager {
+	return &LockManager{locks: make(map[string]*advisoryLock)}
+}
+
+// Lock takes the lock with the name given for the session given, waiting up to the timeout given for another session
+// holding it to release it. A negative timeout waits until the lock is released or the context is canceled. Returns
+// whether the lock was taken.
+func (lm *LockManager) Lock(ctx context.Context, name string, session uint32, timeout time.Duration) (bool, error) {
+	if name == "" {
+		return false, ErrEmptyLockName
+	}
+
+	name = strings.ToLower(name)
+
+	var timedOut <-chan time.Time
+	if timeout >= 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timedOut = timer.C
+	}
+
+	for {
+		released, ok := lm.tryLock(name, session)
+		if ok {
+			return true, nil
+		}
+
+		select {
+		case <-released:
+		case <-timedOut:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// tryLock takes the lock with the name given for the session given if it's free or the session holds it already.
+// Otherwise, returns a channel that's closed when the lock is released.
+func (lm *LockManager) tryLock(name string, session uint32) (<-chan struct{}, bool) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	l, ok := lm.locks[name]
+	if ok && l.session != session {
+		return l.released, false
+	}
+
+	if !ok {
+		l = &advisoryLock{session: session, released: make(chan struct{})}
+		lm.locks[name] = l
+	}
+
+	l.count++
+	return nil, true
+}
+
+// Unlock releases the lock with the name given once, if the session given holds it. Returns whether the session held
+// the lock, and whether the lock is held by any session.
+func (lm *LockManager) Unlock(name string, session uint32) (released bool, exists bool) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	name = strings.ToLower(name)
+	l, ok := lm.locks[name]
+	if !ok {
+		return false, false
+	} else if l.session != session {
+		return false, true
+	}
+
+	l.count--
+	if l.count == 0 {
+		delete(lm.locks, name)
+		close(l.released)
+	}
+
+	return true, true
+}
+
+// UnlockAll releases all the locks held by the session given, and returns the number of times they had been taken.
+func (lm *LockManager) UnlockAll(session uint32) int {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	count := 0
+	for name, l := range lm.locks {
+		if l.session == session {
+			count += l.count
+			delete(lm.locks, name)
+			close(l.released)
+		}
+	}
+
+	return count
+}
+
+// Holder returns the ID of the session holding the lock with the name given, and whether any session holds it.
+func (lm *LockManager) Holder(name string) (uint32, bool) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	l, ok := lm.locks[strings.ToLower(name)]
+	if !ok {
+		return 0, false
+	}
+
+	return l.session, true
+}
+
+// LockTable takes the lock of the table given for the session given, as Lock does.
+func (lm *LockManager) LockTable(ctx context.Context, table string, session uint32, timeout time.Duration) (bool, error) {
+	return lm.Lock(ctx, TableLockName(table), session, timeout)
+}
+
+// UnlockTable releases the lock of the table given, as Unlock does.
+func (lm *LockManager) UnlockTable(table string, session uint32) (released bool, exists bool) {
+	return lm.Unlock(TableLockName(table), session)
+}
+
+// LockBranch takes the lock of the branch given for the session given, as Lock does.
+func (lm *LockManager) LockBranch(ctx context.Context, branch string, session uint32, timeout time.Duration) (bool, error) {
+	return lm.Lock(ctx, BranchLockName(branch), session, timeout)
+}
+
+// UnlockBranch releases the lock of the branch given, as Unlock does.
+func (lm *LockManager) UnlockBranch(branch string, session uint32) (released bool, exists bool) {
+	return lm.Unlock(BranchLockName(branch), session)
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestLockManager(t *testing.T) {
+	ctx := context.Background()
+	lm := NewLockManager()
+
+	ok, err := lm.Lock(ctx, "etl", 1, 0)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// the session holding a lock can take it again, and names are case insensitive
+	ok, err = lm.Lock(ctx, "ETL", 1, 0)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = lm.Lock(ctx, "etl", 2, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	holder, held := lm.Holder("Etl")
+	assert.True(t, held)
+	assert.Equal(t, uint32(1), holder)
+
+	released, exists := lm.Unlock("etl", 2)
+	assert.False(t, released)
+	assert.True(t, exists)
+
+	// a waiting session gets the lock once it's been released as many times as it was taken
+	taken := make(chan bool)
+	go func() {
+		ok, _ := lm.Lock(ctx, "etl", 2, -1)
+		taken <- ok
+	}()
+
+	released, _ = lm.Unlock("etl", 1)
+	assert.True(t, released)
+	select {
+	case <-taken:
+		t.Fatal("lock taken before it was released")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	released, _ = lm.Unlock("etl", 1)
+	assert.True(t, released)
+	assert.True(t, <-taken)
+
+	holder, _ = lm.Holder("etl")
+	assert.Equal(t, uint32(2), holder)
+
+	ok, err = lm.LockTable(ctx, "people", 2, 0)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = lm.LockBranch(ctx, "master", 2, 0)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	holder, held = lm.Holder(TableLockName("people"))
+	assert.True(t, held)
+	assert.Equal(t, uint32(2), holder)
+
+	assert.Equal(t, 3, lm.UnlockAll(2))
+	_, held = lm.Holder("etl")
+	assert.False(t, held)
+	released, exists = lm.UnlockBranch("master", 2)
+	assert.False(t, released)
+	assert.False(t, exists)
+
+	_, err = lm.Lock(ctx, "", 1, 0)
+	assert.Equal(t, ErrEmptyLockName, err)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	lm.Lock(ctx, "etl", 1, 0)
+	_, err = lm.Lock(canceled, "etl", 2, -1)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestLockFunctions(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+	engine.Catalog.MustRegister(LockFunctions(NewLockManager())...)
+
+	session1 := sql.NewContext(context.Background(), sql.WithSession(sql.NewSession("", "", "", 1)))
+	session2 := sql.NewContext(context.Background(), sql.WithSession(sql.NewSession("", "", "", 2)))
+
+	tests := []struct {
+		ctx      *sql.Context
+		query    string
+		expected interface{}
+	}{
+		{session1, "select get_lock('etl', 0)", int64(1)},
+		{session1, "select get_lock('etl', 0)", int64(1)},
+		{session1, "select get_lock('dolt_table:people', 1.5)", int64(1)},
+		{session2, "select get_lock('ETL', 0.01)", int64(0)},
+		{session2, "select is_free_lock('etl')", int64(0)},
+		{session2, "select is_free_lock('other')", int64(1)},
+		{session2, "select is_used_lock('etl')", int64(1)},
+		{session2, "select is_used_lock('other')", nil},
+		{session2, "select release_lock('etl')", int64(0)},
+		{session2, "select release_lock('other')", nil},
+		{session2, "select get_lock(null, 0)", nil},
+		{session1, "select release_lock('etl')", int64(1)},
+		{session1, "select release_all_locks()", int64(2)},
+		{session2, "select get_lock('etl', 0)", int64(1)},
+	}
+
+	for _, test := range tests {
+		_, iter, err := engine.Query(test.ctx, test.query)
+		require.NoError(t, err, test.query)
+		rows, err := sql.RowIterToRows(iter)
+		require.NoError(t, err, test.query)
+		require.Len(t, rows, 1, test.query)
+		assert.Equal(t, test.expected, rows[0][0], "session %d: %s", test.ctx.ID(), test.query)
+	}
+}
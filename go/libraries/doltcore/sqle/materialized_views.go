@@ -0,0 +1,233 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema/encoding"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ErrMaterializedViewNotSelect is returned when a materialized view is defined by a statement other than a select.
+var ErrMaterializedViewNotSelect = errors.New("materialized views must be defined by a select statement")
+
+// MaterializeView defines a materialized view with the name and select statement given, replacing any existing
+// materialized view with the same name, and stores the statement's results in the table with the view's name. The
+// first column of the results is the table's primary key, so it must be unique and not null. The results are
+// recomputed by RefreshMaterializedViews, which is run on the staged root before each commit, so the table always holds
+// the results of the statement as of the commit it's in.
+func MaterializeView(ctx context.Context, root *doltdb.RootValue, name, query string) (*doltdb.RootValue, error) {
+	if !doltdb.IsValidTableName(name) {
+		return nil, fmt.Errorf("invalid table name: '%s'", name)
+	}
+
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	switch stmt.(type) {
+	case *sqlparser.Select, *sqlparser.Union, *sqlparser.ParenSelect:
+	default:
+		return nil, ErrMaterializedViewNotSelect
+	}
+
+	_, isView, err := root.GetSchemaFragment(ctx, doltdb.MaterializedViewFragmentType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isView {
+		if exists, err := root.HasTable(ctx, name); err != nil {
+			return nil, err
+		} else if exists {
+			return nil, sql.ErrTableAlreadyExists.New(name)
+		}
+	}
+
+	frag := doltdb.SchemaFragment{Type: doltdb.MaterializedViewFragmentType, Name: name, Fragment: query}
+	root, err = materialize(ctx, root, frag)
+	if err != nil {
+		return nil, err
+	}
+
+	return root.PutSchemaFragment(ctx, frag)
+}
+
+// DropMaterializedView removes the materialized view with the name given along with its table, returning false if
+// there's no such view.
+func DropMaterializedView(ctx context.Context, root *doltdb.RootValue, name string) (*doltdb.RootValue, bool, error) {
+	root, ok, err := root.RemoveSchemaFragment(ctx, doltdb.MaterializedViewFragmentType, name)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	if exists, err := root.HasTable(ctx, name); err != nil {
+		return nil, false, err
+	} else if exists {
+		root, err = root.RemoveTables(ctx, name)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return root, true, nil
+}
+
+// RefreshMaterializedViews recomputes the results of each materialized view of the root given, in order of name, and
+// returns the root with each view's table holding its results. Tables whose results haven't changed are unchanged.
+func RefreshMaterializedViews(ctx context.Context, root *doltdb.RootValue) (*doltdb.RootValue, error) {
+	frags, err := root.GetSchemaFragments(ctx, doltdb.MaterializedViewFragmentType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, frag := range frags {
+		root, err = materialize(ctx, root, frag)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
+
+// RefreshStagedMaterializedViews refreshes the materialized views of the staged root of the environment given, so that
+// their results are committed along with the tables they're computed from. The refreshed tables are also copied to the
+// working root, so they don't show as unstaged changes.
+func RefreshStagedMaterializedViews(ctx context.Context, dEnv *env.DoltEnv) error {
+	staged, err := dEnv.StagedRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	refreshed, err := RefreshMaterializedViews(ctx, staged)
+	if err != nil {
+		return err
+	}
+
+	if unchanged, err := rootsEqual(staged, refreshed); err != nil || unchanged {
+		return err
+	}
+
+	working, err := dEnv.WorkingRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	frags, err := refreshed.GetSchemaFragments(ctx, doltdb.MaterializedViewFragmentType)
+	if err != nil {
+		return err
+	}
+
+	for _, frag := range frags {
+		tbl, _, err := refreshed.GetTable(ctx, frag.Name)
+		if err != nil {
+			return err
+		}
+
+		working, err = working.PutTable(ctx, frag.Name, tbl)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err = dEnv.UpdateStagedRoot(ctx, refreshed); err != nil {
+		return err
+	}
+
+	return dEnv.UpdateWorkingRoot(ctx, working)
+}
+
+// materialize runs the select statement of the materialized view given against the root given, and returns the root
+// with the view's table replaced by one holding the results.
+func materialize(ctx context.Context, root *doltdb.RootValue, frag doltdb.SchemaFragment) (*doltdb.RootValue, error) {
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, nil, nil))
+
+	sqlSch, iter, err := engine.Query(sql.NewContext(ctx), frag.Fragment)
+	if err != nil {
+		return nil, fmt.Errorf("error computing materialized view %s: %v", frag.Name, err)
+	}
+	defer iter.Close()
+
+	cols := make(sql.Schema, len(sqlSch))
+	for i, col := range sqlSch {
+		cols[i] = &sql.Column{Name: col.Name, Type: col.Type, Nullable: i > 0, PrimaryKey: i == 0}
+	}
+
+	sch, err := SqlSchemaToDoltSchema(cols)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the table of materialized view %s: %v", frag.Name, err)
+	}
+
+	m, err := types.NewMap(ctx, root.VRW())
+	if err != nil {
+		return nil, err
+	}
+
+	me := m.Edit()
+	var numRows uint64
+	for {
+		r, err := iter.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("error computing materialized view %s: %v", frag.Name, err)
+		}
+
+		if err := checkNotNull(r, sch); err != nil {
+			return nil, fmt.Errorf("the first column of materialized view %s is its table's primary key, so it can't be null", frag.Name)
+		}
+
+		dRow, err := SqlRowToDoltRow(root.VRW().Format(), r, sch)
+		if err != nil {
+			return nil, err
+		}
+
+		me.Set(dRow.NomsMapKey(sch), dRow.NomsMapValue(sch))
+		numRows++
+	}
+
+	m, err = me.Map(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Len() != numRows {
+		return nil, fmt.Errorf("the first column of materialized view %s is its table's primary key, so it must be unique", frag.Name)
+	}
+
+	schVal, err := encoding.MarshalAsNomsValue(ctx, root.VRW(), sch)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := doltdb.NewTable(ctx, root.VRW(), schVal, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return root.PutTable(ctx, frag.Name, tbl)
+}
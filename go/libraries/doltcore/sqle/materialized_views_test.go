@@ -0,0 +1,111 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+const lastNamesQuery = "select last, count(*) as n from people group by last"
+
+func TestMaterializedViews(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	root, err = MaterializeView(ctx, root, "last_names", lastNamesQuery)
+	require.NoError(t, err)
+	expected := []sql.Row{{"Gumble", int64(1)}, {"Simpson", int64(4)}, {"Szyslak", int64(1)}}
+	assert.Equal(t, expected, rootQuery(t, root, "select * from last_names order by last"))
+
+	// the results aren't recomputed until the views are refreshed, and refreshing them again changes nothing
+	root, err = executeModify(ctx, root, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	require.NoError(t, err)
+	assert.Equal(t, expected, rootQuery(t, root, "select * from last_names order by last"))
+
+	root, err = RefreshMaterializedViews(ctx, root)
+	require.NoError(t, err)
+	expected = append([]sql.Row{{"Flanders", int64(1)}}, expected...)
+	assert.Equal(t, expected, rootQuery(t, root, "select * from last_names order by last"))
+
+	refreshed, err := RefreshMaterializedViews(ctx, root)
+	require.NoError(t, err)
+	assertRootsEqual(t, root, refreshed)
+
+	_, err = MaterializeView(ctx, root, "people", lastNamesQuery)
+	assert.Error(t, err)
+	_, err = MaterializeView(ctx, root, "not_select", "delete from people")
+	assert.Equal(t, ErrMaterializedViewNotSelect, err)
+	_, err = MaterializeView(ctx, root, "not_unique", "select last from people")
+	assert.Error(t, err)
+
+	root, ok, err := DropMaterializedView(ctx, root, "last_names")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	has, err := root.HasTable(ctx, "last_names")
+	require.NoError(t, err)
+	assert.False(t, has)
+	frags, err := root.GetSchemaFragments(ctx, doltdb.MaterializedViewFragmentType)
+	require.NoError(t, err)
+	assert.Empty(t, frags)
+
+	_, ok, err = DropMaterializedView(ctx, root, "last_names")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRefreshStagedMaterializedViews(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	root, err = MaterializeView(ctx, root, "last_names", lastNamesQuery)
+	require.NoError(t, err)
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, root))
+	updateWorkingSet(t, dEnv, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+	updateWorkingSet(t, dEnv, "insert into people (id, first, last) values (11, 'Seymour', 'Skinner')")
+
+	// the views are computed from the staged tables, and the working set gets the same results
+	require.NoError(t, RefreshStagedMaterializedViews(ctx, dEnv))
+	query := "select n from last_names where last in ('Flanders', 'Skinner')"
+	staged, err := dEnv.StagedRoot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(1)}}, rootQuery(t, staged, query))
+	working, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{int64(1)}}, rootQuery(t, working, query))
+	assert.Len(t, rootQuery(t, working, "select * from people where id = 11"), 1)
+}
+
+func rootQuery(t *testing.T, root *doltdb.RootValue, query string) []sql.Row {
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, nil, nil))
+	return queryRows(t, engine, query)
+}
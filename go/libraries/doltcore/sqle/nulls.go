@@ -0,0 +1,413 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/src-d/go-mysql-server/sql/expression/function/aggregation"
+	"github.com/src-d/go-mysql-server/sql/plan"
+)
+
+// NullOrderingVar is the session variable that sets where ORDER BY puts NULL values, to one of the NullOrdering values.
+const NullOrderingVar = "dolt_null_ordering"
+
+// NullOrdering is a placement of NULL values by ORDER BY.
+type NullOrdering string
+
+const (
+	// NullsLow sorts NULL values before all other values, so that they come first in ascending order and last in
+	// descending order, as in MySQL. It's the default.
+	NullsLow NullOrdering = "low"
+	// NullsFirst puts NULL values first in both ascending and descending order.
+	NullsFirst NullOrdering = "first"
+	// NullsLast puts NULL values last in both ascending and descending order.
+	NullsLast NullOrdering = "last"
+)
+
+const nullsRuleName = "dolt_nulls"
+
+// nullOrderingFromSession returns the NULL ordering set in the session given.
+func nullOrderingFromSession(sess sql.Session) (NullOrdering, error) {
+	_, val := sess.Get(NullOrderingVar)
+	if val == nil {
+		return NullsLow, nil
+	}
+
+	str, ok := val.(string)
+	switch NullOrdering(strings.ToLower(str)) {
+	case NullsLow:
+		return NullsLow, nil
+	case NullsFirst:
+		return NullsFirst, nil
+	case NullsLast:
+		return NullsLast, nil
+	}
+
+	if !ok {
+		str = fmt.Sprint(val)
+	}
+	return "", fmt.Errorf("invalid value '%s' for %s, expected '%s', '%s' or '%s'", str, NullOrderingVar, NullsLow, NullsFirst, NullsLast)
+}
+
+// applyNullSemantics is an analyzer rule that gives NULL values the three-valued semantics of standard SQL where the
+// engine's own expressions and nodes don't:
+//
+//   - IN and NOT IN are NULL, rather than false and true, when the value is NULL, or when it isn't found and one of the
+//     values it's compared to is NULL.
+//   - Join conditions that are NULL don't match, so NULL keys never join.
+//   - AVG ignores NULL values like the other aggregates do, and is NULL when there are no values to average.
+//   - ORDER BY places NULL values as the session's NullOrderingVar says, and orders rows with NULL values in one sort
+//     field by the fields that follow it.
+func applyNullSemantics(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	ordering, err := nullOrderingFromSession(ctx.Session)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err = plan.TransformUp(n, func(node sql.Node) (sql.Node, error) {
+		switch node := node.(type) {
+		case *plan.Sort:
+			return orderNulls(node, ordering), nil
+		case *plan.InnerJoin, *plan.LeftJoin, *plan.RightJoin:
+			cond := node.(sql.Expressioner).Expressions()[0]
+			if _, ok := cond.(*nullIsFalse); ok {
+				return node, nil
+			}
+			return node.(sql.Expressioner).WithExpressions(&nullIsFalse{expression.UnaryExpression{Child: cond}})
+		default:
+			return node, nil
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.TransformExpressionsUp(n, func(e sql.Expression) (sql.Expression, error) {
+		switch e := e.(type) {
+		case *expression.In:
+			if inRightOperand(e.Right()) {
+				return &nullAwareIn{expression.BinaryExpression{Left: e.Left(), Right: e.Right()}, false}, nil
+			}
+		case *expression.NotIn:
+			if inRightOperand(e.Right()) {
+				return &nullAwareIn{expression.BinaryExpression{Left: e.Left(), Right: e.Right()}, true}, nil
+			}
+		case *aggregation.Avg:
+			return &avgOfValues{expression.UnaryExpression{Child: e.Child}}, nil
+		}
+		return e, nil
+	})
+}
+
+// orderNulls replaces each sort field of the node given with two: the first puts the NULL values in their place, and
+// the second orders the other values as the original field did. The engine's sort stops at the first field that's
+// NULL in either row, so the second field never is.
+func orderNulls(s *plan.Sort, ordering NullOrdering) *plan.Sort {
+	fields := make([]plan.SortField, 0, 2*len(s.SortFields))
+	for _, sf := range s.SortFields {
+		switch sf.Column.(type) {
+		case *nullSortRank, *nullSortValue:
+			return s
+		}
+
+		nullsFirst := ordering == NullsFirst || (ordering == NullsLow && sf.Order == plan.Ascending)
+		fields = append(fields,
+			plan.SortField{Column: &nullSortRank{expression.UnaryExpression{Child: sf.Column}, nullsFirst}, Order: plan.Ascending},
+			plan.SortField{Column: &nullSortValue{expression.UnaryExpression{Child: sf.Column}}, Order: sf.Order, NullOrdering: sf.NullOrdering},
+		)
+	}
+
+	return plan.NewSort(fields, s.Child)
+}
+
+// inRightOperand returns whether the expression given is a right operand of IN that nullAwareIn supports.
+func inRightOperand(e sql.Expression) bool {
+	switch e.(type) {
+	case expression.Tuple, *expression.Subquery:
+		return true
+	default:
+		return false
+	}
+}
+
+// nullAwareIn is IN, or NOT IN if not is true, with the values it's compared to given by a tuple or a subquery.
+type nullAwareIn struct {
+	expression.BinaryExpression
+	not bool
+}
+
+var _ sql.Expression = (*nullAwareIn)(nil)
+
+// Type implements sql.Expression
+func (in *nullAwareIn) Type() sql.Type { return sql.Boolean }
+
+// IsNullable implements sql.Expression
+func (in *nullAwareIn) IsNullable() bool { return true }
+
+// WithChildren implements sql.Expression
+func (in *nullAwareIn) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 2 {
+		return nil, sql.ErrInvalidChildrenNumber.New(in, len(children), 2)
+	}
+	return &nullAwareIn{expression.BinaryExpression{Left: children[0], Right: children[1]}, in.not}, nil
+}
+
+// String implements fmt.Stringer
+func (in *nullAwareIn) String() string {
+	if in.not {
+		return fmt.Sprintf("%s NOT IN %s", in.Left, in.Right)
+	}
+	return fmt.Sprintf("%s IN %s", in.Left, in.Right)
+}
+
+// Eval implements sql.Expression
+func (in *nullAwareIn) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	typ := in.Left.Type()
+	leftElems := sql.NumColumns(typ)
+	left, err := in.Left.Eval(ctx, row)
+	if err != nil || left == nil {
+		return nil, err
+	}
+
+	var values []interface{}
+	switch right := in.Right.(type) {
+	case expression.Tuple:
+		for _, el := range right {
+			if sql.NumColumns(el.Type()) != leftElems {
+				return nil, expression.ErrInvalidOperandColumns.New(leftElems, sql.NumColumns(el.Type()))
+			}
+
+			val, err := el.Eval(ctx, row)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, val)
+		}
+	case *expression.Subquery:
+		if leftElems > 1 {
+			return nil, expression.ErrInvalidOperandColumns.New(leftElems, 1)
+		}
+
+		typ = right.Type()
+		values, err = right.EvalMultiple(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	left, err = typ.Convert(left)
+	if err != nil {
+		return nil, err
+	}
+
+	sawNull := false
+	for _, val := range values {
+		if val == nil {
+			sawNull = true
+			continue
+		}
+
+		val, err = typ.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+
+		cmp, err := typ.Compare(left, val)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp == 0 {
+			return !in.not, nil
+		}
+	}
+
+	if sawNull {
+		return nil, nil
+	}
+
+	return in.not, nil
+}
+
+// nullIsFalse is a join condition that is false where the condition it wraps is NULL.
+type nullIsFalse struct {
+	expression.UnaryExpression
+}
+
+var _ sql.Expression = (*nullIsFalse)(nil)
+
+// Type implements sql.Expression
+func (e *nullIsFalse) Type() sql.Type { return e.Child.Type() }
+
+// IsNullable implements sql.Expression
+func (e *nullIsFalse) IsNullable() bool { return false }
+
+// WithChildren implements sql.Expression
+func (e *nullIsFalse) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(e, len(children), 1)
+	}
+	return &nullIsFalse{expression.UnaryExpression{Child: children[0]}}, nil
+}
+
+// String implements fmt.Stringer
+func (e *nullIsFalse) String() string { return e.Child.String() }
+
+// Eval implements sql.Expression
+func (e *nullIsFalse) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := e.Child.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	} else if val == nil {
+		return false, nil
+	}
+	return val, nil
+}
+
+// avgOfValues is AVG, averaging the values that aren't NULL.
+type avgOfValues struct {
+	expression.UnaryExpression
+}
+
+var _ sql.Aggregation = (*avgOfValues)(nil)
+
+// Type implements sql.Expression
+func (a *avgOfValues) Type() sql.Type { return sql.Float64 }
+
+// IsNullable implements sql.Expression
+func (a *avgOfValues) IsNullable() bool { return true }
+
+// WithChildren implements sql.Expression
+func (a *avgOfValues) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(a, len(children), 1)
+	}
+	return &avgOfValues{expression.UnaryExpression{Child: children[0]}}, nil
+}
+
+// String implements fmt.Stringer
+func (a *avgOfValues) String() string { return fmt.Sprintf("AVG(%s)", a.Child) }
+
+// NewBuffer implements sql.Aggregation
+func (a *avgOfValues) NewBuffer() sql.Row {
+	return sql.NewRow(float64(0), int64(0))
+}
+
+// Update implements sql.Aggregation
+func (a *avgOfValues) Update(ctx *sql.Context, buffer, row sql.Row) error {
+	val, err := a.Child.Eval(ctx, row)
+	if err != nil || val == nil {
+		return err
+	}
+
+	val, err = sql.Float64.Convert(val)
+	if err != nil {
+		val = float64(0)
+	}
+
+	buffer[0] = buffer[0].(float64) + val.(float64)
+	buffer[1] = buffer[1].(int64) + 1
+	return nil
+}
+
+// Merge implements sql.Aggregation
+func (a *avgOfValues) Merge(ctx *sql.Context, buffer, partial sql.Row) error {
+	buffer[0] = buffer[0].(float64) + partial[0].(float64)
+	buffer[1] = buffer[1].(int64) + partial[1].(int64)
+	return nil
+}
+
+// Eval implements sql.Expression
+func (a *avgOfValues) Eval(ctx *sql.Context, buffer sql.Row) (interface{}, error) {
+	if buffer[1].(int64) == 0 {
+		return nil, nil
+	}
+	return buffer[0].(float64) / float64(buffer[1].(int64)), nil
+}
+
+// nullSortRank is the sort field that places the NULL values of a field: it's 0 for the rows that come first and 1 for
+// the others.
+type nullSortRank struct {
+	expression.UnaryExpression
+	nullsFirst bool
+}
+
+// Type implements sql.Expression
+func (r *nullSortRank) Type() sql.Type { return sql.Int8 }
+
+// IsNullable implements sql.Expression
+func (r *nullSortRank) IsNullable() bool { return false }
+
+// WithChildren implements sql.Expression
+func (r *nullSortRank) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(r, len(children), 1)
+	}
+	return &nullSortRank{expression.UnaryExpression{Child: children[0]}, r.nullsFirst}, nil
+}
+
+// String implements fmt.Stringer
+func (r *nullSortRank) String() string { return fmt.Sprintf("ISNULL(%s)", r.Child) }
+
+// Eval implements sql.Expression
+func (r *nullSortRank) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := r.Child.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+
+	if (val == nil) == r.nullsFirst {
+		return int8(0), nil
+	}
+	return int8(1), nil
+}
+
+// nullSortValue is the sort field that orders the values of a field that aren't NULL. NULL values are replaced by the
+// zero value of the field's type, as they're only compared with each other.
+type nullSortValue struct {
+	expression.UnaryExpression
+}
+
+// Type implements sql.Expression
+func (v *nullSortValue) Type() sql.Type { return v.Child.Type() }
+
+// IsNullable implements sql.Expression
+func (v *nullSortValue) IsNullable() bool { return false }
+
+// WithChildren implements sql.Expression
+func (v *nullSortValue) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(v, len(children), 1)
+	}
+	return &nullSortValue{expression.UnaryExpression{Child: children[0]}}, nil
+}
+
+// String implements fmt.Stringer
+func (v *nullSortValue) String() string { return v.Child.String() }
+
+// Eval implements sql.Expression
+func (v *nullSortValue) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := v.Child.Eval(ctx, row)
+	if err != nil || val != nil {
+		return val, err
+	}
+	return v.Child.Type().Zero(), nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestNullSemantics(t *testing.T) {
+	engine := nullsEngine(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []sql.Row
+	}{
+		// filters keep the rows whose condition is true, and not those where it's false or NULL
+		{"equals null", "select pk from t where a = null", nil},
+		{"not equals null", "select pk from t where a <> null", nil},
+		{"is null", "select pk from t where a is null order by pk", []sql.Row{{int64(2)}, {int64(4)}}},
+		{"is not null", "select pk from t where a is not null order by pk", []sql.Row{{int64(1)}, {int64(3)}}},
+		{"not equals", "select pk from t where a <> 1", []sql.Row{{int64(3)}}},
+		{"not of equals", "select pk from t where not (a = 1)", []sql.Row{{int64(3)}}},
+		{"or null", "select pk from t where a = 1 or null", []sql.Row{{int64(1)}}},
+		{"and null", "select pk from t where a = 1 and null", nil},
+		{"like null", "select pk from t where b like null", nil},
+		{"between null", "select pk from t where a between null and 5", nil},
+		{"comparison is null", "select pk from t where (a = 1) is null order by pk", []sql.Row{{int64(2)}, {int64(4)}}},
+
+		// IN is NULL rather than false when the value isn't found and a NULL is involved, and NOT IN likewise
+		{"in with null", "select pk from t where a in (1, null)", []sql.Row{{int64(1)}}},
+		{"in null only", "select pk from t where a in (null)", nil},
+		{"not in", "select pk from t where a not in (3)", []sql.Row{{int64(1)}}},
+		{"not in with null", "select pk from t where a not in (1, null)", nil},
+		{"not of in with null", "select pk from t where not (a in (1, null))", nil},
+		{"in with null is null", "select pk from t where (a in (1, null)) is null order by pk", []sql.Row{{int64(2)}, {int64(3)}, {int64(4)}}},
+		{"in subquery", "select pk from t where a in (select a from u)", []sql.Row{{int64(1)}}},
+		{"not in subquery with null", "select pk from t where a not in (select a from u)", nil},
+		{"not in subquery without null", "select pk from t where a not in (select a from u where a is not null)", []sql.Row{{int64(3)}}},
+
+		// expressions
+		{"operators", "select null = null, null < 1, not null, null + 1, null and false, null or true", []sql.Row{{nil, nil, nil, nil, false, true}}},
+		{"coalesce", "select coalesce(a, -1) from t order by pk", []sql.Row{{int64(1)}, {int8(-1)}, {int64(3)}, {int8(-1)}}},
+		{"concat", "select concat(b, 'z') from t order by pk", []sql.Row{{"xz"}, {"yz"}, {nil}, {nil}}},
+
+		// NULL keys never join
+		{"inner join", "select t.pk, u.pk from t join u on t.a = u.a", []sql.Row{{int64(1), int64(1)}}},
+		{"left join", "select t.pk, u.pk from t left join u on t.a = u.a order by t.pk",
+			[]sql.Row{{int64(1), int64(1)}, {int64(2), nil}, {int64(3), nil}, {int64(4), nil}}},
+		{"right join", "select t.pk, u.pk from t right join u on t.a = u.a order by u.pk",
+			[]sql.Row{{int64(1), int64(1)}, {nil, int64(2)}, {nil, int64(3)}}},
+		{"join on is null", "select t.pk, u.pk from t join u on t.a = u.a or (t.a is null and u.a is null) order by t.pk",
+			[]sql.Row{{int64(1), int64(1)}, {int64(2), int64(2)}, {int64(4), int64(2)}}},
+		{"cross join filter", "select t.pk, u.pk from t, u where t.a = u.a", []sql.Row{{int64(1), int64(1)}}},
+
+		// NULL values form one group
+		{"group by", "select a, count(*) from t group by a order by a", []sql.Row{{nil, int64(2)}, {int64(1), int64(1)}, {int64(3), int64(1)}}},
+		{"group by two columns", "select a, b, count(*) from t group by a, b order by a, b",
+			[]sql.Row{{nil, nil, int64(1)}, {nil, "y", int64(1)}, {int64(1), "x", int64(1)}, {int64(3), nil, int64(1)}}},
+		{"distinct", "select distinct a from t order by a", []sql.Row{{nil}, {int64(1)}, {int64(3)}}},
+
+		// aggregates ignore NULL values, and are NULL when there are no values
+		{"aggregates", "select count(a), count(*), min(a), max(a), avg(a) from t", []sql.Row{{int64(2), int64(4), int64(1), int64(3), float64(2)}}},
+		{"aggregates of nulls", "select count(a), sum(a), min(a), max(a), avg(a) from t where a is null", []sql.Row{{int64(0), nil, nil, nil, nil}}},
+		{"aggregates of nothing", "select count(a), sum(a), avg(a) from t where pk > 100", []sql.Row{{int64(0), nil, nil}}},
+		{"avg by group", "select b, avg(a) from t group by b order by b", []sql.Row{{nil, float64(3)}, {"x", float64(1)}, {"y", nil}}},
+
+		// NULL values are lower than any other, and rows with them are ordered by the following fields
+		{"order by", "select pk from t order by a, pk", []sql.Row{{int64(2)}, {int64(4)}, {int64(1)}, {int64(3)}}},
+		{"order by desc", "select pk from t order by a desc, pk", []sql.Row{{int64(3)}, {int64(1)}, {int64(2)}, {int64(4)}}},
+		{"order by then desc", "select pk from t order by b, pk desc", []sql.Row{{int64(4)}, {int64(3)}, {int64(1)}, {int64(2)}}},
+		{"order by two nullable", "select pk from t order by a, b, pk", []sql.Row{{int64(4)}, {int64(2)}, {int64(1)}, {int64(3)}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, queryRows(t, engine, test.query))
+		})
+	}
+}
+
+func TestNullOrdering(t *testing.T) {
+	engine := nullsEngine(t)
+	sqlCtx := sql.NewContext(context.Background())
+	orderedPks := func(query string) []sql.Row {
+		_, iter, err := engine.Query(sqlCtx, query)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(iter)
+		require.NoError(t, err)
+		return rows
+	}
+
+	tests := []struct {
+		ordering string
+		asc      []sql.Row
+		desc     []sql.Row
+	}{
+		{"low", []sql.Row{{int64(2)}, {int64(4)}, {int64(1)}, {int64(3)}}, []sql.Row{{int64(3)}, {int64(1)}, {int64(2)}, {int64(4)}}},
+		{"FIRST", []sql.Row{{int64(2)}, {int64(4)}, {int64(1)}, {int64(3)}}, []sql.Row{{int64(2)}, {int64(4)}, {int64(3)}, {int64(1)}}},
+		{"last", []sql.Row{{int64(1)}, {int64(3)}, {int64(2)}, {int64(4)}}, []sql.Row{{int64(3)}, {int64(1)}, {int64(2)}, {int64(4)}}},
+	}
+
+	for _, test := range tests {
+		_, err := queryRowCount(sqlCtx, engine.Query, "set @@"+NullOrderingVar+" = '"+test.ordering+"'")
+		require.NoError(t, err)
+		assert.Equal(t, test.asc, orderedPks("select pk from t order by a, pk"), test.ordering)
+		assert.Equal(t, test.desc, orderedPks("select pk from t order by a desc, pk"), test.ordering)
+	}
+
+	_, err := queryRowCount(sqlCtx, engine.Query, "set @@"+NullOrderingVar+" = 'middle'")
+	require.NoError(t, err)
+	_, err = queryRowCount(sqlCtx, engine.Query, "select pk from t order by a")
+	assert.Error(t, err)
+}
+
+// nullsEngine returns an engine for a database whose tables t and u have NULL values in their columns.
+func nullsEngine(t *testing.T) *sqle.Engine {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	for _, query := range []string{
+		"create table t (pk bigint, a bigint, b varchar(10), primary key(pk))",
+		"create table u (pk bigint, a bigint, primary key(pk))",
+		"insert into t values (1, 1, 'x'), (2, null, 'y'), (3, 3, null), (4, null, null)",
+		"insert into u values (1, 1), (2, null), (3, 5)",
+	} {
+		root, err = executeModify(ctx, root, query)
+		require.NoError(t, err, query)
+	}
+
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(NewDatabase("dolt", root, nil, nil))
+	return engine
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/plan"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+const rejectWritesRuleName = "dolt_reject_writes"
+
+// ErrReadOnly is returned for statements that would write to a database opened at a commit.
+var ErrReadOnly = errors.New("the database is read-only, as it was opened at a commit rather than the working set")
+
+// rejectWrites is an analyzer rule that fails statements that would write to the current database when it's read-only.
+func rejectWrites(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	sqlDb, err := a.Catalog.Database(a.Catalog.CurrentDatabase())
+	if err != nil {
+		return n, nil
+	}
+
+	if db, ok := sqlDb.(*Database); !ok || !db.ReadOnly() {
+		return n, nil
+	}
+
+	switch n.(type) {
+	case *plan.InsertInto, *plan.Update, *plan.DeleteFrom, *plan.CreateTable, *plan.DropTable, *plan.CreateIndex,
+		*plan.DropIndex, *plan.CreateView, *plan.DropView:
+		return nil, ErrReadOnly
+	}
+
+	return n, nil
+}
+
+// IsWriteStatement returns whether the statement given writes to a database, for callers that execute some statements
+// without the engine and so must reject writes to read-only databases themselves.
+func IsWriteStatement(stmt sqlparser.Statement) bool {
+	switch stmt.(type) {
+	case *sqlparser.Insert, *sqlparser.Update, *sqlparser.Delete, *sqlparser.DDL, *sqlparser.DBDDL:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+func TestCommitDatabase(t *testing.T) {
+	ctx := context.Background()
+	dEnv := branchFunctionsEnv(t)
+	updateWorkingSet(t, dEnv, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+
+	db, err := NewCommitDatabase(ctx, "dolt", dEnv.DoltDB, dEnv.RepoState, "HEAD")
+	require.NoError(t, err)
+	assert.True(t, db.ReadOnly())
+
+	engine := NewEngine(CaseSensitive)
+	engine.AddDatabase(db)
+
+	// the commit's tables are read rather than the working set's, and the commit is reported as the head
+	people := len(queryRows(t, engine, "select * from people"))
+	assert.Empty(t, queryRows(t, engine, "select * from people where id = 10"))
+	assert.Equal(t, []sql.Row{{headHash(t, dEnv, "master")}}, queryRows(t, engine, "select @@dolt_head"))
+
+	for _, query := range []string{
+		"insert into people (id, first, last) values (11, 'Maude', 'Flanders')",
+		"update people set first = 'Homie' where id = 0",
+		"delete from people where id = 0",
+		"create table t (id int primary key)",
+		"drop table people",
+		"create view v as select * from people",
+	} {
+		_, err := queryRowCount(sql.NewEmptyContext(), engine.Query, query)
+		assert.Equal(t, ErrReadOnly, err, query)
+	}
+
+	assert.Len(t, queryRows(t, engine, "select * from people"), people)
+
+	_, err = NewCommitDatabase(ctx, "dolt", dEnv.DoltDB, dEnv.RepoState, "no_such_branch")
+	assert.Error(t, err)
+	assert.False(t, NewDatabase("dolt", db.Root(), dEnv.DoltDB, dEnv.RepoState).ReadOnly())
+}
+
+func TestIsWriteStatement(t *testing.T) {
+	tests := map[string]bool{
+		"select * from people":                false,
+		"show tables":                         false,
+		"set @@dolt_head = 'HEAD'":            false,
+		"insert into people (id) values (1)":  true,
+		"replace into people (id) values (1)": true,
+		"update people set first = 'x'":       true,
+		"delete from people":                  true,
+		"create table t (id int primary key)": true,
+		"alter table people add column x int": true,
+		"drop view v":                         true,
+		"truncate table people":               true,
+		"explain select * from people":        false,
+	}
+
+	for query, expected := range tests {
+		stmt, err := sqlparser.Parse(query)
+		require.NoError(t, err, query)
+		assert.Equal(t, expected, IsWriteStatement(stmt), query)
+	}
+}
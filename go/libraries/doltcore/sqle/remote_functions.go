@@ -0,0 +1,298 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/src-d/go-mysql-server/auth"
+	"github.com/src-d/go-mysql-server/sql"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+	"github.com/liquidata-inc/dolt/go/store/datas"
+)
+
+// RemoteFunctions returns the functions that synchronize the branches of the environment given with its remotes,
+// which the database given must serve. The remote defaults to the default remote, as it does for the dolt commands:
+//
+//	DOLT_FETCH([remote])          fetches the branches of the remote into its remote tracking branches, like dolt
+//	                              fetch does. Returns the number of remote tracking branches that changed.
+//	DOLT_PULL([remote])           fetches the remote's branch with the name of the branch checked out and merges it
+//	                              into the branch checked out, like dolt pull does, making the merged working root the
+//	                              root of the database. There must be no uncommitted writes. Returns 1 if the merge
+//	                              has conflicts, which must be resolved before the merge is committed, and 0 if it
+//	                              doesn't.
+//	DOLT_PUSH([remote[, branch]]) pushes the head commit of the branch, or of the branch checked out if it's not given,
+//	                              to the remote's branch with the same name, like dolt push does. The remote's branch
+//	                              must be an ancestor of the commit. Returns the hash of the commit.
+//
+// If a is not nil, only users with write permission may call them.
+func RemoteFunctions(dEnv *env.DoltEnv, db *Database, a auth.Auth) []sql.Function {
+	return []sql.Function{
+		sql.FunctionN{Name: "dolt_fetch", Fn: func(args ...sql.Expression) (sql.Expression, error) {
+			if len(args) > 1 {
+				return nil, sql.ErrInvalidArgumentNumber.New("DOLT_FETCH", "0 or 1", len(args))
+			}
+			return &BranchFunc{"DOLT_FETCH", sql.Int64, dEnv, db, a, args, fetchRemote}, nil
+		}},
+		sql.FunctionN{Name: "dolt_pull", Fn: func(args ...sql.Expression) (sql.Expression, error) {
+			if len(args) > 1 {
+				return nil, sql.ErrInvalidArgumentNumber.New("DOLT_PULL", "0 or 1", len(args))
+			}
+			return &BranchFunc{"DOLT_PULL", sql.Int64, dEnv, db, a, args, pullRemote}, nil
+		}},
+		sql.FunctionN{Name: "dolt_push", Fn: func(args ...sql.Expression) (sql.Expression, error) {
+			if len(args) > 2 {
+				return nil, sql.ErrInvalidArgumentNumber.New("DOLT_PUSH", "0 to 2", len(args))
+			}
+			return &BranchFunc{"DOLT_PUSH", sql.Text, dEnv, db, a, args, pushBranch}, nil
+		}},
+	}
+}
+
+func fetchRemote(ctx *sql.Context, dEnv *env.DoltEnv, _ *Database, args []string) (interface{}, error) {
+	remote, refSpecs, err := remoteRefSpecs(dEnv, args)
+	if err != nil {
+		return nil, err
+	}
+
+	srcDB, err := remote.GetRemoteDB(ctx, dEnv.DoltDB.ValueReadWriter().Format())
+	if err != nil {
+		return nil, err
+	}
+
+	branchRefs, err := srcDB.GetRefs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed int64
+	for _, rs := range refSpecs {
+		for _, branchRef := range branchRefs {
+			if trackRef := rs.DestRef(branchRef); trackRef != nil {
+				updated, err := fetchBranch(ctx, dEnv, remote, srcDB, branchRef, trackRef)
+				if err != nil {
+					return nil, err
+				} else if updated {
+					changed++
+				}
+			}
+		}
+	}
+
+	return changed, nil
+}
+
+func pullRemote(ctx *sql.Context, dEnv *env.DoltEnv, db *Database, args []string) (interface{}, error) {
+	remote, refSpecs, err := remoteRefSpecs(dEnv, args)
+	if err != nil {
+		return nil, err
+	}
+
+	branch := dEnv.RepoState.Head.Ref
+	var trackRef ref.DoltRef
+	for _, rs := range refSpecs {
+		if trackRef = rs.DestRef(branch); trackRef != nil {
+			break
+		}
+	}
+
+	if trackRef == nil {
+		return nil, fmt.Errorf("remote '%s' doesn't track a branch named '%s'", remote.Name, branch.GetPath())
+	}
+
+	srcDB, err := remote.GetRemoteDB(ctx, dEnv.DoltDB.ValueReadWriter().Format())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fetchBranch(ctx, dEnv, remote, srcDB, branch, trackRef); err != nil {
+		return nil, err
+	}
+
+	var hasConflicts bool
+	err = changeWorking(ctx, dEnv, db, func() error {
+		var err error
+		hasConflicts, err = mergeRefIntoHead(ctx, dEnv, trackRef)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return boolToInt64(hasConflicts), nil
+}
+
+func pushBranch(ctx *sql.Context, dEnv *env.DoltEnv, _ *Database, args []string) (interface{}, error) {
+	remote, refSpecs, err := remoteRefSpecs(dEnv, args)
+	if err != nil {
+		return nil, err
+	}
+
+	branch := dEnv.RepoState.Head.Ref.(ref.BranchRef)
+	if len(args) > 1 {
+		branch = ref.NewBranchRef(args[1])
+	}
+
+	if hasRef, err := dEnv.DoltDB.HasRef(ctx, branch); err != nil {
+		return nil, err
+	} else if !hasRef {
+		return nil, fmt.Errorf("unknown branch: %s", branch.GetPath())
+	}
+
+	var trackRef ref.DoltRef
+	for _, rs := range refSpecs {
+		if trackRef = rs.DestRef(branch); trackRef != nil {
+			break
+		}
+	}
+
+	if trackRef == nil {
+		return nil, fmt.Errorf("remote '%s' doesn't track a branch named '%s'", remote.Name, branch.GetPath())
+	}
+
+	cs, err := doltdb.NewCommitSpec("HEAD", branch.String())
+	if err != nil {
+		return nil, err
+	}
+
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+	if err != nil {
+		return nil, err
+	}
+
+	destDB, err := remote.GetRemoteDB(ctx, dEnv.DoltDB.ValueReadWriter().Format())
+	if err != nil {
+		return nil, err
+	}
+
+	err = discardProgress(func(progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
+		return actions.Push(ctx, dEnv, branch, trackRef.(ref.RemoteRef), dEnv.DoltDB, destDB, cm, progChan, pullerEventCh)
+	})
+
+	if err == doltdb.ErrIsAhead || err == actions.ErrCantFF || err == datas.ErrMergeNeeded {
+		return nil, fmt.Errorf("the push of '%s' to '%s' was rejected because the remote branch has commits the local branch doesn't; pull them before pushing again", branch.GetPath(), remote.Name)
+	} else if err != nil && err != doltdb.ErrUpToDate {
+		return nil, err
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	return h.String(), nil
+}
+
+// remoteRefSpecs returns the remote named by the first of the arguments given, or the default remote if there are no
+// arguments, along with the refspecs it's fetched with.
+func remoteRefSpecs(dEnv *env.DoltEnv, args []string) (env.Remote, []ref.RemoteRefSpec, error) {
+	remoteName := ""
+	if len(args) > 0 {
+		remoteName = args[0]
+		if _, ok := dEnv.RepoState.Remotes[remoteName]; !ok {
+			return env.NoRemote, nil, fmt.Errorf("unknown remote '%s'", remoteName)
+		}
+	} else {
+		remote, verr := dEnv.GetDefaultRemote()
+		if verr == env.ErrNoRemote {
+			return env.NoRemote, nil, fmt.Errorf("the repository has no remotes")
+		} else if verr != nil {
+			return env.NoRemote, nil, fmt.Errorf("unable to determine the default remote, so one must be named")
+		}
+		remoteName = remote.Name
+	}
+
+	refSpecs, verr := dEnv.GetRefSpecs(remoteName)
+	if verr != nil {
+		return env.NoRemote, nil, verr
+	}
+
+	return dEnv.RepoState.Remotes[remoteName], refSpecs, nil
+}
+
+// fetchBranch fetches the head commit of the branch of the remote database given into the remote tracking branch
+// given, and returns whether the tracking branch changed.
+func fetchBranch(ctx context.Context, dEnv *env.DoltEnv, remote env.Remote, srcDB *doltdb.DoltDB, srcRef, trackRef ref.DoltRef) (bool, error) {
+	cs, err := doltdb.NewCommitSpec("HEAD", srcRef.String())
+	if err != nil {
+		return false, err
+	}
+
+	cm, err := srcDB.Resolve(ctx, cs)
+	if err != nil {
+		return false, fmt.Errorf("unable to find '%s' on '%s'", srcRef.GetPath(), remote.Name)
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return false, err
+	}
+
+	cs, err = doltdb.NewCommitSpec("HEAD", trackRef.String())
+	if err != nil {
+		return false, err
+	}
+
+	if current, err := dEnv.DoltDB.Resolve(ctx, cs); err == nil {
+		currentHash, err := current.HashOf()
+		if err != nil {
+			return false, err
+		} else if currentHash == h {
+			return false, nil
+		}
+	} else if err != doltdb.ErrBranchNotFound {
+		return false, err
+	}
+
+	err = discardProgress(func(progChan chan datas.PullProgress, pullerEventCh chan datas.PullerEvent) error {
+		return actions.Fetch(ctx, dEnv, trackRef, srcDB, dEnv.DoltDB, cm, progChan, pullerEventCh)
+	})
+
+	return err == nil, err
+}
+
+// discardProgress calls the function given with progress channels whose events are read and dropped, for transfers
+// that report no progress.
+func discardProgress(transfer func(chan datas.PullProgress, chan datas.PullerEvent) error) error {
+	progChan := make(chan datas.PullProgress)
+	pullerEventCh := make(chan datas.PullerEvent)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range progChan {
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range pullerEventCh {
+		}
+	}()
+
+	err := transfer(progChan, pullerEventCh)
+	close(progChan)
+	close(pullerEventCh)
+	wg.Wait()
+
+	return err
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/ref"
+)
+
+func TestRemoteFunctions(t *testing.T) {
+	ctx := context.Background()
+	dEnv := branchFunctionsEnv(t)
+	dir, err := ioutil.TempDir("", "remote_functions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dEnv.RepoState.AddRemote(env.NewRemote("origin", "file://"+dir, nil))
+
+	engine := remotesEngine(t, dEnv)
+	head := headHash(t, dEnv, "master")
+	assert.Equal(t, []sql.Row{{head}}, queryRows(t, engine, "select dolt_push()"))
+	assert.Equal(t, head, headHash(t, dEnv, "refs/remotes/origin/master"))
+	assert.Equal(t, []sql.Row{{head}}, queryRows(t, engine, "select dolt_push('origin', 'master')"))
+
+	updateWorkingSet(t, dEnv, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	commitWorkingSet(t, dEnv, "added Ned")
+	pushed := headHash(t, dEnv, "master")
+	assert.Equal(t, []sql.Row{{pushed}}, queryRows(t, engine, "select dolt_push('origin')"))
+
+	// forget the push, so that the remote is ahead
+	resetHead(t, dEnv, head)
+	require.NoError(t, dEnv.DoltDB.DeleteBranch(ctx, ref.NewRemoteRef("origin", "master")))
+
+	engine = remotesEngine(t, dEnv)
+	assert.Equal(t, []sql.Row{{int64(1)}}, queryRows(t, engine, "select dolt_fetch()"))
+	assert.Equal(t, []sql.Row{{int64(0)}}, queryRows(t, engine, "select dolt_fetch('origin')"))
+	assert.Equal(t, pushed, headHash(t, dEnv, "refs/remotes/origin/master"))
+	assert.Equal(t, head, headHash(t, dEnv, "master"))
+
+	assert.Equal(t, []sql.Row{{int64(0)}}, queryRows(t, engine, "select dolt_pull()"))
+	assert.Equal(t, pushed, headHash(t, dEnv, "master"))
+	assert.Equal(t, []sql.Row{{"Flanders"}}, queryRows(t, engine, "select last from people where id = 10"))
+
+	// a push that isn't a fast-forward of the remote's branch is rejected
+	resetHead(t, dEnv, head)
+	updateWorkingSet(t, dEnv, "insert into people (id, first, last) values (11, 'Maude', 'Flanders')")
+	commitWorkingSet(t, dEnv, "added Maude")
+	engine = remotesEngine(t, dEnv)
+	for _, query := range []string{
+		"select dolt_push()",
+		"select dolt_push('no_such_remote')",
+		"select dolt_push('origin', 'no_such_branch')",
+		"select dolt_fetch('no_such_remote')",
+	} {
+		_, err := queryRowCount(sql.NewEmptyContext(), engine.Query, query)
+		assert.Error(t, err, query)
+	}
+	assert.Equal(t, pushed, headHash(t, dEnv, "refs/remotes/origin/master"))
+}
+
+func remotesEngine(t *testing.T, dEnv *env.DoltEnv) *sqle.Engine {
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+	engine, db := viewsEngine(dEnv, root)
+	engine.Catalog.MustRegister(RemoteFunctions(dEnv, db, nil)...)
+	return engine
+}
+
+func commitWorkingSet(t *testing.T, dEnv *env.DoltEnv, msg string) {
+	ctx := context.Background()
+	require.NoError(t, actions.StageAllTables(ctx, dEnv, false))
+	require.NoError(t, actions.CommitStaged(ctx, dEnv, msg, time.Now(), false))
+}
+
+// resetHead points master at the commit given, and resets the working and staged roots to its root.
+func resetHead(t *testing.T, dEnv *env.DoltEnv, commit string) {
+	ctx := context.Background()
+	cs, err := doltdb.NewCommitSpec(commit, "master")
+	require.NoError(t, err)
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs)
+	require.NoError(t, err)
+	require.NoError(t, dEnv.DoltDB.SetRef(ctx, ref.NewBranchRef("master"), cm))
+
+	root, err := cm.GetRootValue()
+	require.NoError(t, err)
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, root))
+	_, err = dEnv.UpdateStagedRoot(ctx, root)
+	require.NoError(t, err)
+}
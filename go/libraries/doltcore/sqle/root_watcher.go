@@ -0,0 +1,315 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+const refreshRootsRuleName = "dolt_refresh_roots"
+
+// RootWatcher broadcasts the latest working root of a dolt environment to the databases watching it, so that each
+// query run against them starts from the latest root rather than the one the database was created with.
+//
+// Databases publish the root after each statement that writes, which makes the write visible to the queries of every
+// other database watching the same environment. A statement's writes are merged with the writes published since it
+// started, rather than replacing them. Changes made outside of the process, such as by dolt commands run
+// while a server is up, are found by polling the repository state and the manifest of the environment, with Poll or
+// StartPolling. Such a change replaces the root of the watching databases, including any writes they hold in memory
+// that haven't been written to the working set.
+//
+// The writes of sessions with the Serializable isolation level are validated against the version of the root their
+// statement started from: if the root changed in between, the statement fails with ErrSerializationFailure rather than
+// replacing the change.
+type RootWatcher struct {
+	dEnv *env.DoltEnv
+
+	mu      sync.Mutex
+	root    *doltdb.RootValue
+	rootH   hash.Hash
+	version uint64    // Incremented whenever the root changes
+	working hash.Hash // The working root hash last read from, or written to, the repository state
+
+	snapshots map[uint32]uint64 // The version each Serializable session's current statement started from, by session id
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewRootWatcher returns a watcher of the working root of the environment given, starting with its current working
+// root.
+func NewRootWatcher(ctx context.Context, dEnv *env.DoltEnv) (*RootWatcher, error) {
+	root, err := dEnv.WorkingRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := root.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	return &RootWatcher{
+		dEnv:      dEnv,
+		root:      root,
+		rootH:     h,
+		working:   dEnv.RepoState.WorkingHash(),
+		snapshots: make(map[uint32]uint64),
+	}, nil
+}
+
+// Watch makes the database given follow the roots broadcast by the watcher, starting with the next query. The database
+// must serve the watcher's environment.
+func (w *RootWatcher) Watch(db *Database) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	db.watcher = w
+	db.setRoot(w.root, w.version)
+}
+
+// Publish broadcasts the root given as the latest root to the databases watching.
+func (w *RootWatcher) Publish(root *doltdb.RootValue) error {
+	h, err := root.HashOf()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.publish(root, h)
+	return nil
+}
+
+// publish sets the latest root, if it has changed. The caller must hold w.mu.
+func (w *RootWatcher) publish(root *doltdb.RootValue, h hash.Hash) {
+	if h == w.rootH {
+		return
+	}
+
+	w.root = root
+	w.rootH = h
+	w.version++
+}
+
+// written is called by a watching database after a statement writes to it, and broadcasts the database's root. If
+// write isn't nil, it's called first to write the root to the working set, which is then not mistaken for an external
+// change when polling. If the statement was run by a Serializable session and the root has changed since it started,
+// the database's root is replaced by the latest one, discarding the statement's writes, and ErrSerializationFailure is
+// returned.
+func (w *RootWatcher) written(ctx context.Context, db *Database, write func() error) error {
+	root := db.Root()
+	h, err := root.HashOf()
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sqlCtx, isSQL := ctx.(*sql.Context)
+	if isSQL {
+		if start, ok := w.snapshots[sqlCtx.ID()]; ok && start != w.version {
+			db.setRoot(w.root, w.version)
+			return ErrSerializationFailure
+		}
+	}
+
+	if write != nil {
+		if err := write(); err != nil {
+			return err
+		}
+		w.working = w.dEnv.RepoState.WorkingHash()
+	}
+
+	w.publish(root, h)
+	db.setRootVersion(w.version)
+
+	if isSQL {
+		if _, ok := w.snapshots[sqlCtx.ID()]; ok {
+			w.snapshots[sqlCtx.ID()] = w.version
+		}
+	}
+
+	return nil
+}
+
+// changeWorking is called by a watching database to change the working set of the environment with the function given,
+// such as by checking out or merging a branch. The new working root is broadcast, and becomes the root of the database.
+func (w *RootWatcher) changeWorking(ctx context.Context, db *Database, change func() error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	root, err := w.dEnv.WorkingRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.working = w.dEnv.RepoState.WorkingHash()
+	w.publish(root, w.working)
+	db.setRoot(w.root, w.version)
+	return nil
+}
+
+// started records the version of the root that the statement being run by the session given started from, which its
+// writes are validated against if the session is Serializable.
+func (w *RootWatcher) started(sess sql.Session, version uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if isolationLevel(sess) == Serializable {
+		w.snapshots[sess.ID()] = version
+	} else {
+		delete(w.snapshots, sess.ID())
+	}
+}
+
+// EndSession forgets the session with the id given, which must be called when a session that ran statements against
+// the watching databases closes.
+func (w *RootWatcher) EndSession(id uint32) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.snapshots, id)
+}
+
+// latest returns the latest root and its version, and whether it's newer than the version given.
+func (w *RootWatcher) latest(version uint64) (*doltdb.RootValue, uint64, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.root, w.version, w.version != version
+}
+
+// Served returns the latest root and a copy of the repository state of the watcher's environment, which tells the
+// branch the root belongs to.
+func (w *RootWatcher) Served() (*doltdb.RootValue, env.RepoState) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.root, *w.dEnv.RepoState
+}
+
+// Poll brings the environment's view of its database up to date with its storage and, if the working root in its
+// repository state has changed since it was last read or written through the watcher, reloads the repository state
+// and broadcasts the new working root.
+func (w *RootWatcher) Poll(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.dEnv.DoltDB.Rebase(ctx); err != nil {
+		return err
+	}
+
+	rs, err := env.LoadRepoState(w.dEnv.FS)
+	if err != nil {
+		return err
+	}
+
+	if rs.WorkingHash() == w.working {
+		return nil
+	}
+
+	root, err := w.dEnv.DoltDB.ReadRootValue(ctx, rs.WorkingHash())
+	if err != nil {
+		return err
+	}
+
+	*w.dEnv.RepoState = *rs
+	w.working = rs.WorkingHash()
+	w.publish(root, rs.WorkingHash())
+	return nil
+}
+
+// StartPolling polls the environment for changes once every interval given, until the watcher is closed.
+func (w *RootWatcher) StartPolling(interval time.Duration) {
+	w.stop = make(chan struct{})
+	w.stopped = make(chan struct{})
+
+	go func() {
+		defer close(w.stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				if err := w.Poll(context.Background()); err != nil {
+					logrus.Errorf("failed to check the working set for changes: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Close stops polling, if the watcher was polling.
+func (w *RootWatcher) Close() {
+	if w.stop != nil {
+		close(w.stop)
+		<-w.stopped
+		w.stop = nil
+	}
+}
+
+// refreshRoots is an analyzer rule that sets the root of each database that watches a RootWatcher to the latest one,
+// before the query's tables are resolved, and records it as the snapshot the query's writes are validated against.
+// The query then gets its own root of each database, starting from the database's root. Queries run as part of a larger
+// statement (see asStatementPart) keep using the statement's root.
+func refreshRoots(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	if isStatementPart(ctx) {
+		return n, nil
+	}
+
+	for _, sqlDb := range a.Catalog.AllDatabases() {
+		if db, ok := sqlDb.(*Database); ok {
+			db.beginStatement(ctx)
+		}
+	}
+
+	return n, nil
+}
+
+// beginStatement refreshes the database's root from its watcher, if it has one, and gives the statement of the context
+// given its own root, starting from the database's root.
+func (db *Database) beginStatement(ctx *sql.Context) {
+	if db.watcher != nil {
+		// A statement's writes that are being merged into the root mustn't be replaced before they're published
+		db.writeMu.Lock()
+		version := db.refreshRoot()
+		db.writeMu.Unlock()
+
+		db.watcher.started(ctx.Session, version)
+	}
+
+	db.startStatement(ctx.ID())
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestRootWatcher(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+
+	watcher, err := NewRootWatcher(ctx, dEnv)
+	require.NoError(t, err)
+
+	// two databases for the same working set, as if served by two servers in the same process
+	engine1, db1 := watchedEngine(t, dEnv, watcher)
+	engine2, db2 := watchedEngine(t, dEnv, watcher)
+	committer := NewCommitter(dEnv, db1, CommitConfig{Policy: CommitPerTransaction})
+	defer committer.Close(ctx)
+
+	people := countPeople(t, engine2)
+
+	_, err = queryRowCount(sql.NewEmptyContext(), engine1.Query, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	require.NoError(t, err)
+	assert.Equal(t, people+1, countPeople(t, engine2))
+	assert.Equal(t, db1.Root(), db2.Root())
+
+	_, err = queryRowCount(sql.NewEmptyContext(), engine2.Query, "delete from people where id = 10")
+	require.NoError(t, err)
+	assert.Equal(t, people, countPeople(t, engine1))
+
+	// the committer's own writes aren't mistaken for external changes
+	version := watcher.version
+	require.NoError(t, watcher.Poll(ctx))
+	assert.Equal(t, version, watcher.version)
+
+	// a change to the working set made by another process is only seen once polled for, and replaces the writes that
+	// weren't written to the working set, such as db2's delete
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	root, err = ExecuteSql(dEnv, root, "insert into people (id, first, last) values (11, 'Maude', 'Flanders')")
+	require.NoError(t, err)
+	h, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	rs := *dEnv.RepoState
+	rs.Working = h.String()
+	require.NoError(t, rs.Save(dEnv.FS))
+
+	assert.Equal(t, people, countPeople(t, engine1))
+	require.NoError(t, watcher.Poll(ctx))
+	assert.Equal(t, people+2, countPeople(t, engine1))
+	assert.Equal(t, people+2, countPeople(t, engine2))
+	assert.Equal(t, h, dEnv.RepoState.WorkingHash())
+}
+
+func TestRootWatcherPolling(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+
+	watcher, err := NewRootWatcher(ctx, dEnv)
+	require.NoError(t, err)
+	engine, _ := watchedEngine(t, dEnv, watcher)
+	watcher.StartPolling(10 * time.Millisecond)
+	defer watcher.Close()
+
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	people := countPeople(t, engine)
+	root, err = ExecuteSql(dEnv, root, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	require.NoError(t, err)
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, root))
+
+	assert.Eventually(t, func() bool {
+		return countPeople(t, engine) == people+1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func watchedEngine(t *testing.T, dEnv *env.DoltEnv, watcher *RootWatcher) (*sqle.Engine, *Database) {
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	engine.AddDatabase(db)
+	watcher.Watch(db)
+
+	return engine, db
+}
+
+func countPeople(t *testing.T, engine *sqle.Engine) int {
+	_, iter, err := engine.Query(sql.NewEmptyContext(), "select * from people")
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(iter)
+	require.NoError(t, err)
+	return len(rows)
+}
@@ -0,0 +1,350 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/src-d/go-mysql-server/sql/parse"
+	"github.com/src-d/go-mysql-server/sql/plan"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+)
+
+const rowFiltersRuleName = "dolt_row_filters"
+
+var ErrRowFilteredSystemTableFmt = "table %s is not available because the rows of table %s are filtered"
+var ErrInvalidRowFilterFmt = "invalid row filter for table %s: %s"
+
+// rowFilteredSystemTablePrefixes are the prefixes of the system tables that can't be read for a filtered table, since
+// they would expose rows that don't satisfy its filter.
+var rowFilteredSystemTablePrefixes = []string{DoltDiffTablePrefix, DoltHistoryTablePrefix, DoltBlameTablePrefix, DoltConflictsTablePrefix}
+
+// RowFilters restrict the rows of tables that can be read in a session. Each table named, matched case insensitively,
+// maps to a boolean SQL expression over the table's columns, such as "region = 'EU'", and only the rows for which the
+// expression is true can be read. Filtered tables can't be modified, and their diff, history, blame and conflicts tables
+// can't be read, nor can the tables of materialized views computed from them. Tables without a filter are not
+// restricted.
+type RowFilters map[string]string
+
+// Validate returns an error if any of the filters isn't a valid SQL expression.
+func (f RowFilters) Validate() error {
+	ctx := sql.NewEmptyContext()
+	for tableName, filter := range f {
+		if _, err := parseRowFilter(ctx, tableName, filter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rowFilterSession is a session whose queries are subject to row filters. The filters are held by the session rather
+// than in a session variable so that they can't be changed with SET.
+type rowFilterSession struct {
+	sql.Session
+	filters RowFilters
+}
+
+// NewRowFilterSession returns a session that wraps the one given and restricts the rows its queries can read with the
+// filters given. The filters should be validated first.
+func NewRowFilterSession(sess sql.Session, filters RowFilters) sql.Session {
+	lwrFilters := make(RowFilters, len(filters))
+	for tableName, filter := range filters {
+		lwrFilters[strings.ToLower(tableName)] = filter
+	}
+
+	return &rowFilterSession{Session: sess, filters: lwrFilters}
+}
+
+// applyRowFilters is an analyzer rule that enforces the row filters of the session. Each filtered table read by the
+// query is replaced with one that only returns the rows that satisfy its filter, and queries reading the system tables
+// of a filtered table or the table of a materialized view computed from one are rejected. Tables read in subqueries, including those of views, are filtered
+// the same way.
+func applyRowFilters(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	filters := sessionRowFilters(ctx.Session)
+	if len(filters) == 0 {
+		return n, nil
+	}
+
+	return filterTables(ctx, a, n, filters)
+}
+
+// sessionRowFilters returns the row filters of the session given, if it was created by NewRowFilterSession or wraps a
+// session that was.
+func sessionRowFilters(sess sql.Session) RowFilters {
+	for {
+		switch s := sess.(type) {
+		case *rowFilterSession:
+			return s.filters
+		case *queryLimitSession:
+			sess = s.Session
+		default:
+			return nil
+		}
+	}
+}
+
+func filterTables(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, filters RowFilters) (sql.Node, error) {
+	n, err := plan.TransformUp(n, func(node sql.Node) (sql.Node, error) {
+		rt, ok := node.(*plan.ResolvedTable)
+		if !ok {
+			return node, nil
+		}
+
+		if _, ok := rt.Table.(*rowFilteredTable); ok {
+			return node, nil
+		}
+
+		lwrName := strings.ToLower(rt.Name())
+		if asOf, ok := rt.Table.(*AsOfTable); ok {
+			lwrName = strings.ToLower(asOf.TableName())
+		}
+
+		if src, ok := rowFilteredSource(lwrName, filters); ok && src != lwrName {
+			return nil, fmt.Errorf(ErrRowFilteredSystemTableFmt, rt.Name(), src)
+		}
+
+		filter, ok := filters[lwrName]
+		if !ok {
+			if dt, ok := doltTableOf(rt.Table); ok {
+				src, ok, err := filteredViewSource(ctx, dt.db.rootFor(ctx), lwrName, filters)
+				if err != nil {
+					return nil, err
+				} else if ok {
+					return nil, fmt.Errorf(ErrRowFilteredSystemTableFmt, rt.Name(), src)
+				}
+			}
+
+			return node, nil
+		}
+
+		cond, err := resolveRowFilter(ctx, a.Catalog, rt.Table, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		return plan.NewResolvedTable(&rowFilteredTable{Table: rt.Table, filter: cond}), nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.TransformExpressionsUp(n, func(e sql.Expression) (sql.Expression, error) {
+		if s, ok := e.(*expression.Subquery); ok {
+			query, err := filterTables(ctx, a, s.Query, filters)
+			if err != nil {
+				return nil, err
+			}
+			return s.WithQuery(query), nil
+		}
+		return e, nil
+	})
+}
+
+// rowFilteredSource returns the name of the filtered table whose rows the table named, in lower case, exposes: either
+// the table itself, or the table whose system table it is. Returns false if the table doesn't expose filtered rows.
+func rowFilteredSource(lwrName string, filters RowFilters) (string, bool) {
+	if i := strings.Index(lwrName, AsOfSeparator); i > 0 {
+		lwrName = lwrName[:i]
+	}
+
+	for _, prefix := range rowFilteredSystemTablePrefixes {
+		if strings.HasPrefix(lwrName, prefix) {
+			lwrName = lwrName[len(prefix):]
+			break
+		}
+	}
+
+	_, ok := filters[lwrName]
+	return lwrName, ok
+}
+
+// doltTableOf returns the DoltTable that the table given reads, if any.
+func doltTableOf(tbl sql.Table) (*DoltTable, bool) {
+	switch tbl := tbl.(type) {
+	case *DoltTable:
+		return tbl, true
+	case *AsOfTable:
+		return tbl.table, true
+	default:
+		return nil, false
+	}
+}
+
+// filteredViewSource returns the name of a filtered table that the materialized view named, in lower case, is computed
+// from, including through the views and other materialized views its query reads. Returns false if the table named
+// isn't a materialized view or none of the tables it's computed from are filtered.
+func filteredViewSource(ctx *sql.Context, root *doltdb.RootValue, lwrName string, filters RowFilters) (string, bool, error) {
+	queries := make(map[string]string)
+	for _, fragType := range []string{doltdb.MaterializedViewFragmentType, doltdb.ViewFragmentType} {
+		frags, err := root.GetSchemaFragments(ctx, fragType)
+		if err != nil {
+			return "", false, err
+		}
+
+		for _, frag := range frags {
+			queries[strings.ToLower(frag.Name)] = frag.Fragment
+		}
+	}
+
+	if _, ok := queries[lwrName]; !ok {
+		return "", false, nil
+	}
+
+	visited := make(map[string]bool)
+	pending := []string{lwrName}
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+
+		query, ok := queries[name]
+		if !ok {
+			continue
+		}
+
+		tableNames, err := queryTableNames(ctx, query)
+		if err != nil {
+			return "", false, err
+		}
+
+		for _, tableName := range tableNames {
+			lwrTableName := strings.ToLower(tableName)
+			if src, ok := rowFilteredSource(lwrTableName, filters); ok {
+				return src, true, nil
+			}
+			pending = append(pending, lwrTableName)
+		}
+	}
+
+	return "", false, nil
+}
+
+// queryTableNames returns the names of the tables read by the query given, including those read in subqueries.
+func queryTableNames(ctx *sql.Context, query string) ([]string, error) {
+	node, err := parse.Parse(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	var inspect func(n sql.Node)
+	inspect = func(n sql.Node) {
+		plan.Inspect(n, func(n sql.Node) bool {
+			if t, ok := n.(*plan.UnresolvedTable); ok {
+				names = append(names, t.Name())
+			}
+			return true
+		})
+		plan.InspectExpressions(n, func(e sql.Expression) bool {
+			if s, ok := e.(*expression.Subquery); ok {
+				inspect(s.Query)
+			}
+			return true
+		})
+	}
+	inspect(node)
+
+	return names, nil
+}
+
+// parseRowFilter parses the filter given for the table named, returning the unresolved expression.
+func parseRowFilter(ctx *sql.Context, tableName, filter string) (sql.Expression, error) {
+	node, err := parse.Parse(ctx, "SELECT * FROM t WHERE "+filter)
+	if err != nil {
+		return nil, fmt.Errorf(ErrInvalidRowFilterFmt, tableName, err.Error())
+	}
+
+	var cond sql.Expression
+	plan.Inspect(node, func(n sql.Node) bool {
+		if f, ok := n.(*plan.Filter); ok {
+			cond = f.Expression
+			return false
+		}
+		return true
+	})
+
+	if cond == nil {
+		return nil, fmt.Errorf(ErrInvalidRowFilterFmt, tableName, filter)
+	}
+
+	return cond, nil
+}
+
+// resolveRowFilter returns the filter given as an expression evaluated against the rows of the table given. The
+// filter can refer to the table's columns and use functions and literal values, but not subqueries.
+func resolveRowFilter(ctx *sql.Context, catalog *sql.Catalog, tbl sql.Table, filter string) (sql.Expression, error) {
+	cond, err := parseRowFilter(ctx, tbl.Name(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sch := tbl.Schema()
+	cond, err = expression.TransformUp(cond, func(e sql.Expression) (sql.Expression, error) {
+		switch e := e.(type) {
+		case *expression.UnresolvedColumn:
+			for i, col := range sch {
+				if strings.EqualFold(col.Name, e.Name()) {
+					return expression.NewGetFieldWithTable(i, col.Type, tbl.Name(), col.Name, col.Nullable), nil
+				}
+			}
+			return nil, fmt.Errorf(ErrInvalidRowFilterFmt, tbl.Name(), "unknown column "+e.Name())
+		case *expression.UnresolvedFunction:
+			fn, err := catalog.Function(e.Name())
+			if err != nil {
+				return nil, fmt.Errorf(ErrInvalidRowFilterFmt, tbl.Name(), err.Error())
+			}
+			return fn.Call(e.Arguments...)
+		default:
+			return e, nil
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !cond.Resolved() {
+		return nil, fmt.Errorf(ErrInvalidRowFilterFmt, tbl.Name(), filter)
+	}
+
+	return cond, nil
+}
+
+// rowFilteredTable is a table that only returns the rows of the table it wraps that satisfy its filter. It doesn't
+// support inserts, updates or deletes.
+type rowFilteredTable struct {
+	sql.Table
+	filter sql.Expression
+}
+
+// PartitionRows implements sql.Table.
+func (t *rowFilteredTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	iter, err := t.Table.PartitionRows(ctx, part)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.NewFilterIter(ctx, t.filter, iter), nil
+}
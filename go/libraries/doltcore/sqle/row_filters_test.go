@@ -0,0 +1,229 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestRowFilters(t *testing.T) {
+	simpsons := RowFilters{"People": "last = 'Simpson'"}
+
+	tests := []struct {
+		name        string
+		filters     RowFilters
+		query       string
+		expectedErr string
+		rows        int
+	}{
+		{
+			name:  "no filters",
+			query: "select * from people",
+			rows:  6,
+		},
+		{
+			name:    "filtered table",
+			filters: simpsons,
+			query:   "select * from PEOPLE",
+			rows:    4,
+		},
+		{
+			name:    "filtered table with where clause",
+			filters: simpsons,
+			query:   "select * from people where age > 30",
+			rows:    2,
+		},
+		{
+			name:    "filtered table with index lookup",
+			filters: simpsons,
+			query:   "select * from people where id = 4",
+			rows:    0,
+		},
+		{
+			name:    "unfiltered table",
+			filters: simpsons,
+			query:   "select * from appearances",
+			rows:    10,
+		},
+		{
+			name:    "join",
+			filters: simpsons,
+			query:   "select * from people p join appearances a on p.id = a.character_id",
+			rows:    8,
+		},
+		{
+			name:    "subquery",
+			filters: simpsons,
+			query:   "select * from (select * from people) p",
+			rows:    4,
+		},
+		{
+			name:    "expression subquery",
+			filters: simpsons,
+			query:   "select * from appearances where character_id in (select id from people where age > 30)",
+			rows:    5,
+		},
+		{
+			name:    "filter with function",
+			filters: RowFilters{"people": "lower(first) like 'b%' or age > 45"},
+			query:   "select * from people",
+			rows:    3,
+		},
+		{
+			name:        "filter with unknown column",
+			filters:     RowFilters{"people": "region = 'EU'"},
+			query:       "select * from people",
+			expectedErr: "invalid row filter for table people: unknown column region",
+		},
+		{
+			name:        "history table of filtered table",
+			filters:     simpsons,
+			query:       "select * from dolt_history_people",
+			expectedErr: fmt.Sprintf(ErrRowFilteredSystemTableFmt, "dolt_history_people", "people"),
+		},
+		{
+			name:        "diff table of filtered table",
+			filters:     simpsons,
+			query:       "select * from DOLT_DIFF_people",
+			expectedErr: fmt.Sprintf(ErrRowFilteredSystemTableFmt, "dolt_diff_people", "people"),
+		},
+		{
+			name:        "conflicts table of filtered table",
+			filters:     simpsons,
+			query:       "select * from dolt_conflicts_people",
+			expectedErr: fmt.Sprintf(ErrRowFilteredSystemTableFmt, "dolt_conflicts_people", "people"),
+		},
+		{
+			name:    "history table of unfiltered table",
+			filters: simpsons,
+			query:   "select * from dolt_history_episodes",
+			rows:    0,
+		},
+		{
+			name:        "delete from filtered table",
+			filters:     simpsons,
+			query:       "delete from people",
+			expectedErr: "table doesn't support DELETE FROM",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			root, err := dEnv.WorkingRoot(context.Background())
+			require.NoError(t, err)
+
+			engine := NewEngine(CaseSensitive)
+			engine.AddDatabase(NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+			sqlCtx := sql.NewContext(context.Background(), sql.WithSession(NewRowFilterSession(sql.NewBaseSession(), test.filters)))
+
+			rows, err := queryRowCount(sqlCtx, engine.Query, test.query)
+			if len(test.expectedErr) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.rows, rows)
+		})
+	}
+}
+
+func TestRowFiltersMaterializedViews(t *testing.T) {
+	simpsons := RowFilters{"people": "last = 'Simpson'"}
+
+	tests := []struct {
+		name        string
+		query       string
+		expectedErr string
+		rows        int
+	}{
+		{
+			name:        "view of filtered table",
+			query:       "select * from last_names",
+			expectedErr: fmt.Sprintf(ErrRowFilteredSystemTableFmt, "last_names", "people"),
+		},
+		{
+			name:        "view of view of filtered table",
+			query:       "select * from LAST_NAME_COUNTS",
+			expectedErr: fmt.Sprintf(ErrRowFilteredSystemTableFmt, "last_name_counts", "people"),
+		},
+		{
+			name:        "view reading filtered table in subquery",
+			query:       "select * from cast_episodes",
+			expectedErr: fmt.Sprintf(ErrRowFilteredSystemTableFmt, "cast_episodes", "people"),
+		},
+		{
+			name:  "view of unfiltered table",
+			query: "select * from episode_names",
+			rows:  4,
+		},
+	}
+
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	views := []struct {
+		name  string
+		query string
+	}{
+		{"last_names", lastNamesQuery},
+		{"last_name_counts", "select n, count(*) as c from last_names group by n"},
+		{"cast_episodes", "select id, name from episodes where id in (select episode_id from appearances where character_id in (select id from people))"},
+		{"episode_names", "select id, name from episodes"},
+	}
+	for _, view := range views {
+		root, err = MaterializeView(ctx, root, view.name, view.query)
+		require.NoError(t, err)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine := NewEngine(CaseSensitive)
+			engine.AddDatabase(NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState))
+			sqlCtx := sql.NewContext(ctx, sql.WithSession(NewRowFilterSession(sql.NewBaseSession(), simpsons)))
+
+			rows, err := queryRowCount(sqlCtx, engine.Query, test.query)
+			if len(test.expectedErr) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.rows, rows)
+		})
+	}
+}
+
+func TestRowFiltersValidate(t *testing.T) {
+	assert.NoError(t, RowFilters{"people": "last = 'Simpson' and age > 10"}.Validate())
+	assert.Error(t, RowFilters{"people": "last = "}.Validate())
+	assert.Error(t, RowFilters{"people": "1 = 1; drop table people"}.Validate())
+}
@@ -15,14 +15,14 @@
 package sqle
 
 import (
-	"fmt"
 	"io"
 
 	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/plan"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
-	sqlTypes "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle/types"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -52,8 +52,15 @@ func newRowIterator(tbl *DoltTable, ctx *sql.Context) (*doltTableRowIter, error)
 	return &doltTableRowIter{table: tbl, rowData: rowData, ctx: ctx, nomsIter: mapIter}, nil
 }
 
-// Next returns the next row in this row iterator, or an io.EOF error if there aren't any more.
+// Next returns the next row in this row iterator, or an io.EOF error if there aren't any more. If the context of the
+// iterator has been canceled, the context's error is returned instead.
 func (itr *doltTableRowIter) Next() (sql.Row, error) {
+	select {
+	case <-itr.ctx.Done():
+		return nil, itr.ctx.Err()
+	default:
+	}
+
 	key, val, err := itr.nomsIter.Next(itr.ctx.Context)
 
 	if err != nil {
@@ -78,6 +85,69 @@ func (itr *doltTableRowIter) Close() error {
 	return nil
 }
 
+// subtreeRowIter iterates over the rows of a list of subtrees of a table's row data, such as the subtrees of one of its
+// partitions, in primary key order.
+type subtreeRowIter struct {
+	table    *DoltTable
+	ctx      *sql.Context
+	hashes   []hash.Hash
+	nomsIter types.MapIterator
+}
+
+// Next returns the next row in this row iterator, or an io.EOF error if there aren't any more.
+func (itr *subtreeRowIter) Next() (sql.Row, error) {
+	for {
+		select {
+		case <-itr.ctx.Done():
+			return nil, itr.ctx.Err()
+		default:
+		}
+
+		if itr.nomsIter == nil {
+			if len(itr.hashes) == 0 {
+				return nil, io.EOF
+			}
+
+			subtree, err := itr.table.table.GetRowDataSubtree(itr.ctx, itr.hashes[0])
+
+			if err != nil {
+				return nil, err
+			}
+
+			itr.hashes = itr.hashes[1:]
+			itr.nomsIter, err = subtree.Iterator(itr.ctx)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		key, val, err := itr.nomsIter.Next(itr.ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if key == nil && val == nil {
+			itr.nomsIter = nil
+			continue
+		}
+
+		doltRow, err := row.FromNoms(itr.table.sch, key.(types.Tuple), val.(types.Tuple))
+
+		if err != nil {
+			return nil, err
+		}
+
+		return doltRowToSqlRow(doltRow, itr.table.sch)
+	}
+}
+
+// Close required by sql.RowIter interface
+func (itr *subtreeRowIter) Close() error {
+	return nil
+}
+
 // Returns a SQL row representation for the dolt row given.
 func doltRowToSqlRow(doltRow row.Row, sch schema.Schema) (sql.Row, error) {
 	colVals := make(sql.Row, sch.GetAllCols().Size())
@@ -86,7 +156,7 @@ func doltRowToSqlRow(doltRow row.Row, sch schema.Schema) (sql.Row, error) {
 	err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		var innerErr error
 		value, _ := doltRow.GetColVal(tag)
-		colVals[i], innerErr = sqlTypes.NomsValToSqlVal(value)
+		colVals[i], innerErr = colValToSqlVal(col, value)
 		if innerErr != nil {
 			return true, innerErr
 		}
@@ -101,7 +171,8 @@ func doltRowToSqlRow(doltRow row.Row, sch schema.Schema) (sql.Row, error) {
 	return sql.NewRow(colVals...), nil
 }
 
-// Returns a Dolt row representation for SQL row given
+// Returns a Dolt row representation for SQL row given. Null values are left out of the row. Rows written to a table
+// must be checked with checkNotNull first.
 func SqlRowToDoltRow(nbf *types.NomsBinFormat, r sql.Row, doltSchema schema.Schema) (row.Row, error) {
 	taggedVals := make(row.TaggedValues)
 	allCols := doltSchema.GetAllCols()
@@ -110,13 +181,24 @@ func SqlRowToDoltRow(nbf *types.NomsBinFormat, r sql.Row, doltSchema schema.Sche
 		schCol := allCols.TagToCol[tag]
 		if val != nil {
 			var err error
-			taggedVals[tag], err = sqlTypes.SqlValToNomsVal(val, schCol.Kind)
+			taggedVals[tag], err = sqlValToColVal(schCol, val)
 			if err != nil {
 				return nil, err
 			}
-		} else if !schCol.IsNullable() {
-			return nil, fmt.Errorf("column <%v> received nil but is non-nullable", schCol.Name)
 		}
 	}
 	return row.New(nbf, doltSchema, taggedVals)
 }
+
+// checkNotNull returns an error naming the first column of the schema given with a NOT NULL constraint that is null
+// in the SQL row given. Columns missing from the end of the row are null.
+func checkNotNull(r sql.Row, doltSchema schema.Schema) error {
+	allCols := doltSchema.GetAllCols()
+	for i, tag := range allCols.Tags {
+		col := allCols.TagToCol[tag]
+		if (i >= len(r) || r[i] == nil) && !col.IsNullable() {
+			return plan.ErrInsertIntoNonNullableProvidedNull.New(col.Name)
+		}
+	}
+	return nil
+}
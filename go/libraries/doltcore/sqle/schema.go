@@ -22,6 +22,7 @@ import (
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle/types"
+	dtypes "github.com/liquidata-inc/dolt/go/store/types"
 )
 
 // doltSchemaToSqlSchema returns the sql.Schema corresponding to the dolt schema given.
@@ -98,7 +99,7 @@ func SqlSchemaToDoltSchema(sqlSchema sql.Schema) (schema.Schema, error) {
 
 // doltColToSqlCol returns the SQL column corresponding to the dolt column given.
 func doltColToSqlCol(tableName string, col schema.Column) (*sql.Column, error) {
-	colType, err := types.NomsKindToSqlType(col.Kind)
+	colType, err := colSqlType(col)
 	if err != nil {
 		return nil, err
 	}
@@ -108,6 +109,7 @@ func doltColToSqlCol(tableName string, col schema.Column) (*sql.Column, error) {
 		Default:  nil,
 		Nullable: col.IsNullable(),
 		Source:   tableName,
+		Comment:  col.Comment,
 	}, nil
 }
 
@@ -117,31 +119,55 @@ func SqlColToDoltCol(tag uint64, col *sql.Column) (schema.Column, error) {
 	if !col.Nullable {
 		constraints = append(constraints, schema.NotNullConstraint{})
 	}
-	kind, err := types.SqlTypeToNomsKind(col.Type)
-	if err != nil {
-		return schema.Column{}, err
+	var kind dtypes.NomsKind
+	var typeName string
+	if ct, ok := col.Type.(customSqlType); ok {
+		kind, typeName = ct.ct.Kind, ct.ct.Name
+	} else {
+		var err error
+		kind, err = types.SqlTypeToNomsKind(col.Type)
+		if err != nil {
+			return schema.Column{}, err
+		}
 	}
 
-	return schema.NewColumn(col.Name, tag, kind, col.PrimaryKey, constraints...), nil
+	doltCol := schema.NewColumn(col.Name, tag, kind, col.PrimaryKey, constraints...)
+	doltCol.Comment = extractComment(col)
+	doltCol.TypeName = typeName
+	return doltCol, nil
 }
 
 const tagCommentPrefix = "tag:"
 
 // Extracts the optional comment tag from a column type defn, or InvalidTag if it can't be extracted
 func extractTag(col *sql.Column) uint64 {
+	_, tag := splitComment(col)
+	return tag
+}
+
+// Extracts the comment documenting a column from a column type defn, which is the text of its comment preceding the
+// tag, if it has one.
+func extractComment(col *sql.Column) string {
+	comment, _ := splitComment(col)
+	return comment
+}
+
+// splitComment splits the comment of a column into the text documenting the column and the column's tag, which is
+// InvalidTag if the comment doesn't end with one.
+func splitComment(col *sql.Column) (string, uint64) {
 	if len(col.Comment) == 0 {
-		return schema.InvalidTag
+		return "", schema.InvalidTag
 	}
 
-	i := strings.Index(col.Comment, tagCommentPrefix)
+	i := strings.LastIndex(col.Comment, tagCommentPrefix)
 	if i >= 0 {
 		startIdx := i + len(tagCommentPrefix)
 		tag, err := strconv.ParseUint(col.Comment[startIdx:], 10, 64)
 		if err != nil {
-			return schema.InvalidTag
+			return col.Comment, schema.InvalidTag
 		}
-		return tag
+		return strings.TrimSpace(col.Comment[:i]), tag
 	}
 
-	return schema.InvalidTag
+	return col.Comment, schema.InvalidTag
 }
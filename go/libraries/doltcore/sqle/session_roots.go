@@ -0,0 +1,320 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/src-d/go-mysql-server/sql/plan"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+const (
+	// DoltHeadVar is the session variable holding the hash of the head commit of the current branch. Setting it to a
+	// commit, given as a hash, a branch name or HEAD followed by ancestor references such as ~3, pins the session to the
+	// root of that commit.
+	DoltHeadVar = "dolt_head"
+	// DoltWorkingVar is the session variable holding the hash of the working root the session's queries read. Setting
+	// it to the hash of a root pins the session to that root, which must be the one it last reported, such as by
+	// SET @@dolt_working = @@dolt_working, or one written to the database, such as the root of a commit.
+	DoltWorkingVar = "dolt_working"
+)
+
+const sessionRootsRuleName = "dolt_session_roots"
+
+// dualTableName is the name of the table the parser gives queries without a FROM clause
+const dualTableName = "dual"
+
+// sessionRoots is the state of the dolt_head and dolt_working variables of a session.
+type sessionRoots struct {
+	// pinned is the root the session is pinned to, or nil if the session reads the database's root, and pinnedH is
+	// its hash
+	pinned  *doltdb.RootValue
+	pinnedH string
+	// reported is the root reported by dolt_working in the session's last statement
+	reported *doltdb.RootValue
+	// head is the head commit hash reported to the session while it's pinned
+	head string
+	// setHead and setWorking are true if the session set dolt_head or dolt_working in its last statement
+	setHead    bool
+	setWorking bool
+}
+
+// pinnedRoot returns the root with the hash given if a session is pinned to it. The tables read by pinned sessions are
+// rewritten to be read as of the hash of the root they're pinned to.
+func (db *Database) pinnedRoot(h string) (*doltdb.RootValue, bool) {
+	db.sessionsMu.Lock()
+	defer db.sessionsMu.Unlock()
+
+	for _, sr := range db.sessions {
+		if sr.pinned != nil && sr.pinnedH == h {
+			return sr.pinned, true
+		}
+	}
+
+	return nil, false
+}
+
+// EndSession forgets the dolt_head and dolt_working state and the statement root of the session with the id given,
+// which must be called when a session that ran statements against the database closes.
+func (db *Database) EndSession(id uint32) {
+	db.endStatements(id)
+
+	db.sessionsMu.Lock()
+	defer db.sessionsMu.Unlock()
+
+	delete(db.sessions, id)
+}
+
+// setSessionRoots is an analyzer rule that sets the dolt_head and dolt_working variables of the session running the
+// query to the hashes of the head commit of the current database's branch, or of the commit it was opened at, and of
+// the root the query reads, before the query's variables are resolved. If the session set either variable in its
+// previous statement, it's first pinned to the commit or root given, or unpinned if the value is empty. The tables read
+// by a pinned session are rewritten to be read as of the root it's pinned to, which makes them read-only, and its
+// variables report that root until it's unpinned. System tables such as dolt_log aren't affected.
+func setSessionRoots(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	sqlDb, err := a.Catalog.Database(a.Catalog.CurrentDatabase())
+	if err != nil {
+		return n, nil
+	}
+
+	db, ok := sqlDb.(*Database)
+	if !ok || db.ddb == nil || db.rs == nil {
+		return n, nil
+	}
+
+	db.sessionsMu.Lock()
+	defer db.sessionsMu.Unlock()
+
+	id := ctx.Session.ID()
+	sr, ok := db.sessions[id]
+	if !ok {
+		sr = &sessionRoots{}
+		db.sessions[id] = sr
+	}
+
+	if sr.setWorking {
+		err = sr.pinWorking(ctx, db.ddb, sessionVar(ctx, DoltWorkingVar), sessionVar(ctx, DoltHeadVar), sr.reported, db.Root())
+	} else if sr.setHead {
+		err = sr.pinHead(ctx, db.ddb, db.rs.Head.Ref.String(), sessionVar(ctx, DoltHeadVar))
+	}
+	sr.setHead, sr.setWorking = false, false
+	if err != nil {
+		return nil, err
+	}
+
+	head, root := sr.head, sr.pinned
+	if root == nil {
+		head, root = db.atCommit, db.Root()
+		if head == "" {
+			head, err = branchHeadHash(ctx, db.ddb, db.rs.Head.Ref.String())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	working, err := root.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Set(DoltHeadVar, sql.Text, head)
+	ctx.Set(DoltWorkingVar, sql.Text, working.String())
+	sr.reported = root
+
+	if set, ok := n.(*plan.Set); ok {
+		return n, sr.recordSet(ctx, db, set)
+	} else if sr.pinned == nil {
+		return n, nil
+	}
+
+	return plan.TransformUp(n, func(node sql.Node) (sql.Node, error) {
+		ut, ok := node.(*plan.UnresolvedTable)
+		if !ok || (ut.Database != "" && !strings.EqualFold(ut.Database, db.name)) || strings.EqualFold(ut.Name(), dualTableName) ||
+			isSystemTable(ut.Name()) || strings.Contains(ut.Name(), AsOfSeparator) {
+			return node, nil
+		}
+
+		return plan.NewUnresolvedTable(ut.Name()+AsOfSeparator+sr.pinnedH, ut.Database), nil
+	})
+}
+
+// isSystemTable returns whether the table name given is one of the system tables which are generated from the history
+// of the database rather than read from its root.
+func isSystemTable(tblName string) bool {
+	lwrName := strings.ToLower(tblName)
+	if lwrName == LogTableName || lwrName == ConflictsTableName {
+		return true
+	}
+
+	for _, prefix := range []string{DoltDiffTablePrefix, DoltHistoryTablePrefix, DoltBlameTablePrefix, DoltConflictsTablePrefix} {
+		if strings.HasPrefix(lwrName, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordSet records which of the dolt_head and dolt_working variables the statement given sets, so that the session is
+// pinned when its next statement starts. Values given as literals are checked now, so that a bad value fails the SET.
+func (sr *sessionRoots) recordSet(ctx *sql.Context, db *Database, set *plan.Set) error {
+	for _, v := range set.Variables {
+		name := strings.ToLower(strings.TrimPrefix(strings.TrimLeft(v.Name, "@"), sqlparser.SessionStr+"."))
+		if name != DoltHeadVar && name != DoltWorkingVar {
+			continue
+		}
+
+		if lit, ok := v.Value.(*expression.Literal); ok {
+			val, _ := lit.Value().(string)
+
+			var check sessionRoots
+			var err error
+			if name == DoltHeadVar {
+				err = check.pinHead(ctx, db.ddb, db.rs.Head.Ref.String(), val)
+			} else {
+				err = check.pinWorking(ctx, db.ddb, val, "", sr.reported, db.Root())
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if name == DoltHeadVar {
+			sr.setHead = true
+		} else {
+			sr.setWorking = true
+		}
+	}
+
+	return nil
+}
+
+// pinHead pins the session to the root of the commit given by the spec, in which HEAD refers to the branch given, or
+// unpins it if the spec is empty.
+func (sr *sessionRoots) pinHead(ctx context.Context, ddb *doltdb.DoltDB, branch, spec string) error {
+	if spec == "" {
+		sr.pinned, sr.pinnedH, sr.head = nil, "", ""
+		return nil
+	}
+
+	cs, err := doltdb.NewCommitSpec(spec, branch)
+	if err != nil {
+		return err
+	}
+
+	cm, err := ddb.Resolve(ctx, cs)
+	if err != nil {
+		return fmt.Errorf("unable to resolve '%s': %v", spec, err)
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return err
+	}
+
+	root, err := cm.GetRootValue()
+	if err != nil {
+		return err
+	}
+
+	rootH, err := root.HashOf()
+	if err != nil {
+		return err
+	}
+
+	sr.pinned, sr.pinnedH, sr.head = root, rootH.String(), h.String()
+	return nil
+}
+
+// pinWorking pins the session to the root with the hash given, or unpins it if the hash is empty. The root must have
+// been written to the database, unless it's one of the roots given, which may be nil. The head reported while pinned is
+// the head given, unless the session was already pinned.
+func (sr *sessionRoots) pinWorking(ctx context.Context, ddb *doltdb.DoltDB, str, head string, roots ...*doltdb.RootValue) error {
+	if str == "" {
+		sr.pinned, sr.pinnedH, sr.head = nil, "", ""
+		return nil
+	}
+
+	h, ok := hash.MaybeParse(str)
+	if !ok {
+		return fmt.Errorf("'%s' is not a valid root hash", str)
+	}
+
+	var root *doltdb.RootValue
+	for _, r := range roots {
+		if r == nil {
+			continue
+		}
+
+		rh, err := r.HashOf()
+		if err != nil {
+			return err
+		} else if rh == h {
+			root = r
+			break
+		}
+	}
+
+	if root == nil {
+		var err error
+		root, err = ddb.ReadRootValue(ctx, h)
+		if err != nil {
+			return fmt.Errorf("unable to read root '%s': %v", str, err)
+		}
+	}
+
+	if sr.pinned == nil {
+		sr.head = head
+	}
+	sr.pinned, sr.pinnedH = root, h.String()
+	return nil
+}
+
+// sessionVar returns the value of the string session variable given, or an empty string if it's unset or isn't a string.
+func sessionVar(ctx *sql.Context, name string) string {
+	_, val := ctx.Get(name)
+	str, _ := val.(string)
+	return str
+}
+
+// branchHeadHash returns the hash of the head commit of the branch given.
+func branchHeadHash(ctx context.Context, ddb *doltdb.DoltDB, branch string) (string, error) {
+	cs, err := doltdb.NewCommitSpec("HEAD", branch)
+	if err != nil {
+		return "", err
+	}
+
+	cm, err := ddb.Resolve(ctx, cs)
+	if err != nil {
+		return "", err
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return "", err
+	}
+
+	return h.String(), nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionRoots(t *testing.T) {
+	dEnv := branchFunctionsEnv(t)
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	engine.AddDatabase(db)
+
+	sess1 := sql.NewSession("", "", "", 1)
+	sess2 := sql.NewSession("", "", "", 2)
+	people := len(sessionQuery(t, engine, sess1, "select * from people"))
+
+	h, err := root.HashOf()
+	require.NoError(t, err)
+	head := headHash(t, dEnv, "master")
+	rows := sessionQuery(t, engine, sess1, "select @@dolt_head, @@dolt_working")
+	assert.Equal(t, []sql.Row{{head, h.String()}}, rows)
+
+	// a session pinned to a commit doesn't see the writes made since, and can't write itself
+	sessionQuery(t, engine, sess1, "set @@dolt_head = 'HEAD'")
+	sessionQuery(t, engine, sess2, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	assert.Len(t, sessionQuery(t, engine, sess1, "select * from people"), people)
+	assert.Len(t, sessionQuery(t, engine, sess2, "select * from people"), people+1)
+	_, err = queryRowCount(sql.NewContext(context.Background(), sql.WithSession(sess1)), engine.Query, "insert into people (id, first, last) values (11, 'Maude', 'Flanders')")
+	assert.Error(t, err)
+
+	rows = sessionQuery(t, engine, sess2, "select @@dolt_working")
+	working := rows[0][0].(string)
+	assert.NotEqual(t, h.String(), working)
+	rows = sessionQuery(t, engine, sess1, "select @@dolt_head, @@dolt_working")
+	assert.Equal(t, []sql.Row{{head, h.String()}}, rows)
+
+	// unpinning reads the latest root again, and pinning to the current working root gives repeatable reads
+	sessionQuery(t, engine, sess1, "set @@dolt_head = ''")
+	assert.Len(t, sessionQuery(t, engine, sess1, "select * from people"), people+1)
+	sessionQuery(t, engine, sess1, "set @@dolt_working = @@dolt_working")
+	sessionQuery(t, engine, sess2, "delete from people where id = 10")
+	assert.Len(t, sessionQuery(t, engine, sess1, "select * from people"), people+1)
+	assert.Len(t, sessionQuery(t, engine, sess2, "select * from people"), people)
+	rows = sessionQuery(t, engine, sess1, "select @@dolt_working")
+	assert.Equal(t, []sql.Row{{working}}, rows)
+
+	_, err = queryRowCount(sql.NewContext(context.Background(), sql.WithSession(sess1)), engine.Query, "set @@dolt_head = 'no_such_branch'")
+	assert.Error(t, err)
+	_, err = queryRowCount(sql.NewContext(context.Background(), sql.WithSession(sess1)), engine.Query, "set @@dolt_working = 'not a hash'")
+	assert.Error(t, err)
+
+	db.EndSession(sess1.ID())
+	assert.Len(t, sessionQuery(t, engine, sess1, "select * from people"), people)
+}
+
+func sessionQuery(t *testing.T, engine *sqle.Engine, sess sql.Session, query string) []sql.Row {
+	_, iter, err := engine.Query(sql.NewContext(context.Background(), sql.WithSession(sess), sql.WithQuery(query)), query)
+	require.NoError(t, err, query)
+	rows, err := sql.RowIterToRows(iter)
+	require.NoError(t, err, query)
+	return rows
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/plan"
+
+	dsql "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql"
+)
+
+const showCreateTableRuleName = "dolt_show_create_table"
+
+// showCreateTable is an analyzer rule that has SHOW CREATE TABLE statements for dolt tables give the same CREATE TABLE
+// statement that dolt exports, which includes the tags and comments of the columns and the comment of the table. The
+// engine's statement only has the names and types of the columns.
+func showCreateTable(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	sct, ok := n.(*plan.ShowCreateTable)
+	if !ok {
+		return n, nil
+	}
+
+	db, err := a.Catalog.Database(sct.CurrentDatabase)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, ok, err := db.GetTableInsensitive(ctx, sct.Table)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return n, nil
+	}
+
+	dt, ok := tbl.(*DoltTable)
+	if !ok {
+		return n, nil
+	}
+
+	return &showCreateDoltTable{sct, dt}, nil
+}
+
+// showCreateDoltTable is a SHOW CREATE TABLE node for a dolt table.
+type showCreateDoltTable struct {
+	*plan.ShowCreateTable
+	table *DoltTable
+}
+
+// WithChildren implements the Node interface.
+func (n *showCreateDoltTable) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 0)
+	}
+	return n, nil
+}
+
+// RowIter implements the Node interface.
+func (n *showCreateDoltTable) RowIter(*sql.Context) (sql.RowIter, error) {
+	stmt := dsql.SchemaAsCreateStmt(n.table.Name(), n.table.sch)
+	return sql.RowsToRowIter(sql.NewRow(n.Table, stmt)), nil
+}
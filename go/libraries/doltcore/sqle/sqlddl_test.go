@@ -18,8 +18,10 @@ import (
 	"context"
 	"testing"
 
+	"github.com/src-d/go-mysql-server/sql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"vitess.io/vitess/go/vt/sqlparser"
 
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
@@ -196,8 +198,16 @@ func TestCreateTable(t *testing.T) {
 			query:         "create table testTable (id int primary key comment 'tag:a', age int comment 'this is my personal area')",
 			expectedTable: "testTable",
 			expectedSchema: dtestutils.CreateSchema(
-				schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}),
-				schema.NewColumn("age", 1, types.IntKind, false)),
+				withComment(schema.NewColumn("id", 0, types.IntKind, true, schema.NotNullConstraint{}), "tag:a"),
+				withComment(schema.NewColumn("age", 1, types.IntKind, false), "this is my personal area")),
+		},
+		{
+			name:          "Test comments",
+			query:         "create table testTable (id int primary key comment 'the id tag:5', age int comment 'years tag:6') comment='people and ages'",
+			expectedTable: "testTable",
+			expectedSchema: schema.SchemaWithComment(dtestutils.CreateSchema(
+				withComment(schema.NewColumn("id", 5, types.IntKind, true, schema.NotNullConstraint{}), "the id"),
+				withComment(schema.NewColumn("age", 6, types.IntKind, false), "years")), "people and ages"),
 		},
 		// Real world examples for regression testing
 		{
@@ -266,6 +276,11 @@ func TestCreateTable(t *testing.T) {
 	}
 }
 
+func withComment(col schema.Column, comment string) schema.Column {
+	col.Comment = comment
+	return col
+}
+
 func TestDropTable(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -308,6 +323,26 @@ func TestDropTable(t *testing.T) {
 			query:      "drop table if exists people, not_real, appearances, episodes",
 			tableNames: []string{"people", "appearances", "not_real", "episodes"},
 		},
+		{
+			name:        "drop many tables, one doesn't exist",
+			query:       "drop table people, not_real, appearances",
+			expectedErr: "table not found: not_real",
+		},
+		{
+			name:       "drop same table twice",
+			query:      "drop table people, PEOPLE",
+			tableNames: []string{"people"},
+		},
+		{
+			name:        "drop system table",
+			query:       "drop table dolt_log",
+			expectedErr: "table dolt_log is a system table and cannot be dropped",
+		},
+		{
+			name:        "drop system table if exists",
+			query:       "drop table if exists people, dolt_diff_episodes",
+			expectedErr: "table dolt_diff_episodes is a system table and cannot be dropped",
+		},
 	}
 
 	for _, tt := range tests {
@@ -336,3 +371,96 @@ func TestDropTable(t *testing.T) {
 		})
 	}
 }
+
+func TestDropTableDropsAllOrNone(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	ctx := context.Background()
+	root, _ := dEnv.WorkingRoot(ctx)
+
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	sqlCtx := sql.NewContext(ctx)
+
+	for _, query := range []string{"drop table people, not_real, appearances", "drop table if exists people, dolt_log"} {
+		stmt, err := sqlparser.Parse(query)
+		require.NoError(t, err)
+
+		err = ExecuteDropTable(sqlCtx, db, stmt.(*sqlparser.DDL))
+		require.Error(t, err)
+
+		for _, tableName := range []string{"people", "appearances"} {
+			has, err := db.Root().HasTable(ctx, tableName)
+			require.NoError(t, err)
+			assert.True(t, has, "%s dropped by '%s'", tableName, query)
+		}
+	}
+}
+
+func TestTruncateTable(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		tableName   string
+		expectedErr string
+	}{
+		{
+			name:      "truncate table",
+			query:     "truncate table people",
+			tableName: "people",
+		},
+		{
+			name:      "truncate without table keyword",
+			query:     "truncate episodes",
+			tableName: "episodes",
+		},
+		{
+			name:      "truncate table case insensitive",
+			query:     "truncate table PEOPLE",
+			tableName: "people",
+		},
+		{
+			name:        "truncate non existent",
+			query:       "truncate table notfound",
+			expectedErr: "table not found: notfound",
+		},
+		{
+			name:        "truncate system table",
+			query:       "truncate table dolt_log",
+			expectedErr: "table dolt_log is a system table and cannot be truncated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			ctx := context.Background()
+			root, _ := dEnv.WorkingRoot(ctx)
+
+			updatedRoot, err := ExecuteSql(dEnv, root, tt.query)
+
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+
+			require.NotNil(t, updatedRoot)
+			table, ok, err := updatedRoot.GetTable(ctx, tt.tableName)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			rowData, err := table.GetRowData(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(0), rowData.Len())
+
+			oldTable, _, err := root.GetTable(ctx, tt.tableName)
+			require.NoError(t, err)
+			sameSchema, err := table.HasTheSameSchema(oldTable)
+			require.NoError(t, err)
+			assert.True(t, sameSchema)
+		})
+	}
+}
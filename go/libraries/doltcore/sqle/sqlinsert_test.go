@@ -16,8 +16,11 @@ package sqle
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	sqle "github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -77,3 +80,105 @@ func testInsertQuery(t *testing.T, test InsertTest) {
 	assert.Equal(t, test.ExpectedRows, actualRows)
 	assert.Equal(t, test.ExpectedSchema, sch)
 }
+
+func TestExecuteInsertAffectedRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		affected int64
+		warnings int
+	}{
+		{
+			name:     "insert ignore",
+			query:    "insert ignore into people (id, first, last) values (0, 'Homer', 'Simpson'), (1, 'Marge', 'Simpson'), (1, 'Bart', 'Simpson')",
+			affected: 1,
+			warnings: 2,
+		},
+		{
+			name:     "replace",
+			query:    "replace into people (id, first, last) values (0, 'Homer', 'Simpson'), (1, 'Marge', 'Simpson')",
+			affected: 3,
+		},
+		{
+			name:     "replace same key twice",
+			query:    "replace into people (id, first, last) values (1, 'Marge', 'Simpson'), (1, 'Marge', 'Bouvier')",
+			affected: 3,
+		},
+		{
+			name:     "on duplicate key update",
+			query:    "insert into people (id, first, last) values (0, 'Homer', 'Simpson'), (1, 'Marge', 'Simpson') on duplicate key update first = upper(values(first))",
+			affected: 3,
+		},
+		{
+			name:     "on duplicate key update unchanged",
+			query:    "insert into people (id, first, last) values (0, 'Homer', 'Simpson') on duplicate key update first = values(first)",
+			affected: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			dEnv := dtestutils.CreateTestEnv()
+			CreateEmptyTestDatabase(dEnv, t)
+			CreateTableFn(PeopleTableName, PeopleTestSchema, Homer)(t, dEnv)
+
+			root, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+
+			db := NewDatabase("dolt", root, nil, nil)
+			engine := sqle.NewDefault()
+			engine.AddDatabase(db)
+			engine.Init()
+			sqlCtx := sql.NewContext(ctx)
+
+			ins, ok := parseInsert(test.query)
+			require.True(t, ok)
+
+			_, rowIter, err := ExecuteInsert(sqlCtx, engine, db, test.query, ins)
+			require.NoError(t, err)
+
+			affected, err := drainAffectedRows(rowIter)
+			require.NoError(t, err)
+			assert.Equal(t, test.affected, affected)
+			assert.Equal(t, test.warnings, len(sqlCtx.Warnings()))
+		})
+	}
+}
+
+func TestExecuteInsertOnDupAtomic(t *testing.T) {
+	// the second row has too few values, so the update of the first row mustn't be written either
+	query := "insert into people (id, first, last) values (0, 'Homer', 'Simpson'), (1, 'Marge') on duplicate key update first = 'HOMER'"
+
+	for _, batched := range []bool{false, true} {
+		t.Run(fmt.Sprintf("batched=%v", batched), func(t *testing.T) {
+			ctx := context.Background()
+			dEnv := dtestutils.CreateTestEnv()
+			CreateEmptyTestDatabase(dEnv, t)
+			CreateTableFn(PeopleTableName, PeopleTestSchema, Homer)(t, dEnv)
+
+			root, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+
+			db := NewDatabase("dolt", root, nil, nil)
+			if batched {
+				db = NewBatchedDatabase("dolt", root, nil, nil)
+			}
+			engine := NewEngine(CaseSensitive)
+			engine.AddDatabase(db)
+			sqlCtx := sql.NewContext(ctx)
+
+			ins, ok := parseInsert(query)
+			require.True(t, ok)
+
+			_, _, err = ExecuteInsert(sqlCtx, engine, db, query, ins)
+			require.Error(t, err)
+
+			if batched {
+				require.NoError(t, db.Flush(sqlCtx))
+			}
+			assertRootsEqual(t, root, db.Root())
+			assert.Equal(t, []sql.Row{{"Homer"}}, rootQuery(t, db.Root(), "select first from people"))
+		})
+	}
+}
@@ -17,9 +17,11 @@ package sqle
 import (
 	"context"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 
+	"github.com/src-d/go-mysql-server/sql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -70,6 +72,52 @@ func TestCaseSensitivity(t *testing.T) {
 	}
 }
 
+func TestExecuteSelectIter(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	sch, iter, err := ExecuteSelectIter(ctx, root, "select id, first from people where id > 2 order by id")
+	require.NoError(t, err)
+	defer iter.Close()
+
+	require.Len(t, sch, 2)
+	assert.Equal(t, "id", sch[0].Name)
+	assert.Equal(t, "first", sch[1].Name)
+
+	var names []string
+	var r sql.Row
+	for r, err = iter.Next(); err == nil; r, err = iter.Next() {
+		names = append(names, r[1].(string))
+	}
+
+	require.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"Lisa", "Moe", "Barney"}, names)
+}
+
+func TestExecuteSelectIterCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	_, iter, err := ExecuteSelectIter(ctx, root, "select * from people p1, people p2, people p3")
+	require.NoError(t, err)
+	defer iter.Close()
+
+	_, err = iter.Next()
+	require.NoError(t, err)
+
+	cancel()
+	_, err = iter.Next()
+	assert.Equal(t, context.Canceled, err)
+}
+
 type testCommitClock struct {
 	unixNano int64
 }
@@ -0,0 +1,300 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+
+	"github.com/src-d/go-mysql-server/sql"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/merge"
+	"github.com/liquidata-inc/dolt/go/store/atomicerr"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+var ErrWriteConflict = errors.New("write conflict: another statement changed the same rows while this one ran, try it again")
+
+// statementRoot is the root that the statement a session is running reads and writes. It starts as the database's
+// root when the statement starts, and its writes are merged into the database's root when they're made, so that
+// statements run concurrently by different sessions neither see each other's partial writes nor replace each other's
+// writes.
+type statementRoot struct {
+	start  *doltdb.RootValue     // The database's root that the statement's writes are based on
+	root   *doltdb.RootValue     // start with the statement's writes
+	tables map[string]*DoltTable // The tables loaded from root
+}
+
+// startStatement gives the statement the session with the id given is starting its own root, which is the database's
+// current root. Batched databases, which are written by a single session and flushed explicitly, and read-only
+// databases read and write the database's root directly.
+func (db *Database) startStatement(id uint32) {
+	if db.batchMode == batched || db.ReadOnly() {
+		return
+	}
+
+	root := db.Root()
+
+	db.stmtsMu.Lock()
+	defer db.stmtsMu.Unlock()
+
+	db.stmts[id] = &statementRoot{start: root, root: root, tables: make(map[string]*DoltTable)}
+}
+
+type statementPartKey struct{}
+
+// asStatementPart returns a context for running one of the queries that together make up a single statement, such as
+// the UPDATE or INSERT run for each row of an INSERT ... ON DUPLICATE KEY UPDATE. The queries share the root of the
+// statement, which must have been started with beginStatement, rather than each starting its own, and their writes
+// aren't merged into the database's root until the statement calls written once all of them are done.
+func asStatementPart(ctx *sql.Context) *sql.Context {
+	return ctx.WithContext(context.WithValue(ctx.Context, statementPartKey{}, true))
+}
+
+func isStatementPart(ctx context.Context) bool {
+	part, _ := ctx.Value(statementPartKey{}).(bool)
+	return part
+}
+
+// statement returns the root of the statement being run by the session of the context given, or nil if the context
+// isn't that of a statement with its own root.
+func (db *Database) statement(ctx context.Context) *statementRoot {
+	sqlCtx, ok := ctx.(*sql.Context)
+	if !ok || sqlCtx.Session == nil {
+		return nil
+	}
+
+	db.stmtsMu.Lock()
+	defer db.stmtsMu.Unlock()
+
+	return db.stmts[sqlCtx.ID()]
+}
+
+// rootFor returns the root read and written by the query of the context given, which is its statement's root if it has
+// one, and the database's root if it doesn't.
+func (db *Database) rootFor(ctx context.Context) *doltdb.RootValue {
+	if stmt := db.statement(ctx); stmt != nil {
+		return stmt.root
+	}
+
+	return db.Root()
+}
+
+// setRootFor replaces the root written by the query of the context given. The new root is merged into the database's
+// root by written.
+func (db *Database) setRootFor(ctx context.Context, root *doltdb.RootValue) {
+	if stmt := db.statement(ctx); stmt != nil {
+		stmt.root = root
+		return
+	}
+
+	db.SetRoot(root)
+}
+
+// tablesFor returns the tables loaded from the root read by the query of the context given, by name.
+func (db *Database) tablesFor(ctx context.Context) map[string]*DoltTable {
+	if stmt := db.statement(ctx); stmt != nil {
+		return stmt.tables
+	}
+
+	db.rootMu.Lock()
+	defer db.rootMu.Unlock()
+
+	return db.tables
+}
+
+// mergeStatement merges the writes made by the statement of the context given into the database's root. If the
+// database's root hasn't changed since the statement's root was taken from it, it's replaced by the statement's root.
+// Otherwise another statement wrote to it in the meantime: the writes of a Serializable session then fail with
+// ErrSerializationFailure, and those of other sessions are merged with the other statement's writes, failing with
+// ErrWriteConflict if both changed the same rows. A statement whose writes fail is left with the database's current
+// root.
+func (db *Database) mergeStatement(ctx context.Context) error {
+	stmt := db.statement(ctx)
+	if stmt == nil {
+		return nil
+	}
+
+	db.rootMu.Lock()
+	defer db.rootMu.Unlock()
+
+	unchanged, err := rootsEqual(db.root, stmt.start)
+	if err != nil {
+		return err
+	}
+
+	merged := stmt.root
+	if !unchanged {
+		if isolationLevel(ctx.(*sql.Context).Session) == Serializable {
+			stmt.reset(db.root)
+			return ErrSerializationFailure
+		}
+
+		// A database without a DoltDB has nothing to merge with
+		if db.ddb == nil {
+			stmt.reset(db.root)
+			return ErrWriteConflict
+		}
+
+		// Rows written by both statements would be merged without conflict if they were written the same, so an
+		// update such as v = v + 1 would be lost
+		if conflict, err := rowsWrittenByBoth(ctx, stmt.start, stmt.root, db.root); err != nil {
+			return err
+		} else if conflict {
+			stmt.reset(db.root)
+			return ErrWriteConflict
+		}
+
+		var tblToStats map[string]*merge.MergeStats
+		merged, tblToStats, err = actions.MergeRoots(ctx, db.ddb, db.root, stmt.root, stmt.start)
+		if err == merge.ErrSameTblAddedTwice || err == merge.ErrTblDeletedAndModified {
+			stmt.reset(db.root)
+			return ErrWriteConflict
+		} else if err != nil {
+			return err
+		}
+
+		for _, stats := range tblToStats {
+			if stats.Conflicts > 0 {
+				stmt.reset(db.root)
+				return ErrWriteConflict
+			}
+		}
+	}
+
+	db.root = merged
+	db.tables = make(map[string]*DoltTable)
+	stmt.reset(merged)
+
+	return nil
+}
+
+// rowsWrittenByBoth returns whether any row written between the start root and the root given was also written between
+// the start root and the current root. Tables added or removed by either are left to the merge.
+func rowsWrittenByBoth(ctx context.Context, start, root, current *doltdb.RootValue) (bool, error) {
+	tblNames, err := root.GetTableNames(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, tblName := range tblNames {
+		h, _, err := root.GetTableHash(ctx, tblName)
+		if err != nil {
+			return false, err
+		}
+
+		startH, inStart, err := start.GetTableHash(ctx, tblName)
+		if err != nil {
+			return false, err
+		}
+
+		currentH, inCurrent, err := current.GetTableHash(ctx, tblName)
+		if err != nil {
+			return false, err
+		}
+
+		if !inStart || !inCurrent || h == startH || currentH == startH {
+			continue
+		}
+
+		rows, err := tableRowData(ctx, root, tblName)
+		if err != nil {
+			return false, err
+		}
+
+		startRows, err := tableRowData(ctx, start, tblName)
+		if err != nil {
+			return false, err
+		}
+
+		currentRows, err := tableRowData(ctx, current, tblName)
+		if err != nil {
+			return false, err
+		}
+
+		if conflict, err := keysWrittenByBoth(ctx, startRows, rows, currentRows); err != nil || conflict {
+			return conflict, err
+		}
+	}
+
+	return false, nil
+}
+
+// keysWrittenByBoth returns whether any key whose value differs between startRows and rows also has a different value
+// in currentRows than in startRows.
+func keysWrittenByBoth(ctx context.Context, startRows, rows, currentRows types.Map) (bool, error) {
+	ae := atomicerr.New()
+	changeChan := make(chan types.ValueChanged, 32)
+	stopChan := make(chan struct{})
+
+	go func() {
+		defer close(changeChan)
+		rows.Diff(ctx, startRows, ae, changeChan, stopChan)
+	}()
+
+	defer func() {
+		close(stopChan)
+		for range changeChan {
+		}
+	}()
+
+	for change := range changeChan {
+		startVal, inStart, err := startRows.MaybeGet(ctx, change.Key)
+		if err != nil {
+			return false, err
+		}
+
+		currentVal, inCurrent, err := currentRows.MaybeGet(ctx, change.Key)
+		if err != nil {
+			return false, err
+		}
+
+		if inStart != inCurrent || (inStart && !startVal.Equals(currentVal)) {
+			return true, nil
+		}
+	}
+
+	if err := ae.Get(); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+func tableRowData(ctx context.Context, root *doltdb.RootValue, tblName string) (types.Map, error) {
+	tbl, _, err := root.GetTable(ctx, tblName)
+	if err != nil {
+		return types.EmptyMap, err
+	}
+
+	return tbl.GetRowData(ctx)
+}
+
+// reset sets the statement's root to the root given, dropping its writes and the tables loaded from its old root.
+func (stmt *statementRoot) reset(root *doltdb.RootValue) {
+	stmt.start = root
+	stmt.root = root
+	stmt.tables = make(map[string]*DoltTable)
+}
+
+// endStatements forgets the statement root of the session with the id given.
+func (db *Database) endStatements(id uint32) {
+	db.stmtsMu.Lock()
+	defer db.stmtsMu.Unlock()
+
+	delete(db.stmts, id)
+}
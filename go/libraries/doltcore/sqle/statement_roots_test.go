@@ -0,0 +1,102 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestStatementRoots(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      string
+		row      sql.Row
+		expected error
+	}{
+		{"other row", "", sql.Row{int64(11), "Maude", "Flanders", nil, nil, nil, nil, nil}, nil},
+		{"same row", "", sql.Row{int64(10), "Rod", "Flanders", nil, nil, nil, nil, nil}, ErrWriteConflict},
+		{"same row written the same", "", sql.Row{int64(10), "Ned", "Flanders", nil, nil, nil, nil, nil}, ErrWriteConflict},
+		{"serializable", "SET TRANSACTION ISOLATION LEVEL SERIALIZABLE", sql.Row{int64(11), "Maude", "Flanders", nil, nil, nil, nil, nil}, ErrSerializationFailure},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			root, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+
+			// both sessions share the same database, as the connections of a server do
+			engine := NewEngine(CaseSensitive)
+			db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+			engine.AddDatabase(db)
+
+			ctx1 := sql.NewContext(ctx, sql.WithSession(sql.NewSession("", "", "", 1)))
+			ctx2 := sql.NewContext(ctx, sql.WithSession(sql.NewSession("", "", "", 2)))
+			if test.set != "" {
+				_, err = queryRowCount(ctx1, engine.Query, test.set)
+				require.NoError(t, err)
+			}
+
+			// session 1 starts a statement, then session 2 writes before the statement of session 1 writes
+			_, err = queryRowCount(ctx1, engine.Query, "select * from people")
+			require.NoError(t, err)
+			tbl, ok, err := db.GetTableInsensitive(ctx1, "people")
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			people := countPeople(t, engine)
+			_, err = queryRowCount(ctx2, engine.Query, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+			require.NoError(t, err)
+
+			// the statement doesn't see the insert
+			iter, err := plan.NewResolvedTable(tbl).RowIter(ctx1)
+			require.NoError(t, err)
+			rows, err := sql.RowIterToRows(iter)
+			require.NoError(t, err)
+			assert.Len(t, rows, people)
+
+			inserter := tbl.(*DoltTable).Inserter(ctx1)
+			require.NoError(t, inserter.Insert(ctx1, test.row))
+			err = inserter.Close(ctx1)
+			assert.Equal(t, test.expected, err)
+
+			if test.expected == nil {
+				// the writes of both sessions were kept
+				assert.Equal(t, people+2, countPeople(t, engine))
+			} else {
+				// the write was rejected and the insert kept
+				assert.Equal(t, people+1, countPeople(t, engine))
+				n, err := queryRowCount(sql.NewEmptyContext(), engine.Query, "select * from people where first = 'Ned'")
+				require.NoError(t, err)
+				assert.Equal(t, 1, n)
+			}
+
+			// the next statement starts from the latest root, so it can write
+			_, err = queryRowCount(ctx1, engine.Query, "insert into people (id, first, last) values (12, 'Todd', 'Flanders')")
+			require.NoError(t, err)
+		})
+	}
+}
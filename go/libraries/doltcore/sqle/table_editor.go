@@ -26,6 +26,11 @@ import (
 )
 
 var ErrDuplicatePrimaryKeyFmt = "duplicate primary key given: (%v)"
+var ErrAppendOnlyFmt = "table %s is append-only, so its existing rows can't be updated or deleted"
+
+// tableEditorMaxEdits is the number of edits a tableEditor accumulates before applying them to its map. Applying edits
+// in chunks bounds the memory used by statements that edit a large number of rows.
+var tableEditorMaxEdits int64 = 256 * 1024
 
 // tableEditor supports making multiple row edits (inserts, updates, deletes) to a table. It does error checking for key
 // collision etc. in the Close() method, as well as during Insert / Update.
@@ -55,6 +60,10 @@ func newTableEditor(t *DoltTable) *tableEditor {
 }
 
 func (te *tableEditor) Insert(ctx *sql.Context, sqlRow sql.Row) error {
+	if err := checkNotNull(sqlRow, te.t.sch); err != nil {
+		return err
+	}
+
 	dRow, err := SqlRowToDoltRow(te.t.table.Format(), sqlRow, te.t.sch)
 	if err != nil {
 		return err
@@ -70,6 +79,25 @@ func (te *tableEditor) Insert(ctx *sql.Context, sqlRow sql.Row) error {
 		return err
 	}
 
+	// For INSERT IGNORE, rows with keys that already exist are skipped rather than reported as errors.
+	if stats := insertIgnoreFromContext(ctx); stats != nil {
+		exists, err := te.keyExists(ctx, hash, key)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			value, err := types.EncodedValue(ctx, key)
+			if err != nil {
+				return err
+			}
+
+			ctx.Warn(mysqlErrDupEntry, ErrDuplicatePrimaryKeyFmt, value)
+			stats.ignored++
+			return nil
+		}
+	}
+
 	// If we've already inserted this key as part of this insert operation, that's an error. Inserting a row that already
 	// exists in the table will be handled in Close().
 	if _, ok := te.addedKeys[hash]; ok {
@@ -90,7 +118,7 @@ func (te *tableEditor) Insert(ctx *sql.Context, sqlRow sql.Row) error {
 	}
 
 	te.ed = te.ed.Set(key, dRow.NomsMapValue(te.t.sch))
-	return nil
+	return te.applyEditsIfFull(ctx)
 }
 
 func (te *tableEditor) Delete(ctx *sql.Context, sqlRow sql.Row) error {
@@ -119,7 +147,60 @@ func (te *tableEditor) Delete(ctx *sql.Context, sqlRow sql.Row) error {
 	}
 
 	te.ed = te.ed.Remove(key)
-	return nil
+	return te.applyEditsIfFull(ctx)
+}
+
+// tableReplacer is the sql.RowReplacer for a table. REPLACE deletes the existing row with the key of each row given
+// before inserting it, and counts the row as affected twice if there was one, so unlike a plain delete, deleting a row
+// that doesn't exist is reported with sql.ErrDeleteRowNotFound.
+type tableReplacer struct {
+	*tableEditor
+}
+
+var _ sql.RowReplacer = tableReplacer{}
+
+func (tr tableReplacer) Delete(ctx *sql.Context, sqlRow sql.Row) error {
+	dRow, err := SqlRowToDoltRow(tr.t.table.Format(), sqlRow, tr.t.sch)
+	if err != nil {
+		return err
+	}
+
+	key, err := dRow.NomsMapKey(tr.t.sch).Value(ctx)
+	if err != nil {
+		return errhand.BuildDError("failed to get row key").AddCause(err).Build()
+	}
+
+	hash, err := key.Hash(dRow.Format())
+	if err != nil {
+		return err
+	}
+
+	exists, err := tr.keyExists(ctx, hash, key)
+	if err != nil {
+		return err
+	} else if !exists {
+		return sql.ErrDeleteRowNotFound
+	}
+
+	return tr.tableEditor.Delete(ctx, sqlRow)
+}
+
+// keyExists returns whether a row with the key given exists, taking into account the edits made so far.
+func (te *tableEditor) keyExists(ctx context.Context, h hash.Hash, key types.Value) (bool, error) {
+	if _, ok := te.addedKeys[h]; ok {
+		return true, nil
+	}
+
+	if _, ok := te.removedKeys[h]; ok {
+		return false, nil
+	}
+
+	_, rowExists, err := te.t.table.GetRow(ctx, key.(types.Tuple), te.t.sch)
+	if err != nil {
+		return false, errhand.BuildDError("failed to read table").AddCause(err).Build()
+	}
+
+	return rowExists, nil
 }
 
 func (t *DoltTable) newMapEditor(ctx context.Context) (*types.MapEditor, error) {
@@ -132,6 +213,10 @@ func (t *DoltTable) newMapEditor(ctx context.Context) (*types.MapEditor, error)
 }
 
 func (te *tableEditor) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.Row) error {
+	if err := checkNotNull(newRow, te.t.sch); err != nil {
+		return err
+	}
+
 	dOldRow, err := SqlRowToDoltRow(te.t.table.Format(), oldRow, te.t.sch)
 	if err != nil {
 		return err
@@ -181,6 +266,22 @@ func (te *tableEditor) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.Row)
 	}
 
 	te.ed.Set(dNewKeyVal, dNewRow.NomsMapValue(te.t.sch))
+	return te.applyEditsIfFull(ctx)
+}
+
+// applyEditsIfFull applies the accumulated edits to the map being edited once there are tableEditorMaxEdits of them,
+// and continues with a new editor for the result. The table itself isn't updated until the editor is flushed.
+func (te *tableEditor) applyEditsIfFull(ctx context.Context) error {
+	if te.ed.NumEdits() < tableEditorMaxEdits {
+		return nil
+	}
+
+	m, err := te.ed.Map(ctx)
+	if err != nil {
+		return errhand.BuildDError("failed to modify table").AddCause(err).Build()
+	}
+
+	te.ed = m.Edit()
 	return nil
 }
 
@@ -190,7 +291,11 @@ func (te *tableEditor) Close(ctx *sql.Context) error {
 	if te.t.db.batchMode == batched {
 		return nil
 	}
-	return te.flush(ctx)
+
+	if err := te.flush(ctx); err != nil {
+		return err
+	}
+	return te.t.db.written(ctx)
 }
 
 func (te *tableEditor) flush(ctx context.Context) error {
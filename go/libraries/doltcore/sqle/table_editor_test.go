@@ -16,6 +16,8 @@ package sqle
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/src-d/go-mysql-server/sql"
@@ -181,6 +183,51 @@ func TestTableEditor(t *testing.T) {
 	}
 }
 
+func TestTableEditorLargeInsert(t *testing.T) {
+	const maxEdits = 100
+	defer setTableEditorMaxEdits(maxEdits)()
+
+	// Enough rows for the edits to be applied to the map several times, with some left over for Close to apply
+	const numRows = 3*maxEdits + 7
+	values := make([]string, numRows)
+	expectedRows := make([]sql.Row, numRows)
+	for i := range values {
+		values[i] = fmt.Sprintf("(%d, 'first%d', 'last%d')", i+10, i, i)
+		expectedRows[i] = sql.Row{int64(i + 10), fmt.Sprintf("first%d", i), fmt.Sprintf("last%d", i)}
+	}
+	insert := "insert into people (id, first, last) values " + strings.Join(values, ", ")
+
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	updated, err := ExecuteSql(dEnv, root, insert)
+	require.NoError(t, err)
+
+	rows, err := ExecuteSelect(updated, "select id, first, last from people where id >= 10 order by id")
+	require.NoError(t, err)
+	assert.Equal(t, expectedRows, rows)
+
+	// A duplicate of a row inserted several chunks earlier is still an error
+	_, err = ExecuteSql(dEnv, root, insert+", (10, 'dupe', 'dupe')")
+	require.Error(t, err)
+
+	// As is a duplicate of a row already in the table
+	_, err = ExecuteSql(dEnv, root, insert+", (0, 'dupe', 'dupe')")
+	require.Error(t, err)
+}
+
+// setTableEditorMaxEdits sets the number of edits table editors accumulate before applying them, and returns a func
+// that restores the previous value.
+func setTableEditorMaxEdits(maxEdits int64) func() {
+	prev := tableEditorMaxEdits
+	tableEditorMaxEdits = maxEdits
+	return func() {
+		tableEditorMaxEdits = prev
+	}
+}
+
 func r(row row.Row, sch schema.Schema) sql.Row {
 	sqlRow, err := doltRowToSqlRow(row, sch)
 	if err != nil {
@@ -188,3 +235,35 @@ func r(row row.Row, sch schema.Schema) sql.Row {
 	}
 	return sqlRow
 }
+
+func TestNotNullConstraints(t *testing.T) {
+	tests := []struct {
+		query       string
+		expectedErr string
+	}{
+		{"insert into people (id, first, last) values (100, 'Bart', null)", "column name 'last' is non-nullable but attempted to set a value of null"},
+		{"insert into people (id, last) values (100, 'Simpson')", "column name 'first' is non-nullable but attempted to set default value of null"},
+		{"insert into people (first, last) values ('Bart', 'Simpson')", "column name 'id' is non-nullable but attempted to set default value of null"},
+		{"replace into people (id, first, last) values (0, null, 'Simpson')", "column name 'first' is non-nullable but attempted to set a value of null"},
+		{"update people set last = null where id = 0", "column name 'last' is non-nullable but attempted to set a value of null"},
+		{"update people set age = null where id = 0", ""},
+		{"insert into people (id, first, last) values (100, 'Bart', 'Simpson')", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			dEnv := dtestutils.CreateTestEnv()
+			CreateTestDatabase(dEnv, t)
+			root, err := dEnv.WorkingRoot(context.Background())
+			require.NoError(t, err)
+
+			_, err = executeModify(context.Background(), root, test.query)
+			if len(test.expectedErr) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -25,17 +25,22 @@ import (
 	"github.com/liquidata-inc/dolt/go/cmd/dolt/errhand"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
 	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
 // DoltTable implements the sql.Table interface and gives access to dolt table rows and schema.
 type DoltTable struct {
-	name   string
-	table  *doltdb.Table
-	sch    schema.Schema
-	sqlSch sql.Schema
-	db     *Database
-	ed     *tableEditor
+	name       string
+	table      *doltdb.Table
+	sch        schema.Schema
+	sqlSch     sql.Schema
+	db         *Database
+	ed         *tableEditor
+	projection []string
+
+	// Comparisons that the rows read must satisfy, which let scans skip chunks of rows using their zone maps
+	zonePreds []zonePredicate
 }
 
 var _ sql.Table = (*DoltTable)(nil)
@@ -43,6 +48,8 @@ var _ sql.UpdatableTable = (*DoltTable)(nil)
 var _ sql.DeletableTable = (*DoltTable)(nil)
 var _ sql.InsertableTable = (*DoltTable)(nil)
 var _ sql.ReplaceableTable = (*DoltTable)(nil)
+var _ sql.ProjectedTable = (*DoltTable)(nil)
+var _ sql.PartitionCounter = (*DoltTable)(nil)
 
 // Implements sql.IndexableTable
 func (t *DoltTable) WithIndexLookup(lookup sql.IndexLookup) sql.Table {
@@ -57,6 +64,19 @@ func (t *DoltTable) WithIndexLookup(lookup sql.IndexLookup) sql.Table {
 	}
 }
 
+// WithProjection implements sql.ProjectedTable. The projection doesn't change the schema or the rows returned by a
+// table scan, but lets index lookups that cover every projected column skip reading the rows.
+func (t *DoltTable) WithProjection(colNames []string) sql.Table {
+	nt := *t
+	nt.projection = colNames
+	return &nt
+}
+
+// Projection implements sql.ProjectedTable
+func (t *DoltTable) Projection() []string {
+	return t.projection
+}
+
 // Implements sql.IndexableTable
 func (t *DoltTable) IndexKeyValues(*sql.Context, []string) (sql.PartitionIndexKeyValueIter, error) {
 	return nil, errors.New("creating new indexes not supported")
@@ -97,14 +117,85 @@ func (t *DoltTable) sqlSchema() sql.Schema {
 	return sqlSch
 }
 
-// Returns the partitions for this table. We return a single partition, but could potentially get more performance by
-// returning multiple.
-func (t *DoltTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
-	return &doltTablePartitionIter{}, nil
+// tablePartitions is the number of partitions the rows of a table are split into, if its row data has enough subtrees.
+// The engine can scan the partitions of a table in parallel.
+const tablePartitions = 16
+
+// Partitions implements sql.Table. The rows of the table are split into partitions by subtree of its row data, so that
+// each partition holds a range of primary keys. Tables whose rows fit in a single chunk have a single partition.
+func (t *DoltTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	parts, err := t.partitions(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &doltTablePartitionIter{parts: parts}, nil
+}
+
+// PartitionCount implements sql.PartitionCounter
+func (t *DoltTable) PartitionCount(ctx *sql.Context) (int64, error) {
+	parts, err := t.partitions(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(parts)), nil
+}
+
+// partitions returns the partitions of the table's rows, in primary key order. If the table has zone predicates, only
+// the leaf chunks of its row data that may hold rows satisfying them are partitioned.
+func (t *DoltTable) partitions(ctx *sql.Context) ([]doltTablePartition, error) {
+	var hashes []hash.Hash
+	if len(t.zonePreds) > 0 {
+		matching, ok, err := t.zoneMatchingLeaves(ctx)
+
+		if err != nil {
+			return nil, err
+		} else if ok {
+			hashes = matching
+		}
+	}
+
+	if hashes == nil {
+		rowData, err := t.table.GetRowData(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+
+		hashes, err = rowData.SubtreeHashes(ctx, tablePartitions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(hashes) < 2 {
+			return []doltTablePartition{{}}, nil
+		}
+	}
+
+	n := tablePartitions
+	if len(hashes) < n {
+		n = len(hashes)
+	}
+
+	parts := make([]doltTablePartition, 0, n)
+	for i := 0; i < n; i++ {
+		start, end := i*len(hashes)/n, (i+1)*len(hashes)/n
+		parts = append(parts, doltTablePartition{hashes: hashes[start:end]})
+	}
+
+	return parts, nil
 }
 
-// Returns the table rows for the partition given (all rows of the table).
-func (t *DoltTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+// PartitionRows implements sql.Table, returning the rows of the partition given in primary key order.
+func (t *DoltTable) PartitionRows(ctx *sql.Context, part sql.Partition) (sql.RowIter, error) {
+	if p, ok := part.(*doltTablePartition); ok && p.hashes != nil {
+		return &subtreeRowIter{table: t, ctx: ctx, hashes: p.hashes}, nil
+	}
+
 	return newRowIterator(t, ctx)
 }
 
@@ -140,7 +231,7 @@ func (t *DoltTable) Deleter(*sql.Context) sql.RowDeleter {
 
 // Replacer implements sql.ReplaceableTable
 func (t *DoltTable) Replacer(ctx *sql.Context) sql.RowReplacer {
-	return t.getTableEditor()
+	return tableReplacer{t.getTableEditor()}
 }
 
 // Updater implements sql.UpdatableTable
@@ -151,7 +242,8 @@ func (t *DoltTable) Updater(ctx *sql.Context) sql.RowUpdater {
 // doltTablePartitionIter, an object that knows how to return the single partition exactly once.
 type doltTablePartitionIter struct {
 	sql.PartitionIter
-	i int
+	i     int
+	parts []doltTablePartition // The partitions to return, or nil for a single partition of all the rows
 }
 
 // Close is required by the sql.PartitionIter interface. Does nothing.
@@ -161,24 +253,38 @@ func (itr *doltTablePartitionIter) Close() error {
 
 // Next returns the next partition if there is one, or io.EOF if there isn't.
 func (itr *doltTablePartitionIter) Next() (sql.Partition, error) {
-	if itr.i > 0 {
+	if itr.parts == nil {
+		if itr.i > 0 {
+			return nil, io.EOF
+		}
+		itr.i++
+
+		return &doltTablePartition{}, nil
+	}
+
+	if itr.i >= len(itr.parts) {
 		return nil, io.EOF
 	}
 	itr.i++
 
-	return &doltTablePartition{}, nil
+	return &itr.parts[itr.i-1], nil
 }
 
-// A table partition, currently an unused layer of abstraction but required for the framework.
+// A table partition, which holds either the rows of the subtrees of a table's row data with the hashes given, or all
+// of its rows if it has none.
 type doltTablePartition struct {
 	sql.Partition
+	hashes []hash.Hash
 }
 
 const partitionName = "single"
 
-// Key returns the key for this partition, which must uniquely identity the partition. We have only a single partition
-// per table, so we use a constant.
+// Key returns the key for this partition, which must uniquely identity the partition. A partition of subtrees is
+// identified by the hash of its first subtree.
 func (p doltTablePartition) Key() []byte {
+	if len(p.hashes) > 0 {
+		return []byte(p.hashes[0].String())
+	}
 	return []byte(partitionName)
 }
 
@@ -189,16 +295,19 @@ func (t *DoltTable) updateTable(ctx context.Context, mapEditor *types.MapEditor)
 	}
 
 	newTable, err := t.table.UpdateRows(ctx, updated)
-	if err != nil {
+	if err == doltdb.ErrAppendOnly {
+		return fmt.Errorf(ErrAppendOnlyFmt, t.name)
+	} else if err != nil {
 		return errhand.BuildDError("failed to update rows").AddCause(err).Build()
 	}
 
-	newRoot, err := doltdb.PutTable(ctx, t.db.root, t.db.root.VRW(), t.name, newTable)
+	root := t.db.rootFor(ctx)
+	newRoot, err := doltdb.PutTable(ctx, root, root.VRW(), t.name, newTable)
 	if err != nil {
 		return errhand.BuildDError("failed to write table back to database").AddCause(err).Build()
 	}
 
 	t.table = newTable
-	t.db.root = newRoot
+	t.db.setRootFor(ctx, newRoot)
 	return nil
 }
@@ -0,0 +1,101 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"io"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestTablePartitions(t *testing.T) {
+	root := zoneMapTestRoot(t)
+	db := NewDatabase("dolt", root, nil, nil)
+	ctx := sql.NewEmptyContext()
+
+	sqlTbl, ok, err := db.GetTableInsensitive(ctx, "t")
+	require.NoError(t, err)
+	require.True(t, ok)
+	tbl := sqlTbl.(*DoltTable)
+
+	count, err := tbl.PartitionCount(ctx)
+	require.NoError(t, err)
+	assert.True(t, count > 1)
+	assert.True(t, count <= tablePartitions)
+
+	// the partitions hold every row once, in primary key order
+	partIter, err := tbl.Partitions(ctx)
+	require.NoError(t, err)
+	keys := make(map[string]bool)
+	var pks []int64
+	for {
+		part, err := partIter.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.False(t, keys[string(part.Key())])
+		keys[string(part.Key())] = true
+
+		iter, err := tbl.PartitionRows(ctx, part)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(iter)
+		require.NoError(t, err)
+		assert.NotEmpty(t, rows)
+		for _, r := range rows {
+			pks = append(pks, r[0].(int64))
+		}
+	}
+	require.NoError(t, partIter.Close())
+	assert.Equal(t, int(count), len(keys))
+	require.Len(t, pks, zoneMapTestRows)
+	for i, pk := range pks {
+		assert.Equal(t, int64(i), pk)
+	}
+
+	// the engine scans the partitions in parallel
+	engine := NewEngine(CaseSensitive)
+	engine.Analyzer.Parallelism = 4
+	engine.AddDatabase(db)
+	for query, expected := range map[string]int64{
+		"select count(*) from t":                  zoneMapTestRows,
+		"select count(*) from t where v = 3":      zoneMapTestRows / 10,
+		"select count(*) from t where pk >= 1990": 10,
+	} {
+		_, iter, err := engine.Query(ctx, query)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(iter)
+		require.NoError(t, err)
+		assert.Equal(t, []sql.Row{{expected}}, rows, query)
+	}
+
+	// a table whose rows fit in a single chunk has a single partition
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err = dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	sqlTbl, ok, err = NewDatabase("dolt", root, nil, nil).GetTableInsensitive(ctx, "people")
+	require.NoError(t, err)
+	require.True(t, ok)
+	count, err = sqlTbl.(*DoltTable).PartitionCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
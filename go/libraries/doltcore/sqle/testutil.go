@@ -33,7 +33,7 @@ import (
 // Executes all the SQL non-select statements given in the string against the root value given and returns the updated
 // root, or an error. Statements in the input string are split by `;\n`
 func ExecuteSql(dEnv *env.DoltEnv, root *doltdb.RootValue, statements string) (*doltdb.RootValue, error) {
-	engine := sqle.NewDefault()
+	engine := NewEngine(CaseSensitive)
 	db := NewBatchedDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
 	engine.AddDatabase(db)
 
@@ -48,14 +48,14 @@ func ExecuteSql(dEnv *env.DoltEnv, root *doltdb.RootValue, statements string) (*
 		}
 
 		var execErr error
-		switch sqlStatement.(type) {
+		switch s := sqlStatement.(type) {
 		case *sqlparser.Show:
 			return nil, errors.New("Show statements aren't handled")
 		case *sqlparser.Select, *sqlparser.OtherRead:
 			return nil, errors.New("Select statements aren't handled")
 		case *sqlparser.Insert:
 			var rowIter sql.RowIter
-			_, rowIter, execErr = engine.Query(sql.NewEmptyContext(), query)
+			_, rowIter, execErr = ExecuteInsert(sql.NewEmptyContext(), engine, db, query, s)
 			if execErr == nil {
 				execErr = drainIter(rowIter)
 			}
@@ -88,14 +88,23 @@ func sqlDDL(db *Database, engine *sqle.Engine, dEnv *env.DoltEnv, query string)
 	}
 
 	ddl := stmt.(*sqlparser.DDL)
-	ctx := sql.NewEmptyContext()
+	ctx := sql.NewContext(context.Background(), sql.WithQuery(query))
 	switch ddl.Action {
-	case sqlparser.CreateStr, sqlparser.DropStr:
+	case sqlparser.CreateStr:
 		_, ri, err := engine.Query(ctx, query)
 		if err == nil {
 			ri.Close()
 		}
 		return err
+	case sqlparser.DropStr:
+		if len(ddl.FromViews) > 0 {
+			_, ri, err := engine.Query(ctx, query)
+			if err == nil {
+				ri.Close()
+			}
+			return err
+		}
+		return ExecuteDropTable(ctx, db, ddl)
 	case sqlparser.AlterStr, sqlparser.RenameStr:
 		newRoot, err := dsql.ExecuteAlter(ctx, dEnv.DoltDB, db.Root(), ddl, query)
 		if err != nil {
@@ -104,7 +113,7 @@ func sqlDDL(db *Database, engine *sqle.Engine, dEnv *env.DoltEnv, query string)
 		db.SetRoot(newRoot)
 		return nil
 	case sqlparser.TruncateStr:
-		return fmt.Errorf("Unhandled DDL action %v in query %v", ddl.Action, query)
+		return ExecuteTruncateTable(ctx, db, ddl)
 	default:
 		return fmt.Errorf("Unhandled DDL action %v in query %v", ddl.Action, query)
 	}
@@ -112,19 +121,15 @@ func sqlDDL(db *Database, engine *sqle.Engine, dEnv *env.DoltEnv, query string)
 
 // Executes the select statement given and returns the resulting rows, or an error if one is encountered.
 // This uses the index functionality, which is not ready for prime time. Use with caution.
+// All result rows are held in memory. For large result sets, use ExecuteSelectIter instead.
 func ExecuteSelect(root *doltdb.RootValue, query string) ([]sql.Row, error) {
-	db := NewDatabase("dolt", root, nil, nil)
-	engine := sqle.NewDefault()
-	engine.AddDatabase(db)
-	engine.Catalog.RegisterIndexDriver(NewDoltIndexDriver(db))
-	_ = engine.Init()
-
-	ctx := sql.NewEmptyContext()
-	_, rowIter, err := engine.Query(ctx, query)
+	_, rowIter, err := ExecuteSelectIter(context.Background(), root, query)
 	if err != nil {
 		return nil, err
 	}
 
+	defer rowIter.Close()
+
 	var (
 		rows   []sql.Row
 		rowErr error
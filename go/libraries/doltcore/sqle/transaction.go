@@ -0,0 +1,106 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env/actions"
+)
+
+var ErrTransactionInProgress = errors.New("a transaction is already in progress, COMMIT or ROLLBACK it first")
+var ErrTransactionConflictFmt = "transaction conflicts with changes made to the working set to tables: %s. ROLLBACK the transaction and try again"
+
+// Transaction is a transaction begun with BEGIN or START TRANSACTION. The writes of the statements run during the
+// transaction accumulate in the root of its database, and are merged into the working set when it's committed, or
+// discarded when it's rolled back.
+type Transaction struct {
+	db          *Database
+	startRoot   *doltdb.RootValue
+	workingRoot *doltdb.RootValue
+}
+
+// BeginTransaction begins a transaction on the database given. workingRoot is the root of the working set the
+// database's root was read from, which may have been changed since by the database's own statements. Everything the
+// database's statements changed relative to it, before and during the transaction, is merged into the working set
+// when the transaction is committed.
+func BeginTransaction(db *Database, workingRoot *doltdb.RootValue) *Transaction {
+	return &Transaction{db, db.Root(), workingRoot}
+}
+
+// Rollback discards the writes made during the transaction, returning the database to the root it had when the
+// transaction began.
+func (tx *Transaction) Rollback() {
+	tx.db.replaceRoot(tx.startRoot)
+}
+
+// Commit merges the changes made by the database's statements into workingRoot, which is the current root of the
+// working set, and returns the merged root, which also becomes the database's root. If the working set hasn't changed
+// since the transaction's working root was read, the database's root is returned unchanged. If the changes conflict
+// with the ones made to the working set in the meantime, an error naming the conflicting tables is returned and the
+// transaction is left open.
+func (tx *Transaction) Commit(ctx context.Context, workingRoot *doltdb.RootValue) (*doltdb.RootValue, error) {
+	root := tx.db.Root()
+
+	unchanged, err := rootsEqual(workingRoot, tx.workingRoot)
+
+	if err != nil {
+		return nil, err
+	} else if unchanged {
+		return root, nil
+	}
+
+	merged, tblToStats, err := actions.MergeRoots(ctx, tx.db.ddb, workingRoot, root, tx.workingRoot)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []string
+	for tblName, stats := range tblToStats {
+		if stats.Conflicts > 0 {
+			conflicts = append(conflicts, tblName)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return nil, fmt.Errorf(ErrTransactionConflictFmt, strings.Join(conflicts, ", "))
+	}
+
+	tx.db.replaceRoot(merged)
+	return merged, nil
+}
+
+func rootsEqual(root1, root2 *doltdb.RootValue) (bool, error) {
+	h1, err := root1.HashOf()
+
+	if err != nil {
+		return false, err
+	}
+
+	h2, err := root2.HashOf()
+
+	if err != nil {
+		return false, err
+	}
+
+	return h1 == h2, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestTransactionRollback(t *testing.T) {
+	dEnv, engine, db, working := transactionEnv(t)
+	people := countPeople(t, engine)
+
+	tx := BeginTransaction(db, working)
+	runQuery(t, engine, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	runQuery(t, engine, "delete from people where id = 0")
+	assert.Equal(t, people, countPeople(t, engine))
+
+	tx.Rollback()
+	assert.Equal(t, people, countPeople(t, engine))
+	assert.Equal(t, working, db.Root())
+
+	current, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+	assertRootsEqual(t, working, current)
+}
+
+func TestTransactionCommit(t *testing.T) {
+	ctx := context.Background()
+	dEnv, engine, db, working := transactionEnv(t)
+	people := countPeople(t, engine)
+
+	// the working set is unchanged, so the database's root is committed as is
+	tx := BeginTransaction(db, working)
+	runQuery(t, engine, "insert into people (id, first, last) values (10, 'Ned', 'Flanders')")
+	merged, err := tx.Commit(ctx, working)
+	require.NoError(t, err)
+	assert.Equal(t, db.Root(), merged)
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, merged))
+
+	// writes made before the transaction began are committed along with the ones made during it, and merged with the
+	// changes made to the working set by another process
+	working = merged
+	runQuery(t, engine, "insert into people (id, first, last) values (11, 'Maude', 'Flanders')")
+	tx = BeginTransaction(db, working)
+	runQuery(t, engine, "update people set first = 'Homie' where id = 0")
+	updateWorkingSet(t, dEnv, "insert into people (id, first, last) values (12, 'Rod', 'Flanders')")
+
+	current, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	merged, err = tx.Commit(ctx, current)
+	require.NoError(t, err)
+	assert.Equal(t, merged, db.Root())
+	assert.Equal(t, people+3, countPeople(t, engine))
+	rows := queryRows(t, engine, "select first from people where id = 0")
+	assert.Equal(t, []sql.Row{{"Homie"}}, rows)
+}
+
+func TestTransactionCommitConflict(t *testing.T) {
+	ctx := context.Background()
+	dEnv, engine, db, working := transactionEnv(t)
+
+	tx := BeginTransaction(db, working)
+	runQuery(t, engine, "update people set first = 'Homie' where id = 0")
+	updateWorkingSet(t, dEnv, "update people set first = 'Max' where id = 0")
+	root := db.Root()
+
+	current, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	_, err = tx.Commit(ctx, current)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "people")
+
+	// the transaction is left open, so it can still be rolled back
+	assert.Equal(t, root, db.Root())
+	tx.Rollback()
+	assert.Equal(t, working, db.Root())
+}
+
+func transactionEnv(t *testing.T) (*env.DoltEnv, *sqle.Engine, *Database, *doltdb.RootValue) {
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	engine := NewEngine(CaseSensitive)
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	engine.AddDatabase(db)
+
+	return dEnv, engine, db, root
+}
+
+// updateWorkingSet runs the statement given against the working set and writes the result, as another process would.
+func updateWorkingSet(t *testing.T, dEnv *env.DoltEnv, query string) {
+	ctx := context.Background()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	root, err = executeModify(ctx, root, query)
+	require.NoError(t, err)
+	require.NoError(t, dEnv.UpdateWorkingRoot(ctx, root))
+}
+
+func runQuery(t *testing.T, engine *sqle.Engine, query string) {
+	_, err := queryRowCount(sql.NewEmptyContext(), engine.Query, query)
+	require.NoError(t, err, query)
+}
+
+func assertRootsEqual(t *testing.T, expected, actual *doltdb.RootValue) {
+	equal, err := rootsEqual(expected, actual)
+	require.NoError(t, err)
+	assert.True(t, equal)
+}
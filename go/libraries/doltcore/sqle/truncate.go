@@ -0,0 +1,47 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+var ErrTruncateSystemTableFmt = "table %s is a system table and cannot be truncated"
+
+// ExecuteTruncateTable executes the TRUNCATE TABLE statement given, replacing the rows of the table named with an empty
+// row map in the database's root. The table's schema and indexes are kept. This is much faster than deleting every row,
+// as the old rows aren't read. The table name is matched case insensitively, and generated system tables can't be
+// truncated. Dolt tables have no auto increment counters, so there are none to reset.
+//
+// The engine doesn't support TRUNCATE, which is why this is handled here rather than by the engine.
+func ExecuteTruncateTable(ctx *sql.Context, db *Database, ddl *sqlparser.DDL) error {
+	name := ddl.Table.Name.String()
+	tbl, ok, err := db.GetTableInsensitive(ctx, name)
+
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrTableNotFound.New(name)
+	}
+
+	if _, ok := tbl.(*DoltTable); !ok {
+		return fmt.Errorf(ErrTruncateSystemTableFmt, tbl.Name())
+	}
+
+	return db.TruncateTable(ctx, tbl.Name())
+}
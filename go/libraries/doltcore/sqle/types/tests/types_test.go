@@ -139,8 +139,8 @@ func runServer(t *testing.T) (*dbr.Connection, *sqlserver.ServerController) {
 	require.NoError(t, err)
 	serverConfig := sqlserver.DefaultServerConfig().WithPort(16000 + int(port.Int64()))
 	go func() {
-		root, _ := getEmptyRoot()
-		_, _ = sqlserver.Serve(context.Background(), serverConfig, root, serverController)
+		_, dEnv := getEmptyRoot()
+		_, _ = sqlserver.Serve(context.Background(), serverConfig, dEnv, serverController)
 	}()
 	err = serverController.WaitForStart()
 	require.NoError(t, err)
@@ -0,0 +1,134 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/expression/function"
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// unnestFunctions are the functions expanding JSON arrays added to the engine's catalog by NewEngine.
+var unnestFunctions = []sql.Function{
+	sql.Function1{Name: "unnest", Fn: NewUnnest},
+	sql.Function1{Name: "json_array_elements", Fn: NewJSONArrayElements},
+}
+
+// ErrNotJSONArray is returned when the text given to UNNEST or JSON_ARRAY_ELEMENTS isn't a JSON array.
+var ErrNotJSONArray = errors.NewKind("%s expects a JSON array, got '%s'")
+
+// NewUnnest returns a new UNNEST(json_array) expression, which generates a row for each element of the JSON array
+// given, in the same way as EXPLODE does for arrays. The rest of the row is repeated for each element, and rows whose
+// array is NULL or empty produce no rows at all. Like EXPLODE, it can only be used once in a select list.
+func NewUnnest(arg sql.Expression) sql.Expression {
+	return function.NewExplode(&JSONArrayElements{name: "UNNEST", arg: arg})
+}
+
+// JSONArrayElements is the JSON_ARRAY_ELEMENTS(json_array) function, which converts the text of a JSON array, such as
+// a string column holding imported semi-structured data, to an array of its elements. String elements are unquoted,
+// null elements are NULL, and any other element is given as its JSON text. Arrays are passed through unchanged.
+type JSONArrayElements struct {
+	name string
+	arg  sql.Expression
+}
+
+var _ sql.Expression = (*JSONArrayElements)(nil)
+
+// NewJSONArrayElements returns a new JSON_ARRAY_ELEMENTS expression for the argument given.
+func NewJSONArrayElements(arg sql.Expression) sql.Expression {
+	return &JSONArrayElements{name: "JSON_ARRAY_ELEMENTS", arg: arg}
+}
+
+// Children implements sql.Expression
+func (j *JSONArrayElements) Children() []sql.Expression { return []sql.Expression{j.arg} }
+
+// Type implements sql.Expression
+func (j *JSONArrayElements) Type() sql.Type {
+	if sql.IsArray(j.arg.Type()) {
+		return j.arg.Type()
+	}
+	return sql.CreateArray(sql.Text)
+}
+
+// Resolved implements sql.Expression
+func (j *JSONArrayElements) Resolved() bool { return j.arg.Resolved() }
+
+// IsNullable implements sql.Expression
+func (j *JSONArrayElements) IsNullable() bool { return j.arg.IsNullable() }
+
+// WithChildren implements sql.Expression
+func (j *JSONArrayElements) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(j, len(children), 1)
+	}
+	return &JSONArrayElements{name: j.name, arg: children[0]}, nil
+}
+
+// String implements fmt.Stringer
+func (j *JSONArrayElements) String() string { return fmt.Sprintf("%s(%s)", j.name, j.arg) }
+
+// Eval implements sql.Expression
+func (j *JSONArrayElements) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := j.arg.Eval(ctx, row)
+	if err != nil || val == nil {
+		return nil, err
+	}
+
+	if arr, ok := val.([]interface{}); ok {
+		return arr, nil
+	}
+
+	str, err := sql.Text.Convert(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return jsonArrayElements(j.name, str.(string))
+}
+
+func jsonArrayElements(name, str string) ([]interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(str))
+	dec.UseNumber()
+
+	var elems []json.RawMessage
+	if err := dec.Decode(&elems); err != nil || elems == nil || dec.More() {
+		return nil, ErrNotJSONArray.New(name, str)
+	}
+
+	vals := make([]interface{}, len(elems))
+	for i, elem := range elems {
+		elem = bytes.TrimSpace(elem)
+
+		switch {
+		case bytes.Equal(elem, []byte("null")):
+			vals[i] = nil
+		case len(elem) > 0 && elem[0] == '"':
+			var s string
+			if err := json.Unmarshal(elem, &s); err != nil {
+				return nil, err
+			}
+			vals[i] = s
+		default:
+			vals[i] = string(elem)
+		}
+	}
+
+	return vals, nil
+}
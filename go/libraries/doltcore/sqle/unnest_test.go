@@ -0,0 +1,85 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestUnnest(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, `
+create table items (id int, tags varchar(1023), primary key(id));
+insert into items values (1, '["red", "blue"]'), (2, '[]'), (3, null), (4, '[7, {"a": 1}, null, "x,y"]');
+`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []sql.Row
+	}{
+		{
+			name:  "unnest column",
+			query: "select id, unnest(tags) as tag from items order by id",
+			expected: []sql.Row{
+				{int64(1), "red"},
+				{int64(1), "blue"},
+				{int64(4), "7"},
+				{int64(4), `{"a": 1}`},
+				{int64(4), nil},
+				{int64(4), "x,y"},
+			},
+		},
+		{
+			name:     "unnest literal",
+			query:    `select unnest('["a", "b"]') as elem`,
+			expected: []sql.Row{{"a"}, {"b"}},
+		},
+		{
+			name:     "explode json_array_elements",
+			query:    "select id, explode(json_array_elements(tags)) as tag from items where id = 1",
+			expected: []sql.Row{{int64(1), "red"}, {int64(1), "blue"}},
+		},
+		{
+			name:     "unnest array",
+			query:    "select unnest(split('a,b,c', ',')) as elem",
+			expected: []sql.Row{{"a"}, {"b"}, {"c"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows, err := ExecuteSelect(root, test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, rows)
+		})
+	}
+
+	_, err = ExecuteSelect(root, `select unnest('{"a": 1}')`)
+	assert.True(t, ErrNotJSONArray.Is(err))
+	_, err = ExecuteSelect(root, `select unnest('not json')`)
+	assert.True(t, ErrNotJSONArray.Is(err))
+}
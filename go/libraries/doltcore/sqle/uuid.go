@@ -0,0 +1,243 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/src-d/go-mysql-server/sql"
+)
+
+// uuidFunctions are the UUID functions added to the engine's catalog by NewEngine.
+var uuidFunctions = []sql.Function{
+	sql.Function0{Name: "uuid", Fn: NewUUIDFunc},
+	sql.FunctionN{Name: "uuid_to_bin", Fn: NewUUIDToBin},
+	sql.FunctionN{Name: "bin_to_uuid", Fn: NewBinToUUID},
+}
+
+// UUIDFunc is the UUID() function, which returns a new random UUID in its string form each time it's evaluated.
+type UUIDFunc struct{}
+
+var _ sql.Expression = UUIDFunc{}
+
+// NewUUIDFunc returns a new UUID() expression.
+func NewUUIDFunc() sql.Expression {
+	return UUIDFunc{}
+}
+
+// Children implements sql.Expression
+func (UUIDFunc) Children() []sql.Expression { return nil }
+
+// Type implements sql.Expression
+func (UUIDFunc) Type() sql.Type { return sql.Text }
+
+// Resolved implements sql.Expression
+func (UUIDFunc) Resolved() bool { return true }
+
+// IsNullable implements sql.Expression
+func (UUIDFunc) IsNullable() bool { return false }
+
+// WithChildren implements sql.Expression
+func (u UUIDFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(u, len(children), 0)
+	}
+	return u, nil
+}
+
+// String implements fmt.Stringer
+func (UUIDFunc) String() string { return "UUID()" }
+
+// Eval implements sql.Expression
+func (UUIDFunc) Eval(*sql.Context, sql.Row) (interface{}, error) {
+	return uuid.New().String(), nil
+}
+
+// uuidConversion is the implementation shared by UUID_TO_BIN and BIN_TO_UUID. Both take an optional swap flag which,
+// when true, moves the time-high part of the UUID to the front of the binary form so that time based UUIDs are stored
+// in an index friendly order, as in MySQL.
+type uuidConversion struct {
+	name  string
+	arg   sql.Expression
+	swap  sql.Expression
+	toBin bool
+}
+
+// UUIDToBin is the UUID_TO_BIN(uuid[, swap_flag]) function, which converts a UUID string to its 16 byte binary form.
+type UUIDToBin struct {
+	uuidConversion
+}
+
+// BinToUUID is the BIN_TO_UUID(bin[, swap_flag]) function, which converts the 16 byte binary form of a UUID back to
+// its string form.
+type BinToUUID struct {
+	uuidConversion
+}
+
+var _ sql.Expression = (*UUIDToBin)(nil)
+var _ sql.Expression = (*BinToUUID)(nil)
+
+// NewUUIDToBin returns a new UUID_TO_BIN expression for the arguments given.
+func NewUUIDToBin(args ...sql.Expression) (sql.Expression, error) {
+	conv, err := newUUIDConversion("UUID_TO_BIN", true, args)
+	if err != nil {
+		return nil, err
+	}
+	return &UUIDToBin{conv}, nil
+}
+
+// NewBinToUUID returns a new BIN_TO_UUID expression for the arguments given.
+func NewBinToUUID(args ...sql.Expression) (sql.Expression, error) {
+	conv, err := newUUIDConversion("BIN_TO_UUID", false, args)
+	if err != nil {
+		return nil, err
+	}
+	return &BinToUUID{conv}, nil
+}
+
+func newUUIDConversion(name string, toBin bool, args []sql.Expression) (uuidConversion, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return uuidConversion{}, sql.ErrInvalidArgumentNumber.New(name, "1 or 2", len(args))
+	}
+
+	conv := uuidConversion{name: name, arg: args[0], toBin: toBin}
+	if len(args) == 2 {
+		conv.swap = args[1]
+	}
+
+	return conv, nil
+}
+
+// WithChildren implements sql.Expression
+func (u *UUIDToBin) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != len(u.Children()) {
+		return nil, sql.ErrInvalidChildrenNumber.New(u, len(children), len(u.Children()))
+	}
+	return NewUUIDToBin(children...)
+}
+
+// WithChildren implements sql.Expression
+func (b *BinToUUID) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != len(b.Children()) {
+		return nil, sql.ErrInvalidChildrenNumber.New(b, len(children), len(b.Children()))
+	}
+	return NewBinToUUID(children...)
+}
+
+// Children implements sql.Expression
+func (c uuidConversion) Children() []sql.Expression {
+	if c.swap == nil {
+		return []sql.Expression{c.arg}
+	}
+	return []sql.Expression{c.arg, c.swap}
+}
+
+// Type implements sql.Expression
+func (c uuidConversion) Type() sql.Type {
+	if c.toBin {
+		return sql.Blob
+	}
+	return sql.Text
+}
+
+// Resolved implements sql.Expression
+func (c uuidConversion) Resolved() bool {
+	return c.arg.Resolved() && (c.swap == nil || c.swap.Resolved())
+}
+
+// IsNullable implements sql.Expression
+func (c uuidConversion) IsNullable() bool {
+	return c.arg.IsNullable()
+}
+
+// String implements fmt.Stringer
+func (c uuidConversion) String() string {
+	if c.swap == nil {
+		return fmt.Sprintf("%s(%s)", c.name, c.arg)
+	}
+	return fmt.Sprintf("%s(%s, %s)", c.name, c.arg, c.swap)
+}
+
+// Eval implements sql.Expression
+func (c uuidConversion) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	val, err := c.arg.Eval(ctx, row)
+	if err != nil || val == nil {
+		return nil, err
+	}
+
+	swap := false
+	if c.swap != nil {
+		swapVal, err := c.swap.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+
+		if swapVal != nil {
+			swapVal, err = sql.Int64.Convert(swapVal)
+			if err != nil {
+				return nil, err
+			}
+			swap = swapVal.(int64) != 0
+		}
+	}
+
+	if c.toBin {
+		str, err := sql.Text.Convert(val)
+		if err != nil {
+			return nil, err
+		}
+
+		u, err := uuid.Parse(str.(string))
+		if err != nil {
+			return nil, fmt.Errorf("incorrect string value '%v' for function %s", val, c.name)
+		}
+
+		if swap {
+			return string(swapUUIDTimeFields(u)), nil
+		}
+		return string(u[:]), nil
+	}
+
+	bin, err := sql.Blob.Convert(val)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := uuid.FromBytes([]byte(bin.(string)))
+	if err != nil {
+		return nil, fmt.Errorf("incorrect binary value for function %s, expected 16 bytes", c.name)
+	}
+
+	if swap {
+		var unswapped uuid.UUID
+		copy(unswapped[0:4], u[4:8])
+		copy(unswapped[4:6], u[2:4])
+		copy(unswapped[6:8], u[0:2])
+		copy(unswapped[8:], u[8:])
+		u = unswapped
+	}
+
+	return u.String(), nil
+}
+
+// swapUUIDTimeFields returns the binary form of the UUID given with its time-high and time-low fields swapped.
+func swapUUIDTimeFields(u uuid.UUID) []byte {
+	swapped := make([]byte, 0, len(u))
+	swapped = append(swapped, u[6:8]...)
+	swapped = append(swapped, u[4:6]...)
+	swapped = append(swapped, u[0:4]...)
+	return append(swapped, u[8:]...)
+}
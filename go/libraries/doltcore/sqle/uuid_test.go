@@ -0,0 +1,164 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestUUIDFunctions(t *testing.T) {
+	const uuidStr = "6ccd780c-baba-1026-9564-5b8c656024db"
+
+	tests := []struct {
+		name     string
+		query    string
+		expected interface{}
+	}{
+		{
+			name:     "uuid_to_bin",
+			query:    "select uuid_to_bin('" + uuidStr + "')",
+			expected: "6ccd780cbaba102695645b8c656024db",
+		},
+		{
+			name:     "uuid_to_bin swapped",
+			query:    "select uuid_to_bin('" + uuidStr + "', 1)",
+			expected: "1026baba6ccd780c95645b8c656024db",
+		},
+		{
+			name:     "bin_to_uuid",
+			query:    "select bin_to_uuid(uuid_to_bin('" + uuidStr + "'))",
+			expected: uuidStr,
+		},
+		{
+			name:     "bin_to_uuid swapped",
+			query:    "select bin_to_uuid(uuid_to_bin('" + uuidStr + "', 1), 1)",
+			expected: uuidStr,
+		},
+		{
+			name:     "uuid_to_bin null",
+			query:    "select uuid_to_bin(null)",
+			expected: nil,
+		},
+	}
+
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rows, err := ExecuteSelect(root, test.query)
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+
+			actual := rows[0][0]
+			if bin, ok := actual.(string); ok && len(bin) == 16 {
+				actual = hex.EncodeToString([]byte(bin))
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	rows, err := ExecuteSelect(root, "select uuid(), uuid()")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	first, err := uuid.Parse(rows[0][0].(string))
+	require.NoError(t, err)
+	second, err := uuid.Parse(rows[0][1].(string))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	_, err = ExecuteSelect(root, "select uuid_to_bin('not a uuid')")
+	assert.Error(t, err)
+}
+
+func TestInsertUUIDKeyDefault(t *testing.T) {
+	colColl, err := schema.NewColCollection(
+		schema.NewColumn("id", 0, types.UUIDKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("name", 1, types.StringKind, false),
+	)
+	require.NoError(t, err)
+	sch := schema.SchemaFromCols(colColl)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{
+			name:  "values",
+			query: "insert into keyless (name) values ('Homer'), ('Marge')",
+		},
+		{
+			name:  "select",
+			query: "insert into keyless (name) select first from people where first in ('Homer', 'Marge')",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			dEnv := dtestutils.CreateTestEnv()
+			dtestutils.CreateTestTable(t, dEnv, "people", PeopleTestSchema, Homer, Marge)
+			dtestutils.CreateTestTable(t, dEnv, "keyless", sch)
+
+			root, err := dEnv.WorkingRoot(ctx)
+			require.NoError(t, err)
+
+			root, err = ExecuteSql(dEnv, root, test.query)
+			require.NoError(t, err)
+
+			rows, err := ExecuteSelect(root, "select id, name from keyless order by name")
+			require.NoError(t, err)
+			require.Len(t, rows, 2)
+			assert.Equal(t, "Homer", rows[0][1])
+			assert.Equal(t, "Marge", rows[1][1])
+
+			var ids []uuid.UUID
+			for _, r := range rows {
+				id, err := uuid.Parse(r[0].(string))
+				require.NoError(t, err)
+				ids = append(ids, id)
+			}
+			assert.NotEqual(t, ids[0], ids[1])
+		})
+	}
+
+	// An explicitly given key is used as is
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	dtestutils.CreateTestTable(t, dEnv, "keyless", sch)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	root, err = ExecuteSql(dEnv, root, "insert into keyless (id, name) values ('6ccd780c-baba-1026-9564-5b8c656024db', 'Bart')")
+	require.NoError(t, err)
+
+	rows, err := ExecuteSelect(root, "select id from keyless")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"6ccd780c-baba-1026-9564-5b8c656024db"}}, rows)
+}
@@ -0,0 +1,320 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/parse"
+	"github.com/src-d/go-mysql-server/sql/plan"
+	"vitess.io/vitess/go/vt/sqlparser"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+)
+
+const (
+	loadViewsRuleName    = "dolt_load_views"
+	persistViewsRuleName = "dolt_persist_views"
+	showViewsRuleName    = "dolt_show_views"
+)
+
+// ErrViewQueryUnknown is returned when a view is created by a context without the text of its query, which is needed
+// to store the view's definition.
+var ErrViewQueryUnknown = errors.New("the text of the CREATE VIEW statement is needed to store the view, but the query context doesn't have it")
+
+// loadViews is an analyzer rule that registers the views defined in the dolt_schemas table of each dolt database with
+// the catalog's view registry, whenever the table has changed since the database's views were last registered. It
+// runs before the engine's view resolution, so queries always see the views of the database's current root.
+func loadViews(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	for _, sqlDb := range a.Catalog.AllDatabases() {
+		if db, ok := sqlDb.(*Database); ok {
+			if err := db.loadViews(ctx, a.Catalog.ViewRegistry); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// loadViews replaces the views of the database in the registry given with the views defined in its root, if its
+// dolt_schemas table has changed since they were last loaded. Each view is registered with the unresolved plan of its
+// select statement, which the engine resolves against the database's tables for each query that uses it.
+func (db *Database) loadViews(ctx *sql.Context, registry *sql.ViewRegistry) error {
+	root := db.rootFor(ctx)
+	h, _, err := root.GetTableHash(ctx, doltdb.SchemasTableName)
+	if err != nil {
+		return err
+	}
+
+	if db.viewsLoaded && h == db.viewsHash {
+		return nil
+	}
+
+	frags, err := root.GetSchemaFragments(ctx, doltdb.ViewFragmentType)
+	if err != nil {
+		return err
+	}
+
+	views := make([]sql.View, len(frags))
+	for i, frag := range frags {
+		definition, err := parse.Parse(ctx, frag.Fragment)
+		if err != nil {
+			return fmt.Errorf("error parsing the definition of view %s: %v", frag.Name, err)
+		}
+
+		views[i] = sql.NewView(frag.Name, plan.NewSubqueryAlias(frag.Name, definition))
+	}
+
+	for _, view := range registry.ViewsInDatabase(db.name) {
+		if err := registry.Delete(db.name, view.Name()); err != nil {
+			return err
+		}
+	}
+
+	for _, view := range views {
+		if err := registry.Register(db.name, view); err != nil {
+			return err
+		}
+	}
+
+	db.viewsHash = h
+	db.viewsLoaded = true
+	return nil
+}
+
+// persistViews is an analyzer rule that makes CREATE VIEW and DROP VIEW statements against dolt databases store their
+// changes in the database's dolt_schemas table, so that views are versioned along with the tables they select from.
+func persistViews(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	switch n := n.(type) {
+	case *plan.CreateView:
+		if db, ok := n.Database().(*Database); ok {
+			return &createView{n, db}, nil
+		}
+	case *plan.DropView:
+		var dbs []*Database
+		for _, child := range n.Children() {
+			if databaser, ok := child.(sql.Databaser); ok {
+				if db, ok := databaser.Database().(*Database); ok {
+					dbs = append(dbs, db)
+				}
+			}
+		}
+
+		if len(dbs) > 0 {
+			return &dropView{n, dbs}, nil
+		}
+	}
+
+	return n, nil
+}
+
+// createView is a CREATE VIEW statement that stores the view it creates in the dolt_schemas table of a database.
+type createView struct {
+	*plan.CreateView
+	db *Database
+}
+
+// RowIter implements sql.Node. The view is created by the engine, then its select statement is stored in the database.
+func (cv *createView) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	fragment, err := viewFragment(ctx.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	root := cv.db.rootFor(ctx)
+	if has, err := root.HasTable(ctx, cv.Name); err != nil {
+		return nil, err
+	} else if has {
+		return nil, sql.ErrTableAlreadyExists.New(cv.Name)
+	}
+
+	iter, err := cv.CreateView.RowIter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newRoot, err := root.PutSchemaFragment(ctx, doltdb.SchemaFragment{Type: doltdb.ViewFragmentType, Name: cv.Name, Fragment: fragment})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cv.db.putViews(ctx, newRoot, cv.Catalog.ViewRegistry); err != nil {
+		return nil, err
+	}
+
+	return iter, nil
+}
+
+// WithChildren implements sql.Node
+func (cv *createView) WithChildren(children ...sql.Node) (sql.Node, error) {
+	n, err := cv.CreateView.WithChildren(children...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createView{n.(*plan.CreateView), cv.db}, nil
+}
+
+// dropView is a DROP VIEW statement that removes the views it drops from the dolt_schemas tables of their databases.
+type dropView struct {
+	*plan.DropView
+	dbs []*Database
+}
+
+// RowIter implements sql.Node. The views are dropped by the engine, then each database's stored views that are no
+// longer registered are removed.
+func (dv *dropView) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	iter, err := dv.DropView.RowIter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := dv.Catalog.ViewRegistry
+	for _, db := range dv.dbs {
+		root := db.rootFor(ctx)
+		frags, err := root.GetSchemaFragments(ctx, doltdb.ViewFragmentType)
+		if err != nil {
+			return nil, err
+		}
+
+		newRoot := root
+		for _, frag := range frags {
+			if _, err := registry.View(db.name, frag.Name); sql.ErrNonExistingView.Is(err) {
+				newRoot, _, err = newRoot.RemoveSchemaFragment(ctx, doltdb.ViewFragmentType, frag.Name)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if newRoot != root {
+			if err := db.putViews(ctx, newRoot, registry); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return iter, nil
+}
+
+// WithChildren implements sql.Node
+func (dv *dropView) WithChildren(children ...sql.Node) (sql.Node, error) {
+	n, err := dv.DropView.WithChildren(children...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dropView{n.(*plan.DropView), dv.dbs}, nil
+}
+
+// showViews is an analyzer rule that makes SHOW TABLES list the views of dolt databases along with their tables, as
+// MySQL does. SHOW FULL TABLES gives their type as VIEW.
+func showViews(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	if st, ok := n.(*plan.ShowTables); ok {
+		if db, ok := st.Database().(*Database); ok {
+			return &showTablesAndViews{st, db, a.Catalog.ViewRegistry}, nil
+		}
+	}
+
+	return n, nil
+}
+
+// showTablesAndViews is a SHOW TABLES statement that lists the views registered for a database along with its tables.
+type showTablesAndViews struct {
+	*plan.ShowTables
+	db       *Database
+	registry *sql.ViewRegistry
+}
+
+// RowIter implements sql.Node. Tables and views are listed together in order of name.
+func (st *showTablesAndViews) RowIter(ctx *sql.Context) (sql.RowIter, error) {
+	tableNames, err := st.db.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tableTypes := make(map[string]string)
+	for _, name := range tableNames {
+		tableTypes[name] = "BASE TABLE"
+	}
+
+	for _, view := range st.registry.ViewsInDatabase(st.db.name) {
+		tableTypes[view.Name()] = "VIEW"
+	}
+
+	names := make([]string, 0, len(tableTypes))
+	for name := range tableTypes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	rows := make([]sql.Row, len(names))
+	for i, name := range names {
+		rows[i] = sql.Row{name}
+		if st.Full {
+			rows[i] = append(rows[i], tableTypes[name])
+		}
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// WithChildren implements sql.Node
+func (st *showTablesAndViews) WithChildren(children ...sql.Node) (sql.Node, error) {
+	n, err := st.ShowTables.WithChildren(children...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &showTablesAndViews{n.(*plan.ShowTables), st.db, st.registry}, nil
+}
+
+// putViews sets the database's root to the one given, whose views have changed, and registers its views in place of
+// the ones registered by the engine.
+func (db *Database) putViews(ctx *sql.Context, newRoot *doltdb.RootValue, registry *sql.ViewRegistry) error {
+	db.setRootFor(ctx, newRoot)
+
+	if err := db.loadViews(ctx, registry); err != nil {
+		return err
+	}
+
+	return db.written(ctx)
+}
+
+// viewFragment returns the select statement of the CREATE VIEW statement given, which is stored as the definition of
+// the view.
+func viewFragment(query string) (string, error) {
+	if query == "" {
+		return "", ErrViewQueryUnknown
+	}
+
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return "", err
+	}
+
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.ViewExpr == nil {
+		return "", ErrViewQueryUnknown
+	}
+
+	return sqlparser.String(ddl.ViewExpr), nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	sqle "github.com/src-d/go-mysql-server"
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/env"
+	. "github.com/liquidata-inc/dolt/go/libraries/doltcore/sql/sqltestutil"
+)
+
+func TestViews(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine, db := viewsEngine(dEnv, root)
+
+	oldPeople := queryRows(t, engine, "select first from people where age >= 40")
+	require.NotEmpty(t, oldPeople)
+
+	queryRows(t, engine, "create view old_people as select first from people where age >= 40")
+	assert.Equal(t, oldPeople, queryRows(t, engine, "select * from old_people"))
+
+	frag, ok, err := db.Root().GetSchemaFragment(ctx, doltdb.ViewFragmentType, "old_people")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "select first from people where age >= 40", frag.Fragment)
+
+	// views see the rows written after they were created
+	queryRows(t, engine, "insert into people (id, first, last, age) values (10, 'Abe', 'Simpson', 80)")
+	assert.Len(t, queryRows(t, engine, "select * from old_people"), len(oldPeople)+1)
+
+	// and are loaded from the root by engines created later
+	engine2, _ := viewsEngine(dEnv, db.Root())
+	assert.Len(t, queryRows(t, engine2, "select * from old_people"), len(oldPeople)+1)
+
+	_, _, err = engine.Query(sql.NewContext(ctx, sql.WithQuery("create view people as select 1")), "create view people as select 1")
+	assert.True(t, sql.ErrTableAlreadyExists.Is(err))
+
+	_, _, err = engine.Query(sql.NewContext(ctx), "create view other as select 1")
+	assert.Equal(t, ErrViewQueryUnknown, err)
+
+	created := db.Root()
+	queryRows(t, engine, "drop view old_people")
+	_, ok, err = db.Root().GetSchemaFragment(ctx, doltdb.ViewFragmentType, "old_people")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = engine.Query(sql.NewContext(ctx), "select * from old_people")
+	assert.Error(t, err)
+
+	// the root the view was created in still has it
+	engine3, _ := viewsEngine(dEnv, created)
+	assert.Len(t, queryRows(t, engine3, "select * from old_people"), len(oldPeople)+1)
+}
+
+func TestShowTablesListsViews(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	CreateTestDatabase(dEnv, t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	engine, _ := viewsEngine(dEnv, root)
+	assert.Equal(t, []sql.Row{{"appearances"}, {"episodes"}, {"people"}}, queryRows(t, engine, "show tables"))
+
+	queryRows(t, engine, "create view old_people as select first from people where age >= 40")
+	queryRows(t, engine, "create view a_view as select 1")
+
+	// the views are listed in order of name along with their dolt_schemas table
+	expected := []sql.Row{
+		{"a_view", "VIEW"},
+		{"appearances", "BASE TABLE"},
+		{doltdb.SchemasTableName, "BASE TABLE"},
+		{"episodes", "BASE TABLE"},
+		{"old_people", "VIEW"},
+		{"people", "BASE TABLE"},
+	}
+	assert.Equal(t, expected, queryRows(t, engine, "show full tables"))
+	assert.Len(t, queryRows(t, engine, "show tables"), len(expected))
+
+	queryRows(t, engine, "drop view a_view")
+	assert.Equal(t, expected[1:], queryRows(t, engine, "show full tables"))
+}
+
+func viewsEngine(dEnv *env.DoltEnv, root *doltdb.RootValue) (*sqle.Engine, *Database) {
+	engine := NewEngine(CaseSensitive)
+	db := NewDatabase("dolt", root, dEnv.DoltDB, dEnv.RepoState)
+	engine.AddDatabase(db)
+	return engine, db
+}
+
+func queryRows(t *testing.T, engine *sqle.Engine, query string) []sql.Row {
+	_, iter, err := engine.Query(sql.NewContext(context.Background(), sql.WithQuery(query)), query)
+	require.NoError(t, err, query)
+	rows, err := sql.RowIterToRows(iter)
+	require.NoError(t, err, query)
+	return rows
+}
@@ -0,0 +1,274 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/analyzer"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/src-d/go-mysql-server/sql/plan"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const zoneMapsRuleName = "dolt_zone_maps"
+
+type zoneOp int
+
+const (
+	zoneEq zoneOp = iota
+	zoneLt
+	zoneLte
+	zoneGt
+	zoneGte
+)
+
+// zonePredicate is a comparison of a column with a constant, which a row must satisfy to be returned by a query. A leaf
+// chunk of row data whose zone map shows that none of its rows can satisfy it doesn't need to be read.
+type zonePredicate struct {
+	tag uint64
+	op  zoneOp
+	val types.Value
+}
+
+// applyZoneMaps is an analyzer rule that finds the comparisons of columns with literals in the filters of table scans,
+// and gives them to the tables, which use them to skip the chunks of rows that their zone maps show can't match. The
+// filters are left in place, and still evaluated for the rows that are read.
+func applyZoneMaps(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node) (sql.Node, error) {
+	// Statements that modify a table must use the table held by the database, which owns the editor of batched
+	// modifications, so they are left as they are.
+	switch n.(type) {
+	case *plan.InsertInto, *plan.Update, *plan.DeleteFrom:
+		return n, nil
+	}
+
+	return plan.TransformUp(n, func(node sql.Node) (sql.Node, error) {
+		f, ok := node.(*plan.Filter)
+		if !ok {
+			return node, nil
+		}
+
+		switch child := f.Child.(type) {
+		case *plan.ResolvedTable:
+			if t, ok := child.Table.(*DoltTable); ok {
+				return plan.NewFilter(f.Expression, plan.NewResolvedTable(t.withZonePredicates(f.Expression))), nil
+			}
+		case *plan.TableAlias:
+			if rt, ok := child.Child.(*plan.ResolvedTable); ok {
+				if t, ok := rt.Table.(*DoltTable); ok {
+					aliased := plan.NewTableAlias(child.Name(), plan.NewResolvedTable(t.withZonePredicates(f.Expression)))
+					return plan.NewFilter(f.Expression, aliased), nil
+				}
+			}
+		}
+
+		return node, nil
+	})
+}
+
+// withZonePredicates returns a copy of the table that skips the chunks of rows that can't satisfy the filter given,
+// or the table itself if the filter has no comparisons that zone maps can be used for.
+func (t *DoltTable) withZonePredicates(filter sql.Expression) *DoltTable {
+	var preds []zonePredicate
+	for _, e := range splitConjunction(filter) {
+		if pred, ok := t.zonePredicate(e); ok {
+			preds = append(preds, pred)
+		}
+	}
+
+	if len(preds) == 0 {
+		return t
+	}
+
+	nt := *t
+	nt.zonePreds = preds
+	return &nt
+}
+
+func splitConjunction(e sql.Expression) []sql.Expression {
+	if and, ok := e.(*expression.And); ok {
+		return append(splitConjunction(and.Left), splitConjunction(and.Right)...)
+	}
+	return []sql.Expression{e}
+}
+
+// zonePredicate returns the zonePredicate for the expression given, if it's a comparison of one of the table's
+// columns with a literal whose value compares the same way in noms as in SQL.
+func (t *DoltTable) zonePredicate(e sql.Expression) (zonePredicate, bool) {
+	var op zoneOp
+	switch e.(type) {
+	case *expression.Equals:
+		op = zoneEq
+	case *expression.LessThan:
+		op = zoneLt
+	case *expression.LessThanOrEqual:
+		op = zoneLte
+	case *expression.GreaterThan:
+		op = zoneGt
+	case *expression.GreaterThanOrEqual:
+		op = zoneGte
+	default:
+		return zonePredicate{}, false
+	}
+
+	c := e.(expression.Comparer)
+	field, ok := c.Left().(*expression.GetField)
+	if !ok {
+		return zonePredicate{}, false
+	}
+
+	lit, ok := c.Right().(*expression.Literal)
+	if !ok {
+		return zonePredicate{}, false
+	}
+
+	allCols := t.sch.GetAllCols()
+	if field.Index() < 0 || field.Index() >= allCols.Size() {
+		return zonePredicate{}, false
+	}
+
+	col := allCols.GetByIndex(field.Index())
+	if !strings.EqualFold(col.Name, field.Name()) {
+		return zonePredicate{}, false
+	}
+
+	val, ok := zoneMapValue(col, lit)
+	if !ok {
+		return zonePredicate{}, false
+	}
+
+	return zonePredicate{col.Tag, op, val}, true
+}
+
+// zoneMapValue returns the noms value of the literal given for the column given. Only literals that the engine
+// compares with the column's values in the same order as noms does are converted: integers for integer columns,
+// numbers for float columns and strings for string columns. Columns of a custom type are compared by the type, and
+// are never converted.
+func zoneMapValue(col schema.Column, lit *expression.Literal) (types.Value, bool) {
+	if lit.Value() == nil || col.TypeName != "" {
+		return nil, false
+	}
+
+	litType := lit.Type()
+	switch col.Kind {
+	case types.IntKind:
+		if sql.IsSigned(litType) {
+			if v, err := sql.Int64.Convert(lit.Value()); err == nil {
+				return types.Int(v.(int64)), true
+			}
+		}
+	case types.UintKind:
+		if sql.IsUnsigned(litType) {
+			if v, err := sql.Uint64.Convert(lit.Value()); err == nil {
+				return types.Uint(v.(uint64)), true
+			}
+		}
+	case types.FloatKind:
+		if sql.IsNumber(litType) {
+			if v, err := sql.Float64.Convert(lit.Value()); err == nil {
+				return types.Float(v.(float64)), true
+			}
+		}
+	case types.StringKind:
+		if sql.IsText(litType) {
+			if v, ok := lit.Value().(string); ok {
+				return types.String(v), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// mayMatch returns whether any of the rows of a chunk with the zone map given may satisfy the predicate.
+func (p zonePredicate) mayMatch(nbf *types.NomsBinFormat, zm *doltdb.ZoneMap) (bool, error) {
+	if zm == nil {
+		return true, nil
+	}
+
+	min, ok := zm.Min[p.tag]
+	if !ok || min.Kind() != p.val.Kind() {
+		return true, nil
+	}
+
+	max := zm.Max[p.tag]
+	switch p.op {
+	case zoneEq:
+		if less, err := p.val.Less(nbf, min); err != nil || less {
+			return false, err
+		}
+		less, err := max.Less(nbf, p.val)
+		return !less, err
+	case zoneLt:
+		less, err := min.Less(nbf, p.val)
+		return less, err
+	case zoneLte:
+		less, err := p.val.Less(nbf, min)
+		return !less, err
+	case zoneGt:
+		less, err := p.val.Less(nbf, max)
+		return less, err
+	case zoneGte:
+		less, err := max.Less(nbf, p.val)
+		return !less, err
+	}
+
+	return true, nil
+}
+
+// zoneMatchingLeaves returns the hashes of the leaf chunks of the table's row data that may hold rows satisfying all of
+// its zone predicates, in primary key order, and false if the table doesn't have zone maps.
+func (t *DoltTable) zoneMatchingLeaves(ctx *sql.Context) ([]hash.Hash, bool, error) {
+	leaves, err := t.table.GetRowDataLeaves(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	nbf := t.table.Format()
+	matching := make([]hash.Hash, 0, len(leaves))
+	hasZones := false
+	for _, leaf := range leaves {
+		hasZones = hasZones || leaf.Zone != nil
+
+		mayMatch := true
+		for _, pred := range t.zonePreds {
+			mayMatch, err = pred.mayMatch(nbf, leaf.Zone)
+
+			if err != nil {
+				return nil, false, err
+			}
+
+			if !mayMatch {
+				break
+			}
+		}
+
+		if mayMatch {
+			matching = append(matching, leaf.Hash)
+		}
+	}
+
+	if !hasZones {
+		return nil, false, nil
+	}
+
+	return matching, true, nil
+}
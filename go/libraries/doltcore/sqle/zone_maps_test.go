@@ -0,0 +1,140 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/src-d/go-mysql-server/sql"
+	"github.com/src-d/go-mysql-server/sql/expression"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+)
+
+const zoneMapTestRows = 2000
+
+func zoneMapTestRoot(t *testing.T) *doltdb.RootValue {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+
+	values := make([]string, zoneMapTestRows)
+	for i := range values {
+		values[i] = fmt.Sprintf("(%d, %d, 'row %04d', %d.5)", i, i%10, i, i)
+	}
+
+	root, err = ExecuteSql(dEnv, root, "create table t (pk int primary key, v int, s varchar(20), f float);\n"+
+		"insert into t values "+strings.Join(values, ", "))
+	require.NoError(t, err)
+
+	return root
+}
+
+func TestZoneMaps(t *testing.T) {
+	root := zoneMapTestRoot(t)
+
+	tbl, ok, err := root.GetTable(context.Background(), "t")
+	require.NoError(t, err)
+	require.True(t, ok)
+	leaves, err := tbl.GetRowDataLeaves(context.Background())
+	require.NoError(t, err)
+	require.True(t, len(leaves) > 1)
+
+	for _, leaf := range leaves {
+		require.NotNil(t, leaf.Zone)
+	}
+
+	first, last := leaves[0].Zone, leaves[len(leaves)-1].Zone
+	sch, err := tbl.GetSchema(context.Background())
+	require.NoError(t, err)
+	pkCol, _ := sch.GetAllCols().GetByName("pk")
+	vCol, _ := sch.GetAllCols().GetByName("v")
+	assert.Equal(t, "0", first.Min[pkCol.Tag].HumanReadableString())
+	assert.Equal(t, fmt.Sprint(zoneMapTestRows-1), last.Max[pkCol.Tag].HumanReadableString())
+	assert.Equal(t, "0", first.Min[vCol.Tag].HumanReadableString())
+	assert.Equal(t, "9", first.Max[vCol.Tag].HumanReadableString())
+
+	tests := []struct {
+		query string
+		rows  int
+	}{
+		{"select * from t where pk >= 1990", 10},
+		{"select * from t where pk > 1990", 9},
+		{"select * from t where pk < 10", 10},
+		{"select * from t where pk <= 10", 11},
+		{"select * from t where 1990 < pk", 9},
+		{"select * from t where pk >= 100 and pk < 120", 20},
+		{"select * from t where pk = 1000 or pk = 1001", 2},
+		{"select * from t where pk > 5000", 0},
+		{"select * from t where v = 3", zoneMapTestRows / 10},
+		{"select * from t where v > 9", 0},
+		{"select * from t where s >= 'row 1995'", 5},
+		{"select * from t where s = 'row 0042'", 1},
+		{"select * from t where f > 1997.5", 2},
+		{"select * from t where f < 2", 2},
+		{"select * from t x where x.pk >= 1990", 10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			rows, err := ExecuteSelect(root, test.query)
+			require.NoError(t, err)
+			assert.Equal(t, test.rows, len(rows))
+		})
+	}
+}
+
+func TestZoneMapsSkipChunks(t *testing.T) {
+	root := zoneMapTestRoot(t)
+	db := NewDatabase("dolt", root, nil, nil)
+	ctx := sql.NewEmptyContext()
+
+	sqlTbl, ok, err := db.GetTableInsensitive(ctx, "t")
+	require.NoError(t, err)
+	require.True(t, ok)
+	tbl := sqlTbl.(*DoltTable)
+
+	leaves, err := tbl.table.GetRowDataLeaves(ctx)
+	require.NoError(t, err)
+
+	pk := expression.NewGetFieldWithTable(0, sql.Int64, "t", "pk", false)
+	filtered := tbl.withZonePredicates(expression.NewGreaterThanOrEqual(pk, expression.NewLiteral(int64(1990), sql.Int64)))
+	require.Len(t, filtered.zonePreds, 1)
+
+	matching, ok, err := filtered.zoneMatchingLeaves(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Len(t, matching, 1)
+	assert.True(t, len(matching) < len(leaves))
+
+	parts, err := filtered.partitions(ctx)
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	itr, err := filtered.PartitionRows(ctx, &parts[0])
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(itr)
+	require.NoError(t, err)
+	assert.True(t, len(rows) >= 10)
+
+	// comparisons with literals that the engine compares differently than noms aren't used
+	assert.Equal(t, tbl, tbl.withZonePredicates(expression.NewLessThan(pk, expression.NewLiteral(2.5, sql.Float64))))
+	assert.Equal(t, tbl, tbl.withZonePredicates(expression.NewLessThan(pk, expression.NewLiteral("10", sql.Text))))
+}
@@ -0,0 +1,388 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kv provides point reads and writes and range scans of the rows of dolt tables by primary key, with values
+// given as Go values, for programs that embed dolt and don't need SQL.
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/row"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	sqltypes "github.com/liquidata-inc/dolt/go/libraries/doltcore/sqle/types"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// ErrKeySize is returned when a key doesn't give a value for each primary key column of a table.
+var ErrKeySize = errors.New("key must have a value for each primary key column")
+
+// Row is a row of a table as its values by column name. Values are Go values such as int64, uint64, float64, string,
+// bool, time.Time and uuid.UUID, which are converted to and from the kinds of the table's columns. Columns that are
+// null are left out of rows that are read, and columns left out of rows that are written are null.
+type Row map[string]interface{}
+
+// Store reads and writes the rows of the tables in a root value. Rows that are written are visible to reads from the
+// Store right away, but the tables of the root aren't updated until Flush is called.
+type Store struct {
+	root   *doltdb.RootValue
+	tables map[string]*tableState
+}
+
+// tableState is a table read by a Store, along with the row edits made to it that haven't been flushed.
+type tableState struct {
+	tbl     *doltdb.Table
+	sch     schema.Schema
+	rowData types.Map
+	ed      *types.MapEditor
+	edited  bool
+}
+
+// NewStore returns a Store for the tables of the root value given.
+func NewStore(root *doltdb.RootValue) *Store {
+	return &Store{root: root, tables: make(map[string]*tableState)}
+}
+
+// GetRow returns the row of the table given with the primary key values given, in the order of the table's primary key
+// columns, and whether there is one.
+func (s *Store) GetRow(ctx context.Context, tableName string, key ...interface{}) (Row, bool, error) {
+	ts, err := s.table(ctx, tableName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	keyTpl, err := ts.keyTuple(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rowData, err := ts.rows(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	val, ok, err := rowData.MaybeGet(ctx, keyTpl)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	r, err := ts.toRow(keyTpl, val.(types.Tuple))
+	if err != nil {
+		return nil, false, err
+	}
+
+	return r, true, nil
+}
+
+// PutRow writes the row given to the table given, replacing the row with the same primary key if there is one.
+func (s *Store) PutRow(ctx context.Context, tableName string, r Row) error {
+	ts, err := s.table(ctx, tableName)
+	if err != nil {
+		return err
+	}
+
+	dRow, err := ts.fromRow(r)
+	if err != nil {
+		return err
+	}
+
+	keyVal, err := dRow.NomsMapKey(ts.sch).Value(ctx)
+	if err != nil {
+		return err
+	}
+
+	ts.editor().Set(keyVal, dRow.NomsMapValue(ts.sch))
+	return nil
+}
+
+// DeleteRow removes the row of the table given with the primary key values given, if there is one.
+func (s *Store) DeleteRow(ctx context.Context, tableName string, key ...interface{}) error {
+	ts, err := s.table(ctx, tableName)
+	if err != nil {
+		return err
+	}
+
+	keyTpl, err := ts.keyTuple(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	ts.editor().Remove(keyTpl)
+	return nil
+}
+
+// IterRange calls cb with the rows of the table given with primary keys from start, inclusive, up to end, exclusive, in
+// primary key order, until cb returns true or an error. A nil start begins with the first row and a nil end continues
+// to the last.
+func (s *Store) IterRange(ctx context.Context, tableName string, start, end []interface{}, cb func(r Row) (stop bool, err error)) error {
+	ts, err := s.table(ctx, tableName)
+	if err != nil {
+		return err
+	}
+
+	rowData, err := ts.rows(ctx)
+	if err != nil {
+		return err
+	}
+
+	var itr types.MapIterator
+	if start != nil {
+		startTpl, err := ts.keyTuple(ctx, start)
+		if err != nil {
+			return err
+		}
+
+		itr, err = rowData.IteratorFrom(ctx, startTpl)
+		if err != nil {
+			return err
+		}
+	} else {
+		itr, err = rowData.Iterator(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	var endTpl types.Tuple
+	if end != nil {
+		endTpl, err = ts.keyTuple(ctx, end)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		k, v, err := itr.Next(ctx)
+		if err != nil {
+			return err
+		} else if k == nil {
+			return nil
+		}
+
+		if end != nil {
+			if isLess, err := k.Less(rowData.Format(), endTpl); err != nil {
+				return err
+			} else if !isLess {
+				return nil
+			}
+		}
+
+		r, err := ts.toRow(k.(types.Tuple), v.(types.Tuple))
+		if err != nil {
+			return err
+		}
+
+		if stop, err := cb(r); err != nil || stop {
+			return err
+		}
+	}
+}
+
+// Flush writes the rows written to each table since the last flush to the root, and returns the updated root. The
+// root must be written or committed for the rows to be persisted.
+func (s *Store) Flush(ctx context.Context) (*doltdb.RootValue, error) {
+	for name, ts := range s.tables {
+		if !ts.edited {
+			continue
+		}
+
+		rowData, err := ts.rows(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		tbl, err := ts.tbl.UpdateRows(ctx, rowData)
+		if err != nil {
+			return nil, err
+		}
+
+		root, err := doltdb.PutTable(ctx, s.root, s.root.VRW(), name, tbl)
+		if err != nil {
+			return nil, err
+		}
+
+		s.root = root
+		ts.tbl = tbl
+		ts.edited = false
+	}
+
+	return s.root, nil
+}
+
+// table returns the state of the table given, reading it from the root the first time.
+func (s *Store) table(ctx context.Context, tableName string) (*tableState, error) {
+	if ts, ok := s.tables[tableName]; ok {
+		return ts, nil
+	}
+
+	tbl, ok, err := s.root.GetTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, doltdb.ErrTableNotFound
+	}
+
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rowData, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := &tableState{tbl: tbl, sch: sch, rowData: rowData}
+	s.tables[tableName] = ts
+	return ts, nil
+}
+
+// editor returns the editor of the table's rows, starting one if there isn't one.
+func (ts *tableState) editor() *types.MapEditor {
+	if ts.ed == nil {
+		ts.ed = ts.rowData.Edit()
+	}
+
+	ts.edited = true
+	return ts.ed
+}
+
+// rows returns the rows of the table with the edits made to it applied.
+func (ts *tableState) rows(ctx context.Context) (types.Map, error) {
+	if ts.ed != nil {
+		rowData, err := ts.ed.Map(ctx)
+		if err != nil {
+			return types.EmptyMap, err
+		}
+
+		ts.rowData = rowData
+		ts.ed = nil
+	}
+
+	return ts.rowData, nil
+}
+
+// keyTuple returns the key of the table's row data for the primary key values given.
+func (ts *tableState) keyTuple(ctx context.Context, key []interface{}) (types.Tuple, error) {
+	pkCols := ts.sch.GetPKCols()
+	if len(key) != pkCols.Size() {
+		return types.EmptyTuple(ts.rowData.Format()), ErrKeySize
+	}
+
+	pkVals := make(row.TaggedValues)
+	for i, tag := range pkCols.Tags {
+		col := pkCols.TagToCol[tag]
+		val, err := goValToNomsVal(key[i], col)
+		if err != nil {
+			return types.EmptyTuple(ts.rowData.Format()), err
+		} else if val == nil {
+			return types.EmptyTuple(ts.rowData.Format()), fmt.Errorf("primary key column %s can't be null", col.Name)
+		}
+
+		pkVals[tag] = val
+	}
+
+	keyVal, err := pkVals.NomsTupleForTags(ts.rowData.Format(), pkCols.Tags, true).Value(ctx)
+	if err != nil {
+		return types.EmptyTuple(ts.rowData.Format()), err
+	}
+
+	return keyVal.(types.Tuple), nil
+}
+
+// fromRow returns the row of the table for the row given, checking that the row is valid for the table's schema.
+func (ts *tableState) fromRow(r Row) (row.Row, error) {
+	allCols := ts.sch.GetAllCols()
+	taggedVals := make(row.TaggedValues)
+	for name, goVal := range r {
+		col, ok := allCols.GetByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s", name)
+		}
+
+		val, err := goValToNomsVal(goVal, col)
+		if err != nil {
+			return nil, err
+		} else if val != nil {
+			taggedVals[col.Tag] = val
+		}
+	}
+
+	dRow, err := row.New(ts.rowData.Format(), ts.sch, taggedVals)
+	if err != nil {
+		return nil, err
+	}
+
+	badCol, err := row.GetInvalidCol(dRow, ts.sch)
+	if err != nil {
+		return nil, err
+	} else if badCol != nil {
+		return nil, fmt.Errorf("invalid value for column %s", badCol.Name)
+	}
+
+	return dRow, nil
+}
+
+// toRow returns the row for the key and value of the table's row data given.
+func (ts *tableState) toRow(key, val types.Tuple) (Row, error) {
+	dRow, err := row.FromNoms(ts.sch, key, val)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(Row)
+	err = ts.sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		val, ok := dRow.GetColVal(tag)
+		if !ok || types.IsNull(val) {
+			return false, nil
+		}
+
+		r[col.Name], err = nomsValToGoVal(val)
+		return err != nil, err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// goValToNomsVal converts the Go value given to a value of the kind of the column given. A nil value is null.
+func goValToNomsVal(goVal interface{}, col schema.Column) (types.Value, error) {
+	if u, ok := goVal.(uuid.UUID); ok && col.Kind == types.UUIDKind {
+		return types.UUID(u), nil
+	}
+
+	val, err := sqltypes.SqlValToNomsVal(goVal, col.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for column %s: %v", col.Name, err)
+	}
+
+	return val, nil
+}
+
+// nomsValToGoVal converts the value of a column to a Go value.
+func nomsValToGoVal(val types.Value) (interface{}, error) {
+	if u, ok := val.(types.UUID); ok {
+		return uuid.UUID(u), nil
+	}
+
+	return sqltypes.NomsValToSqlVal(val)
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/doltdb"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/liquidata-inc/dolt/go/libraries/doltcore/schema"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+const (
+	idTag uint64 = iota
+	nameTag
+	scoreTag
+	uidTag
+)
+
+var testSch = dtestutils.CreateSchema(
+	schema.NewColumn("id", idTag, types.IntKind, true, schema.NotNullConstraint{}),
+	schema.NewColumn("name", nameTag, types.StringKind, false, schema.NotNullConstraint{}),
+	schema.NewColumn("score", scoreTag, types.FloatKind, false),
+	schema.NewColumn("uid", uidTag, types.UUIDKind, false),
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	uid := uuid.New()
+	dtestutils.CreateTestTable(t, dEnv, "people", testSch,
+		dtestutils.NewRow(testSch, types.Int(1), types.String("Bill"), types.Float(1.5), types.UUID(uid)),
+		dtestutils.NewRow(testSch, types.Int(2), types.String("John")),
+		dtestutils.NewRow(testSch, types.Int(3), types.String("Rob")),
+	)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	s := NewStore(root)
+
+	r, ok, err := s.GetRow(ctx, "people", 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, Row{"id": int64(1), "name": "Bill", "score": 1.5, "uid": uid}, r)
+
+	// null columns are left out
+	r, ok, err = s.GetRow(ctx, "people", int64(2))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, Row{"id": int64(2), "name": "John"}, r)
+
+	_, ok, err = s.GetRow(ctx, "people", 4)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// writes are visible to reads before they're flushed
+	require.NoError(t, s.PutRow(ctx, "people", Row{"id": 4, "name": "Jill", "score": float32(2)}))
+	require.NoError(t, s.PutRow(ctx, "people", Row{"id": 2, "name": "Johnny"}))
+	require.NoError(t, s.DeleteRow(ctx, "people", 3))
+
+	r, ok, err = s.GetRow(ctx, "people", 4)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, Row{"id": int64(4), "name": "Jill", "score": 2.0}, r)
+
+	var names []string
+	err = s.IterRange(ctx, "people", nil, nil, func(r Row) (bool, error) {
+		names = append(names, r["name"].(string))
+		return false, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bill", "Johnny", "Jill"}, names)
+
+	names = nil
+	err = s.IterRange(ctx, "people", []interface{}{2}, []interface{}{4}, func(r Row) (bool, error) {
+		names = append(names, r["name"].(string))
+		return false, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Johnny"}, names)
+
+	names = nil
+	err = s.IterRange(ctx, "people", []interface{}{2}, nil, func(r Row) (bool, error) {
+		names = append(names, r["name"].(string))
+		return true, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Johnny"}, names)
+
+	// the root isn't changed until the writes are flushed
+	_, ok, err = NewStore(root).GetRow(ctx, "people", 4)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	flushed, err := s.Flush(ctx)
+	require.NoError(t, err)
+	r, ok, err = NewStore(flushed).GetRow(ctx, "people", 4)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "Jill", r["name"])
+	_, ok, err = NewStore(flushed).GetRow(ctx, "people", 3)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	tbl, _, err := flushed.GetTable(ctx, "people")
+	require.NoError(t, err)
+	rowData, err := tbl.GetRowData(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), rowData.Len())
+}
+
+func TestStoreErrors(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	dtestutils.CreateTestTable(t, dEnv, "people", testSch)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	s := NewStore(root)
+
+	_, _, err = s.GetRow(ctx, "missing", 1)
+	assert.Equal(t, doltdb.ErrTableNotFound, err)
+	_, _, err = s.GetRow(ctx, "people")
+	assert.Equal(t, ErrKeySize, err)
+	_, _, err = s.GetRow(ctx, "people", 1, 2)
+	assert.Equal(t, ErrKeySize, err)
+	_, _, err = s.GetRow(ctx, "people", nil)
+	assert.Error(t, err)
+	_, _, err = s.GetRow(ctx, "people", "one")
+	assert.Error(t, err)
+
+	assert.Error(t, s.PutRow(ctx, "people", Row{"id": 1}))
+	assert.Error(t, s.PutRow(ctx, "people", Row{"id": 1, "name": "Bill", "age": 32}))
+	assert.Error(t, s.PutRow(ctx, "people", Row{"id": 1, "name": "Bill", "uid": "not a uuid"}))
+	assert.Error(t, s.PutRow(ctx, "people", Row{"name": "Bill"}))
+}
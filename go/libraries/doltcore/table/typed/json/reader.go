@@ -154,6 +154,15 @@ func (r *JSONReader) convToRow(rowMap map[string]interface{}) (row.Row, error) {
 			}
 			taggedVals[col.Tag], _ = f(types.Int(val))
 		case string:
+			if ct, ok := col.CustomType(); ok {
+				parsed, err := ct.Parse(val)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s value '%s' of column %s: %v", ct.Name, val, col.Name, err)
+				}
+				taggedVals[col.Tag] = parsed
+				continue
+			}
+
 			f, err := doltcore.GetConvFunc(types.StringKind, col.Kind)
 			if err != nil {
 				return nil, err
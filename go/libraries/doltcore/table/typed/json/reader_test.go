@@ -17,6 +17,7 @@ package json
 import (
 	"context"
 	"io"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -161,6 +162,54 @@ func TestReaderBadJson(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCustomTypes(t *testing.T) {
+	err := schema.RegisterCustomType(schema.CustomType{
+		Name: "hex",
+		Kind: types.UintKind,
+		Parse: func(str string) (types.Value, error) {
+			n, err := strconv.ParseUint(str, 16, 64)
+			return types.Uint(n), err
+		},
+		Format: func(val types.Value) (string, error) {
+			return strconv.FormatUint(uint64(val.(types.Uint)), 16), nil
+		},
+	})
+	require.NoError(t, err)
+
+	hexCol := schema.NewColumn("color", 1, types.UintKind, false)
+	hexCol.TypeName = "hex"
+	colColl, err := schema.NewColCollection(schema.NewColumn("id", 0, types.IntKind, true), hexCol)
+	require.NoError(t, err)
+	sch := schema.SchemaFromCols(colColl)
+
+	// values of a custom type are written as their string representations, and parsed when read
+	fs := filesys.EmptyInMemFS("/")
+	wr, err := fs.OpenForWrite("file.json")
+	require.NoError(t, err)
+	writer, err := NewJSONWriter(wr, sch)
+	require.NoError(t, err)
+	r, err := row.New(types.Format_LD_1, sch, row.TaggedValues{0: types.Int(0), 1: types.Uint(0xff00ff)})
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteRow(context.Background(), r))
+	require.NoError(t, writer.Close(context.Background()))
+
+	data, err := fs.ReadFile("file.json")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"color":"ff00ff"`)
+
+	reader, err := OpenJSONReader(types.Format_LD_1, "file.json", fs, sch, "")
+	require.NoError(t, err)
+	read, err := reader.ReadRow(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, r, read)
+
+	require.NoError(t, fs.WriteFile("bad.json", []byte(`{"rows": [{"id": 1, "color": "purple"}]}`)))
+	reader, err = OpenJSONReader(types.Format_LD_1, "bad.json", fs, sch, "")
+	require.NoError(t, err)
+	_, err = reader.ReadRow(context.Background())
+	assert.Error(t, err)
+}
+
 func newRow(sch schema.Schema, id int, first, last string) row.Row {
 	vals := row.TaggedValues{
 		0: types.Int(id),
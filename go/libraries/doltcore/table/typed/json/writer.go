@@ -76,13 +76,27 @@ func (jsonw *JSONWriter) WriteRow(ctx context.Context, r row.Row) error {
 	colValMap := make(map[string]interface{}, allCols.Size())
 	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		val, ok := r.GetColVal(tag)
-		if ok && !types.IsNull(val) {
+		if !ok || types.IsNull(val) {
+			return false, nil
+		}
+
+		if ct, ok := col.CustomType(); ok {
+			str, err := ct.Format(val)
+			if err != nil {
+				return true, err
+			}
+			colValMap[col.Name] = str
+		} else {
 			colValMap[col.Name] = val
 		}
 
 		return false, nil
 	})
 
+	if err != nil {
+		return err
+	}
+
 	data, err := marshalToJson(colValMap)
 	if err != nil {
 		return errors.New("marshaling did not work")
@@ -20,10 +20,14 @@ import (
 
 func TypedSchemaUnion(schemas ...schema.Schema) (schema.Schema, error) {
 	var allCols []schema.Column
+	strategies := make(map[uint64]schema.MergeStrategy)
 
 	for _, sch := range schemas {
 		err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 			allCols = append(allCols, col)
+			if _, ok := strategies[tag]; !ok || strategies[tag] == schema.MergeDefault {
+				strategies[tag] = col.MergeStrategy
+			}
 			return false, nil
 		})
 
@@ -32,12 +36,26 @@ func TypedSchemaUnion(schemas ...schema.Schema) (schema.Schema, error) {
 		}
 	}
 
+	// A column keeps the first merge strategy other than the default that any schema gives it, so that a strategy
+	// declared on one side of a merge isn't lost
+	for i, col := range allCols {
+		allCols[i].MergeStrategy = strategies[col.Tag]
+	}
+
 	allColColl, err := schema.NewColCollection(allCols...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	sch := schema.SchemaFromCols(allColColl)
+	// The table comment of the first schema with one is kept, as the first column with each tag is
+	var comment string
+	for _, sch := range schemas {
+		if comment = sch.GetComment(); comment != "" {
+			break
+		}
+	}
+
+	sch := schema.SchemaWithComment(schema.SchemaFromCols(allColColl), comment)
 	return sch, nil
 }
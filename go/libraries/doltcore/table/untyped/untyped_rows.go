@@ -81,6 +81,7 @@ func UntypeSchema(sch schema.Schema) (schema.Schema, error) {
 	var cols []schema.Column
 	err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		col.Kind = types.StringKind
+		col.TypeName = ""
 		cols = append(cols, col)
 		return false, nil
 	})
@@ -128,6 +129,7 @@ func UntypeUnkeySchema(sch schema.Schema) (schema.Schema, error) {
 	var cols []schema.Column
 	err := sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		col.Kind = types.StringKind
+		col.TypeName = ""
 		col.IsPartOfPK = false
 		col.Constraints = nil
 		cols = append(cols, col)
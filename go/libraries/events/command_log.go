@@ -0,0 +1,156 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+)
+
+// The size classes of the repository a command was run in
+const (
+	RepoSizeNone   = "none"
+	RepoSizeSmall  = "small"
+	RepoSizeMedium = "medium"
+	RepoSizeLarge  = "large"
+)
+
+// The categories of error a command can end with
+const (
+	ErrCategoryUnknownCommand = "unknown_command"
+	ErrCategoryInvalidRepo    = "invalid_repo"
+	ErrCategoryFailed         = "failed"
+)
+
+const (
+	smallRepoMaxBytes  = 10 * 1024 * 1024
+	mediumRepoMaxBytes = 1024 * 1024 * 1024
+)
+
+// sinkTimeout is how long posting a record to the sink of a command log may take.
+const sinkTimeout = 2 * time.Second
+
+// RepoSizeClass gets the size class of a repository whose data takes up the number of bytes given.  A size of -1 is
+// used for a directory that isn't a repository.
+func RepoSizeClass(size int64) string {
+	switch {
+	case size < 0:
+		return RepoSizeNone
+	case size < smallRepoMaxBytes:
+		return RepoSizeSmall
+	case size < mediumRepoMaxBytes:
+		return RepoSizeMedium
+	default:
+		return RepoSizeLarge
+	}
+}
+
+// CommandInfo is filled in while a command runs with the name of the command that was run and the category of error
+// it ended with, if any.
+type CommandInfo struct {
+	Name          string
+	ErrorCategory string
+}
+
+type commandInfoKeyT struct {
+}
+
+// contextCommandInfoKey key used for storing and retrieving the command info from the context.
+var contextCommandInfoKey = commandInfoKeyT{}
+
+// NewContextForCommandInfo creates a new context with the command info provided
+func NewContextForCommandInfo(ctx context.Context, info *CommandInfo) context.Context {
+	return context.WithValue(ctx, contextCommandInfoKey, info)
+}
+
+// GetCommandInfoFromContext retrieves the command info from the context if there is one.
+func GetCommandInfoFromContext(ctx context.Context) *CommandInfo {
+	info, _ := ctx.Value(contextCommandInfoKey).(*CommandInfo)
+	return info
+}
+
+// CommandRecord is the record of a single command written to a command log.
+type CommandRecord struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	DurationMs int64     `json:"duration_ms"`
+	RepoSize   string    `json:"repo_size"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// CommandLog appends a line of JSON for each command recorded to a local file, and posts each record to a sink URL
+// if it has one.
+type CommandLog struct {
+	// Path the path of the file records are appended to
+	Path string
+	// SinkURL the url each record is posted to. Records aren't posted when empty
+	SinkURL string
+}
+
+// Record writes the record given to the command log.  Posting the record to the sink is best effort, and failing to
+// do so is not an error.
+func (cl CommandLog) Record(ctx context.Context, rec CommandRecord) error {
+	data, err := json.Marshal(rec)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(cl.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.ModePerm)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(data, '\n'))
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if cl.SinkURL != "" {
+		cl.post(ctx, data)
+	}
+
+	return nil
+}
+
+func (cl CommandLog) post(ctx context.Context, data []byte) {
+	ctx, cancel := context.WithTimeout(ctx, sinkTimeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, cl.SinkURL, bytes.NewReader(data))
+
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+
+	if err != nil {
+		return
+	}
+
+	_ = resp.Body.Close()
+}
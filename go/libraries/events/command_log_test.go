@@ -0,0 +1,91 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoSizeClass(t *testing.T) {
+	assert.Equal(t, RepoSizeNone, RepoSizeClass(-1))
+	assert.Equal(t, RepoSizeSmall, RepoSizeClass(0))
+	assert.Equal(t, RepoSizeSmall, RepoSizeClass(smallRepoMaxBytes-1))
+	assert.Equal(t, RepoSizeMedium, RepoSizeClass(smallRepoMaxBytes))
+	assert.Equal(t, RepoSizeMedium, RepoSizeClass(mediumRepoMaxBytes-1))
+	assert.Equal(t, RepoSizeLarge, RepoSizeClass(mediumRepoMaxBytes))
+}
+
+func TestCommandInfoContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, GetCommandInfoFromContext(ctx))
+
+	info := &CommandInfo{}
+	ctx = NewContextForCommandInfo(ctx, info)
+	GetCommandInfoFromContext(ctx).Name = "dolt status"
+	assert.Equal(t, "dolt status", info.Name)
+}
+
+func TestCommandLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "command_log")
+	require.NoError(t, err)
+
+	posted := make(chan []byte, 2)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		posted <- data
+	}))
+	defer sink.Close()
+
+	recs := []CommandRecord{
+		{Time: time.Unix(1000, 0).UTC(), Command: "dolt status", DurationMs: 12, RepoSize: RepoSizeSmall},
+		{Time: time.Unix(2000, 0).UTC(), Command: "dolt nope", DurationMs: 1, RepoSize: RepoSizeNone, Error: ErrCategoryUnknownCommand},
+	}
+
+	cl := CommandLog{Path: filepath.Join(dir, "commands.jsonl"), SinkURL: sink.URL}
+	for _, rec := range recs {
+		require.NoError(t, cl.Record(context.Background(), rec))
+	}
+
+	data, err := ioutil.ReadFile(cl.Path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Equal(t, len(recs), len(lines))
+
+	for i, line := range lines {
+		var rec CommandRecord
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		assert.Equal(t, recs[i], rec)
+		assert.Equal(t, line, string(<-posted))
+	}
+
+	// a sink that can't be reached doesn't keep the record from being written
+	cl.SinkURL = "http://127.0.0.1:0"
+	require.NoError(t, cl.Record(context.Background(), recs[0]))
+
+	// the log can't be written when its directory doesn't exist
+	cl.Path = filepath.Join(dir, "missing", "commands.jsonl")
+	assert.Error(t, cl.Record(context.Background(), recs[0]))
+}
@@ -17,6 +17,9 @@ package filesys
 import (
 	"encoding/json"
 	"io"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -103,3 +106,55 @@ func UnmarshalJSONFile(fs ReadableFS, path string, dest interface{}) error {
 
 	return json.Unmarshal(data, dest)
 }
+
+// HasGlobMeta returns true if the path given contains any of the special characters of a glob pattern.
+func HasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// Glob returns the paths of the files matching the pattern given, in sorted order.  The pattern syntax is that of
+// filepath.Match, and only the last element of the pattern may contain special characters.  Directories are never
+// matched.  A pattern without special characters is returned as is, whether or not the file exists.
+func Glob(fs WalkableFS, pattern string) ([]string, error) {
+	if !HasGlobMeta(pattern) {
+		return []string{pattern}, nil
+	}
+
+	dir, filePattern := filepath.Split(pattern)
+
+	if HasGlobMeta(dir) {
+		return nil, errors.New("only the file name of a glob pattern may contain special characters: " + pattern)
+	}
+
+	iterDir := dir
+	if iterDir == "" {
+		iterDir = "."
+	}
+
+	var matches []string
+	var matchErr error
+	err := fs.Iter(iterDir, false, func(path string, size int64, isDir bool) (stop bool) {
+		name := filepath.Base(path)
+		matched, err := filepath.Match(filePattern, name)
+
+		if err != nil {
+			matchErr = err
+			return true
+		}
+
+		if matched && !isDir {
+			matches = append(matches, filepath.Join(dir, name))
+		}
+
+		return false
+	})
+
+	if err != nil {
+		return nil, err
+	} else if matchErr != nil {
+		return nil, matchErr
+	}
+
+	sort.Strings(matches)
+	return matches, nil
+}
@@ -198,6 +198,38 @@ func TestFSIteration(t *testing.T) {
 	}
 }
 
+func TestLocalFilesysWithWorkingDir(t *testing.T) {
+	cwd := test.TestDir("TestLocalFilesysWithWorkingDir")
+	require.NoError(t, LocalFS.MkDirs(cwd))
+	fs, err := LocalFilesysWithWorkingDir(cwd)
+	require.NoError(t, err)
+
+	var expectedDirs []string
+	var expectedFiles []string
+	expectedDirs = makeDirsAddExpected(expectedDirs, fs, "root", "child1", "grandchild1")
+	expectedFiles = writeFileAddToExp(expectedFiles, fs, "root", "child1", "File1.txt")
+	expectedFiles = writeFileAddToExp(expectedFiles, fs, "root", "child1", "grandchild1", "File1.txt")
+
+	// relative paths are in the working directory, and absolute paths aren't affected by it
+	exists, isDir := LocalFS.Exists(filepath.Join(cwd, "root", "child1", "File1.txt"))
+	require.True(t, exists)
+	require.False(t, isDir)
+	exists, _ = fs.Exists(filepath.Join(cwd, "root", "child1", "File1.txt"))
+	require.True(t, exists)
+
+	actualDirs, actualFiles, err := iterate(fs, "root", true, t)
+	require.NoError(t, err)
+	validate(expectedDirs, expectedFiles, actualDirs, actualFiles, "local with working dir", t)
+
+	abs, err := fs.Abs("root")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(cwd, "root"), abs)
+
+	require.NoError(t, fs.Delete("root", true))
+	exists, _ = LocalFS.Exists(filepath.Join(cwd, "root"))
+	require.False(t, exists)
+}
+
 func TestDeletes(t *testing.T) {
 	dir := test.TestDir("TestDeletes")
 
@@ -306,3 +338,38 @@ func validate(expectedDirs, expectedFiles, actualDirs, actualFiles []string, fsN
 		t.Error("fs:", fsName, "Expected files does not match actual files.", "\n\tactual  :", actualFiles, "\n\texpected:", expectedFiles)
 	}
 }
+
+func TestGlob(t *testing.T) {
+	dir := test.TestDir("TestGlob")
+
+	for fsName, fs := range filesysetmsToTest {
+		t.Run(fsName, func(t *testing.T) {
+			makeDirsAddExpected(nil, fs, dir, "part-dir.csv")
+			for _, name := range []string{"part-2.csv", "part-1.csv", "part-10.csv", "other.csv", "part-1.psv"} {
+				writeFileAddToExp(nil, fs, dir, name)
+			}
+
+			matches, err := Glob(fs, filepath.Join(dir, "part-*.csv"))
+			require.NoError(t, err)
+			require.Equal(t, []string{
+				filepath.Join(dir, "part-1.csv"),
+				filepath.Join(dir, "part-10.csv"),
+				filepath.Join(dir, "part-2.csv"),
+			}, matches)
+
+			matches, err = Glob(fs, filepath.Join(dir, "nothing-*.csv"))
+			require.NoError(t, err)
+			require.Empty(t, matches)
+
+			matches, err = Glob(fs, filepath.Join(dir, "not-a-pattern.csv"))
+			require.NoError(t, err)
+			require.Equal(t, []string{filepath.Join(dir, "not-a-pattern.csv")}, matches)
+
+			_, err = Glob(fs, filepath.Join(dir, "*", "part-1.csv"))
+			require.Error(t, err)
+
+			_, err = Glob(fs, filepath.Join(dir, "part-[.csv"))
+			require.Error(t, err)
+		})
+	}
+}
@@ -26,11 +26,36 @@ import (
 // LocalFS is the machines local filesystem
 var LocalFS = &localFS{}
 
-type localFS struct{}
+type localFS struct {
+	// cwd is the directory relative paths are resolved against. When empty, they're resolved against the working
+	// directory of the process.
+	cwd string
+}
+
+// LocalFilesysWithWorkingDir returns the machines local filesystem with relative paths resolved against the directory
+// given rather than the working directory of the process, so that a repository in another directory can be accessed
+// through it without changing the working directory.
+func LocalFilesysWithWorkingDir(cwd string) (Filesys, error) {
+	absCwd, err := filepath.Abs(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localFS{absCwd}, nil
+}
+
+// pathFor returns the path given resolved against the filesystem's working directory.
+func (fs *localFS) pathFor(path string) string {
+	if fs.cwd == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(fs.cwd, path)
+}
 
 // Exists will tell you if a file or directory with a given path already exists, and if it does is it a directory
 func (fs *localFS) Exists(path string) (exists bool, isDir bool) {
-	stat, err := os.Stat(path)
+	stat, err := os.Stat(fs.pathFor(path))
 
 	if err != nil {
 		return false, false
@@ -44,7 +69,7 @@ var errStopMarker = errors.New("stop")
 // Iter iterates over the files and subdirectories within a given directory (Optionally recursively.
 func (fs *localFS) Iter(path string, recursive bool, cb FSIterCB) error {
 	if !recursive {
-		info, err := ioutil.ReadDir(path)
+		info, err := ioutil.ReadDir(fs.pathFor(path))
 
 		if err != nil {
 			return err
@@ -65,8 +90,18 @@ func (fs *localFS) Iter(path string, recursive bool, cb FSIterCB) error {
 }
 
 func (fs *localFS) iter(dir string, cb FSIterCB) error {
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if dir != path {
+	walkDir := fs.pathFor(dir)
+	err := filepath.Walk(walkDir, func(path string, info os.FileInfo, err error) error {
+		if walkDir != path {
+			// report paths relative to the directory given, as it was given
+			if walkDir != dir {
+				rel, err := filepath.Rel(walkDir, path)
+				if err != nil {
+					return err
+				}
+				path = filepath.Join(dir, rel)
+			}
+
 			stop := cb(path, info.Size(), info.IsDir())
 
 			if stop {
@@ -91,28 +126,29 @@ func (fs *localFS) OpenForRead(fp string) (io.ReadCloser, error) {
 		return nil, ErrIsDir
 	}
 
-	return os.Open(fp)
+	return os.Open(fs.pathFor(fp))
 }
 
 // ReadFile reads the entire contents of a file
 func (fs *localFS) ReadFile(fp string) ([]byte, error) {
-	return ioutil.ReadFile(fp)
+	return ioutil.ReadFile(fs.pathFor(fp))
 }
 
 // OpenForWrite opens a file for writing.  The file will be created if it does not exist, and if it does exist
 // it will be overwritten.
 func (fs *localFS) OpenForWrite(fp string) (io.WriteCloser, error) {
-	return os.OpenFile(fp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.ModePerm)
+	return os.OpenFile(fs.pathFor(fp), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.ModePerm)
 }
 
 // WriteFile writes the entire data buffer to a given file.  The file will be created if it does not exist,
 // and if it does exist it will be overwritten.
 func (fs *localFS) WriteFile(fp string, data []byte) error {
-	return ioutil.WriteFile(fp, data, os.ModePerm)
+	return ioutil.WriteFile(fs.pathFor(fp), data, os.ModePerm)
 }
 
 // MkDirs creates a folder and all the parent folders that are necessary to create it.
 func (fs *localFS) MkDirs(path string) error {
+	path = fs.pathFor(path)
 	_, err := os.Stat(path)
 
 	if err != nil {
@@ -129,7 +165,7 @@ func (fs *localFS) DeleteFile(path string) error {
 			return ErrIsDir
 		}
 
-		return os.Remove(path)
+		return os.Remove(fs.pathFor(path))
 	}
 
 	return os.ErrNotExist
@@ -139,9 +175,9 @@ func (fs *localFS) DeleteFile(path string) error {
 // true in order to delete the dir and all of it's contents
 func (fs *localFS) Delete(path string, force bool) error {
 	if !force {
-		return os.Remove(path)
+		return os.Remove(fs.pathFor(path))
 	} else {
-		return os.RemoveAll(path)
+		return os.RemoveAll(fs.pathFor(path))
 	}
 }
 
@@ -165,12 +201,12 @@ func (fs *localFS) MoveFile(srcPath, destPath string) error {
 
 // converts a path to an absolute path.  If it's already an absolute path the input path will be returned unaltered
 func (fs *localFS) Abs(path string) (string, error) {
-	return filepath.Abs(path)
+	return filepath.Abs(fs.pathFor(path))
 }
 
 // LastModified gets the last modified timestamp for a file or directory at a given path
 func (fs *localFS) LastModified(path string) (t time.Time, exists bool) {
-	stat, err := os.Stat(path)
+	stat, err := os.Stat(fs.pathFor(path))
 
 	if err != nil {
 		return time.Time{}, false
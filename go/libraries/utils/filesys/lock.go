@@ -85,6 +85,10 @@ type LocalFileLock struct {
 
 // NewLocalFileLock creates a new LocalFileLock
 func NewLocalFileLock(fs Filesys, filename string) *LocalFileLock {
+	if lfs, ok := fs.(*localFS); ok {
+		filename = lfs.pathFor(filename)
+	}
+
 	lck := fslock.New(filename)
 
 	return &LocalFileLock{lck: lck}
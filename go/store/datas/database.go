@@ -24,11 +24,13 @@ package datas
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/liquidata-inc/dolt/go/store/nbs"
 
 	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -150,3 +152,31 @@ func CanUsePuller(db Database) bool {
 
 	return ok
 }
+
+// MissingChunks returns the hashes in |hashes| of the chunks that |db| doesn't have.
+func MissingChunks(ctx context.Context, db Database, hashes hash.HashSet) (hash.HashSet, error) {
+	return db.chunkStore().HasMany(ctx, hashes)
+}
+
+// StorageReport describes how the chunks of |db| are stored in table files. Chunks reachable from |roots| are not
+// counted as garbage. Not all Databases support this.
+func StorageReport(ctx context.Context, db Database, roots []hash.Hash) (nbs.StorageReport, error) {
+	sr, ok := db.chunkStore().(nbs.StorageReporter)
+
+	if !ok {
+		return nbs.StorageReport{}, errors.New("db is not a Table File Store")
+	}
+
+	return sr.StorageReport(ctx, roots)
+}
+
+// ChunkCount returns the number of chunks stored in |db|. Not all Databases support this.
+func ChunkCount(db Database) (uint32, error) {
+	counter, ok := db.chunkStore().(interface{ Count() (uint32, error) })
+
+	if !ok {
+		return 0, errors.New("db can't count its chunks")
+	}
+
+	return counter.Count()
+}
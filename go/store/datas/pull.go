@@ -105,6 +105,23 @@ func mapTableFiles(tblFiles []nbs.TableFile) ([]string, map[string]nbs.TableFile
 	return fileIds, fileIDtoTblFile
 }
 
+// withoutTableFiles returns the table files of tblFiles that aren't in |existing|.
+func withoutTableFiles(tblFiles, existing []nbs.TableFile) []nbs.TableFile {
+	existingIDs := make(map[string]bool, len(existing))
+	for _, tblFile := range existing {
+		existingIDs[tblFile.FileID()] = true
+	}
+
+	var missing []nbs.TableFile
+	for _, tblFile := range tblFiles {
+		if !existingIDs[tblFile.FileID()] {
+			missing = append(missing, tblFile)
+		}
+	}
+
+	return missing
+}
+
 func clone(ctx context.Context, srcTS, sinkTS nbs.TableFileStore, eventCh chan<- TableFileEvent) error {
 	if eventCh != nil {
 		defer close(eventCh)
@@ -116,6 +133,15 @@ func clone(ctx context.Context, srcTS, sinkTS nbs.TableFileStore, eventCh chan<-
 		return err
 	}
 
+	// The table files of an earlier clone into the sink that didn't complete are kept, so they aren't downloaded again.
+	sinkRoot, sinkFiles, err := sinkTS.Sources(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	tblFiles = withoutTableFiles(tblFiles, sinkFiles)
+
 	// Initializes the list of fileIDs we are going to download, and the map of fileIDToTF.  If this clone takes a long
 	// time some of the urls within the nbs.TableFiles will expire and fail to download.  At that point we will retrieve
 	// the sources again, and update the fileIDToTF map with updated info, but not change the files we are downloading.
@@ -223,7 +249,7 @@ func clone(ctx context.Context, srcTS, sinkTS nbs.TableFileStore, eventCh chan<-
 		return err
 	}
 
-	return sinkTS.SetRootChunk(ctx, root, hash.Hash{})
+	return sinkTS.SetRootChunk(ctx, root, sinkRoot)
 }
 
 // Pull objects that descend from sourceRef from srcDB to sinkDB.
@@ -483,4 +483,29 @@ func TestClone(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.True(t, reflect.DeepEqual(src, dest))
+
+	// a clone into a store holding the table files of an earlier clone that didn't complete only downloads the rest
+	resumed := &TestTableFileStore{
+		root: hash.Hash{},
+		tableFiles: map[string]nbs.TableFile{
+			"file1": src.tableFiles["file1"],
+			"file3": src.tableFiles["file3"],
+		},
+	}
+
+	eventCh := make(chan TableFileEvent, 128)
+	err = clone(ctx, src, resumed, eventCh)
+	require.NoError(t, err)
+
+	var downloaded []string
+	for evt := range eventCh {
+		if evt.EventType == DownloadSuccess {
+			for _, tblFile := range evt.TableFiles {
+				downloaded = append(downloaded, tblFile.FileID())
+			}
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"file2", "file4", "file5"}, downloaded)
+	assert.True(t, reflect.DeepEqual(src, resumed))
 }
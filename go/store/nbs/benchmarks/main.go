@@ -54,6 +54,7 @@ var (
 	useAWS   = flag.String("useAWS", "", "Name of existing Database to use for not-WriteNovel benchmarks")
 	toAWS    = flag.String("toAWS", "", "Write to an NBS store in AWS")
 	toFile   = flag.String("toFile", "", "Write to a file in the given directory")
+	pureIO   = flag.Bool("pureIO", false, "Read the table files of local NBS stores without mmap")
 )
 
 const s3Bucket = "attic-nbs"
@@ -108,7 +109,7 @@ func main() {
 				d.PanicIfError(err)
 			}()
 			open = func() (chunks.ChunkStore, error) {
-				return nbs.NewLocalStore(context.Background(), types.Format_Default.VersionString(), dir, bufSize)
+				return nbs.NewLocalStoreWithOptions(context.Background(), types.Format_Default.VersionString(), dir, bufSize, nbs.LocalStoreOptions{PureIO: *pureIO})
 			}
 			reset = func() {
 				err := os.RemoveAll(dir)
@@ -160,7 +161,7 @@ func main() {
 	} else {
 		if *useNBS != "" {
 			open = func() (chunks.ChunkStore, error) {
-				return nbs.NewLocalStore(context.Background(), types.Format_Default.VersionString(), *useNBS, bufSize)
+				return nbs.NewLocalStoreWithOptions(context.Background(), types.Format_Default.VersionString(), *useNBS, bufSize, nbs.LocalStoreOptions{PureIO: *pureIO})
 			}
 		} else if *useAWS != "" {
 			sess := session.Must(session.NewSession(aws.NewConfig().WithRegion("us-west-2")))
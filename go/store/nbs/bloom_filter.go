@@ -0,0 +1,89 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"encoding/binary"
+)
+
+const (
+	// bloomFilterProbes is the number of bits set per address. With bloomFilterBitsPerChunk bits per chunk this gives
+	// a false positive rate of about 1%.
+	bloomFilterProbes = 7
+)
+
+// bloomFilterBitsPerChunk is the size of the bloom filter built for each table index, in bits per chunk. Setting it
+// to 0 disables the filters.
+var bloomFilterBitsPerChunk uint64 = 10
+
+// bloomFilter answers whether an address may be in a table without searching its index. Addresses are the hashes of
+// chunks, so their bytes are used directly to pick the bits, rather than hashing them again.
+type bloomFilter struct {
+	bits    []uint64
+	numBits uint64
+}
+
+// newTableBloomFilter builds a bloomFilter holding every address in the index given by |prefixes|, |ordinals| and
+// |suffixes|. It returns nil if filters are disabled or the table is empty.
+func newTableBloomFilter(prefixes []uint64, ordinals []uint32, suffixes []byte) *bloomFilter {
+	count := uint64(len(prefixes))
+
+	if bloomFilterBitsPerChunk == 0 || count == 0 {
+		return nil
+	}
+
+	numBits := count * bloomFilterBitsPerChunk
+	bf := &bloomFilter{make([]uint64, (numBits+63)/64), numBits}
+	for i, prefix := range prefixes {
+		li := uint64(ordinals[i]) * addrSuffixSize
+		bf.add(prefix, binary.BigEndian.Uint64(suffixes[li:]))
+	}
+
+	return bf
+}
+
+func (bf *bloomFilter) add(h1, h2 uint64) {
+	for i := uint64(0); i < bloomFilterProbes; i++ {
+		bit := (h1 + i*h2) % bf.numBits
+		bf.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// mayContain returns false if |h| is definitely not in the filter. A nil filter may contain anything.
+func (bf *bloomFilter) mayContain(h addr) bool {
+	if bf == nil {
+		return true
+	}
+
+	h1 := binary.BigEndian.Uint64(h[:addrPrefixSize])
+	h2 := binary.BigEndian.Uint64(h[addrPrefixSize:])
+	for i := uint64(0); i < bloomFilterProbes; i++ {
+		bit := (h1 + i*h2) % bf.numBits
+		if bf.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// size returns the number of bytes held by the filter.
+func (bf *bloomFilter) size() uint64 {
+	if bf == nil {
+		return 0
+	}
+
+	return uint64(len(bf.bits)) * uint64Size
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableBloomFilter(t *testing.T) {
+	ctx := context.Background()
+	chunks := make([][]byte, 1000)
+	for i := range chunks {
+		chunks[i] = []byte(fmt.Sprintf("chunk %d", i))
+	}
+
+	tableData, _, err := buildTable(chunks)
+	require.NoError(t, err)
+	ti, err := parseTableIndex(tableData)
+	require.NoError(t, err)
+	require.NotNil(t, ti.filter)
+	tr := newTableReader(ti, tableReaderAtFromBytes(tableData), fileBlockSize)
+
+	for _, c := range chunks {
+		assert.True(t, ti.filter.mayContain(computeAddr(c)))
+		assert.Equal(t, string(c), mustGetString(assert.New(t), ctx, tr, c))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		absent := []byte(fmt.Sprintf("absent %d", i))
+		if ti.filter.mayContain(computeAddr(absent)) {
+			falsePositives++
+		}
+
+		has, err := tr.has(computeAddr(absent))
+		require.NoError(t, err)
+		assert.False(t, has)
+	}
+	assert.True(t, falsePositives < 300, "%d false positives", falsePositives)
+
+	t.Run("disabled", func(t *testing.T) {
+		defer func(bits uint64) { bloomFilterBitsPerChunk = bits }(bloomFilterBitsPerChunk)
+		bloomFilterBitsPerChunk = 0
+
+		ti, err := parseTableIndex(tableData)
+		require.NoError(t, err)
+		assert.Nil(t, ti.filter)
+		tr := newTableReader(ti, tableReaderAtFromBytes(tableData), fileBlockSize)
+		assertChunksInReader(chunks, tr, assert.New(t))
+	})
+}
@@ -97,7 +97,8 @@ func (fm fileManifest) ParseIfExists(ctx context.Context, stats *Stats, readHook
 
 	// !exists(lockFileName) => unitialized store
 	if locked {
-		var f io.ReadCloser
+		// The manifest is read and closed while the lock is held. On Windows, a writer can't rename a new manifest over
+		// one which is open.
 		err = func() (ferr error) {
 			lck := newLock(fm.dir)
 			ferr = lck.Lock()
@@ -122,39 +123,41 @@ func (fm fileManifest) ParseIfExists(ctx context.Context, stats *Stats, readHook
 				}
 			}
 
-			f, ferr = openIfExists(filepath.Join(fm.dir, manifestFileName))
-
-			if ferr != nil {
-				return ferr
-			}
-
-			return nil
+			exists, contents, ferr = readManifestIfExists(filepath.Join(fm.dir, manifestFileName))
+			return ferr
 		}()
 
 		if err != nil {
-			return exists, contents, err
+			return false, contents, err
 		}
+	}
 
-		if f != nil {
-			defer func() {
-				closeErr := f.Close()
+	return exists, contents, nil
+}
 
-				if err == nil {
-					err = closeErr
-				}
-			}()
+// readManifestIfExists parses the manifest at |path|, returning false if it does not exist.
+func readManifestIfExists(path string) (exists bool, contents manifestContents, err error) {
+	f, err := openIfExists(path)
 
-			exists = true
+	if err != nil || f == nil {
+		return false, manifestContents{}, err
+	}
 
-			contents, err = parseManifest(f)
+	defer func() {
+		closeErr := f.Close()
 
-			if err != nil {
-				return false, contents, err
-			}
+		if err == nil {
+			err = closeErr
 		}
+	}()
+
+	contents, err = parseManifest(f)
+
+	if err != nil {
+		return false, manifestContents{}, err
 	}
 
-	return exists, contents, nil
+	return true, contents, nil
 }
 
 // Returns nil if path does not exist
@@ -210,7 +213,14 @@ func parseManifest(r io.Reader) (manifestContents, error) {
 	}, nil
 }
 
-func (fm fileManifest) Update(ctx context.Context, lastLock addr, newContents manifestContents, stats *Stats, writeHook func() error) (mc manifestContents, err error) {
+func (fm fileManifest) Update(ctx context.Context, lastLock addr, newContents manifestContents, stats *Stats, writeHook func() error) (manifestContents, error) {
+	return fm.update(ctx, lastLock, newContents, stats, writeHook, nil)
+}
+
+// update implements Update. If |commitHook| is non-nil, it is invoked while the manifest file lock is held, once it is
+// known that the update will succeed, and is responsible for calling the function it is given to write the new
+// manifest.
+func (fm fileManifest) update(ctx context.Context, lastLock addr, newContents manifestContents, stats *Stats, writeHook func() error, commitHook func(commit func() error) error) (mc manifestContents, err error) {
 	t1 := time.Now()
 	defer func() { stats.WriteManifestLatency.SampleTimeSince(t1) }()
 
@@ -274,50 +284,33 @@ func (fm fileManifest) Update(ctx context.Context, lastLock addr, newContents ma
 		}
 	}
 
-	var upstream manifestContents
-	// Read current manifest (if it exists). The closure ensures that the file is closed before moving on, so we can rename over it later if need be.
+	// Read current manifest (if it exists). It is closed before moving on, so we can rename over it later if need be.
 	manifestPath := filepath.Join(fm.dir, manifestFileName)
-	upstream, err = func() (upstream manifestContents, ferr error) {
-		if f, ferr := openIfExists(manifestPath); ferr == nil && f != nil {
-			defer func() {
-				closeErr := f.Close()
-
-				if ferr != nil {
-					ferr = closeErr
-				}
-			}()
-
-			upstream, ferr = parseManifest(f)
-
-			if ferr != nil {
-				return manifestContents{}, ferr
-			}
-
-			if newContents.vers != upstream.vers {
-				return manifestContents{}, errors.New("Update cannot change manifest version")
-			}
-
-			return upstream, nil
-		} else if ferr != nil {
-			return manifestContents{}, ferr
-		}
-
-		if lastLock != (addr{}) {
-			return manifestContents{}, errors.New("new manifest created with non 0 lock")
-		}
-
-		return manifestContents{}, nil
-	}()
+	exists, upstream, err := readManifestIfExists(manifestPath)
 
 	if err != nil {
 		return manifestContents{}, err
 	}
 
+	if exists && newContents.vers != upstream.vers {
+		return manifestContents{}, errors.New("Update cannot change manifest version")
+	} else if !exists && lastLock != (addr{}) {
+		return manifestContents{}, errors.New("new manifest created with non 0 lock")
+	}
+
 	if lastLock != upstream.lock {
 		return upstream, nil
 	}
 
-	err = os.Rename(tempManifestPath, manifestPath)
+	commit := func() error {
+		return os.Rename(tempManifestPath, manifestPath)
+	}
+
+	if commitHook != nil {
+		err = commitHook(commit)
+	} else {
+		err = commit()
+	}
 
 	if err != nil {
 		return manifestContents{}, err
@@ -35,18 +35,25 @@ import (
 
 const tempTablePrefix = "nbs_table_"
 
-func newFSTablePersister(dir string, fc *fdCache, indexCache *indexCache) tablePersister {
+// newFSTablePersister returns a tablePersister which persists table files to |dir|. If |pureIO| is true, the table
+// files it opens are never mmapped.
+func newFSTablePersister(dir string, fc *fdCache, indexCache *indexCache, pureIO bool) tablePersister {
 	d.PanicIfTrue(fc == nil)
-	return &fsTablePersister{dir, fc, indexCache}
+	return &fsTablePersister{dir, fc, indexCache, pureIO}
 }
 
 type fsTablePersister struct {
 	dir        string
 	fc         *fdCache
 	indexCache *indexCache
+	pureIO     bool
 }
 
 func (ftp *fsTablePersister) Open(ctx context.Context, name addr, chunkCount uint32, stats *Stats) (chunkSource, error) {
+	if ftp.pureIO {
+		return newPureIOTableReader(ftp.dir, name, chunkCount, ftp.indexCache, ftp.fc)
+	}
+
 	return newMmapTableReader(ftp.dir, name, chunkCount, ftp.indexCache, ftp.fc)
 }
 
@@ -119,7 +126,7 @@ func (ftp *fsTablePersister) persistTable(ctx context.Context, name addr, data [
 		return nil, err
 	}
 
-	err = os.Rename(tempName, newName)
+	err = renameTableFile(tempName, newName)
 
 	if err != nil {
 		return nil, err
@@ -199,7 +206,7 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 		return nil, err
 	}
 
-	err = os.Rename(tempName, filepath.Join(ftp.dir, name.String()))
+	err = renameTableFile(tempName, filepath.Join(ftp.dir, name.String()))
 
 	if err != nil {
 		return nil, err
@@ -207,3 +214,77 @@ func (ftp *fsTablePersister) ConjoinAll(ctx context.Context, sources chunkSource
 
 	return ftp.Open(ctx, name, plan.chunkCount, stats)
 }
+
+// renameTableFile moves the temporary table file |tempName| to |newName|. Table files are named by the hash of their
+// contents, so if |newName| already exists with the same size and footer it holds the same data and is kept, as
+// renaming over it would fail on Windows if the file is open. An existing file that differs, such as one left partly
+// written by an interrupted write, is replaced.
+func renameTableFile(tempName, newName string) error {
+	same, err := sameTableFile(tempName, newName)
+
+	if err != nil {
+		return err
+	} else if same {
+		return os.Remove(tempName)
+	}
+
+	return os.Rename(tempName, newName)
+}
+
+// sameTableFile returns whether the table file |existing| exists and has the same size and footer as the table file
+// |newName|.
+func sameTableFile(newName, existing string) (bool, error) {
+	info, err := os.Stat(existing)
+
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	newInfo, err := os.Stat(newName)
+
+	if err != nil {
+		return false, err
+	}
+
+	if info.Size() != newInfo.Size() || info.Size() < footerSize {
+		return false, nil
+	}
+
+	footer, err := readTableFooter(existing, info.Size())
+
+	if err != nil {
+		return false, err
+	}
+
+	newFooter, err := readTableFooter(newName, newInfo.Size())
+
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(footer, newFooter), nil
+}
+
+// readTableFooter returns the footer of the table file |path|, which is |size| bytes long.
+func readTableFooter(path string, size int64) (footer []byte, err error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		closeErr := f.Close()
+
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	footer = make([]byte, footerSize)
+	_, err = f.ReadAt(footer, size-footerSize)
+
+	return footer, err
+}
@@ -42,7 +42,7 @@ func TestFSTableCacheOnOpen(t *testing.T) {
 	cacheSize := 2
 	fc := newFDCache(cacheSize)
 	defer fc.Drop()
-	fts := newFSTablePersister(dir, fc, nil)
+	fts := newFSTablePersister(dir, fc, nil, false)
 
 	// Create some tables manually, load them into the cache
 	func() {
@@ -118,7 +118,7 @@ func TestFSTablePersisterPersist(t *testing.T) {
 	defer os.RemoveAll(dir)
 	fc := newFDCache(defaultMaxTables)
 	defer fc.Drop()
-	fts := newFSTablePersister(dir, fc, nil)
+	fts := newFSTablePersister(dir, fc, nil, false)
 
 	src, err := persistTableData(fts, testChunks...)
 	assert.NoError(err)
@@ -132,6 +132,47 @@ func TestFSTablePersisterPersist(t *testing.T) {
 	}
 }
 
+func TestRenameTableFile(t *testing.T) {
+	assert := assert.New(t)
+	dir := makeTempDir(t)
+	defer os.RemoveAll(dir)
+
+	data, name, err := buildTable(testChunks)
+	assert.NoError(err)
+	path := filepath.Join(dir, name.String())
+
+	writeTemp := func() string {
+		temp := filepath.Join(dir, tempTablePrefix+"test")
+		assert.NoError(ioutil.WriteFile(temp, data, 0644))
+		return temp
+	}
+
+	// a partial file left by an interrupted write is replaced
+	assert.NoError(ioutil.WriteFile(path, data[:len(data)/2], 0644))
+	assert.NoError(renameTableFile(writeTemp(), path))
+	written, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal(data, written)
+
+	// a file of the same size with a different footer is replaced
+	corrupt := append([]byte{}, data...)
+	corrupt[len(corrupt)-footerSize] ^= 0xff
+	assert.NoError(ioutil.WriteFile(path, corrupt, 0644))
+	assert.NoError(renameTableFile(writeTemp(), path))
+	written, err = ioutil.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal(data, written)
+
+	// an identical file is kept and the temporary file is removed
+	temp := writeTemp()
+	assert.NoError(renameTableFile(temp, path))
+	_, err = os.Stat(temp)
+	assert.True(os.IsNotExist(err))
+	written, err = ioutil.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal(data, written)
+}
+
 func persistTableData(p tablePersister, chunx ...[]byte) (src chunkSource, err error) {
 	mt := newMemTable(testMemTableSize)
 	for _, c := range chunx {
@@ -156,7 +197,7 @@ func TestFSTablePersisterPersistNoData(t *testing.T) {
 	defer os.RemoveAll(dir)
 	fc := newFDCache(defaultMaxTables)
 	defer fc.Drop()
-	fts := newFSTablePersister(dir, fc, nil)
+	fts := newFSTablePersister(dir, fc, nil, false)
 
 	src, err := fts.Persist(context.Background(), mt, existingTable, &Stats{})
 	assert.NoError(err)
@@ -171,7 +212,7 @@ func TestFSTablePersisterCacheOnPersist(t *testing.T) {
 	dir := makeTempDir(t)
 	fc := newFDCache(1)
 	defer fc.Drop()
-	fts := newFSTablePersister(dir, fc, nil)
+	fts := newFSTablePersister(dir, fc, nil, false)
 	defer os.RemoveAll(dir)
 
 	var name addr
@@ -207,7 +248,7 @@ func TestFSTablePersisterConjoinAll(t *testing.T) {
 	defer os.RemoveAll(dir)
 	fc := newFDCache(len(sources))
 	defer fc.Drop()
-	fts := newFSTablePersister(dir, fc, nil)
+	fts := newFSTablePersister(dir, fc, nil, false)
 
 	for i, c := range testChunks {
 		randChunk := make([]byte, (i+1)*13)
@@ -242,7 +283,7 @@ func TestFSTablePersisterConjoinAllDups(t *testing.T) {
 	defer os.RemoveAll(dir)
 	fc := newFDCache(defaultMaxTables)
 	defer fc.Drop()
-	fts := newFSTablePersister(dir, fc, nil)
+	fts := newFSTablePersister(dir, fc, nil, false)
 
 	reps := 3
 	sources := make(chunkSources, reps)
@@ -205,7 +205,7 @@ func (ftc *fsTableCache) store(h addr, data io.Reader, size uint64) error {
 		return err
 	}
 
-	err = os.Rename(tempName, path)
+	err = renameTableFile(tempName, path)
 
 	if err != nil {
 		return err
@@ -0,0 +1,776 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/golang/snappy"
+
+	"github.com/liquidata-inc/dolt/go/store/atomicerr"
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+const (
+	chunkJournalFileName = "journal"
+
+	// defaultJournalWriteSize is the largest set of novel chunks, in uncompressed bytes, that a commit will append to
+	// the chunk journal. Larger sets are persisted as table files.
+	defaultJournalWriteSize = 1 << 20 // 1MB
+
+	// defaultJournalFoldSize is the size at which the chunk journal is folded into a table file.
+	defaultJournalFoldSize = (1 << 20) * 64 // 64MB
+
+	journalRecLenSize = uint32Size
+)
+
+// chunkJournal is an append-only file of chunk records which absorbs small sets of novel chunks so that committing
+// them does not require persisting a new table file. Each record is laid out as:
+//
+// |-- uint32 --|-- 20 bytes --|-- n bytes --|-- uint32 --|
+// |   length   |   address    | snappy data |    crc     |
+//
+// where length is the number of bytes following the length field. The data and crc are stored exactly as they are in
+// table files. A torn record at the end of the journal, left by a crashed writer, is ignored when the journal is read
+// and overwritten by the next append.
+//
+// Records are only appended, and the journal only replaced, while the manifest file lock is held (see
+// journalManifest) and only when the manifest update they accompany is going to succeed. Chunks are staged in memory
+// until then and remain readable from the journal in the meantime.
+type chunkJournal struct {
+	path string
+
+	mu           sync.RWMutex // protects the following state
+	f            *os.File     // nil until the journal file exists
+	end          uint64       // offset just past the last valid record
+	ranges       map[addr]Range
+	order        []addr
+	uncompressed uint64
+
+	pending    *memTable   // chunks staged to be appended with the next manifest update
+	foldOffset uint64      // if non-zero, records before this offset are being folded into a table file
+	foldFile   os.FileInfo // the journal file |foldOffset| refers to
+}
+
+func openChunkJournal(dir string) (*chunkJournal, error) {
+	j := &chunkJournal{path: filepath.Join(dir, chunkJournalFileName), ranges: map[addr]Range{}}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	err := j.refresh()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// refresh brings the in-memory index up to date with the journal file, reading any records appended by other
+// processes. If the journal file has been replaced since it was opened the index is rebuilt from scratch. Callers
+// must hold j.mu.
+func (j *chunkJournal) refresh() error {
+	info, err := os.Stat(j.path)
+
+	if os.IsNotExist(err) {
+		return j.reset(nil)
+	} else if err != nil {
+		return err
+	}
+
+	if j.f != nil {
+		curr, err := j.f.Stat()
+
+		if err != nil {
+			return err
+		}
+
+		if os.SameFile(curr, info) {
+			return j.replay()
+		}
+	}
+
+	f, err := os.OpenFile(j.path, os.O_RDWR, 0666)
+
+	if err != nil {
+		return err
+	}
+
+	err = j.reset(f)
+
+	if err != nil {
+		return err
+	}
+
+	return j.replay()
+}
+
+// reload refreshes the journal, picking up records written by other processes.
+func (j *chunkJournal) reload() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.refresh()
+}
+
+// reset closes the current journal file, if any, and clears the index. Callers must hold j.mu.
+func (j *chunkJournal) reset(f *os.File) error {
+	var err error
+	if j.f != nil && j.f != f {
+		err = j.f.Close()
+	}
+
+	j.f = f
+	j.end = 0
+	j.ranges = map[addr]Range{}
+	j.order = nil
+	j.uncompressed = 0
+
+	return err
+}
+
+// replay indexes the records between j.end and the end of the journal file, stopping at the first incomplete or
+// corrupt record. Callers must hold j.mu.
+func (j *chunkJournal) replay() error {
+	if j.f == nil {
+		return nil
+	}
+
+	info, err := j.f.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	size := uint64(info.Size())
+
+	if size <= j.end {
+		return nil
+	}
+
+	rd := bufio.NewReader(io.NewSectionReader(j.f, int64(j.end), int64(size-j.end)))
+	lenBuff := make([]byte, journalRecLenSize)
+	for {
+		_, err = io.ReadFull(rd, lenBuff)
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		recLen := uint64(binary.BigEndian.Uint32(lenBuff))
+
+		if recLen <= addrSize+checksumSize || j.end+journalRecLenSize+recLen > size {
+			return nil
+		}
+
+		rec := make([]byte, recLen)
+		_, err = io.ReadFull(rd, rec)
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var a addr
+		copy(a[:], rec)
+		cmp, err := NewCompressedChunk(hash.Hash(a), rec[addrSize:])
+
+		if err != nil {
+			return nil
+		}
+
+		dataLen, err := snappy.DecodedLen(cmp.CompressedData)
+
+		if err != nil {
+			return nil
+		}
+
+		j.index(a, Range{Offset: j.end + journalRecLenSize + addrSize, Length: uint32(recLen - addrSize)}, uint64(dataLen))
+		j.end += journalRecLenSize + recLen
+	}
+}
+
+func (j *chunkJournal) index(a addr, r Range, dataLen uint64) {
+	if _, ok := j.ranges[a]; ok {
+		return
+	}
+
+	j.ranges[a] = r
+	j.order = append(j.order, a)
+	j.uncompressed += dataLen
+}
+
+// size returns the number of bytes of records in the journal.
+func (j *chunkJournal) size() uint64 {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.end
+}
+
+func (j *chunkJournal) hasPending() bool {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.pending != nil
+}
+
+// stage adds the chunks in |mt| to those which will be appended to the journal the next time the manifest is
+// successfully updated. If |foldOffset| is non-zero, the records before it, which must have been written to a table
+// file included in that update, will be dropped from the journal after the update.
+func (j *chunkJournal) stage(mt *memTable, foldOffset uint64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if mt != nil {
+		if j.pending == nil {
+			j.pending = newMemTable(math.MaxUint64)
+		}
+
+		for _, hr := range mt.order {
+			j.pending.addChunk(*hr.a, mt.chunks[*hr.a])
+		}
+	}
+
+	j.foldOffset = foldOffset
+}
+
+// snapshot returns a memTable holding every chunk currently in the journal, along with the offset of the end of the
+// records it was built from.
+func (j *chunkJournal) snapshot(ctx context.Context) (*memTable, uint64, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	mt := newMemTable(j.uncompressed + 1)
+	for _, a := range j.order {
+		data, err := j.readChunk(a, j.ranges[a])
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		mt.addChunk(a, data)
+	}
+
+	if j.f != nil {
+		info, err := j.f.Stat()
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		j.foldFile = info
+	}
+
+	return mt, j.end, nil
+}
+
+// commit is called while the manifest file lock is held, once it is known that the manifest update will succeed.
+// Staged chunks are appended and synced before |commitManifest| is called so that the new manifest never references
+// chunks which aren't durable. Afterwards, a completed fold drops the folded records from the journal.
+func (j *chunkJournal) commit(commitManifest func() error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	err := j.refresh()
+
+	if err != nil {
+		return err
+	}
+
+	if j.pending != nil {
+		err = j.writePending()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	err = commitManifest()
+
+	if err != nil {
+		return err
+	}
+
+	foldOffset, foldFile := j.foldOffset, j.foldFile
+	j.foldOffset, j.foldFile = 0, nil
+
+	if foldOffset > 0 && j.f != nil && foldFile != nil {
+		info, err := j.f.Stat()
+
+		// If the journal was replaced since the snapshot was taken, another process has already folded it.
+		if err == nil && os.SameFile(info, foldFile) {
+			// The folded chunks are safely in a table file now. Failing to truncate the journal only means it will be
+			// folded again later, so errors are ignored.
+			_ = j.truncateTo(foldOffset)
+		}
+	}
+
+	return nil
+}
+
+// writePending appends the staged chunks to the journal and syncs it. Callers must hold j.mu.
+func (j *chunkJournal) writePending() error {
+	if j.f == nil {
+		f, err := os.OpenFile(j.path, os.O_RDWR|os.O_CREATE, 0666)
+
+		if err != nil {
+			return err
+		}
+
+		j.f = f
+	}
+
+	info, err := j.f.Stat()
+
+	if err != nil {
+		return err
+	}
+
+	if uint64(info.Size()) > j.end {
+		// Drop a torn record left by a crashed writer.
+		err = j.f.Truncate(int64(j.end))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	type newRecord struct {
+		a       addr
+		r       Range
+		dataLen uint64
+	}
+
+	var buff []byte
+	var written []newRecord
+	for _, hr := range j.pending.order {
+		a := *hr.a
+		if _, ok := j.ranges[a]; ok {
+			continue
+		}
+
+		data := j.pending.chunks[a]
+		cmp := ChunkToCompressedChunk(chunks.NewChunkWithHash(hash.Hash(a), data))
+		recLen := addrSize + len(cmp.FullCompressedChunk)
+
+		start := len(buff)
+		buff = append(buff, make([]byte, journalRecLenSize)...)
+		binary.BigEndian.PutUint32(buff[start:], uint32(recLen))
+		buff = append(buff, a[:]...)
+		buff = append(buff, cmp.FullCompressedChunk...)
+
+		offset := j.end + uint64(start) + journalRecLenSize + addrSize
+		written = append(written, newRecord{a, Range{Offset: offset, Length: uint32(len(cmp.FullCompressedChunk))}, uint64(len(data))})
+	}
+
+	if len(buff) > 0 {
+		_, err = j.f.WriteAt(buff, int64(j.end))
+
+		if err != nil {
+			return err
+		}
+
+		err = j.f.Sync()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rec := range written {
+		j.index(rec.a, rec.r, rec.dataLen)
+	}
+
+	j.end += uint64(len(buff))
+	j.pending = nil
+
+	return nil
+}
+
+// truncateTo replaces the journal with one holding only the records after |offset|. Callers must hold j.mu.
+func (j *chunkJournal) truncateTo(offset uint64) error {
+	tail := make([]byte, j.end-offset)
+	_, err := j.f.ReadAt(tail, int64(offset))
+
+	if err != nil {
+		return err
+	}
+
+	tempPath, err := func() (name string, ferr error) {
+		var temp *os.File
+		temp, ferr = ioutil.TempFile(filepath.Dir(j.path), "nbs_journal_")
+
+		if ferr != nil {
+			return "", ferr
+		}
+
+		defer func() {
+			closeErr := temp.Close()
+
+			if ferr == nil {
+				ferr = closeErr
+			}
+		}()
+
+		_, ferr = temp.Write(tail)
+
+		if ferr != nil {
+			return "", ferr
+		}
+
+		return temp.Name(), temp.Sync()
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tempPath) // If we rename below, this will be a no-op
+
+	// The journal is closed first, as renaming over a file which is open fails on Windows. Whether or not the rename
+	// succeeds, refresh reopens the journal and reindexes it.
+	err = j.reset(nil)
+
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tempPath, j.path)
+
+	if err != nil {
+		refreshErr := j.refresh()
+
+		if refreshErr != nil {
+			return refreshErr
+		}
+
+		return err
+	}
+
+	return j.refresh()
+}
+
+// readCompressed reads the compressed chunk record at |r|. Callers must hold j.mu.
+func (j *chunkJournal) readCompressed(a addr, r Range) (CompressedChunk, error) {
+	buff := make([]byte, r.Length)
+	_, err := j.f.ReadAt(buff, int64(r.Offset))
+
+	if err != nil {
+		return CompressedChunk{}, err
+	}
+
+	return NewCompressedChunk(hash.Hash(a), buff)
+}
+
+// readChunk reads and decompresses the chunk record at |r|. Callers must hold j.mu.
+func (j *chunkJournal) readChunk(a addr, r Range) ([]byte, error) {
+	cmp, err := j.readCompressed(a, r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snappy.Decode(nil, cmp.CompressedData)
+}
+
+func (j *chunkJournal) has(h addr) (bool, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.pending != nil {
+		if _, ok := j.pending.chunks[h]; ok {
+			return true, nil
+		}
+	}
+
+	_, ok := j.ranges[h]
+	return ok, nil
+}
+
+func (j *chunkJournal) hasMany(addrs []hasRecord) (bool, error) {
+	var remaining bool
+	for i, hr := range addrs {
+		if hr.has {
+			continue
+		}
+
+		ok, err := j.has(*hr.a)
+
+		if err != nil {
+			return false, err
+		}
+
+		if ok {
+			addrs[i].has = true
+		} else {
+			remaining = true
+		}
+	}
+
+	return remaining, nil
+}
+
+func (j *chunkJournal) get(ctx context.Context, h addr, stats *Stats) ([]byte, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.pending != nil {
+		if data, ok := j.pending.chunks[h]; ok {
+			return data, nil
+		}
+	}
+
+	r, ok := j.ranges[h]
+
+	if !ok {
+		return nil, nil
+	}
+
+	return j.readChunk(h, r)
+}
+
+func (j *chunkJournal) getMany(ctx context.Context, reqs []getRecord, foundChunks chan<- *chunks.Chunk, wg *sync.WaitGroup, ae *atomicerr.AtomicError, stats *Stats) bool {
+	var remaining bool
+	for i, r := range reqs {
+		if r.found {
+			continue
+		}
+
+		data, err := j.get(ctx, *r.a, stats)
+
+		if ae.SetIfError(err) {
+			return false
+		}
+
+		if data != nil {
+			reqs[i].found = true
+			c := chunks.NewChunkWithHash(hash.Hash(*r.a), data)
+			foundChunks <- &c
+		} else {
+			remaining = true
+		}
+	}
+
+	return remaining
+}
+
+func (j *chunkJournal) getManyCompressed(ctx context.Context, reqs []getRecord, foundCmpChunks chan<- CompressedChunk, wg *sync.WaitGroup, ae *atomicerr.AtomicError, stats *Stats) bool {
+	var remaining bool
+	for i, r := range reqs {
+		if r.found {
+			continue
+		}
+
+		cmp, found, err := func() (CompressedChunk, bool, error) {
+			j.mu.RLock()
+			defer j.mu.RUnlock()
+
+			if j.pending != nil {
+				if data, ok := j.pending.chunks[*r.a]; ok {
+					return ChunkToCompressedChunk(chunks.NewChunkWithHash(hash.Hash(*r.a), data)), true, nil
+				}
+			}
+
+			rng, ok := j.ranges[*r.a]
+
+			if !ok {
+				return CompressedChunk{}, false, nil
+			}
+
+			cmp, err := j.readCompressed(*r.a, rng)
+			return cmp, err == nil, err
+		}()
+
+		if ae.SetIfError(err) {
+			return false
+		}
+
+		if found {
+			reqs[i].found = true
+			foundCmpChunks <- cmp
+		} else {
+			remaining = true
+		}
+	}
+
+	return remaining
+}
+
+func (j *chunkJournal) extract(ctx context.Context, chunks chan<- extractRecord) error {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	for _, a := range j.order {
+		data, err := j.readChunk(a, j.ranges[a])
+
+		if err != nil {
+			return err
+		}
+
+		chunks <- extractRecord{a: a, data: data}
+	}
+
+	if j.pending != nil {
+		return j.pending.extract(ctx, chunks)
+	}
+
+	return nil
+}
+
+func (j *chunkJournal) count() (uint32, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	cnt := uint32(len(j.order))
+	if j.pending != nil {
+		cnt += uint32(len(j.pending.order))
+	}
+
+	return cnt, nil
+}
+
+func (j *chunkJournal) uncompressedLen() (uint64, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	l := j.uncompressed
+	if j.pending != nil {
+		l += j.pending.totalData
+	}
+
+	return l, nil
+}
+
+func (j *chunkJournal) close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.reset(nil)
+}
+
+// journaledTableSet is a chunkReader which consults a tableSet and then a chunkJournal.
+type journaledTableSet struct {
+	tableSet
+	j *chunkJournal
+}
+
+func (jts journaledTableSet) has(h addr) (bool, error) {
+	has, err := jts.tableSet.has(h)
+
+	if err != nil || has {
+		return has, err
+	}
+
+	return jts.j.has(h)
+}
+
+func (jts journaledTableSet) hasMany(addrs []hasRecord) (bool, error) {
+	remaining, err := jts.tableSet.hasMany(addrs)
+
+	if err != nil || !remaining {
+		return remaining, err
+	}
+
+	return jts.j.hasMany(addrs)
+}
+
+func (jts journaledTableSet) get(ctx context.Context, h addr, stats *Stats) ([]byte, error) {
+	data, err := jts.tableSet.get(ctx, h, stats)
+
+	if err != nil || data != nil {
+		return data, err
+	}
+
+	return jts.j.get(ctx, h, stats)
+}
+
+func (jts journaledTableSet) getMany(ctx context.Context, reqs []getRecord, foundChunks chan<- *chunks.Chunk, wg *sync.WaitGroup, ae *atomicerr.AtomicError, stats *Stats) bool {
+	if !jts.tableSet.getMany(ctx, reqs, foundChunks, wg, ae, stats) {
+		return false
+	}
+
+	return jts.j.getMany(ctx, reqs, foundChunks, wg, ae, stats)
+}
+
+func (jts journaledTableSet) getManyCompressed(ctx context.Context, reqs []getRecord, foundCmpChunks chan<- CompressedChunk, wg *sync.WaitGroup, ae *atomicerr.AtomicError, stats *Stats) bool {
+	if !jts.tableSet.getManyCompressed(ctx, reqs, foundCmpChunks, wg, ae, stats) {
+		return false
+	}
+
+	return jts.j.getManyCompressed(ctx, reqs, foundCmpChunks, wg, ae, stats)
+}
+
+func (jts journaledTableSet) extract(ctx context.Context, chunks chan<- extractRecord) error {
+	err := jts.tableSet.extract(ctx, chunks)
+
+	if err != nil {
+		return err
+	}
+
+	return jts.j.extract(ctx, chunks)
+}
+
+func (jts journaledTableSet) count() (uint32, error) {
+	cnt, err := jts.tableSet.count()
+
+	if err != nil {
+		return 0, err
+	}
+
+	journalCnt, err := jts.j.count()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return cnt + journalCnt, nil
+}
+
+func (jts journaledTableSet) uncompressedLen() (uint64, error) {
+	l, err := jts.tableSet.uncompressedLen()
+
+	if err != nil {
+		return 0, err
+	}
+
+	journalLen, err := jts.j.uncompressedLen()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return l + journalLen, nil
+}
+
+// journalManifest is a fileManifest which appends staged chunks to, and folds, a chunkJournal as part of each
+// successful update.
+type journalManifest struct {
+	fileManifest
+	journal *chunkJournal
+}
+
+func (jm journalManifest) Update(ctx context.Context, lastLock addr, newContents manifestContents, stats *Stats, writeHook func() error) (manifestContents, error) {
+	return jm.update(ctx, lastLock, newContents, stats, writeHook, jm.journal.commit)
+}
@@ -0,0 +1,199 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func makeJournalTestDir(t *testing.T) string {
+	dir := filepath.Join(os.TempDir(), uuid.New().String())
+	err := os.MkdirAll(dir, os.ModePerm)
+	require.NoError(t, err)
+	return dir
+}
+
+func putJournalTestChunks(t *testing.T, st *NomsBlockStore, prefix string, n int) []chunks.Chunk {
+	ctx := context.Background()
+	var chks []chunks.Chunk
+	for i := 0; i < n; i++ {
+		c := chunks.NewChunk([]byte(fmt.Sprintf("%s:%d", prefix, i)))
+		err := st.Put(ctx, c)
+		require.NoError(t, err)
+		chks = append(chks, c)
+	}
+
+	root, err := st.Root(ctx)
+	require.NoError(t, err)
+	success, err := st.Commit(ctx, chks[0].Hash(), root)
+	require.NoError(t, err)
+	require.True(t, success)
+
+	return chks
+}
+
+func requireChunks(t *testing.T, st *NomsBlockStore, chks []chunks.Chunk) {
+	ctx := context.Background()
+	for _, c := range chks {
+		actual, err := st.Get(ctx, c.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, c.Data(), actual.Data())
+	}
+
+	hashes := hash.HashSet{}
+	for _, c := range chks {
+		hashes.Insert(c.Hash())
+	}
+
+	absent, err := st.HasMany(ctx, hashes)
+	require.NoError(t, err)
+	assert.Empty(t, absent)
+}
+
+func TestChunkJournalCommit(t *testing.T) {
+	ctx := context.Background()
+	dir := makeJournalTestDir(t)
+	defer os.RemoveAll(dir)
+
+	st, err := NewLocalJournalingStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	defer st.Close()
+
+	chks := putJournalTestChunks(t, st, "first", 32)
+	chks = append(chks, putJournalTestChunks(t, st, "second", 32)...)
+	requireChunks(t, st, chks)
+
+	// No table files should have been written.
+	assert.Equal(t, 0, st.tables.Size())
+	assert.NotZero(t, st.journal.size())
+
+	other, err := NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	defer other.Close()
+
+	root, err := other.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, chks[32].Hash(), root)
+	requireChunks(t, other, chks)
+
+	cnt, err := other.Count()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(64), cnt)
+}
+
+func TestChunkJournalTornRecord(t *testing.T) {
+	ctx := context.Background()
+	dir := makeJournalTestDir(t)
+	defer os.RemoveAll(dir)
+
+	st, err := NewLocalJournalingStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	chks := putJournalTestChunks(t, st, "first", 16)
+	require.NoError(t, st.Close())
+
+	// Simulate a writer which crashed part way through appending a record.
+	f, err := os.OpenFile(filepath.Join(dir, chunkJournalFileName), os.O_WRONLY|os.O_APPEND, 0666)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 1, 0, 0xde, 0xad})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	st, err = NewLocalJournalingStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	requireChunks(t, st, chks)
+
+	chks = append(chks, putJournalTestChunks(t, st, "second", 16)...)
+	require.NoError(t, st.Close())
+
+	st, err = NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	defer st.Close()
+	requireChunks(t, st, chks)
+}
+
+func TestChunkJournalFold(t *testing.T) {
+	ctx := context.Background()
+	dir := makeJournalTestDir(t)
+	defer os.RemoveAll(dir)
+
+	st, err := NewLocalJournalingStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	defer st.Close()
+
+	chks := putJournalTestChunks(t, st, "first", 32)
+	journalRoot, err := st.Root(ctx)
+	require.NoError(t, err)
+
+	// Listing the store's table files folds the journal into a table file.
+	root, sources, err := st.Sources(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, journalRoot, root)
+	require.Len(t, sources, 1)
+	assert.Equal(t, 32, sources[0].NumChunks())
+	assert.Zero(t, st.journal.size())
+	requireChunks(t, st, chks)
+
+	chks = append(chks, putJournalTestChunks(t, st, "second", 8)...)
+	assert.NotZero(t, st.journal.size())
+
+	other, err := NewLocalStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	defer other.Close()
+	requireChunks(t, other, chks)
+}
+
+// Folding the journal replaces it, which must work while other stores have it open. On Windows, renaming over a file
+// which is open fails, in which case the folded records are kept and folded again later.
+func TestChunkJournalFoldWhileOpen(t *testing.T) {
+	ctx := context.Background()
+	dir := makeJournalTestDir(t)
+	defer os.RemoveAll(dir)
+
+	st, err := NewLocalJournalingStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	defer st.Close()
+
+	other, err := NewLocalJournalingStore(ctx, types.Format_Default.VersionString(), dir, 0)
+	require.NoError(t, err)
+	defer other.Close()
+
+	chks := putJournalTestChunks(t, st, "first", 16)
+	require.NoError(t, other.Rebase(ctx))
+	requireChunks(t, other, chks)
+
+	_, _, err = st.Sources(ctx)
+	require.NoError(t, err)
+	requireChunks(t, st, chks)
+
+	require.NoError(t, other.Rebase(ctx))
+	requireChunks(t, other, chks)
+
+	chks = append(chks, putJournalTestChunks(t, other, "second", 16)...)
+	require.NoError(t, st.Rebase(ctx))
+	requireChunks(t, st, chks)
+	requireChunks(t, other, chks)
+}
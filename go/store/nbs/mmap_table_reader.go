@@ -55,7 +55,22 @@ func init() {
 	}
 }
 
-func newMmapTableReader(dir string, h addr, chunkCount uint32, indexCache *indexCache, fc *fdCache) (cs chunkSource, err error) {
+// tableIndexLoader reads the index of the table file |f|, which is |size| bytes long and holds |chunkCount| chunks.
+type tableIndexLoader func(f *os.File, path string, size int64, chunkCount uint32) (tableIndex, error)
+
+// newMmapTableReader returns a chunkSource for the table file |h| in |dir| whose index is read by mmapping the end of
+// the file.
+func newMmapTableReader(dir string, h addr, chunkCount uint32, indexCache *indexCache, fc *fdCache) (chunkSource, error) {
+	return newFileTableReader(dir, h, chunkCount, indexCache, fc, mmapTableIndex)
+}
+
+// newPureIOTableReader returns a chunkSource for the table file |h| in |dir| which never mmaps the file. Its index is
+// read with ReadAt instead, for platforms and filesystems where mapping many large files is unreliable.
+func newPureIOTableReader(dir string, h addr, chunkCount uint32, indexCache *indexCache, fc *fdCache) (chunkSource, error) {
+	return newFileTableReader(dir, h, chunkCount, indexCache, fc, readTableIndex)
+}
+
+func newFileTableReader(dir string, h addr, chunkCount uint32, indexCache *indexCache, fc *fdCache, loadIndex tableIndexLoader) (cs chunkSource, err error) {
 	path := filepath.Join(dir, h.String())
 
 	var index tableIndex
@@ -102,32 +117,7 @@ func newMmapTableReader(dir string, h addr, chunkCount uint32, indexCache *index
 				return
 			}
 
-			// index. Mmap won't take an offset that's not page-aligned, so find the nearest page boundary preceding the index.
-			indexOffset := fi.Size() - int64(footerSize) - int64(indexSize(chunkCount))
-			aligned := indexOffset / mmapAlignment * mmapAlignment // Thanks, integer arithmetic!
-
-			if fi.Size()-aligned > maxInt {
-				err = fmt.Errorf("%s - size: %d alignment: %d> maxInt: %d", path, fi.Size(), aligned, maxInt)
-				return
-			}
-
-			var mm mmap.MMap
-			mm, err = mmap.MapRegion(f, int(fi.Size()-aligned), mmap.RDONLY, 0, aligned)
-
-			if err != nil {
-				return
-			}
-
-			defer func() {
-				unmapErr := mm.Unmap()
-
-				if unmapErr != nil {
-					err = unmapErr
-				}
-			}()
-
-			buff := []byte(mm)
-			ti, err = parseTableIndex(buff[indexOffset-aligned:])
+			ti, err = loadIndex(f, path, fi.Size(), chunkCount)
 
 			if err != nil {
 				return
@@ -159,6 +149,52 @@ func newMmapTableReader(dir string, h addr, chunkCount uint32, indexCache *index
 	}, nil
 }
 
+func mmapTableIndex(f *os.File, path string, size int64, chunkCount uint32) (ti tableIndex, err error) {
+	// index. Mmap won't take an offset that's not page-aligned, so find the nearest page boundary preceding the index.
+	indexOffset := size - int64(footerSize) - int64(indexSize(chunkCount))
+	aligned := indexOffset / mmapAlignment * mmapAlignment // Thanks, integer arithmetic!
+
+	if size-aligned > maxInt {
+		err = fmt.Errorf("%s - size: %d alignment: %d> maxInt: %d", path, size, aligned, maxInt)
+		return
+	}
+
+	var mm mmap.MMap
+	mm, err = mmap.MapRegion(f, int(size-aligned), mmap.RDONLY, 0, aligned)
+
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		unmapErr := mm.Unmap()
+
+		if unmapErr != nil {
+			err = unmapErr
+		}
+	}()
+
+	buff := []byte(mm)
+	return parseTableIndex(buff[indexOffset-aligned:])
+}
+
+func readTableIndex(f *os.File, path string, size int64, chunkCount uint32) (tableIndex, error) {
+	indexOffset := size - int64(footerSize) - int64(indexSize(chunkCount))
+
+	if indexOffset < 0 || size-indexOffset > maxInt {
+		return tableIndex{}, fmt.Errorf("%s - size: %d index offset: %d", path, size, indexOffset)
+	}
+
+	buff := make([]byte, size-indexOffset)
+	_, err := f.ReadAt(buff, indexOffset)
+
+	if err != nil {
+		return tableIndex{}, err
+	}
+
+	return parseTableIndex(buff)
+}
+
 func (mmtr *mmapTableReader) hash() (addr, error) {
 	return mmtr.h, nil
 }
@@ -22,12 +22,15 @@
 package nbs
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMmapTableReader(t *testing.T) {
@@ -54,3 +57,69 @@ func TestMmapTableReader(t *testing.T) {
 	assert.NoError(err)
 	assertChunksInReader(chunks, trc, assert)
 }
+
+func TestPureIOTableReader(t *testing.T) {
+	assert := assert.New(t)
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fc := newFDCache(1)
+	defer fc.Drop()
+
+	chunks := [][]byte{
+		[]byte("hello2"),
+		[]byte("goodbye2"),
+		[]byte("badbye2"),
+	}
+
+	tableData, h, err := buildTable(chunks)
+	assert.NoError(err)
+	err = ioutil.WriteFile(filepath.Join(dir, h.String()), tableData, 0666)
+	assert.NoError(err)
+
+	trc, err := newPureIOTableReader(dir, h, uint32(len(chunks)), nil, fc)
+	assert.NoError(err)
+	assertChunksInReader(chunks, trc, assert)
+}
+
+func BenchmarkMmapTableReader(b *testing.B) {
+	benchmarkFileTableReader(b, newMmapTableReader)
+}
+
+func BenchmarkPureIOTableReader(b *testing.B) {
+	benchmarkFileTableReader(b, newPureIOTableReader)
+}
+
+// benchmarkFileTableReader measures opening a table file with a large index and reading every chunk from it.
+func benchmarkFileTableReader(b *testing.B, open func(dir string, h addr, chunkCount uint32, indexCache *indexCache, fc *fdCache) (chunkSource, error)) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(b, err)
+	defer os.RemoveAll(dir)
+
+	fc := newFDCache(1)
+	defer fc.Drop()
+
+	chunks := make([][]byte, 1<<14)
+	for i := range chunks {
+		chunks[i] = []byte(fmt.Sprintf("chunk data %d", i))
+	}
+
+	tableData, h, err := buildTable(chunks)
+	require.NoError(b, err)
+	err = ioutil.WriteFile(filepath.Join(dir, h.String()), tableData, 0666)
+	require.NoError(b, err)
+
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trc, err := open(dir, h, uint32(len(chunks)), nil, fc)
+		require.NoError(b, err)
+
+		for _, c := range chunks {
+			data, err := trc.get(ctx, computeAddr(c), &Stats{})
+			require.NoError(b, err)
+			require.Equal(b, c, data)
+		}
+	}
+}
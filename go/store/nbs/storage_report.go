@@ -0,0 +1,224 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"context"
+	"math/bits"
+	"sort"
+
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+// StorageReporter is implemented by chunk stores that can describe how their chunks are laid out in table files.
+type StorageReporter interface {
+	StorageReport(ctx context.Context, roots []hash.Hash) (StorageReport, error)
+}
+
+// StorageReport describes the chunks stored in the table files of a NomsBlockStore. Sizes are of chunk records as
+// they are stored, compressed and including their checksums.
+type StorageReport struct {
+	// Root is the root hash of the store when the report was built
+	Root hash.Hash
+	// Tables has an entry for each table file in the manifest, in manifest order
+	Tables []TableFileReport
+	// ChunkSizes is a histogram of the sizes of the chunk records in all table files
+	ChunkSizes []ChunkSizeBucket
+
+	TotalChunks  uint64
+	TotalBytes   uint64
+	UniqueChunks uint64
+
+	// DuplicateChunks and DuplicateBytes count the copies of chunks beyond the first, which conjoining the table files
+	// that hold them would remove.
+	DuplicateChunks uint64
+	DuplicateBytes  uint64
+
+	// GarbageChunks and GarbageBytes count the chunk records that are not reachable from Root or the other roots the
+	// report was built with, which a garbage collection would remove.
+	GarbageChunks uint64
+	GarbageBytes  uint64
+}
+
+// TableFileReport describes the chunks in a single table file.
+type TableFileReport struct {
+	Name              string
+	Chunks            uint64
+	Bytes             uint64
+	UncompressedBytes uint64
+
+	// DuplicateChunks and DuplicateBytes count the chunks also stored in a table file earlier in the manifest
+	DuplicateChunks uint64
+	DuplicateBytes  uint64
+
+	// GarbageChunks and GarbageBytes count the chunks not reachable from any root
+	GarbageChunks uint64
+	GarbageBytes  uint64
+}
+
+// ChunkSizeBucket counts the chunk records larger than half of UpperBound, and no larger than UpperBound.
+type ChunkSizeBucket struct {
+	UpperBound uint64
+	Chunks     uint64
+	Bytes      uint64
+}
+
+// StorageReport scans the indexes of the store's table files and walks the chunks reachable from its root, and from
+// |roots|, to build a StorageReport. |roots| holds the values that are referenced from outside of the store, which
+// must not be counted as garbage. Chunks in the chunk journal are first folded into a table file, as they are for
+// Sources.
+func (nbs *NomsBlockStore) StorageReport(ctx context.Context, roots []hash.Hash) (StorageReport, error) {
+	err := nbs.foldJournal(ctx)
+
+	if err != nil {
+		return StorageReport{}, err
+	}
+
+	nbs.mu.RLock()
+	root := nbs.upstream.root
+	sources := append(chunkSources(nil), nbs.tables.upstream...)
+	nbs.mu.RUnlock()
+
+	nbf, err := types.GetFormatForVersionString(nbs.Version())
+
+	if err != nil {
+		return StorageReport{}, err
+	}
+
+	reachable, err := nbs.reachableChunks(ctx, append([]hash.Hash{root}, roots...), nbf)
+
+	if err != nil {
+		return StorageReport{}, err
+	}
+
+	report := StorageReport{Root: root}
+	seen := make(map[addr]struct{})
+	buckets := make(map[int]*ChunkSizeBucket)
+	for _, src := range sources {
+		name, err := src.hash()
+
+		if err != nil {
+			return StorageReport{}, err
+		}
+
+		idx, err := src.index()
+
+		if err != nil {
+			return StorageReport{}, err
+		}
+
+		tr := TableFileReport{Name: name.String(), Chunks: uint64(idx.chunkCount), UncompressedBytes: idx.totalUncompressedData}
+		for i := uint32(0); i < idx.chunkCount; i++ {
+			ordinal := idx.prefixIdxToOrdinal(i)
+			a := idx.addrAt(i)
+			size := uint64(idx.lengths[ordinal])
+
+			tr.Bytes += size
+
+			bucket := bits.Len64(size - 1)
+			if buckets[bucket] == nil {
+				buckets[bucket] = &ChunkSizeBucket{UpperBound: 1 << uint(bucket)}
+			}
+			buckets[bucket].Chunks++
+			buckets[bucket].Bytes += size
+
+			if _, ok := seen[a]; ok {
+				tr.DuplicateChunks++
+				tr.DuplicateBytes += size
+			} else {
+				seen[a] = struct{}{}
+			}
+
+			if !reachable.Has(hash.Hash(a)) {
+				tr.GarbageChunks++
+				tr.GarbageBytes += size
+			}
+		}
+
+		report.Tables = append(report.Tables, tr)
+		report.TotalChunks += tr.Chunks
+		report.TotalBytes += tr.Bytes
+		report.DuplicateChunks += tr.DuplicateChunks
+		report.DuplicateBytes += tr.DuplicateBytes
+		report.GarbageChunks += tr.GarbageChunks
+		report.GarbageBytes += tr.GarbageBytes
+	}
+
+	report.UniqueChunks = uint64(len(seen))
+
+	for _, b := range buckets {
+		report.ChunkSizes = append(report.ChunkSizes, *b)
+	}
+
+	sort.Slice(report.ChunkSizes, func(i, j int) bool {
+		return report.ChunkSizes[i].UpperBound < report.ChunkSizes[j].UpperBound
+	})
+
+	return report, nil
+}
+
+// reachableChunks returns the addresses of every chunk reachable from |roots|, including |roots|.
+func (nbs *NomsBlockStore) reachableChunks(ctx context.Context, roots []hash.Hash, nbf *types.NomsBinFormat) (hash.HashSet, error) {
+	reachable := hash.HashSet{}
+	frontier := hash.HashSet{}
+	for _, root := range roots {
+		if !root.IsEmpty() {
+			reachable.Insert(root)
+			frontier.Insert(root)
+		}
+	}
+
+	for len(frontier) > 0 {
+		var found []*chunks.Chunk
+		foundChunks := make(chan *chunks.Chunk, 128)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for c := range foundChunks {
+				found = append(found, c)
+			}
+		}()
+
+		err := nbs.GetMany(ctx, frontier, foundChunks)
+		close(foundChunks)
+		<-done
+
+		if err != nil {
+			return nil, err
+		}
+
+		next := hash.HashSet{}
+		for _, c := range found {
+			err = types.WalkRefs(*c, nbf, func(r types.Ref) error {
+				h := r.TargetHash()
+				if !reachable.Has(h) {
+					reachable.Insert(h)
+					next.Insert(h)
+				}
+				return nil
+			})
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		frontier = next
+	}
+
+	return reachable, nil
+}
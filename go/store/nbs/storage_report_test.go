@@ -0,0 +1,88 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nbs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/hash"
+	"github.com/liquidata-inc/dolt/go/store/types"
+)
+
+func TestStorageReport(t *testing.T) {
+	ctx := context.Background()
+	testDir := makeLocalStoreTestDir(t)
+	defer os.RemoveAll(testDir)
+
+	st, err := NewLocalStore(ctx, types.Format_Default.VersionString(), testDir, defaultMemTableSize)
+	require.NoError(t, err)
+	defer st.Close()
+
+	vs := types.NewValueStore(st)
+	one, err := vs.WriteValue(ctx, types.String("one"))
+	require.NoError(t, err)
+	two, err := vs.WriteValue(ctx, types.String("two"))
+	require.NoError(t, err)
+	_, err = vs.WriteValue(ctx, types.String("unreachable"))
+	require.NoError(t, err)
+	kept, err := vs.WriteValue(ctx, types.String("kept by an outside root"))
+	require.NoError(t, err)
+	l, err := types.NewList(ctx, vs, one, two)
+	require.NoError(t, err)
+	root, err := vs.WriteValue(ctx, l)
+	require.NoError(t, err)
+	_, err = vs.Commit(ctx, root.TargetHash(), hash.Hash{})
+	require.NoError(t, err)
+
+	// a second copy of "two" in its own table file
+	c, err := types.EncodeValue(types.String("two"), vs.Format())
+	require.NoError(t, err)
+	data, name, err := buildTable([][]byte{c.Data()})
+	require.NoError(t, err)
+	err = st.WriteTableFile(ctx, name.String(), 1, bytes.NewReader(data), 0, nil)
+	require.NoError(t, err)
+
+	report, err := st.StorageReport(ctx, []hash.Hash{kept.TargetHash()})
+	require.NoError(t, err)
+
+	assert.Equal(t, root.TargetHash(), report.Root)
+	assert.Len(t, report.Tables, 2)
+	assert.Equal(t, uint64(6), report.TotalChunks)
+	assert.Equal(t, uint64(5), report.UniqueChunks)
+	assert.Equal(t, uint64(1), report.DuplicateChunks)
+	assert.Equal(t, uint64(1), report.GarbageChunks)
+
+	var histChunks, histBytes, tableBytes uint64
+	for _, b := range report.ChunkSizes {
+		histChunks += b.Chunks
+		histBytes += b.Bytes
+	}
+	for _, tr := range report.Tables {
+		tableBytes += tr.Bytes
+	}
+	assert.Equal(t, report.TotalChunks, histChunks)
+	assert.Equal(t, report.TotalBytes, histBytes)
+	assert.Equal(t, report.TotalBytes, tableBytes)
+
+	report, err = st.StorageReport(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), report.GarbageChunks)
+}
@@ -25,6 +25,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -86,6 +87,10 @@ type NomsBlockStore struct {
 	tables   tableSet
 	upstream manifestContents
 
+	journal       *chunkJournal // nil unless the store is local
+	journalWrites bool          // true if small sets of novel chunks are written to the journal
+	foldOffset    uint64        // end of the journal records being folded into a novel table, if any
+
 	mtSize   uint64
 	putCount uint64
 
@@ -272,7 +277,29 @@ func NewGCSStore(ctx context.Context, nbfVerStr string, bucketName, path string,
 	return newNomsBlockStore(ctx, nbfVerStr, mm, p, inlineConjoiner{defaultMaxTables}, memTableSize)
 }
 
+// LocalStoreOptions configures the stores returned by NewLocalStoreWithOptions.
+type LocalStoreOptions struct {
+	// JournalWrites causes small sets of novel chunks to be written to an append-only chunk journal rather than to new
+	// table files, which makes committing them much cheaper. The journal is folded into a table file once it grows
+	// large. Stores which don't journal writes still read journaled chunks.
+	JournalWrites bool
+
+	// PureIO causes table files to be read using only ReadAt, never mmap, for platforms and filesystems where mapping
+	// many large files is unreliable.
+	PureIO bool
+}
+
 func NewLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64) (*NomsBlockStore, error) {
+	return NewLocalStoreWithOptions(ctx, nbfVerStr, dir, memTableSize, LocalStoreOptions{})
+}
+
+// NewLocalJournalingStore returns a local store which writes small sets of novel chunks to the chunk journal. See
+// LocalStoreOptions.JournalWrites.
+func NewLocalJournalingStore(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64) (*NomsBlockStore, error) {
+	return NewLocalStoreWithOptions(ctx, nbfVerStr, dir, memTableSize, LocalStoreOptions{JournalWrites: true})
+}
+
+func NewLocalStoreWithOptions(ctx context.Context, nbfVerStr string, dir string, memTableSize uint64, opts LocalStoreOptions) (*NomsBlockStore, error) {
 	cacheOnce.Do(makeGlobalCaches)
 	err := checkDir(dir)
 
@@ -280,9 +307,24 @@ func NewLocalStore(ctx context.Context, nbfVerStr string, dir string, memTableSi
 		return nil, err
 	}
 
-	mm := makeManifestManager(fileManifest{dir})
-	p := newFSTablePersister(dir, globalFDCache, globalIndexCache)
-	return newNomsBlockStore(ctx, nbfVerStr, mm, p, inlineConjoiner{defaultMaxTables}, memTableSize)
+	j, err := openChunkJournal(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	mm := makeManifestManager(journalManifest{fileManifest{dir}, j})
+	p := newFSTablePersister(dir, globalFDCache, globalIndexCache, opts.PureIO)
+	nbs, err := newNomsBlockStore(ctx, nbfVerStr, mm, p, inlineConjoiner{defaultMaxTables}, memTableSize)
+
+	if err != nil {
+		_ = j.close()
+		return nil, err
+	}
+
+	nbs.journal = j
+	nbs.journalWrites = opts.JournalWrites
+	return nbs, nil
 }
 
 func checkDir(dir string) error {
@@ -384,7 +426,7 @@ func (nbs *NomsBlockStore) Get(ctx context.Context, h hash.Hash) (chunks.Chunk,
 				return nil, nil, err
 			}
 		}
-		return data, nbs.tables, nil
+		return data, nbs.readers(), nil
 	}()
 
 	if err != nil {
@@ -441,7 +483,7 @@ func (nbs *NomsBlockStore) getManyWithFunc(
 	tables, remaining := func() (tables chunkReader, remaining bool) {
 		nbs.mu.RLock()
 		defer nbs.mu.RUnlock()
-		tables = nbs.tables
+		tables = nbs.readers()
 		remaining = true
 		if nbs.mt != nil {
 			remaining = getManyFunc(ctx, nbs.mt, reqs, nil, ae, nbs.stats)
@@ -513,7 +555,7 @@ func (nbs *NomsBlockStore) Count() (uint32, error) {
 			return 0, nil, err
 		}
 
-		return count, nbs.tables, nil
+		return count, nbs.readers(), nil
 	}()
 
 	if err != nil {
@@ -548,10 +590,10 @@ func (nbs *NomsBlockStore) Has(ctx context.Context, h hash.Hash) (bool, error) {
 				return false, nil, err
 			}
 
-			return has, nbs.tables, nil
+			return has, nbs.readers(), nil
 		}
 
-		return false, nbs.tables, nil
+		return false, nbs.readers(), nil
 	}()
 
 	if err != nil {
@@ -577,7 +619,7 @@ func (nbs *NomsBlockStore) HasMany(ctx context.Context, hashes hash.HashSet) (ha
 	tables, remaining, err := func() (tables chunkReader, remaining bool, err error) {
 		nbs.mu.RLock()
 		defer nbs.mu.RUnlock()
-		tables = nbs.tables
+		tables = nbs.readers()
 
 		remaining = true
 		if nbs.mt != nil {
@@ -617,6 +659,15 @@ func (nbs *NomsBlockStore) HasMany(ctx context.Context, hashes hash.HashSet) (ha
 	return absent, nil
 }
 
+// readers returns the chunkReader holding every chunk not in the memTable. Callers must hold nbs.mu.
+func (nbs *NomsBlockStore) readers() chunkReader {
+	if nbs.journal == nil {
+		return nbs.tables
+	}
+
+	return journaledTableSet{nbs.tables, nbs.journal}
+}
+
 func toHasRecords(hashes hash.HashSet) []hasRecord {
 	reqs := make([]hasRecord, len(hashes))
 	idx := 0
@@ -654,6 +705,10 @@ func (nbs *NomsBlockStore) Rebase(ctx context.Context) error {
 		nbs.tables = newTables
 	}
 
+	if nbs.journal != nil {
+		return nbs.journal.reload()
+	}
+
 	return nil
 }
 
@@ -670,7 +725,7 @@ func (nbs *NomsBlockStore) Commit(ctx context.Context, current, last hash.Hash)
 	anyPossiblyNovelChunks := func() bool {
 		nbs.mu.Lock()
 		defer nbs.mu.Unlock()
-		return nbs.mt != nil || nbs.tables.Novel() > 0
+		return nbs.mt != nil || nbs.tables.Novel() > 0 || (nbs.journal != nil && nbs.journal.hasPending())
 	}
 
 	if !anyPossiblyNovelChunks() && current == last {
@@ -700,7 +755,7 @@ func (nbs *NomsBlockStore) Commit(ctx context.Context, current, last hash.Hash)
 				return err
 			}
 
-			if cnt > preflushChunkCount {
+			if cnt > preflushChunkCount && !nbs.journalable(nbs.mt) {
 				nbs.tables = nbs.tables.Prepend(ctx, nbs.mt, nbs.stats)
 				nbs.mt = nil
 			}
@@ -723,7 +778,7 @@ func (nbs *NomsBlockStore) Commit(ctx context.Context, current, last hash.Hash)
 	}()
 
 	for {
-		if err := nbs.updateManifest(ctx, current, last); err == nil {
+		if err := nbs.updateManifest(ctx, current, last, false); err == nil {
 			return true, nil
 		} else if err == errOptimisticLockFailedRoot || err == errLastRootMismatch {
 			return false, nil
@@ -741,7 +796,15 @@ var (
 	errOptimisticLockFailedTables = fmt.Errorf("tables changed")
 )
 
-func (nbs *NomsBlockStore) updateManifest(ctx context.Context, current, last hash.Hash) error {
+// journalable returns true if the chunks in |mt| should be written to the chunk journal rather than a table file.
+func (nbs *NomsBlockStore) journalable(mt *memTable) bool {
+	return nbs.journalWrites && mt.totalData <= defaultJournalWriteSize
+}
+
+// updateManifest tries to update the manifest to reference |current| and all the chunks written to the store. If
+// |foldJournal| is true, the chunk journal is folded into a table file even if it hasn't reached
+// defaultJournalFoldSize.
+func (nbs *NomsBlockStore) updateManifest(ctx context.Context, current, last hash.Hash, foldJournal bool) error {
 	nbs.mu.Lock()
 	defer nbs.mu.Unlock()
 	if nbs.upstream.root != last {
@@ -757,6 +820,14 @@ func (nbs *NomsBlockStore) updateManifest(ctx context.Context, current, last has
 		nbs.upstream = upstream
 		nbs.tables = newTables
 
+		if nbs.journal != nil {
+			err = nbs.journal.reload()
+
+			if err != nil {
+				return err
+			}
+		}
+
 		if last != upstream.root {
 			return errOptimisticLockFailedRoot
 		}
@@ -777,11 +848,32 @@ func (nbs *NomsBlockStore) updateManifest(ctx context.Context, current, last has
 		}
 
 		if cnt > 0 {
-			nbs.tables = nbs.tables.Prepend(ctx, nbs.mt, nbs.stats)
+			if nbs.journalable(nbs.mt) {
+				nbs.journal.stage(nbs.mt, 0)
+			} else {
+				nbs.tables = nbs.tables.Prepend(ctx, nbs.mt, nbs.stats)
+			}
 			nbs.mt = nil
 		}
 	}
 
+	if nbs.journal != nil && nbs.foldOffset == 0 && (foldJournal || nbs.journal.size() >= defaultJournalFoldSize) {
+		mt, offset, err := nbs.journal.snapshot(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		if offset > 0 {
+			nbs.tables = nbs.tables.Prepend(ctx, mt, nbs.stats)
+			nbs.foldOffset = offset
+		}
+	}
+
+	if nbs.journal != nil {
+		nbs.journal.stage(nil, nbs.foldOffset)
+	}
+
 	if nbs.c.ConjoinRequired(nbs.tables) {
 		var err error
 		newUpstream, err := nbs.c.Conjoin(ctx, nbs.upstream, nbs.mm, nbs.p, nbs.stats)
@@ -833,6 +925,7 @@ func (nbs *NomsBlockStore) updateManifest(ctx context.Context, current, last has
 
 	nbs.upstream = newContents
 	nbs.tables = newTables
+	nbs.foldOffset = 0
 
 	return nil
 }
@@ -842,6 +935,10 @@ func (nbs *NomsBlockStore) Version() string {
 }
 
 func (nbs *NomsBlockStore) Close() (err error) {
+	if nbs.journal != nil {
+		return nbs.journal.close()
+	}
+
 	return
 }
 
@@ -908,6 +1005,12 @@ func (tf NomsBlockStoreTableFile) Open() (io.ReadCloser, error) {
 
 // Sources retrieves the current root hash, and a list of all the table files
 func (nbs *NomsBlockStore) Sources(ctx context.Context) (hash.Hash, []TableFile, error) {
+	err := nbs.foldJournal(ctx)
+
+	if err != nil {
+		return hash.Hash{}, nil, err
+	}
+
 	nbs.mu.Lock()
 	defer nbs.mu.Unlock()
 
@@ -943,6 +1046,39 @@ func (nbs *NomsBlockStore) Sources(ctx context.Context) (hash.Hash, []TableFile,
 	return contents.GetRoot(), tableFiles, nil
 }
 
+// foldJournal writes the chunks in the chunk journal to a table file so that every chunk referenced by the manifest
+// is in one of its table files.
+func (nbs *NomsBlockStore) foldJournal(ctx context.Context) (err error) {
+	if nbs.journal == nil || nbs.journal.size() == 0 {
+		return nil
+	}
+
+	nbs.mm.LockForUpdate()
+	defer func() {
+		unlockErr := nbs.mm.UnlockForUpdate()
+
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
+	for {
+		root, err := nbs.Root(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		err = nbs.updateManifest(ctx, root, root, true)
+
+		if err == nil {
+			return nil
+		} else if err != errOptimisticLockFailedRoot && err != errOptimisticLockFailedTables && err != errLastRootMismatch {
+			return err
+		}
+	}
+}
+
 // WriteTableFile will read a table file from the provided reader and write it to the TableFileStore
 func (nbs *NomsBlockStore) WriteTableFile(ctx context.Context, fileId string, numChunks int, rd io.Reader, contentLength uint64, contentHash []byte) error {
 	fsPersister, ok := nbs.p.(*fsTablePersister)
@@ -951,14 +1087,14 @@ func (nbs *NomsBlockStore) WriteTableFile(ctx context.Context, fileId string, nu
 		return errors.New("Not implemented")
 	}
 
-	path := filepath.Join(fsPersister.dir, fileId)
-
-	err := func() (err error) {
+	// The table file is written to a temporary file and renamed into place, so that a partially written file is never
+	// visible under its final name and an existing copy, which may be open, is never truncated.
+	tempName, err := func() (name string, err error) {
 		var f *os.File
-		f, err = os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.ModePerm)
+		f, err = ioutil.TempFile(fsPersister.dir, tempTablePrefix)
 
 		if err != nil {
-			return err
+			return "", err
 		}
 
 		defer func() {
@@ -971,9 +1107,19 @@ func (nbs *NomsBlockStore) WriteTableFile(ctx context.Context, fileId string, nu
 
 		_, err = io.Copy(f, rd)
 
-		return err
+		return f.Name(), err
 	}()
 
+	if tempName != "" {
+		defer os.Remove(tempName) // If we rename below, this will be a no-op
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = renameTableFile(tempName, filepath.Join(fsPersister.dir, fileId))
+
 	if err != nil {
 		return err
 	}
@@ -992,7 +1138,7 @@ func (nbs *NomsBlockStore) WriteTableFile(ctx context.Context, fileId string, nu
 // SetRootChunk changes the root chunk hash from the previous value to the new root.
 func (nbs *NomsBlockStore) SetRootChunk(ctx context.Context, root, previous hash.Hash) error {
 	for {
-		err := nbs.updateManifest(ctx, root, previous)
+		err := nbs.updateManifest(ctx, root, previous, false)
 
 		if err == nil {
 			return nil
@@ -21,12 +21,14 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/liquidata-inc/dolt/go/store/chunks"
 	"github.com/liquidata-inc/dolt/go/store/types"
 )
 
@@ -77,3 +79,151 @@ func TestNBSAsTableFileStore(t *testing.T) {
 		assert.Equal(t, expected, data)
 	}
 }
+
+func makeLocalStoreTestDir(t *testing.T) string {
+	testDir := filepath.Join(os.TempDir(), uuid.New().String())
+	err := os.MkdirAll(testDir, os.ModePerm)
+	require.NoError(t, err)
+	return testDir
+}
+
+// Persisting a table file that already exists must not replace it. On Windows, renaming over a table file which
+// another store has open fails.
+func TestLocalStorePersistExistingTable(t *testing.T) {
+	ctx := context.Background()
+	testDir := makeLocalStoreTestDir(t)
+	defer os.RemoveAll(testDir)
+
+	st1, err := NewLocalStore(ctx, types.Format_Default.VersionString(), testDir, defaultMemTableSize)
+	require.NoError(t, err)
+	defer st1.Close()
+
+	st2, err := NewLocalStore(ctx, types.Format_Default.VersionString(), testDir, defaultMemTableSize)
+	require.NoError(t, err)
+	defer st2.Close()
+
+	var chks []chunks.Chunk
+	for i := 0; i < 2*preflushChunkCount; i++ {
+		chks = append(chks, chunks.NewChunk([]byte(fmt.Sprintf("chunk %d", i))))
+	}
+
+	for _, st := range []*NomsBlockStore{st1, st2} {
+		for _, c := range chks {
+			err = st.Put(ctx, c)
+			require.NoError(t, err)
+		}
+
+		root, err := st.Root(ctx)
+		require.NoError(t, err)
+
+		success, err := st.Commit(ctx, chks[0].Hash(), root)
+		require.NoError(t, err)
+
+		if !success {
+			require.NoError(t, st.Rebase(ctx))
+			root, err = st.Root(ctx)
+			require.NoError(t, err)
+			success, err = st.Commit(ctx, chks[0].Hash(), root)
+			require.NoError(t, err)
+		}
+
+		require.True(t, success)
+	}
+
+	for _, st := range []*NomsBlockStore{st1, st2} {
+		for _, c := range chks {
+			actual, err := st.Get(ctx, c.Hash())
+			require.NoError(t, err)
+			assert.Equal(t, c.Data(), actual.Data())
+		}
+	}
+}
+
+// Readers of the manifest must not keep it open once they have parsed it. On Windows, writers can't rename a new
+// manifest over one which is open.
+func TestLocalStoreConcurrentManifestAccess(t *testing.T) {
+	ctx := context.Background()
+	testDir := makeLocalStoreTestDir(t)
+	defer os.RemoveAll(testDir)
+
+	writer, err := NewLocalStore(ctx, types.Format_Default.VersionString(), testDir, defaultMemTableSize)
+	require.NoError(t, err)
+	defer writer.Close()
+
+	reader, err := NewLocalStore(ctx, types.Format_Default.VersionString(), testDir, defaultMemTableSize)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	done := make(chan struct{})
+	readErrs := make(chan error, 1)
+	go func() {
+		defer close(readErrs)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			if err := reader.Rebase(ctx); err != nil {
+				readErrs <- err
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 32; i++ {
+		c := chunks.NewChunk([]byte(fmt.Sprintf("root %d", i)))
+		err = writer.Put(ctx, c)
+		require.NoError(t, err)
+
+		root, err := writer.Root(ctx)
+		require.NoError(t, err)
+
+		success, err := writer.Commit(ctx, c.Hash(), root)
+		require.NoError(t, err)
+		require.True(t, success)
+	}
+
+	close(done)
+	require.NoError(t, <-readErrs)
+
+	require.NoError(t, reader.Rebase(ctx))
+	writerRoot, err := writer.Root(ctx)
+	require.NoError(t, err)
+	readerRoot, err := reader.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, writerRoot, readerRoot)
+}
+
+// Writing a table file which the store already has must leave the existing file intact.
+func TestLocalStoreWriteExistingTableFile(t *testing.T) {
+	ctx := context.Background()
+	testDir := makeLocalStoreTestDir(t)
+	defer os.RemoveAll(testDir)
+
+	st, err := NewLocalStore(ctx, types.Format_Default.VersionString(), testDir, defaultMemTableSize)
+	require.NoError(t, err)
+	defer st.Close()
+
+	chunkData := [][]byte{[]byte("hello"), []byte("goodbye")}
+	data, addr, err := buildTable(chunkData)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		err = st.WriteTableFile(ctx, addr.String(), len(chunkData), bytes.NewReader(data), 0, nil)
+		require.NoError(t, err)
+	}
+
+	for _, d := range chunkData {
+		c, err := st.Get(ctx, chunks.NewChunk(d).Hash())
+		require.NoError(t, err)
+		assert.Equal(t, d, c.Data())
+	}
+
+	infos, err := ioutil.ReadDir(testDir)
+	require.NoError(t, err)
+	for _, info := range infos {
+		assert.False(t, strings.HasPrefix(info.Name(), tempTablePrefix), "temporary table file %s was left behind", info.Name())
+	}
+}
@@ -105,6 +105,7 @@ import (
   There are two phases to loading chunk data for a given Hash from an NBS Table: Checking for the chunk's presence, and fetching the chunk's bytes. When performing a has-check, only the first phase is necessary.
 
   Phase one: Chunk presence
+  - Check your Hash against the bloom filter built from the Index when the Table is opened. If it is not in the filter, your chunk is not in this Table.
   - Slice off the first 8 bytes of your Hash to create a Prefix
   - Since the Prefix Tuples in the Prefix Map are in lexicographic order, binary search the Prefix Map for the desired Prefix.
   - For all Prefix Tuples with a matching Prefix:
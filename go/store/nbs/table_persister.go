@@ -106,7 +106,7 @@ func (sic *indexCache) get(name addr) (tableIndex, bool) {
 }
 
 func (sic *indexCache) put(name addr, idx tableIndex) {
-	indexSize := uint64(idx.chunkCount) * (addrSize + ordinalSize + lengthSize + uint64Size)
+	indexSize := uint64(idx.chunkCount)*(addrSize+ordinalSize+lengthSize+uint64Size) + idx.filter.size()
 	sic.cache.Add(name, indexSize, idx)
 }
 
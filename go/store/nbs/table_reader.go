@@ -107,6 +107,9 @@ type tableIndex struct {
 	prefixes, offsets     []uint64
 	lengths, ordinals     []uint32
 	suffixes              []byte
+	// filter answers most lookups of addresses absent from the table without searching the index. It is built when
+	// the index is parsed and may be nil.
+	filter *bloomFilter
 }
 
 type tableReaderAt interface {
@@ -188,6 +191,7 @@ func parseTableIndex(buff []byte) (tableIndex, error) {
 		prefixes, offsets,
 		lengths, ordinals,
 		suffixes,
+		newTableBloomFilter(prefixes, ordinals, suffixes),
 	}, nil
 }
 
@@ -220,6 +224,14 @@ func (ti tableIndex) prefixIdxToOrdinal(idx uint32) uint32 {
 	return ti.ordinals[idx]
 }
 
+// returns the address at position |idx| in |ti.prefixes|
+func (ti tableIndex) addrAt(idx uint32) (a addr) {
+	binary.BigEndian.PutUint64(a[:], ti.prefixes[idx])
+	li := uint64(ti.ordinals[idx]) * addrSuffixSize
+	copy(a[addrPrefixSize:], ti.suffixes[li:li+addrSuffixSize])
+	return a
+}
+
 // returns the first position in |tr.prefixes| whose value == |prefix|. Returns |tr.chunkCount|
 // if absent
 func (ti tableIndex) prefixIdx(prefix uint64) (idx uint32) {
@@ -247,6 +259,10 @@ func (ti tableIndex) ordinalSuffixMatches(ordinal uint32, h addr) bool {
 
 // returns the ordinal of |h| if present. returns |ti.chunkCount| if absent
 func (ti tableIndex) lookupOrdinal(h addr) uint32 {
+	if !ti.filter.mayContain(h) {
+		return ti.chunkCount
+	}
+
 	prefix := h.Prefix()
 
 	for idx := ti.prefixIdx(prefix); idx < ti.chunkCount && ti.prefixes[idx] == prefix; idx++ {
@@ -279,6 +295,11 @@ func (tr tableReader) hasMany(addrs []hasRecord) (bool, error) {
 			continue
 		}
 
+		if !tr.filter.mayContain(*addr.a) {
+			remaining = true
+			continue
+		}
+
 		for filterIdx < filterLen && addr.prefix > tr.prefixes[filterIdx] {
 			filterIdx++
 		}
@@ -633,6 +654,11 @@ func (tr tableReader) findOffsets(reqs []getRecord) (ors offsetRecSlice, remaini
 			continue
 		}
 
+		if !tr.filter.mayContain(*req.a) {
+			remaining = true
+			continue
+		}
+
 		// advance within the prefixes until we reach one which is >= req.prefix
 		for filterIdx < filterLen && tr.prefixes[filterIdx] < req.prefix {
 			filterIdx++
@@ -32,25 +32,40 @@ type NomsBinFormat struct {
 	tag *formatTag
 }
 
-type formatTag struct{}
+// formatTag is not zero sized, as pointers to distinct zero sized values are not guaranteed to be distinct.
+type formatTag struct {
+	_ byte
+}
 
 var formatTag_7_18 *formatTag = nil
 var formatTag_LD_1 = &formatTag{}
+var formatTag_LD_2 = &formatTag{}
 
 var Format_7_18 = &NomsBinFormat{}
 var Format_LD_1 = &NomsBinFormat{formatTag_LD_1}
 
+// Format_LD_2 is Format_LD_1 with string dictionary encoding of the tuples in map leaf chunks.
+var Format_LD_2 = &NomsBinFormat{formatTag_LD_2}
+
 var Format_Default *NomsBinFormat
 
 func isFormat_7_18(nbf *NomsBinFormat) bool {
 	return nbf.tag == formatTag_7_18
 }
 
+// usesStringDictionary returns whether map leaf sequences written in |nbf| carry a dictionary of the strings that are
+// repeated in their tuples.
+func usesStringDictionary(nbf *NomsBinFormat) bool {
+	return nbf.tag == formatTag_LD_2
+}
+
 func GetFormatForVersionString(s string) (*NomsBinFormat, error) {
 	if s == constants.Format718String {
 		return Format_7_18, nil
 	} else if s == constants.FormatLD1String {
 		return Format_LD_1, nil
+	} else if s == constants.FormatLD2String {
+		return Format_LD_2, nil
 	} else {
 		return nil, errors.New("unsupported ChunkStore version " + s)
 	}
@@ -61,6 +76,8 @@ func (nbf *NomsBinFormat) VersionString() string {
 		return constants.Format718String
 	} else if nbf.tag == formatTag_LD_1 {
 		return constants.FormatLD1String
+	} else if nbf.tag == formatTag_LD_2 {
+		return constants.FormatLD2String
 	} else {
 		panic("unrecognized NomsBinFormat tag value")
 	}
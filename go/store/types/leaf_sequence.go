@@ -30,10 +30,35 @@ import (
 
 type leafSequence struct {
 	sequenceImpl
+	// dict holds the strings that the tuples of a map leaf sequence reference by index, in formats that use a string
+	// dictionary.
+	dict []string
 }
 
 func newLeafSequence(vrw ValueReadWriter, buff []byte, offsets []uint32, len uint64) leafSequence {
-	return leafSequence{newSequenceImpl(vrw, buff, offsets, len)}
+	return leafSequence{sequenceImpl: newSequenceImpl(vrw, buff, offsets, len)}
+}
+
+func (seq leafSequence) decoder() valueDecoder {
+	dec := seq.sequenceImpl.decoder()
+	dec.dict = seq.dict
+	return dec
+}
+
+func (seq leafSequence) decoderAtOffset(offset int) valueDecoder {
+	dec := seq.sequenceImpl.decoderAtOffset(offset)
+	dec.dict = seq.dict
+	return dec
+}
+
+func (seq leafSequence) decoderSkipToIndex(idx int) valueDecoder {
+	return seq.decoderAtOffset(seq.getItemOffset(idx))
+}
+
+func (seq leafSequence) decoderSkipToValues() (valueDecoder, uint64) {
+	dec, count := seq.sequenceImpl.decoderSkipToValues()
+	dec.dict = seq.dict
+	return dec, count
 }
 
 func newLeafSequenceFromValues(kind NomsKind, vrw ValueReadWriter, vs ...Value) (leafSequence, error) {
@@ -28,6 +28,7 @@ import (
 
 	"github.com/liquidata-inc/dolt/go/store/atomicerr"
 	"github.com/liquidata-inc/dolt/go/store/d"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 )
 
 var ErrKeysNotOrdered = errors.New("streaming map keys not ordered")
@@ -105,6 +106,47 @@ func NewStreamingMap(ctx context.Context, vrw ValueReadWriter, ae *atomicerr.Ato
 	})
 }
 
+// Rechunk returns a copy of |m| built from scratch by writing its entries to |vrw|, so that its tree is split into
+// chunks with the chunking parameters of |vrw|. Editing a map keeps the chunks of the unedited parts of its tree, which
+// keeps the parameters it was built with.
+func (m Map) Rechunk(ctx context.Context, vrw ValueReadWriter) (Map, error) {
+	ae := atomicerr.New()
+	kvs := make(chan Value, 16)
+	mapChan := NewStreamingMap(ctx, vrw, ae, kvs)
+
+	// The streaming map stops reading, and closes its output, if it fails
+	send := func(v Value) error {
+		select {
+		case kvs <- v:
+			return nil
+		case <-mapChan:
+			return ae.Get()
+		}
+	}
+
+	err := m.IterAll(ctx, func(k, v Value) error {
+		if err := send(k); err != nil {
+			return err
+		}
+
+		return send(v)
+	})
+
+	close(kvs)
+
+	if err != nil {
+		return EmptyMap, err
+	}
+
+	rechunked := <-mapChan
+
+	if err := ae.Get(); err != nil {
+		return EmptyMap, err
+	}
+
+	return rechunked, nil
+}
+
 type streamingMapReadFunc func(vrw ValueReadWriter, kvs <-chan Value, outChan chan<- Map)
 
 func newStreamingMap(vrw ValueReadWriter, kvs <-chan Value, readFunc streamingMapReadFunc) <-chan Map {
@@ -370,6 +412,119 @@ func (m Map) IteratorFrom(ctx context.Context, key Value) (MapIterator, error) {
 	return &mapIterator{cursor: cur}, nil
 }
 
+// LeafHashes returns the hashes of the chunks holding the map's entries, in key order, reading only the chunks above
+// them. A map whose entries fit in a single chunk is its own leaf, and its own hash is returned.
+func (m Map) LeafHashes(ctx context.Context) ([]hash.Hash, error) {
+	if m.orderedSequence.isLeaf() {
+		h, err := m.Hash(m.Format())
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []hash.Hash{h}, nil
+	}
+
+	return leafHashes(ctx, m.orderedSequence.(metaSequence), nil)
+}
+
+func leafHashes(ctx context.Context, ms metaSequence, hashes []hash.Hash) ([]hash.Hash, error) {
+	tuples, err := ms.tuples()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mt := range tuples {
+		if ms.treeLevel() == 1 {
+			ref, err := mt.ref()
+
+			if err != nil {
+				return nil, err
+			}
+
+			hashes = append(hashes, ref.TargetHash())
+			continue
+		}
+
+		child, err := mt.getChildSequence(ctx, ms.vrw)
+
+		if err != nil {
+			return nil, err
+		}
+
+		hashes, err = leafHashes(ctx, child.(metaSequence), hashes)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return hashes, nil
+}
+
+// SubtreeHashes returns the hashes of the chunks at the top of at least n subtrees of the map, in key order, reading
+// only the chunks above them. Subtrees are taken from the highest level of the tree that has n of them, or from the
+// leaves if none does. A map whose entries fit in a single chunk is its own subtree, and its own hash is returned.
+func (m Map) SubtreeHashes(ctx context.Context, n int) ([]hash.Hash, error) {
+	if m.orderedSequence.isLeaf() {
+		h, err := m.Hash(m.Format())
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []hash.Hash{h}, nil
+	}
+
+	level := []metaSequence{m.orderedSequence.(metaSequence)}
+	for {
+		var hashes []hash.Hash
+		for _, ms := range level {
+			tuples, err := ms.tuples()
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, mt := range tuples {
+				ref, err := mt.ref()
+
+				if err != nil {
+					return nil, err
+				}
+
+				hashes = append(hashes, ref.TargetHash())
+			}
+		}
+
+		if len(hashes) >= n || level[0].treeLevel() == 1 {
+			return hashes, nil
+		}
+
+		var children []metaSequence
+		for _, ms := range level {
+			tuples, err := ms.tuples()
+
+			if err != nil {
+				return nil, err
+			}
+
+			for _, mt := range tuples {
+				child, err := mt.getChildSequence(ctx, ms.vrw)
+
+				if err != nil {
+					return nil, err
+				}
+
+				children = append(children, child.(metaSequence))
+			}
+		}
+
+		level = children
+	}
+}
+
 type mapIterAllCallback func(key, value Value) error
 
 func (m Map) IterAll(ctx context.Context, cb mapIterAllCallback) error {
@@ -103,12 +103,30 @@ func newMapLeafSequence(vrw ValueReadWriter, data ...mapEntry) (orderedSequence,
 
 	offsets[sequencePartLevel] = w.offset
 	w.writeCount(0) // level
+
+	var dict []string
+	var dictIndexes map[string]uint64
+	if usesStringDictionary(vrw.Format()) {
+		dict, err = buildStringDictionary(vrw.Format(), data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		dictIndexes = make(map[string]uint64, len(dict))
+		w.writeCount(uint64(len(dict)))
+		for i, str := range dict {
+			dictIndexes[str] = uint64(i)
+			w.writeString(str)
+		}
+	}
+
 	offsets[sequencePartCount] = w.offset
 	count := uint64(len(data))
 	w.writeCount(count)
 	offsets[sequencePartValues] = w.offset
 	for i, me := range data {
-		err := me.writeTo(&w, vrw.Format())
+		err := me.writeWithDict(&w, vrw.Format(), dictIndexes)
 
 		if err != nil {
 			return nil, err
@@ -116,7 +134,116 @@ func newMapLeafSequence(vrw ValueReadWriter, data ...mapEntry) (orderedSequence,
 
 		offsets[i+sequencePartValues+1] = w.offset
 	}
-	return mapLeafSequence{newLeafSequence(vrw, w.data(), offsets, count)}, nil
+	seq := newLeafSequence(vrw, w.data(), offsets, count)
+	seq.dict = dict
+	return mapLeafSequence{seq}, nil
+}
+
+// minDictStringLen is the length below which a string costs about as much to reference in a dictionary as it does to
+// write out.
+const minDictStringLen = 4
+
+// buildStringDictionary returns the strings that appear more than once among the fields of the key and value tuples
+// of |data|, in the order they first appear.
+func buildStringDictionary(nbf *NomsBinFormat, data []mapEntry) ([]string, error) {
+	var seen []string
+	occurrences := make(map[string]int)
+	countStrs := func(v Value) error {
+		t, ok := v.(Tuple)
+
+		if !ok {
+			return nil
+		}
+
+		return t.IterFields(func(_ uint64, fv Value) (bool, error) {
+			if str, ok := fv.(String); ok && len(str) >= minDictStringLen {
+				if occurrences[string(str)] == 0 {
+					seen = append(seen, string(str))
+				}
+				occurrences[string(str)]++
+			}
+			return false, nil
+		})
+	}
+
+	for _, me := range data {
+		if err := countStrs(me.key); err != nil {
+			return nil, err
+		}
+
+		if err := countStrs(me.value); err != nil {
+			return nil, err
+		}
+	}
+
+	var dict []string
+	for _, str := range seen {
+		if occurrences[str] > 1 {
+			dict = append(dict, str)
+		}
+	}
+
+	return dict, nil
+}
+
+// writeWithDict writes the entry, replacing the string fields of its key and value tuples that are in |dictIndexes|
+// with references to the dictionary.
+func (entry mapEntry) writeWithDict(w *binaryNomsWriter, nbf *NomsBinFormat, dictIndexes map[string]uint64) error {
+	if len(dictIndexes) == 0 {
+		return entry.writeTo(w, nbf)
+	}
+
+	err := writeValueWithDict(w, nbf, entry.key, dictIndexes)
+
+	if err != nil {
+		return err
+	}
+
+	return writeValueWithDict(w, nbf, entry.value, dictIndexes)
+}
+
+func writeValueWithDict(w *binaryNomsWriter, nbf *NomsBinFormat, v Value, dictIndexes map[string]uint64) error {
+	t, ok := v.(Tuple)
+
+	if !ok {
+		return v.writeTo(w, nbf)
+	}
+
+	err := TupleKind.writeTo(w, nbf)
+
+	if err != nil {
+		return err
+	}
+
+	dec, count := t.decoderSkipToFields()
+	w.writeCount(count)
+	for i := uint64(0); i < count; i++ {
+		start := dec.pos()
+
+		if dec.peekKind() == StringKind {
+			dec.skipKind()
+			if idx, ok := dictIndexes[dec.readString()]; ok {
+				err = stringDictRefKind.writeTo(w, nbf)
+
+				if err != nil {
+					return err
+				}
+
+				w.writeCount(idx)
+				continue
+			}
+		} else {
+			err = dec.skipValue(nbf)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		w.writeRaw(dec.byteSlice(start, dec.pos()))
+	}
+
+	return nil
 }
 
 func (ml mapLeafSequence) writeTo(w nomsWriter, nbf *NomsBinFormat) error {
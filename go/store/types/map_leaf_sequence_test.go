@@ -0,0 +1,123 @@
+// Copyright 2019 Liquidata, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/liquidata-inc/dolt/go/store/chunks"
+	"github.com/liquidata-inc/dolt/go/store/constants"
+	"github.com/liquidata-inc/dolt/go/store/hash"
+)
+
+type versionedChunkStore struct {
+	chunks.ChunkStore
+	version string
+}
+
+func (cs versionedChunkStore) Version() string {
+	return cs.version
+}
+
+func newVersionedValueStore(storage *chunks.MemoryStorage, version string) *ValueStore {
+	return NewValueStore(versionedChunkStore{storage.NewView(), version})
+}
+
+func dictTestEntries(t *testing.T, nbf *NomsBinFormat, n int) []mapEntry {
+	colors := []string{"red", "green", "blue", "yellow"}
+	entries := make([]mapEntry, n)
+	for i := range entries {
+		k, err := NewTuple(nbf, Uint(0), Int(i))
+		require.NoError(t, err)
+		v, err := NewTuple(nbf, Uint(1), String(colors[i%len(colors)]), Uint(2), String("category "+colors[i%3]), Uint(3), String(fmt.Sprintf("name %d", i)))
+		require.NoError(t, err)
+		entries[i] = mapEntry{k, v}
+	}
+	return entries
+}
+
+func TestMapLeafStringDictionary(t *testing.T) {
+	ctx := context.Background()
+	ld1Vrw := newVersionedValueStore(&chunks.MemoryStorage{}, constants.FormatLD1String)
+	ld2Storage := &chunks.MemoryStorage{}
+	ld2Vrw := newVersionedValueStore(ld2Storage, constants.FormatLD2String)
+	require.Equal(t, Format_LD_2, ld2Vrw.Format())
+
+	entries := dictTestEntries(t, Format_LD_2, 100)
+	ld1Seq, err := newMapLeafSequence(ld1Vrw, entries...)
+	require.NoError(t, err)
+	ld2Seq, err := newMapLeafSequence(ld2Vrw, entries...)
+	require.NoError(t, err)
+
+	assert.Nil(t, ld1Seq.(mapLeafSequence).dict)
+	ld2Leaf := ld2Seq.(mapLeafSequence)
+	assert.Equal(t, []string{"category red", "green", "category green", "blue", "category blue", "yellow"}, ld2Leaf.dict)
+	assert.True(t, len(ld2Leaf.buff) < len(ld1Seq.(mapLeafSequence).buff))
+
+	ld2Entries, err := ld2Leaf.entries()
+	require.NoError(t, err)
+	assert.Equal(t, len(entries), ld2Entries.Len())
+	for i, me := range ld2Entries.entries {
+		assert.True(t, entries[i].key.Equals(me.key))
+		assert.True(t, entries[i].value.Equals(me.value))
+	}
+
+	// a map spanning many chunks, read back by a store that didn't write it
+	var kvs []Value
+	for _, me := range dictTestEntries(t, Format_LD_2, 5000) {
+		kvs = append(kvs, me.key, me.value)
+	}
+	m, err := NewMap(ctx, ld2Vrw, kvs...)
+	require.NoError(t, err)
+	ref, err := ld2Vrw.WriteValue(ctx, m)
+	require.NoError(t, err)
+	_, err = ld2Vrw.Commit(ctx, ref.TargetHash(), hash.Hash{})
+	require.NoError(t, err)
+
+	val, err := newVersionedValueStore(ld2Storage, constants.FormatLD2String).ReadValue(ctx, ref.TargetHash())
+	require.NoError(t, err)
+	readM := val.(Map)
+	assert.True(t, m.Equals(readM))
+	assert.Equal(t, uint64(5000), readM.Len())
+
+	for i := 0; i < len(kvs); i += 2 {
+		v, ok, err := readM.MaybeGet(ctx, kvs[i])
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.True(t, kvs[i+1].Equals(v))
+	}
+
+	i := 0
+	err = readM.IterAll(ctx, func(k, v Value) error {
+		assert.True(t, kvs[i].Equals(k))
+		assert.True(t, kvs[i+1].Equals(v))
+		i += 2
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, len(kvs), i)
+
+	edited, err := readM.Edit().Set(kvs[0], kvs[3]).Map(ctx)
+	require.NoError(t, err)
+	v, ok, err := edited.MaybeGet(ctx, kvs[0])
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, kvs[3].Equals(v))
+}
@@ -32,11 +32,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/liquidata-inc/dolt/go/store/atomicerr"
 	"github.com/liquidata-inc/dolt/go/store/chunks"
 	"github.com/liquidata-inc/dolt/go/store/d"
+	"github.com/liquidata-inc/dolt/go/store/hash"
 )
 
 const testMapSize = 8000
@@ -1320,6 +1322,96 @@ func TestMapIterAll(t *testing.T) {
 	doTest(getTestRefToValueOrderMap, 2)
 }
 
+func TestMapLeafHashes(t *testing.T) {
+	smallTestChunks()
+	defer normalProductionChunks()
+
+	ctx := context.Background()
+	vrw := newTestValueStore()
+
+	small, err := NewMap(ctx, vrw, Int(1), String("one"))
+	require.NoError(t, err)
+	hashes, err := small.LeafHashes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []hash.Hash{mustHash(small.Hash(Format_7_18))}, hashes)
+
+	kvs := make([]Value, 0, 2000)
+	for i := 0; i < 1000; i++ {
+		kvs = append(kvs, Int(i), String(fmt.Sprintf("value %d", i)))
+	}
+	m, err := NewMap(ctx, vrw, kvs...)
+	require.NoError(t, err)
+	_, err = vrw.WriteValue(ctx, m)
+	require.NoError(t, err)
+	require.True(t, m.orderedSequence.treeLevel() > 1)
+
+	hashes, err = m.LeafHashes(ctx)
+	require.NoError(t, err)
+	require.True(t, len(hashes) > 1)
+
+	i := 0
+	for _, h := range hashes {
+		leaf, err := vrw.ReadValue(ctx, h)
+		require.NoError(t, err)
+		err = leaf.(Map).IterAll(ctx, func(k, v Value) error {
+			assert.True(t, kvs[i].Equals(k))
+			assert.True(t, kvs[i+1].Equals(v))
+			i += 2
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, len(kvs), i)
+}
+
+func TestMapSubtreeHashes(t *testing.T) {
+	smallTestChunks()
+	defer normalProductionChunks()
+
+	ctx := context.Background()
+	vrw := newTestValueStore()
+
+	small, err := NewMap(ctx, vrw, Int(1), String("one"))
+	require.NoError(t, err)
+	hashes, err := small.SubtreeHashes(ctx, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []hash.Hash{mustHash(small.Hash(Format_7_18))}, hashes)
+
+	kvs := make([]Value, 0, 2000)
+	for i := 0; i < 1000; i++ {
+		kvs = append(kvs, Int(i), String(fmt.Sprintf("value %d", i)))
+	}
+	m, err := NewMap(ctx, vrw, kvs...)
+	require.NoError(t, err)
+	_, err = vrw.WriteValue(ctx, m)
+	require.NoError(t, err)
+	require.True(t, m.orderedSequence.treeLevel() > 1)
+
+	leaves, err := m.LeafHashes(ctx)
+	require.NoError(t, err)
+
+	for _, n := range []int{1, 4, len(leaves), len(leaves) + 1} {
+		hashes, err = m.SubtreeHashes(ctx, n)
+		require.NoError(t, err)
+		assert.True(t, len(hashes) >= n || len(hashes) == len(leaves))
+
+		// the subtrees hold all the entries, in order
+		i := 0
+		for _, h := range hashes {
+			subtree, err := vrw.ReadValue(ctx, h)
+			require.NoError(t, err)
+			err = subtree.(Map).IterAll(ctx, func(k, v Value) error {
+				assert.True(t, kvs[i].Equals(k))
+				assert.True(t, kvs[i+1].Equals(v))
+				i += 2
+				return nil
+			})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, len(kvs), i)
+	}
+}
+
 func TestMapEquals(t *testing.T) {
 	assert := assert.New(t)
 
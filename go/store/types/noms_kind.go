@@ -55,6 +55,9 @@ const (
 	InlineBlobKind
 	TimestampKind
 
+	// Internal to decoder: a reference to a string in the dictionary of a map leaf sequence
+	stringDictRefKind NomsKind = 254
+
 	UnknownKind NomsKind = 255
 )
 
@@ -22,6 +22,8 @@
 package types
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/liquidata-inc/dolt/go/store/sloppy"
@@ -37,6 +39,67 @@ const (
 	maxChunkSize = 1 << 24 // TODO: Remove when https://github.com/attic-labs/noms/issues/3743 is fixed.
 )
 
+const (
+	// MinChunkTargetSize and MaxChunkTargetSize bound the average chunk size a ChunkingConfig can target.
+	MinChunkTargetSize = 1 << 8
+	MaxChunkTargetSize = 1 << 20
+
+	// MaxChunkWindow bounds the rolling hash window a ChunkingConfig can use.
+	MaxChunkWindow = 1 << 12
+)
+
+var ErrInvalidChunkTargetSize = fmt.Errorf("the chunk target size must be a power of two between %d and %d", MinChunkTargetSize, MaxChunkTargetSize)
+var ErrInvalidChunkWindow = fmt.Errorf("the chunk window must be between 1 and %d", MaxChunkWindow)
+var errChunkingConfigUnsupported = errors.New("the chunking parameters of this store can't be configured")
+
+// ChunkingConfig holds the parameters of the content-defined chunking that splits the trees of lists, maps, sets and
+// blobs into chunks. A chunk ends where the rolling hash of the last Window bytes of the encoded values matches a pattern
+// which occurs once every TargetSize bytes on average.
+//
+// A larger target size makes for fewer, larger chunks, and suits very wide rows, which otherwise end up with chunk
+// boundaries inside nearly every row and dedupe poorly. The same values written with different parameters are split
+// into different chunks, and so have different hashes, so every writer to a database should use the same parameters.
+type ChunkingConfig struct {
+	TargetSize uint32
+	Window     uint32
+}
+
+// DefaultChunkingConfig is the ChunkingConfig values are chunked with unless configured otherwise.
+var DefaultChunkingConfig = ChunkingConfig{TargetSize: defaultChunkPattern + 1, Window: chunkWindow}
+
+// Validate returns an error if the config's target size isn't a power of two within the bounds allowed, or its window
+// is out of bounds.
+func (cfg ChunkingConfig) Validate() error {
+	if cfg.TargetSize < MinChunkTargetSize || cfg.TargetSize > MaxChunkTargetSize || cfg.TargetSize&(cfg.TargetSize-1) != 0 {
+		return ErrInvalidChunkTargetSize
+	}
+
+	if cfg.Window < 1 || cfg.Window > MaxChunkWindow {
+		return ErrInvalidChunkWindow
+	}
+
+	return nil
+}
+
+// chunkingConfigurer is implemented by ValueReaders, such as ValueStore, whose values are chunked with parameters of
+// their own.
+type chunkingConfigurer interface {
+	ChunkingConfig() ChunkingConfig
+}
+
+// SetChunkingConfig sets the chunking parameters of |vr|, which must be a ValueStore, or embed one.
+func SetChunkingConfig(vr ValueReader, cfg ChunkingConfig) error {
+	vs, ok := vr.(interface {
+		SetChunkingConfig(cfg ChunkingConfig) error
+	})
+
+	if !ok {
+		return errChunkingConfigUnsupported
+	}
+
+	return vs.SetChunkingConfig(cfg)
+}
+
 // Only set by tests
 var (
 	chunkPattern  = defaultChunkPattern
@@ -81,8 +144,15 @@ func hashValueByte(item sequenceItem, rv *rollingValueHasher) error {
 	return nil
 }
 
-func newRollingValueHasher(nbf *NomsBinFormat, salt byte) *rollingValueHasher {
+// newRollingValueHasher returns a hasher which finds the chunk boundaries of values written to |vr|, using its chunking
+// parameters if it has its own.
+func newRollingValueHasher(vr ValueReader, salt byte) *rollingValueHasher {
 	pattern, window := chunkingConfig()
+	if cc, ok := vr.(chunkingConfigurer); ok {
+		cfg := cc.ChunkingConfig()
+		pattern, window = cfg.TargetSize-1, cfg.Window
+	}
+
 	w := newBinaryNomsWriter()
 
 	rv := &rollingValueHasher{
@@ -91,7 +161,7 @@ func newRollingValueHasher(nbf *NomsBinFormat, salt byte) *rollingValueHasher {
 		pattern: pattern,
 		window:  window,
 		salt:    salt,
-		nbf:     nbf,
+		nbf:     vr.Format(),
 	}
 
 	rv.sl = sloppy.New(rv.HashByte)
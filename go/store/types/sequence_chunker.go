@@ -67,7 +67,7 @@ func newSequenceChunker(ctx context.Context, cur *sequenceCursor, level uint64,
 		makeChunk, parentMakeChunk,
 		true,
 		hashValueBytes,
-		newRollingValueHasher(vrw.Format(), byte(level%256)),
+		newRollingValueHasher(vrw, byte(level%256)),
 		false,
 		nil,
 	}
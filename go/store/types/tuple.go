@@ -77,15 +77,86 @@ type Tuple struct {
 // readTuple reads the data provided by a decoder and moves the decoder forward.
 func readTuple(nbf *NomsBinFormat, dec *valueDecoder) (Tuple, error) {
 	start := dec.pos()
-	err := skipTuple(nbf, dec)
+	hasDictRefs, err := skipTupleCheckingDictRefs(nbf, dec)
 
 	if err != nil {
 		return EmptyTuple(nbf), err
 	}
+
+	if hasDictRefs {
+		// Tuples referencing the string dictionary of a map leaf are re-encoded without it, so that they are
+		// byte for byte the same as the tuple that was written
+		dec.offset = start
+		return readTupleWithDict(nbf, dec)
+	}
+
 	end := dec.pos()
 	return Tuple{valueImpl{dec.vrw, nbf, dec.byteSlice(start, end), nil}}, nil
 }
 
+func skipTupleCheckingDictRefs(nbf *NomsBinFormat, dec *valueDecoder) (bool, error) {
+	if dec.dict == nil {
+		return false, skipTuple(nbf, dec)
+	}
+
+	hasDictRefs := false
+	dec.skipKind()
+	count := dec.readCount()
+	for i := uint64(0); i < count; i++ {
+		if dec.peekKind() == stringDictRefKind {
+			hasDictRefs = true
+		}
+
+		err := dec.skipValue(nbf)
+
+		if err != nil {
+			return false, err
+		}
+	}
+	return hasDictRefs, nil
+}
+
+func readTupleWithDict(nbf *NomsBinFormat, dec *valueDecoder) (Tuple, error) {
+	w := newBinaryNomsWriter()
+	err := TupleKind.writeTo(&w, nbf)
+
+	if err != nil {
+		return EmptyTuple(nbf), err
+	}
+
+	dec.skipKind()
+	count := dec.readCount()
+	w.writeCount(count)
+	for i := uint64(0); i < count; i++ {
+		if dec.peekKind() == stringDictRefKind {
+			v, err := dec.readValue(nbf)
+
+			if err != nil {
+				return EmptyTuple(nbf), err
+			}
+
+			err = v.writeTo(&w, nbf)
+
+			if err != nil {
+				return EmptyTuple(nbf), err
+			}
+
+			continue
+		}
+
+		start := dec.pos()
+		err := dec.skipValue(nbf)
+
+		if err != nil {
+			return EmptyTuple(nbf), err
+		}
+
+		w.writeRaw(dec.byteSlice(start, dec.pos()))
+	}
+
+	return Tuple{valueImpl{dec.vrw, nbf, w.data(), nil}}, nil
+}
+
 func skipTuple(nbf *NomsBinFormat, dec *valueDecoder) error {
 	dec.skipKind()
 	count := dec.readCount()
@@ -28,10 +28,13 @@ import (
 )
 
 var ErrUnknownType = errors.New("unknown type")
+var ErrBadStringDictRef = errors.New("string dictionary reference out of range")
 
 type valueDecoder struct {
 	typedBinaryNomsReader
 	vrw ValueReadWriter
+	// dict is the string dictionary of the map leaf sequence being decoded, if it has one.
+	dict []string
 }
 
 // typedBinaryNomsReader provides some functionality for reading and skipping types that is shared by both valueDecoder and refWalker.
@@ -42,11 +45,11 @@ type typedBinaryNomsReader struct {
 
 func newValueDecoder(buff []byte, vrw ValueReadWriter) valueDecoder {
 	nr := binaryNomsReader{buff, 0}
-	return valueDecoder{typedBinaryNomsReader{nr, false}, vrw}
+	return valueDecoder{typedBinaryNomsReader{nr, false}, vrw, nil}
 }
 
 func newValueDecoderWithValidation(nr binaryNomsReader, vrw ValueReadWriter) valueDecoder {
-	return valueDecoder{typedBinaryNomsReader{nr, true}, vrw}
+	return valueDecoder{typedBinaryNomsReader{nr, true}, vrw, nil}
 }
 
 func (r *valueDecoder) readRef(nbf *NomsBinFormat) (Ref, error) {
@@ -100,6 +103,10 @@ func (r *valueDecoder) readSequence(nbf *NomsBinFormat, kind NomsKind, leafSkipp
 	r.skipKind()
 	offsets = append(offsets, r.pos())
 	level := r.readCount()
+	var dict []string
+	if level == 0 && kind == MapKind && usesStringDictionary(nbf) {
+		dict = r.readStringDictionary()
+	}
 	offsets = append(offsets, r.pos())
 	var seqOffsets []uint32
 	var length uint64
@@ -125,7 +132,9 @@ func (r *valueDecoder) readSequence(nbf *NomsBinFormat, kind NomsKind, leafSkipp
 		return newMetaSequence(r.vrw, r.byteSlice(start, end), offsets, length), nil
 	}
 
-	return newLeafSequence(r.vrw, r.byteSlice(start, end), offsets, length), nil
+	seq := newLeafSequence(r.vrw, r.byteSlice(start, end), offsets, length)
+	seq.dict = dict
+	return seq, nil
 }
 
 func (r *valueDecoder) readBlobSequence(nbf *NomsBinFormat) (sequence, error) {
@@ -209,6 +218,10 @@ func (r *valueDecoder) skipSequence(nbf *NomsBinFormat, kind NomsKind, leafSkipp
 			return err
 		}
 	} else {
+		if kind == MapKind && usesStringDictionary(nbf) {
+			r.skipStringDictionary()
+		}
+
 		_, _, err := leafSkipper(nbf)
 
 		if err != nil {
@@ -291,6 +304,13 @@ func (r *valueDecoder) readValue(nbf *NomsBinFormat) (Value, error) {
 	case StringKind:
 		r.skipKind()
 		return String(r.readString()), nil
+	case stringDictRefKind:
+		r.skipKind()
+		idx := r.readCount()
+		if idx >= uint64(len(r.dict)) {
+			return nil, ErrBadStringDictRef
+		}
+		return String(r.dict[idx]), nil
 	case ListKind:
 		seq, err := r.readListSequence(nbf)
 		if err != nil {
@@ -365,6 +385,9 @@ func (r *valueDecoder) skipValue(nbf *NomsBinFormat) error {
 	case StringKind:
 		r.skipKind()
 		r.skipString()
+	case stringDictRefKind:
+		r.skipKind()
+		r.skipCount()
 	case ListKind:
 		err := r.skipList(nbf)
 		if err != nil {
@@ -559,6 +582,23 @@ func (r *valueDecoder) readOrderedKey(nbf *NomsBinFormat) (orderedKey, error) {
 	}
 }
 
+// readStringDictionary reads the dictionary written at the start of a map leaf sequence in formats that use one.
+func (r *typedBinaryNomsReader) readStringDictionary() []string {
+	count := r.readCount()
+	dict := make([]string, count)
+	for i := range dict {
+		dict[i] = r.readString()
+	}
+	return dict
+}
+
+func (r *typedBinaryNomsReader) skipStringDictionary() {
+	count := r.readCount()
+	for i := uint64(0); i < count; i++ {
+		r.skipString()
+	}
+}
+
 func (r *typedBinaryNomsReader) readType() (*Type, error) {
 	t, err := r.readTypeInner(map[string]*Type{})
 
@@ -75,6 +75,7 @@ type ValueStore struct {
 	enforceCompleteness  bool
 	decodedChunks        *sizecache.SizeCache
 	nbf                  *NomsBinFormat
+	chunking             *ChunkingConfig // nil to chunk values with the process default
 
 	versOnce sync.Once
 }
@@ -124,6 +125,27 @@ func newValueStoreWithCacheAndPending(cs chunks.ChunkStore, cacheSize, pendingMa
 	}
 }
 
+// SetChunkingConfig sets the parameters that the trees of values written to this ValueStore are chunked with. It must be
+// called before any values are written.
+func (lvs *ValueStore) SetChunkingConfig(cfg ChunkingConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	lvs.chunking = &cfg
+	return nil
+}
+
+// ChunkingConfig returns the parameters that the trees of values written to this ValueStore are chunked with.
+func (lvs *ValueStore) ChunkingConfig() ChunkingConfig {
+	if lvs.chunking != nil {
+		return *lvs.chunking
+	}
+
+	pattern, window := chunkingConfig()
+	return ChunkingConfig{TargetSize: pattern + 1, Window: window}
+}
+
 func (lvs *ValueStore) expectVersion() {
 	dataVersion := lvs.cs.Version()
 	nbf, err := GetFormatForVersionString(dataVersion)
@@ -23,6 +23,7 @@ package types
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -355,3 +356,62 @@ type badVersionStore struct {
 func (b *badVersionStore) Version() string {
 	return "BAD"
 }
+
+func TestValueStoreChunkingConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(DefaultChunkingConfig.Validate())
+	assert.Equal(ErrInvalidChunkTargetSize, ChunkingConfig{TargetSize: 3000, Window: 67}.Validate())
+	assert.Equal(ErrInvalidChunkTargetSize, ChunkingConfig{TargetSize: 1 << 4, Window: 67}.Validate())
+	assert.Equal(ErrInvalidChunkWindow, ChunkingConfig{TargetSize: 1 << 12, Window: 0}.Validate())
+
+	newStore := func(cfg *ChunkingConfig) (*ValueStore, *chunks.TestStoreView) {
+		ts := (&chunks.TestStorage{}).NewView()
+		vs := NewValueStore(ts)
+
+		if cfg != nil {
+			assert.NoError(SetChunkingConfig(vs, *cfg))
+			assert.Equal(*cfg, vs.ChunkingConfig())
+		} else {
+			assert.Equal(DefaultChunkingConfig, vs.ChunkingConfig())
+		}
+
+		return vs, ts
+	}
+
+	commitMap := func(vs *ValueStore, m Map) hash.Hash {
+		r, err := vs.WriteValue(context.Background(), m)
+		assert.NoError(err)
+		rt, err := vs.Root(context.Background())
+		assert.NoError(err)
+		_, err = vs.Commit(context.Background(), rt, rt)
+		assert.NoError(err)
+
+		return r.TargetHash()
+	}
+
+	kvs := make([]Value, 0, 2000)
+	for i := 0; i < 1000; i++ {
+		kvs = append(kvs, Int(i), String(fmt.Sprintf("%0100d", i)))
+	}
+
+	large := &ChunkingConfig{TargetSize: 1 << 16, Window: 67}
+
+	defaultVS, defaultTS := newStore(nil)
+	defaultMap, err := NewMap(context.Background(), defaultVS, kvs...)
+	assert.NoError(err)
+	defaultHash := commitMap(defaultVS, defaultMap)
+
+	largeVS, largeTS := newStore(large)
+	largeMap, err := NewMap(context.Background(), largeVS, kvs...)
+	assert.NoError(err)
+	largeHash := commitMap(largeVS, largeMap)
+
+	assert.NotEqual(defaultHash, largeHash)
+	assert.True(largeTS.Writes() < defaultTS.Writes(), "expected fewer than %d chunks, got %d", defaultTS.Writes(), largeTS.Writes())
+
+	rechunkVS, _ := newStore(large)
+	rechunked, err := defaultMap.Rechunk(context.Background(), rechunkVS)
+	assert.NoError(err)
+	assert.Equal(largeHash, commitMap(rechunkVS, rechunked))
+}
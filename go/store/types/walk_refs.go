@@ -120,6 +120,10 @@ func (r *refWalker) walkBlob(nbf *NomsBinFormat, cb RefCallback) error {
 }
 
 func (r *refWalker) walkMapLeafSequence(nbf *NomsBinFormat, cb RefCallback) error {
+	if usesStringDictionary(nbf) {
+		r.skipStringDictionary()
+	}
+
 	count := r.readCount()
 	for i := uint64(0); i < count; i++ {
 		err := r.walkValue(nbf, cb) // k
@@ -191,6 +195,10 @@ func (r *refWalker) walkValue(nbf *NomsBinFormat, cb RefCallback) error {
 	case TypeKind:
 		r.skipKind()
 		return r.skipType()
+	case stringDictRefKind:
+		r.skipKind()
+		r.skipCount()
+		return nil
 	case CycleKind, UnionKind, ValueKind:
 		d.Panic("A value instance can never have type %s", k)
 	default: